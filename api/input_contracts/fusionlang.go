@@ -0,0 +1,6 @@
+package input_contracts
+
+// FusionlangSourceInputContract contains a single Fusionlang source file
+type FusionlangSourceInputContract struct {
+	Source string `json:"source" binding:"required"`
+}
@@ -0,0 +1,18 @@
+package input_contracts
+
+// ConfigureGitSyncInputContract points a project at a local git repository (bare or with a working tree).
+// Architecture files are read from Subdirectory of Branch, the root file is detected like in archive uploads
+// unless RootFile is set. A non-zero PollIntervalSeconds enables polling of the branch.
+type ConfigureGitSyncInputContract struct {
+	RepositoryPath      string `json:"repository_path" binding:"required"`
+	Branch              string `json:"branch" binding:"required"`
+	Subdirectory        string `json:"subdirectory"`
+	RootFile            string `json:"root_file"`
+	PollIntervalSeconds int    `json:"poll_interval_seconds" binding:"omitempty,gte=10"`
+}
+
+// GitSyncInputContract optionally selects a commit, tag or branch to synchronize,
+// the head of the configured branch is used by default
+type GitSyncInputContract struct {
+	Revision string `json:"revision"`
+}
@@ -1,5 +1,10 @@
 package input_contracts
 
+// ImportFileInputContract contains the root architecture file (YAML, JSON or Fusionlang,
+// as specified by Format, YAML by default). Files is an optional set of additional files referenced
+// by the root via "include" or by schemas via "file", keyed by their relative paths.
 type ImportFileInputContract struct {
-	YAML string `json:"yaml" binding:"required"`
-}
\ No newline at end of file
+	YAML   string            `json:"yaml" binding:"required"`
+	Format string            `json:"format" binding:"omitempty,oneof=yaml json fusionlang"`
+	Files  map[string]string `json:"files"`
+}
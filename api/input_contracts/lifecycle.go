@@ -0,0 +1,13 @@
+package input_contracts
+
+// LifecycleApiInputContract changes the lifecycle state of schemas, schema versions and messages.
+// Deprecation reason, replacement and sunset date can only be set for deprecated and retired entities.
+type LifecycleApiInputContract struct {
+	State             string `json:"state" binding:"required,oneof=draft active deprecated retired"`
+	DeprecationReason string `json:"deprecation_reason"`
+	// Name of the replacing schema or message, URI of the replacing schema version
+	// like fusioncat://schemas/order_created/versions/2
+	ReplacedBy string `json:"replaced_by" binding:"max=255"`
+	// Sunset date in the YYYY-MM-DD format
+	SunsetAt string `json:"sunset_at"`
+}
@@ -5,4 +5,6 @@ type CreateMessageApiInputContract struct {
 	Description   string `json:"description"`
 	SchemaID      string `json:"schema_id" binding:"required,uuid"`
 	SchemaVersion int    `json:"schema_version" binding:"required,min=1"`
-}
\ No newline at end of file
+	// Message type inside a protobuf schema, the first message of the schema is used when empty
+	SchemaMessageType string `json:"schema_message_type" binding:"max=255"`
+}
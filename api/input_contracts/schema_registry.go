@@ -0,0 +1,21 @@
+package input_contracts
+
+// Request bodies of the Confluent compatible schema registry API keep the field names of that API
+type RegistrySchemaApiInputContract struct {
+	Schema string `json:"schema" binding:"required"`
+	// AVRO (default), JSON or PROTOBUF
+	SchemaType string `json:"schemaType"`
+	// References to schemas of other subjects are not supported, JSON schemas reference other schemas
+	// with fusioncat:// URIs and protobuf schemas import them by their import paths
+	References []RegistrySchemaReferenceApiInputContract `json:"references"`
+}
+
+type RegistrySchemaReferenceApiInputContract struct {
+	Name    string `json:"name"`
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+}
+
+type RegistryConfigApiInputContract struct {
+	Compatibility string `json:"compatibility" binding:"required"`
+}
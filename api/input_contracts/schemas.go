@@ -1,12 +1,74 @@
 package input_contracts
 
+import "encoding/json"
+
+// Content of schemas is validated by the handlers against the type of the schema,
+// see logic.ValidateSchemaContent
 type CreateSchemaApiInputContract struct {
 	Name        string `json:"name" binding:"required,min=1,max=45,alphanum_with_underscore"`
 	Description string `json:"description"`
-	Type        string `json:"type" binding:"required,oneof=jsonschema"`
-	Schema      string `json:"schema" binding:"required,valid_json_schema"`
+	Type        string `json:"type" binding:"required,oneof=jsonschema avro protobuf"`
+	Schema      string `json:"schema" binding:"required"`
+	// Compatibility mode which new versions of the schema must satisfy, the schema compatibility of the project by default
+	Compatibility string `json:"compatibility" binding:"omitempty,oneof=none backward backward_transitive forward forward_transitive full full_transitive"`
 }
 
+// New versions are checked against the compatibility mode of the schema unless IgnoreCompatibility is set
 type ModifySchemaApiInputContract struct {
-	Schema string `json:"schema" binding:"required,valid_json_schema"`
+	Schema              string `json:"schema" binding:"required"`
+	IgnoreCompatibility bool   `json:"ignore_compatibility"`
+}
+
+type SchemaCompatibilityApiInputContract struct {
+	Compatibility string `json:"compatibility" binding:"required,oneof=none backward backward_transitive forward forward_transitive full full_transitive"`
+}
+
+// ValidatePayloadsApiInputContract contains either a single payload or a list of payloads to validate.
+// A null payload means it is not set, null values can be validated as items of payloads.
+type ValidatePayloadsApiInputContract struct {
+	Payload  json.RawMessage   `json:"payload,omitempty" swaggertype:"object"`
+	Payloads []json.RawMessage `json:"payloads,omitempty" swaggertype:"array,object"`
+}
+
+// SchemaImpactApiInputContract is a proposed new version of a schema, it is analyzed and not saved
+type SchemaImpactApiInputContract struct {
+	Schema string `json:"schema" binding:"required"`
+}
+
+// InferSchemaApiInputContract contains sample payloads to infer a JSON schema from. Multipart uploads send
+// the samples as an NDJSON "file" part and the other fields as form fields. The inferred schema is saved
+// as a new schema when Name is set or as a new version of the schema SchemaID when it is set.
+type InferSchemaApiInputContract struct {
+	Payloads    []json.RawMessage `json:"payloads" form:"-" swaggertype:"array,object"`
+	Name        string            `json:"name" form:"name" binding:"omitempty,min=1,max=45,alphanum_with_underscore"`
+	Description string            `json:"description" form:"description"`
+	// Compatibility mode of a new schema, the schema compatibility of the project by default
+	Compatibility       string `json:"compatibility" form:"compatibility" binding:"omitempty,oneof=none backward backward_transitive forward forward_transitive full full_transitive"`
+	SchemaID            string `json:"schema_id" form:"schema_id" binding:"omitempty,uuid"`
+	IgnoreCompatibility bool   `json:"ignore_compatibility" form:"ignore_compatibility"`
+}
+
+// SchemaLintRuleApiInputContract configures a lint rule of a project, omitted fields keep their values.
+// Option selects the naming convention of the property-naming rule.
+type SchemaLintRuleApiInputContract struct {
+	Enabled  *bool  `json:"enabled"`
+	Severity string `json:"severity" binding:"omitempty,oneof=error warning info"`
+	Option   string `json:"option"`
+}
+
+// RevertSchemaVersionApiInputContract optionally moves messages pinned to the current version of the schema
+// to the version created by the revert
+type RevertSchemaVersionApiInputContract struct {
+	MoveMessages        bool `json:"move_messages"`
+	IgnoreCompatibility bool `json:"ignore_compatibility"`
+}
+
+// ConvertSchemaVersionApiInputContract selects the schema type a version is converted to. The converted schema
+// is saved as a new schema linked to the version when Name is set. MessageType selects the converted message
+// of protobuf schemas.
+type ConvertSchemaVersionApiInputContract struct {
+	Target      string `json:"target" binding:"required,oneof=jsonschema avro protobuf"`
+	MessageType string `json:"message_type"`
+	Name        string `json:"name" binding:"omitempty,min=1,max=45,alphanum_with_underscore"`
+	Description string `json:"description"`
 }
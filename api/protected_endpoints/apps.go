@@ -1,6 +1,7 @@
 package protected_endpoints
 
 import (
+	"errors"
 	"github.com/fusioncatltd/fusioncat/api"
 	"github.com/fusioncatltd/fusioncat/api/input_contracts"
 	"github.com/fusioncatltd/fusioncat/api/protected_endpoints/large_chunks_of_logic"
@@ -153,6 +154,7 @@ func GetAppUsageV1(c *gin.Context) {
 // @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
 // @Failure 404 {object} map[string]string "App not found"
 // @Failure 500 {object} map[string]string "Internal server error during code generation"
+// @Failure 504 {object} map[string]string "Code generation of a schema timed out"
 // @Router /v1/protected/apps/{id}/code/{language} [get]
 func GetAppGeneratedCodeV1(c *gin.Context) {
 	language := c.Param("language")
@@ -192,7 +194,11 @@ func GetAppGeneratedCodeV1(c *gin.Context) {
 	}
 
 	// Generate the complete Go code
-	generatedCode, err := large_chunks_of_logic.GenerateAppCode(app, appUsageMatrix)
+	generatedCode, err := large_chunks_of_logic.GenerateAppCode(c.Request.Context(), app, appUsageMatrix)
+	if errors.Is(err, logic.ErrCodeGenerationTimedOut) {
+		c.JSON(http.StatusGatewayTimeout, gin.H{"error": "Failed to generate code: " + err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate code: " + err.Error()})
 		return
@@ -0,0 +1,52 @@
+package protected_endpoints
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/fusioncatltd/fusioncat/api"
+	"github.com/fusioncatltd/fusioncat/api/input_contracts"
+	"github.com/fusioncatltd/fusioncat/fusionlang"
+	"github.com/gin-gonic/gin"
+)
+
+func FusionlangProtectedRoutesV1(router *gin.RouterGroup) {
+	router.POST("/fusionlang/formatter", FormatFusionlangSourceV1)
+}
+
+// Format Fusionlang source
+// @Summary Format Fusionlang source
+// @Description Print a Fusionlang source in the canonical layout. Comments are preserved.
+// @Description Sources with syntax errors are not formatted, the errors are returned instead.
+// @Accept json
+// @Produce json
+// @Tags Fusionlang
+// @Security BearerAuth
+// @Param source body input_contracts.FusionlangSourceInputContract true "Fusionlang source"
+// @Success 200 {object} map[string]string "Formatted source"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 409 {object} map[string][]fusionlang.Error "Syntax errors"
+// @Failure 422 {object} api.DataValidationErrorAPIResponse "JSON payload validation errors"
+// @Router /v1/protected/fusionlang/formatter [post]
+func FormatFusionlangSourceV1(c *gin.Context) {
+	var input input_contracts.FusionlangSourceInputContract
+
+	err := c.ShouldBindJSON(&input)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, api.GetValidationErrors(err))
+		return
+	}
+
+	formatted, formatError := fusionlang.Format(input.Source)
+	if formatError != nil {
+		var syntaxErrors fusionlang.ErrorList
+		if errors.As(formatError, &syntaxErrors) {
+			c.JSON(http.StatusConflict, gin.H{"errors": syntaxErrors})
+			return
+		}
+		c.JSON(http.StatusConflict, gin.H{"error": formatError.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"source": formatted})
+}
@@ -0,0 +1,191 @@
+package protected_endpoints
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/fusioncatltd/fusioncat/api"
+	"github.com/fusioncatltd/fusioncat/api/input_contracts"
+	"github.com/fusioncatltd/fusioncat/logic"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func GitSyncProtectedRoutesV1(router *gin.RouterGroup) {
+	router.GET("/projects/:id/git-sync", GetGitSyncV1)
+	router.PUT("/projects/:id/git-sync", ConfigureGitSyncV1)
+	router.DELETE("/projects/:id/git-sync", RemoveGitSyncV1)
+	router.POST("/projects/:id/git-sync/syncs", RunGitSyncV1)
+}
+
+// Get git synchronization settings of a project
+// @Summary Get git synchronization settings of a project
+// @Description Get the repository, branch and subdirectory the project follows and the last synchronized commit
+// @Produce json
+// @Tags Git synchronization
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Success 200 {object} logic.ProjectGitSyncDBSerializerStruct "Git synchronization settings"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} map[string]string "Project not found or git synchronization is not configured"
+// @Router /v1/protected/projects/{id}/git-sync [get]
+func GetGitSyncV1(c *gin.Context) {
+	id := c.Param("id")
+	parsedProjectID, _ := uuid.Parse(id)
+
+	projectsManager := logic.ProjectsObjectsManager{}
+	_, projectError := projectsManager.GetByID(parsedProjectID)
+	if projectError != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+
+	gitSyncManager := logic.ProjectGitSyncObjectsManager{}
+	gitSync, err := gitSyncManager.GetForProject(parsedProjectID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": logic.ErrGitSyncNotConfigured.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gitSync.Serialize())
+}
+
+// Configure git synchronization of a project
+// @Summary Configure git synchronization of a project
+// @Description Point the project at a local git repository (bare or with a working tree). Architecture files
+// @Description are read from the subdirectory of the branch, files are always read from commits.
+// @Description A poll interval enables automatic synchronization when the branch moves.
+// @Description Repositories must be located in GIT_SYNC_REPOSITORIES_ROOT, git synchronization is disabled if it isn't set.
+// @Produce json
+// @Accept json
+// @Tags Git synchronization
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param settings body input_contracts.ConfigureGitSyncInputContract true "Git synchronization settings"
+// @Success 200 {object} logic.ProjectGitSyncDBSerializerStruct "Git synchronization is configured"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} map[string]string "Project not found"
+// @Failure 409 {object} map[string]string "Repository or branch can't be used"
+// @Failure 422 {object} api.DataValidationErrorAPIResponse "JSON payload validation errors"
+// @Router /v1/protected/projects/{id}/git-sync [put]
+func ConfigureGitSyncV1(c *gin.Context) {
+	id := c.Param("id")
+	parsedProjectID, _ := uuid.Parse(id)
+
+	projectsManager := logic.ProjectsObjectsManager{}
+	_, projectError := projectsManager.GetByID(parsedProjectID)
+	if projectError != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+
+	var input input_contracts.ConfigureGitSyncInputContract
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, api.GetValidationErrors(err))
+		return
+	}
+
+	userID, _ := c.Get("UserID")
+	gitSyncManager := logic.ProjectGitSyncObjectsManager{}
+	gitSync, err := gitSyncManager.Configure(
+		parsedProjectID,
+		userID.(uuid.UUID),
+		input.RepositoryPath,
+		input.Branch,
+		input.Subdirectory,
+		input.RootFile,
+		input.PollIntervalSeconds,
+	)
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gitSync.Serialize())
+}
+
+// Stop git synchronization of a project
+// @Summary Stop git synchronization of a project
+// @Description Remove git synchronization settings. Entities created by previous synchronizations are kept.
+// @Produce json
+// @Tags Git synchronization
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Success 200 {object} map[string]string "Git synchronization is removed"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} map[string]string "Project not found or git synchronization is not configured"
+// @Router /v1/protected/projects/{id}/git-sync [delete]
+func RemoveGitSyncV1(c *gin.Context) {
+	id := c.Param("id")
+	parsedProjectID, _ := uuid.Parse(id)
+
+	projectsManager := logic.ProjectsObjectsManager{}
+	_, projectError := projectsManager.GetByID(parsedProjectID)
+	if projectError != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+
+	gitSyncManager := logic.ProjectGitSyncObjectsManager{}
+	if err := gitSyncManager.Remove(parsedProjectID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Git synchronization is removed"})
+}
+
+// Synchronize a project with its git repository
+// @Summary Synchronize a project with its git repository
+// @Description Read architecture files at a commit (the head of the configured branch by default) and apply them
+// @Description to the project. Existing entities are kept, missing ones are created and schemas whose content
+// @Description changed get a new version. The commit SHA is recorded on the import and on new schema versions.
+// @Produce json
+// @Accept json
+// @Tags Git synchronization
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param sync body input_contracts.GitSyncInputContract false "Revision to synchronize"
+// @Success 200 {object} logic.ProjectImportJobDBSerializerStruct "Project is synchronized"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} map[string]string "Project not found or git synchronization is not configured"
+// @Failure 409 {object} logic.ProjectImportJobDBSerializerStruct "Synchronization failed, contains the errors"
+// @Failure 422 {object} api.DataValidationErrorAPIResponse "JSON payload validation errors"
+// @Router /v1/protected/projects/{id}/git-sync/syncs [post]
+func RunGitSyncV1(c *gin.Context) {
+	id := c.Param("id")
+	parsedProjectID, _ := uuid.Parse(id)
+
+	projectsManager := logic.ProjectsObjectsManager{}
+	_, projectError := projectsManager.GetByID(parsedProjectID)
+	if projectError != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+
+	var input input_contracts.GitSyncInputContract
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	userID, _ := c.Get("UserID")
+	gitSyncManager := logic.ProjectGitSyncObjectsManager{}
+	importJob, err := gitSyncManager.Sync(parsedProjectID, userID.(uuid.UUID), input.Revision)
+	if errors.Is(err, logic.ErrGitSyncNotConfigured) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	if importJob.GetStatus() != logic.ImportJobStatusCompleted {
+		c.JSON(http.StatusConflict, importJob.Serialize())
+		return
+	}
+	c.JSON(http.StatusOK, importJob.Serialize())
+}
@@ -0,0 +1,242 @@
+package protected_endpoints
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/fusioncatltd/fusioncat/common"
+	"github.com/fusioncatltd/fusioncat/logic"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func ImportsProtectedRoutesV1(router *gin.RouterGroup) {
+	router.POST("/projects/:id/imports/jobs", SubmitImportJobV1)
+	router.GET("/projects/:id/imports", GetProjectImportsV1)
+	router.GET("/imports/:importID", GetImportJobV1)
+	router.POST("/imports/:importID/cancel", CancelImportJobV1)
+	router.GET("/imports/:importID/entities", GetImportedEntitiesV1)
+	router.POST("/imports/:importID/revert", RevertImportV1)
+}
+
+// Submit an import job
+// @Summary Submit an import job
+// @Description Submit project architecture for import in the background. Accepts the same payloads as the
+// @Description import endpoint and responds immediately with the job, use the job ID to follow the progress.
+// @Produce json
+// @Accept json,mpfd
+// @Tags Imports
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param import body input_contracts.ImportFileInputContract false "Architecture content to import"
+// @Param archive formData file false "Archive with architecture files (multipart upload)"
+// @Param files formData file false "Architecture files (multipart upload)"
+// @Param root formData string false "Name of the root file in a multipart upload"
+// @Success 202 {object} logic.ProjectImportJobDBSerializerStruct "Import job is submitted"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} map[string]string "Project not found"
+// @Failure 409 {object} map[string]string "Import job can't be created"
+// @Failure 422 {object} api.DataValidationErrorAPIResponse "JSON payload validation errors"
+// @Router /v1/protected/projects/{id}/imports/jobs [post]
+func SubmitImportJobV1(c *gin.Context) {
+	id := c.Param("id")
+	parsedProjectID, _ := uuid.Parse(id)
+
+	projectsManager := logic.ProjectsObjectsManager{}
+	_, projectError := projectsManager.GetByID(parsedProjectID)
+	if projectError != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+
+	bundle, ok := readImportBundle(c)
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("UserID")
+	importsManager := logic.ProjectImportJobsObjectsManager{}
+	importJob, importError := importsManager.SubmitImport(bundle, parsedProjectID, userID.(uuid.UUID))
+	if importError != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": importError.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, importJob.Serialize())
+}
+
+// Get history of imports into a project
+// @Summary Get history of imports into a project
+// @Description Get all imports into a project, newest first. Source documents are not included.
+// @Produce json
+// @Tags Imports
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Success 200 {array} logic.ProjectImportJobDBSerializerStruct "List of imports"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} map[string]string "Project not found"
+// @Router /v1/protected/projects/{id}/imports [get]
+func GetProjectImportsV1(c *gin.Context) {
+	id := c.Param("id")
+	parsedProjectID, _ := uuid.Parse(id)
+
+	projectsManager := logic.ProjectsObjectsManager{}
+	_, projectError := projectsManager.GetByID(parsedProjectID)
+	if projectError != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+
+	importsManager := logic.ProjectImportJobsObjectsManager{}
+	importJobs, err := importsManager.GetAllImportsForProject(parsedProjectID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	serializedImports := make([]*logic.ProjectImportJobDBSerializerStruct, 0, len(importJobs))
+	for _, importJob := range importJobs {
+		serializedImports = append(serializedImports, importJob.Serialize())
+	}
+
+	c.JSON(http.StatusOK, serializedImports)
+}
+
+// Get status of an import
+// @Summary Get status of an import
+// @Description Get status, current phase, counts of created entities, errors and the source document of an import
+// @Produce json
+// @Tags Imports
+// @Security BearerAuth
+// @Param importID path string true "Import ID"
+// @Success 200 {object} logic.ProjectImportJobDBSerializerStruct "Import information"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} map[string]string "Import not found"
+// @Router /v1/protected/imports/{importID} [get]
+func GetImportJobV1(c *gin.Context) {
+	importID, err := uuid.Parse(c.Param("importID"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Import not found"})
+		return
+	}
+
+	importsManager := logic.ProjectImportJobsObjectsManager{}
+	importJob, err := importsManager.GetByID(importID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Import not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, importJob.SerializeWithSource())
+}
+
+// Cancel an import
+// @Summary Cancel an import
+// @Description Cancel a queued or running import. The import stops before the next entity is created,
+// @Description entities created before cancellation are removed.
+// @Produce json
+// @Tags Imports
+// @Security BearerAuth
+// @Param importID path string true "Import ID"
+// @Success 200 {object} logic.ProjectImportJobDBSerializerStruct "Cancellation is requested"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} map[string]string "Import not found"
+// @Failure 409 {object} map[string]string "Import is already finished"
+// @Router /v1/protected/imports/{importID}/cancel [post]
+func CancelImportJobV1(c *gin.Context) {
+	importID, err := uuid.Parse(c.Param("importID"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Import not found"})
+		return
+	}
+
+	importsManager := logic.ProjectImportJobsObjectsManager{}
+	importJob, err := importsManager.CancelImport(importID)
+	if errors.Is(err, logic.ErrImportAlreadyFinished) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Import not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, importJob.Serialize())
+}
+
+// Get entities created by an import
+// @Summary Get entities created by an import
+// @Description List servers, resources, bindings, schemas, messages, apps and app connections created by an import
+// @Produce json
+// @Tags Imports
+// @Security BearerAuth
+// @Param importID path string true "Import ID"
+// @Success 200 {object} logic.ImportedEntitiesSerializerStruct "Entities created by the import"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} map[string]string "Import not found"
+// @Router /v1/protected/imports/{importID}/entities [get]
+func GetImportedEntitiesV1(c *gin.Context) {
+	importID, err := uuid.Parse(c.Param("importID"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Import not found"})
+		return
+	}
+
+	importsManager := logic.ProjectImportJobsObjectsManager{}
+	importJob, err := importsManager.GetByID(importID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Import not found"})
+		return
+	}
+
+	entities, err := importJob.GetCreatedEntities()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, entities)
+}
+
+// Revert an import
+// @Summary Revert an import
+// @Description Remove all entities created by a finished import. The import is not reverted if entities
+// @Description created outside of it depend on the imported ones, such entities are listed in the response.
+// @Description Git synchronizations can't be reverted.
+// @Produce json
+// @Tags Imports
+// @Security BearerAuth
+// @Param importID path string true "Import ID"
+// @Success 200 {object} logic.ProjectImportJobDBSerializerStruct "Import is reverted"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} map[string]string "Import not found"
+// @Failure 409 {object} map[string]interface{} "Import can't be reverted, contains the blocking dependencies"
+// @Router /v1/protected/imports/{importID}/revert [post]
+func RevertImportV1(c *gin.Context) {
+	importID, err := uuid.Parse(c.Param("importID"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Import not found"})
+		return
+	}
+
+	userID, _ := c.Get("UserID")
+	importsManager := logic.ProjectImportJobsObjectsManager{}
+	importJob, blockers, err := importsManager.RevertImport(importID, userID.(uuid.UUID))
+	switch {
+	case errors.Is(err, logic.ErrImportHasDependents):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "dependencies": blockers})
+		return
+	case errors.Is(err, logic.ErrImportNotFinished), errors.Is(err, logic.ErrImportAlreadyReverted),
+		errors.Is(err, logic.ErrImportIsSynchronization):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, common.FusioncatErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Import not found"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, importJob.Serialize())
+}
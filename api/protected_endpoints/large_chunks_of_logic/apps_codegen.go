@@ -2,11 +2,14 @@ package large_chunks_of_logic
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"github.com/fusioncatltd/fusioncat/common"
 	"github.com/fusioncatltd/lib-go-asyncresourceuri"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"text/template"
 
@@ -33,7 +36,8 @@ func mapProtocolToAsync(protocol string) string {
 	}
 }
 
-func GenerateAppCode(app *logic.AppObject, usage *logic.AppUsageMatrixResponse) (string, error) {
+// Schemas of the app are generated in parallel, generation stops when the context is done
+func GenerateAppCode(ctx context.Context, app *logic.AppObject, usage *logic.AppUsageMatrixResponse) (string, error) {
 	// Get templates folder from environment
 	templatesFolder := os.Getenv("PATH_TO_STUBS_TEMPLATES_FOLDER")
 	if templatesFolder == "" {
@@ -61,6 +65,8 @@ func GenerateAppCode(app *logic.AppObject, usage *logic.AppUsageMatrixResponse)
 		SchemaVersion    int
 		StructName       string
 		SchemaStructName string
+		// "// Deprecated:" comment of deprecated and retired messages
+		DeprecationComment string
 	}
 
 	type ResourceTemplateData struct {
@@ -126,8 +132,16 @@ func GenerateAppCode(app *logic.AppObject, usage *logic.AppUsageMatrixResponse)
 	resourcesManager := logic.ResourcesObjectsManager{}
 	serversManager := logic.ServersObjectsManager{}
 
-	// Collect unique entities
+	// Collect unique entities. Messages of protobuf schemas select a message type,
+	// each used message type of a schema gets its own schema struct.
 	schemas := make(map[string]*logic.SchemaObject)
+	schemaMessageTypes := make(map[string]string)
+	schemaKey := func(schemaID string, messageType string) string {
+		if messageType == "" {
+			return schemaID
+		}
+		return schemaID + "#" + messageType
+	}
 	messages := make(map[string]*logic.MessageObject)
 	resources := make(map[string]*logic.ResourceObject)
 	servers := make(map[string]*logic.ServerObject)
@@ -145,7 +159,9 @@ func GenerateAppCode(app *logic.AppObject, usage *logic.AppUsageMatrixResponse)
 					// Get schema for this message
 					schema, err := schemasManager.GetByID(msg.GetSchemaID())
 					if err == nil {
-						schemas[schema.GetID().String()] = schema
+						key := schemaKey(schema.GetID().String(), msg.GetSchemaMessageType())
+						schemas[key] = schema
+						schemaMessageTypes[key] = msg.GetSchemaMessageType()
 					}
 				}
 			}
@@ -195,23 +211,50 @@ func GenerateAppCode(app *logic.AppObject, usage *logic.AppUsageMatrixResponse)
 	resourceStructNames := make(map[string]string)
 	serverStructNames := make(map[string]string)
 
-	// Generate schemas first
-	for schemaID, schema := range schemas {
+	// Generate schemas first, in a stable order
+	schemaKeys := make([]string, 0, len(schemas))
+	for key := range schemas {
+		schemaKeys = append(schemaKeys, key)
+	}
+	sort.Strings(schemaKeys)
+
+	var codeRequests []logic.SchemaCodeRequest
+	var requestedKeys []string
+	for _, key := range schemaKeys {
+		schema := schemas[key]
+
 		// Get schema version for code generation
 		schemaVersion, err := schemasManager.GetSpecificVersionOfSchema(schema.GetID(), schema.GetCurrentVersion())
 		if err != nil {
 			continue
 		}
+		codeRequests = append(codeRequests, logic.SchemaCodeRequest{
+			SchemaVersion: schemaVersion,
+			SchemaType:    schema.GetType(),
+			SchemaName:    schema.GetName(),
+			MessageType:   schemaMessageTypes[key],
+		})
+		requestedKeys = append(requestedKeys, key)
+	}
 
-		// Generate schema code
-		schemaCode, structName, err := schemaVersion.GenerateCode("go", schema.GetType(), schema.GetName())
-		if err != nil {
+	// Generate schema code
+	codeResults := logic.GenerateCodeOfSchemaVersions(ctx, "go", codeRequests)
+	for index, codeResult := range codeResults {
+		key := requestedKeys[index]
+		schema := schemas[key]
+		schemaID := schema.GetID().String()
+
+		if errors.Is(codeResult.Err, logic.ErrCodeGenerationTimedOut) || ctx.Err() != nil {
+			return "", fmt.Errorf("failed to generate schema %s: %w", schema.GetName(), codeResult.Err)
+		}
+		if codeResult.Err != nil {
 			continue
 		}
+		schemaCode, structName := codeResult.Code, codeResult.StructName
 
 		// Use the struct name with a suffix to ensure uniqueness
 		formattedStructName := structName + "FusioncatGeneratedSchema"
-		schemaStructNames[schemaID] = formattedStructName
+		schemaStructNames[key] = formattedStructName
 
 		// Replace the struct name in the generated code
 		schemaCodeWithCorrectName := strings.Replace(schemaCode, "type "+structName+" struct", "type "+formattedStructName+" struct", 1)
@@ -235,7 +278,7 @@ func GenerateAppCode(app *logic.AppObject, usage *logic.AppUsageMatrixResponse)
 		}
 		processedMessages[messageID] = true
 
-		schemaStructName, exists := schemaStructNames[message.GetSchemaID().String()]
+		schemaStructName, exists := schemaStructNames[schemaKey(message.GetSchemaID().String(), message.GetSchemaMessageType())]
 		if !exists {
 			continue
 		}
@@ -245,13 +288,14 @@ func GenerateAppCode(app *logic.AppObject, usage *logic.AppUsageMatrixResponse)
 		messageStructNames[messageID] = msgStructName
 
 		messageImplData.Messages = append(messageImplData.Messages, MessageTemplateData{
-			ID:               messageID,
-			Name:             message.Serialize().Name,
-			Description:      strings.ReplaceAll(message.Serialize().Description, "\"", "\\\""),
-			SchemaID:         message.GetSchemaID().String(),
-			SchemaVersion:    message.GetSchemaVersion(),
-			StructName:       msgStructName,
-			SchemaStructName: schemaStructName,
+			ID:                 messageID,
+			Name:               message.Serialize().Name,
+			Description:        strings.ReplaceAll(message.Serialize().Description, "\"", "\\\""),
+			SchemaID:           message.GetSchemaID().String(),
+			SchemaVersion:      message.GetSchemaVersion(),
+			StructName:         msgStructName,
+			SchemaStructName:   schemaStructName,
+			DeprecationComment: message.GoDeprecationComment(),
 		})
 	}
 
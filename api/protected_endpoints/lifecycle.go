@@ -0,0 +1,202 @@
+package protected_endpoints
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/fusioncatltd/fusioncat/api"
+	"github.com/fusioncatltd/fusioncat/api/input_contracts"
+	"github.com/fusioncatltd/fusioncat/logic"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// bindLifecycleUpdate reads requested lifecycle changes of schemas, schema versions and messages
+func bindLifecycleUpdate(c *gin.Context) (logic.LifecycleUpdate, bool) {
+	var input input_contracts.LifecycleApiInputContract
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, api.GetValidationErrors(err))
+		return logic.LifecycleUpdate{}, false
+	}
+	return logic.LifecycleUpdate{
+		State:             input.State,
+		DeprecationReason: input.DeprecationReason,
+		ReplacedBy:        input.ReplacedBy,
+		SunsetAt:          input.SunsetAt,
+	}, true
+}
+
+// respondLifecycleUpdateError reports lifecycle changes which can't be applied as validation errors
+func respondLifecycleUpdateError(c *gin.Context, err error) {
+	var lifecycleError *logic.LifecycleError
+	if errors.As(err, &lifecycleError) {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, api.DataValidationErrorAPIResponse{
+			Errors: []api.APIDataFieldErrorResponseField{{Field: lifecycleError.Field, Message: lifecycleError.Message}},
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{})
+}
+
+// Change lifecycle state of schema
+// @Summary Change lifecycle state of schema
+// @Description Change the lifecycle state of the schema: draft, active, deprecated or retired.
+// @Description Drafts become active, active schemas are deprecated or retired, deprecated schemas are retired
+// @Description or become active again, retired schemas stay retired. Deprecated and retired schemas can have
+// @Description a deprecation reason, a sunset date and the name of the replacing schema.
+// @Description Retired schemas can't be used by new messages, generated Go code of deprecated and retired schemas
+// @Description has "// Deprecated:" comments.
+// @Produce json
+// @Accept json
+// @Tags Schemas
+// @Security BearerAuth
+// @Param schemaID path string true "Schema ID"
+// @Param lifecycle body input_contracts.LifecycleApiInputContract true "Lifecycle state"
+// @Success 200 {object} logic.SchemaDBSerializerStruct "Modified schema"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} map[string]string "Schema not found"
+// @Failure 422 {object} api.DataValidationErrorAPIResponse "Invalid state change or replacement"
+// @Router /v1/protected/schemas/{schemaID}/lifecycle [put]
+func ModifySchemaLifecycleV1(c *gin.Context) {
+	update, ok := bindLifecycleUpdate(c)
+	if !ok {
+		return
+	}
+
+	schemaID := c.Param("schemaID")
+	parsedSchemaID, _ := uuid.Parse(schemaID)
+
+	schemasManager := logic.SchemaObjectsManager{}
+	schema, err := schemasManager.GetByID(parsedSchemaID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	// Schema is found, verify user has access to its project
+	projectsManager := logic.ProjectsObjectsManager{}
+	_, projectError := projectsManager.GetByID(schema.GetProjectID())
+	if projectError != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	if err := schema.UpdateLifecycle(update); err != nil {
+		respondLifecycleUpdateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, schema.Serialize())
+}
+
+// Change lifecycle state of schema version
+// @Summary Change lifecycle state of schema version
+// @Description Change the lifecycle state of a version of the schema, following the same rules as schemas.
+// @Description Versions are replaced by versions of schemas of the project, referenced by their URIs,
+// @Description e.g. fusioncat://schemas/order_created/versions/2. Retired versions can't be used by new messages.
+// @Produce json
+// @Accept json
+// @Tags Schemas
+// @Security BearerAuth
+// @Param schemaID path string true "Schema ID"
+// @Param versionID path int true "Schema version"
+// @Param lifecycle body input_contracts.LifecycleApiInputContract true "Lifecycle state"
+// @Success 200 {object} logic.SchemaEditDBSerializerStruct "Modified schema version"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} map[string]string "Schema or version not found"
+// @Failure 422 {object} api.DataValidationErrorAPIResponse "Invalid state change or replacement"
+// @Router /v1/protected/schemas/{schemaID}/versions/{versionID}/lifecycle [put]
+func ModifySchemaVersionLifecycleV1(c *gin.Context) {
+	update, ok := bindLifecycleUpdate(c)
+	if !ok {
+		return
+	}
+
+	schemaID := c.Param("schemaID")
+	parsedSchemaID, _ := uuid.Parse(schemaID)
+
+	versionID := c.Param("versionID")
+	parsedVersionID, err := strconv.ParseInt(versionID, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	schemasManager := logic.SchemaObjectsManager{}
+	schema, err := schemasManager.GetByID(parsedSchemaID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	// Schema is found, verify user has access to its project
+	projectsManager := logic.ProjectsObjectsManager{}
+	_, projectError := projectsManager.GetByID(schema.GetProjectID())
+	if projectError != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	schemaVersion, err := schemasManager.GetSpecificVersionOfSchema(parsedSchemaID, int(parsedVersionID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	if err := schemaVersion.UpdateLifecycle(update); err != nil {
+		respondLifecycleUpdateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, schemaVersion.SerializeLong())
+}
+
+// Change lifecycle state of message
+// @Summary Change lifecycle state of message
+// @Description Change the lifecycle state of the message, following the same rules as schemas.
+// @Description Messages are replaced by other messages of the project, referenced by name.
+// @Description Imports warn about apps wired to deprecated messages and reject apps wired to retired messages,
+// @Description generated Go code of deprecated and retired messages has "// Deprecated:" comments.
+// @Produce json
+// @Accept json
+// @Tags Messages
+// @Security BearerAuth
+// @Param messageID path string true "Message ID"
+// @Param lifecycle body input_contracts.LifecycleApiInputContract true "Lifecycle state"
+// @Success 200 {object} logic.MessageDBSerializerStruct "Modified message"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} map[string]string "Message not found"
+// @Failure 422 {object} api.DataValidationErrorAPIResponse "Invalid state change or replacement"
+// @Router /v1/protected/messages/{messageID}/lifecycle [put]
+func ModifyMessageLifecycleV1(c *gin.Context) {
+	update, ok := bindLifecycleUpdate(c)
+	if !ok {
+		return
+	}
+
+	messageID := c.Param("messageID")
+	parsedMessageID, _ := uuid.Parse(messageID)
+
+	messagesManager := logic.MessagesObjectsManager{}
+	message, err := messagesManager.GetByID(parsedMessageID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	// Message is found, verify user has access to its project
+	projectsManager := logic.ProjectsObjectsManager{}
+	_, projectError := projectsManager.GetByID(message.GetProjectID())
+	if projectError != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	if err := message.UpdateLifecycle(update); err != nil {
+		respondLifecycleUpdateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, message.Serialize())
+}
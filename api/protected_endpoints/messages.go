@@ -12,6 +12,9 @@ import (
 func MessagesProtectedRoutesV1(router *gin.RouterGroup) {
 	router.GET("/projects/:id/messages", GetAllMessagesInProjectV1)
 	router.POST("/projects/:id/messages", NewMessageV1)
+	router.POST("/messages/:messageID/validate", ValidatePayloadsOfMessageV1)
+	router.GET("/messages/:messageID/examples", GenerateExamplesOfMessageV1)
+	router.PUT("/messages/:messageID/lifecycle", ModifyMessageLifecycleV1)
 }
 
 // Get all messages in project
@@ -62,7 +65,7 @@ func GetAllMessagesInProjectV1(c *gin.Context) {
 
 // Create new message in project
 // @Summary Create message
-// @Description Create a new message in a project
+// @Description Create a new message in a project. Messages of protobuf schemas can select a message type of the schema with schema_message_type, the first message of the schema is used by default.
 // @Produce json
 // @Accept json
 // @Tags Messages
@@ -74,7 +77,7 @@ func GetAllMessagesInProjectV1(c *gin.Context) {
 // @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
 // @Failure 404 {object} map[string]string "Project or schema not found"
 // @Failure 409 {object} map[string]string "Message with this name already exists in this project"
-// @Failure 422 {object} api.DataValidationErrorAPIResponse "JSON payload validation errors"
+// @Failure 422 {object} api.DataValidationErrorAPIResponse "JSON payload validation errors, retired schema or schema version, or unknown message type of a protobuf schema"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /v1/protected/projects/{id}/messages [post]
 func NewMessageV1(c *gin.Context) {
@@ -131,6 +134,25 @@ func NewMessageV1(c *gin.Context) {
 		return
 	}
 
+	// Messages of protobuf schemas use one of the message types defined in the schema
+	schemaVersion, err := schemasManager.GetSpecificVersionOfSchema(parsedSchemaID, input.SchemaVersion)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Schema version does not exist"})
+		return
+	}
+
+	// Retired schemas and schema versions are kept for existing messages only
+	if schema.IsRetired() || schemaVersion.IsRetired() {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Retired schemas and schema versions can't be used by new messages"})
+		return
+	}
+
+	schemaMessageType, err := schema.ResolveMessageType(schemaVersion, input.SchemaMessageType)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
 	// Create the new message
 	message, err := messagesManager.CreateANewMessage(
 		input.Description,
@@ -139,6 +161,7 @@ func NewMessageV1(c *gin.Context) {
 		input.Name,
 		parsedSchemaID,
 		input.SchemaVersion,
+		schemaMessageType,
 	)
 
 	if err != nil {
@@ -147,4 +170,112 @@ func NewMessageV1(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, message.Serialize())
-}
\ No newline at end of file
+}
+// Validate payloads of message
+// @Summary Validate payloads of message
+// @Description Validate one ("payload") or many ("payloads") JSON payloads against the schema version
+// @Description and the message type of the message. Every payload gets its own result with the failures.
+// @Produce json
+// @Accept json
+// @Tags Messages
+// @Security BearerAuth
+// @Param messageID path string true "Message ID"
+// @Param payloads body input_contracts.ValidatePayloadsApiInputContract true "Payloads to validate"
+// @Success 200 {object} logic.PayloadsValidationSerializerStruct "Validation results"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} map[string]string "Message or its schema not found"
+// @Failure 422 {object} api.DataValidationErrorAPIResponse "No payloads or the schema of the message can't be compiled"
+// @Router /v1/protected/messages/{messageID}/validate [post]
+func ValidatePayloadsOfMessageV1(c *gin.Context) {
+	messageID := c.Param("messageID")
+	parsedMessageID, _ := uuid.Parse(messageID)
+
+	messagesManager := logic.MessagesObjectsManager{}
+	message, err := messagesManager.GetByID(parsedMessageID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	// Message is found, verify user has access to its project
+	projectsManager := logic.ProjectsObjectsManager{}
+	_, projectError := projectsManager.GetByID(message.GetProjectID())
+	if projectError != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	schemasManager := logic.SchemaObjectsManager{}
+	if !schemasManager.SchemaWithVersionExists(message.GetSchemaID(), message.GetSchemaVersion()) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Schema of the message not found"})
+		return
+	}
+
+	payloads, ok := bindPayloadsToValidate(c)
+	if !ok {
+		return
+	}
+
+	results, err := message.ValidatePayloads(payloads)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+// Generate example payloads of message
+// @Summary Generate example payloads of message
+// @Description Generate example payloads valid against the schema version and the message type of the message.
+// @Description The same seed produces the same examples, requests without a seed get a random one,
+// @Description which is returned with the examples.
+// @Produce json
+// @Tags Messages
+// @Security BearerAuth
+// @Param messageID path string true "Message ID"
+// @Param count query int false "Number of examples, 1 by default, at most 100"
+// @Param seed query int false "Seed of the generator"
+// @Success 200 {object} logic.SchemaExamplesSerializerStruct "Generated examples"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} map[string]string "Message or its schema not found"
+// @Failure 422 {object} api.DataValidationErrorAPIResponse "Invalid count or seed, or no valid example could be generated"
+// @Router /v1/protected/messages/{messageID}/examples [get]
+func GenerateExamplesOfMessageV1(c *gin.Context) {
+	messageID := c.Param("messageID")
+	parsedMessageID, _ := uuid.Parse(messageID)
+
+	messagesManager := logic.MessagesObjectsManager{}
+	message, err := messagesManager.GetByID(parsedMessageID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	// Message is found, verify user has access to its project
+	projectsManager := logic.ProjectsObjectsManager{}
+	_, projectError := projectsManager.GetByID(message.GetProjectID())
+	if projectError != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	schemasManager := logic.SchemaObjectsManager{}
+	if !schemasManager.SchemaWithVersionExists(message.GetSchemaID(), message.GetSchemaVersion()) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Schema of the message not found"})
+		return
+	}
+
+	count, seed, ok := bindExamplesQuery(c)
+	if !ok {
+		return
+	}
+
+	examples, err := message.GenerateExamples(count, seed)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, examples)
+}
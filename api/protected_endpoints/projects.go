@@ -1,11 +1,15 @@
 package protected_endpoints
 
 import (
+	"fmt"
 	"github.com/fusioncatltd/fusioncat/api"
 	"github.com/fusioncatltd/fusioncat/api/input_contracts"
 	"github.com/fusioncatltd/fusioncat/logic"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"io"
+	"mime"
+	"mime/multipart"
 	"net/http"
 )
 
@@ -107,17 +111,24 @@ func CreateNewProjectV1(c *gin.Context) {
 
 // Import a project architecture
 // @Summary Import a project architecture
-// @Description Import project architecture including servers, resources, schemas, messages, and apps from YAML
+// @Description Import project architecture including servers, resources, schemas, messages, and apps from YAML or JSON.
+// @Description The architecture can be split into several files: send them in the "files" field of the JSON payload
+// @Description or as a multipart upload with several "files" parts or a single "archive" part (zip, tar, tar.gz).
+// @Description The import runs within the request and is recorded in the import history of the project,
+// @Description use the import jobs endpoint for large imports.
 // @Produce json
-// @Accept json
+// @Accept json,mpfd
 // @Tags Projects
 // @Security BearerAuth
 // @Param id path string true "Project ID"
-// @Param import body input_contracts.ImportFileInputContract true "YAML content to import"
-// @Success 200 {object} map[string]string "Import successful"
+// @Param import body input_contracts.ImportFileInputContract false "Architecture content to import"
+// @Param archive formData file false "Archive with architecture files (multipart upload)"
+// @Param files formData file false "Architecture files (multipart upload)"
+// @Param root formData string false "Name of the root file in a multipart upload"
+// @Success 200 {object} map[string]interface{} "Import successful, contains the import ID and warnings"
 // @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
 // @Failure 404 {object} map[string]string "Project not found"
-// @Failure 409 {object} map[string]interface{} "Import validation errors"
+// @Failure 409 {object} map[string][]logic.ImportValidationIssue "Import validation errors and warnings"
 // @Failure 422 {object} api.DataValidationErrorAPIResponse "JSON payload validation errors"
 // @Router /v1/protected/projects/{id}/imports [post]
 func ImportProjectArchitectureV1(c *gin.Context) {
@@ -131,45 +142,62 @@ func ImportProjectArchitectureV1(c *gin.Context) {
 		return
 	}
 
-	var input input_contracts.ImportFileInputContract
-
-	err := c.ShouldBindJSON(&input)
-	if err != nil {
-		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, api.GetValidationErrors(err))
-		return
-	}
-
-	// Validate the YAML
-	validationErrors := logic.ValidateProjectImportYAML(input.YAML, parsedProjectID)
-	if len(validationErrors) > 0 {
-		c.JSON(http.StatusConflict, gin.H{"errors": validationErrors})
+	bundle, ok := readImportBundle(c)
+	if !ok {
 		return
 	}
 
-	// Import the project architecture
+	// Import the project architecture. The import is recorded in the import history
+	// like background import jobs, its ID is returned in every response.
 	userID, _ := c.Get("UserID")
-	importError := logic.ImportProjectFromYAML(input.YAML, parsedProjectID, userID.(uuid.UUID))
+	importsManager := logic.ProjectImportJobsObjectsManager{}
+	importJob, importError := importsManager.RunImport(bundle, parsedProjectID, userID.(uuid.UUID))
 	if importError != nil {
 		c.JSON(http.StatusConflict, gin.H{"error": importError.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Import completed successfully"})
+	importID := importJob.GetID().String()
+	switch importJob.GetStatus() {
+	case logic.ImportJobStatusCompleted:
+		c.JSON(http.StatusOK, gin.H{
+			"message":   "Import completed successfully",
+			"import_id": importID,
+			"warnings":  importJob.GetWarnings(),
+		})
+	case logic.ImportJobStatusCancelled:
+		c.JSON(http.StatusConflict, gin.H{"error": logic.ErrImportCancelled.Error(), "import_id": importID})
+	default:
+		importErrors := importJob.GetErrors()
+		if len(importErrors) == 1 && importErrors[0].Code == logic.ImportErrCodeImportFailed {
+			c.JSON(http.StatusConflict, gin.H{"error": importErrors[0].Message, "import_id": importID})
+			return
+		}
+		c.JSON(http.StatusConflict, gin.H{
+			"errors":    importErrors,
+			"warnings":  importJob.GetWarnings(),
+			"import_id": importID,
+		})
+	}
 }
 
 // Validate architecture file
 // @Summary Validate architecture file
-// @Description Validate YAML file structure for project import
+// @Description Validate YAML file structure for project import. Every error and warning contains
+// @Description a machine-readable code, YAML path, line and column of the offending node.
 // @Produce json
-// @Accept json
+// @Accept json,mpfd
 // @Tags Projects
 // @Security BearerAuth
 // @Param id path string true "Project ID"
-// @Param import body input_contracts.ImportFileInputContract true "YAML content to validate"
-// @Success 200 {object} map[string]string "File is valid"
+// @Param import body input_contracts.ImportFileInputContract false "Architecture content to validate"
+// @Param archive formData file false "Archive with architecture files (multipart upload)"
+// @Param files formData file false "Architecture files (multipart upload)"
+// @Param root formData string false "Name of the root file in a multipart upload"
+// @Success 200 {object} map[string]interface{} "File is valid, may contain warnings"
 // @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
 // @Failure 404 {object} map[string]string "Project not found"
-// @Failure 409 {object} map[string]interface{} "Validation errors"
+// @Failure 409 {object} map[string][]logic.ImportValidationIssue "Validation errors and warnings"
 // @Failure 422 {object} api.DataValidationErrorAPIResponse "JSON payload validation errors"
 // @Router /v1/protected/projects/{id}/imports/validator [post]
 func ValidateArchitectureFileV1(c *gin.Context) {
@@ -183,20 +211,105 @@ func ValidateArchitectureFileV1(c *gin.Context) {
 		return
 	}
 
-	var input input_contracts.ImportFileInputContract
-
-	err := c.ShouldBindJSON(&input)
-	if err != nil {
-		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, api.GetValidationErrors(err))
+	bundle, ok := readImportBundle(c)
+	if !ok {
 		return
 	}
 
-	// Validate the YAML
-	validationErrors := logic.ValidateProjectImportYAML(input.YAML, parsedProjectID)
-	if len(validationErrors) > 0 {
-		c.JSON(http.StatusConflict, gin.H{"errors": validationErrors})
+	// Validate the architecture files
+	validationIssues := logic.ValidateProjectImportBundle(bundle, parsedProjectID)
+	if validationIssues.HasErrors() {
+		c.JSON(http.StatusConflict, gin.H{"errors": validationIssues.Errors(), "warnings": validationIssues.Warnings()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "YAML is valid"})
+	c.JSON(http.StatusOK, gin.H{"message": "YAML is valid", "warnings": validationIssues.Warnings()})
+}
+
+// readImportBundle reads architecture files from the request. JSON payloads carry the root file inline
+// and optional additional files, multipart uploads carry either a single "archive" part or several
+// "files" parts plus an optional "root" field. Responds with an error and returns false on failure.
+func readImportBundle(c *gin.Context) (*logic.ImportBundle, bool) {
+	if c.ContentType() != "multipart/form-data" {
+		var input input_contracts.ImportFileInputContract
+
+		err := c.ShouldBindJSON(&input)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, api.GetValidationErrors(err))
+			return nil, false
+		}
+
+		bundle := logic.NewSingleFileImportBundle(input.YAML)
+		bundle.RootFormat = input.Format
+		for name, content := range input.Files {
+			if err := bundle.AddFile(name, content); err != nil {
+				c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+				return nil, false
+			}
+		}
+		return bundle, true
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": "Invalid multipart form: " + err.Error()})
+		return nil, false
+	}
+
+	var bundle *logic.ImportBundle
+	if archives := form.File["archive"]; len(archives) > 0 {
+		content, err := readUploadedFile(archives[0])
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return nil, false
+		}
+		bundle, err = logic.NewImportBundleFromArchive(archives[0].Filename, content)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return nil, false
+		}
+	} else {
+		bundle = &logic.ImportBundle{}
+		for _, fileHeader := range form.File["files"] {
+			content, err := readUploadedFile(fileHeader)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+				return nil, false
+			}
+			if err := bundle.AddFile(uploadedFilePath(fileHeader), string(content)); err != nil {
+				c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+				return nil, false
+			}
+		}
+	}
+
+	if len(bundle.Files) == 0 {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": "No architecture files uploaded"})
+		return nil, false
+	}
+
+	if err := bundle.DetectRootFile(c.PostForm("root")); err != nil {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return bundle, true
+}
+
+func readUploadedFile(fileHeader *multipart.FileHeader) ([]byte, error) {
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read uploaded file %s: %v", fileHeader.Filename, err)
+	}
+	defer file.Close()
+	return io.ReadAll(file)
+}
+
+// uploadedFilePath returns the file name as sent by the client. FileHeader.Filename keeps only
+// the base name, but includes refer to files in subdirectories, so the raw header is preferred.
+func uploadedFilePath(fileHeader *multipart.FileHeader) string {
+	_, params, err := mime.ParseMediaType(fileHeader.Header.Get("Content-Disposition"))
+	if err == nil && params["filename"] != "" {
+		return params["filename"]
+	}
+	return fileHeader.Filename
 }
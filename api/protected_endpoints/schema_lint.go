@@ -0,0 +1,133 @@
+package protected_endpoints
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/fusioncatltd/fusioncat/api"
+	"github.com/fusioncatltd/fusioncat/api/input_contracts"
+	"github.com/fusioncatltd/fusioncat/logic"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func SchemaLintProtectedRoutesV1(router *gin.RouterGroup) {
+	router.GET("/projects/:id/lint", LintProjectSchemasV1)
+	router.GET("/projects/:id/lint/rules", GetSchemaLintRulesV1)
+	router.PUT("/projects/:id/lint/rules/:rule", ConfigureSchemaLintRuleV1)
+}
+
+// Lint all schemas of project
+// @Summary Lint all schemas of project
+// @Description Lint the current versions of all JSON schemas of the project with the lint rules of the project
+// @Produce json
+// @Tags Schema linting
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Success 200 {object} logic.ProjectLintSerializerStruct "Lint findings of every schema"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} map[string]string "Project not found"
+// @Router /v1/protected/projects/{id}/lint [get]
+func LintProjectSchemasV1(c *gin.Context) {
+	id := c.Param("id")
+	parsedProjectID, _ := uuid.Parse(id)
+
+	projectsManager := logic.ProjectsObjectsManager{}
+	_, projectError := projectsManager.GetByID(parsedProjectID)
+	if projectError != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	result, err := logic.LintProject(parsedProjectID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Get lint rules of project
+// @Summary Get lint rules of project
+// @Description Get all lint rules with their configuration in the project: whether they are enabled,
+// @Description the severity of their findings and their option (the naming convention of property-naming)
+// @Produce json
+// @Tags Schema linting
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Success 200 {array} logic.SchemaLintRuleSerializerStruct "Lint rules"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} map[string]string "Project not found"
+// @Router /v1/protected/projects/{id}/lint/rules [get]
+func GetSchemaLintRulesV1(c *gin.Context) {
+	id := c.Param("id")
+	parsedProjectID, _ := uuid.Parse(id)
+
+	projectsManager := logic.ProjectsObjectsManager{}
+	_, projectError := projectsManager.GetByID(parsedProjectID)
+	if projectError != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	rules, err := logic.GetSchemaLintRules(parsedProjectID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+// Configure lint rule of project
+// @Summary Configure lint rule of project
+// @Description Enable or disable a lint rule in the project, change the severity of its findings or its option.
+// @Description Findings with the "error" severity reject new schemas and versions. Omitted fields keep their values.
+// @Produce json
+// @Accept json
+// @Tags Schema linting
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param rule path string true "Rule, e.g. property-description"
+// @Param settings body input_contracts.SchemaLintRuleApiInputContract true "Rule configuration"
+// @Success 200 {object} logic.SchemaLintRuleSerializerStruct "Rule configuration"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} map[string]string "Project or rule not found"
+// @Failure 422 {object} api.DataValidationErrorAPIResponse "JSON payload validation errors"
+// @Router /v1/protected/projects/{id}/lint/rules/{rule} [put]
+func ConfigureSchemaLintRuleV1(c *gin.Context) {
+	id := c.Param("id")
+	parsedProjectID, _ := uuid.Parse(id)
+
+	projectsManager := logic.ProjectsObjectsManager{}
+	_, projectError := projectsManager.GetByID(parsedProjectID)
+	if projectError != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	var input input_contracts.SchemaLintRuleApiInputContract
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, api.GetValidationErrors(err))
+		return
+	}
+
+	rule, err := logic.ConfigureSchemaLintRule(parsedProjectID, c.Param("rule"), input.Enabled, input.Severity, input.Option)
+	if errors.Is(err, logic.ErrUnknownLintRule) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, logic.ErrInvalidLintRuleOption) {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, api.DataValidationErrorAPIResponse{
+			Errors: []api.APIDataFieldErrorResponseField{{Field: "option", Message: err.Error()}},
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
@@ -0,0 +1,522 @@
+package protected_endpoints
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/fusioncatltd/fusioncat/api/input_contracts"
+	"github.com/fusioncatltd/fusioncat/logic"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SchemaRegistryProtectedRoutesV1 exposes a subset of the Confluent schema registry API for every project.
+// Kafka serializers use https://<host>/v1/protected/projects/<project ID>/registry as the registry URL
+// and the bearer token for authentication.
+func SchemaRegistryProtectedRoutesV1(router *gin.RouterGroup) {
+	router.GET("/projects/:id/registry/subjects", GetRegistrySubjectsV1)
+	router.GET("/projects/:id/registry/subjects/:subject/versions", GetRegistrySubjectVersionsV1)
+	router.GET("/projects/:id/registry/subjects/:subject/versions/:version", GetRegistrySubjectVersionV1)
+	router.GET("/projects/:id/registry/subjects/:subject/versions/:version/schema", GetRegistrySubjectVersionSchemaV1)
+	router.POST("/projects/:id/registry/subjects/:subject/versions", RegisterRegistrySchemaV1)
+	router.POST("/projects/:id/registry/subjects/:subject", LookupRegistrySchemaV1)
+	router.GET("/projects/:id/registry/schemas/types", GetRegistrySchemaTypesV1)
+	router.GET("/projects/:id/registry/schemas/ids/:registryID", GetRegistrySchemaByIDV1)
+	router.GET("/projects/:id/registry/schemas/ids/:registryID/schema", GetRegistrySchemaByIDRawV1)
+	router.GET("/projects/:id/registry/schemas/ids/:registryID/versions", GetRegistrySchemaVersionsByIDV1)
+	router.POST("/projects/:id/registry/compatibility/subjects/:subject/versions", CheckRegistryCompatibilityV1)
+	router.POST("/projects/:id/registry/compatibility/subjects/:subject/versions/:version", CheckRegistryCompatibilityV1)
+	router.GET("/projects/:id/registry/config", GetRegistryConfigV1)
+	router.PUT("/projects/:id/registry/config", UpdateRegistryConfigV1)
+	router.GET("/projects/:id/registry/config/:subject", GetRegistrySubjectConfigV1)
+	router.PUT("/projects/:id/registry/config/:subject", UpdateRegistrySubjectConfigV1)
+}
+
+// registryContentType is the content type of responses of the Confluent schema registry API
+const registryContentType = "application/vnd.schemaregistry.v1+json"
+
+func registryJSON(c *gin.Context, status int, response interface{}) {
+	c.Header("Content-Type", registryContentType)
+	c.JSON(status, response)
+}
+
+// registryError responds with the error code of the registry error, other errors are errors of the backend store
+func registryError(c *gin.Context, err error) {
+	var registryErr *logic.RegistryError
+	if !errors.As(err, &registryErr) {
+		registryErr = &logic.RegistryError{ErrorCode: 50001, Message: "Error in the backend data store: " + err.Error()}
+	}
+	c.Header("Content-Type", registryContentType)
+	c.AbortWithStatusJSON(registryErr.StatusCode(), registryErr.Serialize())
+}
+
+// getSchemaRegistry returns the registry of the project of the request, or responds with 404
+func getSchemaRegistry(c *gin.Context) (*logic.SchemaRegistryObject, bool) {
+	parsedProjectID, _ := uuid.Parse(c.Param("id"))
+	projectsManager := logic.ProjectsObjectsManager{}
+	project, err := projectsManager.GetByID(parsedProjectID)
+	if err != nil {
+		registryError(c, &logic.RegistryError{ErrorCode: http.StatusNotFound, Message: "Project not found"})
+		return nil, false
+	}
+	return project.GetSchemaRegistry(), true
+}
+
+// bindRegistrySchema reads schemas sent to the registry, references to other subjects are rejected
+func bindRegistrySchema(c *gin.Context) (*input_contracts.RegistrySchemaApiInputContract, bool) {
+	var input input_contracts.RegistrySchemaApiInputContract
+	if err := c.ShouldBindJSON(&input); err != nil {
+		registryError(c, &logic.RegistryError{ErrorCode: logic.RegistryErrorInvalidSchema, Message: "Invalid schema: " + err.Error()})
+		return nil, false
+	}
+	if len(input.References) > 0 {
+		registryError(c, &logic.RegistryError{ErrorCode: logic.RegistryErrorInvalidSchema,
+			Message: "Schema references are not supported, JSON schemas reference other schemas of the project " +
+				"with fusioncat:// URIs and protobuf schemas import them"})
+		return nil, false
+	}
+	return &input, true
+}
+
+// bindRegistryID parses IDs of schemas, or responds with 404
+func bindRegistryID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("registryID"), 10, 64)
+	if err != nil {
+		registryError(c, &logic.RegistryError{ErrorCode: logic.RegistryErrorSchemaNotFound, Message: "Schema not found"})
+		return 0, false
+	}
+	return id, true
+}
+
+// List subjects of the schema registry
+// @Summary List subjects of the schema registry
+// @Description Confluent compatible schema registry: list subjects, which are schemas and messages of the project.
+// @Description Subjects are mapped to names of schemas and messages by replacing characters other than letters, digits
+// @Description and underscores with underscores, "-key" and "-value" suffixes are dropped when nothing has the full name.
+// @Produce json
+// @Tags Schema registry
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Success 200 {array} string "Subjects"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} logic.RegistryErrorSerializerStruct "Project not found"
+// @Router /v1/protected/projects/{id}/registry/subjects [get]
+func GetRegistrySubjectsV1(c *gin.Context) {
+	registry, found := getSchemaRegistry(c)
+	if !found {
+		return
+	}
+	subjects, err := registry.GetSubjects()
+	if err != nil {
+		registryError(c, err)
+		return
+	}
+	registryJSON(c, http.StatusOK, subjects)
+}
+
+// List versions of a subject
+// @Summary List versions of a subject
+// @Description Confluent compatible schema registry: list versions of a subject. Subjects of schemas have all versions
+// @Description of the schemas, subjects of messages have the schema versions of the messages.
+// @Produce json
+// @Tags Schema registry
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param subject path string true "Subject"
+// @Success 200 {array} int "Versions"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} logic.RegistryErrorSerializerStruct "Project or subject not found"
+// @Router /v1/protected/projects/{id}/registry/subjects/{subject}/versions [get]
+func GetRegistrySubjectVersionsV1(c *gin.Context) {
+	registry, found := getSchemaRegistry(c)
+	if !found {
+		return
+	}
+	versions, err := registry.GetSubjectVersions(c.Param("subject"))
+	if err != nil {
+		registryError(c, err)
+		return
+	}
+	registryJSON(c, http.StatusOK, versions)
+}
+
+// Get a version of a subject
+// @Summary Get a version of a subject
+// @Description Confluent compatible schema registry: get a version of a subject with its schema and ID
+// @Produce json
+// @Tags Schema registry
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param subject path string true "Subject"
+// @Param version path string true "Version number or latest"
+// @Success 200 {object} logic.RegistrySchemaSerializerStruct "Version of the subject"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} logic.RegistryErrorSerializerStruct "Project, subject or version not found"
+// @Failure 422 {object} logic.RegistryErrorSerializerStruct "Invalid version"
+// @Router /v1/protected/projects/{id}/registry/subjects/{subject}/versions/{version} [get]
+func GetRegistrySubjectVersionV1(c *gin.Context) {
+	registry, found := getSchemaRegistry(c)
+	if !found {
+		return
+	}
+	version, err := registry.GetSubjectVersion(c.Param("subject"), c.Param("version"))
+	if err != nil {
+		registryError(c, err)
+		return
+	}
+	registryJSON(c, http.StatusOK, version)
+}
+
+// Get the schema of a version of a subject
+// @Summary Get the schema of a version of a subject
+// @Description Confluent compatible schema registry: get only the schema of a version of a subject
+// @Produce plain
+// @Tags Schema registry
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param subject path string true "Subject"
+// @Param version path string true "Version number or latest"
+// @Success 200 {string} string "Schema"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} logic.RegistryErrorSerializerStruct "Project, subject or version not found"
+// @Failure 422 {object} logic.RegistryErrorSerializerStruct "Invalid version"
+// @Router /v1/protected/projects/{id}/registry/subjects/{subject}/versions/{version}/schema [get]
+func GetRegistrySubjectVersionSchemaV1(c *gin.Context) {
+	registry, found := getSchemaRegistry(c)
+	if !found {
+		return
+	}
+	version, err := registry.GetSubjectVersion(c.Param("subject"), c.Param("version"))
+	if err != nil {
+		registryError(c, err)
+		return
+	}
+	c.Data(http.StatusOK, registryContentType, []byte(version.Schema))
+}
+
+// Register a schema under a subject
+// @Summary Register a schema under a subject
+// @Description Confluent compatible schema registry: register a schema under a subject and get its ID. Registering
+// @Description a schema the subject already has returns its ID. Unknown subjects become new schemas of the project,
+// @Description new versions must satisfy the compatibility modes of the schemas. Subjects of messages are read-only.
+// @Accept json
+// @Produce json
+// @Tags Schema registry
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param subject path string true "Subject"
+// @Param schema body input_contracts.RegistrySchemaApiInputContract true "Schema"
+// @Success 200 {object} logic.RegistryRegisteredSchemaSerializerStruct "ID of the schema"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} logic.RegistryErrorSerializerStruct "Project not found"
+// @Failure 409 {object} logic.RegistryErrorSerializerStruct "The schema is incompatible with an earlier version"
+// @Failure 422 {object} logic.RegistryErrorSerializerStruct "Invalid schema or subject of a message"
+// @Router /v1/protected/projects/{id}/registry/subjects/{subject}/versions [post]
+func RegisterRegistrySchemaV1(c *gin.Context) {
+	registry, found := getSchemaRegistry(c)
+	if !found {
+		return
+	}
+	input, valid := bindRegistrySchema(c)
+	if !valid {
+		return
+	}
+
+	userID, _ := c.Get("UserID")
+	id, err := registry.RegisterSchema(c.Param("subject"), input.SchemaType, input.Schema, userID.(uuid.UUID))
+	if err != nil {
+		registryError(c, err)
+		return
+	}
+	registryJSON(c, http.StatusOK, logic.RegistryRegisteredSchemaSerializerStruct{ID: id})
+}
+
+// Check if a schema is registered under a subject
+// @Summary Check if a schema is registered under a subject
+// @Description Confluent compatible schema registry: find the version of a subject with the schema
+// @Accept json
+// @Produce json
+// @Tags Schema registry
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param subject path string true "Subject"
+// @Param schema body input_contracts.RegistrySchemaApiInputContract true "Schema"
+// @Success 200 {object} logic.RegistrySchemaSerializerStruct "Version of the subject with the schema"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} logic.RegistryErrorSerializerStruct "Project, subject or schema not found"
+// @Failure 422 {object} logic.RegistryErrorSerializerStruct "Invalid schema"
+// @Router /v1/protected/projects/{id}/registry/subjects/{subject} [post]
+func LookupRegistrySchemaV1(c *gin.Context) {
+	registry, found := getSchemaRegistry(c)
+	if !found {
+		return
+	}
+	input, valid := bindRegistrySchema(c)
+	if !valid {
+		return
+	}
+
+	version, err := registry.LookupSchema(c.Param("subject"), input.SchemaType, input.Schema)
+	if err != nil {
+		registryError(c, err)
+		return
+	}
+	registryJSON(c, http.StatusOK, version)
+}
+
+// List schema types of the schema registry
+// @Summary List schema types of the schema registry
+// @Description Confluent compatible schema registry: list supported schema types
+// @Produce json
+// @Tags Schema registry
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Success 200 {array} string "Schema types"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} logic.RegistryErrorSerializerStruct "Project not found"
+// @Router /v1/protected/projects/{id}/registry/schemas/types [get]
+func GetRegistrySchemaTypesV1(c *gin.Context) {
+	if _, found := getSchemaRegistry(c); !found {
+		return
+	}
+	registryJSON(c, http.StatusOK, logic.RegistrySchemaTypes)
+}
+
+// Get a schema by ID
+// @Summary Get a schema by ID
+// @Description Confluent compatible schema registry: get a schema by the ID serializers embed into messages.
+// @Description IDs of schemas are IDs of schema versions of the project.
+// @Produce json
+// @Tags Schema registry
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param registryID path int true "Schema ID"
+// @Success 200 {object} logic.RegistrySchemaByIDSerializerStruct "Schema"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} logic.RegistryErrorSerializerStruct "Project or schema not found"
+// @Router /v1/protected/projects/{id}/registry/schemas/ids/{registryID} [get]
+func GetRegistrySchemaByIDV1(c *gin.Context) {
+	registry, found := getSchemaRegistry(c)
+	if !found {
+		return
+	}
+	id, valid := bindRegistryID(c)
+	if !valid {
+		return
+	}
+	schema, err := registry.GetSchemaByID(id)
+	if err != nil {
+		registryError(c, err)
+		return
+	}
+	registryJSON(c, http.StatusOK, schema)
+}
+
+// Get only a schema by ID
+// @Summary Get only a schema by ID
+// @Description Confluent compatible schema registry: get only the schema with the ID
+// @Produce plain
+// @Tags Schema registry
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param registryID path int true "Schema ID"
+// @Success 200 {string} string "Schema"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} logic.RegistryErrorSerializerStruct "Project or schema not found"
+// @Router /v1/protected/projects/{id}/registry/schemas/ids/{registryID}/schema [get]
+func GetRegistrySchemaByIDRawV1(c *gin.Context) {
+	registry, found := getSchemaRegistry(c)
+	if !found {
+		return
+	}
+	id, valid := bindRegistryID(c)
+	if !valid {
+		return
+	}
+	schema, err := registry.GetSchemaByID(id)
+	if err != nil {
+		registryError(c, err)
+		return
+	}
+	c.Data(http.StatusOK, registryContentType, []byte(schema.Schema))
+}
+
+// List subjects and versions of a schema
+// @Summary List subjects and versions of a schema
+// @Description Confluent compatible schema registry: list the subject of the schema of the ID and subjects of
+// @Description messages which use it
+// @Produce json
+// @Tags Schema registry
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param registryID path int true "Schema ID"
+// @Success 200 {array} logic.RegistrySubjectVersionSerializerStruct "Subjects and versions"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} logic.RegistryErrorSerializerStruct "Project or schema not found"
+// @Router /v1/protected/projects/{id}/registry/schemas/ids/{registryID}/versions [get]
+func GetRegistrySchemaVersionsByIDV1(c *gin.Context) {
+	registry, found := getSchemaRegistry(c)
+	if !found {
+		return
+	}
+	id, valid := bindRegistryID(c)
+	if !valid {
+		return
+	}
+	versions, err := registry.GetSubjectVersionsOfSchema(id)
+	if err != nil {
+		registryError(c, err)
+		return
+	}
+	registryJSON(c, http.StatusOK, versions)
+}
+
+// Check compatibility of a schema with a subject
+// @Summary Check compatibility of a schema with a subject
+// @Description Confluent compatible schema registry: check the schema against a version of the subject in the directions
+// @Description of its compatibility mode. Without a version the schema is checked as a new version of the subject.
+// @Accept json
+// @Produce json
+// @Tags Schema registry
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param subject path string true "Subject"
+// @Param version path string true "Version number or latest"
+// @Param schema body input_contracts.RegistrySchemaApiInputContract true "Schema"
+// @Success 200 {object} logic.RegistryCompatibilitySerializerStruct "Compatibility of the schema"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} logic.RegistryErrorSerializerStruct "Project, subject or version not found"
+// @Failure 422 {object} logic.RegistryErrorSerializerStruct "Invalid schema or version"
+// @Router /v1/protected/projects/{id}/registry/compatibility/subjects/{subject}/versions/{version} [post]
+func CheckRegistryCompatibilityV1(c *gin.Context) {
+	registry, found := getSchemaRegistry(c)
+	if !found {
+		return
+	}
+	input, valid := bindRegistrySchema(c)
+	if !valid {
+		return
+	}
+
+	compatibility, err := registry.CheckCompatibility(c.Param("subject"), c.Param("version"), input.SchemaType, input.Schema)
+	if err != nil {
+		registryError(c, err)
+		return
+	}
+	registryJSON(c, http.StatusOK, compatibility)
+}
+
+// Get the default compatibility level
+// @Summary Get the default compatibility level
+// @Description Confluent compatible schema registry: get the compatibility level of new subjects, the schema
+// @Description compatibility of the project
+// @Produce json
+// @Tags Schema registry
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Success 200 {object} logic.RegistryConfigSerializerStruct "Compatibility level"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} logic.RegistryErrorSerializerStruct "Project not found"
+// @Router /v1/protected/projects/{id}/registry/config [get]
+func GetRegistryConfigV1(c *gin.Context) {
+	registry, found := getSchemaRegistry(c)
+	if !found {
+		return
+	}
+	registryJSON(c, http.StatusOK, registry.GetConfig())
+}
+
+// Change the default compatibility level
+// @Summary Change the default compatibility level
+// @Description Confluent compatible schema registry: change the compatibility level of schemas created afterwards
+// @Description without an explicit compatibility mode
+// @Accept json
+// @Produce json
+// @Tags Schema registry
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param config body input_contracts.RegistryConfigApiInputContract true "Compatibility level, e.g. BACKWARD"
+// @Success 200 {object} logic.RegistryConfigUpdateSerializerStruct "New compatibility level"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} logic.RegistryErrorSerializerStruct "Project not found"
+// @Failure 422 {object} logic.RegistryErrorSerializerStruct "Invalid compatibility level"
+// @Router /v1/protected/projects/{id}/registry/config [put]
+func UpdateRegistryConfigV1(c *gin.Context) {
+	registry, found := getSchemaRegistry(c)
+	if !found {
+		return
+	}
+	var input input_contracts.RegistryConfigApiInputContract
+	if err := c.ShouldBindJSON(&input); err != nil {
+		registryError(c, &logic.RegistryError{ErrorCode: logic.RegistryErrorInvalidCompatibility, Message: "Invalid compatibility level"})
+		return
+	}
+
+	config, err := registry.UpdateConfig(input.Compatibility)
+	if err != nil {
+		registryError(c, err)
+		return
+	}
+	registryJSON(c, http.StatusOK, config)
+}
+
+// Get the compatibility level of a subject
+// @Summary Get the compatibility level of a subject
+// @Description Confluent compatible schema registry: get the compatibility mode of the schema of the subject
+// @Produce json
+// @Tags Schema registry
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param subject path string true "Subject"
+// @Success 200 {object} logic.RegistryConfigSerializerStruct "Compatibility level"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} logic.RegistryErrorSerializerStruct "Project or subject not found"
+// @Router /v1/protected/projects/{id}/registry/config/{subject} [get]
+func GetRegistrySubjectConfigV1(c *gin.Context) {
+	registry, found := getSchemaRegistry(c)
+	if !found {
+		return
+	}
+	config, err := registry.GetSubjectConfig(c.Param("subject"))
+	if err != nil {
+		registryError(c, err)
+		return
+	}
+	registryJSON(c, http.StatusOK, config)
+}
+
+// Change the compatibility level of a subject
+// @Summary Change the compatibility level of a subject
+// @Description Confluent compatible schema registry: change the compatibility mode of the schema of the subject.
+// @Description Subjects of messages are read-only.
+// @Accept json
+// @Produce json
+// @Tags Schema registry
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param subject path string true "Subject"
+// @Param config body input_contracts.RegistryConfigApiInputContract true "Compatibility level, e.g. BACKWARD"
+// @Success 200 {object} logic.RegistryConfigUpdateSerializerStruct "New compatibility level"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} logic.RegistryErrorSerializerStruct "Project or subject not found"
+// @Failure 422 {object} logic.RegistryErrorSerializerStruct "Invalid compatibility level or subject of a message"
+// @Router /v1/protected/projects/{id}/registry/config/{subject} [put]
+func UpdateRegistrySubjectConfigV1(c *gin.Context) {
+	registry, found := getSchemaRegistry(c)
+	if !found {
+		return
+	}
+	var input input_contracts.RegistryConfigApiInputContract
+	if err := c.ShouldBindJSON(&input); err != nil {
+		registryError(c, &logic.RegistryError{ErrorCode: logic.RegistryErrorInvalidCompatibility, Message: "Invalid compatibility level"})
+		return
+	}
+
+	config, err := registry.UpdateSubjectConfig(c.Param("subject"), input.Compatibility)
+	if err != nil {
+		registryError(c, err)
+		return
+	}
+	registryJSON(c, http.StatusOK, config)
+}
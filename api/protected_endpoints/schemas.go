@@ -1,24 +1,49 @@
 package protected_endpoints
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/fusioncatltd/fusioncat/api"
 	"github.com/fusioncatltd/fusioncat/api/input_contracts"
+	"github.com/fusioncatltd/fusioncat/common"
+	"github.com/fusioncatltd/fusioncat/jsonschemainfer"
 	"github.com/fusioncatltd/fusioncat/logic"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 )
 
 func SchemasProtectedRoutesV1(router *gin.RouterGroup) {
 	router.GET("/projects/:id/schemas", GetAllSchemasInProjectV1)
 	router.POST("/projects/:id/schemas", NewSchemaInProjectV1)
+	router.POST("/projects/:id/schemas/infer", InferSchemaInProjectV1)
 	router.GET("/schemas/:schemaID", GetSingleSchemaV1)
 	router.PUT("/schemas/:schemaID", ModifySchemaV1)
+	router.DELETE("/schemas/:schemaID", DeleteSchemaV1)
+	router.PUT("/schemas/:schemaID/compatibility", ModifySchemaCompatibilityV1)
+	router.PUT("/schemas/:schemaID/lifecycle", ModifySchemaLifecycleV1)
+	router.GET("/schemas/:schemaID/references", GetSchemaReferencesV1)
 	router.GET("/schemas/:schemaID/versions", GetSchemaVersionsV1)
 	router.GET("/schemas/:schemaID/versions/:versionID", GetSingleSchemaVersionsV1)
+	router.PUT("/schemas/:schemaID/versions/:versionID/lifecycle", ModifySchemaVersionLifecycleV1)
+	router.POST("/schemas/:schemaID/versions/:versionID/revert", RevertSchemaToVersionV1)
+	router.POST("/schemas/:schemaID/versions/:versionID/convert", ConvertSchemaVersionV1)
+	router.POST("/schemas/:schemaID/versions/:versionID/validate", ValidatePayloadsOfSchemaVersionV1)
+	router.GET("/schemas/:schemaID/versions/:versionID/examples", GenerateExamplesOfSchemaVersionV1)
+	router.GET("/schemas/:schemaID/versions/:versionID/fields", GetFieldsOfSchemaVersionV1)
+	router.GET("/projects/:id/fields", SearchFieldsInProjectV1)
+	router.GET("/projects/:id/fields/consistency", GetFieldsConsistencyReportV1)
+	router.GET("/schemas/:schemaID/diff", GetSchemaVersionsDiffV1)
+	router.GET("/schemas/:schemaID/impact", GetSchemaImpactV1)
+	router.POST("/schemas/:schemaID/impact", ProposeSchemaVersionImpactV1)
 	router.GET("/schemas/:schemaID/code/:language", GenerateCodeOfSchemaV1)
 }
 
@@ -61,7 +86,12 @@ func GetAllSchemasInProjectV1(c *gin.Context) {
 
 // Create new schema in project
 // @Summary Create new schema in project
-// @Description Create new schema in project
+// @Description Create new schema in project. Supported schema types are "jsonschema" (JSON schemas must
+// @Description declare their dialect in the "$schema" field), "avro" (Apache Avro schema definitions, .avsc)
+// @Description and "protobuf" (.proto sources, import "name.proto" refers to the protobuf schema "name" of the same project).
+// @Description The compatibility mode (none, backward, backward_transitive, forward, forward_transitive, full, full_transitive)
+// @Description is enforced when new versions are created, protobuf schemas support only "none".
+// @Description JSON schemas are linted with the rules of the project, findings are returned in "lint".
 // @Produce json
 // @Accept json
 // @Tags Schemas
@@ -72,6 +102,7 @@ func GetAllSchemasInProjectV1(c *gin.Context) {
 // @Success 401 "Access denied: missing or invalid Authorization header"
 // @Success 404 "Project not found"
 // @Success 422 {object} api.DataValidationErrorAPIResponse "JSON payload validation errors"
+// @Failure 422 {object} logic.SchemaLintErrorSerializerStruct "The schema breaks lint rules with the error severity"
 // @Router /v1/protected/projects/{id}/schemas [post]
 func NewSchemaInProjectV1(c *gin.Context) {
 
@@ -86,6 +117,17 @@ func NewSchemaInProjectV1(c *gin.Context) {
 	id := c.Param("id")
 	parsedProjectID, _ := uuid.Parse(id)
 
+	if err := logic.ValidateSchemaContent(parsedProjectID, input.Name, input.Type, input.Schema); err != nil {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, api.GetSchemaContentValidationErrors(err))
+		return
+	}
+	if input.Compatibility != "" {
+		if err := logic.CheckCompatibilityModeOfType(input.Type, input.Compatibility); err != nil {
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
 	userID, _ := c.Get("UserID")
 	projectsManager := logic.ProjectsObjectsManager{}
 	_, projectError := projectsManager.GetByID(parsedProjectID)
@@ -94,6 +136,17 @@ func NewSchemaInProjectV1(c *gin.Context) {
 		return
 	}
 
+	lintFindings, err := logic.LintSchemaContent(parsedProjectID, input.Type, input.Schema)
+	var lintError *logic.SchemaLintError
+	if errors.As(err, &lintError) {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, lintError.Serialize())
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	schemasManager := logic.SchemaObjectsManager{}
 
 	// Making sure the schema name is unique for the project
@@ -107,11 +160,14 @@ func NewSchemaInProjectV1(c *gin.Context) {
 		input.Description,
 		input.Schema,
 		input.Type,
+		input.Compatibility,
 		"user", userID.(uuid.UUID),
 		userID.(uuid.UUID),
 		parsedProjectID,
 	)
-	c.JSON(http.StatusOK, schema.Serialize())
+	serialized := schema.Serialize()
+	serialized.Lint = lintFindings
+	c.JSON(http.StatusOK, serialized)
 }
 
 // Get schema
@@ -150,7 +206,10 @@ func GetSingleSchemaV1(c *gin.Context) {
 
 // Modify schema
 // @Summary Modify schema
-// @Description Modify schema by creating a new version
+// @Description Modify schema by creating a new version. The new version is checked against previous versions
+// @Description according to the compatibility mode of the schema, incompatible versions are rejected with
+// @Description the list of violations unless "ignore_compatibility" is set. JSON schemas are linted with
+// @Description the rules of the project, findings are returned in "lint".
 // @Produce json
 // @Accept json
 // @Tags Schemas
@@ -160,7 +219,8 @@ func GetSingleSchemaV1(c *gin.Context) {
 // @Success 200 {object} logic.SchemaDBSerializerStruct "Modified schema"
 // @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
 // @Failure 404 {object} map[string]string "Schema not found"
-// @Failure 422 {object} api.DataValidationErrorAPIResponse "JSON payload validation errors"
+// @Failure 409 {object} logic.SchemaCompatibilityErrorSerializerStruct "The new version breaks the compatibility mode"
+// @Failure 422 {object} logic.SchemaLintErrorSerializerStruct "JSON payload validation errors or the new version breaks lint rules with the error severity"
 // @Router /v1/protected/schemas/{schemaID} [put]
 func ModifySchemaV1(c *gin.Context) {
 	var input input_contracts.ModifySchemaApiInputContract
@@ -191,9 +251,88 @@ func ModifySchemaV1(c *gin.Context) {
 		return
 	}
 
+	// New versions must be valid schemas of the same type
+	if err := logic.ValidateSchemaContent(schema.GetProjectID(), schema.GetName(), schema.GetType(), input.Schema); err != nil {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, api.GetSchemaContentValidationErrors(err))
+		return
+	}
+
+	lintFindings, err := logic.LintSchemaContent(schema.GetProjectID(), schema.GetType(), input.Schema)
+	var lintError *logic.SchemaLintError
+	if errors.As(err, &lintError) {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, lintError.Serialize())
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	// Create a new version of the schema
-	schema, err = schema.CreateANewVersion(input.Schema, userID.(uuid.UUID))
+	schema, err = schema.CreateANewVersion(input.Schema, userID.(uuid.UUID), input.IgnoreCompatibility)
+	var compatibilityError *logic.SchemaCompatibilityError
+	if errors.As(err, &compatibilityError) {
+		c.AbortWithStatusJSON(http.StatusConflict, compatibilityError.Serialize())
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+
+	serialized := schema.Serialize()
+	serialized.Lint = lintFindings
+	c.JSON(http.StatusOK, serialized)
+}
+
+// Change compatibility mode of schema
+// @Summary Change compatibility mode of schema
+// @Description Change the compatibility mode which new versions of the schema must satisfy.
+// @Description Existing versions are not rechecked.
+// @Produce json
+// @Accept json
+// @Tags Schemas
+// @Security BearerAuth
+// @Param schemaID path string true "Schema ID"
+// @Param compatibility body input_contracts.SchemaCompatibilityApiInputContract true "Compatibility mode"
+// @Success 200 {object} logic.SchemaDBSerializerStruct "Modified schema"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} map[string]string "Schema not found"
+// @Failure 422 {object} api.DataValidationErrorAPIResponse "JSON payload validation errors"
+// @Router /v1/protected/schemas/{schemaID}/compatibility [put]
+func ModifySchemaCompatibilityV1(c *gin.Context) {
+	var input input_contracts.SchemaCompatibilityApiInputContract
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, api.GetValidationErrors(err))
+		return
+	}
+
+	schemaID := c.Param("schemaID")
+	parsedSchemaID, _ := uuid.Parse(schemaID)
+
+	schemasManager := logic.SchemaObjectsManager{}
+	schema, err := schemasManager.GetByID(parsedSchemaID)
+
 	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	// Schema is found, verify user has access to its project
+	projectsManager := logic.ProjectsObjectsManager{}
+	_, projectError := projectsManager.GetByID(schema.GetProjectID())
+	if projectError != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	if err := logic.CheckCompatibilityModeOfType(schema.GetType(), input.Compatibility); err != nil {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := schema.UpdateCompatibility(input.Compatibility); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{})
 		return
 	}
@@ -201,6 +340,93 @@ func ModifySchemaV1(c *gin.Context) {
 	c.JSON(http.StatusOK, schema.Serialize())
 }
 
+// Delete schema
+// @Summary Delete schema
+// @Description Delete schema from the project, its name can be reused afterwards. Schemas referenced by other schemas
+// @Description or used by messages can't be deleted, the response lists the referencing schema versions and the messages.
+// @Produce json
+// @Tags Schemas
+// @Security BearerAuth
+// @Param schemaID path string true "Schema ID"
+// @Success 200 {object} map[string]string "Schema is deleted"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} map[string]string "Schema not found"
+// @Failure 409 {object} logic.SchemaInUseErrorSerializerStruct "The schema is referenced by other schemas or used by messages"
+// @Router /v1/protected/schemas/{schemaID} [delete]
+func DeleteSchemaV1(c *gin.Context) {
+	schemaID := c.Param("schemaID")
+	parsedSchemaID, _ := uuid.Parse(schemaID)
+
+	schemasManager := logic.SchemaObjectsManager{}
+	schema, err := schemasManager.GetByID(parsedSchemaID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	// Schema is found, verify user has access to its project
+	projectsManager := logic.ProjectsObjectsManager{}
+	_, projectError := projectsManager.GetByID(schema.GetProjectID())
+	if projectError != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	err = schema.Delete()
+	var inUseError *logic.SchemaInUseError
+	if errors.As(err, &inUseError) {
+		c.AbortWithStatusJSON(http.StatusConflict, inUseError.Serialize())
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Schema is deleted"})
+}
+
+// Get references of schema
+// @Summary Get references of schema
+// @Description JSON schemas reference other JSON schemas of the project by URIs of their versions,
+// @Description e.g. {"$ref": "fusioncat://schemas/address/versions/2"}. Get versions of other schemas referenced
+// @Description by versions of the schema, and versions of other schemas which reference versions of the schema.
+// @Produce json
+// @Tags Schemas
+// @Security BearerAuth
+// @Param schemaID path string true "Schema ID"
+// @Success 200 {object} logic.SchemaReferencesSerializerStruct "References of the schema"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} map[string]string "Schema not found"
+// @Router /v1/protected/schemas/{schemaID}/references [get]
+func GetSchemaReferencesV1(c *gin.Context) {
+	schemaID := c.Param("schemaID")
+	parsedSchemaID, _ := uuid.Parse(schemaID)
+
+	schemasManager := logic.SchemaObjectsManager{}
+	schema, err := schemasManager.GetByID(parsedSchemaID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	// Schema is found, verify user has access to its project
+	projectsManager := logic.ProjectsObjectsManager{}
+	_, projectError := projectsManager.GetByID(schema.GetProjectID())
+	if projectError != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	references, err := schema.GetReferences()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, references)
+}
+
 // Get list of schema versions
 // @Summary Get list of schema versions
 // @Description Get list of schema versions
@@ -295,41 +521,47 @@ func GetSingleSchemaVersionsV1(c *gin.Context) {
 	c.JSON(http.StatusOK, schemaVersion.SerializeLong())
 }
 
-// Generate code from schema
-// @Summary Generate code from schema in specified language
-// @Description Generate code from schema in specified programming language. The generated code will be returned as a plain text file with appropriate content type headers.
-// @Produce text/plain
+// Revert schema to a previous version
+// @Summary Revert schema to a previous version
+// @Description Create a new version of the schema with the content of a previous version, versions are never
+// @Description rewritten. The new version is checked against the compatibility mode of the schema like any other
+// @Description one, unless "ignore_compatibility" is set. "move_messages" moves messages pinned to the current
+// @Description version to the new one, protobuf messages selecting a message type the new version doesn't define are skipped.
+// @Produce json
+// @Accept json
 // @Tags Schemas
 // @Security BearerAuth
 // @Param schemaID path string true "Schema ID"
-// @Param language path string true "Programming language" Enums(typescript, java, go, python)
-// @Success 200 {string} string "Generated code as plain text file"
-// @Failure 400 {object} map[string]string "Invalid language or schema type"
+// @Param versionID path int true "Version to revert to"
+// @Param options body input_contracts.RevertSchemaVersionApiInputContract false "Revert options"
+// @Success 200 {object} logic.SchemaRevertSerializerStruct "Schema with the new version and moved messages"
 // @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
-// @Failure 404 {object} map[string]string "Schema not found"
-// @Router /v1/protected/schemas/{schemaID}/code/{language} [get]
-func GenerateCodeOfSchemaV1(c *gin.Context) {
+// @Failure 404 {object} map[string]string "Schema or version not found"
+// @Failure 409 {object} logic.SchemaCompatibilityErrorSerializerStruct "The new version breaks the compatibility mode"
+// @Failure 422 {object} map[string]string "Invalid payload or the version is the current one"
+// @Router /v1/protected/schemas/{schemaID}/versions/{versionID}/revert [post]
+func RevertSchemaToVersionV1(c *gin.Context) {
 	schemaID := c.Param("schemaID")
-	language := c.Param("language")
 	parsedSchemaID, _ := uuid.Parse(schemaID)
 
-	// Validate language parameter
-	validLanguages := map[string]bool{
-		"typescript": true,
-		"java":       true,
-		"go":         true,
-		"python":     true,
+	versionID := c.Param("versionID")
+	parsedVersionID, err := strconv.ParseInt(versionID, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
 	}
-	if !validLanguages[language] {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid programming language. Supported languages are: typescript, java, go, python"})
+
+	// Options are optional, requests may have no body
+	var input input_contracts.RevertSchemaVersionApiInputContract
+	if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Get the schema
 	schemasManager := logic.SchemaObjectsManager{}
 	schema, err := schemasManager.GetByID(parsedSchemaID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Schema not found"})
+		c.JSON(http.StatusNotFound, gin.H{})
 		return
 	}
 
@@ -337,12 +569,873 @@ func GenerateCodeOfSchemaV1(c *gin.Context) {
 	projectsManager := logic.ProjectsObjectsManager{}
 	_, projectError := projectsManager.GetByID(schema.GetProjectID())
 	if projectError != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		c.JSON(http.StatusNotFound, gin.H{})
 		return
 	}
 
-	// Generate the code using the schema's GenerateCode method
-	generatedCode, _, err := schema.GenerateCode(language)
+	schemaVersion, err := schemasManager.GetSpecificVersionOfSchema(parsedSchemaID, int(parsedVersionID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	userID, _ := c.Get("UserID")
+	result, err := schema.RevertToVersion(schemaVersion, userID.(uuid.UUID), input.IgnoreCompatibility, input.MoveMessages)
+	var compatibilityError *logic.SchemaCompatibilityError
+	if errors.As(err, &compatibilityError) {
+		c.AbortWithStatusJSON(http.StatusConflict, compatibilityError.Serialize())
+		return
+	}
+	if errors.Is(err, logic.ErrRevertToCurrentVersion) {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Convert schema version to another schema type
+// @Summary Convert schema version to another schema type
+// @Description Convert a version of the schema to a JSON schema, an Avro schema or a protobuf definition. Objects
+// @Description become records and messages, optional fields become nullable, string formats become logical types
+// @Description and well-known types. Avro and protobuf schemas are converted to each other through JSON Schema.
+// @Description Constructs which the target type can't express, e.g. constraints of JSON schemas or oneofs of protobuf
+// @Description messages, are reported as losses. The result is saved as a new schema linked to the version when "name" is set.
+// @Produce json
+// @Accept json
+// @Tags Schemas
+// @Security BearerAuth
+// @Param schemaID path string true "Schema ID"
+// @Param versionID path int true "Schema version"
+// @Param conversion body input_contracts.ConvertSchemaVersionApiInputContract true "Target type and the new schema"
+// @Success 200 {object} logic.SchemaConversionSerializerStruct "Converted schema, losses and the saved schema"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} map[string]string "Schema or version not found"
+// @Failure 409 {object} map[string]string "The schema name is taken"
+// @Failure 422 {object} api.DataValidationErrorAPIResponse "Invalid payload, unknown message type or the schema is of the target type"
+// @Router /v1/protected/schemas/{schemaID}/versions/{versionID}/convert [post]
+func ConvertSchemaVersionV1(c *gin.Context) {
+	schemaID := c.Param("schemaID")
+	parsedSchemaID, _ := uuid.Parse(schemaID)
+
+	versionID := c.Param("versionID")
+	parsedVersionID, err := strconv.ParseInt(versionID, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	var input input_contracts.ConvertSchemaVersionApiInputContract
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, api.GetValidationErrors(err))
+		return
+	}
+
+	schemasManager := logic.SchemaObjectsManager{}
+	schema, err := schemasManager.GetByID(parsedSchemaID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	// Schema is found, verify user has access to its project
+	projectsManager := logic.ProjectsObjectsManager{}
+	_, projectError := projectsManager.GetByID(schema.GetProjectID())
+	if projectError != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	schemaVersion, err := schemasManager.GetSpecificVersionOfSchema(parsedSchemaID, int(parsedVersionID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	messageType, err := schema.ResolveMessageType(schemaVersion, input.MessageType)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	conversion, err := schema.ConvertVersion(schemaVersion, input.Target, messageType, input.Name)
+	var contentError *logic.SchemaContentError
+	if errors.Is(err, logic.ErrConversionToSameType) {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, api.DataValidationErrorAPIResponse{
+			Errors: []api.APIDataFieldErrorResponseField{{Field: "target", Message: "Should differ from the type of the schema"}},
+		})
+		return
+	}
+	if errors.As(err, &contentError) {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if input.Name == "" {
+		c.JSON(http.StatusOK, conversion)
+		return
+	}
+
+	// Making sure the schema name is unique for the project
+	if schemasManager.CheckIfThisSchemaNameAlreadyExists(schema.GetProjectID(), input.Name) {
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "Schema with this name already exists"})
+		return
+	}
+	userID, _ := c.Get("UserID")
+	converted, err := schemasManager.CreateAConvertedSchema(input.Name, input.Description, conversion, schemaVersion,
+		userID.(uuid.UUID), schema.GetProjectID())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	conversion.SavedSchema = converted.Serialize()
+	c.JSON(http.StatusOK, conversion)
+}
+
+// maxValidatedPayloads limits the number of payloads validated in a single request
+const maxValidatedPayloads = 1000
+
+// bindPayloadsToValidate reads payloads of validation requests, either a single payload or a list of them
+func bindPayloadsToValidate(c *gin.Context) ([]json.RawMessage, bool) {
+	var input input_contracts.ValidatePayloadsApiInputContract
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	payloads := input.Payloads
+	hasPayload := input.Payload != nil && string(input.Payload) != "null"
+	message := ""
+	switch {
+	case hasPayload && input.Payloads != nil:
+		message = "Either payload or payloads should be set, not both"
+	case hasPayload:
+		payloads = []json.RawMessage{input.Payload}
+	case len(input.Payloads) == 0:
+		message = "This field is required"
+	case len(input.Payloads) > maxValidatedPayloads:
+		message = fmt.Sprintf("Should contain at most %d payloads", maxValidatedPayloads)
+	}
+	if message != "" {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, api.DataValidationErrorAPIResponse{
+			Errors: []api.APIDataFieldErrorResponseField{{Field: "payloads", Message: message}},
+		})
+		return nil, false
+	}
+	return payloads, true
+}
+
+// Validate payloads against schema version
+// @Summary Validate payloads against schema version
+// @Description Validate one ("payload") or many ("payloads") JSON payloads against a version of the schema.
+// @Description Every payload gets its own result with the failures, JSON schemas report JSON pointers
+// @Description to the invalid values and the failed keywords. Avro data is expected in the Avro JSON encoding,
+// @Description protobuf messages in the proto3 JSON mapping, message_type selects a message of protobuf schemas.
+// @Produce json
+// @Accept json
+// @Tags Schemas
+// @Security BearerAuth
+// @Param schemaID path string true "Schema ID"
+// @Param versionID path int true "Schema version"
+// @Param message_type query string false "Full name of a message type of a protobuf schema"
+// @Param payloads body input_contracts.ValidatePayloadsApiInputContract true "Payloads to validate"
+// @Success 200 {object} logic.PayloadsValidationSerializerStruct "Validation results"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} map[string]string "Schema or version not found"
+// @Failure 422 {object} api.DataValidationErrorAPIResponse "No payloads or unknown message type"
+// @Router /v1/protected/schemas/{schemaID}/versions/{versionID}/validate [post]
+func ValidatePayloadsOfSchemaVersionV1(c *gin.Context) {
+	schemaID := c.Param("schemaID")
+	parsedSchemaID, _ := uuid.Parse(schemaID)
+
+	versionID := c.Param("versionID")
+	parsedVersionID, err := strconv.ParseInt(versionID, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	schemasManager := logic.SchemaObjectsManager{}
+	schema, err := schemasManager.GetByID(parsedSchemaID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	// Schema is found, verify user has access to its project
+	projectsManager := logic.ProjectsObjectsManager{}
+	_, projectError := projectsManager.GetByID(schema.GetProjectID())
+	if projectError != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	schemaVersion, err := schemasManager.GetSpecificVersionOfSchema(parsedSchemaID, int(parsedVersionID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	payloads, ok := bindPayloadsToValidate(c)
+	if !ok {
+		return
+	}
+
+	messageType, err := schema.ResolveMessageType(schemaVersion, c.Query("message_type"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	results, err := schemaVersion.ValidatePayloads(schema.GetType(), messageType, payloads)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+// maxGeneratedExamples limits the number of examples generated in a single request
+const maxGeneratedExamples = 100
+
+// bindExamplesQuery reads the number of examples and the seed of example generation requests.
+// Requests without a seed get a random one, it is returned so that the examples can be reproduced.
+func bindExamplesQuery(c *gin.Context) (int, int64, bool) {
+	var validationErrors []api.APIDataFieldErrorResponseField
+	count := 1
+	if value := c.Query("count"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxGeneratedExamples {
+			validationErrors = append(validationErrors, api.APIDataFieldErrorResponseField{
+				Field:   "count",
+				Message: fmt.Sprintf("Should be a number from 1 to %d", maxGeneratedExamples),
+			})
+		}
+		count = parsed
+	}
+	// Random seeds stay below 2^53, so that they survive JSON numbers of clients
+	seed := time.Now().UnixNano() % (1 << 53)
+	if value := c.Query("seed"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			validationErrors = append(validationErrors, api.APIDataFieldErrorResponseField{
+				Field:   "seed",
+				Message: "Should be an integer",
+			})
+		}
+		seed = parsed
+	}
+	if len(validationErrors) > 0 {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, api.DataValidationErrorAPIResponse{Errors: validationErrors})
+		return 0, 0, false
+	}
+	return count, seed, true
+}
+
+// Generate example payloads of schema version
+// @Summary Generate example payloads of schema version
+// @Description Generate example payloads valid against a version of the schema. Examples honor types, formats,
+// @Description enums, numeric bounds, lengths, patterns, required properties and "examples" of JSON schemas.
+// @Description Avro examples are in the Avro JSON encoding, protobuf examples in the proto3 JSON mapping,
+// @Description message_type selects a message of protobuf schemas. The same seed produces the same examples,
+// @Description requests without a seed get a random one, which is returned with the examples.
+// @Produce json
+// @Tags Schemas
+// @Security BearerAuth
+// @Param schemaID path string true "Schema ID"
+// @Param versionID path int true "Schema version"
+// @Param count query int false "Number of examples, 1 by default, at most 100"
+// @Param seed query int false "Seed of the generator"
+// @Param message_type query string false "Full name of a message type of a protobuf schema"
+// @Success 200 {object} logic.SchemaExamplesSerializerStruct "Generated examples"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} map[string]string "Schema or version not found"
+// @Failure 422 {object} api.DataValidationErrorAPIResponse "Invalid count or seed, unknown message type or no valid example could be generated"
+// @Router /v1/protected/schemas/{schemaID}/versions/{versionID}/examples [get]
+func GenerateExamplesOfSchemaVersionV1(c *gin.Context) {
+	schemaID := c.Param("schemaID")
+	parsedSchemaID, _ := uuid.Parse(schemaID)
+
+	versionID := c.Param("versionID")
+	parsedVersionID, err := strconv.ParseInt(versionID, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	schemasManager := logic.SchemaObjectsManager{}
+	schema, err := schemasManager.GetByID(parsedSchemaID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	// Schema is found, verify user has access to its project
+	projectsManager := logic.ProjectsObjectsManager{}
+	_, projectError := projectsManager.GetByID(schema.GetProjectID())
+	if projectError != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	schemaVersion, err := schemasManager.GetSpecificVersionOfSchema(parsedSchemaID, int(parsedVersionID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	count, seed, ok := bindExamplesQuery(c)
+	if !ok {
+		return
+	}
+
+	messageType, err := schema.ResolveMessageType(schemaVersion, c.Query("message_type"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	examples, err := schemaVersion.GenerateExamples(schema.GetType(), messageType, count, seed)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, examples)
+}
+
+// Get field catalog of schema version
+// @Summary Get field catalog of schema version
+// @Description Get fields of a version of the schema, flattened. Paths are JSON pointers to the fields in the data,
+// @Description items of arrays and values of maps are "*". Fields of protobuf schemas are listed for every message type.
+// @Produce json
+// @Tags Schemas
+// @Security BearerAuth
+// @Param schemaID path string true "Schema ID"
+// @Param versionID path int true "Schema version"
+// @Success 200 {array} logic.SchemaFieldSerializerStruct "Fields of the schema version"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} map[string]string "Schema or version not found"
+// @Router /v1/protected/schemas/{schemaID}/versions/{versionID}/fields [get]
+func GetFieldsOfSchemaVersionV1(c *gin.Context) {
+	schemaID := c.Param("schemaID")
+	parsedSchemaID, _ := uuid.Parse(schemaID)
+
+	versionID := c.Param("versionID")
+	parsedVersionID, err := strconv.ParseInt(versionID, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	schemasManager := logic.SchemaObjectsManager{}
+	schema, err := schemasManager.GetByID(parsedSchemaID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	// Schema is found, verify user has access to its project
+	projectsManager := logic.ProjectsObjectsManager{}
+	_, projectError := projectsManager.GetByID(schema.GetProjectID())
+	if projectError != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	schemaVersion, err := schemasManager.GetSpecificVersionOfSchema(parsedSchemaID, int(parsedVersionID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	fields, err := schemaVersion.GetFields()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, fields)
+}
+
+// Search fields in project
+// @Summary Search fields in project
+// @Description Find fields of a name (case-insensitive) in all versions of active schemas of the project,
+// @Description with their types and the messages which use the versions.
+// @Produce json
+// @Tags Schemas
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param name query string true "Name of the field, e.g. customer_id"
+// @Success 200 {object} logic.SchemaFieldSearchSerializerStruct "Found fields"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} map[string]string "Project not found"
+// @Failure 422 {object} api.DataValidationErrorAPIResponse "Missing name"
+// @Router /v1/protected/projects/{id}/fields [get]
+func SearchFieldsInProjectV1(c *gin.Context) {
+	id := c.Param("id")
+	parsedProjectID, _ := uuid.Parse(id)
+
+	projectsManager := logic.ProjectsObjectsManager{}
+	_, projectError := projectsManager.GetByID(parsedProjectID)
+	if projectError != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	name := strings.TrimSpace(c.Query("name"))
+	if name == "" {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, api.DataValidationErrorAPIResponse{
+			Errors: []api.APIDataFieldErrorResponseField{{Field: "name", Message: "This field is required"}},
+		})
+		return
+	}
+
+	search, err := logic.SearchSchemaFields(parsedProjectID, name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, search)
+}
+
+// Get field consistency report of project
+// @Summary Get field consistency report of project
+// @Description Group fields of the same name across current versions of active schemas and versions messages are
+// @Description pinned to, and report groups whose fields conflict by type, format, enum or unit. Types and formats
+// @Description of Avro and protobuf schemas are mapped to JSON types and formats, units are read from descriptions
+// @Description and Avro logical types. Every field links the schema version which defines it and the messages using it.
+// @Produce json
+// @Tags Schemas
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Success 200 {object} logic.ProjectConsistencySerializerStruct "Inconsistent fields"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} map[string]string "Project not found"
+// @Router /v1/protected/projects/{id}/fields/consistency [get]
+func GetFieldsConsistencyReportV1(c *gin.Context) {
+	id := c.Param("id")
+	parsedProjectID, _ := uuid.Parse(id)
+
+	projectsManager := logic.ProjectsObjectsManager{}
+	_, projectError := projectsManager.GetByID(parsedProjectID)
+	if projectError != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	report, err := logic.GetProjectConsistencyReport(parsedProjectID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// maxInferenceSamples limits the number of samples a schema is inferred from in a single request
+const maxInferenceSamples = 10000
+
+// bindInferenceSamples reads samples of schema inference requests, JSON requests send them in "payloads",
+// multipart uploads send them as an NDJSON "file" part
+func bindInferenceSamples(c *gin.Context) (input_contracts.InferSchemaApiInputContract, []interface{}, bool) {
+	var input input_contracts.InferSchemaApiInputContract
+	isUpload := c.ContentType() == "multipart/form-data"
+
+	var err error
+	if isUpload {
+		err = c.ShouldBindWith(&input, binding.FormMultipart)
+	} else {
+		err = c.ShouldBindJSON(&input)
+	}
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, api.GetValidationErrors(validationErrors))
+		return input, nil, false
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return input, nil, false
+	}
+
+	field := "payloads"
+	var samples []interface{}
+	if isUpload {
+		field = "file"
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, api.DataValidationErrorAPIResponse{
+				Errors: []api.APIDataFieldErrorResponseField{{Field: field, Message: "This field is required"}},
+			})
+			return input, nil, false
+		}
+		content, err := readUploadedFile(fileHeader)
+		if err == nil {
+			samples, err = jsonschemainfer.DecodeNDJSON(content)
+		}
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, api.DataValidationErrorAPIResponse{
+				Errors: []api.APIDataFieldErrorResponseField{{Field: field, Message: err.Error()}},
+			})
+			return input, nil, false
+		}
+	} else {
+		for index, payload := range input.Payloads {
+			sample, err := jsonschemainfer.DecodeJSON(payload)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusUnprocessableEntity, api.DataValidationErrorAPIResponse{
+					Errors: []api.APIDataFieldErrorResponseField{{Field: field, Message: fmt.Sprintf("Payload %d: %v", index, err)}},
+				})
+				return input, nil, false
+			}
+			samples = append(samples, sample)
+		}
+	}
+
+	message := ""
+	switch {
+	case len(samples) == 0:
+		message = "Should contain at least one payload"
+	case len(samples) > maxInferenceSamples:
+		message = fmt.Sprintf("Should contain at most %d payloads", maxInferenceSamples)
+	case input.Name != "" && input.SchemaID != "":
+		field = "schema_id"
+		message = "Either name or schema_id should be set, not both"
+	}
+	if message != "" {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, api.DataValidationErrorAPIResponse{
+			Errors: []api.APIDataFieldErrorResponseField{{Field: field, Message: message}},
+		})
+		return input, nil, false
+	}
+	return input, samples, true
+}
+
+// Infer JSON schema from sample payloads
+// @Summary Infer JSON schema from sample payloads
+// @Description Infer a draft 2020-12 JSON schema from sample payloads sent in "payloads" or uploaded as an NDJSON
+// @Description "file" part of a multipart form (other fields are sent as form fields then). Types are merged across
+// @Description samples, properties present in every sample are required, strings get formats (date-time, date, time,
+// @Description uuid, email, ipv4, ipv6, uri) when all of them match and enums when they take a few repeated values.
+// @Description The inferred schema is saved as a new schema when "name" is set, or as a new version of the schema
+// @Description "schema_id" when it is set, new versions are checked against the compatibility mode of the schema.
+// @Produce json
+// @Accept json,mpfd
+// @Tags Schemas
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param samples body input_contracts.InferSchemaApiInputContract false "Sample payloads"
+// @Param file formData file false "Sample payloads, one JSON value per line (multipart upload)"
+// @Success 200 {object} logic.InferredSchemaSerializerStruct "Inferred schema and the saved schema"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} map[string]string "Project or schema not found"
+// @Failure 409 {object} logic.SchemaCompatibilityErrorSerializerStruct "The schema name is taken or the new version breaks the compatibility mode"
+// @Failure 422 {object} api.DataValidationErrorAPIResponse "Payload validation errors"
+// @Router /v1/protected/projects/{id}/schemas/infer [post]
+func InferSchemaInProjectV1(c *gin.Context) {
+	id := c.Param("id")
+	parsedProjectID, _ := uuid.Parse(id)
+
+	projectsManager := logic.ProjectsObjectsManager{}
+	_, projectError := projectsManager.GetByID(parsedProjectID)
+	if projectError != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	input, samples, ok := bindInferenceSamples(c)
+	if !ok {
+		return
+	}
+
+	schemasManager := logic.SchemaObjectsManager{}
+	title := input.Name
+	var schema *logic.SchemaObject
+	if input.SchemaID != "" {
+		var err error
+		schema, err = schemasManager.GetByID(uuid.MustParse(input.SchemaID))
+		if err != nil || schema.GetProjectID() != parsedProjectID {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Schema not found"})
+			return
+		}
+		if schema.GetType() != logic.SchemaTypeJSONSchema {
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, api.DataValidationErrorAPIResponse{
+				Errors: []api.APIDataFieldErrorResponseField{{Field: "schema_id", Message: "Should be a JSON schema"}},
+			})
+			return
+		}
+		title = schema.GetName()
+	}
+
+	inferred, err := logic.InferJSONSchema(samples, title)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := c.Get("UserID")
+	switch {
+	case input.Name != "":
+		// Making sure the schema name is unique for the project
+		if schemasManager.CheckIfThisSchemaNameAlreadyExists(parsedProjectID, input.Name) {
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "Schema with this name already exists"})
+			return
+		}
+		schema, err = schemasManager.CreateANewSchema(
+			input.Name,
+			input.Description,
+			inferred.Schema,
+			logic.SchemaTypeJSONSchema,
+			input.Compatibility,
+			"user", userID.(uuid.UUID),
+			userID.(uuid.UUID),
+			parsedProjectID,
+		)
+	case schema != nil:
+		schema, err = schema.CreateANewVersion(inferred.Schema, userID.(uuid.UUID), input.IgnoreCompatibility)
+	default:
+		c.JSON(http.StatusOK, inferred)
+		return
+	}
+
+	var compatibilityError *logic.SchemaCompatibilityError
+	if errors.As(err, &compatibilityError) {
+		c.AbortWithStatusJSON(http.StatusConflict, compatibilityError.Serialize())
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	inferred.SavedSchema = schema.Serialize()
+	c.JSON(http.StatusOK, inferred)
+}
+
+// Get structural diff between schema versions
+// @Summary Get structural diff between schema versions
+// @Description Compare two versions of a JSON schema. Changes are reported by JSON pointers of the changed
+// @Description schema locations (added, removed and changed properties, type, required set, enum and constraint changes).
+// @Description A change breaks producers when data valid against the "from" version can be invalid against the "to" version,
+// @Description and breaks consumers when data valid against the "to" version can be invalid against the "from" version.
+// @Produce json
+// @Tags Schemas
+// @Security BearerAuth
+// @Param schemaID path string true "Schema ID"
+// @Param from query int true "Version to compare from"
+// @Param to query int true "Version to compare to"
+// @Success 200 {object} logic.SchemaDiffSerializerStruct "Changes between the versions"
+// @Failure 400 {object} map[string]string "Diffs are not supported for the schema type"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} map[string]string "Schema or version not found"
+// @Failure 422 {object} api.DataValidationErrorAPIResponse "Invalid version numbers"
+// @Router /v1/protected/schemas/{schemaID}/diff [get]
+func GetSchemaVersionsDiffV1(c *gin.Context) {
+	schemaID := c.Param("schemaID")
+	parsedSchemaID, _ := uuid.Parse(schemaID)
+
+	var validationErrors []api.APIDataFieldErrorResponseField
+	versions := make(map[string]int)
+	for _, parameter := range []string{"from", "to"} {
+		version, err := strconv.Atoi(c.Query(parameter))
+		if err != nil || version < 1 {
+			validationErrors = append(validationErrors, api.APIDataFieldErrorResponseField{
+				Field:   parameter,
+				Message: "Should be a version number",
+			})
+			continue
+		}
+		versions[parameter] = version
+	}
+	if len(validationErrors) > 0 {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, api.DataValidationErrorAPIResponse{Errors: validationErrors})
+		return
+	}
+
+	schemasManager := logic.SchemaObjectsManager{}
+	schema, err := schemasManager.GetByID(parsedSchemaID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	// Schema is found, verify user has access to its project
+	projectsManager := logic.ProjectsObjectsManager{}
+	_, projectError := projectsManager.GetByID(schema.GetProjectID())
+	if projectError != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	diff, err := schema.Diff(versions["from"], versions["to"])
+	switch {
+	case errors.Is(err, logic.ErrSchemaDiffNotSupported):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, common.FusioncatErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Schema version not found"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+// Get impact of schema versions
+// @Summary Get impact of schema versions
+// @Description List messages pinned to every version of the schema, apps which send or receive the messages
+// @Description and resources the messages travel through.
+// @Produce json
+// @Tags Schemas
+// @Security BearerAuth
+// @Param schemaID path string true "Schema ID"
+// @Success 200 {object} logic.SchemaImpactSerializerStruct "Usage of the schema versions"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} map[string]string "Schema not found"
+// @Router /v1/protected/schemas/{schemaID}/impact [get]
+func GetSchemaImpactV1(c *gin.Context) {
+	analyzeSchemaImpact(c, "")
+}
+
+// Get impact of proposed schema version
+// @Summary Get impact of proposed schema version
+// @Description List messages pinned to every version of the schema, apps which send or receive the messages
+// @Description and resources the messages travel through, and check a proposed new version without creating it.
+// @Description Receivers of messages pinned to a version break when data of the proposed version is invalid
+// @Description against their version (forward violations), senders break when readers on the proposed version
+// @Description can't read data of their version (backward violations). Both directions are checked whatever
+// @Description the compatibility mode is, "compatible" tells if the compatibility mode allows the proposed version.
+// @Description Proposed versions of protobuf schemas can't be checked.
+// @Produce json
+// @Accept json
+// @Tags Schemas
+// @Security BearerAuth
+// @Param schemaID path string true "Schema ID"
+// @Param schema body input_contracts.SchemaImpactApiInputContract true "Proposed new version of the schema"
+// @Success 200 {object} logic.SchemaImpactSerializerStruct "Usage of the schema versions and apps broken by the proposed version"
+// @Failure 400 {object} map[string]string "Proposed versions are not supported for the schema type"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} map[string]string "Schema not found"
+// @Failure 422 {object} api.DataValidationErrorAPIResponse "JSON payload validation errors"
+// @Router /v1/protected/schemas/{schemaID}/impact [post]
+func ProposeSchemaVersionImpactV1(c *gin.Context) {
+	var input input_contracts.SchemaImpactApiInputContract
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, api.GetValidationErrors(err))
+		return
+	}
+
+	analyzeSchemaImpact(c, input.Schema)
+}
+
+func analyzeSchemaImpact(c *gin.Context, proposedContent string) {
+	schemaID := c.Param("schemaID")
+	parsedSchemaID, _ := uuid.Parse(schemaID)
+
+	schemasManager := logic.SchemaObjectsManager{}
+	schema, err := schemasManager.GetByID(parsedSchemaID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	// Schema is found, verify user has access to its project
+	projectsManager := logic.ProjectsObjectsManager{}
+	_, projectError := projectsManager.GetByID(schema.GetProjectID())
+	if projectError != nil {
+		c.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+
+	if proposedContent != "" && schema.GetType() != logic.SchemaTypeProtobuf {
+		if err := logic.ValidateSchemaContent(schema.GetProjectID(), schema.GetName(), schema.GetType(), proposedContent); err != nil {
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, api.GetSchemaContentValidationErrors(err))
+			return
+		}
+	}
+
+	impact, err := schema.AnalyzeImpact(proposedContent)
+	switch {
+	case errors.Is(err, logic.ErrSchemaImpactNotSupported):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, impact)
+}
+
+// Generate code from schema
+// @Summary Generate code from schema in specified language
+// @Description Generate code from schema in specified programming language. The generated code will be returned as a plain text file with appropriate content type headers.
+// @Description Code of Avro and Protocol Buffers schemas can be generated only in Go. For protobuf schemas
+// @Description the message_type query parameter selects the generated message, the first message of the file is used by default.
+// @Produce text/plain
+// @Tags Schemas
+// @Security BearerAuth
+// @Param schemaID path string true "Schema ID"
+// @Param language path string true "Programming language" Enums(typescript, java, go, python)
+// @Param message_type query string false "Full name of a message type of a protobuf schema"
+// @Success 200 {string} string "Generated code as plain text file"
+// @Failure 400 {object} map[string]string "Invalid language or schema type"
+// @Failure 401 {object} map[string]string "Access denied: missing or invalid Authorization header"
+// @Failure 404 {object} map[string]string "Schema not found"
+// @Failure 504 {object} map[string]string "Code generation timed out"
+// @Router /v1/protected/schemas/{schemaID}/code/{language} [get]
+func GenerateCodeOfSchemaV1(c *gin.Context) {
+	schemaID := c.Param("schemaID")
+	language := c.Param("language")
+	parsedSchemaID, _ := uuid.Parse(schemaID)
+
+	// Validate language parameter
+	validLanguages := map[string]bool{
+		"typescript": true,
+		"java":       true,
+		"go":         true,
+		"python":     true,
+	}
+	if !validLanguages[language] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid programming language. Supported languages are: typescript, java, go, python"})
+		return
+	}
+
+	// Get the schema
+	schemasManager := logic.SchemaObjectsManager{}
+	schema, err := schemasManager.GetByID(parsedSchemaID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Schema not found"})
+		return
+	}
+
+	// Schema is found, verify user has access to its project
+	projectsManager := logic.ProjectsObjectsManager{}
+	_, projectError := projectsManager.GetByID(schema.GetProjectID())
+	if projectError != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+
+	// Generate the code using the schema's GenerateCode method
+	generatedCode, _, err := schema.GenerateCode(c.Request.Context(), language, c.Query("message_type"))
+	if errors.Is(err, logic.ErrCodeGenerationTimedOut) {
+		c.JSON(http.StatusGatewayTimeout, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
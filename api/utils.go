@@ -2,7 +2,6 @@ package api
 
 import (
 	"github.com/go-playground/validator/v10"
-	"github.com/santhosh-tekuri/jsonschema/v5"
 	"strings"
 )
 
@@ -33,34 +32,12 @@ func getErrorMsg(fe validator.FieldError) string {
 		return "Should be greater than " + fe.Param()
 	case "email":
 		return "This field is not a valid email"
+	case "oneof":
+		return "Should be one of: " + strings.ReplaceAll(fe.Param(), " ", ", ")
 	case "contains_valid_stringified_json":
 		return "This field should contain valid stringified JSONs"
+	case "valid_existing_schema_id_and_version":
 		return "Invalid reference to schema or to schmea version"
-	case "valid_json_schema":
-		f := fe.Value().(string)
-
-		// For some reason it's not possible to return the error message
-		// from validation of JSON schema, so we need to compile it again
-		// to get the error message with all the details
-		_, err := jsonschema.CompileString("", f)
-		if err != nil {
-
-			originalString := err.Error()
-			substring := "compilation failed:"
-			errorMessage := ""
-			index := strings.Index(originalString, substring)
-			if index != -1 {
-				// Add the length of the substring to skip it too
-				result := originalString[index+len(substring):]
-				errorMessage = strings.TrimSpace(result)
-			} else {
-				errorMessage = originalString
-			}
-
-			return "Invalid JSON schema: " + errorMessage
-		}
-
-		return "Invalid JSON schema"
 	}
 	return "Unknown error"
 }
@@ -77,3 +54,13 @@ func GetValidationErrors(err error) DataValidationErrorAPIResponse {
 	response := DataValidationErrorAPIResponse{Errors: errors}
 	return response
 }
+
+// GetSchemaContentValidationErrors reports an invalid schema content in the same format
+// as validation errors of other fields
+func GetSchemaContentValidationErrors(err error) DataValidationErrorAPIResponse {
+	message := err.Error()
+	return DataValidationErrorAPIResponse{Errors: []APIDataFieldErrorResponseField{{
+		Field:   "schema",
+		Message: strings.ToUpper(message[:1]) + message[1:],
+	}}}
+}
@@ -0,0 +1,237 @@
+package avro
+
+import (
+	"fmt"
+	"go/format"
+	"strings"
+)
+
+// GenerateGo generates Go types for the schema. The top-level type gets typeName, other named types
+// get typeName as a prefix, so that types of several schemas can be placed into the same package.
+// Struct fields are tagged with the names of Avro fields for both encoding/json and Avro libraries
+// which map records to structs by "avro" tags. Optional values (unions with null and one other type)
+// are pointers, other unions are interface{}.
+func GenerateGo(schema *Schema, typeName string) (string, error) {
+	generator := &goGenerator{
+		rootName:  typeName,
+		typeNames: make(map[*Schema]string),
+		usedNames: map[string]bool{typeName: true},
+	}
+
+	if schema.Type == TypeUnion {
+		// Methods can't be declared on pointer and interface types, a top-level optional value
+		// gets the type of its non-null branch
+		topLevel := optionalBranch(schema)
+		if topLevel == nil && len(schema.Branches) == 1 {
+			topLevel = schema.Branches[0]
+		}
+		if topLevel == nil {
+			return "", fmt.Errorf("code can't be generated for top-level unions of several types")
+		}
+		schema = topLevel
+	}
+	generator.typeNames[schema] = typeName
+
+	if schema.IsNamed() {
+		generator.declare(schema, typeName)
+	} else {
+		generator.writeDoc(schema.Doc, "")
+		fmt.Fprintf(&generator.output, "type %s %s\n", typeName, generator.goType(schema, nil))
+	}
+	// Named types are declared in the order they are referenced
+	for index := 0; index < len(generator.pending); index++ {
+		generator.output.WriteString("\n")
+		generator.declare(generator.pending[index], generator.typeNames[generator.pending[index]])
+	}
+
+	formatted, err := format.Source([]byte(generator.output.String()))
+	if err != nil {
+		return "", fmt.Errorf("failed to format generated code: %v", err)
+	}
+	return string(formatted), nil
+}
+
+type goGenerator struct {
+	rootName  string
+	typeNames map[*Schema]string
+	usedNames map[string]bool
+	pending   []*Schema
+	output    strings.Builder
+}
+
+// namedType returns the Go name of a named type and schedules its declaration
+func (generator *goGenerator) namedType(schema *Schema) string {
+	if name, exists := generator.typeNames[schema]; exists {
+		return name
+	}
+	baseName := generator.rootName + exportedGoName(schema.ShortName())
+	name := baseName
+	for suffix := 2; generator.usedNames[name]; suffix++ {
+		name = fmt.Sprintf("%s%d", baseName, suffix)
+	}
+	generator.usedNames[name] = true
+	generator.typeNames[schema] = name
+	generator.pending = append(generator.pending, schema)
+	return name
+}
+
+// goType returns the Go type of the schema used in a field of the record
+func (generator *goGenerator) goType(schema *Schema, record *Schema) string {
+	switch schema.Type {
+	case TypeNull:
+		return "interface{}"
+	case TypeBoolean:
+		return "bool"
+	case TypeInt:
+		return "int32"
+	case TypeLong:
+		return "int64"
+	case TypeFloat:
+		return "float32"
+	case TypeDouble:
+		return "float64"
+	case TypeBytes:
+		return "[]byte"
+	case TypeString:
+		return "string"
+	case TypeArray:
+		return "[]" + generator.goType(schema.Items, nil)
+	case TypeMap:
+		return "map[string]" + generator.goType(schema.Values, nil)
+	case TypeRecord:
+		// A struct can't contain itself, records which lead back to the record are referenced by pointers
+		if record != nil && containsByValue(schema, record, make(map[*Schema]bool)) {
+			return "*" + generator.namedType(schema)
+		}
+		return generator.namedType(schema)
+	case TypeEnum, TypeFixed:
+		return generator.namedType(schema)
+	case TypeUnion:
+		optional := optionalBranch(schema)
+		if optional == nil {
+			if len(schema.Branches) == 1 {
+				return generator.goType(schema.Branches[0], record)
+			}
+			return "interface{}"
+		}
+		elementType := generator.goType(optional, nil)
+		if strings.HasPrefix(elementType, "*") || strings.HasPrefix(elementType, "[]") ||
+			strings.HasPrefix(elementType, "map[") || elementType == "interface{}" {
+			return elementType
+		}
+		return "*" + elementType
+	}
+	return "interface{}"
+}
+
+func (generator *goGenerator) declare(schema *Schema, name string) {
+	generator.writeDoc(schema.Doc, "")
+	switch schema.Type {
+	case TypeRecord:
+		fmt.Fprintf(&generator.output, "type %s struct {\n", name)
+		usedFieldNames := make(map[string]bool)
+		for _, field := range schema.Fields {
+			fieldName := exportedGoName(field.Name)
+			for suffix := 2; usedFieldNames[fieldName]; suffix++ {
+				fieldName = fmt.Sprintf("%s%d", exportedGoName(field.Name), suffix)
+			}
+			usedFieldNames[fieldName] = true
+
+			generator.writeDoc(field.Doc, "\t")
+			fmt.Fprintf(&generator.output, "\t%s %s `json:\"%s\" avro:\"%s\"`", fieldName,
+				generator.goType(field.Type, schema), field.Name, field.Name)
+			if logicalType := fieldLogicalType(field.Type); logicalType != "" {
+				fmt.Fprintf(&generator.output, " // %s", logicalType)
+			}
+			generator.output.WriteString("\n")
+		}
+		generator.output.WriteString("}\n")
+	case TypeEnum:
+		fmt.Fprintf(&generator.output, "type %s string\n\nconst (\n", name)
+		for _, symbol := range schema.Symbols {
+			fmt.Fprintf(&generator.output, "\t%s%s %s = %q\n", name, exportedGoName(symbol), name, symbol)
+		}
+		generator.output.WriteString(")\n")
+	case TypeFixed:
+		fmt.Fprintf(&generator.output, "type %s [%d]byte\n", name, schema.Size)
+	}
+}
+
+func (generator *goGenerator) writeDoc(doc string, indent string) {
+	doc = strings.TrimSpace(doc)
+	if doc == "" {
+		return
+	}
+	for _, line := range strings.Split(doc, "\n") {
+		fmt.Fprintf(&generator.output, "%s// %s\n", indent, strings.TrimSpace(line))
+	}
+}
+
+// optionalBranch returns the only non-null type of a union with null, nil for other unions
+func optionalBranch(union *Schema) *Schema {
+	if len(union.Branches) != 2 {
+		return nil
+	}
+	if union.Branches[0].Type == TypeNull {
+		return union.Branches[1]
+	}
+	if union.Branches[1].Type == TypeNull {
+		return union.Branches[0]
+	}
+	return nil
+}
+
+// containsByValue tells if the schema leads to the target record through fields which are
+// not behind a pointer, a slice or a map
+func containsByValue(schema *Schema, target *Schema, visited map[*Schema]bool) bool {
+	if schema == target {
+		return true
+	}
+	if visited[schema] {
+		return false
+	}
+	visited[schema] = true
+	if schema.Type == TypeUnion && len(schema.Branches) == 1 {
+		return containsByValue(schema.Branches[0], target, visited)
+	}
+	if schema.Type != TypeRecord {
+		return false
+	}
+	for _, field := range schema.Fields {
+		if containsByValue(field.Type, target, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldLogicalType describes the logical type of a field for a comment in the generated code
+func fieldLogicalType(schema *Schema) string {
+	if schema.Type == TypeUnion {
+		if optional := optionalBranch(schema); optional != nil {
+			schema = optional
+		}
+	}
+	if schema.Type == TypeArray || schema.Type == TypeMap || (schema.IsNamed() && schema.Type != TypeFixed) {
+		return ""
+	}
+	if schema.LogicalType == "decimal" {
+		return fmt.Sprintf("decimal(%d, %d)", schema.Precision, schema.Scale)
+	}
+	return schema.LogicalType
+}
+
+// exportedGoName converts an Avro name (snake_case or camelCase) to an exported Go identifier
+func exportedGoName(name string) string {
+	var builder strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		if part == "" {
+			continue
+		}
+		builder.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	if builder.Len() == 0 {
+		return "X"
+	}
+	return builder.String()
+}
@@ -0,0 +1,179 @@
+package avro
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Incompatibility tells why data written with one schema can't be read with another.
+// Path is a JSON pointer to the value in the data, e.g. /address/street.
+type Incompatibility struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (incompatibility *Incompatibility) Error() string {
+	if incompatibility.Path == "" {
+		return incompatibility.Message
+	}
+	return incompatibility.Path + ": " + incompatibility.Message
+}
+
+// CheckCompatibility checks that data written with the writer schema can be read with the reader
+// schema following the schema resolution rules of the specification: fields missing in the writer
+// need defaults, numeric types can only be promoted, enum symbols of the writer must be known
+// to the reader unless the reader has a default symbol, every branch of a writer union must resolve.
+func CheckCompatibility(reader *Schema, writer *Schema) []Incompatibility {
+	checker := &compatibilityChecker{visited: make(map[[2]*Schema]bool)}
+	checker.check(reader, writer, "")
+	return checker.incompatibilities
+}
+
+type compatibilityChecker struct {
+	// visited holds pairs of named types which are checked already, recursive records are checked once
+	visited           map[[2]*Schema]bool
+	incompatibilities []Incompatibility
+}
+
+func (checker *compatibilityChecker) errorf(path string, format string, args ...interface{}) {
+	checker.incompatibilities = append(checker.incompatibilities, Incompatibility{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (checker *compatibilityChecker) check(reader *Schema, writer *Schema, path string) {
+	if writer.Type == TypeUnion {
+		// Every value of the writer union must be readable
+		for _, branch := range writer.Branches {
+			checker.check(reader, branch, path)
+		}
+		return
+	}
+	if reader.Type == TypeUnion {
+		for _, branch := range reader.Branches {
+			// Pairs being checked up the stack are assumed to be compatible, so that recursive types terminate
+			attempt := &compatibilityChecker{visited: make(map[[2]*Schema]bool, len(checker.visited))}
+			for pair := range checker.visited {
+				attempt.visited[pair] = true
+			}
+			attempt.check(branch, writer, path)
+			if len(attempt.incompatibilities) == 0 {
+				return
+			}
+		}
+		checker.errorf(path, "%s written by the writer doesn't match any type of the reader union", writer.TypeName())
+		return
+	}
+
+	if reader.IsNamed() && writer.IsNamed() {
+		pair := [2]*Schema{reader, writer}
+		if checker.visited[pair] {
+			return
+		}
+		checker.visited[pair] = true
+	}
+
+	if reader.Type != writer.Type {
+		if !isPromotable(writer.Type, reader.Type) {
+			checker.errorf(path, "%s written by the writer can't be read as %s", writer.TypeName(), reader.TypeName())
+		}
+		return
+	}
+
+	switch reader.Type {
+	case TypeRecord:
+		if !namesMatch(reader, writer) {
+			checker.errorf(path, "record %s can't be read as record %s", writer.Name, reader.Name)
+			return
+		}
+		for _, readerField := range reader.Fields {
+			fieldPath := path + "/" + escapePointer(readerField.Name)
+			writerField := writerFieldFor(writer, readerField)
+			if writerField == nil {
+				if !readerField.HasDefault {
+					checker.errorf(fieldPath, "field %q is missing in data of the writer and has no default", readerField.Name)
+				}
+				continue
+			}
+			checker.check(readerField.Type, writerField.Type, fieldPath)
+		}
+	case TypeEnum:
+		if !namesMatch(reader, writer) {
+			checker.errorf(path, "enum %s can't be read as enum %s", writer.Name, reader.Name)
+			return
+		}
+		if reader.EnumDefault != "" {
+			return
+		}
+		var unknownSymbols []string
+		for _, symbol := range writer.Symbols {
+			if !containsString(reader.Symbols, symbol) {
+				unknownSymbols = append(unknownSymbols, symbol)
+			}
+		}
+		if len(unknownSymbols) > 0 {
+			checker.errorf(path, "symbols %s of enum %s are unknown to the reader, which has no default symbol",
+				strings.Join(unknownSymbols, ", "), writer.Name)
+		}
+	case TypeFixed:
+		if !namesMatch(reader, writer) {
+			checker.errorf(path, "fixed %s can't be read as fixed %s", writer.Name, reader.Name)
+		} else if reader.Size != writer.Size {
+			checker.errorf(path, "size of fixed %s changed from %d to %d", reader.Name, writer.Size, reader.Size)
+		}
+	case TypeArray:
+		checker.check(reader.Items, writer.Items, path+"/items")
+	case TypeMap:
+		checker.check(reader.Values, writer.Values, path+"/values")
+	}
+}
+
+// isPromotable tells if values of the writer type can be read as the reader type
+func isPromotable(writerType string, readerType string) bool {
+	switch writerType {
+	case TypeInt:
+		return readerType == TypeLong || readerType == TypeFloat || readerType == TypeDouble
+	case TypeLong:
+		return readerType == TypeFloat || readerType == TypeDouble
+	case TypeFloat:
+		return readerType == TypeDouble
+	case TypeString:
+		return readerType == TypeBytes
+	case TypeBytes:
+		return readerType == TypeString
+	}
+	return false
+}
+
+// namesMatch compares unqualified names of named types, aliases of the reader are taken into account
+func namesMatch(reader *Schema, writer *Schema) bool {
+	if reader.ShortName() == writer.ShortName() {
+		return true
+	}
+	for _, alias := range reader.Aliases {
+		if alias == writer.Name || alias[strings.LastIndex(alias, ".")+1:] == writer.ShortName() {
+			return true
+		}
+	}
+	return false
+}
+
+// writerFieldFor finds the field of the writer record which the reader field reads
+func writerFieldFor(writer *Schema, readerField *Field) *Field {
+	if field := writer.Field(readerField.Name); field != nil {
+		return field
+	}
+	for _, alias := range readerField.Aliases {
+		if field := writer.Field(alias); field != nil {
+			return field
+		}
+	}
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package avro
+
+import (
+	"strings"
+
+	"github.com/fusioncatltd/fusioncat/fakedata"
+)
+
+// maxExampleDepth limits nesting of examples of recursive schemas: deeper arrays and maps are empty
+// and unions prefer null and non-record branches
+const maxExampleDepth = 8
+
+// Example generates an example value in the Avro JSON encoding, the same encoding ValidateJSON expects
+func (schema *Schema) Example(data *fakedata.Generator) interface{} {
+	return example(schema, "", data, 0)
+}
+
+func example(schema *Schema, name string, data *fakedata.Generator, depth int) interface{} {
+	switch schema.Type {
+	case TypeNull:
+		return nil
+	case TypeBoolean:
+		return data.Bool()
+	case TypeInt:
+		switch schema.LogicalType {
+		case "date":
+			return data.Time().Unix() / (24 * 60 * 60)
+		case "time-millis":
+			return data.IntBetween(0, 24*60*60*1000-1)
+		}
+		return data.IntBetween(1, 1000)
+	case TypeLong:
+		switch schema.LogicalType {
+		case "timestamp-millis", "local-timestamp-millis":
+			return data.Time().UnixMilli()
+		case "timestamp-micros", "local-timestamp-micros":
+			return data.Time().UnixMicro()
+		case "timestamp-nanos", "local-timestamp-nanos":
+			return data.Time().UnixNano()
+		case "time-micros":
+			return data.IntBetween(0, 24*60*60*1000*1000-1)
+		}
+		return data.IntBetween(1, 100000)
+	case TypeFloat, TypeDouble:
+		return data.FloatBetween(0, 1000)
+	case TypeString:
+		if schema.LogicalType == "uuid" {
+			return data.UUID()
+		}
+		if value, known := data.ForName(name); known {
+			return value
+		}
+		return data.Word()
+	case TypeBytes:
+		return data.Word()
+	case TypeFixed:
+		// Fixed values are strings of exactly Size code points from U+0000 to U+00FF
+		var builder strings.Builder
+		for index := 0; index < schema.Size; index++ {
+			builder.WriteRune(rune('a' + data.Intn(26)))
+		}
+		return builder.String()
+	case TypeEnum:
+		return schema.Symbols[data.Intn(len(schema.Symbols))]
+	case TypeArray:
+		items := make([]interface{}, 0)
+		if depth < maxExampleDepth {
+			for count := 1 + data.Intn(3); len(items) < count; {
+				items = append(items, example(schema.Items, fakedata.Singular(name), data, depth+1))
+			}
+		}
+		return items
+	case TypeMap:
+		values := make(map[string]interface{})
+		if depth < maxExampleDepth {
+			for count := 1 + data.Intn(3); len(values) < count; {
+				values[data.Word()] = example(schema.Values, "", data, depth+1)
+			}
+		}
+		return values
+	case TypeRecord:
+		record := make(map[string]interface{}, len(schema.Fields))
+		for _, field := range schema.Fields {
+			record[field.Name] = example(field.Type, field.Name, data, depth+1)
+		}
+		return record
+	case TypeUnion:
+		branch := schema.Branches[data.Intn(len(schema.Branches))]
+		if depth >= maxExampleDepth {
+			branch = shallowestBranch(schema.Branches)
+		}
+		if branch.Type == TypeNull {
+			return nil
+		}
+		return map[string]interface{}{branch.TypeName(): example(branch, name, data, depth+1)}
+	}
+	return nil
+}
+
+// shallowestBranch prefers branches which don't nest other values, so that recursion stops
+func shallowestBranch(branches []*Schema) *Schema {
+	for _, branch := range branches {
+		if branch.Type == TypeNull {
+			return branch
+		}
+	}
+	for _, branch := range branches {
+		if branch.Type != TypeRecord && branch.Type != TypeArray && branch.Type != TypeMap {
+			return branch
+		}
+	}
+	return branches[0]
+}
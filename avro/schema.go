@@ -0,0 +1,520 @@
+// Package avro implements the parts of the Apache Avro specification Fusioncat needs to manage
+// Avro schemas: parsing of schema definitions (.avsc), validation of JSON-encoded data and
+// generation of Go types. The binary encoding is out of scope, data is produced and consumed
+// by applications with their own Avro libraries.
+//
+//	{
+//	  "type": "record",
+//	  "name": "User",
+//	  "namespace": "com.example",
+//	  "fields": [
+//	    {"name": "id", "type": "string"},
+//	    {"name": "email", "type": ["null", "string"], "default": null}
+//	  ]
+//	}
+package avro
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Types of Avro schemas. Unions are written as JSON arrays in schema definitions,
+// they get the "union" type to be represented the same way as other schemas.
+const (
+	TypeNull    = "null"
+	TypeBoolean = "boolean"
+	TypeInt     = "int"
+	TypeLong    = "long"
+	TypeFloat   = "float"
+	TypeDouble  = "double"
+	TypeBytes   = "bytes"
+	TypeString  = "string"
+	TypeRecord  = "record"
+	TypeEnum    = "enum"
+	TypeArray   = "array"
+	TypeMap     = "map"
+	TypeFixed   = "fixed"
+	TypeUnion   = "union"
+)
+
+var primitiveTypes = map[string]bool{
+	TypeNull:    true,
+	TypeBoolean: true,
+	TypeInt:     true,
+	TypeLong:    true,
+	TypeFloat:   true,
+	TypeDouble:  true,
+	TypeBytes:   true,
+	TypeString:  true,
+}
+
+var namePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Schema is a parsed Avro schema. Every reference to a named type (record, enum or fixed) points
+// at the same Schema, so recursive records form cycles.
+type Schema struct {
+	Type string
+	// Full name of named types, including the namespace
+	Name    string
+	Doc     string
+	Aliases []string
+
+	Fields      []*Field  // record
+	Symbols     []string  // enum
+	EnumDefault string    // enum
+	Items       *Schema   // array
+	Values      *Schema   // map
+	Size        int       // fixed
+	Branches    []*Schema // union
+
+	// Logical types annotate primitive and fixed types, invalid logical types are dropped
+	// as the specification requires
+	LogicalType string
+	Precision   int
+	Scale       int
+}
+
+// Field is a field of a record
+type Field struct {
+	Name       string
+	Doc        string
+	Aliases    []string
+	Type       *Schema
+	Default    interface{}
+	HasDefault bool
+	Order      string
+}
+
+// IsNamed tells if the schema is a named type which can be referenced by its name
+func (schema *Schema) IsNamed() bool {
+	return schema.Type == TypeRecord || schema.Type == TypeEnum || schema.Type == TypeFixed
+}
+
+// ShortName is the name of a named type without the namespace
+func (schema *Schema) ShortName() string {
+	return schema.Name[strings.LastIndex(schema.Name, ".")+1:]
+}
+
+// Namespace is the namespace of a named type, empty for the null namespace
+func (schema *Schema) Namespace() string {
+	if index := strings.LastIndex(schema.Name, "."); index >= 0 {
+		return schema.Name[:index]
+	}
+	return ""
+}
+
+// TypeName identifies the schema in unions: full name of named types and the type of other schemas
+func (schema *Schema) TypeName() string {
+	if schema.IsNamed() {
+		return schema.Name
+	}
+	return schema.Type
+}
+
+// Field returns a field of the record by its name
+func (schema *Schema) Field(name string) *Field {
+	for _, field := range schema.Fields {
+		if field.Name == name {
+			return field
+		}
+	}
+	return nil
+}
+
+// SchemaError is an error in a schema definition. Path points at the invalid part of the definition,
+// e.g. "fields[1].type".
+type SchemaError struct {
+	Path    string
+	Message string
+}
+
+func (err *SchemaError) Error() string {
+	if err.Path == "" {
+		return err.Message
+	}
+	return err.Path + ": " + err.Message
+}
+
+// Parse parses an Avro schema definition
+func Parse(source string) (*Schema, error) {
+	decoder := json.NewDecoder(strings.NewReader(source))
+	decoder.UseNumber()
+	var definition interface{}
+	if err := decoder.Decode(&definition); err != nil {
+		return nil, &SchemaError{Message: fmt.Sprintf("invalid JSON: %v", err)}
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, &SchemaError{Message: "invalid JSON: unexpected data after the schema"}
+	}
+
+	parser := &schemaParser{namedTypes: make(map[string]*Schema)}
+	return parser.parse(definition, "", "")
+}
+
+type schemaParser struct {
+	namedTypes map[string]*Schema
+}
+
+func (parser *schemaParser) errorf(path string, format string, args ...interface{}) error {
+	return &SchemaError{Path: path, Message: fmt.Sprintf(format, args...)}
+}
+
+// parse parses a definition in the enclosing namespace
+func (parser *schemaParser) parse(definition interface{}, namespace string, path string) (*Schema, error) {
+	switch typedDefinition := definition.(type) {
+	case string:
+		return parser.reference(typedDefinition, namespace, path)
+	case []interface{}:
+		return parser.parseUnion(typedDefinition, namespace, path)
+	case map[string]interface{}:
+		return parser.parseObject(typedDefinition, namespace, path)
+	}
+	return nil, parser.errorf(path, "schema must be a type name, an object or an array of types")
+}
+
+// reference resolves a primitive type or a named type defined earlier
+func (parser *schemaParser) reference(name string, namespace string, path string) (*Schema, error) {
+	if primitiveTypes[name] {
+		return &Schema{Type: name}, nil
+	}
+	if named, exists := parser.namedTypes[qualifyName(name, namespace)]; exists {
+		return named, nil
+	}
+	if named, exists := parser.namedTypes[name]; exists {
+		return named, nil
+	}
+	return nil, parser.errorf(path, "unknown type %q", name)
+}
+
+func (parser *schemaParser) parseUnion(definitions []interface{}, namespace string, path string) (*Schema, error) {
+	if len(definitions) == 0 {
+		return nil, parser.errorf(path, "union must have at least one type")
+	}
+	union := &Schema{Type: TypeUnion}
+	seen := make(map[string]bool)
+	for index, definition := range definitions {
+		branchPath := fmt.Sprintf("%s[%d]", path, index)
+		branch, err := parser.parse(definition, namespace, branchPath)
+		if err != nil {
+			return nil, err
+		}
+		if branch.Type == TypeUnion {
+			return nil, parser.errorf(branchPath, "unions can't contain other unions")
+		}
+		if seen[branch.TypeName()] {
+			return nil, parser.errorf(branchPath, "union contains type %q more than once", branch.TypeName())
+		}
+		seen[branch.TypeName()] = true
+		union.Branches = append(union.Branches, branch)
+	}
+	return union, nil
+}
+
+func (parser *schemaParser) parseObject(definition map[string]interface{}, namespace string, path string) (*Schema, error) {
+	rawType, exists := definition["type"]
+	if !exists {
+		return nil, parser.errorf(path, "\"type\" is required")
+	}
+	typeName, isString := rawType.(string)
+	if !isString {
+		// {"type": {...}} and {"type": [...]} are the same as the nested definition
+		return parser.parse(rawType, namespace, joinPath(path, "type"))
+	}
+
+	var schema *Schema
+	var err error
+	switch {
+	case primitiveTypes[typeName]:
+		schema = &Schema{Type: typeName}
+	case typeName == TypeRecord || typeName == "error":
+		schema, err = parser.parseRecord(definition, namespace, path)
+	case typeName == TypeEnum:
+		schema, err = parser.parseEnum(definition, namespace, path)
+	case typeName == TypeFixed:
+		schema, err = parser.parseFixed(definition, namespace, path)
+	case typeName == TypeArray:
+		schema, err = parser.parseContainer(definition, "items", namespace, path)
+	case typeName == TypeMap:
+		schema, err = parser.parseContainer(definition, "values", namespace, path)
+	default:
+		return parser.reference(typeName, namespace, joinPath(path, "type"))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if logicalType, isString := definition["logicalType"].(string); isString {
+		parser.applyLogicalType(schema, logicalType, definition)
+	}
+	return schema, nil
+}
+
+// defineNamedType reads the name of a named type and registers it, so that it can be referenced
+// by the types defined after it (and by its own fields)
+func (parser *schemaParser) defineNamedType(schema *Schema, definition map[string]interface{}, namespace string, path string) (string, error) {
+	name, isString := definition["name"].(string)
+	if !isString || name == "" {
+		return "", parser.errorf(path, "\"name\" is required for %s types", schema.Type)
+	}
+	if ownNamespace, exists := definition["namespace"]; exists && !strings.Contains(name, ".") {
+		namespaceString, isString := ownNamespace.(string)
+		if !isString {
+			return "", parser.errorf(joinPath(path, "namespace"), "namespace must be a string")
+		}
+		namespace = namespaceString
+	}
+	fullName := qualifyName(name, namespace)
+	if err := validateFullName(fullName); err != nil {
+		return "", parser.errorf(joinPath(path, "name"), "%v", err)
+	}
+	if primitiveTypes[fullName] {
+		return "", parser.errorf(joinPath(path, "name"), "%q is a primitive type and can't be redefined", fullName)
+	}
+	if _, exists := parser.namedTypes[fullName]; exists {
+		return "", parser.errorf(joinPath(path, "name"), "type %q is defined more than once", fullName)
+	}
+
+	schema.Name = fullName
+	schema.Doc, _ = definition["doc"].(string)
+	aliases, err := parser.parseAliases(definition, schema.Namespace(), path)
+	if err != nil {
+		return "", err
+	}
+	schema.Aliases = aliases
+	parser.namedTypes[fullName] = schema
+	return schema.Namespace(), nil
+}
+
+func (parser *schemaParser) parseAliases(definition map[string]interface{}, namespace string, path string) ([]string, error) {
+	rawAliases, exists := definition["aliases"]
+	if !exists {
+		return nil, nil
+	}
+	aliasList, isList := rawAliases.([]interface{})
+	if !isList {
+		return nil, parser.errorf(joinPath(path, "aliases"), "aliases must be an array of names")
+	}
+	var aliases []string
+	for index, rawAlias := range aliasList {
+		alias, isString := rawAlias.(string)
+		aliasPath := fmt.Sprintf("%s[%d]", joinPath(path, "aliases"), index)
+		if !isString {
+			return nil, parser.errorf(aliasPath, "alias must be a string")
+		}
+		if namespace != "" {
+			alias = qualifyName(alias, namespace)
+		}
+		if err := validateFullName(alias); err != nil {
+			return nil, parser.errorf(aliasPath, "%v", err)
+		}
+		aliases = append(aliases, alias)
+	}
+	return aliases, nil
+}
+
+func (parser *schemaParser) parseRecord(definition map[string]interface{}, namespace string, path string) (*Schema, error) {
+	record := &Schema{Type: TypeRecord}
+	recordNamespace, err := parser.defineNamedType(record, definition, namespace, path)
+	if err != nil {
+		return nil, err
+	}
+
+	rawFields, isList := definition["fields"].([]interface{})
+	if !isList {
+		return nil, parser.errorf(path, "\"fields\" is required for record %s and must be an array", record.Name)
+	}
+	fieldNames := make(map[string]bool)
+	for index, rawField := range rawFields {
+		fieldPath := fmt.Sprintf("%s[%d]", joinPath(path, "fields"), index)
+		fieldDefinition, isObject := rawField.(map[string]interface{})
+		if !isObject {
+			return nil, parser.errorf(fieldPath, "field must be an object")
+		}
+
+		name, _ := fieldDefinition["name"].(string)
+		if !namePattern.MatchString(name) {
+			return nil, parser.errorf(joinPath(fieldPath, "name"), "invalid field name %q", name)
+		}
+		if fieldNames[name] {
+			return nil, parser.errorf(joinPath(fieldPath, "name"), "field %q is defined more than once", name)
+		}
+		fieldNames[name] = true
+
+		rawType, exists := fieldDefinition["type"]
+		if !exists {
+			return nil, parser.errorf(fieldPath, "\"type\" is required for field %q", name)
+		}
+		fieldType, err := parser.parse(rawType, recordNamespace, joinPath(fieldPath, "type"))
+		if err != nil {
+			return nil, err
+		}
+
+		field := &Field{Name: name, Type: fieldType}
+		field.Doc, _ = fieldDefinition["doc"].(string)
+		if field.Aliases, err = parser.parseAliases(fieldDefinition, "", fieldPath); err != nil {
+			return nil, err
+		}
+		if rawOrder, exists := fieldDefinition["order"]; exists {
+			order, _ := rawOrder.(string)
+			if order != "ascending" && order != "descending" && order != "ignore" {
+				return nil, parser.errorf(joinPath(fieldPath, "order"), "order must be one of: ascending, descending, ignore")
+			}
+			field.Order = order
+		}
+		if defaultValue, exists := fieldDefinition["default"]; exists {
+			if err := validateDefault(fieldType, defaultValue); err != nil {
+				return nil, parser.errorf(joinPath(fieldPath, "default"), "invalid default value of field %q: %v", name, err)
+			}
+			field.Default = defaultValue
+			field.HasDefault = true
+		}
+		record.Fields = append(record.Fields, field)
+	}
+	return record, nil
+}
+
+func (parser *schemaParser) parseEnum(definition map[string]interface{}, namespace string, path string) (*Schema, error) {
+	enum := &Schema{Type: TypeEnum}
+	if _, err := parser.defineNamedType(enum, definition, namespace, path); err != nil {
+		return nil, err
+	}
+
+	rawSymbols, isList := definition["symbols"].([]interface{})
+	if !isList {
+		return nil, parser.errorf(path, "\"symbols\" is required for enum %s and must be an array", enum.Name)
+	}
+	seen := make(map[string]bool)
+	for index, rawSymbol := range rawSymbols {
+		symbol, _ := rawSymbol.(string)
+		symbolPath := fmt.Sprintf("%s[%d]", joinPath(path, "symbols"), index)
+		if !namePattern.MatchString(symbol) {
+			return nil, parser.errorf(symbolPath, "invalid enum symbol %q", symbol)
+		}
+		if seen[symbol] {
+			return nil, parser.errorf(symbolPath, "symbol %q is defined more than once", symbol)
+		}
+		seen[symbol] = true
+		enum.Symbols = append(enum.Symbols, symbol)
+	}
+
+	if rawDefault, exists := definition["default"]; exists {
+		defaultSymbol, _ := rawDefault.(string)
+		if !seen[defaultSymbol] {
+			return nil, parser.errorf(joinPath(path, "default"), "default %q is not a symbol of enum %s", defaultSymbol, enum.Name)
+		}
+		enum.EnumDefault = defaultSymbol
+	}
+	return enum, nil
+}
+
+func (parser *schemaParser) parseFixed(definition map[string]interface{}, namespace string, path string) (*Schema, error) {
+	fixed := &Schema{Type: TypeFixed}
+	if _, err := parser.defineNamedType(fixed, definition, namespace, path); err != nil {
+		return nil, err
+	}
+	size, isInt := asInt(definition["size"])
+	if !isInt || size < 0 {
+		return nil, parser.errorf(path, "\"size\" is required for fixed %s and must be a non-negative integer", fixed.Name)
+	}
+	fixed.Size = size
+	return fixed, nil
+}
+
+// parseContainer parses arrays and maps, attribute is "items" or "values"
+func (parser *schemaParser) parseContainer(definition map[string]interface{}, attribute string, namespace string, path string) (*Schema, error) {
+	rawElement, exists := definition[attribute]
+	if !exists {
+		return nil, parser.errorf(path, "%q is required for %s types", attribute, definition["type"])
+	}
+	element, err := parser.parse(rawElement, namespace, joinPath(path, attribute))
+	if err != nil {
+		return nil, err
+	}
+	if attribute == "items" {
+		return &Schema{Type: TypeArray, Items: element}, nil
+	}
+	return &Schema{Type: TypeMap, Values: element}, nil
+}
+
+// applyLogicalType annotates the schema with the logical type if it is valid for the schema.
+// Unknown logical types are kept, so that they are visible in generated code.
+func (parser *schemaParser) applyLogicalType(schema *Schema, logicalType string, definition map[string]interface{}) {
+	validFor := map[string][]string{
+		"decimal":                {TypeBytes, TypeFixed},
+		"uuid":                   {TypeString, TypeFixed},
+		"date":                   {TypeInt},
+		"time-millis":            {TypeInt},
+		"time-micros":            {TypeLong},
+		"timestamp-millis":       {TypeLong},
+		"timestamp-micros":       {TypeLong},
+		"timestamp-nanos":        {TypeLong},
+		"local-timestamp-millis": {TypeLong},
+		"local-timestamp-micros": {TypeLong},
+		"local-timestamp-nanos":  {TypeLong},
+		"duration":               {TypeFixed},
+	}
+	if types, known := validFor[logicalType]; known {
+		valid := false
+		for _, validType := range types {
+			valid = valid || schema.Type == validType
+		}
+		if !valid || (logicalType == "duration" && schema.Size != 12) {
+			return
+		}
+	}
+
+	if logicalType == "decimal" {
+		precision, precisionIsInt := asInt(definition["precision"])
+		scale, scaleIsInt := asInt(definition["scale"])
+		if _, exists := definition["scale"]; !exists {
+			scale, scaleIsInt = 0, true
+		}
+		if !precisionIsInt || precision <= 0 || !scaleIsInt || scale < 0 || scale > precision {
+			return
+		}
+		schema.Precision = precision
+		schema.Scale = scale
+	}
+	schema.LogicalType = logicalType
+}
+
+// qualifyName adds the namespace to names which don't contain one
+func qualifyName(name string, namespace string) string {
+	if strings.Contains(name, ".") || namespace == "" {
+		return name
+	}
+	return namespace + "." + name
+}
+
+func validateFullName(fullName string) error {
+	for _, part := range strings.Split(fullName, ".") {
+		if !namePattern.MatchString(part) {
+			return fmt.Errorf("invalid name %q", fullName)
+		}
+	}
+	return nil
+}
+
+func joinPath(path string, element string) string {
+	if path == "" {
+		return element
+	}
+	return path + "." + element
+}
+
+// asInt reads an integer from a decoded JSON value
+func asInt(value interface{}) (int, bool) {
+	switch number := value.(type) {
+	case json.Number:
+		parsed, err := strconv.Atoi(number.String())
+		return parsed, err == nil
+	case float64:
+		return int(number), number == float64(int(number))
+	}
+	return 0, false
+}
@@ -0,0 +1,238 @@
+package avro
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Data is validated in the Avro JSON encoding: bytes and fixed values are strings of code points
+// from U+0000 to U+00FF, non-null union values are wrapped into an object with the name of the
+// branch as the only key, e.g. {"string": "value"}.
+
+// ValidationError tells why a value doesn't match the schema. Path is a JSON pointer to the value.
+type ValidationError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (err *ValidationError) Error() string {
+	if err.Path == "" {
+		return err.Message
+	}
+	return err.Path + ": " + err.Message
+}
+
+// ValidateJSON checks that the document is data of the schema in the Avro JSON encoding
+func (schema *Schema) ValidateJSON(document []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return &ValidationError{Message: fmt.Sprintf("invalid JSON: %v", err)}
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return &ValidationError{Message: "invalid JSON: unexpected data after the value"}
+	}
+	return schema.Validate(value)
+}
+
+// Validate checks a value decoded from JSON (numbers can be json.Number or float64)
+func (schema *Schema) Validate(value interface{}) error {
+	validator := &dataValidator{}
+	return validator.validate(schema, value, "")
+}
+
+// validateDefault checks a default value of a field. Defaults of unions are not wrapped
+// and must match the first type of the union.
+func validateDefault(schema *Schema, value interface{}) error {
+	validator := &dataValidator{defaults: true}
+	return validator.validate(schema, value, "")
+}
+
+type dataValidator struct {
+	defaults bool
+}
+
+func (validator *dataValidator) errorf(path string, format string, args ...interface{}) error {
+	return &ValidationError{Path: path, Message: fmt.Sprintf(format, args...)}
+}
+
+func (validator *dataValidator) validate(schema *Schema, value interface{}, path string) error {
+	switch schema.Type {
+	case TypeNull:
+		if value != nil {
+			return validator.errorf(path, "expected null")
+		}
+	case TypeBoolean:
+		if _, isBool := value.(bool); !isBool {
+			return validator.errorf(path, "expected a boolean")
+		}
+	case TypeInt, TypeLong:
+		number, isNumber := asNumber(value)
+		if !isNumber || strings.ContainsAny(number.String(), ".eE") {
+			return validator.errorf(path, "expected an integer")
+		}
+		bitSize := 64
+		if schema.Type == TypeInt {
+			bitSize = 32
+		}
+		if _, err := strconv.ParseInt(number.String(), 10, bitSize); err != nil {
+			return validator.errorf(path, "expected an integer in the range of %s", schema.Type)
+		}
+	case TypeFloat, TypeDouble:
+		if text, isString := value.(string); isString && (text == "NaN" || text == "Infinity" || text == "-Infinity") {
+			return nil
+		}
+		number, isNumber := asNumber(value)
+		if !isNumber {
+			return validator.errorf(path, "expected a number")
+		}
+		if schema.Type == TypeFloat {
+			if parsed, err := number.Float64(); err == nil && math.Abs(parsed) > math.MaxFloat32 {
+				return validator.errorf(path, "number is out of the range of float")
+			}
+		}
+	case TypeString:
+		if _, isString := value.(string); !isString {
+			return validator.errorf(path, "expected a string")
+		}
+	case TypeBytes, TypeFixed:
+		text, isString := value.(string)
+		if !isString {
+			return validator.errorf(path, "expected a string of bytes")
+		}
+		for _, char := range text {
+			if char > 0xFF {
+				return validator.errorf(path, "bytes must be encoded as characters from U+0000 to U+00FF")
+			}
+		}
+		if schema.Type == TypeFixed && utf8.RuneCountInString(text) != schema.Size {
+			return validator.errorf(path, "expected %d bytes of %s, got %d", schema.Size, schema.Name, utf8.RuneCountInString(text))
+		}
+	case TypeEnum:
+		symbol, isString := value.(string)
+		if !isString {
+			return validator.errorf(path, "expected a symbol of %s", schema.Name)
+		}
+		for _, enumSymbol := range schema.Symbols {
+			if symbol == enumSymbol {
+				return nil
+			}
+		}
+		return validator.errorf(path, "%q is not a symbol of %s (symbols: %s)", symbol, schema.Name, strings.Join(schema.Symbols, ", "))
+	case TypeArray:
+		items, isList := value.([]interface{})
+		if !isList {
+			return validator.errorf(path, "expected an array")
+		}
+		for index, item := range items {
+			if err := validator.validate(schema.Items, item, path+"/"+strconv.Itoa(index)); err != nil {
+				return err
+			}
+		}
+	case TypeMap:
+		values, isObject := value.(map[string]interface{})
+		if !isObject {
+			return validator.errorf(path, "expected an object")
+		}
+		for _, key := range sortedKeys(values) {
+			if err := validator.validate(schema.Values, values[key], path+"/"+escapePointer(key)); err != nil {
+				return err
+			}
+		}
+	case TypeRecord:
+		return validator.validateRecord(schema, value, path)
+	case TypeUnion:
+		return validator.validateUnion(schema, value, path)
+	}
+	return nil
+}
+
+func (validator *dataValidator) validateRecord(schema *Schema, value interface{}, path string) error {
+	values, isObject := value.(map[string]interface{})
+	if !isObject {
+		return validator.errorf(path, "expected an object of record %s", schema.Name)
+	}
+	for _, field := range schema.Fields {
+		fieldValue, exists := values[field.Name]
+		if !exists {
+			if field.HasDefault {
+				continue
+			}
+			return validator.errorf(path, "missing required field %q of record %s", field.Name, schema.Name)
+		}
+		if err := validator.validate(field.Type, fieldValue, path+"/"+escapePointer(field.Name)); err != nil {
+			return err
+		}
+	}
+	for _, key := range sortedKeys(values) {
+		if schema.Field(key) == nil {
+			return validator.errorf(path+"/"+escapePointer(key), "record %s has no field %q", schema.Name, key)
+		}
+	}
+	return nil
+}
+
+func (validator *dataValidator) validateUnion(schema *Schema, value interface{}, path string) error {
+	if validator.defaults {
+		return validator.validate(schema.Branches[0], value, path)
+	}
+
+	var branchNames []string
+	for _, branch := range schema.Branches {
+		branchNames = append(branchNames, branch.TypeName())
+	}
+	if value == nil {
+		for _, branch := range schema.Branches {
+			if branch.Type == TypeNull {
+				return nil
+			}
+		}
+		return validator.errorf(path, "null is not allowed, expected one of: %s", strings.Join(branchNames, ", "))
+	}
+
+	wrapped, isObject := value.(map[string]interface{})
+	if !isObject || len(wrapped) != 1 {
+		return validator.errorf(path, "expected a union value wrapped into an object with one of the keys: %s",
+			strings.Join(branchNames, ", "))
+	}
+	for branchName, branchValue := range wrapped {
+		for _, branch := range schema.Branches {
+			if branch.TypeName() == branchName && branch.Type != TypeNull {
+				return validator.validate(branch, branchValue, path+"/"+escapePointer(branchName))
+			}
+		}
+		return validator.errorf(path, "%q is not a type of the union, expected one of: %s", branchName, strings.Join(branchNames, ", "))
+	}
+	return nil
+}
+
+func asNumber(value interface{}) (json.Number, bool) {
+	switch number := value.(type) {
+	case json.Number:
+		return number, true
+	case float64:
+		return json.Number(strconv.FormatFloat(number, 'f', -1, 64)), true
+	}
+	return "", false
+}
+
+func sortedKeys(values map[string]interface{}) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
@@ -62,12 +62,18 @@ func GetDbConnection() *gorm.DB {
 		&ProjectsDBModel{},
 		&SchemaVersionsDBModel{},
 		&SchemasDBModel{},
+		&SchemaReferencesDBModel{},
+		&SchemaFieldsDBModel{},
+		&SchemaLintRulesDBModel{},
 		&MessagesDBModel{},
 		&AppsDBModel{},
 		&ServersDBModel{},
 		&ResourcesDBModel{},
 		&ResourceBindingsDBModel{},
 		&AppResourceMessagesDBModel{},
+		&ProjectImportsDBModel{},
+		&ProjectGitSyncsDBModel{},
+		&GeneratedCodeDBModel{},
 	)
 	if err != nil {
 		panic("DB GORM migration error" + err.Error())
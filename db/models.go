@@ -31,28 +31,45 @@ type ProjectsDBModel struct {
 	Description   string    `gorm:"column:description;type:text;default null"`
 	IsPrivate     bool      `gorm:"column:is_private;type:bool;default:false;not null"`
 	Status        string    `gorm:"column:status;type:varchar(30);not null;default:'active'"`
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
+	// Compatibility mode of schemas created without an explicit one
+	SchemaCompatibility string `gorm:"column:schema_compatibility;type:varchar(30);not null;default:'none'"`
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
 }
 
 func (ProjectsDBModel) TableName() string {
 	return "projects"
 }
 
+// LifecycleDBFields are the lifecycle state of schemas, schema versions and messages.
+// Status tells if an entity exists, the lifecycle state tells if it should be used.
+type LifecycleDBFields struct {
+	State             string     `gorm:"column:lifecycle_state;type:varchar(30);not null;default:'active'"`
+	DeprecationReason string     `gorm:"column:deprecation_reason;type:text;default null"`
+	ReplacedBy        string     `gorm:"column:replaced_by;type:varchar(255);default null"`
+	SunsetAt          *time.Time `gorm:"column:sunset_at;type:date;default null"`
+}
+
 type SchemasDBModel struct {
 	gorm.Model
-	ID            uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primary_key;"`
-	ProjectID     uuid.UUID `gorm:"project_id:uuid;column:project_id;uniqueIndex:idx_unique_schema_name,where:status = 'active'"`
-	CreatedByType string    `gorm:"column:created_by_type;type:varchar(45);not null; default:'user'"`
-	CreatedByID   uuid.UUID `gorm:"type:uuid;column:created_by_id;"`
-	Name          string    `gorm:"column:name;type:varchar(45);not null;uniqueIndex:idx_unique_schema_name,where:status = 'active'"`
-	Description   string    `gorm:"column:description;type:text;default null"`
-	Status        string    `gorm:"column:status;type:varchar(30);not null;default:'active'"`
-	Type          string    `gorm:"column:type;type:varchar(30);not null;default:'jsonschema'"`
-	Schema        string    `gorm:"column:schema;type:text;not null"`
-	Version       int       `gorm:"column:version;type:int;not null;default:1;"`
+	ID            uuid.UUID         `gorm:"type:uuid;default:uuid_generate_v4();primary_key;"`
+	ProjectID     uuid.UUID         `gorm:"project_id:uuid;column:project_id;uniqueIndex:idx_unique_schema_name,where:status = 'active'"`
+	CreatedByType string            `gorm:"column:created_by_type;type:varchar(45);not null; default:'user'"`
+	CreatedByID   uuid.UUID         `gorm:"type:uuid;column:created_by_id;"`
+	Name          string            `gorm:"column:name;type:varchar(45);not null;uniqueIndex:idx_unique_schema_name,where:status = 'active'"`
+	Description   string            `gorm:"column:description;type:text;default null"`
+	Status        string            `gorm:"column:status;type:varchar(30);not null;default:'active'"`
+	Type          string            `gorm:"column:type;type:varchar(30);not null;default:'jsonschema'"`
+	Schema        string            `gorm:"column:schema;type:text;not null"`
+	Version       int               `gorm:"column:version;type:int;not null;default:1;"`
+	Compatibility string            `gorm:"column:compatibility;type:varchar(30);not null;default:'none'"`
+	ImportID      *uuid.UUID        `gorm:"type:uuid;column:import_id;index;default null"`
+	Lifecycle     LifecycleDBFields `gorm:"embedded"`
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
+	// Schemas converted from a version of a schema of another type are linked to it
+	ConvertedFromSchemaID *uuid.UUID `gorm:"type:uuid;column:converted_from_schema_id;index;default null"`
+	ConvertedFromVersion  *int       `gorm:"column:converted_from_version;type:int;default null"`
 }
 
 func (SchemasDBModel) TableName() string {
@@ -77,14 +94,78 @@ type SchemaVersionsDBModel struct {
 	UserID    uuid.UUID `gorm:"type:uuid;column:user_id;"`
 	Version   int       `gorm:"column:version;type:int;not null;default:1;"`
 	Schema    string    `gorm:"column:schema;type:text;not null"`
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	CommitSHA string    `gorm:"column:commit_sha;type:varchar(64);default null"`
+	// RegistryID is the numeric ID of the version in the Confluent compatible schema registry API
+	RegistryID int64             `gorm:"column:registry_id;autoIncrement;not null;uniqueIndex"`
+	Lifecycle  LifecycleDBFields `gorm:"embedded"`
+	// FieldsCatalogedAt is set when fields of the version are stored in schema_fields
+	FieldsCatalogedAt *time.Time `gorm:"column:fields_cataloged_at;default null"`
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
 }
 
 func (SchemaVersionsDBModel) TableName() string {
 	return "schema_versions"
 }
 
+// SchemaReferencesDBModel records that a version of a schema references a version of another schema
+// of the same project, e.g. with {"$ref": "fusioncat://schemas/address/versions/2"}
+type SchemaReferencesDBModel struct {
+	gorm.Model
+	ID                      uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primary_key;"`
+	SchemaID                uuid.UUID `gorm:"type:uuid;column:schema_id;not null;index"`
+	SchemaVersion           int       `gorm:"column:schema_version;type:int;not null"`
+	ReferencedSchemaID      uuid.UUID `gorm:"type:uuid;column:referenced_schema_id;not null;index"`
+	ReferencedSchemaVersion int       `gorm:"column:referenced_schema_version;type:int;not null"`
+	CreatedAt               time.Time
+	UpdatedAt               time.Time
+}
+
+func (SchemaReferencesDBModel) TableName() string {
+	return "schema_references"
+}
+
+// SchemaFieldsDBModel is a field of a schema version in the field catalog. Protobuf schemas have
+// fields of every message type, InDefaultMessageType marks fields of the type used by messages
+// which don't select one (all fields of other schema types).
+type SchemaFieldsDBModel struct {
+	ID                   uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primary_key;"`
+	ProjectID            uuid.UUID `gorm:"type:uuid;column:project_id;not null;index:idx_schema_fields_name"`
+	SchemaID             uuid.UUID `gorm:"type:uuid;column:schema_id;not null;index:idx_schema_fields_version"`
+	SchemaVersion        int       `gorm:"column:schema_version;type:int;not null;index:idx_schema_fields_version"`
+	MessageType          string    `gorm:"column:message_type;type:varchar(255);default null"`
+	InDefaultMessageType bool      `gorm:"column:in_default_message_type;not null;default:true"`
+	Path                 string    `gorm:"column:path;type:text;not null"`
+	Name                 string    `gorm:"column:name;type:varchar(255);not null;index:idx_schema_fields_name,expression:lower(name)"`
+	Type                 string    `gorm:"column:type;type:varchar(255)"`
+	Format               string    `gorm:"column:format;type:varchar(100);default null"`
+	Required             bool      `gorm:"column:required;not null;default:false"`
+	Description          string    `gorm:"column:description;type:text;default null"`
+	// Enum is the JSON array of allowed values
+	Enum      string `gorm:"column:enum;type:text;default null"`
+	CreatedAt time.Time
+}
+
+func (SchemaFieldsDBModel) TableName() string {
+	return "schema_fields"
+}
+
+// SchemaLintRulesDBModel overrides the default configuration of a lint rule in a project
+type SchemaLintRulesDBModel struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primary_key;"`
+	ProjectID uuid.UUID `gorm:"type:uuid;column:project_id;not null;uniqueIndex:idx_unique_schema_lint_rule"`
+	Rule      string    `gorm:"column:rule;type:varchar(100);not null;uniqueIndex:idx_unique_schema_lint_rule"`
+	Enabled   bool      `gorm:"column:enabled;not null;default:true"`
+	Severity  string    `gorm:"column:severity;type:varchar(30);not null"`
+	Option    string    `gorm:"column:rule_option;type:varchar(100);default null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (SchemaLintRulesDBModel) TableName() string {
+	return "schema_lint_rules"
+}
+
 type MessagesDBModel struct {
 	gorm.Model
 	ID            uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primary_key;"`
@@ -94,9 +175,13 @@ type MessagesDBModel struct {
 	Status        string    `gorm:"column:status;type:varchar(30);not null;default:'active'"`
 	SchemaID      uuid.UUID `gorm:"type:uuid;column:schema_id;"`
 	SchemaVersion int       `gorm:"column:schema_version;type:int;not null;default:1;"`
-	CreatedByID   uuid.UUID `gorm:"type:uuid;column:created_by_id;"`
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
+	// Full name of the message type inside protobuf schemas which define several messages
+	SchemaMessageType string            `gorm:"column:schema_message_type;type:varchar(255);default null"`
+	CreatedByID       uuid.UUID         `gorm:"type:uuid;column:created_by_id;"`
+	ImportID          *uuid.UUID        `gorm:"type:uuid;column:import_id;index;default null"`
+	Lifecycle         LifecycleDBFields `gorm:"embedded"`
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
 }
 
 func (MessagesDBModel) TableName() string {
@@ -106,13 +191,14 @@ func (MessagesDBModel) TableName() string {
 // AppResourceMessagesDBModel represents the apps_resources_messages table
 type AppResourceMessagesDBModel struct {
 	gorm.Model
-	ID              uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primary_key;"`
-	AppID           uuid.UUID `gorm:"type:uuid;column:app_id;"`
-	ResourceID      uuid.UUID `gorm:"type:uuid;column:resource_id;"`
-	MessageID       uuid.UUID `gorm:"type:uuid;column:message_id;"`
-	CreatedByUserID uuid.UUID `gorm:"type:uuid;column:created_by_user_id;"`
-	Status          string    `gorm:"column:status;type:varchar(30);not null;default:'active'"`
-	Direction       string    `gorm:"column:direction;type:varchar(30);default null;"`
+	ID              uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primary_key;"`
+	AppID           uuid.UUID  `gorm:"type:uuid;column:app_id;"`
+	ResourceID      uuid.UUID  `gorm:"type:uuid;column:resource_id;"`
+	MessageID       uuid.UUID  `gorm:"type:uuid;column:message_id;"`
+	CreatedByUserID uuid.UUID  `gorm:"type:uuid;column:created_by_user_id;"`
+	Status          string     `gorm:"column:status;type:varchar(30);not null;default:'active'"`
+	Direction       string     `gorm:"column:direction;type:varchar(30);default null;"`
+	ImportID        *uuid.UUID `gorm:"type:uuid;column:import_id;index;default null"`
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
 }
@@ -123,12 +209,13 @@ func (AppResourceMessagesDBModel) TableName() string {
 
 type AppsDBModel struct {
 	gorm.Model
-	ID              uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primary_key;"`
-	CreatedByUserID uuid.UUID `gorm:"type:uuid;column:created_by_user_id;"`
-	ProjectID       uuid.UUID `gorm:"type:uuid;column:project_id;uniqueIndex:idx_unique_app_name,where:status = 'active'"`
-	Name            string    `gorm:"column:name;type:varchar(45);not null;uniqueIndex:idx_unique_app_name,where:status = 'active'"`
-	Description     string    `gorm:"column:description;type:text;default null"`
-	Status          string    `gorm:"column:status;type:varchar(30);not null;default:'active'"`
+	ID              uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primary_key;"`
+	CreatedByUserID uuid.UUID  `gorm:"type:uuid;column:created_by_user_id;"`
+	ProjectID       uuid.UUID  `gorm:"type:uuid;column:project_id;uniqueIndex:idx_unique_app_name,where:status = 'active'"`
+	Name            string     `gorm:"column:name;type:varchar(45);not null;uniqueIndex:idx_unique_app_name,where:status = 'active'"`
+	Description     string     `gorm:"column:description;type:text;default null"`
+	Status          string     `gorm:"column:status;type:varchar(30);not null;default:'active'"`
+	ImportID        *uuid.UUID `gorm:"type:uuid;column:import_id;index;default null"`
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
 }
@@ -150,13 +237,14 @@ func (a AppsDBModel) CanCreate(projectID uuid.UUID, name string) (bool, error) {
 
 type ServersDBModel struct {
 	gorm.Model
-	ID              uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primary_key;"`
-	Name            string    `gorm:"column:name;type:varchar(45);not null;uniqueIndex:idx_server_name_project,where:status='active'"`
-	Protocol        string    `gorm:"column:protocol;type:varchar(50);not null"`
-	ProjectID       uuid.UUID `gorm:"type:uuid;column:project_id;uniqueIndex:idx_server_name_project,where:status='active'"`
-	CreatedByUserID uuid.UUID `gorm:"type:uuid;column:created_by_user_id;"`
-	Description     string    `gorm:"column:description;type:text;default null"`
-	Status          string    `gorm:"column:status;type:varchar(30);not null;default:'active'"`
+	ID              uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primary_key;"`
+	Name            string     `gorm:"column:name;type:varchar(45);not null;uniqueIndex:idx_server_name_project,where:status='active'"`
+	Protocol        string     `gorm:"column:protocol;type:varchar(50);not null"`
+	ProjectID       uuid.UUID  `gorm:"type:uuid;column:project_id;uniqueIndex:idx_server_name_project,where:status='active'"`
+	CreatedByUserID uuid.UUID  `gorm:"type:uuid;column:created_by_user_id;"`
+	Description     string     `gorm:"column:description;type:text;default null"`
+	Status          string     `gorm:"column:status;type:varchar(30);not null;default:'active'"`
+	ImportID        *uuid.UUID `gorm:"type:uuid;column:import_id;index;default null"`
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
 }
@@ -167,15 +255,16 @@ func (ServersDBModel) TableName() string {
 
 type ResourcesDBModel struct {
 	gorm.Model
-	ID              uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primary_key;"`
-	ServerID        uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_unique_resource,where:status='active'"`
-	Mode            string    `gorm:"column:mode;type:varchar(20);default:'readwrite';uniqueIndex:idx_unique_resource,where:status='active'"`
-	ResourceType    string    `gorm:"column:resource_type;type:varchar(30);not null;uniqueIndex:idx_unique_resource,where:status='active'"`
-	Description     string    `gorm:"column:description;type:text;default null"`
-	Status          string    `gorm:"column:status;type:varchar(30);not null;default:'active'"`
-	Name            string    `gorm:"column:name;type:varchar(100);not null;uniqueIndex:idx_unique_resource,where:status='active'"`
-	ProjectID       uuid.UUID `gorm:"type:uuid;column:project_id;"`
-	CreatedByUserID uuid.UUID `gorm:"type:uuid;column:created_by_user_id;"`
+	ID              uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primary_key;"`
+	ServerID        uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_unique_resource,where:status='active'"`
+	Mode            string     `gorm:"column:mode;type:varchar(20);default:'readwrite';uniqueIndex:idx_unique_resource,where:status='active'"`
+	ResourceType    string     `gorm:"column:resource_type;type:varchar(30);not null;uniqueIndex:idx_unique_resource,where:status='active'"`
+	Description     string     `gorm:"column:description;type:text;default null"`
+	Status          string     `gorm:"column:status;type:varchar(30);not null;default:'active'"`
+	Name            string     `gorm:"column:name;type:varchar(100);not null;uniqueIndex:idx_unique_resource,where:status='active'"`
+	ProjectID       uuid.UUID  `gorm:"type:uuid;column:project_id;"`
+	CreatedByUserID uuid.UUID  `gorm:"type:uuid;column:created_by_user_id;"`
+	ImportID        *uuid.UUID `gorm:"type:uuid;column:import_id;index;default null"`
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
 }
@@ -186,9 +275,10 @@ func (ResourcesDBModel) TableName() string {
 
 type ResourceBindingsDBModel struct {
 	gorm.Model
-	ID               uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primary_key;"`
-	SourceResourceID uuid.UUID `gorm:"column:source_resource_id;type:uuid;not null;uniqueIndex:idx_unique_binding"`
-	TargetResourceID uuid.UUID `gorm:"column:target_resource_id;type:uuid;not null;uniqueIndex:idx_unique_binding"`
+	ID               uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primary_key;"`
+	SourceResourceID uuid.UUID  `gorm:"column:source_resource_id;type:uuid;not null;uniqueIndex:idx_unique_binding"`
+	TargetResourceID uuid.UUID  `gorm:"column:target_resource_id;type:uuid;not null;uniqueIndex:idx_unique_binding"`
+	ImportID         *uuid.UUID `gorm:"type:uuid;column:import_id;index;default null"`
 	CreatedAt        time.Time
 	UpdatedAt        time.Time
 }
@@ -196,3 +286,76 @@ type ResourceBindingsDBModel struct {
 func (ResourceBindingsDBModel) TableName() string {
 	return "resource_bindings"
 }
+
+// ProjectImportsDBModel is a single run of the architecture importer. Counters are updated
+// while the import is running, so the record also serves as a progress report.
+type ProjectImportsDBModel struct {
+	gorm.Model
+	ID                    uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primary_key;"`
+	ProjectID             uuid.UUID  `gorm:"type:uuid;column:project_id;not null;index:idx_project_imports_project"`
+	CreatedByUserID       uuid.UUID  `gorm:"type:uuid;column:created_by_user_id;"`
+	Status                string     `gorm:"column:status;type:varchar(30);not null;default:'queued'"`
+	Phase                 string     `gorm:"column:phase;type:varchar(30);not null;default:'queued'"`
+	Mode                  string     `gorm:"column:mode;type:varchar(30);not null;default:'import'"`
+	CommitSHA             string     `gorm:"column:commit_sha;type:varchar(64);default null"`
+	RootFile              string     `gorm:"column:root_file;type:varchar(255);default null"`
+	RootFormat            string     `gorm:"column:root_format;type:varchar(30);default null"`
+	Source                string     `gorm:"column:source;type:text;not null"`
+	ServersCreated        int        `gorm:"column:servers_created;type:int;not null;default:0"`
+	ResourcesCreated      int        `gorm:"column:resources_created;type:int;not null;default:0"`
+	BindingsCreated       int        `gorm:"column:bindings_created;type:int;not null;default:0"`
+	SchemasCreated        int        `gorm:"column:schemas_created;type:int;not null;default:0"`
+	MessagesCreated       int        `gorm:"column:messages_created;type:int;not null;default:0"`
+	AppsCreated           int        `gorm:"column:apps_created;type:int;not null;default:0"`
+	ConnectionsCreated    int        `gorm:"column:connections_created;type:int;not null;default:0"`
+	SchemaVersionsCreated int        `gorm:"column:schema_versions_created;type:int;not null;default:0"`
+	Errors                string     `gorm:"column:errors;type:text;default null"`
+	Warnings              string     `gorm:"column:warnings;type:text;default null"`
+	StartedAt             *time.Time `gorm:"column:started_at;default null"`
+	FinishedAt            *time.Time `gorm:"column:finished_at;default null"`
+	RevertedAt            *time.Time `gorm:"column:reverted_at;default null"`
+	RevertedByUserID      *uuid.UUID `gorm:"type:uuid;column:reverted_by_user_id;default null"`
+	CreatedAt             time.Time
+	UpdatedAt             time.Time
+}
+
+func (ProjectImportsDBModel) TableName() string {
+	return "project_imports"
+}
+
+type ProjectGitSyncsDBModel struct {
+	gorm.Model
+	ID                  uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primary_key;"`
+	ProjectID           uuid.UUID  `gorm:"type:uuid;column:project_id;not null;uniqueIndex:idx_unique_project_git_sync"`
+	RepositoryPath      string     `gorm:"column:repository_path;type:text;not null"`
+	Branch              string     `gorm:"column:branch;type:varchar(255);not null"`
+	Subdirectory        string     `gorm:"column:subdirectory;type:text;default null"`
+	RootFile            string     `gorm:"column:root_file;type:varchar(255);default null"`
+	PollIntervalSeconds int        `gorm:"column:poll_interval_seconds;type:int;not null;default:0"`
+	ConfiguredByUserID  uuid.UUID  `gorm:"type:uuid;column:configured_by_user_id;"`
+	LastSyncedCommitSHA string     `gorm:"column:last_synced_commit_sha;type:varchar(64);default null"`
+	LastSyncedAt        *time.Time `gorm:"column:last_synced_at;default null"`
+	LastPolledAt        *time.Time `gorm:"column:last_polled_at;default null"`
+	LastPolledCommitSHA string     `gorm:"column:last_polled_commit_sha;type:varchar(64);default null"`
+	LastImportID        *uuid.UUID `gorm:"type:uuid;column:last_import_id;default null"`
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+func (ProjectGitSyncsDBModel) TableName() string {
+	return "project_git_syncs"
+}
+
+// GeneratedCodeDBModel caches code generated for schemas. Entries are addressed by a hash of everything
+// the generated code depends on, so they never go stale and are shared by all projects.
+type GeneratedCodeDBModel struct {
+	SchemaHash       string `gorm:"column:schema_hash;type:varchar(64);primaryKey"`
+	Language         string `gorm:"column:language;type:varchar(30);primaryKey"`
+	GeneratorVersion string `gorm:"column:generator_version;type:varchar(100);primaryKey"`
+	Code             string `gorm:"column:code;type:text;not null"`
+	CreatedAt        time.Time
+}
+
+func (GeneratedCodeDBModel) TableName() string {
+	return "generated_code"
+}
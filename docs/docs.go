@@ -88,6 +88,15 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "504": {
+                        "description": "Code generation of a schema timed out",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             }
@@ -172,14 +181,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/protected/me": {
-            "get": {
+        "/v1/protected/fusionlang/formatter": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Read personal information of  user who owns the authentication token",
+                "description": "Print a Fusionlang source in the canonical layout. Comments are preserved.\nSources with syntax errors are not formatted, the errors are returned instead.",
                 "consumes": [
                     "application/json"
                 ],
@@ -187,44 +196,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Authentication related"
+                    "Fusionlang"
                 ],
-                "summary": "Read personal information of  user who owns the authentication token",
-                "responses": {
-                    "200": {
-                        "description": "User information",
+                "summary": "Format Fusionlang source",
+                "parameters": [
+                    {
+                        "description": "Fusionlang source",
+                        "name": "source",
+                        "in": "body",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/logic.UserDBSerializerStruct"
+                            "$ref": "#/definitions/input_contracts.FusionlangSourceInputContract"
                         }
-                    },
-                    "401": {
-                        "description": "Access denied: missing or invalid Authorization header"
-                    }
-                }
-            }
-        },
-        "/v1/protected/projects": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
                     }
                 ],
-                "description": "Get information about projects I am a member of",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Projects"
-                ],
-                "summary": "Get information about projects I am a member of",
                 "responses": {
                     "200": {
-                        "description": "Response with projects information",
+                        "description": "Formatted source",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/logic.ProjectDBSerializerStruct"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
@@ -236,39 +228,57 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "409": {
+                        "description": "Syntax errors",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/fusionlang.Error"
+                                }
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "JSON payload validation errors",
+                        "schema": {
+                            "$ref": "#/definitions/api.DataValidationErrorAPIResponse"
+                        }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/v1/protected/imports/{importID}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new project",
+                "description": "Get status, current phase, counts of created entities, errors and the source document of an import",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Projects"
+                    "Imports"
                 ],
-                "summary": "Create a new project",
+                "summary": "Get status of an import",
                 "parameters": [
                     {
-                        "description": "Project create request payload",
-                        "name": "project",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/input_contracts.CreateModifyProjectApiInputContract"
-                        }
+                        "type": "string",
+                        "description": "Import ID",
+                        "name": "importID",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "New project has been created",
+                        "description": "Import information",
                         "schema": {
-                            "$ref": "#/definitions/logic.ProjectDBSerializerStruct"
+                            "$ref": "#/definitions/logic.ProjectImportJobDBSerializerStruct"
                         }
                     },
                     "401": {
@@ -280,53 +290,47 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "409": {
-                        "description": "Project with this name already exists",
+                    "404": {
+                        "description": "Import not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
-                    },
-                    "422": {
-                        "description": "JSON payload validation errors",
-                        "schema": {
-                            "$ref": "#/definitions/api.DataValidationErrorAPIResponse"
-                        }
                     }
                 }
             }
         },
-        "/v1/protected/projects/{id}": {
-            "get": {
+        "/v1/protected/imports/{importID}/cancel": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get information about a single project",
+                "description": "Cancel a queued or running import. The import stops before the next entity is created,\nentities created before cancellation are removed.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Projects"
+                    "Imports"
                 ],
-                "summary": "Get information about a single project",
+                "summary": "Cancel an import",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Project ID",
-                        "name": "id",
+                        "description": "Import ID",
+                        "name": "importID",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Response with project information",
+                        "description": "Cancellation is requested",
                         "schema": {
-                            "$ref": "#/definitions/logic.ProjectDBSerializerStruct"
+                            "$ref": "#/definitions/logic.ProjectImportJobDBSerializerStruct"
                         }
                     },
                     "401": {
@@ -339,7 +343,16 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Project not found",
+                        "description": "Import not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Import is already finished",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -350,38 +363,35 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/protected/projects/{id}/apps": {
+        "/v1/protected/imports/{importID}/entities": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all applications in a project",
+                "description": "List servers, resources, bindings, schemas, messages, apps and app connections created by an import",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Apps"
+                    "Imports"
                 ],
-                "summary": "Get all applications in a project",
+                "summary": "Get entities created by an import",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Project ID",
-                        "name": "id",
+                        "description": "Import ID",
+                        "name": "importID",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of apps",
+                        "description": "Entities created by the import",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/logic.AppDBSerializerStruct"
-                            }
+                            "$ref": "#/definitions/logic.ImportedEntitiesSerializerStruct"
                         }
                     },
                     "401": {
@@ -394,7 +404,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Project not found",
+                        "description": "Import not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -403,44 +413,37 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/v1/protected/imports/{importID}/revert": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new application in project",
+                "description": "Remove all entities created by a finished import. The import is not reverted if entities\ncreated outside of it depend on the imported ones, such entities are listed in the response.\nGit synchronizations can't be reverted.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Apps"
+                    "Imports"
                 ],
-                "summary": "Create a new application in project",
+                "summary": "Revert an import",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Project ID",
-                        "name": "id",
+                        "description": "Import ID",
+                        "name": "importID",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "App create request payload",
-                        "name": "app",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/input_contracts.CreateAppApiInputContract"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "App created",
+                        "description": "Import is reverted",
                         "schema": {
-                            "$ref": "#/definitions/logic.AppDBSerializerStruct"
+                            "$ref": "#/definitions/logic.ProjectImportJobDBSerializerStruct"
                         }
                     },
                     "401": {
@@ -453,7 +456,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Project not found",
+                        "description": "Import not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -462,31 +465,23 @@ const docTemplate = `{
                         }
                     },
                     "409": {
-                        "description": "App with this name already exists in this project",
+                        "description": "Import can't be reverted, contains the blocking dependencies",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "422": {
-                        "description": "JSON payload validation errors",
-                        "schema": {
-                            "$ref": "#/definitions/api.DataValidationErrorAPIResponse"
+                            "additionalProperties": true
                         }
                     }
                 }
             }
         },
-        "/v1/protected/projects/{id}/imports": {
-            "post": {
+        "/v1/protected/me": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Import project architecture including servers, resources, schemas, messages, and apps from YAML",
+                "description": "Read personal information of  user who owns the authentication token",
                 "consumes": [
                     "application/json"
                 ],
@@ -494,35 +489,63 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Projects"
+                    "Authentication related"
                 ],
-                "summary": "Import a project architecture",
+                "summary": "Read personal information of  user who owns the authentication token",
+                "responses": {
+                    "200": {
+                        "description": "User information",
+                        "schema": {
+                            "$ref": "#/definitions/logic.UserDBSerializerStruct"
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header"
+                    }
+                }
+            }
+        },
+        "/v1/protected/messages/{messageID}/examples": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Generate example payloads valid against the schema version and the message type of the message.\nThe same seed produces the same examples, requests without a seed get a random one,\nwhich is returned with the examples.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Messages"
+                ],
+                "summary": "Generate example payloads of message",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Project ID",
-                        "name": "id",
+                        "description": "Message ID",
+                        "name": "messageID",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "YAML content to import",
-                        "name": "import",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/input_contracts.ImportFileInputContract"
-                        }
+                        "type": "integer",
+                        "description": "Number of examples, 1 by default, at most 100",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Seed of the generator",
+                        "name": "seed",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Import successful",
+                        "description": "Generated examples",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/logic.SchemaExamplesSerializerStruct"
                         }
                     },
                     "401": {
@@ -535,7 +558,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Project not found",
+                        "description": "Message or its schema not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -543,15 +566,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "409": {
-                        "description": "Import validation errors",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
                     "422": {
-                        "description": "JSON payload validation errors",
+                        "description": "Invalid count or seed, or no valid example could be generated",
                         "schema": {
                             "$ref": "#/definitions/api.DataValidationErrorAPIResponse"
                         }
@@ -559,14 +575,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/protected/projects/{id}/imports/validator": {
-            "post": {
+        "/v1/protected/messages/{messageID}/lifecycle": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Validate YAML file structure for project import",
+                "description": "Change the lifecycle state of the message, following the same rules as schemas.\nMessages are replaced by other messages of the project, referenced by name.\nImports warn about apps wired to deprecated messages and reject apps wired to retired messages,\ngenerated Go code of deprecated and retired messages has \"// Deprecated:\" comments.",
                 "consumes": [
                     "application/json"
                 ],
@@ -574,35 +590,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Projects"
+                    "Messages"
                 ],
-                "summary": "Validate architecture file",
+                "summary": "Change lifecycle state of message",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Project ID",
-                        "name": "id",
+                        "description": "Message ID",
+                        "name": "messageID",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "YAML content to validate",
-                        "name": "import",
+                        "description": "Lifecycle state",
+                        "name": "lifecycle",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/input_contracts.ImportFileInputContract"
+                            "$ref": "#/definitions/input_contracts.LifecycleApiInputContract"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "File is valid",
+                        "description": "Modified message",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/logic.MessageDBSerializerStruct"
                         }
                     },
                     "401": {
@@ -615,7 +628,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Project not found",
+                        "description": "Message not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -623,15 +636,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "409": {
-                        "description": "Validation errors",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
                     "422": {
-                        "description": "JSON payload validation errors",
+                        "description": "Invalid state change or replacement",
                         "schema": {
                             "$ref": "#/definitions/api.DataValidationErrorAPIResponse"
                         }
@@ -639,38 +645,47 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/protected/projects/{id}/messages": {
-            "get": {
+        "/v1/protected/messages/{messageID}/validate": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get list of messages in a project",
+                "description": "Validate one (\"payload\") or many (\"payloads\") JSON payloads against the schema version\nand the message type of the message. Every payload gets its own result with the failures.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Messages"
                 ],
-                "summary": "Get messages in project",
+                "summary": "Validate payloads of message",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Project ID",
-                        "name": "id",
+                        "description": "Message ID",
+                        "name": "messageID",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Payloads to validate",
+                        "name": "payloads",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/input_contracts.ValidatePayloadsApiInputContract"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of messages in project",
+                        "description": "Validation results",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/logic.MessageDBSerializerStruct"
-                            }
+                            "$ref": "#/definitions/logic.PayloadsValidationSerializerStruct"
                         }
                     },
                     "401": {
@@ -683,7 +698,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Project not found",
+                        "description": "Message or its schema not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -691,8 +706,42 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "422": {
+                        "description": "No payloads or the schema of the message can't be compiled",
+                        "schema": {
+                            "$ref": "#/definitions/api.DataValidationErrorAPIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/projects": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get information about projects I am a member of",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Projects"
+                ],
+                "summary": "Get information about projects I am a member of",
+                "responses": {
+                    "200": {
+                        "description": "Response with projects information",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/logic.ProjectDBSerializerStruct"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -708,44 +757,34 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new message in a project",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Create a new project",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Messages"
+                    "Projects"
                 ],
-                "summary": "Create message",
+                "summary": "Create a new project",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Project ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Message creation payload",
-                        "name": "message",
+                        "description": "Project create request payload",
+                        "name": "project",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/input_contracts.CreateMessageApiInputContract"
+                            "$ref": "#/definitions/input_contracts.CreateModifyProjectApiInputContract"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Created message",
+                        "description": "New project has been created",
                         "schema": {
-                            "$ref": "#/definitions/logic.MessageDBSerializerStruct"
+                            "$ref": "#/definitions/logic.ProjectDBSerializerStruct"
                         }
                     },
-                    "400": {
-                        "description": "Schema does not belong to this project or schema version does not exist",
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -753,8 +792,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "401": {
-                        "description": "Access denied: missing or invalid Authorization header",
+                    "409": {
+                        "description": "Project with this name already exists",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -762,17 +801,48 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Project or schema not found",
+                    "422": {
+                        "description": "JSON payload validation errors",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/api.DataValidationErrorAPIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/projects/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get information about a single project",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Projects"
+                ],
+                "summary": "Get information about a single project",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Response with project information",
+                        "schema": {
+                            "$ref": "#/definitions/logic.ProjectDBSerializerStruct"
                         }
                     },
-                    "409": {
-                        "description": "Message with this name already exists in this project",
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -780,14 +850,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "422": {
-                        "description": "JSON payload validation errors",
-                        "schema": {
-                            "$ref": "#/definitions/api.DataValidationErrorAPIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
+                    "404": {
+                        "description": "Project not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -798,21 +862,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/protected/projects/{id}/schemas": {
+        "/v1/protected/projects/{id}/apps": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all schemas in project",
+                "description": "Get all applications in a project",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Schemas"
+                    "Apps"
                 ],
-                "summary": "Get all schemas in project",
+                "summary": "Get all applications in a project",
                 "parameters": [
                     {
                         "type": "string",
@@ -824,11 +888,11 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of schemas in project",
+                        "description": "List of apps",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/logic.SchemaDBSerializerStruct"
+                                "$ref": "#/definitions/logic.AppDBSerializerStruct"
                             }
                         }
                     },
@@ -858,17 +922,14 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create new schema in project",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Create a new application in project",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Schemas"
+                    "Apps"
                 ],
-                "summary": "Create new schema in project",
+                "summary": "Create a new application in project",
                 "parameters": [
                     {
                         "type": "string",
@@ -878,27 +939,48 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "New schema request payload",
-                        "name": "project",
+                        "description": "App create request payload",
+                        "name": "app",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/input_contracts.CreateSchemaApiInputContract"
+                            "$ref": "#/definitions/input_contracts.CreateAppApiInputContract"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Nodified schema",
+                        "description": "App created",
                         "schema": {
-                            "$ref": "#/definitions/logic.SchemaDBSerializerStruct"
+                            "$ref": "#/definitions/logic.AppDBSerializerStruct"
                         }
                     },
                     "401": {
-                        "description": "Access denied: missing or invalid Authorization header"
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     },
                     "404": {
-                        "description": "Project not found"
+                        "description": "Project not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "App with this name already exists in this project",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     },
                     "422": {
                         "description": "JSON payload validation errors",
@@ -909,21 +991,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/protected/projects/{id}/servers": {
+        "/v1/protected/projects/{id}/fields": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all servers in a project",
+                "description": "Find fields of a name (case-insensitive) in all versions of active schemas of the project,\nwith their types and the messages which use the versions.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Servers"
+                    "Schemas"
                 ],
-                "summary": "Get all servers in a project",
+                "summary": "Search fields in project",
                 "parameters": [
                     {
                         "type": "string",
@@ -931,16 +1013,20 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the field, e.g. customer_id",
+                        "name": "name",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of servers",
+                        "description": "Found fields",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/logic.ServerDBSerializerStruct"
-                            }
+                            "$ref": "#/definitions/logic.SchemaFieldSearchSerializerStruct"
                         }
                     },
                     "401": {
@@ -960,23 +1046,31 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "422": {
+                        "description": "Missing name",
+                        "schema": {
+                            "$ref": "#/definitions/api.DataValidationErrorAPIResponse"
+                        }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/v1/protected/projects/{id}/fields/consistency": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new server in project",
+                "description": "Group fields of the same name across current versions of active schemas and versions messages are\npinned to, and report groups whose fields conflict by type, format, enum or unit. Types and formats\nof Avro and protobuf schemas are mapped to JSON types and formats, units are read from descriptions\nand Avro logical types. Every field links the schema version which defines it and the messages using it.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Servers"
+                    "Schemas"
                 ],
-                "summary": "Create a new server in project",
+                "summary": "Get field consistency report of project",
                 "parameters": [
                     {
                         "type": "string",
@@ -984,22 +1078,13 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Server create request payload",
-                        "name": "server",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/input_contracts.CreateServerApiInputContract"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Server created",
+                        "description": "Inconsistent fields",
                         "schema": {
-                            "$ref": "#/definitions/logic.ServerDBSerializerStruct"
+                            "$ref": "#/definitions/logic.ProjectConsistencySerializerStruct"
                         }
                     },
                     "401": {
@@ -1019,54 +1104,39 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
-                    },
-                    "409": {
-                        "description": "Server with this name already exists in this project",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "422": {
-                        "description": "JSON payload validation errors",
-                        "schema": {
-                            "$ref": "#/definitions/api.DataValidationErrorAPIResponse"
-                        }
                     }
                 }
             }
         },
-        "/v1/protected/schemas/{schemaID}": {
+        "/v1/protected/projects/{id}/git-sync": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get schema",
+                "description": "Get the repository, branch and subdirectory the project follows and the last synchronized commit",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Schemas"
+                    "Git synchronization"
                 ],
-                "summary": "Get schema",
+                "summary": "Get git synchronization settings of a project",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Schema ID",
-                        "name": "schemaID",
+                        "description": "Project ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Schema information",
+                        "description": "Git synchronization settings",
                         "schema": {
-                            "$ref": "#/definitions/logic.SchemaDBSerializerStruct"
+                            "$ref": "#/definitions/logic.ProjectGitSyncDBSerializerStruct"
                         }
                     },
                     "401": {
@@ -1079,7 +1149,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Schema not found",
+                        "description": "Project not found or git synchronization is not configured",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1095,7 +1165,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Modify schema by creating a new version",
+                "description": "Point the project at a local git repository (bare or with a working tree). Architecture files\nare read from the subdirectory of the branch, files are always read from commits.\nA poll interval enables automatic synchronization when the branch moves.\nRepositories must be located in GIT_SYNC_REPOSITORIES_ROOT, git synchronization is disabled if it isn't set.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1103,32 +1173,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Schemas"
+                    "Git synchronization"
                 ],
-                "summary": "Modify schema",
+                "summary": "Configure git synchronization of a project",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Schema ID",
-                        "name": "schemaID",
+                        "description": "Project ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Schema modification payload",
-                        "name": "schema",
+                        "description": "Git synchronization settings",
+                        "name": "settings",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/input_contracts.ModifySchemaApiInputContract"
+                            "$ref": "#/definitions/input_contracts.ConfigureGitSyncInputContract"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Modified schema",
+                        "description": "Git synchronization is configured",
                         "schema": {
-                            "$ref": "#/definitions/logic.SchemaDBSerializerStruct"
+                            "$ref": "#/definitions/logic.ProjectGitSyncDBSerializerStruct"
                         }
                     },
                     "401": {
@@ -1141,7 +1211,16 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Schema not found",
+                        "description": "Project not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Repository or branch can't be used",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1156,54 +1235,33 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/protected/schemas/{schemaID}/code/{language}": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Generate code from schema in specified programming language. The generated code will be returned as a plain text file with appropriate content type headers.",
+                "description": "Remove git synchronization settings. Entities created by previous synchronizations are kept.",
                 "produces": [
-                    "text/plain"
+                    "application/json"
                 ],
                 "tags": [
-                    "Schemas"
+                    "Git synchronization"
                 ],
-                "summary": "Generate code from schema in specified language",
+                "summary": "Stop git synchronization of a project",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Schema ID",
-                        "name": "schemaID",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "enum": [
-                            "typescript",
-                            "java",
-                            "go",
-                            "python"
-                        ],
-                        "type": "string",
-                        "description": "Programming language",
-                        "name": "language",
+                        "description": "Project ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Generated code as plain text file",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid language or schema type",
+                        "description": "Git synchronization is removed",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1221,7 +1279,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Schema not found",
+                        "description": "Project not found or git synchronization is not configured",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1232,38 +1290,46 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/protected/schemas/{schemaID}/versions": {
-            "get": {
+        "/v1/protected/projects/{id}/git-sync/syncs": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get list of schema versions",
+                "description": "Read architecture files at a commit (the head of the configured branch by default) and apply them\nto the project. Existing entities are kept, missing ones are created and schemas whose content\nchanged get a new version. The commit SHA is recorded on the import and on new schema versions.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Schemas"
+                    "Git synchronization"
                 ],
-                "summary": "Get list of schema versions",
+                "summary": "Synchronize a project with its git repository",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Schema ID",
-                        "name": "schemaID",
+                        "description": "Project ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Revision to synchronize",
+                        "name": "sync",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/input_contracts.GitSyncInputContract"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of schema versions",
+                        "description": "Project is synchronized",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/logic.SchemaEditDBSerializerStruct"
-                            }
+                            "$ref": "#/definitions/logic.ProjectImportJobDBSerializerStruct"
                         }
                     },
                     "401": {
@@ -1276,95 +1342,48 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Schema not found",
+                        "description": "Project not found or git synchronization is not configured",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
-                    }
-                }
-            }
-        },
-        "/v1/protected/schemas/{schemaID}/versions/{versionID}": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Get a single schema version",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Schemas"
-                ],
-                "summary": "Get a single schema version",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Schema ID",
-                        "name": "schemaID",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Version ID (integer number)",
-                        "name": "versionID",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Schema version information",
-                        "schema": {
-                            "$ref": "#/definitions/logic.SchemaEditDBSerializerStruct"
-                        }
                     },
-                    "401": {
-                        "description": "Access denied: missing or invalid Authorization header",
+                    "409": {
+                        "description": "Synchronization failed, contains the errors",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/logic.ProjectImportJobDBSerializerStruct"
                         }
                     },
-                    "404": {
-                        "description": "Schema version not found or can't be accessed by user",
+                    "422": {
+                        "description": "JSON payload validation errors",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/api.DataValidationErrorAPIResponse"
                         }
                     }
                 }
             }
         },
-        "/v1/protected/servers/{id}/binds": {
+        "/v1/protected/projects/{id}/imports": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all resource bindings for resources in a server",
+                "description": "Get all imports into a project, newest first. Source documents are not included.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Server Resources"
+                    "Imports"
                 ],
-                "summary": "Get all resource bindings in a server",
+                "summary": "Get history of imports into a project",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Server ID",
+                        "description": "Project ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1372,11 +1391,11 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of resource bindings",
+                        "description": "List of imports",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/logic.ResourceBindingDBSerializerStruct"
+                                "$ref": "#/definitions/logic.ProjectImportJobDBSerializerStruct"
                             }
                         }
                     },
@@ -1390,7 +1409,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Server not found",
+                        "description": "Project not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1406,46 +1425,59 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a resource binding between two resources in the same server",
+                "description": "Import project architecture including servers, resources, schemas, messages, and apps from YAML or JSON.\nThe architecture can be split into several files: send them in the \"files\" field of the JSON payload\nor as a multipart upload with several \"files\" parts or a single \"archive\" part (zip, tar, tar.gz).\nThe import runs within the request and is recorded in the import history of the project,\nuse the import jobs endpoint for large imports.",
+                "consumes": [
+                    "application/json",
+                    "multipart/form-data"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Server Resources"
+                    "Projects"
                 ],
-                "summary": "Create a resource binding in server",
+                "summary": "Import a project architecture",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Server ID",
+                        "description": "Project ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Resource binding request payload",
-                        "name": "binding",
+                        "description": "Architecture content to import",
+                        "name": "import",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/input_contracts.CreateResourceBindApiInputContract"
+                            "$ref": "#/definitions/input_contracts.ImportFileInputContract"
                         }
+                    },
+                    {
+                        "type": "file",
+                        "description": "Archive with architecture files (multipart upload)",
+                        "name": "archive",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Architecture files (multipart upload)",
+                        "name": "files",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the root file in a multipart upload",
+                        "name": "root",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Resource binding created",
-                        "schema": {
-                            "$ref": "#/definitions/logic.ResourceBindingDBSerializerStruct"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid input or resources not in same server",
+                        "description": "Import successful, contains the import ID and warnings",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     },
                     "401": {
@@ -1458,7 +1490,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Server or resource not found",
+                        "description": "Project not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1467,11 +1499,14 @@ const docTemplate = `{
                         }
                     },
                     "409": {
-                        "description": "Resource binding already exists",
+                        "description": "Import validation errors and warnings",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
-                                "type": "string"
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/logic.ImportValidationIssue"
+                                }
                             }
                         }
                     },
@@ -1484,38 +1519,65 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/protected/servers/{id}/resources": {
-            "get": {
+        "/v1/protected/projects/{id}/imports/jobs": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all resources in a server",
+                "description": "Submit project architecture for import in the background. Accepts the same payloads as the\nimport endpoint and responds immediately with the job, use the job ID to follow the progress.",
+                "consumes": [
+                    "application/json",
+                    "multipart/form-data"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Server Resources"
+                    "Imports"
                 ],
-                "summary": "Get all resources in a server",
+                "summary": "Submit an import job",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Server ID",
+                        "description": "Project ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Architecture content to import",
+                        "name": "import",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/input_contracts.ImportFileInputContract"
+                        }
+                    },
+                    {
+                        "type": "file",
+                        "description": "Archive with architecture files (multipart upload)",
+                        "name": "archive",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Architecture files (multipart upload)",
+                        "name": "files",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the root file in a multipart upload",
+                        "name": "root",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "List of resources",
+                    "202": {
+                        "description": "Import job is submitted",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/logic.ResourceDBSerializerStruct"
-                            }
+                            "$ref": "#/definitions/logic.ProjectImportJobDBSerializerStruct"
                         }
                     },
                     "401": {
@@ -1528,53 +1590,92 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Server not found",
+                        "description": "Project not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Import job can't be created",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
+                    },
+                    "422": {
+                        "description": "JSON payload validation errors",
+                        "schema": {
+                            "$ref": "#/definitions/api.DataValidationErrorAPIResponse"
+                        }
                     }
                 }
-            },
+            }
+        },
+        "/v1/protected/projects/{id}/imports/validator": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new resource in server",
+                "description": "Validate YAML file structure for project import. Every error and warning contains\na machine-readable code, YAML path, line and column of the offending node.",
+                "consumes": [
+                    "application/json",
+                    "multipart/form-data"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Server Resources"
+                    "Projects"
                 ],
-                "summary": "Create a new resource in server",
+                "summary": "Validate architecture file",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Server ID",
+                        "description": "Project ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Resource create request payload",
-                        "name": "resource",
+                        "description": "Architecture content to validate",
+                        "name": "import",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/input_contracts.CreateResourceApiInputContract"
+                            "$ref": "#/definitions/input_contracts.ImportFileInputContract"
                         }
+                    },
+                    {
+                        "type": "file",
+                        "description": "Archive with architecture files (multipart upload)",
+                        "name": "archive",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Architecture files (multipart upload)",
+                        "name": "files",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the root file in a multipart upload",
+                        "name": "root",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Resource created",
+                        "description": "File is valid, may contain warnings",
                         "schema": {
-                            "$ref": "#/definitions/logic.ResourceDBSerializerStruct"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "401": {
@@ -1587,7 +1688,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Server not found",
+                        "description": "Project not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1596,11 +1697,14 @@ const docTemplate = `{
                         }
                     },
                     "409": {
-                        "description": "Resource with this name already exists in this server",
+                        "description": "Validation errors and warnings",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
-                                "type": "string"
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/logic.ImportValidationIssue"
+                                }
                             }
                         }
                     },
@@ -1613,9 +1717,121 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/public/authentication": {
-            "post": {
-                "description": "Sign in via login and password",
+        "/v1/protected/projects/{id}/lint": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lint the current versions of all JSON schemas of the project with the lint rules of the project",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schema linting"
+                ],
+                "summary": "Lint all schemas of project",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Lint findings of every schema",
+                        "schema": {
+                            "$ref": "#/definitions/logic.ProjectLintSerializerStruct"
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/projects/{id}/lint/rules": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all lint rules with their configuration in the project: whether they are enabled,\nthe severity of their findings and their option (the naming convention of property-naming)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schema linting"
+                ],
+                "summary": "Get lint rules of project",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Lint rules",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/logic.SchemaLintRuleSerializerStruct"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/projects/{id}/lint/rules/{rule}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Enable or disable a lint rule in the project, change the severity of its findings or its option.\nFindings with the \"error\" severity reject new schemas and versions. Omitted fields keep their values.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1623,29 +1839,58 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Authentication related"
+                    "Schema linting"
                 ],
-                "summary": "Sign in via login and password",
+                "summary": "Configure lint rule of project",
                 "parameters": [
                     {
-                        "description": "Sign in request payload",
-                        "name": "project",
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Rule, e.g. property-description",
+                        "name": "rule",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rule configuration",
+                        "name": "settings",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/input_contracts.SignInSignUpApiInputContract"
+                            "$ref": "#/definitions/input_contracts.SchemaLintRuleApiInputContract"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully signed in",
+                        "description": "Rule configuration",
                         "schema": {
-                            "$ref": "#/definitions/logic.UserDBSerializerStruct"
+                            "$ref": "#/definitions/logic.SchemaLintRuleSerializerStruct"
                         }
                     },
                     "401": {
-                        "description": "Invalid login or password"
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Project or rule not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     },
                     "422": {
                         "description": "JSON payload validation errors",
@@ -1656,9 +1901,76 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/public/users": {
+        "/v1/protected/projects/{id}/messages": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get list of messages in a project",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Messages"
+                ],
+                "summary": "Get messages in project",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of messages in project",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/logic.MessageDBSerializerStruct"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
             "post": {
-                "description": "Sign up via email and password with optional invitation code",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new message in a project. Messages of protobuf schemas can select a message type of the schema with schema_message_type, the first message of the schema is used by default.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1666,510 +1978,4953 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Authentication related"
+                    "Messages"
                 ],
-                "summary": "Sign up via email and password",
+                "summary": "Create message",
                 "parameters": [
                     {
-                        "description": "Sign up request payload",
-                        "name": "project",
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Message creation payload",
+                        "name": "message",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/input_contracts.SignInSignUpApiInputContract"
+                            "$ref": "#/definitions/input_contracts.CreateMessageApiInputContract"
                         }
-                    },
-                    {
-                        "type": "string",
-                        "description": "Optional invitation code",
-                        "name": "code",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successful sign up",
+                        "description": "Created message",
                         "schema": {
-                            "$ref": "#/definitions/logic.UserDBSerializerStruct"
+                            "$ref": "#/definitions/logic.MessageDBSerializerStruct"
                         }
                     },
-                    "409": {
-                        "description": "User with specified email is already registered in the system"
+                    "400": {
+                        "description": "Schema does not belong to this project or schema version does not exist",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     },
-                    "422": {
-                        "description": "JSON payload validation error",
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
                         "schema": {
-                            "$ref": "#/definitions/api.DataValidationErrorAPIResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
-                    }
+                    },
+                    "404": {
+                        "description": "Project or schema not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Message with this name already exists in this project",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "JSON payload validation errors, retired schema or schema version, or unknown message type of a protobuf schema",
+                        "schema": {
+                            "$ref": "#/definitions/api.DataValidationErrorAPIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/projects/{id}/registry/compatibility/subjects/{subject}/versions/{version}": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Confluent compatible schema registry: check the schema against a version of the subject in the directions\nof its compatibility mode. Without a version the schema is checked as a new version of the subject.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schema registry"
+                ],
+                "summary": "Check compatibility of a schema with a subject",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Subject",
+                        "name": "subject",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Version number or latest",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Schema",
+                        "name": "schema",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/input_contracts.RegistrySchemaApiInputContract"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Compatibility of the schema",
+                        "schema": {
+                            "$ref": "#/definitions/logic.RegistryCompatibilitySerializerStruct"
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Project, subject or version not found",
+                        "schema": {
+                            "$ref": "#/definitions/logic.RegistryErrorSerializerStruct"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid schema or version",
+                        "schema": {
+                            "$ref": "#/definitions/logic.RegistryErrorSerializerStruct"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/projects/{id}/registry/config": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Confluent compatible schema registry: get the compatibility level of new subjects, the schema\ncompatibility of the project",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schema registry"
+                ],
+                "summary": "Get the default compatibility level",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Compatibility level",
+                        "schema": {
+                            "$ref": "#/definitions/logic.RegistryConfigSerializerStruct"
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/logic.RegistryErrorSerializerStruct"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Confluent compatible schema registry: change the compatibility level of schemas created afterwards\nwithout an explicit compatibility mode",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schema registry"
+                ],
+                "summary": "Change the default compatibility level",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Compatibility level, e.g. BACKWARD",
+                        "name": "config",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/input_contracts.RegistryConfigApiInputContract"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "New compatibility level",
+                        "schema": {
+                            "$ref": "#/definitions/logic.RegistryConfigUpdateSerializerStruct"
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/logic.RegistryErrorSerializerStruct"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid compatibility level",
+                        "schema": {
+                            "$ref": "#/definitions/logic.RegistryErrorSerializerStruct"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/projects/{id}/registry/config/{subject}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Confluent compatible schema registry: get the compatibility mode of the schema of the subject",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schema registry"
+                ],
+                "summary": "Get the compatibility level of a subject",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Subject",
+                        "name": "subject",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Compatibility level",
+                        "schema": {
+                            "$ref": "#/definitions/logic.RegistryConfigSerializerStruct"
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Project or subject not found",
+                        "schema": {
+                            "$ref": "#/definitions/logic.RegistryErrorSerializerStruct"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Confluent compatible schema registry: change the compatibility mode of the schema of the subject.\nSubjects of messages are read-only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schema registry"
+                ],
+                "summary": "Change the compatibility level of a subject",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Subject",
+                        "name": "subject",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Compatibility level, e.g. BACKWARD",
+                        "name": "config",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/input_contracts.RegistryConfigApiInputContract"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "New compatibility level",
+                        "schema": {
+                            "$ref": "#/definitions/logic.RegistryConfigUpdateSerializerStruct"
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Project or subject not found",
+                        "schema": {
+                            "$ref": "#/definitions/logic.RegistryErrorSerializerStruct"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid compatibility level or subject of a message",
+                        "schema": {
+                            "$ref": "#/definitions/logic.RegistryErrorSerializerStruct"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/projects/{id}/registry/schemas/ids/{registryID}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Confluent compatible schema registry: get a schema by the ID serializers embed into messages.\nIDs of schemas are IDs of schema versions of the project.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schema registry"
+                ],
+                "summary": "Get a schema by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Schema ID",
+                        "name": "registryID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Schema",
+                        "schema": {
+                            "$ref": "#/definitions/logic.RegistrySchemaByIDSerializerStruct"
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Project or schema not found",
+                        "schema": {
+                            "$ref": "#/definitions/logic.RegistryErrorSerializerStruct"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/projects/{id}/registry/schemas/ids/{registryID}/schema": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Confluent compatible schema registry: get only the schema with the ID",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "Schema registry"
+                ],
+                "summary": "Get only a schema by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Schema ID",
+                        "name": "registryID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Schema",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Project or schema not found",
+                        "schema": {
+                            "$ref": "#/definitions/logic.RegistryErrorSerializerStruct"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/projects/{id}/registry/schemas/ids/{registryID}/versions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Confluent compatible schema registry: list the subject of the schema of the ID and subjects of\nmessages which use it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schema registry"
+                ],
+                "summary": "List subjects and versions of a schema",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Schema ID",
+                        "name": "registryID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Subjects and versions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/logic.RegistrySubjectVersionSerializerStruct"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Project or schema not found",
+                        "schema": {
+                            "$ref": "#/definitions/logic.RegistryErrorSerializerStruct"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/projects/{id}/registry/schemas/types": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Confluent compatible schema registry: list supported schema types",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schema registry"
+                ],
+                "summary": "List schema types of the schema registry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Schema types",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/logic.RegistryErrorSerializerStruct"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/projects/{id}/registry/subjects": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Confluent compatible schema registry: list subjects, which are schemas and messages of the project.\nSubjects are mapped to names of schemas and messages by replacing characters other than letters, digits\nand underscores with underscores, \"-key\" and \"-value\" suffixes are dropped when nothing has the full name.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schema registry"
+                ],
+                "summary": "List subjects of the schema registry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Subjects",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/logic.RegistryErrorSerializerStruct"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/projects/{id}/registry/subjects/{subject}": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Confluent compatible schema registry: find the version of a subject with the schema",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schema registry"
+                ],
+                "summary": "Check if a schema is registered under a subject",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Subject",
+                        "name": "subject",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Schema",
+                        "name": "schema",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/input_contracts.RegistrySchemaApiInputContract"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Version of the subject with the schema",
+                        "schema": {
+                            "$ref": "#/definitions/logic.RegistrySchemaSerializerStruct"
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Project, subject or schema not found",
+                        "schema": {
+                            "$ref": "#/definitions/logic.RegistryErrorSerializerStruct"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid schema",
+                        "schema": {
+                            "$ref": "#/definitions/logic.RegistryErrorSerializerStruct"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/projects/{id}/registry/subjects/{subject}/versions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Confluent compatible schema registry: list versions of a subject. Subjects of schemas have all versions\nof the schemas, subjects of messages have the schema versions of the messages.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schema registry"
+                ],
+                "summary": "List versions of a subject",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Subject",
+                        "name": "subject",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Versions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Project or subject not found",
+                        "schema": {
+                            "$ref": "#/definitions/logic.RegistryErrorSerializerStruct"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Confluent compatible schema registry: register a schema under a subject and get its ID. Registering\na schema the subject already has returns its ID. Unknown subjects become new schemas of the project,\nnew versions must satisfy the compatibility modes of the schemas. Subjects of messages are read-only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schema registry"
+                ],
+                "summary": "Register a schema under a subject",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Subject",
+                        "name": "subject",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Schema",
+                        "name": "schema",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/input_contracts.RegistrySchemaApiInputContract"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ID of the schema",
+                        "schema": {
+                            "$ref": "#/definitions/logic.RegistryRegisteredSchemaSerializerStruct"
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/logic.RegistryErrorSerializerStruct"
+                        }
+                    },
+                    "409": {
+                        "description": "The schema is incompatible with an earlier version",
+                        "schema": {
+                            "$ref": "#/definitions/logic.RegistryErrorSerializerStruct"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid schema or subject of a message",
+                        "schema": {
+                            "$ref": "#/definitions/logic.RegistryErrorSerializerStruct"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/projects/{id}/registry/subjects/{subject}/versions/{version}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Confluent compatible schema registry: get a version of a subject with its schema and ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schema registry"
+                ],
+                "summary": "Get a version of a subject",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Subject",
+                        "name": "subject",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Version number or latest",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Version of the subject",
+                        "schema": {
+                            "$ref": "#/definitions/logic.RegistrySchemaSerializerStruct"
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Project, subject or version not found",
+                        "schema": {
+                            "$ref": "#/definitions/logic.RegistryErrorSerializerStruct"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid version",
+                        "schema": {
+                            "$ref": "#/definitions/logic.RegistryErrorSerializerStruct"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/projects/{id}/registry/subjects/{subject}/versions/{version}/schema": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Confluent compatible schema registry: get only the schema of a version of a subject",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "Schema registry"
+                ],
+                "summary": "Get the schema of a version of a subject",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Subject",
+                        "name": "subject",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Version number or latest",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Schema",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Project, subject or version not found",
+                        "schema": {
+                            "$ref": "#/definitions/logic.RegistryErrorSerializerStruct"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid version",
+                        "schema": {
+                            "$ref": "#/definitions/logic.RegistryErrorSerializerStruct"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/projects/{id}/schemas": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all schemas in project",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schemas"
+                ],
+                "summary": "Get all schemas in project",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of schemas in project",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/logic.SchemaDBSerializerStruct"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create new schema in project. Supported schema types are \"jsonschema\" (JSON schemas must\ndeclare their dialect in the \"$schema\" field), \"avro\" (Apache Avro schema definitions, .avsc)\nand \"protobuf\" (.proto sources, import \"name.proto\" refers to the protobuf schema \"name\" of the same project).\nThe compatibility mode (none, backward, backward_transitive, forward, forward_transitive, full, full_transitive)\nis enforced when new versions are created, protobuf schemas support only \"none\".\nJSON schemas are linted with the rules of the project, findings are returned in \"lint\".",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schemas"
+                ],
+                "summary": "Create new schema in project",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New schema request payload",
+                        "name": "project",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/input_contracts.CreateSchemaApiInputContract"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Nodified schema",
+                        "schema": {
+                            "$ref": "#/definitions/logic.SchemaDBSerializerStruct"
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header"
+                    },
+                    "404": {
+                        "description": "Project not found"
+                    },
+                    "422": {
+                        "description": "The schema breaks lint rules with the error severity",
+                        "schema": {
+                            "$ref": "#/definitions/logic.SchemaLintErrorSerializerStruct"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/projects/{id}/schemas/infer": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Infer a draft 2020-12 JSON schema from sample payloads sent in \"payloads\" or uploaded as an NDJSON\n\"file\" part of a multipart form (other fields are sent as form fields then). Types are merged across\nsamples, properties present in every sample are required, strings get formats (date-time, date, time,\nuuid, email, ipv4, ipv6, uri) when all of them match and enums when they take a few repeated values.\nThe inferred schema is saved as a new schema when \"name\" is set, or as a new version of the schema\n\"schema_id\" when it is set, new versions are checked against the compatibility mode of the schema.",
+                "consumes": [
+                    "application/json",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schemas"
+                ],
+                "summary": "Infer JSON schema from sample payloads",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Sample payloads",
+                        "name": "samples",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/input_contracts.InferSchemaApiInputContract"
+                        }
+                    },
+                    {
+                        "type": "file",
+                        "description": "Sample payloads, one JSON value per line (multipart upload)",
+                        "name": "file",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Inferred schema and the saved schema",
+                        "schema": {
+                            "$ref": "#/definitions/logic.InferredSchemaSerializerStruct"
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Project or schema not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "The schema name is taken or the new version breaks the compatibility mode",
+                        "schema": {
+                            "$ref": "#/definitions/logic.SchemaCompatibilityErrorSerializerStruct"
+                        }
+                    },
+                    "422": {
+                        "description": "Payload validation errors",
+                        "schema": {
+                            "$ref": "#/definitions/api.DataValidationErrorAPIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/projects/{id}/servers": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all servers in a project",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Servers"
+                ],
+                "summary": "Get all servers in a project",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of servers",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/logic.ServerDBSerializerStruct"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new server in project",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Servers"
+                ],
+                "summary": "Create a new server in project",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Server create request payload",
+                        "name": "server",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/input_contracts.CreateServerApiInputContract"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Server created",
+                        "schema": {
+                            "$ref": "#/definitions/logic.ServerDBSerializerStruct"
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Server with this name already exists in this project",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "JSON payload validation errors",
+                        "schema": {
+                            "$ref": "#/definitions/api.DataValidationErrorAPIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/schemas/{schemaID}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get schema",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schemas"
+                ],
+                "summary": "Get schema",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Schema ID",
+                        "name": "schemaID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Schema information",
+                        "schema": {
+                            "$ref": "#/definitions/logic.SchemaDBSerializerStruct"
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Schema not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Modify schema by creating a new version. The new version is checked against previous versions\naccording to the compatibility mode of the schema, incompatible versions are rejected with\nthe list of violations unless \"ignore_compatibility\" is set. JSON schemas are linted with\nthe rules of the project, findings are returned in \"lint\".",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schemas"
+                ],
+                "summary": "Modify schema",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Schema ID",
+                        "name": "schemaID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Schema modification payload",
+                        "name": "schema",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/input_contracts.ModifySchemaApiInputContract"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Modified schema",
+                        "schema": {
+                            "$ref": "#/definitions/logic.SchemaDBSerializerStruct"
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Schema not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "The new version breaks the compatibility mode",
+                        "schema": {
+                            "$ref": "#/definitions/logic.SchemaCompatibilityErrorSerializerStruct"
+                        }
+                    },
+                    "422": {
+                        "description": "JSON payload validation errors or the new version breaks lint rules with the error severity",
+                        "schema": {
+                            "$ref": "#/definitions/logic.SchemaLintErrorSerializerStruct"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete schema from the project, its name can be reused afterwards. Schemas referenced by other schemas\nor used by messages can't be deleted, the response lists the referencing schema versions and the messages.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schemas"
+                ],
+                "summary": "Delete schema",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Schema ID",
+                        "name": "schemaID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Schema is deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Schema not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "The schema is referenced by other schemas or used by messages",
+                        "schema": {
+                            "$ref": "#/definitions/logic.SchemaInUseErrorSerializerStruct"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/schemas/{schemaID}/code/{language}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Generate code from schema in specified programming language. The generated code will be returned as a plain text file with appropriate content type headers.\nCode of Avro and Protocol Buffers schemas can be generated only in Go. For protobuf schemas\nthe message_type query parameter selects the generated message, the first message of the file is used by default.",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "Schemas"
+                ],
+                "summary": "Generate code from schema in specified language",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Schema ID",
+                        "name": "schemaID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "typescript",
+                            "java",
+                            "go",
+                            "python"
+                        ],
+                        "type": "string",
+                        "description": "Programming language",
+                        "name": "language",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Full name of a message type of a protobuf schema",
+                        "name": "message_type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Generated code as plain text file",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid language or schema type",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Schema not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "504": {
+                        "description": "Code generation timed out",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/schemas/{schemaID}/compatibility": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change the compatibility mode which new versions of the schema must satisfy.\nExisting versions are not rechecked.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schemas"
+                ],
+                "summary": "Change compatibility mode of schema",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Schema ID",
+                        "name": "schemaID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Compatibility mode",
+                        "name": "compatibility",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/input_contracts.SchemaCompatibilityApiInputContract"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Modified schema",
+                        "schema": {
+                            "$ref": "#/definitions/logic.SchemaDBSerializerStruct"
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Schema not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "JSON payload validation errors",
+                        "schema": {
+                            "$ref": "#/definitions/api.DataValidationErrorAPIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/schemas/{schemaID}/diff": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Compare two versions of a JSON schema. Changes are reported by JSON pointers of the changed\nschema locations (added, removed and changed properties, type, required set, enum and constraint changes).\nA change breaks producers when data valid against the \"from\" version can be invalid against the \"to\" version,\nand breaks consumers when data valid against the \"to\" version can be invalid against the \"from\" version.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schemas"
+                ],
+                "summary": "Get structural diff between schema versions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Schema ID",
+                        "name": "schemaID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Version to compare from",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Version to compare to",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Changes between the versions",
+                        "schema": {
+                            "$ref": "#/definitions/logic.SchemaDiffSerializerStruct"
+                        }
+                    },
+                    "400": {
+                        "description": "Diffs are not supported for the schema type",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Schema or version not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid version numbers",
+                        "schema": {
+                            "$ref": "#/definitions/api.DataValidationErrorAPIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/schemas/{schemaID}/impact": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List messages pinned to every version of the schema, apps which send or receive the messages\nand resources the messages travel through.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schemas"
+                ],
+                "summary": "Get impact of schema versions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Schema ID",
+                        "name": "schemaID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Usage of the schema versions",
+                        "schema": {
+                            "$ref": "#/definitions/logic.SchemaImpactSerializerStruct"
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Schema not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List messages pinned to every version of the schema, apps which send or receive the messages\nand resources the messages travel through, and check a proposed new version without creating it.\nReceivers of messages pinned to a version break when data of the proposed version is invalid\nagainst their version (forward violations), senders break when readers on the proposed version\ncan't read data of their version (backward violations). Both directions are checked whatever\nthe compatibility mode is, \"compatible\" tells if the compatibility mode allows the proposed version.\nProposed versions of protobuf schemas can't be checked.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schemas"
+                ],
+                "summary": "Get impact of proposed schema version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Schema ID",
+                        "name": "schemaID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Proposed new version of the schema",
+                        "name": "schema",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/input_contracts.SchemaImpactApiInputContract"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Usage of the schema versions and apps broken by the proposed version",
+                        "schema": {
+                            "$ref": "#/definitions/logic.SchemaImpactSerializerStruct"
+                        }
+                    },
+                    "400": {
+                        "description": "Proposed versions are not supported for the schema type",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Schema not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "JSON payload validation errors",
+                        "schema": {
+                            "$ref": "#/definitions/api.DataValidationErrorAPIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/schemas/{schemaID}/lifecycle": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change the lifecycle state of the schema: draft, active, deprecated or retired.\nDrafts become active, active schemas are deprecated or retired, deprecated schemas are retired\nor become active again, retired schemas stay retired. Deprecated and retired schemas can have\na deprecation reason, a sunset date and the name of the replacing schema.\nRetired schemas can't be used by new messages, generated Go code of deprecated and retired schemas\nhas \"// Deprecated:\" comments.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schemas"
+                ],
+                "summary": "Change lifecycle state of schema",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Schema ID",
+                        "name": "schemaID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Lifecycle state",
+                        "name": "lifecycle",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/input_contracts.LifecycleApiInputContract"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Modified schema",
+                        "schema": {
+                            "$ref": "#/definitions/logic.SchemaDBSerializerStruct"
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Schema not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid state change or replacement",
+                        "schema": {
+                            "$ref": "#/definitions/api.DataValidationErrorAPIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/schemas/{schemaID}/references": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "JSON schemas reference other JSON schemas of the project by URIs of their versions,\ne.g. {\"$ref\": \"fusioncat://schemas/address/versions/2\"}. Get versions of other schemas referenced\nby versions of the schema, and versions of other schemas which reference versions of the schema.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schemas"
+                ],
+                "summary": "Get references of schema",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Schema ID",
+                        "name": "schemaID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "References of the schema",
+                        "schema": {
+                            "$ref": "#/definitions/logic.SchemaReferencesSerializerStruct"
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Schema not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/schemas/{schemaID}/versions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get list of schema versions",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schemas"
+                ],
+                "summary": "Get list of schema versions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Schema ID",
+                        "name": "schemaID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of schema versions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/logic.SchemaEditDBSerializerStruct"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Schema not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/schemas/{schemaID}/versions/{versionID}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a single schema version",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schemas"
+                ],
+                "summary": "Get a single schema version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Schema ID",
+                        "name": "schemaID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Version ID (integer number)",
+                        "name": "versionID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Schema version information",
+                        "schema": {
+                            "$ref": "#/definitions/logic.SchemaEditDBSerializerStruct"
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Schema version not found or can't be accessed by user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/schemas/{schemaID}/versions/{versionID}/convert": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Convert a version of the schema to a JSON schema, an Avro schema or a protobuf definition. Objects\nbecome records and messages, optional fields become nullable, string formats become logical types\nand well-known types. Avro and protobuf schemas are converted to each other through JSON Schema.\nConstructs which the target type can't express, e.g. constraints of JSON schemas or oneofs of protobuf\nmessages, are reported as losses. The result is saved as a new schema linked to the version when \"name\" is set.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schemas"
+                ],
+                "summary": "Convert schema version to another schema type",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Schema ID",
+                        "name": "schemaID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Schema version",
+                        "name": "versionID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Target type and the new schema",
+                        "name": "conversion",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/input_contracts.ConvertSchemaVersionApiInputContract"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Converted schema, losses and the saved schema",
+                        "schema": {
+                            "$ref": "#/definitions/logic.SchemaConversionSerializerStruct"
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Schema or version not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "The schema name is taken",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid payload, unknown message type or the schema is of the target type",
+                        "schema": {
+                            "$ref": "#/definitions/api.DataValidationErrorAPIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/schemas/{schemaID}/versions/{versionID}/examples": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Generate example payloads valid against a version of the schema. Examples honor types, formats,\nenums, numeric bounds, lengths, patterns, required properties and \"examples\" of JSON schemas.\nAvro examples are in the Avro JSON encoding, protobuf examples in the proto3 JSON mapping,\nmessage_type selects a message of protobuf schemas. The same seed produces the same examples,\nrequests without a seed get a random one, which is returned with the examples.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schemas"
+                ],
+                "summary": "Generate example payloads of schema version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Schema ID",
+                        "name": "schemaID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Schema version",
+                        "name": "versionID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of examples, 1 by default, at most 100",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Seed of the generator",
+                        "name": "seed",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Full name of a message type of a protobuf schema",
+                        "name": "message_type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Generated examples",
+                        "schema": {
+                            "$ref": "#/definitions/logic.SchemaExamplesSerializerStruct"
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Schema or version not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid count or seed, unknown message type or no valid example could be generated",
+                        "schema": {
+                            "$ref": "#/definitions/api.DataValidationErrorAPIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/schemas/{schemaID}/versions/{versionID}/fields": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get fields of a version of the schema, flattened. Paths are JSON pointers to the fields in the data,\nitems of arrays and values of maps are \"*\". Fields of protobuf schemas are listed for every message type.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schemas"
+                ],
+                "summary": "Get field catalog of schema version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Schema ID",
+                        "name": "schemaID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Schema version",
+                        "name": "versionID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Fields of the schema version",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/logic.SchemaFieldSerializerStruct"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Schema or version not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/schemas/{schemaID}/versions/{versionID}/lifecycle": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change the lifecycle state of a version of the schema, following the same rules as schemas.\nVersions are replaced by versions of schemas of the project, referenced by their URIs,\ne.g. fusioncat://schemas/order_created/versions/2. Retired versions can't be used by new messages.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schemas"
+                ],
+                "summary": "Change lifecycle state of schema version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Schema ID",
+                        "name": "schemaID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Schema version",
+                        "name": "versionID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Lifecycle state",
+                        "name": "lifecycle",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/input_contracts.LifecycleApiInputContract"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Modified schema version",
+                        "schema": {
+                            "$ref": "#/definitions/logic.SchemaEditDBSerializerStruct"
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Schema or version not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid state change or replacement",
+                        "schema": {
+                            "$ref": "#/definitions/api.DataValidationErrorAPIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/schemas/{schemaID}/versions/{versionID}/revert": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new version of the schema with the content of a previous version, versions are never\nrewritten. The new version is checked against the compatibility mode of the schema like any other\none, unless \"ignore_compatibility\" is set. \"move_messages\" moves messages pinned to the current\nversion to the new one, protobuf messages selecting a message type the new version doesn't define are skipped.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schemas"
+                ],
+                "summary": "Revert schema to a previous version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Schema ID",
+                        "name": "schemaID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Version to revert to",
+                        "name": "versionID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Revert options",
+                        "name": "options",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/input_contracts.RevertSchemaVersionApiInputContract"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Schema with the new version and moved messages",
+                        "schema": {
+                            "$ref": "#/definitions/logic.SchemaRevertSerializerStruct"
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Schema or version not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "The new version breaks the compatibility mode",
+                        "schema": {
+                            "$ref": "#/definitions/logic.SchemaCompatibilityErrorSerializerStruct"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid payload or the version is the current one",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/schemas/{schemaID}/versions/{versionID}/validate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Validate one (\"payload\") or many (\"payloads\") JSON payloads against a version of the schema.\nEvery payload gets its own result with the failures, JSON schemas report JSON pointers\nto the invalid values and the failed keywords. Avro data is expected in the Avro JSON encoding,\nprotobuf messages in the proto3 JSON mapping, message_type selects a message of protobuf schemas.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schemas"
+                ],
+                "summary": "Validate payloads against schema version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Schema ID",
+                        "name": "schemaID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Schema version",
+                        "name": "versionID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Full name of a message type of a protobuf schema",
+                        "name": "message_type",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Payloads to validate",
+                        "name": "payloads",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/input_contracts.ValidatePayloadsApiInputContract"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Validation results",
+                        "schema": {
+                            "$ref": "#/definitions/logic.PayloadsValidationSerializerStruct"
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Schema or version not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "No payloads or unknown message type",
+                        "schema": {
+                            "$ref": "#/definitions/api.DataValidationErrorAPIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/servers/{id}/binds": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all resource bindings for resources in a server",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Server Resources"
+                ],
+                "summary": "Get all resource bindings in a server",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Server ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of resource bindings",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/logic.ResourceBindingDBSerializerStruct"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Server not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a resource binding between two resources in the same server",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Server Resources"
+                ],
+                "summary": "Create a resource binding in server",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Server ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Resource binding request payload",
+                        "name": "binding",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/input_contracts.CreateResourceBindApiInputContract"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Resource binding created",
+                        "schema": {
+                            "$ref": "#/definitions/logic.ResourceBindingDBSerializerStruct"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input or resources not in same server",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Server or resource not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Resource binding already exists",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "JSON payload validation errors",
+                        "schema": {
+                            "$ref": "#/definitions/api.DataValidationErrorAPIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/servers/{id}/resources": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all resources in a server",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Server Resources"
+                ],
+                "summary": "Get all resources in a server",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Server ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of resources",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/logic.ResourceDBSerializerStruct"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Server not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new resource in server",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Server Resources"
+                ],
+                "summary": "Create a new resource in server",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Server ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Resource create request payload",
+                        "name": "resource",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/input_contracts.CreateResourceApiInputContract"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Resource created",
+                        "schema": {
+                            "$ref": "#/definitions/logic.ResourceDBSerializerStruct"
+                        }
+                    },
+                    "401": {
+                        "description": "Access denied: missing or invalid Authorization header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Server not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Resource with this name already exists in this server",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "JSON payload validation errors",
+                        "schema": {
+                            "$ref": "#/definitions/api.DataValidationErrorAPIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/public/authentication": {
+            "post": {
+                "description": "Sign in via login and password",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication related"
+                ],
+                "summary": "Sign in via login and password",
+                "parameters": [
+                    {
+                        "description": "Sign in request payload",
+                        "name": "project",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/input_contracts.SignInSignUpApiInputContract"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully signed in",
+                        "schema": {
+                            "$ref": "#/definitions/logic.UserDBSerializerStruct"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid login or password"
+                    },
+                    "422": {
+                        "description": "JSON payload validation errors",
+                        "schema": {
+                            "$ref": "#/definitions/api.DataValidationErrorAPIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/public/users": {
+            "post": {
+                "description": "Sign up via email and password with optional invitation code",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication related"
+                ],
+                "summary": "Sign up via email and password",
+                "parameters": [
+                    {
+                        "description": "Sign up request payload",
+                        "name": "project",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/input_contracts.SignInSignUpApiInputContract"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Optional invitation code",
+                        "name": "code",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful sign up",
+                        "schema": {
+                            "$ref": "#/definitions/logic.UserDBSerializerStruct"
+                        }
+                    },
+                    "409": {
+                        "description": "User with specified email is already registered in the system"
+                    },
+                    "422": {
+                        "description": "JSON payload validation error",
+                        "schema": {
+                            "$ref": "#/definitions/api.DataValidationErrorAPIResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "api.APIDataFieldErrorResponseField": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.DataValidationErrorAPIResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.APIDataFieldErrorResponseField"
+                    }
+                }
+            }
+        },
+        "fusionlang.Error": {
+            "type": "object",
+            "properties": {
+                "column": {
+                    "type": "integer"
+                },
+                "line": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "input_contracts.ConfigureGitSyncInputContract": {
+            "type": "object",
+            "required": [
+                "branch",
+                "repository_path"
+            ],
+            "properties": {
+                "branch": {
+                    "type": "string"
+                },
+                "poll_interval_seconds": {
+                    "type": "integer",
+                    "minimum": 10
+                },
+                "repository_path": {
+                    "type": "string"
+                },
+                "root_file": {
+                    "type": "string"
+                },
+                "subdirectory": {
+                    "type": "string"
+                }
+            }
+        },
+        "input_contracts.ConvertSchemaVersionApiInputContract": {
+            "type": "object",
+            "required": [
+                "target"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "message_type": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 45,
+                    "minLength": 1
+                },
+                "target": {
+                    "type": "string",
+                    "enum": [
+                        "jsonschema",
+                        "avro",
+                        "protobuf"
+                    ]
+                }
+            }
+        },
+        "input_contracts.CreateAppApiInputContract": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 45,
+                    "minLength": 1
+                }
+            }
+        },
+        "input_contracts.CreateMessageApiInputContract": {
+            "type": "object",
+            "required": [
+                "name",
+                "schema_id",
+                "schema_version"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 45,
+                    "minLength": 1
+                },
+                "schema_id": {
+                    "type": "string"
+                },
+                "schema_message_type": {
+                    "description": "Message type inside a protobuf schema, the first message of the schema is used when empty",
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "schema_version": {
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "input_contracts.CreateModifyProjectApiInputContract": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 45,
+                    "minLength": 1
+                }
+            }
+        },
+        "input_contracts.CreateResourceApiInputContract": {
+            "type": "object",
+            "required": [
+                "mode",
+                "name",
+                "resource_type"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "mode": {
+                    "type": "string",
+                    "enum": [
+                        "read",
+                        "write",
+                        "readwrite"
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                },
+                "resource_type": {
+                    "type": "string",
+                    "enum": [
+                        "topic",
+                        "exchange",
+                        "queue",
+                        "table",
+                        "endpoint"
+                    ]
+                }
+            }
+        },
+        "input_contracts.CreateResourceBindApiInputContract": {
+            "type": "object",
+            "required": [
+                "source_resource_id",
+                "target_resource_id"
+            ],
+            "properties": {
+                "source_resource_id": {
+                    "type": "string"
+                },
+                "target_resource_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "input_contracts.CreateSchemaApiInputContract": {
+            "type": "object",
+            "required": [
+                "name",
+                "schema",
+                "type"
+            ],
+            "properties": {
+                "compatibility": {
+                    "description": "Compatibility mode which new versions of the schema must satisfy, the schema compatibility of the project by default",
+                    "type": "string",
+                    "enum": [
+                        "none",
+                        "backward",
+                        "backward_transitive",
+                        "forward",
+                        "forward_transitive",
+                        "full",
+                        "full_transitive"
+                    ]
+                },
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 45,
+                    "minLength": 1
+                },
+                "schema": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "jsonschema",
+                        "avro",
+                        "protobuf"
+                    ]
+                }
+            }
+        },
+        "input_contracts.CreateServerApiInputContract": {
+            "type": "object",
+            "required": [
+                "name",
+                "protocol"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 45,
+                    "minLength": 1
+                },
+                "protocol": {
+                    "type": "string"
+                }
+            }
+        },
+        "input_contracts.FusionlangSourceInputContract": {
+            "type": "object",
+            "required": [
+                "source"
+            ],
+            "properties": {
+                "source": {
+                    "type": "string"
+                }
+            }
+        },
+        "input_contracts.GitSyncInputContract": {
+            "type": "object",
+            "properties": {
+                "revision": {
+                    "type": "string"
+                }
+            }
+        },
+        "input_contracts.ImportFileInputContract": {
+            "type": "object",
+            "required": [
+                "yaml"
+            ],
+            "properties": {
+                "files": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "format": {
+                    "type": "string",
+                    "enum": [
+                        "yaml",
+                        "json",
+                        "fusionlang"
+                    ]
+                },
+                "yaml": {
+                    "type": "string"
+                }
+            }
+        },
+        "input_contracts.InferSchemaApiInputContract": {
+            "type": "object",
+            "properties": {
+                "compatibility": {
+                    "description": "Compatibility mode of a new schema, the schema compatibility of the project by default",
+                    "type": "string",
+                    "enum": [
+                        "none",
+                        "backward",
+                        "backward_transitive",
+                        "forward",
+                        "forward_transitive",
+                        "full",
+                        "full_transitive"
+                    ]
+                },
+                "description": {
+                    "type": "string"
+                },
+                "ignore_compatibility": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 45,
+                    "minLength": 1
+                },
+                "payloads": {
+                    "type": "array",
+                    "items": {
+                        "type": "object"
+                    }
+                },
+                "schema_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "input_contracts.LifecycleApiInputContract": {
+            "type": "object",
+            "required": [
+                "state"
+            ],
+            "properties": {
+                "deprecation_reason": {
+                    "type": "string"
+                },
+                "replaced_by": {
+                    "description": "Name of the replacing schema or message, URI of the replacing schema version\nlike fusioncat://schemas/order_created/versions/2",
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "state": {
+                    "type": "string",
+                    "enum": [
+                        "draft",
+                        "active",
+                        "deprecated",
+                        "retired"
+                    ]
+                },
+                "sunset_at": {
+                    "description": "Sunset date in the YYYY-MM-DD format",
+                    "type": "string"
+                }
+            }
+        },
+        "input_contracts.ModifySchemaApiInputContract": {
+            "type": "object",
+            "required": [
+                "schema"
+            ],
+            "properties": {
+                "ignore_compatibility": {
+                    "type": "boolean"
+                },
+                "schema": {
+                    "type": "string"
+                }
+            }
+        },
+        "input_contracts.RegistryConfigApiInputContract": {
+            "type": "object",
+            "required": [
+                "compatibility"
+            ],
+            "properties": {
+                "compatibility": {
+                    "type": "string"
+                }
+            }
+        },
+        "input_contracts.RegistrySchemaApiInputContract": {
+            "type": "object",
+            "required": [
+                "schema"
+            ],
+            "properties": {
+                "references": {
+                    "description": "References to schemas of other subjects are not supported, JSON schemas reference other schemas\nwith fusioncat:// URIs and protobuf schemas import them by their import paths",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/input_contracts.RegistrySchemaReferenceApiInputContract"
+                    }
+                },
+                "schema": {
+                    "type": "string"
+                },
+                "schemaType": {
+                    "description": "AVRO (default), JSON or PROTOBUF",
+                    "type": "string"
+                }
+            }
+        },
+        "input_contracts.RegistrySchemaReferenceApiInputContract": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "subject": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "input_contracts.RevertSchemaVersionApiInputContract": {
+            "type": "object",
+            "properties": {
+                "ignore_compatibility": {
+                    "type": "boolean"
+                },
+                "move_messages": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "input_contracts.SchemaCompatibilityApiInputContract": {
+            "type": "object",
+            "required": [
+                "compatibility"
+            ],
+            "properties": {
+                "compatibility": {
+                    "type": "string",
+                    "enum": [
+                        "none",
+                        "backward",
+                        "backward_transitive",
+                        "forward",
+                        "forward_transitive",
+                        "full",
+                        "full_transitive"
+                    ]
+                }
+            }
+        },
+        "input_contracts.SchemaImpactApiInputContract": {
+            "type": "object",
+            "required": [
+                "schema"
+            ],
+            "properties": {
+                "schema": {
+                    "type": "string"
+                }
+            }
+        },
+        "input_contracts.SchemaLintRuleApiInputContract": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "option": {
+                    "type": "string"
+                },
+                "severity": {
+                    "type": "string",
+                    "enum": [
+                        "error",
+                        "warning",
+                        "info"
+                    ]
+                }
+            }
+        },
+        "input_contracts.SignInSignUpApiInputContract": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 8
+                }
+            }
+        },
+        "input_contracts.ValidatePayloadsApiInputContract": {
+            "type": "object",
+            "properties": {
+                "payload": {
+                    "type": "object"
+                },
+                "payloads": {
+                    "type": "array",
+                    "items": {
+                        "type": "object"
+                    }
+                }
+            }
+        },
+        "jsonschemalint.Finding": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "path": {
+                    "description": "Path is a JSON pointer to the location in the schema, e.g. /properties/created_at",
+                    "type": "string"
+                },
+                "rule": {
+                    "type": "string"
+                },
+                "severity": {
+                    "type": "string"
+                }
+            }
+        },
+        "logic.AppDBSerializerStruct": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by_user_id": {
+                    "type": "string"
+                },
+                "created_by_user_name": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "project_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "logic.AppUsageMatrixReader": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "$ref": "#/definitions/logic.MessageDBSerializerStruct"
+                },
+                "resource": {
+                    "$ref": "#/definitions/logic.ResourceDBSerializerStruct"
+                },
+                "server": {
+                    "$ref": "#/definitions/logic.ServerDBSerializerStruct"
+                }
+            }
+        },
+        "logic.AppUsageMatrixResponse": {
+            "type": "object",
+            "properties": {
+                "app_id": {
+                    "type": "string"
+                },
+                "receives": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logic.AppUsageMatrixReader"
+                    }
+                },
+                "sends": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logic.AppUsageMatrixReader"
+                    }
+                }
+            }
+        },
+        "logic.CompatibilityViolation": {
+            "type": "object",
+            "properties": {
+                "direction": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "path": {
+                    "description": "Path is a JSON pointer to the schema location (JSON schemas) or to the data (Avro schemas)",
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version is the previous version the new content is checked against",
+                    "type": "integer"
+                }
+            }
+        },
+        "logic.FieldConflictSerializerStruct": {
+            "type": "object",
+            "properties": {
+                "kind": {
+                    "type": "string"
+                },
+                "values": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "logic.FieldConsistencyOccurrenceSerializerStruct": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "enum": {
+                    "type": "array",
+                    "items": {}
+                },
+                "format": {
+                    "type": "string"
+                },
+                "message_type": {
+                    "type": "string"
+                },
+                "messages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logic.SchemaUsageMessageStruct"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "normalized_format": {
+                    "type": "string"
+                },
+                "normalized_type": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "required": {
+                    "type": "boolean"
+                },
+                "schema_id": {
+                    "type": "string"
+                },
+                "schema_name": {
+                    "type": "string"
+                },
+                "schema_type": {
+                    "type": "string"
+                },
+                "schema_uri": {
+                    "description": "SchemaURI identifies the schema version, see SchemaReferenceURI",
+                    "type": "string"
+                },
+                "schema_version": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "unit": {
+                    "type": "string"
+                }
+            }
+        },
+        "logic.ImportValidationIssue": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "column": {
+                    "type": "integer"
+                },
+                "file": {
+                    "type": "string"
+                },
+                "line": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "severity": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "logic.ImportedEntitiesSerializerStruct": {
+            "type": "object",
+            "properties": {
+                "apps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logic.ImportedEntityStruct"
+                    }
+                },
+                "bindings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logic.ImportedEntityStruct"
+                    }
+                },
+                "connections": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logic.ImportedEntityStruct"
+                    }
+                },
+                "import_id": {
+                    "type": "string"
+                },
+                "messages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logic.ImportedEntityStruct"
+                    }
+                },
+                "resources": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logic.ImportedEntityStruct"
+                    }
+                },
+                "schemas": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logic.ImportedEntityStruct"
+                    }
+                },
+                "servers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logic.ImportedEntityStruct"
+                    }
+                }
+            }
+        },
+        "logic.ImportedEntityStruct": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "logic.InconsistentFieldSerializerStruct": {
+            "type": "object",
+            "properties": {
+                "conflicts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logic.FieldConflictSerializerStruct"
+                    }
+                },
+                "fields": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logic.FieldConsistencyOccurrenceSerializerStruct"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "logic.InferredSchemaSerializerStruct": {
+            "type": "object",
+            "properties": {
+                "samples": {
+                    "type": "integer"
+                },
+                "saved_schema": {
+                    "$ref": "#/definitions/logic.SchemaDBSerializerStruct"
+                },
+                "schema": {
+                    "type": "string"
+                }
+            }
+        },
+        "logic.LifecycleSerializerStruct": {
+            "type": "object",
+            "properties": {
+                "deprecation_reason": {
+                    "type": "string"
+                },
+                "replaced_by": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "sunset_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "logic.MessageDBSerializerStruct": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by_id": {
+                    "type": "string"
+                },
+                "created_by_name": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lifecycle": {
+                    "$ref": "#/definitions/logic.LifecycleSerializerStruct"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "project_id": {
+                    "type": "string"
+                },
+                "schema_id": {
+                    "type": "string"
+                },
+                "schema_message_type": {
+                    "type": "string"
+                },
+                "schema_version": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "logic.PayloadValidationErrorStruct": {
+            "type": "object",
+            "properties": {
+                "instance_location": {
+                    "type": "string"
+                },
+                "keyword": {
+                    "type": "string"
+                },
+                "keyword_location": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "logic.PayloadValidationResultStruct": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logic.PayloadValidationErrorStruct"
+                    }
+                },
+                "index": {
+                    "description": "Index is the position of the payload in the request",
+                    "type": "integer"
+                },
+                "valid": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "logic.PayloadsValidationSerializerStruct": {
+            "type": "object",
+            "properties": {
+                "message_type": {
+                    "type": "string"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logic.PayloadValidationResultStruct"
+                    }
+                },
+                "schema_id": {
+                    "type": "string"
+                },
+                "schema_version": {
+                    "type": "integer"
+                },
+                "valid": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "logic.ProjectConsistencySerializerStruct": {
+            "type": "object",
+            "properties": {
+                "checked_names": {
+                    "type": "integer"
+                },
+                "inconsistencies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logic.InconsistentFieldSerializerStruct"
+                    }
+                }
+            }
+        },
+        "logic.ProjectDBSerializerStruct": {
+            "type": "object",
+            "properties": {
+                "created_by_id": {
+                    "type": "string"
+                },
+                "created_by_name": {
+                    "type": "string"
+                },
+                "created_by_type": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_private": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "schema_compatibility": {
+                    "description": "Compatibility mode of schemas created without an explicit one",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "logic.ProjectGitSyncDBSerializerStruct": {
+            "type": "object",
+            "properties": {
+                "branch": {
+                    "type": "string"
+                },
+                "configured_by_user_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "last_import_id": {
+                    "type": "string"
+                },
+                "last_synced_at": {
+                    "type": "string"
+                },
+                "last_synced_commit_sha": {
+                    "type": "string"
+                },
+                "poll_interval_seconds": {
+                    "type": "integer"
+                },
+                "project_id": {
+                    "type": "string"
+                },
+                "repository_path": {
+                    "type": "string"
+                },
+                "root_file": {
+                    "type": "string"
+                },
+                "subdirectory": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "logic.ProjectImportCountsStruct": {
+            "type": "object",
+            "properties": {
+                "apps": {
+                    "type": "integer"
+                },
+                "bindings": {
+                    "type": "integer"
+                },
+                "connections": {
+                    "type": "integer"
+                },
+                "messages": {
+                    "type": "integer"
+                },
+                "resources": {
+                    "type": "integer"
+                },
+                "schema_versions": {
+                    "description": "Versions of schemas created by the job, including first versions of new schemas",
+                    "type": "integer"
+                },
+                "schemas": {
+                    "type": "integer"
+                },
+                "servers": {
+                    "type": "integer"
+                }
+            }
+        },
+        "logic.ProjectImportJobDBSerializerStruct": {
+            "type": "object",
+            "properties": {
+                "commit_sha": {
+                    "type": "string"
+                },
+                "counts": {
+                    "$ref": "#/definitions/logic.ProjectImportCountsStruct"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by_user_id": {
+                    "type": "string"
+                },
+                "created_by_user_name": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logic.ImportValidationIssue"
+                    }
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "phase": {
+                    "type": "string"
+                },
+                "project_id": {
+                    "type": "string"
+                },
+                "reverted_at": {
+                    "type": "string"
+                },
+                "reverted_by_user_id": {
+                    "type": "string"
+                },
+                "source": {
+                    "$ref": "#/definitions/logic.ProjectImportSourceStruct"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logic.ImportValidationIssue"
+                    }
+                }
+            }
+        },
+        "logic.ProjectImportSourceStruct": {
+            "type": "object",
+            "properties": {
+                "files": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "root_file": {
+                    "type": "string"
+                },
+                "root_format": {
+                    "type": "string"
+                }
+            }
+        },
+        "logic.ProjectLintSerializerStruct": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "integer"
+                },
+                "infos": {
+                    "type": "integer"
+                },
+                "schemas": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logic.SchemaLintResultSerializerStruct"
+                    }
+                },
+                "warnings": {
+                    "type": "integer"
+                }
+            }
+        },
+        "logic.RegistryCompatibilitySerializerStruct": {
+            "type": "object",
+            "properties": {
+                "is_compatible": {
+                    "type": "boolean"
+                },
+                "messages": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "logic.RegistryConfigSerializerStruct": {
+            "type": "object",
+            "properties": {
+                "compatibilityLevel": {
+                    "type": "string"
+                }
+            }
+        },
+        "logic.RegistryConfigUpdateSerializerStruct": {
+            "type": "object",
+            "properties": {
+                "compatibility": {
+                    "type": "string"
+                }
+            }
+        },
+        "logic.RegistryErrorSerializerStruct": {
+            "type": "object",
+            "properties": {
+                "error_code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "logic.RegistryRegisteredSchemaSerializerStruct": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "logic.RegistrySchemaByIDSerializerStruct": {
+            "type": "object",
+            "properties": {
+                "schema": {
+                    "type": "string"
+                },
+                "schemaType": {
+                    "type": "string"
+                }
+            }
+        },
+        "logic.RegistrySchemaSerializerStruct": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "schema": {
+                    "type": "string"
+                },
+                "schemaType": {
+                    "type": "string"
+                },
+                "subject": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "logic.RegistrySubjectVersionSerializerStruct": {
+            "type": "object",
+            "properties": {
+                "subject": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "logic.ResourceBindingDBSerializerStruct": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "source_resource_id": {
+                    "type": "string"
+                },
+                "target_resource_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "logic.ResourceDBSerializerStruct": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by_user_id": {
+                    "type": "string"
+                },
+                "created_by_user_name": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "project_id": {
+                    "type": "string"
+                },
+                "resource_type": {
+                    "type": "string"
+                },
+                "server_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
-        }
-    },
-    "definitions": {
-        "api.APIDataFieldErrorResponseField": {
+        },
+        "logic.SchemaCompatibilityErrorSerializerStruct": {
             "type": "object",
             "properties": {
-                "field": {
+                "compatibility": {
                     "type": "string"
                 },
-                "message": {
+                "error": {
                     "type": "string"
+                },
+                "violations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logic.CompatibilityViolation"
+                    }
                 }
             }
         },
-        "api.DataValidationErrorAPIResponse": {
+        "logic.SchemaConversionSerializerStruct": {
             "type": "object",
             "properties": {
-                "error": {
+                "losses": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/api.APIDataFieldErrorResponseField"
+                        "$ref": "#/definitions/schemaconvert.Loss"
                     }
+                },
+                "saved_schema": {
+                    "$ref": "#/definitions/logic.SchemaDBSerializerStruct"
+                },
+                "schema": {
+                    "type": "string"
+                },
+                "source_type": {
+                    "type": "string"
+                },
+                "target_type": {
+                    "type": "string"
                 }
             }
         },
-        "input_contracts.CreateAppApiInputContract": {
+        "logic.SchemaConversionSourceSerializerStruct": {
             "type": "object",
-            "required": [
-                "name"
-            ],
             "properties": {
-                "description": {
+                "schema_id": {
                     "type": "string"
                 },
-                "name": {
-                    "type": "string",
-                    "maxLength": 45,
-                    "minLength": 1
+                "schema_version": {
+                    "type": "integer"
                 }
             }
         },
-        "input_contracts.CreateMessageApiInputContract": {
+        "logic.SchemaDBSerializerStruct": {
             "type": "object",
-            "required": [
-                "name",
-                "schema_id",
-                "schema_version"
-            ],
             "properties": {
+                "compatibility": {
+                    "type": "string"
+                },
+                "converted_from": {
+                    "description": "Version of the schema this schema was converted from, see SchemaObject.ConvertVersion",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/logic.SchemaConversionSourceSerializerStruct"
+                        }
+                    ]
+                },
+                "created_by_id": {
+                    "type": "string"
+                },
+                "created_by_name": {
+                    "type": "string"
+                },
+                "created_by_type": {
+                    "type": "string"
+                },
                 "description": {
                     "type": "string"
                 },
+                "id": {
+                    "type": "string"
+                },
+                "lifecycle": {
+                    "$ref": "#/definitions/logic.LifecycleSerializerStruct"
+                },
+                "lint": {
+                    "description": "Lint findings are returned when schemas are created or modified",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/jsonschemalint.Finding"
+                    }
+                },
                 "name": {
-                    "type": "string",
-                    "maxLength": 45,
-                    "minLength": 1
+                    "type": "string"
                 },
-                "schema_id": {
+                "project_id": {
                     "type": "string"
                 },
-                "schema_version": {
-                    "type": "integer",
-                    "minimum": 1
+                "schema": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
-        "input_contracts.CreateModifyProjectApiInputContract": {
+        "logic.SchemaDiffChangeSerializerStruct": {
             "type": "object",
-            "required": [
-                "name"
-            ],
             "properties": {
-                "description": {
+                "breaking": {
+                    "type": "boolean"
+                },
+                "breaks_consumers": {
+                    "type": "boolean"
+                },
+                "breaks_producers": {
+                    "type": "boolean"
+                },
+                "kind": {
                     "type": "string"
                 },
-                "name": {
-                    "type": "string",
-                    "maxLength": 45,
-                    "minLength": 1
+                "message": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
                 }
             }
         },
-        "input_contracts.CreateResourceApiInputContract": {
+        "logic.SchemaDiffSerializerStruct": {
             "type": "object",
-            "required": [
-                "mode",
-                "name",
-                "resource_type"
-            ],
             "properties": {
-                "description": {
-                    "type": "string"
+                "breaking": {
+                    "type": "boolean"
                 },
-                "mode": {
-                    "type": "string",
-                    "enum": [
-                        "read",
-                        "write",
-                        "readwrite"
-                    ]
+                "breaks_consumers": {
+                    "type": "boolean"
                 },
-                "name": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 1
+                "breaks_producers": {
+                    "type": "boolean"
                 },
-                "resource_type": {
-                    "type": "string",
-                    "enum": [
-                        "topic",
-                        "exchange",
-                        "queue",
-                        "table",
-                        "endpoint"
-                    ]
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logic.SchemaDiffChangeSerializerStruct"
+                    }
+                },
+                "from": {
+                    "type": "integer"
+                },
+                "schema_id": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "integer"
                 }
             }
         },
-        "input_contracts.CreateResourceBindApiInputContract": {
+        "logic.SchemaEditDBSerializerStruct": {
             "type": "object",
-            "required": [
-                "source_resource_id",
-                "target_resource_id"
-            ],
             "properties": {
-                "source_resource_id": {
+                "commit_sha": {
                     "type": "string"
                 },
-                "target_resource_id": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by_name": {
+                    "type": "string"
+                },
+                "lifecycle": {
+                    "$ref": "#/definitions/logic.LifecycleSerializerStruct"
+                },
+                "schema": {
+                    "type": "string"
+                },
+                "schema_id": {
+                    "type": "string"
+                },
+                "user_id": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
-        "input_contracts.CreateSchemaApiInputContract": {
+        "logic.SchemaExamplesSerializerStruct": {
             "type": "object",
-            "required": [
-                "name",
-                "schema",
-                "type"
-            ],
             "properties": {
-                "description": {
-                    "type": "string"
+                "examples": {
+                    "type": "array",
+                    "items": {}
                 },
-                "name": {
-                    "type": "string",
-                    "maxLength": 45,
-                    "minLength": 1
+                "message_type": {
+                    "type": "string"
                 },
-                "schema": {
+                "schema_id": {
                     "type": "string"
                 },
-                "type": {
-                    "type": "string",
-                    "enum": [
-                        "jsonschema"
-                    ]
+                "schema_version": {
+                    "type": "integer"
+                },
+                "seed": {
+                    "type": "integer"
                 }
             }
         },
-        "input_contracts.CreateServerApiInputContract": {
+        "logic.SchemaFieldOccurrenceSerializerStruct": {
             "type": "object",
-            "required": [
-                "name",
-                "protocol"
-            ],
             "properties": {
                 "description": {
                     "type": "string"
                 },
+                "enum": {
+                    "type": "array",
+                    "items": {}
+                },
+                "format": {
+                    "type": "string"
+                },
+                "message_type": {
+                    "type": "string"
+                },
+                "messages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logic.SchemaUsageMessageStruct"
+                    }
+                },
                 "name": {
-                    "type": "string",
-                    "maxLength": 45,
-                    "minLength": 1
+                    "type": "string"
                 },
-                "protocol": {
+                "path": {
                     "type": "string"
-                }
-            }
-        },
-        "input_contracts.ImportFileInputContract": {
-            "type": "object",
-            "required": [
-                "yaml"
-            ],
-            "properties": {
-                "yaml": {
+                },
+                "required": {
+                    "type": "boolean"
+                },
+                "schema_id": {
+                    "type": "string"
+                },
+                "schema_name": {
+                    "type": "string"
+                },
+                "schema_type": {
+                    "type": "string"
+                },
+                "schema_uri": {
+                    "description": "SchemaURI identifies the schema version, see SchemaReferenceURI",
+                    "type": "string"
+                },
+                "schema_version": {
+                    "type": "integer"
+                },
+                "type": {
                     "type": "string"
                 }
             }
         },
-        "input_contracts.ModifySchemaApiInputContract": {
+        "logic.SchemaFieldSearchSerializerStruct": {
             "type": "object",
-            "required": [
-                "schema"
-            ],
             "properties": {
-                "schema": {
+                "fields": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logic.SchemaFieldOccurrenceSerializerStruct"
+                    }
+                },
+                "name": {
                     "type": "string"
+                },
+                "types": {
+                    "description": "Types are the distinct types of the found fields",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "input_contracts.SignInSignUpApiInputContract": {
+        "logic.SchemaFieldSerializerStruct": {
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
             "properties": {
-                "email": {
+                "description": {
                     "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "minLength": 8
+                "enum": {
+                    "type": "array",
+                    "items": {}
+                },
+                "format": {
+                    "type": "string"
+                },
+                "message_type": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "required": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "type": "string"
                 }
             }
         },
-        "logic.AppDBSerializerStruct": {
+        "logic.SchemaImpactBreakSerializerStruct": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "app_id": {
                     "type": "string"
                 },
-                "created_by_user_id": {
+                "app_name": {
                     "type": "string"
                 },
-                "created_by_user_name": {
+                "direction": {
                     "type": "string"
                 },
-                "description": {
+                "message_id": {
                     "type": "string"
                 },
-                "id": {
+                "message_name": {
                     "type": "string"
                 },
-                "name": {
+                "resource_id": {
                     "type": "string"
                 },
-                "project_id": {
+                "resource_name": {
                     "type": "string"
                 },
-                "status": {
-                    "type": "string"
+                "version": {
+                    "type": "integer"
                 },
-                "updated_at": {
-                    "type": "string"
+                "violations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logic.CompatibilityViolation"
+                    }
                 }
             }
         },
-        "logic.AppUsageMatrixReader": {
+        "logic.SchemaImpactMessageSerializerStruct": {
             "type": "object",
             "properties": {
-                "message": {
-                    "$ref": "#/definitions/logic.MessageDBSerializerStruct"
+                "id": {
+                    "type": "string"
                 },
-                "resource": {
-                    "$ref": "#/definitions/logic.ResourceDBSerializerStruct"
+                "name": {
+                    "type": "string"
                 },
-                "server": {
-                    "$ref": "#/definitions/logic.ServerDBSerializerStruct"
+                "schema_message_type": {
+                    "type": "string"
+                },
+                "usages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logic.SchemaImpactUsageSerializerStruct"
+                    }
                 }
             }
         },
-        "logic.AppUsageMatrixResponse": {
+        "logic.SchemaImpactProposalSerializerStruct": {
             "type": "object",
             "properties": {
-                "app_id": {
-                    "type": "string"
-                },
-                "receives": {
+                "breaks": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/logic.AppUsageMatrixReader"
+                        "$ref": "#/definitions/logic.SchemaImpactBreakSerializerStruct"
                     }
                 },
-                "sends": {
+                "compatible": {
+                    "description": "Compatible tells if the proposed version satisfies the compatibility mode of the schema",
+                    "type": "boolean"
+                },
+                "violations": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/logic.AppUsageMatrixReader"
+                        "$ref": "#/definitions/logic.CompatibilityViolation"
                     }
                 }
             }
         },
-        "logic.MessageDBSerializerStruct": {
+        "logic.SchemaImpactResourceSerializerStruct": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "id": {
                     "type": "string"
                 },
-                "created_by_id": {
+                "mode": {
                     "type": "string"
                 },
-                "created_by_name": {
+                "name": {
                     "type": "string"
                 },
-                "description": {
+                "resource_type": {
                     "type": "string"
                 },
-                "id": {
+                "server_id": {
                     "type": "string"
                 },
-                "name": {
+                "server_name": {
                     "type": "string"
-                },
-                "project_id": {
+                }
+            }
+        },
+        "logic.SchemaImpactSerializerStruct": {
+            "type": "object",
+            "properties": {
+                "compatibility": {
                     "type": "string"
                 },
+                "current_version": {
+                    "type": "integer"
+                },
+                "proposed_change": {
+                    "$ref": "#/definitions/logic.SchemaImpactProposalSerializerStruct"
+                },
+                "resources": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logic.SchemaImpactResourceSerializerStruct"
+                    }
+                },
                 "schema_id": {
                     "type": "string"
                 },
-                "schema_version": {
-                    "type": "integer"
-                },
-                "status": {
+                "schema_name": {
                     "type": "string"
+                },
+                "versions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logic.SchemaImpactVersionSerializerStruct"
+                    }
                 }
             }
         },
-        "logic.ProjectDBSerializerStruct": {
+        "logic.SchemaImpactUsageSerializerStruct": {
             "type": "object",
             "properties": {
-                "created_by_id": {
+                "app_id": {
                     "type": "string"
                 },
-                "created_by_name": {
+                "app_name": {
                     "type": "string"
                 },
-                "created_by_type": {
+                "direction": {
                     "type": "string"
                 },
-                "description": {
+                "resource_id": {
                     "type": "string"
                 },
-                "id": {
+                "resource_name": {
                     "type": "string"
                 },
-                "is_private": {
-                    "type": "boolean"
-                },
-                "name": {
+                "server_id": {
                     "type": "string"
                 },
-                "status": {
+                "server_name": {
                     "type": "string"
                 }
             }
         },
-        "logic.ResourceBindingDBSerializerStruct": {
+        "logic.SchemaImpactVersionSerializerStruct": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
+                "messages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logic.SchemaImpactMessageSerializerStruct"
+                    }
                 },
-                "id": {
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "logic.SchemaInUseErrorSerializerStruct": {
+            "type": "object",
+            "properties": {
+                "error": {
                     "type": "string"
                 },
-                "source_resource_id": {
-                    "type": "string"
+                "messages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logic.SchemaUsageMessageStruct"
+                    }
                 },
-                "target_resource_id": {
+                "referenced_by": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logic.SchemaReferenceSerializerStruct"
+                    }
+                }
+            }
+        },
+        "logic.SchemaLintErrorSerializerStruct": {
+            "type": "object",
+            "properties": {
+                "error": {
                     "type": "string"
                 },
-                "updated_at": {
-                    "type": "string"
+                "findings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/jsonschemalint.Finding"
+                    }
                 }
             }
         },
-        "logic.ResourceDBSerializerStruct": {
+        "logic.SchemaLintResultSerializerStruct": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
+                "findings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/jsonschemalint.Finding"
+                    }
                 },
-                "created_by_user_id": {
+                "schema_id": {
                     "type": "string"
                 },
-                "created_by_user_name": {
+                "schema_name": {
                     "type": "string"
                 },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "logic.SchemaLintRuleSerializerStruct": {
+            "type": "object",
+            "properties": {
                 "description": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "string"
-                },
-                "mode": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "project_id": {
-                    "type": "string"
+                "enabled": {
+                    "type": "boolean"
                 },
-                "resource_type": {
+                "option": {
                     "type": "string"
                 },
-                "server_id": {
-                    "type": "string"
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "status": {
+                "rule": {
                     "type": "string"
                 },
-                "updated_at": {
+                "severity": {
                     "type": "string"
                 }
             }
         },
-        "logic.SchemaDBSerializerStruct": {
+        "logic.SchemaReferenceSerializerStruct": {
             "type": "object",
             "properties": {
-                "created_by_id": {
+                "referenced_schema_id": {
                     "type": "string"
                 },
-                "created_by_name": {
-                    "type": "string"
-                },
-                "created_by_type": {
+                "referenced_schema_name": {
                     "type": "string"
                 },
-                "description": {
-                    "type": "string"
+                "referenced_schema_version": {
+                    "type": "integer"
                 },
-                "id": {
+                "schema_id": {
                     "type": "string"
                 },
-                "name": {
+                "schema_name": {
                     "type": "string"
                 },
-                "project_id": {
-                    "type": "string"
+                "schema_version": {
+                    "type": "integer"
                 },
-                "schema": {
+                "uri": {
                     "type": "string"
+                }
+            }
+        },
+        "logic.SchemaReferencesSerializerStruct": {
+            "type": "object",
+            "properties": {
+                "referenced_by": {
+                    "description": "ReferencedBy are versions of other schemas which reference versions of this schema",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logic.SchemaReferenceSerializerStruct"
+                    }
                 },
-                "status": {
-                    "type": "string"
+                "references": {
+                    "description": "References are versions of other schemas referenced by versions of this schema",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logic.SchemaReferenceSerializerStruct"
+                    }
                 },
-                "type": {
+                "schema_id": {
                     "type": "string"
-                },
-                "version": {
-                    "type": "integer"
                 }
             }
         },
-        "logic.SchemaEditDBSerializerStruct": {
+        "logic.SchemaRevertSerializerStruct": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
+                "moved_messages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logic.SchemaUsageMessageStruct"
+                    }
                 },
-                "created_by_name": {
-                    "type": "string"
+                "reverted_from": {
+                    "type": "integer"
+                },
+                "reverted_to": {
+                    "type": "integer"
                 },
                 "schema": {
-                    "type": "string"
+                    "$ref": "#/definitions/logic.SchemaDBSerializerStruct"
                 },
-                "schema_id": {
+                "skipped_messages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logic.SchemaUsageMessageStruct"
+                    }
+                }
+            }
+        },
+        "logic.SchemaUsageMessageStruct": {
+            "type": "object",
+            "properties": {
+                "id": {
                     "type": "string"
                 },
-                "user_id": {
+                "name": {
                     "type": "string"
                 },
-                "version": {
+                "schema_version": {
                     "type": "integer"
                 }
             }
@@ -2222,6 +6977,17 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "schemaconvert.Loss": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
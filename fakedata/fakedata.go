@@ -0,0 +1,266 @@
+// Package fakedata produces realistic-looking values for example payloads: names, emails,
+// identifiers, timestamps, strings matching regular expressions etc. All values are drawn
+// from the random source of the generator, so generators created with the same seed
+// produce the same values.
+//
+//	data := fakedata.New(42)
+//	data.Email()                       // e.g. "olivia.smith@example.com"
+//	data.ForName("created_at")         // e.g. "2024-03-18T09:41:27Z"
+//	data.Pattern(`^[A-Z]{3}-\d{4}$`)   // e.g. "QKD-5820"
+package fakedata
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+var (
+	firstNames = []string{"Olivia", "Liam", "Emma", "Noah", "Amelia", "Oliver", "Sophia", "Elijah", "Mia", "Lucas",
+		"Isabella", "Mateo", "Ava", "Leo", "Hannah", "Jonas", "Yuki", "Arjun", "Fatima", "Diego"}
+	lastNames = []string{"Smith", "Johnson", "Garcia", "Brown", "Miller", "Davis", "Martinez", "Wilson", "Anderson",
+		"Taylor", "Thomas", "Moore", "Jackson", "Martin", "Lee", "Schmidt", "Rossi", "Novak", "Kowalski", "Tanaka"}
+	cities    = []string{"London", "Berlin", "Lisbon", "Warsaw", "Toronto", "Austin", "Osaka", "Melbourne", "Nairobi", "Oslo"}
+	countries = []string{"GB", "DE", "PT", "PL", "CA", "US", "JP", "AU", "KE", "NO"}
+	streets   = []string{"High Street", "Station Road", "Main Street", "Park Avenue", "Church Lane", "Mill Road"}
+	domains   = []string{"example.com", "example.org", "example.net"}
+	words     = []string{"alpha", "bravo", "cargo", "delta", "event", "order", "stream", "signal", "ledger", "parcel",
+		"market", "river", "summit", "harbor", "vector", "lumen", "pixel", "quartz", "nova", "ember"}
+	currencies = []string{"USD", "EUR", "GBP", "JPY", "PLN", "CAD"}
+)
+
+// Generator produces fake values from its random source
+type Generator struct {
+	random *rand.Rand
+}
+
+// New creates a generator with a deterministic random source
+func New(seed int64) *Generator {
+	return &Generator{random: rand.New(rand.NewSource(seed))}
+}
+
+// Intn returns a number in [0, n)
+func (data *Generator) Intn(n int) int {
+	if n <= 0 {
+		return 0
+	}
+	return data.random.Intn(n)
+}
+
+// IntBetween returns a number in [min, max]
+func (data *Generator) IntBetween(min int64, max int64) int64 {
+	if max <= min {
+		return min
+	}
+	span := uint64(max - min)
+	if span >= 1<<63-1 {
+		return min + data.random.Int63()
+	}
+	return min + data.random.Int63n(int64(span)+1)
+}
+
+// FloatBetween returns a number in [min, max) rounded to two decimal places when possible
+func (data *Generator) FloatBetween(min float64, max float64) float64 {
+	if max <= min {
+		return min
+	}
+	value := min + data.random.Float64()*(max-min)
+	rounded := float64(int64(value*100)) / 100
+	if rounded >= min && rounded < max {
+		return rounded
+	}
+	return value
+}
+
+// Bool returns true with the probability of one half
+func (data *Generator) Bool() bool {
+	return data.random.Intn(2) == 1
+}
+
+// Chance returns true with the given probability
+func (data *Generator) Chance(probability float64) bool {
+	return data.random.Float64() < probability
+}
+
+func (data *Generator) pick(values []string) string {
+	return values[data.random.Intn(len(values))]
+}
+
+func (data *Generator) FirstName() string { return data.pick(firstNames) }
+
+func (data *Generator) LastName() string { return data.pick(lastNames) }
+
+func (data *Generator) FullName() string { return data.FirstName() + " " + data.LastName() }
+
+func (data *Generator) City() string { return data.pick(cities) }
+
+// Country returns an ISO 3166-1 alpha-2 code
+func (data *Generator) Country() string { return data.pick(countries) }
+
+// Currency returns an ISO 4217 code
+func (data *Generator) Currency() string { return data.pick(currencies) }
+
+func (data *Generator) Word() string { return data.pick(words) }
+
+func (data *Generator) Street() string {
+	return fmt.Sprintf("%d %s", 1+data.random.Intn(250), data.pick(streets))
+}
+
+// Words returns count words separated by spaces
+func (data *Generator) Words(count int) string {
+	parts := make([]string, count)
+	for index := range parts {
+		parts[index] = data.Word()
+	}
+	return strings.Join(parts, " ")
+}
+
+func (data *Generator) Email() string {
+	return strings.ToLower(data.FirstName()+"."+data.LastName()) + "@" + data.pick(domains)
+}
+
+func (data *Generator) Phone() string {
+	return fmt.Sprintf("+1555%07d", data.random.Intn(10000000))
+}
+
+// UUID returns a random version 4 UUID
+func (data *Generator) UUID() string {
+	var bytes [16]byte
+	for index := range bytes {
+		bytes[index] = byte(data.random.Intn(256))
+	}
+	bytes[6] = bytes[6]&0x0f | 0x40
+	bytes[8] = bytes[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", bytes[0:4], bytes[4:6], bytes[6:8], bytes[8:10], bytes[10:16])
+}
+
+// Time returns a moment within a year before 2025-01-01, so that examples don't depend on the current time
+func (data *Generator) Time() time.Time {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	return base.Add(-time.Duration(data.random.Int63n(int64(365*24*time.Hour/time.Second))) * time.Second)
+}
+
+func (data *Generator) DateTime() string { return data.Time().Format(time.RFC3339) }
+
+func (data *Generator) Date() string { return data.Time().Format("2006-01-02") }
+
+func (data *Generator) TimeOfDay() string { return data.Time().Format("15:04:05Z") }
+
+// Duration returns an ISO 8601 duration
+func (data *Generator) Duration() string {
+	return fmt.Sprintf("PT%dH%dM", data.random.Intn(24), data.random.Intn(60))
+}
+
+func (data *Generator) Hostname() string {
+	return data.Word() + "." + data.pick(domains)
+}
+
+func (data *Generator) URI() string {
+	return "https://" + data.Hostname() + "/" + data.Word()
+}
+
+func (data *Generator) IPv4() string {
+	return fmt.Sprintf("192.0.2.%d", 1+data.random.Intn(254))
+}
+
+func (data *Generator) IPv6() string {
+	return fmt.Sprintf("2001:db8::%x", 1+data.random.Intn(0xfffe))
+}
+
+// Format returns a value of the JSON schema format, ok is false for unknown formats
+func (data *Generator) Format(format string) (value string, ok bool) {
+	switch format {
+	case "uuid":
+		return data.UUID(), true
+	case "date-time":
+		return data.DateTime(), true
+	case "date":
+		return data.Date(), true
+	case "time":
+		return data.TimeOfDay(), true
+	case "duration":
+		return data.Duration(), true
+	case "email", "idn-email":
+		return data.Email(), true
+	case "hostname", "idn-hostname":
+		return data.Hostname(), true
+	case "uri", "iri", "uri-reference", "iri-reference", "url":
+		return data.URI(), true
+	case "ipv4":
+		return data.IPv4(), true
+	case "ipv6":
+		return data.IPv6(), true
+	}
+	return "", false
+}
+
+// ForName returns a string which suits a field with the name, e.g. an email for "contact_email".
+// ok is false when the name doesn't hint at any kind of value.
+func (data *Generator) ForName(name string) (value string, ok bool) {
+	normalized := strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(name))
+	switch {
+	case strings.Contains(normalized, "email"):
+		return data.Email(), true
+	case normalized == "firstname" || normalized == "givenname":
+		return data.FirstName(), true
+	case normalized == "lastname" || normalized == "surname" || normalized == "familyname":
+		return data.LastName(), true
+	case normalized == "name" || normalized == "fullname" || normalized == "displayname" || normalized == "username":
+		return data.FullName(), true
+	case normalized == "id" || normalized == "uuid" || strings.HasSuffix(normalized, "uuid") ||
+		strings.HasSuffix(strings.ToLower(name), "_id") || strings.HasSuffix(name, "Id"):
+		return data.UUID(), true
+	case strings.HasSuffix(strings.ToLower(name), "_at") || strings.HasSuffix(name, "At") ||
+		strings.HasSuffix(normalized, "timestamp") || strings.HasSuffix(normalized, "time"):
+		return data.DateTime(), true
+	case strings.HasSuffix(normalized, "date") || normalized == "birthday":
+		return data.Date(), true
+	case strings.Contains(normalized, "phone"):
+		return data.Phone(), true
+	case normalized == "city":
+		return data.City(), true
+	case normalized == "country" || normalized == "countrycode":
+		return data.Country(), true
+	case normalized == "currency" || normalized == "currencycode":
+		return data.Currency(), true
+	case normalized == "street" || normalized == "address" || normalized == "addressline":
+		return data.Street(), true
+	case strings.HasSuffix(normalized, "url") || strings.HasSuffix(normalized, "uri") || normalized == "website":
+		return data.URI(), true
+	case normalized == "description" || normalized == "comment" || normalized == "note" || normalized == "reason":
+		sentence := data.Words(4)
+		return strings.ToUpper(sentence[:1]) + sentence[1:], true
+	}
+	return "", false
+}
+
+// String returns a string with the length in [minLength, maxLength], maxLength below zero means no limit
+func (data *Generator) String(minLength int, maxLength int) string {
+	text := data.Word()
+	for len(text) < minLength {
+		text += " " + data.Word()
+	}
+	if maxLength >= 0 && len(text) > maxLength {
+		text = text[:maxLength]
+		if minLength < maxLength {
+			text = strings.TrimRight(text, " ")
+		}
+		for len(text) < minLength {
+			text += "x"
+		}
+	}
+	return text
+}
+
+// Singular turns names of lists into names of their items, e.g. "emails" into "email",
+// so that items get values which suit the name of the list
+func Singular(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies"):
+		return strings.TrimSuffix(name, "ies") + "y"
+	case strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss"):
+		return strings.TrimSuffix(name, "s")
+	}
+	return name
+}
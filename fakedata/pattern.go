@@ -0,0 +1,94 @@
+package fakedata
+
+import (
+	"regexp/syntax"
+	"strings"
+	"unicode"
+)
+
+// maxPatternRepeat limits unbounded repetitions (*, +, {n,}) of patterns
+const maxPatternRepeat = 5
+
+// Pattern returns a string matching the regular expression. Patterns are not anchored the same way
+// as in JSON schemas, anchors are ignored and the generated string matches the whole pattern.
+func (data *Generator) Pattern(pattern string) (string, error) {
+	parsed, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", err
+	}
+	var builder strings.Builder
+	data.writePattern(&builder, parsed.Simplify())
+	return builder.String(), nil
+}
+
+func (data *Generator) writePattern(builder *strings.Builder, node *syntax.Regexp) {
+	switch node.Op {
+	case syntax.OpLiteral:
+		for _, char := range node.Rune {
+			if node.Flags&syntax.FoldCase != 0 && data.Bool() {
+				char = unicode.SimpleFold(char)
+			}
+			builder.WriteRune(char)
+		}
+	case syntax.OpCharClass:
+		builder.WriteRune(data.charFromClass(node.Rune))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		builder.WriteRune(rune('a' + data.Intn(26)))
+	case syntax.OpCapture:
+		for _, sub := range node.Sub {
+			data.writePattern(builder, sub)
+		}
+	case syntax.OpConcat:
+		for _, sub := range node.Sub {
+			data.writePattern(builder, sub)
+		}
+	case syntax.OpAlternate:
+		data.writePattern(builder, node.Sub[data.Intn(len(node.Sub))])
+	case syntax.OpStar:
+		data.repeatPattern(builder, node.Sub[0], 0, maxPatternRepeat)
+	case syntax.OpPlus:
+		data.repeatPattern(builder, node.Sub[0], 1, maxPatternRepeat)
+	case syntax.OpQuest:
+		data.repeatPattern(builder, node.Sub[0], 0, 1)
+	case syntax.OpRepeat:
+		max := node.Max
+		if max < 0 {
+			max = node.Min + maxPatternRepeat
+		}
+		data.repeatPattern(builder, node.Sub[0], node.Min, max)
+	}
+	// Anchors, word boundaries and empty matches don't produce characters
+}
+
+func (data *Generator) repeatPattern(builder *strings.Builder, node *syntax.Regexp, min int, max int) {
+	count := min + data.Intn(max-min+1)
+	for index := 0; index < count; index++ {
+		data.writePattern(builder, node)
+	}
+}
+
+// charFromClass picks a character from ranges of a class, printable ASCII characters are preferred
+func (data *Generator) charFromClass(ranges []rune) rune {
+	var printable []rune
+	for index := 0; index+1 < len(ranges); index += 2 {
+		low, high := ranges[index], ranges[index+1]
+		if low < ' ' {
+			low = ' '
+		}
+		if high > '~' {
+			high = '~'
+		}
+		if low <= high {
+			printable = append(printable, low, high)
+		}
+	}
+	if len(printable) == 0 {
+		printable = ranges
+	}
+	if len(printable) == 0 {
+		return 'x'
+	}
+	pair := data.Intn(len(printable)/2) * 2
+	low, high := printable[pair], printable[pair+1]
+	return low + rune(data.Intn(int(high-low)+1))
+}
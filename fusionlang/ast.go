@@ -0,0 +1,151 @@
+// Package fusionlang implements Fusionlang, the human-readable language for defining
+// project architectures. Fusionlang sources are compiled into the same document model
+// the YAML importer works with, so both formats share validation and import logic.
+//
+//	version 1
+//
+//	server kafka_server kafka {
+//	    description "Main Kafka server"
+//	    resource users_topic topic readwrite
+//	}
+//
+//	schema user jsonschema {
+//	    file "schemas/user.json"
+//	    compatibility backward
+//	}
+//
+//	message user_created {
+//	    schema user
+//	}
+//
+//	app UsersService {
+//	    sends user_created to "async+kafka://kafka_server@readwrite/topic/users_topic"
+//	}
+package fusionlang
+
+// Position is a 1-based location in the source
+type Position struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Comment is a single "//" or "#" comment, Text includes the comment marker
+type Comment struct {
+	Position
+	Text string
+}
+
+// Comments attached to a declaration. Leading comments are placed on the lines right before
+// the declaration, trailing comments are placed on the same line after it.
+type Comments struct {
+	Leading  []Comment
+	Trailing []Comment
+}
+
+func (comments *Comments) comments() *Comments { return comments }
+
+// Value is a name, number or string literal. Kind is kept so that the formatter
+// can print the value the way it was written.
+type Value struct {
+	Position
+	Kind TokenKind
+	Text string
+}
+
+// Block is an optional "{ ... }" part of a declaration.
+// Dangling comments are placed after the last item, right before the closing brace.
+type Block struct {
+	Present  bool
+	Items    []Item
+	Dangling []Comment
+}
+
+// Item is anything that can be placed at the top level or inside a block
+type Item interface {
+	comments() *Comments
+	position() Position
+}
+
+// File is a parsed Fusionlang source
+type File struct {
+	Items    []Item
+	Trailing []Comment
+}
+
+// PropertyItem is a "keyword value" pair, e.g. `version 1`, `include "x.fusion"` or `description "..."`
+type PropertyItem struct {
+	Comments
+	Position
+	Keyword string
+	Value   Value
+}
+
+// ServerDecl is `server <name> <protocol> { ... }`
+type ServerDecl struct {
+	Comments
+	Position
+	Name     Value
+	Protocol Value
+	Block    Block
+}
+
+// ResourceDecl is `resource <name> <type> <mode> { ... }` inside a server
+type ResourceDecl struct {
+	Comments
+	Position
+	Name  Value
+	Type  Value
+	Mode  Value
+	Block Block
+}
+
+// BindDecl is `bind <source> -> <target>` inside a server
+type BindDecl struct {
+	Comments
+	Position
+	Source Value
+	Target Value
+}
+
+// SchemaDecl is `schema <name> <type> { ... }`
+type SchemaDecl struct {
+	Comments
+	Position
+	Name  Value
+	Type  Value
+	Block Block
+}
+
+// MessageDecl is `message <name> { ... }`
+type MessageDecl struct {
+	Comments
+	Position
+	Name  Value
+	Block Block
+}
+
+// AppDecl is `app <name> { ... }`
+type AppDecl struct {
+	Comments
+	Position
+	Name  Value
+	Block Block
+}
+
+// FlowItem is `sends <message> to <resource>` or `receives <message> from <resource>` inside an app
+type FlowItem struct {
+	Comments
+	Position
+	Direction string
+	Message   Value
+	Resource  Value
+}
+
+func (item *PropertyItem) position() Position { return item.Position }
+func (item *ServerDecl) position() Position   { return item.Position }
+func (item *ResourceDecl) position() Position { return item.Position }
+func (item *BindDecl) position() Position     { return item.Position }
+func (item *SchemaDecl) position() Position   { return item.Position }
+func (item *MessageDecl) position() Position  { return item.Position }
+func (item *AppDecl) position() Position      { return item.Position }
+func (item *FlowItem) position() Position     { return item.Position }
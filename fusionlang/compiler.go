@@ -0,0 +1,245 @@
+package fusionlang
+
+import (
+	"fmt"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Compile parses the source and converts it into the YAML document model used by the
+// architecture importer. Every node keeps the position of the Fusionlang token it was created
+// from, so import validation issues point at the right place of the .fusion file.
+func Compile(source string) (*yaml.Node, error) {
+	file, err := Parse(source)
+	if err != nil {
+		return nil, err
+	}
+	return CompileFile(file)
+}
+
+// CompileFile converts a parsed file into the YAML document model
+func CompileFile(file *File) (*yaml.Node, error) {
+	compiler := &compiler{root: mappingNode(Position{Line: 1, Column: 1})}
+	sections := make(map[string]*yaml.Node)
+	section := func(name string, position Position) *yaml.Node {
+		if sections[name] == nil {
+			sections[name] = sequenceNode(position)
+			addMappingValue(compiler.root, name, position, sections[name])
+		}
+		return sections[name]
+	}
+
+	for _, item := range file.Items {
+		switch item := item.(type) {
+		case *PropertyItem:
+			switch item.Keyword {
+			case "version":
+				if compiler.checkUnique(compiler.root, item) {
+					addMappingValue(compiler.root, "version", item.Position, scalarNode(item.Value, "!!int"))
+				}
+			case "include":
+				includes := section("include", item.Position)
+				includes.Content = append(includes.Content, scalarNode(item.Value, "!!str"))
+			}
+		case *ServerDecl:
+			servers := section("servers", item.Position)
+			servers.Content = append(servers.Content, compiler.compileServer(item))
+		case *SchemaDecl:
+			schemas := section("schemas", item.Position)
+			schemas.Content = append(schemas.Content, compiler.compileSchema(item))
+		case *MessageDecl:
+			messages := section("messages", item.Position)
+			messages.Content = append(messages.Content, compiler.compileMessage(item))
+		case *AppDecl:
+			apps := section("apps", item.Position)
+			apps.Content = append(apps.Content, compiler.compileApp(item))
+		}
+	}
+
+	if len(compiler.errors) > 0 {
+		sort.SliceStable(compiler.errors, func(i, j int) bool {
+			return compiler.errors[i].Line < compiler.errors[j].Line
+		})
+		return nil, compiler.errors
+	}
+	return &yaml.Node{Kind: yaml.DocumentNode, Line: 1, Column: 1, Content: []*yaml.Node{compiler.root}}, nil
+}
+
+type compiler struct {
+	root   *yaml.Node
+	errors ErrorList
+}
+
+func (compiler *compiler) compileServer(server *ServerDecl) *yaml.Node {
+	node := mappingNode(server.Position)
+	addMappingValue(node, "name", server.Name.Position, scalarNode(server.Name, "!!str"))
+	addMappingValue(node, "type", server.Protocol.Position, scalarNode(server.Protocol, "!!str"))
+
+	// Resources are always present, the importer treats servers without resources as a warning
+	resources := sequenceNode(server.Position)
+	var binds *yaml.Node
+	for _, item := range server.Block.Items {
+		switch item := item.(type) {
+		case *PropertyItem:
+			compiler.compileProperty(node, item)
+		case *ResourceDecl:
+			resource := mappingNode(item.Position)
+			addMappingValue(resource, "name", item.Name.Position, scalarNode(item.Name, "!!str"))
+			addMappingValue(resource, "type", item.Type.Position, scalarNode(item.Type, "!!str"))
+			addMappingValue(resource, "mode", item.Mode.Position, scalarNode(item.Mode, "!!str"))
+			for _, child := range item.Block.Items {
+				if property, ok := child.(*PropertyItem); ok {
+					compiler.compileProperty(resource, property)
+				}
+			}
+			resources.Content = append(resources.Content, resource)
+		case *BindDecl:
+			if binds == nil {
+				binds = sequenceNode(item.Position)
+			}
+			bind := mappingNode(item.Position)
+			addMappingValue(bind, "source", item.Source.Position, scalarNode(item.Source, "!!str"))
+			addMappingValue(bind, "target", item.Target.Position, scalarNode(item.Target, "!!str"))
+			binds.Content = append(binds.Content, bind)
+		}
+	}
+
+	addMappingValue(node, "resources", server.Position, resources)
+	if binds != nil {
+		addMappingValue(node, "binds", Position{Line: binds.Line, Column: binds.Column}, binds)
+	}
+	return node
+}
+
+func (compiler *compiler) compileSchema(schema *SchemaDecl) *yaml.Node {
+	node := mappingNode(schema.Position)
+	addMappingValue(node, "name", schema.Name.Position, scalarNode(schema.Name, "!!str"))
+	addMappingValue(node, "type", schema.Type.Position, scalarNode(schema.Type, "!!str"))
+	for _, item := range schema.Block.Items {
+		if property, ok := item.(*PropertyItem); ok {
+			compiler.compileProperty(node, property)
+		}
+	}
+	return node
+}
+
+func (compiler *compiler) compileMessage(message *MessageDecl) *yaml.Node {
+	node := mappingNode(message.Position)
+	addMappingValue(node, "name", message.Name.Position, scalarNode(message.Name, "!!str"))
+	var reference *yaml.Node
+	var messageType *PropertyItem
+	for _, item := range message.Block.Items {
+		property, ok := item.(*PropertyItem)
+		if !ok {
+			continue
+		}
+		switch property.Keyword {
+		case "schema":
+			if compiler.checkUnique(node, property) {
+				reference = mappingNode(property.Value.Position)
+				addMappingValue(reference, "name", property.Value.Position, scalarNode(property.Value, "!!str"))
+				addMappingValue(node, "schema", property.Position, reference)
+			}
+		case "message_type":
+			// The message type is a part of the schema reference: schema: {name: ..., message_type: ...}
+			if messageType != nil {
+				compiler.errors = append(compiler.errors, &Error{
+					Position: property.Position,
+					Message: fmt.Sprintf("message_type is already set at line %d, column %d",
+						messageType.Position.Line, messageType.Position.Column),
+				})
+				continue
+			}
+			messageType = property
+		default:
+			compiler.compileProperty(node, property)
+		}
+	}
+	if messageType != nil {
+		if reference == nil {
+			compiler.errors = append(compiler.errors, &Error{
+				Position: messageType.Position,
+				Message:  "message_type requires the schema of the message",
+			})
+		} else {
+			addMappingValue(reference, "message_type", messageType.Position, scalarNode(messageType.Value, "!!str"))
+		}
+	}
+	return node
+}
+
+func (compiler *compiler) compileApp(app *AppDecl) *yaml.Node {
+	node := mappingNode(app.Position)
+	addMappingValue(node, "name", app.Name.Position, scalarNode(app.Name, "!!str"))
+	flows := make(map[string]*yaml.Node)
+	for _, item := range app.Block.Items {
+		switch item := item.(type) {
+		case *PropertyItem:
+			compiler.compileProperty(node, item)
+		case *FlowItem:
+			if flows[item.Direction] == nil {
+				flows[item.Direction] = sequenceNode(item.Position)
+				addMappingValue(node, item.Direction, item.Position, flows[item.Direction])
+			}
+			flow := mappingNode(item.Position)
+			addMappingValue(flow, "message", item.Message.Position, scalarNode(item.Message, "!!str"))
+			addMappingValue(flow, "resource", item.Resource.Position, scalarNode(item.Resource, "!!str"))
+			flows[item.Direction].Content = append(flows[item.Direction].Content, flow)
+		}
+	}
+	return node
+}
+
+// compileProperty adds a property to a declaration. "content" is the Fusionlang name
+// of the inline schema which is called "schema" in YAML.
+func (compiler *compiler) compileProperty(node *yaml.Node, property *PropertyItem) {
+	if !compiler.checkUnique(node, property) {
+		return
+	}
+	key := property.Keyword
+	tag := "!!str"
+	switch key {
+	case "content":
+		key = "schema"
+	case "version":
+		tag = "!!int"
+	}
+	addMappingValue(node, key, property.Position, scalarNode(property.Value, tag))
+}
+
+// checkUnique reports properties which are set more than once in the same declaration
+func (compiler *compiler) checkUnique(node *yaml.Node, property *PropertyItem) bool {
+	key := property.Keyword
+	if key == "content" {
+		key = "schema"
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			compiler.errors = append(compiler.errors, &Error{
+				Position: property.Position,
+				Message: fmt.Sprintf("%s is already set at line %d, column %d",
+					property.Keyword, node.Content[i].Line, node.Content[i].Column),
+			})
+			return false
+		}
+	}
+	return true
+}
+
+func mappingNode(position Position) *yaml.Node {
+	return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Line: position.Line, Column: position.Column}
+}
+
+func sequenceNode(position Position) *yaml.Node {
+	return &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Line: position.Line, Column: position.Column}
+}
+
+func scalarNode(value Value, tag string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value.Text, Line: value.Line, Column: value.Column}
+}
+
+func addMappingValue(mapping *yaml.Node, key string, keyPosition Position, value *yaml.Node) {
+	keyNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key, Line: keyPosition.Line, Column: keyPosition.Column}
+	mapping.Content = append(mapping.Content, keyNode, value)
+}
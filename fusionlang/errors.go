@@ -0,0 +1,70 @@
+package fusionlang
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Error is a syntax error with its position in the source
+type Error struct {
+	Position
+	Message string `json:"message"`
+}
+
+func (err *Error) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", err.Line, err.Column, err.Message)
+}
+
+// ErrorList is a list of syntax errors ordered by position
+type ErrorList []*Error
+
+func (list ErrorList) Error() string {
+	messages := make([]string, 0, len(list))
+	for _, err := range list {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Err returns nil for an empty list, so that callers can use the usual err != nil check
+func (list ErrorList) Err() error {
+	if len(list) == 0 {
+		return nil
+	}
+	return list
+}
+
+// suggest returns the candidate closest to the misspelled word, or an empty string
+// if nothing is close enough to be a plausible typo
+func suggest(word string, candidates []string) string {
+	best := ""
+	bestDistance := len(word)/3 + 1
+	for _, candidate := range candidates {
+		distance := levenshteinDistance(strings.ToLower(word), candidate)
+		if distance < bestDistance || (distance == bestDistance && best == "" && distance <= 2) {
+			best = candidate
+			bestDistance = distance
+		}
+	}
+	return best
+}
+
+func levenshteinDistance(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,124 @@
+package fusionlang
+
+import (
+	"strconv"
+	"strings"
+)
+
+const formatterIndent = "    "
+
+// Format parses the source and prints it in the canonical layout: four-space indentation,
+// one declaration per line and a blank line between top-level declarations.
+// Comments are preserved. Sources with syntax errors are not formatted.
+func Format(source string) (string, error) {
+	file, err := Parse(source)
+	if err != nil {
+		return "", err
+	}
+	return FormatFile(file), nil
+}
+
+// FormatFile prints a parsed file in the canonical layout
+func FormatFile(file *File) string {
+	printer := &printer{}
+	for index, item := range file.Items {
+		if index > 0 && needsBlankLine(file.Items[index-1], item) {
+			printer.builder.WriteString("\n")
+		}
+		printer.printItem(item, 0)
+	}
+	if len(file.Trailing) > 0 {
+		if len(file.Items) > 0 {
+			printer.builder.WriteString("\n")
+		}
+		printer.printComments(file.Trailing, 0)
+	}
+	return printer.builder.String()
+}
+
+// needsBlankLine keeps consecutive "version" and "include" lines together,
+// all other top-level declarations are separated by a blank line
+func needsBlankLine(previous Item, current Item) bool {
+	_, previousIsProperty := previous.(*PropertyItem)
+	_, currentIsProperty := current.(*PropertyItem)
+	return !previousIsProperty || !currentIsProperty
+}
+
+type printer struct {
+	builder strings.Builder
+}
+
+func (printer *printer) printItem(item Item, depth int) {
+	comments := item.comments()
+	printer.printComments(comments.Leading, depth)
+	printer.builder.WriteString(strings.Repeat(formatterIndent, depth))
+
+	var block *Block
+	switch item := item.(type) {
+	case *PropertyItem:
+		printer.printWords(item.Keyword, formatValue(item.Value))
+	case *ServerDecl:
+		printer.printWords("server", formatValue(item.Name), formatValue(item.Protocol))
+		block = &item.Block
+	case *ResourceDecl:
+		printer.printWords("resource", formatValue(item.Name), formatValue(item.Type), formatValue(item.Mode))
+		block = &item.Block
+	case *BindDecl:
+		printer.printWords("bind", formatValue(item.Source), "->", formatValue(item.Target))
+	case *SchemaDecl:
+		printer.printWords("schema", formatValue(item.Name), formatValue(item.Type))
+		block = &item.Block
+	case *MessageDecl:
+		printer.printWords("message", formatValue(item.Name))
+		block = &item.Block
+	case *AppDecl:
+		printer.printWords("app", formatValue(item.Name))
+		block = &item.Block
+	case *FlowItem:
+		preposition := "to"
+		if item.Direction == "receives" {
+			preposition = "from"
+		}
+		printer.printWords(item.Direction, formatValue(item.Message), preposition, formatValue(item.Resource))
+	}
+
+	// Empty blocks are dropped unless they hold comments
+	if block != nil && (len(block.Items) > 0 || len(block.Dangling) > 0) {
+		printer.builder.WriteString(" {\n")
+		for _, child := range block.Items {
+			printer.printItem(child, depth+1)
+		}
+		printer.printComments(block.Dangling, depth+1)
+		printer.builder.WriteString(strings.Repeat(formatterIndent, depth) + "}")
+	}
+
+	printer.printTrailing(comments.Trailing)
+	printer.builder.WriteString("\n")
+}
+
+func (printer *printer) printWords(words ...string) {
+	printer.builder.WriteString(strings.Join(words, " "))
+}
+
+func (printer *printer) printComments(comments []Comment, depth int) {
+	for _, comment := range comments {
+		printer.builder.WriteString(strings.Repeat(formatterIndent, depth) + comment.Text + "\n")
+	}
+}
+
+func (printer *printer) printTrailing(comments []Comment) {
+	for _, comment := range comments {
+		printer.builder.WriteString(" " + comment.Text)
+	}
+}
+
+// formatValue prints a value the way it was written, strings are re-quoted in the canonical form
+func formatValue(value Value) string {
+	switch value.Kind {
+	case TokenString:
+		return strconv.Quote(value.Text)
+	case TokenRawString:
+		return "`" + value.Text + "`"
+	}
+	return value.Text
+}
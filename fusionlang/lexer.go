@@ -0,0 +1,233 @@
+package fusionlang
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type TokenKind int
+
+const (
+	TokenEOF TokenKind = iota
+	TokenIdent
+	TokenNumber
+	TokenString
+	TokenRawString
+	TokenLBrace
+	TokenRBrace
+	TokenArrow
+	TokenComment
+	TokenIllegal
+)
+
+func (kind TokenKind) String() string {
+	switch kind {
+	case TokenEOF:
+		return "end of file"
+	case TokenIdent:
+		return "name"
+	case TokenNumber:
+		return "number"
+	case TokenString, TokenRawString:
+		return "string"
+	case TokenLBrace:
+		return "'{'"
+	case TokenRBrace:
+		return "'}'"
+	case TokenArrow:
+		return "'->'"
+	case TokenComment:
+		return "comment"
+	}
+	return "invalid character"
+}
+
+// Token is a lexical token. For strings Text contains the unquoted value.
+// EndLine differs from Line only for raw strings spanning several lines.
+type Token struct {
+	Position
+	Kind    TokenKind
+	Text    string
+	EndLine int
+}
+
+// describe returns a short human-readable description of the token for error messages
+func (token Token) describe() string {
+	switch token.Kind {
+	case TokenIdent, TokenNumber:
+		return fmt.Sprintf("'%s'", token.Text)
+	case TokenString, TokenRawString:
+		text := token.Text
+		if len(text) > 20 {
+			text = text[:20] + "..."
+		}
+		return fmt.Sprintf("string %q", text)
+	case TokenIllegal:
+		return fmt.Sprintf("'%s'", token.Text)
+	}
+	return token.Kind.String()
+}
+
+type lexer struct {
+	source string
+	offset int
+	line   int
+	column int
+	errors ErrorList
+}
+
+func newLexer(source string) *lexer {
+	// Byte order marks are added by some editors on Windows
+	source = strings.TrimPrefix(source, "\uFEFF")
+	return &lexer{source: source, line: 1, column: 1}
+}
+
+func isIdentRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' || r == '-'
+}
+
+func (lex *lexer) peekRune(ahead int) rune {
+	offset := lex.offset
+	for i := 0; i < ahead; i++ {
+		if offset >= len(lex.source) {
+			return 0
+		}
+		_, size := utf8.DecodeRuneInString(lex.source[offset:])
+		offset += size
+	}
+	if offset >= len(lex.source) {
+		return 0
+	}
+	r, _ := utf8.DecodeRuneInString(lex.source[offset:])
+	return r
+}
+
+func (lex *lexer) advance() rune {
+	r, size := utf8.DecodeRuneInString(lex.source[lex.offset:])
+	lex.offset += size
+	if r == '\n' {
+		lex.line++
+		lex.column = 1
+	} else {
+		lex.column++
+	}
+	return r
+}
+
+func (lex *lexer) errorf(position Position, format string, args ...interface{}) {
+	lex.errors = append(lex.errors, &Error{Position: position, Message: fmt.Sprintf(format, args...)})
+}
+
+// next returns the next token including comments
+func (lex *lexer) next() Token {
+	for lex.offset < len(lex.source) {
+		r := lex.peekRune(0)
+		if r == ' ' || r == '\t' || r == '\r' || r == '\n' || r == ';' {
+			lex.advance()
+			continue
+		}
+		break
+	}
+
+	start := Position{Line: lex.line, Column: lex.column}
+	token := Token{Position: start, EndLine: start.Line}
+	if lex.offset >= len(lex.source) {
+		token.Kind = TokenEOF
+		return token
+	}
+
+	startOffset := lex.offset
+	r := lex.peekRune(0)
+	switch {
+	case r == '#' || (r == '/' && lex.peekRune(1) == '/'):
+		for lex.offset < len(lex.source) && lex.peekRune(0) != '\n' {
+			lex.advance()
+		}
+		token.Kind = TokenComment
+		token.Text = strings.TrimRight(lex.source[startOffset:lex.offset], " \t\r")
+	case r == '{':
+		lex.advance()
+		token.Kind = TokenLBrace
+		token.Text = "{"
+	case r == '}':
+		lex.advance()
+		token.Kind = TokenRBrace
+		token.Text = "}"
+	case r == '-' && lex.peekRune(1) == '>':
+		lex.advance()
+		lex.advance()
+		token.Kind = TokenArrow
+		token.Text = "->"
+	case r == '"':
+		token.Kind = TokenString
+		token.Text = lex.scanString(start)
+	case r == '`':
+		token.Kind = TokenRawString
+		token.Text = lex.scanRawString(start)
+		token.EndLine = lex.line
+	case isIdentRune(r):
+		for lex.offset < len(lex.source) {
+			next := lex.peekRune(0)
+			// "a->b" is a bind of two names, not a single name
+			if !isIdentRune(next) || (next == '-' && lex.peekRune(1) == '>') {
+				break
+			}
+			lex.advance()
+		}
+		token.Text = lex.source[startOffset:lex.offset]
+		token.Kind = TokenIdent
+		if _, err := strconv.Atoi(token.Text); err == nil {
+			token.Kind = TokenNumber
+		}
+	default:
+		lex.advance()
+		token.Kind = TokenIllegal
+		token.Text = lex.source[startOffset:lex.offset]
+	}
+	return token
+}
+
+func (lex *lexer) scanString(start Position) string {
+	startOffset := lex.offset
+	lex.advance()
+	for {
+		if lex.offset >= len(lex.source) || lex.peekRune(0) == '\n' {
+			lex.errorf(start, "string is not terminated, add a closing '\"' (use `backquotes` for multi-line strings)")
+			return lex.source[startOffset+1 : lex.offset]
+		}
+		r := lex.advance()
+		if r == '\\' && lex.offset < len(lex.source) {
+			lex.advance()
+			continue
+		}
+		if r == '"' {
+			break
+		}
+	}
+
+	literal := lex.source[startOffset:lex.offset]
+	value, err := strconv.Unquote(literal)
+	if err != nil {
+		lex.errorf(start, "invalid escape sequence in string %s", literal)
+		return literal[1 : len(literal)-1]
+	}
+	return value
+}
+
+func (lex *lexer) scanRawString(start Position) string {
+	lex.advance()
+	contentStart := lex.offset
+	for lex.offset < len(lex.source) {
+		if lex.peekRune(0) == '`' {
+			value := lex.source[contentStart:lex.offset]
+			lex.advance()
+			return value
+		}
+		lex.advance()
+	}
+	lex.errorf(start, "raw string is not terminated, add a closing '`'")
+	return lex.source[contentStart:]
+}
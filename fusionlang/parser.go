@@ -0,0 +1,366 @@
+package fusionlang
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Keywords allowed at the top level of a file and inside blocks of every declaration
+var (
+	topLevelKeywords = []string{"version", "include", "server", "schema", "message", "app"}
+	serverKeywords   = []string{"description", "resource", "bind"}
+	resourceKeywords = []string{"description"}
+	schemaKeywords   = []string{"description", "version", "file", "content", "compatibility"}
+	messageKeywords  = []string{"description", "schema", "message_type"}
+	appKeywords      = []string{"description", "sends", "receives"}
+)
+
+const topLevelPlace = "at the top level"
+
+// bailout is used to unwind the parser to the closest declaration after a syntax error
+type bailout struct{}
+
+type parser struct {
+	tokens []Token
+	pos    int
+	depth  int
+	// stray comments found in the middle of a declaration, they are kept as trailing comments
+	stray  []Comment
+	errors ErrorList
+	// line of the last consumed token, used to attach trailing comments
+	lastLine int
+}
+
+// Parse parses a Fusionlang source. Syntax errors don't stop the parser: it skips to the next
+// top-level declaration and continues, so that all errors can be reported at once.
+// The returned file contains every declaration which was parsed successfully.
+func Parse(source string) (*File, error) {
+	lex := newLexer(source)
+	p := &parser{}
+	for {
+		token := lex.next()
+		p.tokens = append(p.tokens, token)
+		if token.Kind == TokenEOF {
+			break
+		}
+	}
+	p.errors = append(p.errors, lex.errors...)
+
+	file := p.parseFile()
+	sort.SliceStable(p.errors, func(i, j int) bool {
+		if p.errors[i].Line != p.errors[j].Line {
+			return p.errors[i].Line < p.errors[j].Line
+		}
+		return p.errors[i].Column < p.errors[j].Column
+	})
+	return file, p.errors.Err()
+}
+
+func (p *parser) parseFile() *File {
+	file := &File{}
+	for {
+		leading := p.takeComments()
+		if p.peek().Kind == TokenEOF {
+			file.Trailing = leading
+			return file
+		}
+		item := p.parseDeclaration()
+		if item == nil {
+			continue
+		}
+		item.comments().Leading = leading
+		p.attachTrailing(item)
+		file.Items = append(file.Items, item)
+	}
+}
+
+// parseDeclaration parses a single top-level declaration and recovers from syntax errors found in it
+// by skipping to the next top-level keyword. Errors inside blocks abandon the whole declaration.
+func (p *parser) parseDeclaration() (item Item) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			if _, ok := recovered.(bailout); !ok {
+				panic(recovered)
+			}
+			p.synchronize()
+			p.stray = nil
+			item = nil
+		}
+	}()
+	return p.parseItem(topLevelKeywords, topLevelPlace)
+}
+
+// synchronize skips tokens until a top-level keyword at the start of a line outside of any block
+func (p *parser) synchronize() {
+	for {
+		token := p.peek()
+		if token.Kind == TokenEOF {
+			break
+		}
+		if p.depth == 0 && token.Kind == TokenIdent && p.startsLine() && isKeyword(token.Text, topLevelKeywords) {
+			break
+		}
+		p.next()
+	}
+	p.depth = 0
+	p.takeComments()
+}
+
+func (p *parser) parseItem(keywords []string, where string) Item {
+	keyword := p.peek()
+	if keyword.Kind != TokenIdent || !isKeyword(keyword.Text, keywords) {
+		p.unexpectedKeyword(keyword, keywords, where)
+	}
+	p.next()
+
+	switch keyword.Text {
+	case "version":
+		return p.parseProperty(keyword, "a number", TokenNumber)
+	case "include", "description", "file", "content", "message_type":
+		return p.parseProperty(keyword, "a string", TokenString, TokenRawString)
+	case "server":
+		return p.parseServer(keyword)
+	case "resource":
+		return p.parseResource(keyword)
+	case "bind":
+		return p.parseBind(keyword)
+	case "schema":
+		if where == topLevelPlace {
+			return p.parseSchema(keyword)
+		}
+		// Inside a message "schema" references a schema by name
+		return &PropertyItem{Position: keyword.Position, Keyword: keyword.Text, Value: p.expectName("schema name")}
+	case "compatibility":
+		return &PropertyItem{Position: keyword.Position, Keyword: keyword.Text, Value: p.expectName("compatibility mode")}
+	case "message":
+		return p.parseMessage(keyword)
+	case "app":
+		return p.parseApp(keyword)
+	case "sends":
+		return p.parseFlow(keyword, "to")
+	case "receives":
+		return p.parseFlow(keyword, "from")
+	}
+	p.unexpectedKeyword(keyword, keywords, where)
+	return nil
+}
+
+func (p *parser) parseProperty(keyword Token, expected string, kinds ...TokenKind) *PropertyItem {
+	token := p.peek()
+	for _, kind := range kinds {
+		if token.Kind == kind {
+			p.next()
+			return &PropertyItem{Position: keyword.Position, Keyword: keyword.Text, Value: tokenValue(token)}
+		}
+	}
+	hint := ""
+	if token.Kind == TokenIdent && kinds[0] == TokenString {
+		hint = fmt.Sprintf(", put it in quotes: \"%s\"", token.Text)
+	}
+	p.failAt(token.Position, "%s expects %s, found %s%s", keyword.Text, expected, token.describe(), hint)
+	return nil
+}
+
+func (p *parser) parseServer(keyword Token) *ServerDecl {
+	server := &ServerDecl{Position: keyword.Position}
+	server.Name = p.expectName("server name")
+	server.Protocol = p.expectName(fmt.Sprintf("protocol of server %s", server.Name.Text))
+	server.Block = p.parseBlock(serverKeywords, "in a server block")
+	return server
+}
+
+func (p *parser) parseResource(keyword Token) *ResourceDecl {
+	resource := &ResourceDecl{Position: keyword.Position}
+	resource.Name = p.expectName("resource name")
+	resource.Type = p.expectName(fmt.Sprintf("type of resource %s", resource.Name.Text))
+	resource.Mode = p.expectName(fmt.Sprintf("mode of resource %s", resource.Name.Text))
+	resource.Block = p.parseBlock(resourceKeywords, "in a resource block")
+	return resource
+}
+
+func (p *parser) parseBind(keyword Token) *BindDecl {
+	bind := &BindDecl{Position: keyword.Position}
+	bind.Source = p.expectName("source resource of bind")
+	if arrow := p.peek(); arrow.Kind != TokenArrow {
+		p.failAt(arrow.Position, "expected '->' between bound resources, found %s", arrow.describe())
+	}
+	p.next()
+	bind.Target = p.expectName("target resource of bind")
+	return bind
+}
+
+func (p *parser) parseSchema(keyword Token) *SchemaDecl {
+	schema := &SchemaDecl{Position: keyword.Position}
+	schema.Name = p.expectName("schema name")
+	schema.Type = p.expectName(fmt.Sprintf("type of schema %s", schema.Name.Text))
+	schema.Block = p.parseBlock(schemaKeywords, "in a schema block")
+	return schema
+}
+
+func (p *parser) parseMessage(keyword Token) *MessageDecl {
+	message := &MessageDecl{Position: keyword.Position}
+	message.Name = p.expectName("message name")
+	message.Block = p.parseBlock(messageKeywords, "in a message block")
+	return message
+}
+
+func (p *parser) parseApp(keyword Token) *AppDecl {
+	app := &AppDecl{Position: keyword.Position}
+	app.Name = p.expectName("app name")
+	app.Block = p.parseBlock(appKeywords, "in an app block")
+	return app
+}
+
+func (p *parser) parseFlow(keyword Token, preposition string) *FlowItem {
+	flow := &FlowItem{Position: keyword.Position, Direction: keyword.Text}
+	flow.Message = p.expectName(fmt.Sprintf("message name after '%s'", keyword.Text))
+
+	token := p.peek()
+	if token.Kind != TokenIdent || token.Text != preposition {
+		p.failAt(token.Position, "expected '%s' after message %s, found %s (%s <message> %s \"<resource uri>\")",
+			preposition, flow.Message.Text, token.describe(), keyword.Text, preposition)
+	}
+	p.next()
+
+	token = p.peek()
+	if token.Kind != TokenString && token.Kind != TokenRawString {
+		p.failAt(token.Position, "expected resource URI in quotes after '%s', found %s", preposition, token.describe())
+	}
+	p.next()
+	flow.Resource = tokenValue(token)
+	return flow
+}
+
+// parseBlock parses an optional "{ ... }" block. Declarations without a block are allowed.
+func (p *parser) parseBlock(keywords []string, where string) Block {
+	block := Block{}
+	open := p.peek()
+	if open.Kind != TokenLBrace {
+		return block
+	}
+	p.next()
+	block.Present = true
+
+	for {
+		leading := p.takeComments()
+		token := p.peek()
+		if token.Kind == TokenRBrace {
+			block.Dangling = leading
+			p.next()
+			return block
+		}
+		if token.Kind == TokenEOF {
+			p.failAt(token.Position, "missing '}' to close the block opened at line %d, column %d", open.Line, open.Column)
+		}
+		item := p.parseItem(keywords, where)
+		item.comments().Leading = leading
+		p.attachTrailing(item)
+		block.Items = append(block.Items, item)
+	}
+}
+
+func (p *parser) expectName(what string) Value {
+	token := p.peek()
+	switch token.Kind {
+	case TokenIdent, TokenNumber, TokenString:
+		p.next()
+		return tokenValue(token)
+	}
+	p.failAt(token.Position, "expected %s, found %s", what, token.describe())
+	return Value{}
+}
+
+func (p *parser) unexpectedKeyword(token Token, keywords []string, where string) {
+	if token.Kind == TokenRBrace && p.depth == 0 {
+		p.failAt(token.Position, "unexpected '}' without a matching '{'")
+	}
+	if token.Kind == TokenIdent {
+		if suggestion := suggest(token.Text, keywords); suggestion != "" {
+			p.failAt(token.Position, "unknown keyword '%s' %s, did you mean '%s'?", token.Text, where, suggestion)
+		}
+		p.failAt(token.Position, "unknown keyword '%s' %s, expected one of: %s", token.Text, where, strings.Join(keywords, ", "))
+	}
+	p.failAt(token.Position, "unexpected %s %s, expected one of: %s", token.describe(), where, strings.Join(keywords, ", "))
+}
+
+func (p *parser) failAt(position Position, format string, args ...interface{}) {
+	p.errors = append(p.errors, &Error{Position: position, Message: fmt.Sprintf(format, args...)})
+	panic(bailout{})
+}
+
+// peek returns the next token which is not a comment without consuming anything
+func (p *parser) peek() Token {
+	for i := p.pos; i < len(p.tokens); i++ {
+		if p.tokens[i].Kind != TokenComment {
+			return p.tokens[i]
+		}
+	}
+	return p.tokens[len(p.tokens)-1]
+}
+
+// next consumes the next token which is not a comment
+func (p *parser) next() Token {
+	for p.pos < len(p.tokens)-1 && p.tokens[p.pos].Kind == TokenComment {
+		p.stray = append(p.stray, tokenComment(p.tokens[p.pos]))
+		p.pos++
+	}
+	token := p.tokens[p.pos]
+	if token.Kind == TokenEOF {
+		return token
+	}
+	p.pos++
+	p.lastLine = token.EndLine
+	switch token.Kind {
+	case TokenLBrace:
+		p.depth++
+	case TokenRBrace:
+		if p.depth > 0 {
+			p.depth--
+		}
+	}
+	return token
+}
+
+// startsLine reports whether the next token is the first token on its line
+func (p *parser) startsLine() bool {
+	return p.peek().Line > p.lastLine
+}
+
+// takeComments consumes comments placed before the next token
+func (p *parser) takeComments() []Comment {
+	var comments []Comment
+	for p.pos < len(p.tokens) && p.tokens[p.pos].Kind == TokenComment {
+		comments = append(comments, tokenComment(p.tokens[p.pos]))
+		p.pos++
+	}
+	return comments
+}
+
+// attachTrailing attaches comments placed on the last line of the item and comments found inside it
+func (p *parser) attachTrailing(item Item) {
+	comments := item.comments()
+	comments.Trailing = append(comments.Trailing, p.stray...)
+	p.stray = nil
+	for p.pos < len(p.tokens) && p.tokens[p.pos].Kind == TokenComment && p.tokens[p.pos].Line == p.lastLine {
+		comments.Trailing = append(comments.Trailing, tokenComment(p.tokens[p.pos]))
+		p.pos++
+	}
+}
+
+func tokenValue(token Token) Value {
+	return Value{Position: token.Position, Kind: token.Kind, Text: token.Text}
+}
+
+func tokenComment(token Token) Comment {
+	return Comment{Position: token.Position, Text: token.Text}
+}
+
+func isKeyword(word string, keywords []string) bool {
+	for _, keyword := range keywords {
+		if word == keyword {
+			return true
+		}
+	}
+	return false
+}
@@ -3,6 +3,7 @@ module github.com/fusioncatltd/fusioncat
 go 1.23.11
 
 require (
+	github.com/emicklei/proto v1.14.3
 	github.com/fusioncatltd/lib-go-asyncresourceuri v0.0.0-20250819122037-f311ff8db025
 	github.com/gavv/httpexpect/v2 v2.17.0
 	github.com/gin-contrib/cors v1.7.6
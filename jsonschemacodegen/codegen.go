@@ -0,0 +1,723 @@
+// Package jsonschemacodegen generates Go types for JSON schemas without external tools.
+// Objects become structs with fields in the order of their properties. Nested objects, string enums
+// and referenced definitions become named types prefixed with the name of the top-level type,
+// so that types of several schemas can be placed into the same package:
+//
+//	code, err := jsonschemacodegen.GenerateGo(schema, "OrderCreated")
+//
+// Required properties are values, optional and nullable ones are pointers, optional properties
+// are omitted when empty. String enums get constants. oneOf and anyOf of a single type and null
+// are nullable values of the type, alternatives of objects are merged into a struct with optional
+// fields and other alternatives are interface{}. Formats are noted in comments, so the generated
+// code needs no imports. Only local references ("#/$defs/address") are resolved, references
+// to other documents must be bundled into the schema beforehand.
+package jsonschemacodegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+)
+
+// maxFlattenDepth limits nesting of allOf and references merged into a single schema
+const maxFlattenDepth = 32
+
+// GenerateGo generates Go types for the schema, the top-level type gets typeName
+func GenerateGo(schema []byte, typeName string) (string, error) {
+	root, err := decode(schema)
+	if err != nil {
+		return "", err
+	}
+
+	generator := &generator{
+		root:       root,
+		rootName:   typeName,
+		usedNames:  make(map[string]bool),
+		references: make(map[string]resolvedType),
+	}
+	declaration := generator.reserve(typeName)
+	generator.references["#"] = resolvedType{typ: goType{expr: typeName, named: declaration}}
+	generator.finishReserved(declaration, generator.typeOf(root, typeName, declaration))
+	if generator.err != nil {
+		return "", generator.err
+	}
+
+	formatted, err := format.Source([]byte(generator.render()))
+	if err != nil {
+		return "", fmt.Errorf("failed to format generated code: %v", err)
+	}
+	return string(formatted), nil
+}
+
+type declarationKind int
+
+const (
+	// reservedDeclaration is a name taken by a definition whose type is not known yet
+	reservedDeclaration declarationKind = iota
+	structDeclaration
+	enumDeclaration
+	// definedDeclaration is a named type of another type, e.g. type OrderTags []string
+	definedDeclaration
+)
+
+type declaration struct {
+	name       string
+	kind       declarationKind
+	doc        string
+	fields     []*field
+	values     []string
+	underlying goType
+}
+
+type field struct {
+	name     string
+	jsonName string
+	doc      string
+	comment  string
+	typ      goType
+	required bool
+	nullable bool
+}
+
+// goType is a Go type expression, named is set for declared types
+type goType struct {
+	expr  string
+	named *declaration
+}
+
+var anyType = goType{expr: "interface{}"}
+
+// resolvedType is the Go type of a schema. Nullable schemas also allow null, comments describe
+// formats which have no Go types of their own.
+type resolvedType struct {
+	typ      goType
+	nullable bool
+	comment  string
+}
+
+type generator struct {
+	root         interface{}
+	rootName     string
+	usedNames    map[string]bool
+	declarations []*declaration
+	references   map[string]resolvedType
+	err          error
+}
+
+func (generator *generator) fail(err error) {
+	if generator.err == nil {
+		generator.err = err
+	}
+}
+
+// uniqueName returns the name or the name with a number if it is taken
+func (generator *generator) uniqueName(name string) string {
+	unique := name
+	for suffix := 2; generator.usedNames[unique]; suffix++ {
+		unique = fmt.Sprintf("%s%d", name, suffix)
+	}
+	generator.usedNames[unique] = true
+	return unique
+}
+
+func (generator *generator) reserve(name string) *declaration {
+	declaration := &declaration{name: generator.uniqueName(name), kind: reservedDeclaration}
+	generator.declarations = append(generator.declarations, declaration)
+	return declaration
+}
+
+// declare returns the reserved declaration for the schema, or a new declaration if there is none
+func (generator *generator) declare(name string, reserved *declaration, kind declarationKind) *declaration {
+	declaration := reserved
+	if declaration == nil || declaration.kind != reservedDeclaration {
+		declaration = generator.reserve(name)
+	}
+	declaration.kind = kind
+	return declaration
+}
+
+// finishReserved declares reserved names of schemas which are not structs or enums as named types
+func (generator *generator) finishReserved(declaration *declaration, resolved resolvedType) resolvedType {
+	if declaration.kind != reservedDeclaration {
+		return resolved
+	}
+	declaration.kind = definedDeclaration
+	declaration.underlying = resolved.typ
+	resolved.typ = goType{expr: declaration.name, named: declaration}
+	return resolved
+}
+
+// typeOf returns the Go type of the schema, name is the name of new named types.
+// The reserved declaration is used for the type when the schema is a struct or an enum.
+func (generator *generator) typeOf(schema interface{}, name string, reserved *declaration) resolvedType {
+	schemaObject, isObject := schema.(*object)
+	if !isObject {
+		return resolvedType{typ: anyType}
+	}
+	if reference, isReference := pureReference(schemaObject); isReference {
+		return generator.referenceType(reference)
+	}
+
+	flattened := generator.flatten(schemaObject, 0)
+	for _, keyword := range []string{"oneOf", "anyOf"} {
+		if alternatives, isList := flattened.get(keyword).([]interface{}); isList {
+			return generator.alternativesType(flattened.without(keyword), alternatives, name, reserved)
+		}
+	}
+
+	types, nullable := schemaTypes(flattened)
+	var resolved resolvedType
+	switch {
+	case len(types) == 0:
+		resolved = resolvedType{typ: anyType}
+	case len(types) == 2 && contains(types, "integer") && contains(types, "number"):
+		resolved = resolvedType{typ: goType{expr: "float64"}}
+	case len(types) > 1:
+		resolved = resolvedType{typ: anyType}
+	case flattened.has("enum") || flattened.has("const"):
+		resolved = generator.enumType(flattened, types[0], name, reserved)
+	default:
+		resolved = generator.singleType(flattened, types[0], name, reserved)
+	}
+	resolved.nullable = resolved.nullable || nullable
+	return resolved
+}
+
+func (generator *generator) singleType(schema *object, typeName string, name string, reserved *declaration) resolvedType {
+	switch typeName {
+	case "string":
+		format, _ := schema.get("format").(string)
+		if encoding, _ := schema.get("contentEncoding").(string); encoding == "base64" || format == "byte" {
+			// encoding/json encodes byte slices as base64 strings
+			return resolvedType{typ: goType{expr: "[]byte"}}
+		}
+		return resolvedType{typ: goType{expr: "string"}, comment: format}
+	case "integer":
+		format, _ := schema.get("format").(string)
+		if format == "int32" {
+			return resolvedType{typ: goType{expr: "int32"}}
+		}
+		return resolvedType{typ: goType{expr: "int64"}}
+	case "number":
+		format, _ := schema.get("format").(string)
+		if format == "float" {
+			return resolvedType{typ: goType{expr: "float32"}}
+		}
+		return resolvedType{typ: goType{expr: "float64"}}
+	case "boolean":
+		return resolvedType{typ: goType{expr: "bool"}}
+	case "array":
+		items, isSchema := schema.get("items").(*object)
+		if !isSchema {
+			// Tuples and arrays of anything
+			return resolvedType{typ: goType{expr: "[]interface{}"}}
+		}
+		item := generator.typeOf(items, singularName(name), nil)
+		return resolvedType{typ: goType{expr: "[]" + pointerIfNullable(item)}}
+	case "object":
+		properties, _ := schema.get("properties").(*object)
+		if properties == nil || len(properties.keys) == 0 {
+			values, isSchema := schema.get("additionalProperties").(*object)
+			if !isSchema {
+				return resolvedType{typ: goType{expr: "map[string]interface{}"}}
+			}
+			value := generator.typeOf(values, name+"Value", nil)
+			return resolvedType{typ: goType{expr: "map[string]" + pointerIfNullable(value)}}
+		}
+		return generator.structType(schema, properties, name, reserved)
+	}
+	return resolvedType{typ: anyType}
+}
+
+func (generator *generator) structType(schema *object, properties *object, name string, reserved *declaration) resolvedType {
+	declaration := generator.declare(name, reserved, structDeclaration)
+	declaration.doc, _ = schema.get("description").(string)
+
+	required := make(map[string]bool)
+	if requiredList, isList := schema.get("required").([]interface{}); isList {
+		for _, property := range requiredList {
+			if propertyName, isString := property.(string); isString {
+				required[propertyName] = true
+			}
+		}
+	}
+
+	usedFieldNames := make(map[string]bool)
+	for _, propertyName := range properties.keys {
+		fieldName := exportedName(propertyName)
+		if fieldName == "" || !isLetter(fieldName[0]) {
+			fieldName = "Field" + fieldName
+		}
+		baseFieldName := fieldName
+		for suffix := 2; usedFieldNames[fieldName]; suffix++ {
+			fieldName = fmt.Sprintf("%s%d", baseFieldName, suffix)
+		}
+		usedFieldNames[fieldName] = true
+
+		property := properties.get(propertyName)
+		resolved := generator.typeOf(property, declaration.name+fieldName, nil)
+		doc := ""
+		if propertyObject, isObject := property.(*object); isObject {
+			doc, _ = propertyObject.get("description").(string)
+		}
+		declaration.fields = append(declaration.fields, &field{
+			name:     fieldName,
+			jsonName: propertyName,
+			doc:      doc,
+			comment:  resolved.comment,
+			typ:      resolved.typ,
+			required: required[propertyName],
+			nullable: resolved.nullable,
+		})
+	}
+	return resolvedType{typ: goType{expr: declaration.name, named: declaration}}
+}
+
+// enumType returns a named string type with constants for string enums, other enums get
+// the type of their values
+func (generator *generator) enumType(schema *object, typeName string, name string, reserved *declaration) resolvedType {
+	values, _ := schema.get("enum").([]interface{})
+	if constant, exists := schema.values["const"]; exists {
+		values = []interface{}{constant}
+	}
+	nullable := false
+	var stringValues []string
+	for _, value := range values {
+		switch typed := value.(type) {
+		case nil:
+			nullable = true
+		case string:
+			stringValues = append(stringValues, typed)
+		}
+	}
+	if typeName != "string" || len(stringValues) == 0 {
+		resolved := generator.singleType(schema.without("enum", "const"), typeName, name, reserved)
+		resolved.nullable = resolved.nullable || nullable
+		return resolved
+	}
+
+	declaration := generator.declare(name, reserved, enumDeclaration)
+	declaration.doc, _ = schema.get("description").(string)
+	declaration.values = stringValues
+	return resolvedType{typ: goType{expr: declaration.name, named: declaration}, nullable: nullable}
+}
+
+// alternativesType returns the type of oneOf and anyOf alternatives. The rest of the schema
+// applies to all alternatives.
+func (generator *generator) alternativesType(rest *object, alternatives []interface{}, name string,
+	reserved *declaration) resolvedType {
+	nullable := false
+	var kept []interface{}
+	var flattened []*object
+	for _, alternative := range alternatives {
+		alternativeObject, isObject := alternative.(*object)
+		if !isObject {
+			if allowed, isBool := alternative.(bool); isBool && !allowed {
+				continue
+			}
+			return resolvedType{typ: anyType}
+		}
+		flat := generator.flatten(alternativeObject, 0)
+		if isNullSchema(flat) {
+			nullable = true
+			continue
+		}
+		// Alternatives which only list required properties constrain the rest of the schema
+		if !hasTypeKeywords(flat) {
+			continue
+		}
+		kept = append(kept, alternative)
+		flattened = append(flattened, flat)
+	}
+
+	var resolved resolvedType
+	switch {
+	case len(kept) == 0:
+		resolved = generator.typeOf(rest, name, reserved)
+	case len(kept) == 1 && !hasTypeKeywords(rest):
+		resolved = generator.typeOf(kept[0], name, reserved)
+	case len(kept) == 1:
+		resolved = generator.typeOf(mergeSchemas(rest, flattened[0]), name, reserved)
+	default:
+		resolved = generator.mergedAlternativesType(rest, flattened, name, reserved)
+	}
+	resolved.nullable = resolved.nullable || nullable
+	return resolved
+}
+
+// mergedAlternativesType merges alternatives of the same type: enums of strings are combined,
+// objects are merged into a struct with optional fields
+func (generator *generator) mergedAlternativesType(rest *object, alternatives []*object, name string,
+	reserved *declaration) resolvedType {
+	var commonType string
+	var enumValues []interface{}
+	allEnums := true
+	for _, alternative := range alternatives {
+		types, _ := schemaTypes(mergeSchemas(rest, alternative))
+		if len(types) != 1 {
+			return resolvedType{typ: anyType}
+		}
+		switch {
+		case commonType == "" || commonType == types[0]:
+			commonType = types[0]
+		case (commonType == "integer" && types[0] == "number") || (commonType == "number" && types[0] == "integer"):
+			commonType = "number"
+		default:
+			return resolvedType{typ: anyType}
+		}
+		if constant, exists := alternative.values["const"]; exists {
+			enumValues = append(enumValues, constant)
+		} else if values, isList := alternative.get("enum").([]interface{}); isList {
+			enumValues = append(enumValues, values...)
+		} else {
+			allEnums = false
+		}
+	}
+
+	switch {
+	case commonType == "string" && allEnums:
+		merged := rest.without("enum", "const")
+		merged.set("type", "string")
+		merged.set("enum", enumValues)
+		return generator.typeOf(merged, name, reserved)
+	case commonType == "object":
+		merged := rest
+		for _, alternative := range alternatives {
+			merged = mergeSchemas(merged, alternative)
+		}
+		// Properties of an alternative are not required by the others
+		return generator.typeOf(merged.without("required"), name, reserved)
+	}
+	return generator.singleType(rest.without("enum", "const"), commonType, name, reserved)
+}
+
+// referenceType returns the named type of the reference target. Targets are declared once,
+// under the name of the last token of the reference, e.g. "#/$defs/address" becomes <Root>Address.
+func (generator *generator) referenceType(reference string) resolvedType {
+	if resolved, exists := generator.references[reference]; exists {
+		return resolved
+	}
+	target, found := resolvePointer(generator.root, reference)
+	if !found {
+		generator.fail(fmt.Errorf("unresolved reference %q, only local references are supported", reference))
+		return resolvedType{typ: anyType}
+	}
+
+	tokens := pointerTokens(strings.TrimPrefix(reference, "#"))
+	name := exportedName(tokens[len(tokens)-1])
+	if name == "" || !isLetter(name[0]) {
+		name = "Ref" + name
+	}
+	declaration := generator.reserve(generator.rootName + name)
+	generator.references[reference] = resolvedType{typ: goType{expr: declaration.name, named: declaration}}
+	resolved := generator.finishReserved(declaration, generator.typeOf(target, declaration.name, declaration))
+	generator.references[reference] = resolved
+	return resolved
+}
+
+// flatten merges allOf subschemas and targets of references with other keywords into a single schema
+func (generator *generator) flatten(schema *object, depth int) *object {
+	flattened := schema.without("$ref", "allOf")
+	if depth > maxFlattenDepth {
+		return flattened
+	}
+	if reference, isString := schema.get("$ref").(string); isString {
+		target, found := resolvePointer(generator.root, reference)
+		if !found {
+			generator.fail(fmt.Errorf("unresolved reference %q, only local references are supported", reference))
+		} else if targetObject, isObject := target.(*object); isObject {
+			flattened = mergeSchemas(flattened, generator.flatten(targetObject, depth+1))
+		}
+	}
+	if subschemas, isList := schema.get("allOf").([]interface{}); isList {
+		for _, subschema := range subschemas {
+			if subschemaObject, isObject := subschema.(*object); isObject {
+				flattened = mergeSchemas(flattened, generator.flatten(subschemaObject, depth+1))
+			}
+		}
+	}
+	return flattened
+}
+
+// mergeSchemas returns the base schema with keywords of the extra schema. Keywords of the base schema
+// win, except properties and required lists, which are combined.
+func mergeSchemas(base *object, extra *object) *object {
+	merged := base.without()
+	for _, key := range extra.keys {
+		value := extra.values[key]
+		existing, exists := merged.values[key]
+		if !exists {
+			merged.set(key, value)
+			continue
+		}
+		switch key {
+		case "properties":
+			existingProperties, existingIsObject := existing.(*object)
+			extraProperties, extraIsObject := value.(*object)
+			if existingIsObject && extraIsObject {
+				properties := existingProperties.without()
+				for _, propertyName := range extraProperties.keys {
+					if !properties.has(propertyName) {
+						properties.set(propertyName, extraProperties.values[propertyName])
+					}
+				}
+				merged.set(key, properties)
+			}
+		case "required":
+			existingRequired, existingIsList := existing.([]interface{})
+			extraRequired, extraIsList := value.([]interface{})
+			if existingIsList && extraIsList {
+				merged.set(key, append(append([]interface{}{}, existingRequired...), extraRequired...))
+			}
+		}
+	}
+	return merged
+}
+
+// annotationKeywords don't change the type of a schema
+var annotationKeywords = map[string]bool{
+	"$comment": true, "$id": true, "$schema": true, "title": true, "description": true, "default": true,
+	"examples": true, "deprecated": true, "readOnly": true, "writeOnly": true,
+	"$defs": true, "definitions": true,
+}
+
+// pureReference returns the reference of schemas which only refer to another schema
+func pureReference(schema *object) (string, bool) {
+	reference, isString := schema.get("$ref").(string)
+	if !isString {
+		return "", false
+	}
+	for _, key := range schema.keys {
+		if key != "$ref" && !annotationKeywords[key] {
+			return "", false
+		}
+	}
+	return reference, true
+}
+
+// typeKeywords define the type of values, other keywords only constrain them
+var typeKeywords = []string{"type", "properties", "additionalProperties", "items", "prefixItems",
+	"enum", "const", "oneOf", "anyOf"}
+
+func hasTypeKeywords(schema *object) bool {
+	for _, keyword := range typeKeywords {
+		if schema.has(keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func isNullSchema(schema *object) bool {
+	if typeName, isString := schema.get("type").(string); isString && typeName == "null" {
+		return true
+	}
+	if constant, exists := schema.values["const"]; exists && constant == nil {
+		return true
+	}
+	if values, isList := schema.get("enum").([]interface{}); isList && len(values) == 1 && values[0] == nil {
+		return true
+	}
+	return false
+}
+
+// schemaTypes returns the JSON types allowed by the schema besides null. Schemas without types
+// get them from their values and keywords, no types means any value.
+func schemaTypes(schema *object) ([]string, bool) {
+	var types []string
+	nullable := false
+	add := func(typeName string) {
+		if typeName == "null" {
+			nullable = true
+		} else if !contains(types, typeName) {
+			types = append(types, typeName)
+		}
+	}
+
+	switch declared := schema.get("type").(type) {
+	case string:
+		add(declared)
+	case []interface{}:
+		for _, typeName := range declared {
+			if typeName, isString := typeName.(string); isString {
+				add(typeName)
+			}
+		}
+	}
+	if nullable, isBool := schema.get("nullable").(bool); isBool && nullable {
+		add("null")
+	}
+	if schema.has("type") {
+		return types, nullable
+	}
+
+	var values []interface{}
+	if constant, exists := schema.values["const"]; exists {
+		values = []interface{}{constant}
+	} else if enum, isList := schema.get("enum").([]interface{}); isList {
+		values = enum
+	}
+	for _, value := range values {
+		add(valueType(value))
+	}
+	if values != nil {
+		return types, nullable
+	}
+
+	for _, keywords := range []struct {
+		typeName string
+		keywords []string
+	}{
+		{"object", []string{"properties", "required", "additionalProperties", "minProperties", "maxProperties"}},
+		{"array", []string{"items", "prefixItems", "minItems", "maxItems", "contains", "uniqueItems"}},
+		{"number", []string{"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "multipleOf"}},
+		{"string", []string{"minLength", "maxLength", "pattern", "format"}},
+	} {
+		for _, keyword := range keywords.keywords {
+			if schema.has(keyword) {
+				add(keywords.typeName)
+				return types, nullable
+			}
+		}
+	}
+	return types, nullable
+}
+
+func valueType(value interface{}) string {
+	switch typed := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if _, err := typed.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	}
+	return "object"
+}
+
+// pointerIfNullable returns the type of nullable slice items and map values
+func pointerIfNullable(resolved resolvedType) string {
+	if resolved.nullable && !isNilable(resolved.typ) {
+		return "*" + resolved.typ.expr
+	}
+	return resolved.typ.expr
+}
+
+// isNilable tells if values of the type can be nil without a pointer
+func isNilable(typ goType) bool {
+	if typ.named != nil && typ.named.kind == definedDeclaration {
+		return isNilable(typ.named.underlying)
+	}
+	return strings.HasPrefix(typ.expr, "*") || strings.HasPrefix(typ.expr, "[]") ||
+		strings.HasPrefix(typ.expr, "map[") || typ.expr == "interface{}"
+}
+
+func contains(values []string, value string) bool {
+	for _, existing := range values {
+		if existing == value {
+			return true
+		}
+	}
+	return false
+}
+
+func isLetter(character byte) bool {
+	return (character >= 'a' && character <= 'z') || (character >= 'A' && character <= 'Z')
+}
+
+func (generator *generator) render() string {
+	var output strings.Builder
+	for index, declaration := range generator.declarations {
+		if index > 0 {
+			output.WriteString("\n")
+		}
+		writeDoc(&output, declaration.doc, "")
+		switch declaration.kind {
+		case structDeclaration:
+			fmt.Fprintf(&output, "type %s struct {\n", declaration.name)
+			for _, field := range declaration.fields {
+				writeDoc(&output, field.doc, "\t")
+				fmt.Fprintf(&output, "\t%s %s `json:%s`", field.name, fieldType(field, declaration),
+					strconv.Quote(jsonTag(field)))
+				if field.comment != "" {
+					fmt.Fprintf(&output, " // %s", field.comment)
+				}
+				output.WriteString("\n")
+			}
+			output.WriteString("}\n")
+		case enumDeclaration:
+			fmt.Fprintf(&output, "type %s string\n\nconst (\n", declaration.name)
+			for _, value := range declaration.values {
+				constantName := generator.uniqueName(declaration.name + exportedName(value))
+				fmt.Fprintf(&output, "\t%s %s = %s\n", constantName, declaration.name, strconv.Quote(value))
+			}
+			output.WriteString(")\n")
+		default:
+			fmt.Fprintf(&output, "type %s %s\n", declaration.name, declaration.underlying.expr)
+		}
+	}
+	return output.String()
+}
+
+// fieldType returns the type of the struct field. Optional and nullable values are pointers,
+// so are structs which lead back to the struct they are declared in.
+func fieldType(field *field, parent *declaration) string {
+	if isNilable(field.typ) {
+		return field.typ.expr
+	}
+	if !field.required || field.nullable {
+		return "*" + field.typ.expr
+	}
+	if field.typ.named != nil && containsByValue(field.typ.named, parent, make(map[*declaration]bool)) {
+		return "*" + field.typ.expr
+	}
+	return field.typ.expr
+}
+
+// containsByValue tells if the declared type leads to the target through fields which are not
+// behind pointers, slices or maps
+func containsByValue(declaration *declaration, target *declaration, visited map[*declaration]bool) bool {
+	if declaration == target {
+		return true
+	}
+	if visited[declaration] {
+		return false
+	}
+	visited[declaration] = true
+	if declaration.kind == definedDeclaration && declaration.underlying.named != nil {
+		return containsByValue(declaration.underlying.named, target, visited)
+	}
+	for _, field := range declaration.fields {
+		if field.required && !field.nullable && field.typ.named != nil && !isNilable(field.typ) &&
+			containsByValue(field.typ.named, target, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+func jsonTag(field *field) string {
+	if field.required {
+		return field.jsonName
+	}
+	return field.jsonName + ",omitempty"
+}
+
+func writeDoc(output *strings.Builder, doc string, indent string) {
+	doc = strings.TrimSpace(doc)
+	if doc == "" {
+		return
+	}
+	for _, line := range strings.Split(doc, "\n") {
+		fmt.Fprintf(output, "%s// %s\n", indent, strings.TrimSpace(line))
+	}
+}
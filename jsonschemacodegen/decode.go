@@ -0,0 +1,151 @@
+package jsonschemacodegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// object is a decoded JSON object which keeps the order of its keys, so that fields of generated
+// structs follow the order of properties in the schema
+type object struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func newObject() *object {
+	return &object{values: make(map[string]interface{})}
+}
+
+func (obj *object) get(key string) interface{} {
+	return obj.values[key]
+}
+
+func (obj *object) has(key string) bool {
+	_, exists := obj.values[key]
+	return exists
+}
+
+func (obj *object) set(key string, value interface{}) {
+	if _, exists := obj.values[key]; !exists {
+		obj.keys = append(obj.keys, key)
+	}
+	obj.values[key] = value
+}
+
+// without returns a shallow copy of the object without the keys
+func (obj *object) without(keys ...string) *object {
+	copied := newObject()
+	for _, key := range obj.keys {
+		skipped := false
+		for _, removed := range keys {
+			if key == removed {
+				skipped = true
+			}
+		}
+		if !skipped {
+			copied.set(key, obj.values[key])
+		}
+	}
+	return copied
+}
+
+// decode decodes a JSON document, objects are decoded into *object and numbers into json.Number
+func decode(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	value, err := decodeValue(decoder)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON: %v", err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("invalid JSON: unexpected data after the schema")
+	}
+	return value, nil
+}
+
+func decodeValue(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	delimiter, isDelimiter := token.(json.Delim)
+	if !isDelimiter {
+		return token, nil
+	}
+	switch delimiter {
+	case '{':
+		result := newObject()
+		for decoder.More() {
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			result.set(keyToken.(string), value)
+		}
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		return result, nil
+	case '[':
+		list := []interface{}{}
+		for decoder.More() {
+			value, err := decodeValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		return list, nil
+	}
+	return nil, fmt.Errorf("unexpected %v", delimiter)
+}
+
+// resolvePointer finds the target of a local reference, "#" or a JSON pointer like "#/$defs/address"
+func resolvePointer(root interface{}, reference string) (interface{}, bool) {
+	if !strings.HasPrefix(reference, "#") {
+		return nil, false
+	}
+	current := root
+	pointer := strings.TrimPrefix(reference, "#")
+	if pointer == "" {
+		return current, true
+	}
+	for _, token := range pointerTokens(pointer) {
+		switch node := current.(type) {
+		case *object:
+			value, exists := node.values[token]
+			if !exists {
+				return nil, false
+			}
+			current = value
+		case []interface{}:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+			current = node[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func pointerTokens(pointer string) []string {
+	tokens := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for index, token := range tokens {
+		tokens[index] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return tokens
+}
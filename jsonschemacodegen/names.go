@@ -0,0 +1,76 @@
+package jsonschemacodegen
+
+import (
+	"strings"
+	"unicode"
+)
+
+// commonInitialisms are written in upper case in Go names, e.g. "user_id" becomes UserID
+var commonInitialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true, "EOF": true,
+	"GUID": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true,
+	"QPS": true, "RAM": true, "RPC": true, "SLA": true, "SMTP": true, "SQL": true, "SSH": true,
+	"TCP": true, "TLS": true, "TTL": true, "UDP": true, "UI": true, "UID": true, "UUID": true,
+	"URI": true, "URL": true, "UTF8": true, "VM": true, "XML": true, "XMPP": true, "XSRF": true, "XSS": true,
+}
+
+// exportedName converts a property name, a definition name or an enum value to an exported Go name:
+// words are split at non-alphanumeric characters and case changes, e.g. "created_at", "createdAt"
+// and "created-at" all become CreatedAt. The name is empty if there are no letters or digits.
+func exportedName(name string) string {
+	var builder strings.Builder
+	for _, word := range splitWords(name) {
+		upper := strings.ToUpper(word)
+		if commonInitialisms[upper] {
+			builder.WriteString(upper)
+			continue
+		}
+		runes := []rune(strings.ToLower(word))
+		runes[0] = unicode.ToUpper(runes[0])
+		builder.WriteString(string(runes))
+	}
+	return builder.String()
+}
+
+func splitWords(name string) []string {
+	var words []string
+	var current []rune
+	runes := []rune(name)
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, string(current))
+			current = nil
+		}
+	}
+	for index, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if len(current) > 0 && unicode.IsUpper(r) {
+			previous := current[len(current)-1]
+			// "userID" splits before I, "HTTPServer" splits before S
+			nextIsLower := index+1 < len(runes) && unicode.IsLower(runes[index+1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || (unicode.IsUpper(previous) && nextIsLower) {
+				flush()
+			}
+		}
+		current = append(current, r)
+	}
+	flush()
+	return words
+}
+
+// singularName names items of arrays, e.g. OrderItems have OrderItem items
+func singularName(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies") && len(name) > 3:
+		return strings.TrimSuffix(name, "ies") + "y"
+	case strings.HasSuffix(name, "sses"):
+		return strings.TrimSuffix(name, "es")
+	case strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss") && !strings.HasSuffix(name, "us") &&
+		!strings.HasSuffix(name, "is") && len(name) > 1:
+		return strings.TrimSuffix(name, "s")
+	}
+	return name + "Item"
+}
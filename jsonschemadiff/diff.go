@@ -0,0 +1,591 @@
+// Package jsonschemadiff compares two versions of a JSON schema. Every change is reported by the
+// JSON pointer of the changed schema location and is classified by the data it breaks:
+//   - backward breaking changes make data valid against the old schema invalid against the new one
+//     (e.g. a new required property), consumers reading with the new schema can't read old data;
+//   - forward breaking changes make data valid against the new schema invalid against the old one
+//     (e.g. a new enum value), consumers still reading with the old schema can't read new data.
+//
+// Example:
+//
+//	changes, err := jsonschemadiff.CompareJSON(oldSchema, newSchema)
+//	for _, change := range changes {
+//		fmt.Println(change.Path, change.Kind, change.BreaksBackward, change.BreaksForward)
+//	}
+//
+// Local references ("#/definitions/...", "#/$defs/...") are followed. Keywords which can't be compared
+// structurally (allOf, anyOf, oneOf, not, conditionals etc.) are reported as breaking in both directions
+// when they change.
+package jsonschemadiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// Kinds of changes
+const (
+	KindSchemaAdded                 = "schema_added"
+	KindSchemaRemoved               = "schema_removed"
+	KindTypeChanged                 = "type_changed"
+	KindPropertyAdded               = "property_added"
+	KindPropertyRemoved             = "property_removed"
+	KindRequiredAdded               = "required_added"
+	KindRequiredRemoved             = "required_removed"
+	KindEnumValuesAdded             = "enum_values_added"
+	KindEnumValuesRemoved           = "enum_values_removed"
+	KindEnumAdded                   = "enum_added"
+	KindEnumRemoved                 = "enum_removed"
+	KindConstraintTightened         = "constraint_tightened"
+	KindConstraintRelaxed           = "constraint_relaxed"
+	KindConstraintChanged           = "constraint_changed"
+	KindAdditionalPropertiesChanged = "additional_properties_changed"
+	KindKeywordChanged              = "keyword_changed"
+	KindAnnotationChanged           = "annotation_changed"
+)
+
+// Change is a difference between two versions of a schema
+type Change struct {
+	// Path is a JSON pointer to the changed location in the schema, e.g. /properties/address/required
+	Path    string `json:"path"`
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+	// BreaksBackward means that data valid against the old schema can be invalid against the new one
+	BreaksBackward bool `json:"breaks_backward"`
+	// BreaksForward means that data valid against the new schema can be invalid against the old one
+	BreaksForward bool `json:"breaks_forward"`
+}
+
+// IsBreaking tells if the change breaks data in any direction
+func (change Change) IsBreaking() bool {
+	return change.BreaksBackward || change.BreaksForward
+}
+
+// CompareJSON compares schemas in their JSON form
+func CompareJSON(oldSchema []byte, newSchema []byte) ([]Change, error) {
+	oldDocument, err := decode(oldSchema)
+	if err != nil {
+		return nil, fmt.Errorf("invalid old schema: %v", err)
+	}
+	newDocument, err := decode(newSchema)
+	if err != nil {
+		return nil, fmt.Errorf("invalid new schema: %v", err)
+	}
+	return Compare(oldDocument, newDocument), nil
+}
+
+// Compare compares schemas decoded from JSON with numbers decoded as json.Number or float64
+func Compare(oldSchema interface{}, newSchema interface{}) []Change {
+	comparator := &comparator{
+		oldRoot: oldSchema,
+		newRoot: newSchema,
+		visited: make(map[string]bool),
+	}
+	comparator.compare(oldSchema, newSchema, "")
+	return comparator.changes
+}
+
+func decode(document []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after the schema")
+	}
+	return value, nil
+}
+
+type comparator struct {
+	oldRoot interface{}
+	newRoot interface{}
+	// visited holds pairs of compared references, recursive schemas are compared once
+	visited map[string]bool
+	changes []Change
+}
+
+func (comparator *comparator) add(path string, kind string, backward bool, forward bool, format string, args ...interface{}) {
+	comparator.changes = append(comparator.changes, Change{
+		Path:           path,
+		Kind:           kind,
+		Message:        fmt.Sprintf(format, args...),
+		BreaksBackward: backward,
+		BreaksForward:  forward,
+	})
+}
+
+func (comparator *comparator) compare(oldSchema interface{}, newSchema interface{}, path string) {
+	oldSchema, oldReference := resolve(comparator.oldRoot, oldSchema)
+	newSchema, newReference := resolve(comparator.newRoot, newSchema)
+	if oldReference != "" || newReference != "" {
+		pair := oldReference + "|" + newReference
+		if comparator.visited[pair] {
+			return
+		}
+		comparator.visited[pair] = true
+	}
+
+	// Boolean schemas: true accepts everything like {}, false rejects everything
+	oldBool, oldIsBool := oldSchema.(bool)
+	newBool, newIsBool := newSchema.(bool)
+	if oldIsBool && !oldBool || newIsBool && !newBool {
+		switch {
+		case oldIsBool && newIsBool && oldBool == newBool:
+		case oldIsBool && !oldBool:
+			comparator.add(path, KindKeywordChanged, false, true, "schema no longer rejects all values")
+		default:
+			comparator.add(path, KindKeywordChanged, true, false, "schema rejects all values")
+		}
+		return
+	}
+	oldObject := asObject(oldSchema)
+	newObject := asObject(newSchema)
+
+	comparator.compareTypes(oldObject, newObject, path)
+	comparator.compareEnums(oldObject, newObject, path)
+	comparator.compareBounds(oldObject, newObject, path)
+	comparator.compareConstraints(oldObject, newObject, path)
+	comparator.compareObjects(oldObject, newObject, path)
+	comparator.compareArrays(oldObject, newObject, path)
+	comparator.compareOpaqueKeywords(oldObject, newObject, path)
+	comparator.compareAnnotations(oldObject, newObject, path)
+}
+
+// resolve follows local references, the reference is returned to detect recursion
+func resolve(root interface{}, schema interface{}) (interface{}, string) {
+	reference := ""
+	for depth := 0; depth < 32; depth++ {
+		object, isObject := schema.(map[string]interface{})
+		if !isObject {
+			return schema, reference
+		}
+		ref, hasRef := object["$ref"].(string)
+		if !hasRef || !strings.HasPrefix(ref, "#") {
+			return schema, reference
+		}
+		target, found := pointerGet(root, strings.TrimPrefix(ref, "#"))
+		if !found {
+			return schema, reference
+		}
+		reference = ref
+		schema = target
+	}
+	return schema, reference
+}
+
+func pointerGet(document interface{}, pointer string) (interface{}, bool) {
+	if pointer == "" {
+		return document, true
+	}
+	current := document
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		object, isObject := current.(map[string]interface{})
+		if !isObject {
+			return nil, false
+		}
+		next, exists := object[token]
+		if !exists {
+			return nil, false
+		}
+		current = next
+	}
+	return current, true
+}
+
+func asObject(schema interface{}) map[string]interface{} {
+	if object, isObject := schema.(map[string]interface{}); isObject {
+		return object
+	}
+	return map[string]interface{}{}
+}
+
+// typeSet returns types allowed by the schema, nil when any type is allowed
+func typeSet(schema map[string]interface{}) map[string]bool {
+	switch types := schema["type"].(type) {
+	case string:
+		return map[string]bool{types: true}
+	case []interface{}:
+		set := make(map[string]bool)
+		for _, schemaType := range types {
+			if name, isString := schemaType.(string); isString {
+				set[name] = true
+			}
+		}
+		return set
+	}
+	return nil
+}
+
+// uncoveredTypes returns types of the first set which are not allowed by the second set
+func uncoveredTypes(types map[string]bool, by map[string]bool) []string {
+	var uncovered []string
+	for schemaType := range types {
+		if by[schemaType] || (schemaType == "integer" && by["number"]) {
+			continue
+		}
+		uncovered = append(uncovered, schemaType)
+	}
+	sort.Strings(uncovered)
+	return uncovered
+}
+
+func describeTypes(types map[string]bool) string {
+	if types == nil {
+		return "any"
+	}
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func (comparator *comparator) compareTypes(oldSchema map[string]interface{}, newSchema map[string]interface{}, path string) {
+	oldTypes := typeSet(oldSchema)
+	newTypes := typeSet(newSchema)
+	if oldTypes == nil && newTypes == nil {
+		return
+	}
+	var narrowed, widened bool
+	switch {
+	case oldTypes == nil:
+		narrowed = true
+	case newTypes == nil:
+		widened = true
+	default:
+		narrowed = len(uncoveredTypes(oldTypes, newTypes)) > 0
+		widened = len(uncoveredTypes(newTypes, oldTypes)) > 0
+	}
+	if narrowed || widened {
+		comparator.add(path+"/type", KindTypeChanged, narrowed, widened,
+			"type changed from %s to %s", describeTypes(oldTypes), describeTypes(newTypes))
+	}
+}
+
+// enumValues returns canonical JSON of values allowed by enum or const, nil when there is no such restriction
+func enumValues(schema map[string]interface{}) map[string]bool {
+	var values []interface{}
+	if enum, isList := schema["enum"].([]interface{}); isList {
+		values = enum
+	} else if constValue, hasConst := schema["const"]; hasConst {
+		values = []interface{}{constValue}
+	} else {
+		return nil
+	}
+	set := make(map[string]bool)
+	for _, value := range values {
+		set[canonicalJSON(value)] = true
+	}
+	return set
+}
+
+func (comparator *comparator) compareEnums(oldSchema map[string]interface{}, newSchema map[string]interface{}, path string) {
+	oldValues := enumValues(oldSchema)
+	newValues := enumValues(newSchema)
+	_, oldHasEnum := oldSchema["enum"]
+	_, newHasEnum := newSchema["enum"]
+	keyword := "/enum"
+	if !oldHasEnum && !newHasEnum {
+		keyword = "/const"
+	}
+	switch {
+	case oldValues == nil && newValues == nil:
+	case oldValues == nil:
+		comparator.add(path+keyword, KindEnumAdded, true, false, "allowed values are restricted to %s", describeValues(newValues))
+	case newValues == nil:
+		comparator.add(path+keyword, KindEnumRemoved, false, true, "restriction of allowed values is removed")
+	default:
+		if removed := difference(oldValues, newValues); len(removed) > 0 {
+			comparator.add(path+keyword, KindEnumValuesRemoved, true, false, "allowed values removed: %s", strings.Join(removed, ", "))
+		}
+		if added := difference(newValues, oldValues); len(added) > 0 {
+			comparator.add(path+keyword, KindEnumValuesAdded, false, true, "allowed values added: %s", strings.Join(added, ", "))
+		}
+	}
+}
+
+func describeValues(values map[string]bool) string {
+	return strings.Join(difference(values, nil), ", ")
+}
+
+// difference returns sorted elements of the first set which are not in the second one
+func difference(set map[string]bool, other map[string]bool) []string {
+	var result []string
+	for value := range set {
+		if !other[value] {
+			result = append(result, value)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Lower and upper bounds. Raising a lower bound or lowering an upper bound tightens the schema.
+var (
+	lowerBoundKeywords = []string{"minimum", "exclusiveMinimum", "minLength", "minItems", "minProperties", "minContains"}
+	upperBoundKeywords = []string{"maximum", "exclusiveMaximum", "maxLength", "maxItems", "maxProperties", "maxContains"}
+)
+
+func (comparator *comparator) compareBounds(oldSchema map[string]interface{}, newSchema map[string]interface{}, path string) {
+	compare := func(keyword string, isLower bool) {
+		oldValue, oldExists := oldSchema[keyword]
+		newValue, newExists := newSchema[keyword]
+		if !oldExists && !newExists {
+			return
+		}
+		oldNumber, oldIsNumber := asNumber(oldValue)
+		newNumber, newIsNumber := asNumber(newValue)
+		switch {
+		case !oldExists:
+			comparator.add(path+"/"+keyword, KindConstraintTightened, true, false, "%s %s is added", keyword, formatValue(newValue))
+		case !newExists:
+			comparator.add(path+"/"+keyword, KindConstraintRelaxed, false, true, "%s %s is removed", keyword, formatValue(oldValue))
+		case !oldIsNumber || !newIsNumber:
+			// e.g. boolean exclusiveMinimum of draft 4
+			if canonicalJSON(oldValue) != canonicalJSON(newValue) {
+				comparator.add(path+"/"+keyword, KindConstraintChanged, true, true, "%s changed from %s to %s",
+					keyword, formatValue(oldValue), formatValue(newValue))
+			}
+		default:
+			order := oldNumber.Cmp(newNumber)
+			if order == 0 {
+				return
+			}
+			tightened := (order < 0) == isLower
+			if tightened {
+				comparator.add(path+"/"+keyword, KindConstraintTightened, true, false, "%s changed from %s to %s",
+					keyword, formatValue(oldValue), formatValue(newValue))
+			} else {
+				comparator.add(path+"/"+keyword, KindConstraintRelaxed, false, true, "%s changed from %s to %s",
+					keyword, formatValue(oldValue), formatValue(newValue))
+			}
+		}
+	}
+	for _, keyword := range lowerBoundKeywords {
+		compare(keyword, true)
+	}
+	for _, keyword := range upperBoundKeywords {
+		compare(keyword, false)
+	}
+}
+
+// Constraints which restrict values when added and can't be ordered when changed
+var constraintKeywords = []string{"pattern", "format", "multipleOf", "contentEncoding", "contentMediaType"}
+
+func (comparator *comparator) compareConstraints(oldSchema map[string]interface{}, newSchema map[string]interface{}, path string) {
+	for _, keyword := range constraintKeywords {
+		oldValue, oldExists := oldSchema[keyword]
+		newValue, newExists := newSchema[keyword]
+		switch {
+		case !oldExists && !newExists:
+		case !oldExists:
+			comparator.add(path+"/"+keyword, KindConstraintTightened, true, false, "%s %s is added", keyword, formatValue(newValue))
+		case !newExists:
+			comparator.add(path+"/"+keyword, KindConstraintRelaxed, false, true, "%s %s is removed", keyword, formatValue(oldValue))
+		case canonicalJSON(oldValue) != canonicalJSON(newValue):
+			comparator.add(path+"/"+keyword, KindConstraintChanged, true, true, "%s changed from %s to %s",
+				keyword, formatValue(oldValue), formatValue(newValue))
+		}
+	}
+
+	oldUnique, _ := oldSchema["uniqueItems"].(bool)
+	newUnique, _ := newSchema["uniqueItems"].(bool)
+	if !oldUnique && newUnique {
+		comparator.add(path+"/uniqueItems", KindConstraintTightened, true, false, "items must be unique")
+	} else if oldUnique && !newUnique {
+		comparator.add(path+"/uniqueItems", KindConstraintRelaxed, false, true, "items no longer must be unique")
+	}
+}
+
+func (comparator *comparator) compareObjects(oldSchema map[string]interface{}, newSchema map[string]interface{}, path string) {
+	oldRequired := stringSet(oldSchema["required"])
+	newRequired := stringSet(newSchema["required"])
+	for _, name := range difference(newRequired, oldRequired) {
+		comparator.add(path+"/required", KindRequiredAdded, true, false, "property %q is required", name)
+	}
+	for _, name := range difference(oldRequired, newRequired) {
+		comparator.add(path+"/required", KindRequiredRemoved, false, true, "property %q is no longer required", name)
+	}
+
+	oldProperties := asObject(oldSchema["properties"])
+	newProperties := asObject(newSchema["properties"])
+	oldClosed := closesAdditionalProperties(oldSchema)
+	newClosed := closesAdditionalProperties(newSchema)
+	for _, name := range sortedUnion(oldProperties, newProperties) {
+		propertyPath := path + "/properties/" + escapePointer(name)
+		oldProperty, oldExists := oldProperties[name]
+		newProperty, newExists := newProperties[name]
+		switch {
+		case oldExists && newExists:
+			comparator.compare(oldProperty, newProperty, propertyPath)
+		case newExists:
+			// Data of the new schema can contain the property, the old schema rejects it if its content model is closed
+			comparator.add(propertyPath, KindPropertyAdded, false, oldClosed, "property %q is added", name)
+		default:
+			// Data of the old schema can contain the property, the new schema rejects it if its content model is closed
+			comparator.add(propertyPath, KindPropertyRemoved, newClosed, false, "property %q is removed", name)
+		}
+	}
+
+	oldAdditional := oldSchema["additionalProperties"]
+	newAdditional := newSchema["additionalProperties"]
+	_, oldIsSchema := oldAdditional.(map[string]interface{})
+	_, newIsSchema := newAdditional.(map[string]interface{})
+	switch {
+	case oldIsSchema && newIsSchema:
+		comparator.compare(oldAdditional, newAdditional, path+"/additionalProperties")
+	case !oldClosed && newClosed:
+		comparator.add(path+"/additionalProperties", KindAdditionalPropertiesChanged, true, false,
+			"additional properties are no longer allowed")
+	case oldClosed && !newClosed:
+		comparator.add(path+"/additionalProperties", KindAdditionalPropertiesChanged, false, true,
+			"additional properties are allowed")
+	case oldIsSchema || newIsSchema:
+		// A schema of additional properties restricts them compared to true or no schema at all
+		comparator.add(path+"/additionalProperties", KindAdditionalPropertiesChanged, newIsSchema, oldIsSchema,
+			"schema of additional properties changed")
+	}
+}
+
+func closesAdditionalProperties(schema map[string]interface{}) bool {
+	additional, isBool := schema["additionalProperties"].(bool)
+	return isBool && !additional
+}
+
+func (comparator *comparator) compareArrays(oldSchema map[string]interface{}, newSchema map[string]interface{}, path string) {
+	oldItems, oldExists := oldSchema["items"]
+	newItems, newExists := newSchema["items"]
+	oldTuple, oldIsTuple := oldItems.([]interface{})
+	newTuple, newIsTuple := newItems.([]interface{})
+	switch {
+	case !oldExists && !newExists:
+	case oldIsTuple && newIsTuple:
+		for index := 0; index < len(oldTuple) || index < len(newTuple); index++ {
+			itemPath := fmt.Sprintf("%s/items/%d", path, index)
+			switch {
+			case index >= len(oldTuple):
+				comparator.add(itemPath, KindSchemaAdded, true, false, "schema of item %d is added", index)
+			case index >= len(newTuple):
+				comparator.add(itemPath, KindSchemaRemoved, false, true, "schema of item %d is removed", index)
+			default:
+				comparator.compare(oldTuple[index], newTuple[index], itemPath)
+			}
+		}
+	case oldIsTuple || newIsTuple:
+		comparator.add(path+"/items", KindKeywordChanged, true, true, "items changed between a list and a single schema")
+	case !oldExists:
+		comparator.compare(true, newItems, path+"/items")
+	case !newExists:
+		comparator.compare(oldItems, true, path+"/items")
+	default:
+		comparator.compare(oldItems, newItems, path+"/items")
+	}
+}
+
+// Keywords compared only by their content, any change can break data in both directions
+var opaqueKeywords = []string{
+	"allOf", "anyOf", "oneOf", "not", "if", "then", "else",
+	"dependencies", "dependentRequired", "dependentSchemas",
+	"patternProperties", "propertyNames", "contains", "prefixItems",
+	"additionalItems", "unevaluatedItems", "unevaluatedProperties",
+}
+
+func (comparator *comparator) compareOpaqueKeywords(oldSchema map[string]interface{}, newSchema map[string]interface{}, path string) {
+	for _, keyword := range opaqueKeywords {
+		oldValue, oldExists := oldSchema[keyword]
+		newValue, newExists := newSchema[keyword]
+		switch {
+		case !oldExists && !newExists:
+		case !oldExists:
+			comparator.add(path+"/"+keyword, KindKeywordChanged, true, true, "%s is added", keyword)
+		case !newExists:
+			comparator.add(path+"/"+keyword, KindKeywordChanged, true, true, "%s is removed", keyword)
+		case canonicalJSON(oldValue) != canonicalJSON(newValue):
+			comparator.add(path+"/"+keyword, KindKeywordChanged, true, true, "%s changed", keyword)
+		}
+	}
+}
+
+// Annotations don't affect validation
+var annotationKeywords = []string{"title", "description", "default", "examples", "deprecated", "readOnly", "writeOnly", "$comment"}
+
+func (comparator *comparator) compareAnnotations(oldSchema map[string]interface{}, newSchema map[string]interface{}, path string) {
+	for _, keyword := range annotationKeywords {
+		oldValue, oldExists := oldSchema[keyword]
+		newValue, newExists := newSchema[keyword]
+		switch {
+		case !oldExists && !newExists:
+		case !oldExists:
+			comparator.add(path+"/"+keyword, KindAnnotationChanged, false, false, "%s is added", keyword)
+		case !newExists:
+			comparator.add(path+"/"+keyword, KindAnnotationChanged, false, false, "%s is removed", keyword)
+		case canonicalJSON(oldValue) != canonicalJSON(newValue):
+			comparator.add(path+"/"+keyword, KindAnnotationChanged, false, false, "%s changed", keyword)
+		}
+	}
+}
+
+func stringSet(value interface{}) map[string]bool {
+	set := make(map[string]bool)
+	if list, isList := value.([]interface{}); isList {
+		for _, item := range list {
+			if name, isString := item.(string); isString {
+				set[name] = true
+			}
+		}
+	}
+	return set
+}
+
+func sortedUnion(first map[string]interface{}, second map[string]interface{}) []string {
+	keys := make(map[string]bool)
+	for key := range first {
+		keys[key] = true
+	}
+	for key := range second {
+		keys[key] = true
+	}
+	return difference(keys, nil)
+}
+
+func asNumber(value interface{}) (*big.Float, bool) {
+	var text string
+	switch number := value.(type) {
+	case json.Number:
+		text = number.String()
+	case float64:
+		return big.NewFloat(number), true
+	default:
+		return nil, false
+	}
+	parsed, _, err := big.ParseFloat(text, 10, 128, big.ToNearestEven)
+	if err != nil {
+		return nil, false
+	}
+	return parsed, true
+}
+
+// canonicalJSON encodes a value with sorted keys, so that equal values have equal encodings
+func canonicalJSON(value interface{}) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(encoded)
+}
+
+func formatValue(value interface{}) string {
+	return canonicalJSON(value)
+}
+
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
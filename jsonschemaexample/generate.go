@@ -0,0 +1,516 @@
+// Package jsonschemaexample generates example instances of JSON schemas. Examples honor types,
+// formats, enums and constants, numeric bounds, string lengths and patterns, required properties,
+// item counts and the "examples" keyword. Values of strings without formats are picked by names
+// of their properties where possible, e.g. "email" properties get email addresses.
+//
+//	data := fakedata.New(seed)
+//	example, err := jsonschemaexample.GenerateJSON(schema, data)
+//
+// Generation is best effort: combinations of keywords like "not" or overlapping "oneOf" branches
+// can produce invalid instances, callers are expected to validate examples when it matters.
+package jsonschemaexample
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fusioncatltd/fusioncat/fakedata"
+)
+
+// maxDepth limits nesting of generated values, deeper objects get only required properties
+// and arrays get only the minimal number of items, so that recursive schemas terminate
+const maxDepth = 8
+
+// GenerateJSON generates an example of the schema in its JSON form
+func GenerateJSON(schema []byte, data *fakedata.Generator) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(schema))
+	decoder.UseNumber()
+	var root interface{}
+	if err := decoder.Decode(&root); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %v", err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("invalid JSON: unexpected data after the schema")
+	}
+	return Generate(root, data), nil
+}
+
+// Generate generates an example of the decoded schema
+func Generate(schema interface{}, data *fakedata.Generator) interface{} {
+	generator := &generator{root: schema, data: data}
+	return generator.generate(schema, "", 0)
+}
+
+type generator struct {
+	root interface{}
+	data *fakedata.Generator
+}
+
+func (generator *generator) generate(schema interface{}, name string, depth int) interface{} {
+	object := generator.flatten(schema, 0)
+	if object == nil {
+		if allowed, isBool := schema.(bool); isBool && !allowed {
+			return nil
+		}
+		return generator.data.Word()
+	}
+
+	if constant, exists := object["const"]; exists {
+		return constant
+	}
+	if examples, isList := object["examples"].([]interface{}); isList && len(examples) > 0 {
+		return examples[generator.data.Intn(len(examples))]
+	}
+	if enum, isList := object["enum"].([]interface{}); isList && len(enum) > 0 {
+		return enum[generator.data.Intn(len(enum))]
+	}
+	for _, keyword := range []string{"oneOf", "anyOf"} {
+		if branches, isList := object[keyword].([]interface{}); isList && len(branches) > 0 {
+			branch := generator.flatten(branches[generator.data.Intn(len(branches))], 0)
+			merged := make(map[string]interface{}, len(object))
+			for key, value := range object {
+				if key != keyword {
+					merged[key] = value
+				}
+			}
+			return generator.generate(mergeSchemas(merged, branch), name, depth)
+		}
+	}
+
+	switch generator.pickType(object) {
+	case "null":
+		return nil
+	case "boolean":
+		return generator.data.Bool()
+	case "integer":
+		return generator.number(object, true)
+	case "number":
+		return generator.number(object, false)
+	case "object":
+		return generator.object(object, depth)
+	case "array":
+		return generator.array(object, name, depth)
+	}
+	return generator.string(object, name)
+}
+
+// flatten resolves references and merges allOf subschemas into a single schema object
+func (generator *generator) flatten(schema interface{}, level int) map[string]interface{} {
+	object, isObject := schema.(map[string]interface{})
+	if !isObject || level > maxDepth {
+		return nil
+	}
+	flattened := make(map[string]interface{}, len(object))
+	for key, value := range object {
+		if key != "$ref" && key != "allOf" {
+			flattened[key] = value
+		}
+	}
+	if reference, isString := object["$ref"].(string); isString {
+		if target, found := generator.resolve(reference); found {
+			flattened = mergeSchemas(flattened, generator.flatten(target, level+1))
+		}
+	}
+	if subschemas, isList := object["allOf"].([]interface{}); isList {
+		for _, subschema := range subschemas {
+			flattened = mergeSchemas(flattened, generator.flatten(subschema, level+1))
+		}
+	}
+	return flattened
+}
+
+// resolve finds targets of local references, "#" and JSON pointers like "#/$defs/address"
+func (generator *generator) resolve(reference string) (interface{}, bool) {
+	if !strings.HasPrefix(reference, "#") {
+		return nil, false
+	}
+	current := generator.root
+	pointer := strings.TrimPrefix(reference, "#")
+	if pointer == "" {
+		return current, true
+	}
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, exists := node[token]
+			if !exists {
+				return nil, false
+			}
+			current = value
+		case []interface{}:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+			current = node[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// mergeSchemas adds keywords of the extra schema to the base schema. Keywords of the base schema win,
+// except properties and required lists, which are combined.
+func mergeSchemas(base map[string]interface{}, extra map[string]interface{}) map[string]interface{} {
+	if extra == nil {
+		return base
+	}
+	for key, value := range extra {
+		existing, exists := base[key]
+		if !exists {
+			base[key] = value
+			continue
+		}
+		switch key {
+		case "properties":
+			existingProperties, existingIsObject := existing.(map[string]interface{})
+			extraProperties, extraIsObject := value.(map[string]interface{})
+			if existingIsObject && extraIsObject {
+				properties := make(map[string]interface{}, len(existingProperties)+len(extraProperties))
+				for name, property := range extraProperties {
+					properties[name] = property
+				}
+				for name, property := range existingProperties {
+					properties[name] = property
+				}
+				base[key] = properties
+			}
+		case "required":
+			existingRequired, existingIsList := existing.([]interface{})
+			extraRequired, extraIsList := value.([]interface{})
+			if existingIsList && extraIsList {
+				base[key] = append(append([]interface{}{}, existingRequired...), extraRequired...)
+			}
+		}
+	}
+	return base
+}
+
+// pickType chooses one of the allowed types, or infers the type from keywords of the schema
+func (generator *generator) pickType(object map[string]interface{}) string {
+	switch types := object["type"].(type) {
+	case string:
+		return types
+	case []interface{}:
+		var candidates []string
+		for _, candidate := range types {
+			if typeName, isString := candidate.(string); isString && typeName != "null" {
+				candidates = append(candidates, typeName)
+			}
+		}
+		if len(candidates) == 0 {
+			return "null"
+		}
+		return candidates[generator.data.Intn(len(candidates))]
+	}
+	for _, keywords := range []struct {
+		typeName string
+		keywords []string
+	}{
+		{"object", []string{"properties", "required", "additionalProperties", "minProperties", "maxProperties"}},
+		{"array", []string{"items", "prefixItems", "minItems", "maxItems", "contains", "uniqueItems"}},
+		{"number", []string{"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "multipleOf"}},
+	} {
+		for _, keyword := range keywords.keywords {
+			if _, exists := object[keyword]; exists {
+				return keywords.typeName
+			}
+		}
+	}
+	return "string"
+}
+
+func (generator *generator) string(object map[string]interface{}, name string) string {
+	minLength := intKeyword(object, "minLength", 0)
+	maxLength := intKeyword(object, "maxLength", -1)
+	fits := func(value string) bool {
+		length := utf8.RuneCountInString(value)
+		return length >= minLength && (maxLength < 0 || length <= maxLength)
+	}
+
+	if format, isString := object["format"].(string); isString {
+		if value, known := generator.data.Format(format); known && fits(value) {
+			return value
+		}
+	}
+	if pattern, isString := object["pattern"].(string); isString {
+		// Patterns can't be combined with lengths, a few attempts are made to fit them
+		for attempt := 0; attempt < 5; attempt++ {
+			value, err := generator.data.Pattern(pattern)
+			if err != nil {
+				break
+			}
+			if fits(value) {
+				return value
+			}
+		}
+	}
+	if value, known := generator.data.ForName(name); known && fits(value) {
+		return value
+	}
+	return generator.data.String(minLength, maxLength)
+}
+
+// number generates numbers within the bounds of the schema, multiples of multipleOf if it is set
+func (generator *generator) number(object map[string]interface{}, integer bool) interface{} {
+	minimum, hasMinimum := numberKeyword(object, "minimum")
+	maximum, hasMaximum := numberKeyword(object, "maximum")
+	exclusiveMinimum, hasExclusiveMinimum := numberKeyword(object, "exclusiveMinimum")
+	exclusiveMaximum, hasExclusiveMaximum := numberKeyword(object, "exclusiveMaximum")
+	// Draft 4 uses boolean exclusiveMinimum and exclusiveMaximum modifying minimum and maximum
+	if exclusive, isBool := object["exclusiveMinimum"].(bool); isBool && exclusive && hasMinimum {
+		exclusiveMinimum, hasExclusiveMinimum, hasMinimum = minimum, true, false
+	}
+	if exclusive, isBool := object["exclusiveMaximum"].(bool); isBool && exclusive && hasMaximum {
+		exclusiveMaximum, hasExclusiveMaximum, hasMaximum = maximum, true, false
+	}
+
+	low, high := math.Inf(-1), math.Inf(1)
+	lowExclusive, highExclusive := false, false
+	if hasMinimum {
+		low = minimum
+	}
+	if hasExclusiveMinimum && exclusiveMinimum >= low {
+		low, lowExclusive = exclusiveMinimum, true
+	}
+	if hasMaximum {
+		high = maximum
+	}
+	if hasExclusiveMaximum && exclusiveMaximum <= high {
+		high, highExclusive = exclusiveMaximum, true
+	}
+	switch {
+	case math.IsInf(low, -1) && math.IsInf(high, 1):
+		low, high = 1, 1000
+	case math.IsInf(low, -1):
+		low = high - 1000
+	case math.IsInf(high, 1):
+		high = low + 1000
+	}
+
+	multipleOf, hasMultipleOf := numberKeyword(object, "multipleOf")
+	if !hasMultipleOf || multipleOf <= 0 {
+		if !integer {
+			value := generator.data.FloatBetween(low, high)
+			if lowExclusive && value <= low {
+				value = low + (high-low)/2
+			}
+			return value
+		}
+		multipleOf = 1
+	}
+
+	// Values are multipleOf * k, decimal places of multipleOf are kept so that the values are exact
+	lowK := math.Ceil(low / multipleOf)
+	if lowExclusive && lowK*multipleOf <= low {
+		lowK++
+	}
+	highK := math.Floor(high / multipleOf)
+	if highExclusive && highK*multipleOf >= high {
+		highK--
+	}
+	if integer && multipleOf != math.Trunc(multipleOf) {
+		// Integers must also be multiples of multipleOf, stepping by the least common integer multiple is avoided
+		// for simplicity, the closest integer value is used
+		return int64(math.Round(lowK * multipleOf))
+	}
+	k := int64(lowK)
+	if highK > lowK {
+		k = generator.data.IntBetween(int64(lowK), int64(highK))
+	}
+	if integer {
+		return k * int64(multipleOf)
+	}
+	decimals := 0
+	if text := strconv.FormatFloat(multipleOf, 'f', -1, 64); strings.Contains(text, ".") {
+		decimals = len(text) - strings.Index(text, ".") - 1
+	}
+	value := new(big.Rat).Mul(new(big.Rat).SetInt64(k), new(big.Rat).SetFloat64(multipleOf))
+	return json.Number(value.FloatString(decimals))
+}
+
+func (generator *generator) object(object map[string]interface{}, depth int) map[string]interface{} {
+	properties, _ := object["properties"].(map[string]interface{})
+	required := map[string]bool{}
+	if requiredList, isList := object["required"].([]interface{}); isList {
+		for _, name := range requiredList {
+			if nameString, isString := name.(string); isString {
+				required[nameString] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(properties)+len(required))
+	for name := range properties {
+		names = append(names, name)
+	}
+	for name := range required {
+		if _, exists := properties[name]; !exists {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	minProperties := intKeyword(object, "minProperties", 0)
+	maxProperties := intKeyword(object, "maxProperties", -1)
+	result := make(map[string]interface{}, len(names))
+	var skipped []string
+	for _, name := range names {
+		include := required[name] || depth < maxDepth && generator.data.Chance(0.7)
+		if !include || maxProperties >= 0 && len(result) >= maxProperties && !required[name] {
+			skipped = append(skipped, name)
+			continue
+		}
+		result[name] = generator.generate(generator.propertySchema(object, properties, name), name, depth+1)
+	}
+	for _, name := range skipped {
+		if len(result) >= minProperties {
+			break
+		}
+		result[name] = generator.generate(generator.propertySchema(object, properties, name), name, depth+1)
+	}
+	for index := 1; len(result) < minProperties && !isFalse(object["additionalProperties"]); index++ {
+		name := fmt.Sprintf("%s_%d", generator.data.Word(), index)
+		result[name] = generator.generate(generator.propertySchema(object, properties, name), name, depth+1)
+	}
+
+	// Properties required by present properties
+	if dependentRequired, isObject := object["dependentRequired"].(map[string]interface{}); isObject {
+		for _, name := range sortedKeys(dependentRequired) {
+			dependents, isList := dependentRequired[name].([]interface{})
+			if _, present := result[name]; !present || !isList {
+				continue
+			}
+			for _, dependent := range dependents {
+				if dependentName, isString := dependent.(string); isString {
+					if _, present := result[dependentName]; !present {
+						result[dependentName] = generator.generate(
+							generator.propertySchema(object, properties, dependentName), dependentName, depth+1)
+					}
+				}
+			}
+		}
+	}
+	return result
+}
+
+// propertySchema returns the schema of a property, properties which are not declared
+// follow additionalProperties
+func (generator *generator) propertySchema(object map[string]interface{}, properties map[string]interface{},
+	name string) interface{} {
+	if schema, exists := properties[name]; exists {
+		return schema
+	}
+	if additional, isObject := object["additionalProperties"].(map[string]interface{}); isObject {
+		return additional
+	}
+	return map[string]interface{}{"type": "string"}
+}
+
+func (generator *generator) array(object map[string]interface{}, name string, depth int) []interface{} {
+	var prefixItems []interface{}
+	itemsSchema := object["items"]
+	if prefix, isList := object["prefixItems"].([]interface{}); isList {
+		prefixItems = prefix
+	} else if tuple, isList := object["items"].([]interface{}); isList {
+		// Draft 7 and earlier tuples, additionalItems describe the rest of items
+		prefixItems = tuple
+		itemsSchema = object["additionalItems"]
+	}
+
+	minItems := intKeyword(object, "minItems", 0)
+	maxItems := intKeyword(object, "maxItems", -1)
+	count := minItems
+	if depth < maxDepth {
+		count = minItems + generator.data.Intn(3)
+		if count == 0 {
+			count = 1
+		}
+	}
+	if maxItems >= 0 && count > maxItems {
+		count = maxItems
+	}
+	if isFalse(itemsSchema) && count > len(prefixItems) {
+		count = len(prefixItems)
+	}
+	if itemsSchema == nil {
+		itemsSchema = true
+	}
+
+	uniqueItems, _ := object["uniqueItems"].(bool)
+	itemName := fakedata.Singular(name)
+	items := make([]interface{}, 0, count)
+	seen := map[string]bool{}
+	for index := 0; index < count; index++ {
+		schema := itemsSchema
+		if index < len(prefixItems) {
+			schema = prefixItems[index]
+		}
+		item := generator.generate(schema, itemName, depth+1)
+		if uniqueItems {
+			for attempt := 0; attempt < 10 && seen[canonical(item)]; attempt++ {
+				item = generator.generate(schema, itemName, depth+1)
+			}
+			seen[canonical(item)] = true
+		}
+		items = append(items, item)
+	}
+	if contains, exists := object["contains"]; exists {
+		item := generator.generate(contains, itemName, depth+1)
+		if len(items) > 0 && len(items) > len(prefixItems) && (maxItems < 0 || len(items) >= maxItems) {
+			items[len(items)-1] = item
+		} else {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func intKeyword(object map[string]interface{}, keyword string, fallback int) int {
+	value, exists := numberKeyword(object, keyword)
+	if !exists || value < 0 {
+		return fallback
+	}
+	return int(value)
+}
+
+func numberKeyword(object map[string]interface{}, keyword string) (float64, bool) {
+	switch value := object[keyword].(type) {
+	case json.Number:
+		number, err := value.Float64()
+		return number, err == nil
+	case float64:
+		return value, true
+	}
+	return 0, false
+}
+
+func isFalse(schema interface{}) bool {
+	allowed, isBool := schema.(bool)
+	return isBool && !allowed
+}
+
+func sortedKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// canonical is the JSON form of a value, keys of objects are sorted by the encoder
+func canonical(value interface{}) string {
+	encoded, _ := json.Marshal(value)
+	return string(encoded)
+}
@@ -0,0 +1,351 @@
+// Package jsonschemainfer infers a draft 2020-12 JSON schema from sample instances. Types of the samples
+// are merged (integers and numbers become numbers, several types become a list of types), properties
+// present in every sample of an object are required, strings get a format when all of them match it
+// and an enum when they take a few values repeated across samples.
+//
+// Example:
+//
+//	samples, err := jsonschemainfer.DecodeNDJSON(upload)
+//	schema := jsonschemainfer.Infer(samples)
+//
+// Samples should be decoded with json.Decoder.UseNumber, so that integers are told from numbers.
+// Inferred schemas describe the samples only: they never restrict additional properties, lengths or
+// bounds of values, which can't be told from samples.
+package jsonschemainfer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Dialect is the value of "$schema" of inferred schemas
+const Dialect = "https://json-schema.org/draft/2020-12/schema"
+
+const (
+	// maxEnumValues is the number of distinct values of strings inferred as enums
+	maxEnumValues = 10
+	// minEnumRepeats is how many times values must be seen on average to be inferred as an enum,
+	// strings which rarely repeat are free text or identifiers
+	minEnumRepeats = 2
+	// maxEnumValueLength excludes long texts from enums
+	maxEnumValueLength = 50
+)
+
+// Types of JSON values in the order they are listed in "type"
+var typeOrder = []string{"object", "array", "string", "integer", "number", "boolean", "null"}
+
+// Formats in the order they are checked, more specific formats go first
+var formats = []struct {
+	name    string
+	matches func(value string) bool
+}{
+	{"date-time", isDateTime},
+	{"date", isDate},
+	{"time", isTime},
+	{"uuid", isUUID},
+	{"email", isEmail},
+	{"ipv4", isIPv4},
+	{"ipv6", isIPv6},
+	{"uri", isURI},
+}
+
+// node accumulates values seen at a location of the samples
+type node struct {
+	types map[string]int
+
+	// Objects
+	objects    int
+	properties map[string]*node
+
+	// Arrays
+	items *node
+
+	// Strings
+	strings        int
+	values         map[string]int
+	tooManyValues  bool
+	formatsMatched map[string]int
+}
+
+func newNode() *node {
+	return &node{types: make(map[string]int)}
+}
+
+func (n *node) add(value interface{}) {
+	switch typed := value.(type) {
+	case nil:
+		n.types["null"]++
+	case bool:
+		n.types["boolean"]++
+	case json.Number:
+		if _, err := typed.Int64(); err == nil {
+			n.types["integer"]++
+		} else if strings.ContainsAny(typed.String(), ".eE") {
+			n.types["number"]++
+		} else {
+			// Integers beyond int64 are integers still
+			n.types["integer"]++
+		}
+	case float64:
+		if typed == float64(int64(typed)) {
+			n.types["integer"]++
+		} else {
+			n.types["number"]++
+		}
+	case string:
+		n.types["string"]++
+		n.addString(typed)
+	case []interface{}:
+		n.types["array"]++
+		if n.items == nil {
+			n.items = newNode()
+		}
+		for _, item := range typed {
+			n.items.add(item)
+		}
+	case map[string]interface{}:
+		n.types["object"]++
+		n.objects++
+		if n.properties == nil {
+			n.properties = make(map[string]*node)
+		}
+		for name, propertyValue := range typed {
+			property, exists := n.properties[name]
+			if !exists {
+				property = newNode()
+				n.properties[name] = property
+			}
+			property.add(propertyValue)
+		}
+	}
+}
+
+func (n *node) addString(value string) {
+	n.strings++
+	if n.formatsMatched == nil {
+		n.formatsMatched = make(map[string]int)
+	}
+	for _, format := range formats {
+		if format.matches(value) {
+			n.formatsMatched[format.name]++
+		}
+	}
+
+	if n.tooManyValues {
+		return
+	}
+	if len(value) > maxEnumValueLength {
+		n.tooManyValues = true
+		n.values = nil
+		return
+	}
+	if n.values == nil {
+		n.values = make(map[string]int)
+	}
+	n.values[value]++
+	if len(n.values) > maxEnumValues {
+		n.tooManyValues = true
+		n.values = nil
+	}
+}
+
+// format returns the first format all strings match
+func (n *node) format() string {
+	for _, format := range formats {
+		if n.strings > 0 && n.formatsMatched[format.name] == n.strings {
+			return format.name
+		}
+	}
+	return ""
+}
+
+// enum returns sorted values of strings which look like an enumeration
+func (n *node) enum() []string {
+	if n.tooManyValues || len(n.values) == 0 || n.strings < minEnumRepeats*len(n.values) {
+		return nil
+	}
+	values := make([]string, 0, len(n.values))
+	for value := range n.values {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	return values
+}
+
+func (n *node) schema() map[string]interface{} {
+	schema := make(map[string]interface{})
+
+	var types []string
+	for _, name := range typeOrder {
+		// Integers are numbers, samples with both are numbers
+		if name == "integer" && n.types["number"] > 0 {
+			continue
+		}
+		if n.types[name] > 0 {
+			types = append(types, name)
+		}
+	}
+	switch len(types) {
+	case 0:
+		// No values were seen, anything is allowed
+		return schema
+	case 1:
+		schema["type"] = types[0]
+	default:
+		schema["type"] = types
+	}
+
+	if n.types["object"] > 0 {
+		properties := make(map[string]interface{}, len(n.properties))
+		required := make([]string, 0)
+		for name, property := range n.properties {
+			properties[name] = property.schema()
+			if property.count() == n.objects {
+				required = append(required, name)
+			}
+		}
+		schema["properties"] = properties
+		if len(required) > 0 {
+			sort.Strings(required)
+			schema["required"] = required
+		}
+	}
+	if n.types["array"] > 0 && n.items != nil && n.items.count() > 0 {
+		schema["items"] = n.items.schema()
+	}
+	if n.types["string"] > 0 {
+		if format := n.format(); format != "" {
+			schema["format"] = format
+		} else if enum := n.enum(); enum != nil && n.onlyStringsAndNulls() {
+			// Enums restrict values of all types, nulls are listed among the values
+			values := make([]interface{}, 0, len(enum)+1)
+			for _, value := range enum {
+				values = append(values, value)
+			}
+			if n.types["null"] > 0 {
+				values = append(values, nil)
+			}
+			schema["enum"] = values
+		}
+	}
+	return schema
+}
+
+func (n *node) onlyStringsAndNulls() bool {
+	return n.types["string"]+n.types["null"] == n.count()
+}
+
+// count returns the number of values seen
+func (n *node) count() int {
+	count := 0
+	for _, typeCount := range n.types {
+		count += typeCount
+	}
+	return count
+}
+
+// Infer infers a schema describing all samples
+func Infer(samples []interface{}) map[string]interface{} {
+	root := newNode()
+	for _, sample := range samples {
+		root.add(sample)
+	}
+	schema := root.schema()
+	schema["$schema"] = Dialect
+	return schema
+}
+
+// DecodeJSON decodes a single sample
+func DecodeJSON(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var sample interface{}
+	if err := decoder.Decode(&sample); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %v", err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("invalid JSON: unexpected data after the value")
+	}
+	return sample, nil
+}
+
+// DecodeNDJSON decodes newline delimited samples, blank lines are skipped
+func DecodeNDJSON(data []byte) ([]interface{}, error) {
+	var samples []interface{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		sample, err := DecodeJSON(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		samples = append(samples, sample)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return samples, nil
+}
+
+var (
+	uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	datePattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+)
+
+func isDateTime(value string) bool {
+	_, err := time.Parse(time.RFC3339Nano, value)
+	return err == nil
+}
+
+func isDate(value string) bool {
+	if !datePattern.MatchString(value) {
+		return false
+	}
+	_, err := time.Parse(time.DateOnly, value)
+	return err == nil
+}
+
+func isTime(value string) bool {
+	_, err := time.Parse("15:04:05.999999999Z07:00", value)
+	return err == nil
+}
+
+func isUUID(value string) bool {
+	return uuidPattern.MatchString(value)
+}
+
+func isEmail(value string) bool {
+	address, err := mail.ParseAddress(value)
+	return err == nil && address.Address == value && address.Name == ""
+}
+
+func isIPv4(value string) bool {
+	ip := net.ParseIP(value)
+	return ip != nil && ip.To4() != nil && strings.Count(value, ".") == 3
+}
+
+func isIPv6(value string) bool {
+	ip := net.ParseIP(value)
+	return ip != nil && strings.Contains(value, ":")
+}
+
+func isURI(value string) bool {
+	parsed, err := url.Parse(value)
+	return err == nil && parsed.Scheme != "" && parsed.Host != "" && !strings.ContainsAny(value, " \t\n")
+}
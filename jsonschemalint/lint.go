@@ -0,0 +1,468 @@
+// Package jsonschemalint checks JSON schemas against style rules which valid schemas can still break:
+// undocumented properties, objects open to any additional property, inconsistent property names, objects
+// without required properties, properties of any type and timestamps in inconsistent formats.
+// Every finding is reported by the JSON pointer of the schema location and the severity of its rule.
+//
+// Example:
+//
+//	config := jsonschemalint.DefaultConfig()
+//	config[jsonschemalint.RulePropertyNaming] = jsonschemalint.RuleConfig{
+//		Enabled: true, Severity: jsonschemalint.SeverityError, Option: jsonschemalint.NamingCamelCase}
+//	findings, err := jsonschemalint.LintJSON(schema, config)
+//
+// All subschemas are checked, definitions included. Local references ("#/$defs/...") are followed
+// to find descriptions and types of properties, referenced schemas are checked where they are defined.
+package jsonschemalint
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Severities of findings
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// Rules
+const (
+	RulePropertyDescription  = "property-description"
+	RuleAdditionalProperties = "additional-properties"
+	RulePropertyNaming       = "property-naming"
+	RuleRequiredDeclared     = "required-declared"
+	RuleNoAnyType            = "no-any-type"
+	RuleDateTimeFormat       = "date-time-format"
+)
+
+// Naming conventions of the property-naming rule
+const (
+	NamingSnakeCase = "snake_case"
+	NamingCamelCase = "camelCase"
+)
+
+// Rule describes a built-in rule. Rules with options take one of Options, DefaultOption by default.
+type Rule struct {
+	ID              string
+	Description     string
+	DefaultSeverity string
+	Options         []string
+	DefaultOption   string
+}
+
+// Rules are the built-in rules, all of them are enabled by default
+var Rules = []Rule{
+	{
+		ID:              RulePropertyDescription,
+		Description:     "Every property has a description",
+		DefaultSeverity: SeverityWarning,
+	},
+	{
+		ID:              RuleAdditionalProperties,
+		Description:     "Objects specify additionalProperties (or unevaluatedProperties)",
+		DefaultSeverity: SeverityWarning,
+	},
+	{
+		ID:              RulePropertyNaming,
+		Description:     "Property names follow the naming convention",
+		DefaultSeverity: SeverityWarning,
+		Options:         []string{NamingSnakeCase, NamingCamelCase},
+		DefaultOption:   NamingSnakeCase,
+	},
+	{
+		ID:              RuleRequiredDeclared,
+		Description:     "Objects declare their required properties",
+		DefaultSeverity: SeverityWarning,
+	},
+	{
+		ID:              RuleNoAnyType,
+		Description:     "Properties and items don't accept values of any type",
+		DefaultSeverity: SeverityWarning,
+	},
+	{
+		ID:              RuleDateTimeFormat,
+		Description:     "Timestamps are strings in the date-time, date or time format, the same in the whole schema",
+		DefaultSeverity: SeverityWarning,
+	},
+}
+
+// FindRule returns the built-in rule with the ID
+func FindRule(id string) (Rule, bool) {
+	for _, rule := range Rules {
+		if rule.ID == id {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+// RuleConfig enables a rule and sets the severity of its findings
+type RuleConfig struct {
+	Enabled  bool
+	Severity string
+	Option   string
+}
+
+// Config configures rules by their IDs, rules missing from it are disabled
+type Config map[string]RuleConfig
+
+// DefaultConfig enables all rules with their default severities and options
+func DefaultConfig() Config {
+	config := make(Config, len(Rules))
+	for _, rule := range Rules {
+		config[rule.ID] = RuleConfig{Enabled: true, Severity: rule.DefaultSeverity, Option: rule.DefaultOption}
+	}
+	return config
+}
+
+// Finding is a location of the schema breaking a rule
+type Finding struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	// Path is a JSON pointer to the location in the schema, e.g. /properties/created_at
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// LintJSON checks a schema in its JSON form
+func LintJSON(schema []byte, config Config) ([]Finding, error) {
+	decoder := json.NewDecoder(bytes.NewReader(schema))
+	decoder.UseNumber()
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return nil, fmt.Errorf("invalid schema: %v", err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("invalid schema: unexpected data after the schema")
+	}
+	return Lint(document, config), nil
+}
+
+// Lint checks a decoded schema, findings are ordered by path
+func Lint(document interface{}, config Config) []Finding {
+	linter := &linter{root: document, config: config}
+	linter.walk(document, "")
+	linter.checkTimestamps()
+	sort.SliceStable(linter.findings, func(i, j int) bool {
+		return linter.findings[i].Path < linter.findings[j].Path
+	})
+	return linter.findings
+}
+
+// Formats of timestamps
+var dateTimeFormats = map[string]bool{"date-time": true, "date": true, "time": true}
+
+// Formats which mean date-time but are not the standard one
+var dateTimeFormatMisspellings = map[string]bool{
+	"datetime": true, "date_time": true, "timestamp": true, "iso8601": true, "iso-8601": true, "rfc3339": true,
+}
+
+// Keywords which constrain values, schemas with none of them accept values of any type
+var constrainingKeywords = []string{
+	"type", "$ref", "$dynamicRef", "enum", "const", "properties", "items", "prefixItems",
+	"allOf", "anyOf", "oneOf", "not", "patternProperties", "additionalProperties", "if",
+}
+
+var (
+	snakeCasePattern = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+	camelCasePattern = regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`)
+)
+
+// timestamp is a property which holds a timestamp, kept until the whole schema is walked
+type timestamp struct {
+	path           string
+	name           string
+	representation string
+}
+
+const (
+	timestampAsString  = "string"
+	timestampAsNumber  = "number"
+	timestampAsUnknown = ""
+)
+
+type linter struct {
+	root       interface{}
+	config     Config
+	findings   []Finding
+	timestamps []timestamp
+}
+
+func (linter *linter) report(rule string, path string, message string) {
+	ruleConfig, configured := linter.config[rule]
+	if !configured || !ruleConfig.Enabled {
+		return
+	}
+	severity := ruleConfig.Severity
+	if severity == "" {
+		definition, _ := FindRule(rule)
+		severity = definition.DefaultSeverity
+	}
+	linter.findings = append(linter.findings, Finding{Rule: rule, Severity: severity, Path: path, Message: message})
+}
+
+// resolve follows local references, schemas which can't be resolved are returned as they are
+func (linter *linter) resolve(schema map[string]interface{}) map[string]interface{} {
+	for depth := 0; depth < 32; depth++ {
+		reference, isReference := schema["$ref"].(string)
+		if !isReference || !strings.HasPrefix(reference, "#") {
+			return schema
+		}
+		var target interface{} = linter.root
+		pointer := strings.TrimPrefix(strings.TrimPrefix(reference, "#"), "/")
+		if pointer != "" {
+			for _, token := range strings.Split(pointer, "/") {
+				token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+				object, isObject := target.(map[string]interface{})
+				if !isObject {
+					return schema
+				}
+				target = object[token]
+			}
+		}
+		resolved, isObject := target.(map[string]interface{})
+		if !isObject {
+			return schema
+		}
+		schema = resolved
+	}
+	return schema
+}
+
+// walk checks the schema and its subschemas
+func (linter *linter) walk(node interface{}, path string) {
+	schema, isObject := node.(map[string]interface{})
+	if !isObject {
+		return
+	}
+
+	if format, isString := schema["format"].(string); isString && dateTimeFormatMisspellings[strings.ToLower(format)] {
+		linter.report(RuleDateTimeFormat, path+"/format",
+			fmt.Sprintf("format %q is not a standard format, use \"date-time\"", format))
+	}
+
+	if properties, isObject := schema["properties"].(map[string]interface{}); isObject {
+		linter.checkObject(schema, properties, path)
+	}
+
+	if items, isObject := schema["items"].(map[string]interface{}); isObject {
+		if acceptsAnyType(items) {
+			linter.report(RuleNoAnyType, path+"/items", "items accept values of any type")
+		}
+	} else if items, isTrue := schema["items"].(bool); isTrue && items {
+		linter.report(RuleNoAnyType, path+"/items", "items accept values of any type")
+	}
+
+	for _, keyword := range []string{"properties", "patternProperties", "$defs", "definitions", "dependentSchemas"} {
+		subschemas, _ := schema[keyword].(map[string]interface{})
+		for _, name := range sortedKeys(subschemas) {
+			linter.walk(subschemas[name], path+"/"+keyword+"/"+escape(name))
+		}
+	}
+	for _, keyword := range []string{"items", "prefixItems", "allOf", "anyOf", "oneOf"} {
+		switch value := schema[keyword].(type) {
+		case map[string]interface{}:
+			linter.walk(value, path+"/"+keyword)
+		case []interface{}:
+			for index, item := range value {
+				linter.walk(item, fmt.Sprintf("%s/%s/%d", path, keyword, index))
+			}
+		}
+	}
+	for _, keyword := range []string{"additionalProperties", "unevaluatedProperties", "contains", "not", "if", "then", "else"} {
+		linter.walk(schema[keyword], path+"/"+keyword)
+	}
+}
+
+// checkObject checks an object schema and its properties
+func (linter *linter) checkObject(schema map[string]interface{}, properties map[string]interface{}, path string) {
+	_, hasAdditional := schema["additionalProperties"]
+	_, hasUnevaluated := schema["unevaluatedProperties"]
+	if !hasAdditional && !hasUnevaluated {
+		linter.report(RuleAdditionalProperties, path,
+			"additionalProperties is not specified, the object accepts any additional property")
+	}
+	if _, hasRequired := schema["required"]; !hasRequired && len(properties) > 0 {
+		linter.report(RuleRequiredDeclared, path, "required properties are not declared")
+	}
+
+	convention := linter.config[RulePropertyNaming].Option
+	if convention == "" {
+		convention = NamingSnakeCase
+	}
+	for _, name := range sortedKeys(properties) {
+		propertyPath := path + "/properties/" + escape(name)
+		if !followsConvention(name, convention) {
+			linter.report(RulePropertyNaming, propertyPath, fmt.Sprintf("property %q is not %s", name, convention))
+		}
+
+		property, isObject := properties[name].(map[string]interface{})
+		if !isObject {
+			if accepted, isBool := properties[name].(bool); isBool && accepted {
+				linter.report(RulePropertyDescription, propertyPath, fmt.Sprintf("property %q has no description", name))
+				linter.report(RuleNoAnyType, propertyPath, fmt.Sprintf("property %q accepts values of any type", name))
+			}
+			continue
+		}
+		resolved := linter.resolve(property)
+		if !hasDescription(property) && !hasDescription(resolved) {
+			linter.report(RulePropertyDescription, propertyPath, fmt.Sprintf("property %q has no description", name))
+		}
+		if acceptsAnyType(resolved) {
+			linter.report(RuleNoAnyType, propertyPath, fmt.Sprintf("property %q accepts values of any type", name))
+		}
+		if isTimestampName(name) {
+			linter.timestamps = append(linter.timestamps, timestamp{
+				path:           propertyPath,
+				name:           name,
+				representation: timestampRepresentation(resolved),
+			})
+		}
+	}
+}
+
+// checkTimestamps reports timestamps which are strings without a date-time format, and numeric
+// timestamps of schemas which have timestamps in strings as well
+func (linter *linter) checkTimestamps() {
+	hasStrings := false
+	for _, property := range linter.timestamps {
+		if property.representation == timestampAsString {
+			hasStrings = true
+		}
+	}
+	for _, property := range linter.timestamps {
+		switch property.representation {
+		case timestampAsUnknown:
+			linter.report(RuleDateTimeFormat, property.path,
+				fmt.Sprintf("timestamp %q should be a string in the date-time, date or time format", property.name))
+		case timestampAsNumber:
+			if hasStrings {
+				linter.report(RuleDateTimeFormat, property.path,
+					fmt.Sprintf("timestamp %q is a number while other timestamps are date-time strings", property.name))
+			}
+		}
+	}
+}
+
+// timestampRepresentation tells how a timestamp is represented. Strings without a date-time format
+// and schemas of other types are unknown representations.
+func timestampRepresentation(schema map[string]interface{}) string {
+	types := schemaTypes(schema)
+	if types["string"] {
+		if format, _ := schema["format"].(string); dateTimeFormats[format] {
+			return timestampAsString
+		}
+		return timestampAsUnknown
+	}
+	if types["integer"] || types["number"] {
+		return timestampAsNumber
+	}
+	if len(types) == 0 {
+		// Timestamps constrained by their format only
+		if format, _ := schema["format"].(string); dateTimeFormats[format] {
+			return timestampAsString
+		}
+	}
+	return timestampAsUnknown
+}
+
+func schemaTypes(schema map[string]interface{}) map[string]bool {
+	types := make(map[string]bool)
+	switch value := schema["type"].(type) {
+	case string:
+		types[value] = true
+	case []interface{}:
+		for _, item := range value {
+			if name, isString := item.(string); isString && name != "null" {
+				types[name] = true
+			}
+		}
+	}
+	return types
+}
+
+// isTimestampName tells if the property name looks like a timestamp: created_at, createdAt,
+// start_time, birthDate, timestamp
+func isTimestampName(name string) bool {
+	words := splitWords(name)
+	if len(words) == 0 {
+		return false
+	}
+	last := words[len(words)-1]
+	switch last {
+	case "date", "time", "timestamp", "datetime":
+		return true
+	case "at":
+		return len(words) > 1
+	}
+	return false
+}
+
+// splitWords splits snake_case, kebab-case and camelCase names into lowercase words
+func splitWords(name string) []string {
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, strings.ToLower(string(word)))
+			word = word[:0]
+		}
+	}
+	runes := []rune(name)
+	for index, r := range runes {
+		switch {
+		case r == '_' || r == '-' || r == '.' || unicode.IsSpace(r):
+			flush()
+		case unicode.IsUpper(r) && index > 0 && !unicode.IsUpper(runes[index-1]):
+			flush()
+			word = append(word, r)
+		default:
+			word = append(word, r)
+		}
+	}
+	flush()
+	return words
+}
+
+func followsConvention(name string, convention string) bool {
+	switch convention {
+	case NamingCamelCase:
+		return camelCasePattern.MatchString(name)
+	default:
+		return snakeCasePattern.MatchString(name)
+	}
+}
+
+func hasDescription(schema map[string]interface{}) bool {
+	description, _ := schema["description"].(string)
+	return strings.TrimSpace(description) != ""
+}
+
+func acceptsAnyType(schema map[string]interface{}) bool {
+	for _, keyword := range constrainingKeywords {
+		if _, exists := schema[keyword]; exists {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func escape(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
@@ -10,6 +10,11 @@ type AppResourceMessageObject struct {
 	dbModel db.AppResourceMessagesDBModel
 }
 
+// GetID returns the connection ID
+func (arm *AppResourceMessageObject) GetID() uuid.UUID {
+	return arm.dbModel.ID
+}
+
 // GetAppID returns the app ID
 func (arm *AppResourceMessageObject) GetAppID() uuid.UUID {
 	return arm.dbModel.AppID
@@ -0,0 +1,160 @@
+package logic
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Architecture files are read from local git repositories (bare or with a working tree) with the git
+// command line tool. Files are always read from a commit, never from the working tree, so uncommitted
+// changes are ignored. The git binary can be overridden with GIT_CMD. Git synchronization is disabled
+// unless GIT_SYNC_REPOSITORIES_ROOT is set, only repositories inside this directory can be used.
+
+const gitCommandTimeout = time.Minute
+
+var (
+	ErrGitSyncDisabled         = errors.New("git synchronization is disabled, GIT_SYNC_REPOSITORIES_ROOT is not set")
+	ErrGitRepositoryNotAllowed = errors.New("repository is outside of the allowed repositories root")
+)
+
+type gitRepository struct {
+	path string
+}
+
+// openGitRepository checks that the path points at a git repository the server is allowed to read.
+// Symbolic links are resolved before the path is compared with the repositories root, so links
+// inside the root can't point at repositories elsewhere on the server.
+func openGitRepository(repositoryPath string) (*gitRepository, error) {
+	allowedRoot := os.Getenv("GIT_SYNC_REPOSITORIES_ROOT")
+	if allowedRoot == "" {
+		return nil, ErrGitSyncDisabled
+	}
+	if !filepath.IsAbs(repositoryPath) {
+		return nil, fmt.Errorf("repository path must be absolute: %s", repositoryPath)
+	}
+	cleanPath := filepath.Clean(repositoryPath)
+	absoluteRoot, err := filepath.Abs(allowedRoot)
+	if err != nil || !isPathInside(absoluteRoot, cleanPath) {
+		return nil, ErrGitRepositoryNotAllowed
+	}
+
+	resolvedRoot, err := filepath.EvalSymlinks(absoluteRoot)
+	if err != nil {
+		return nil, fmt.Errorf("repositories root %s can't be read: %v", allowedRoot, err)
+	}
+	resolvedPath, err := filepath.EvalSymlinks(cleanPath)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a git repository", repositoryPath)
+	}
+	if !isPathInside(resolvedRoot, resolvedPath) {
+		return nil, ErrGitRepositoryNotAllowed
+	}
+
+	repository := &gitRepository{path: resolvedPath}
+	if _, err := repository.git("rev-parse", "--git-dir"); err != nil {
+		return nil, fmt.Errorf("%s is not a git repository", repositoryPath)
+	}
+	return repository, nil
+}
+
+// isPathInside tells if the clean absolute path is the root directory or is located in it
+func isPathInside(root string, path string) bool {
+	relativePath, err := filepath.Rel(root, path)
+	return err == nil && relativePath != ".." && !strings.HasPrefix(relativePath, "../")
+}
+
+// resolveCommit returns the SHA of the commit a branch, tag or commit SHA points at
+func (repository *gitRepository) resolveCommit(revision string) (string, error) {
+	if revision == "" || strings.HasPrefix(revision, "-") {
+		return "", fmt.Errorf("invalid revision: %q", revision)
+	}
+	output, err := repository.git("rev-parse", "--verify", "--quiet", revision+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("revision %s not found in the repository", revision)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// readFiles reads all files under the subdirectory at the commit. Names are relative to the subdirectory.
+func (repository *gitRepository) readFiles(commitSHA string, subdirectory string) (map[string]string, error) {
+	prefix := ""
+	if subdirectory != "" {
+		cleanSubdirectory, ok := cleanImportBundlePath(subdirectory)
+		if !ok {
+			return nil, fmt.Errorf("invalid subdirectory: %s", subdirectory)
+		}
+		prefix = cleanSubdirectory + "/"
+	}
+
+	args := []string{"ls-tree", "-r", "-z", "--long", "--full-tree", commitSHA}
+	if prefix != "" {
+		args = append(args, "--", prefix)
+	}
+	listing, err := repository.git(args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files of commit %s: %v", commitSHA, err)
+	}
+
+	files := make(map[string]string)
+	totalSize := 0
+	for _, entry := range bytes.Split(listing, []byte{0}) {
+		if len(entry) == 0 {
+			continue
+		}
+		// <mode> SP <type> SP <object> SP+ <size> TAB <path>
+		meta, name, found := strings.Cut(string(entry), "\t")
+		fields := strings.Fields(meta)
+		if !found || len(fields) != 4 || fields[1] != "blob" || fields[0] == "120000" {
+			continue // submodules and symlinks are not followed
+		}
+		size, _ := strconv.Atoi(fields[3])
+		totalSize += size
+		if totalSize > maxImportBundleSize {
+			return nil, ErrImportBundleTooLarge
+		}
+
+		content, err := repository.git("cat-file", "blob", fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", name, err)
+		}
+		files[strings.TrimPrefix(name, prefix)] = string(content)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no files found in %q at commit %s", subdirectory, commitSHA)
+	}
+	return files, nil
+}
+
+func (repository *gitRepository) git(args ...string) ([]byte, error) {
+	gitCmd := os.Getenv("GIT_CMD")
+	if gitCmd == "" {
+		gitCmd = "git"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), gitCommandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, gitCmd, append([]string{"-C", repository.path}, args...)...)
+	// Repositories are never discovered in parent directories of the configured path
+	cmd.Env = append(os.Environ(),
+		"GIT_TERMINAL_PROMPT=0",
+		"GIT_CEILING_DIRECTORIES="+filepath.Dir(repository.path))
+
+	var output bytes.Buffer
+	var stderrBuf bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &stderrBuf
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%v - stderr: %s", err, strings.TrimSpace(stderrBuf.String()))
+	}
+	return output.Bytes(), nil
+}
@@ -0,0 +1,355 @@
+package logic
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fusioncatltd/fusioncat/db"
+	"github.com/google/uuid"
+)
+
+// Lifecycle states of schemas, schema versions and messages
+const (
+	LifecycleDraft      = "draft"
+	LifecycleActive     = "active"
+	LifecycleDeprecated = "deprecated"
+	LifecycleRetired    = "retired"
+)
+
+// LifecycleDateFormat is the format of sunset dates
+const LifecycleDateFormat = "2006-01-02"
+
+// lifecycleTransitions lists the states which can follow each state besides the state itself.
+// Retired entities stay retired.
+var lifecycleTransitions = map[string][]string{
+	LifecycleDraft:      {LifecycleActive, LifecycleRetired},
+	LifecycleActive:     {LifecycleDeprecated, LifecycleRetired},
+	LifecycleDeprecated: {LifecycleActive, LifecycleRetired},
+	LifecycleRetired:    {},
+}
+
+// LifecycleUpdate is a requested change of the lifecycle of a schema, schema version or message.
+// The deprecation reason, replacement and sunset date can only be set for deprecated and retired entities.
+type LifecycleUpdate struct {
+	State             string
+	DeprecationReason string
+	ReplacedBy        string
+	// Sunset date in the YYYY-MM-DD format
+	SunsetAt string
+}
+
+// LifecycleError is returned for lifecycle updates which can't be applied, Field is the invalid input field
+type LifecycleError struct {
+	Field   string
+	Message string
+}
+
+func (err *LifecycleError) Error() string {
+	return err.Message
+}
+
+type LifecycleSerializerStruct struct {
+	State             string `json:"state"`
+	DeprecationReason string `json:"deprecation_reason,omitempty"`
+	ReplacedBy        string `json:"replaced_by,omitempty"`
+	SunsetAt          string `json:"sunset_at,omitempty"`
+}
+
+// lifecycleState returns the lifecycle state, records created before lifecycles were introduced are active
+func lifecycleState(fields db.LifecycleDBFields) string {
+	if fields.State == "" {
+		return LifecycleActive
+	}
+	return fields.State
+}
+
+// isDeprecatedLifecycle tells if users of the entity should move to its replacement
+func isDeprecatedLifecycle(fields db.LifecycleDBFields) bool {
+	state := lifecycleState(fields)
+	return state == LifecycleDeprecated || state == LifecycleRetired
+}
+
+func serializeLifecycle(fields db.LifecycleDBFields) *LifecycleSerializerStruct {
+	serialized := &LifecycleSerializerStruct{
+		State:             lifecycleState(fields),
+		DeprecationReason: fields.DeprecationReason,
+		ReplacedBy:        fields.ReplacedBy,
+	}
+	if fields.SunsetAt != nil {
+		serialized.SunsetAt = fields.SunsetAt.Format(LifecycleDateFormat)
+	}
+	return serialized
+}
+
+// applyLifecycleUpdate checks the update against the current lifecycle and returns the new lifecycle.
+// checkReplacement validates the replacement reference, it is called only when a replacement is set.
+func applyLifecycleUpdate(current db.LifecycleDBFields, update LifecycleUpdate,
+	checkReplacement func(replacedBy string) error) (db.LifecycleDBFields, error) {
+	currentState := lifecycleState(current)
+	if _, known := lifecycleTransitions[update.State]; !known {
+		return current, &LifecycleError{Field: "state", Message: fmt.Sprintf("unknown lifecycle state %q", update.State)}
+	}
+	if update.State != currentState {
+		allowed := false
+		for _, state := range lifecycleTransitions[currentState] {
+			if state == update.State {
+				allowed = true
+			}
+		}
+		if !allowed {
+			return current, &LifecycleError{Field: "state",
+				Message: fmt.Sprintf("lifecycle state can't change from %s to %s", currentState, update.State)}
+		}
+	}
+
+	updated := db.LifecycleDBFields{State: update.State}
+	deprecated := update.State == LifecycleDeprecated || update.State == LifecycleRetired
+	if !deprecated {
+		if update.DeprecationReason != "" || update.ReplacedBy != "" || update.SunsetAt != "" {
+			return current, &LifecycleError{Field: "state",
+				Message: "deprecation reason, replacement and sunset date can only be set for deprecated and retired entities"}
+		}
+		return updated, nil
+	}
+
+	updated.DeprecationReason = strings.TrimSpace(update.DeprecationReason)
+	if update.SunsetAt != "" {
+		sunsetAt, err := time.Parse(LifecycleDateFormat, update.SunsetAt)
+		if err != nil {
+			return current, &LifecycleError{Field: "sunset_at",
+				Message: fmt.Sprintf("sunset date %q is not in the YYYY-MM-DD format", update.SunsetAt)}
+		}
+		updated.SunsetAt = &sunsetAt
+	}
+	if update.ReplacedBy != "" {
+		if err := checkReplacement(update.ReplacedBy); err != nil {
+			return current, err
+		}
+		updated.ReplacedBy = update.ReplacedBy
+	}
+	return updated, nil
+}
+
+// saveLifecycle stores the lifecycle fields of a schema, schema version or message record
+func saveLifecycle(model interface{}, fields db.LifecycleDBFields) error {
+	return db.GetDB().Model(model).Updates(map[string]interface{}{
+		"lifecycle_state":    fields.State,
+		"deprecation_reason": fields.DeprecationReason,
+		"replaced_by":        fields.ReplacedBy,
+		"sunset_at":          fields.SunsetAt,
+	}).Error
+}
+
+// GetLifecycle returns the lifecycle of the schema
+func (schema *SchemaObject) GetLifecycle() *LifecycleSerializerStruct {
+	return serializeLifecycle(schema.dbModel.Lifecycle)
+}
+
+// IsRetired tells if the schema is retired and can't be used by new messages
+func (schema *SchemaObject) IsRetired() bool {
+	return lifecycleState(schema.dbModel.Lifecycle) == LifecycleRetired
+}
+
+// UpdateLifecycle changes the lifecycle state of the schema.
+// Schemas are replaced by other schemas of the project, referenced by name.
+func (schema *SchemaObject) UpdateLifecycle(update LifecycleUpdate) error {
+	fields, err := applyLifecycleUpdate(schema.dbModel.Lifecycle, update, func(replacedBy string) error {
+		var replacement db.SchemasDBModel
+		result := db.GetDB().Where("project_id = ? AND name = ? AND status = ?",
+			schema.dbModel.ProjectID, replacedBy, "active").Limit(1).Find(&replacement)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 || replacement.ID == schema.dbModel.ID {
+			return &LifecycleError{Field: "replaced_by",
+				Message: fmt.Sprintf("replacement schema %q is not another schema of the project", replacedBy)}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := saveLifecycle(&schema.dbModel, fields); err != nil {
+		return err
+	}
+	schema.dbModel.Lifecycle = fields
+	return nil
+}
+
+// GetLifecycle returns the lifecycle of the schema version
+func (schemaVersion *SchemaVersionObject) GetLifecycle() *LifecycleSerializerStruct {
+	return serializeLifecycle(schemaVersion.dbModel.Lifecycle)
+}
+
+// IsRetired tells if the schema version is retired and can't be used by new messages
+func (schemaVersion *SchemaVersionObject) IsRetired() bool {
+	return lifecycleState(schemaVersion.dbModel.Lifecycle) == LifecycleRetired
+}
+
+// UpdateLifecycle changes the lifecycle state of the schema version. Versions are replaced by other versions
+// of schemas of the project, referenced by their URIs, e.g. fusioncat://schemas/order_created/versions/2
+func (schemaVersion *SchemaVersionObject) UpdateLifecycle(update LifecycleUpdate) error {
+	schemaRecord, err := schemaVersion.getSchemaRecord()
+	if err != nil {
+		return err
+	}
+	fields, err := applyLifecycleUpdate(schemaVersion.dbModel.Lifecycle, update, func(replacedBy string) error {
+		key, ok := parseSchemaReferenceURI(replacedBy)
+		if !ok || strings.Contains(replacedBy, "#") {
+			return &LifecycleError{Field: "replaced_by", Message: fmt.Sprintf(
+				"replacement %q is not a schema version URI like %s", replacedBy, SchemaReferenceURI("name", 1))}
+		}
+		if key.name == schemaRecord.Name && key.version == schemaVersion.dbModel.Version {
+			return &LifecycleError{Field: "replaced_by", Message: "schema version can't replace itself"}
+		}
+		var count int64
+		err := db.GetDB().Model(&db.SchemaVersionsDBModel{}).
+			Joins("JOIN schemas ON schemas.id = schema_versions.schema_id").
+			Where("schemas.project_id = ? AND schemas.name = ? AND schemas.status = ? AND schema_versions.version = ?",
+				schemaRecord.ProjectID, key.name, "active", key.version).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return &LifecycleError{Field: "replaced_by",
+				Message: fmt.Sprintf("replacement schema version %q does not exist", replacedBy)}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := saveLifecycle(&schemaVersion.dbModel, fields); err != nil {
+		return err
+	}
+	schemaVersion.dbModel.Lifecycle = fields
+	return nil
+}
+
+// GetLifecycle returns the lifecycle of the message
+func (message *MessageObject) GetLifecycle() *LifecycleSerializerStruct {
+	return serializeLifecycle(message.dbModel.Lifecycle)
+}
+
+// IsDeprecated tells if the message is deprecated or retired
+func (message *MessageObject) IsDeprecated() bool {
+	return isDeprecatedLifecycle(message.dbModel.Lifecycle)
+}
+
+// UpdateLifecycle changes the lifecycle state of the message.
+// Messages are replaced by other messages of the project, referenced by name.
+func (message *MessageObject) UpdateLifecycle(update LifecycleUpdate) error {
+	fields, err := applyLifecycleUpdate(message.dbModel.Lifecycle, update, func(replacedBy string) error {
+		var replacement db.MessagesDBModel
+		result := db.GetDB().Where("project_id = ? AND name = ? AND status = ?",
+			message.dbModel.ProjectID, replacedBy, "active").Limit(1).Find(&replacement)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 || replacement.ID == message.dbModel.ID {
+			return &LifecycleError{Field: "replaced_by",
+				Message: fmt.Sprintf("replacement message %q is not another message of the project", replacedBy)}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := saveLifecycle(&message.dbModel, fields); err != nil {
+		return err
+	}
+	message.dbModel.Lifecycle = fields
+	return nil
+}
+
+// deprecationNotice describes a deprecated entity, e.g. "message order_created_v1 is deprecated: ..."
+// The subject is the kind and name of the entity, the notice is empty for entities which are not deprecated.
+func deprecationNotice(subject string, fields db.LifecycleDBFields) []string {
+	if !isDeprecatedLifecycle(fields) {
+		return nil
+	}
+	summary := fmt.Sprintf("%s is %s", subject, lifecycleState(fields))
+	if fields.DeprecationReason != "" {
+		summary += ": " + strings.TrimSuffix(fields.DeprecationReason, ".")
+	}
+	notice := []string{summary + "."}
+	if fields.ReplacedBy != "" {
+		notice = append(notice, fmt.Sprintf("Use %s instead.", fields.ReplacedBy))
+	}
+	if fields.SunsetAt != nil {
+		notice = append(notice, fmt.Sprintf("Sunset on %s.", fields.SunsetAt.Format(LifecycleDateFormat)))
+	}
+	return notice
+}
+
+// GoDeprecationComment returns the "// Deprecated:" paragraph of Go doc comments for deprecated and retired
+// messages, the comment is empty for other messages
+func (message *MessageObject) GoDeprecationComment() string {
+	return goDeprecationComment(deprecationNotice("message "+message.dbModel.Name, message.dbModel.Lifecycle))
+}
+
+func goDeprecationComment(notice []string) string {
+	if len(notice) == 0 {
+		return ""
+	}
+	lines := make([]string, 0, len(notice))
+	for i, line := range notice {
+		if i == 0 {
+			line = "Deprecated: " + line
+		}
+		lines = append(lines, "// "+strings.ReplaceAll(line, "\n", " "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// addGoDeprecationComment adds the deprecation paragraph to the doc comment of the type declaration
+// of the generated Go code
+func addGoDeprecationComment(code string, typeName string, notice []string) string {
+	comment := goDeprecationComment(notice)
+	if comment == "" {
+		return code
+	}
+	lines := strings.Split(code, "\n")
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "type "+typeName+" ") {
+			continue
+		}
+		// Deprecation is the last paragraph of existing doc comments
+		if i > 0 && strings.HasPrefix(lines[i-1], "//") {
+			comment = "//\n" + comment
+		}
+		lines[i] = comment + "\n" + line
+		return strings.Join(lines, "\n")
+	}
+	return code
+}
+
+// schemaDeprecationNotice describes the deprecation of the schema or, if the schema is not deprecated,
+// of the schema version
+func schemaDeprecationNotice(schema *db.SchemasDBModel, schemaVersion *db.SchemaVersionsDBModel) []string {
+	if notice := deprecationNotice("schema "+schema.Name, schema.Lifecycle); notice != nil {
+		return notice
+	}
+	if schemaVersion == nil {
+		return nil
+	}
+	return deprecationNotice(fmt.Sprintf("version %d of schema %s", schemaVersion.Version, schema.Name),
+		schemaVersion.Lifecycle)
+}
+
+// GetDeprecatedMessagesByName returns deprecated and retired messages of the project by name
+func (messagesManager *MessagesObjectsManager) GetDeprecatedMessagesByName(projectID uuid.UUID) (map[string]*MessageObject, error) {
+	var messages []db.MessagesDBModel
+	err := db.GetDB().Where("project_id = ? AND status = ? AND lifecycle_state IN ?",
+		projectID, "active", []string{LifecycleDeprecated, LifecycleRetired}).Find(&messages).Error
+	if err != nil {
+		return nil, err
+	}
+	deprecated := make(map[string]*MessageObject, len(messages))
+	for _, message := range messages {
+		deprecated[message.Name] = &MessageObject{dbModel: message}
+	}
+	return deprecated, nil
+}
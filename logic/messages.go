@@ -12,37 +12,41 @@ type MessageObject struct {
 type MessagesObjectsManager struct{}
 
 type MessageDBSerializerStruct struct {
-	ID            string `json:"id"`
-	ProjectID     string `json:"project_id"`
-	Name          string `json:"name"`
-	Description   string `json:"description"`
-	Status        string `json:"status"`
-	SchemaID      string `json:"schema_id"`
-	SchemaVersion int    `json:"schema_version"`
-	CreatedByID   string `json:"created_by_id"`
-	CreatedByName string `json:"created_by_name"`
-	CreatedAt     string `json:"created_at"`
+	ID                string                     `json:"id"`
+	ProjectID         string                     `json:"project_id"`
+	Name              string                     `json:"name"`
+	Description       string                     `json:"description"`
+	Status            string                     `json:"status"`
+	SchemaID          string                     `json:"schema_id"`
+	SchemaVersion     int                        `json:"schema_version"`
+	SchemaMessageType string                     `json:"schema_message_type,omitempty"`
+	CreatedByID       string                     `json:"created_by_id"`
+	CreatedByName     string                     `json:"created_by_name"`
+	CreatedAt         string                     `json:"created_at"`
+	Lifecycle         *LifecycleSerializerStruct `json:"lifecycle"`
 }
 
 // Serialize converts a MessageObject to its serialized form
 func (message *MessageObject) Serialize() *MessageDBSerializerStruct {
 	createdByName := ""
-	
+
 	userDbRecord := db.UsersDBModel{}
 	_ = db.GetDB().First(&userDbRecord, message.dbModel.CreatedByID)
 	createdByName = userDbRecord.Handle
-	
+
 	return &MessageDBSerializerStruct{
-		ID:            message.dbModel.ID.String(),
-		ProjectID:     message.dbModel.ProjectID.String(),
-		Name:          message.dbModel.Name,
-		Description:   message.dbModel.Description,
-		Status:        message.dbModel.Status,
-		SchemaID:      message.dbModel.SchemaID.String(),
-		SchemaVersion: message.dbModel.SchemaVersion,
-		CreatedByID:   message.dbModel.CreatedByID.String(),
-		CreatedByName: createdByName,
-		CreatedAt:     message.dbModel.CreatedAt.String(),
+		ID:                message.dbModel.ID.String(),
+		ProjectID:         message.dbModel.ProjectID.String(),
+		Name:              message.dbModel.Name,
+		Description:       message.dbModel.Description,
+		Status:            message.dbModel.Status,
+		SchemaID:          message.dbModel.SchemaID.String(),
+		SchemaVersion:     message.dbModel.SchemaVersion,
+		SchemaMessageType: message.dbModel.SchemaMessageType,
+		CreatedByID:       message.dbModel.CreatedByID.String(),
+		CreatedByName:     createdByName,
+		CreatedAt:         message.dbModel.CreatedAt.String(),
+		Lifecycle:         message.GetLifecycle(),
 	}
 }
 
@@ -66,6 +70,11 @@ func (message *MessageObject) GetSchemaVersion() int {
 	return message.dbModel.SchemaVersion
 }
 
+// GetSchemaMessageType returns the message type inside a protobuf schema, empty for other schemas
+func (message *MessageObject) GetSchemaMessageType() string {
+	return message.dbModel.SchemaMessageType
+}
+
 // GetAllMessagesInProject retrieves all messages in a project
 func (messagesManager *MessagesObjectsManager) GetAllMessagesInProject(projectID uuid.UUID) ([]MessageObject, error) {
 	var messages []db.MessagesDBModel
@@ -73,12 +82,12 @@ func (messagesManager *MessagesObjectsManager) GetAllMessagesInProject(projectID
 	if result.Error != nil {
 		return nil, result.Error
 	}
-	
+
 	var messageObjects []MessageObject
 	for _, message := range messages {
 		messageObjects = append(messageObjects, MessageObject{dbModel: message})
 	}
-	
+
 	return messageObjects, nil
 }
 
@@ -108,28 +117,30 @@ func (messagesManager *MessagesObjectsManager) CreateANewMessage(
 	name string,
 	schemaID uuid.UUID,
 	schemaVersion int,
+	schemaMessageType string,
 ) (*MessageObject, error) {
-	
+
 	connection := db.GetDB()
 	tx := connection.Begin()
-	
+
 	// Create a new message
 	newMessage := db.MessagesDBModel{
-		Name:          name,
-		Description:   description,
-		ProjectID:     projectID,
-		SchemaID:      schemaID,
-		SchemaVersion: schemaVersion,
-		Status:        "active",
-		CreatedByID:   userID,
+		Name:              name,
+		Description:       description,
+		ProjectID:         projectID,
+		SchemaID:          schemaID,
+		SchemaVersion:     schemaVersion,
+		SchemaMessageType: schemaMessageType,
+		Status:            "active",
+		CreatedByID:       userID,
 	}
-	
+
 	if err := tx.Create(&newMessage).Error; err != nil {
 		tx.Rollback()
 		return nil, err
 	}
-	
+
 	tx.Commit()
-	
+
 	return &MessageObject{dbModel: newMessage}, nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,264 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fusioncatltd/fusioncat/common"
+	"github.com/fusioncatltd/fusioncat/db"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// A project can follow architecture files kept in a git repository. Synchronization reads the files
+// at a commit of the configured branch and applies them with the import pipeline in the sync mode,
+// the commit SHA is recorded on the import and on the schema versions it creates.
+
+var ErrGitSyncNotConfigured = errors.New("git synchronization is not configured for the project")
+
+// How often the poller looks for projects which are due to be polled
+const gitSyncPollerTick = 10 * time.Second
+
+type ProjectGitSyncObject struct {
+	dbModel db.ProjectGitSyncsDBModel
+}
+
+type ProjectGitSyncDBSerializerStruct struct {
+	ProjectID           string `json:"project_id"`
+	RepositoryPath      string `json:"repository_path"`
+	Branch              string `json:"branch"`
+	Subdirectory        string `json:"subdirectory"`
+	RootFile            string `json:"root_file"`
+	PollIntervalSeconds int    `json:"poll_interval_seconds"`
+	ConfiguredByUserID  string `json:"configured_by_user_id"`
+	LastSyncedCommitSHA string `json:"last_synced_commit_sha,omitempty"`
+	LastSyncedAt        string `json:"last_synced_at,omitempty"`
+	LastImportID        string `json:"last_import_id,omitempty"`
+	CreatedAt           string `json:"created_at"`
+	UpdatedAt           string `json:"updated_at"`
+}
+
+func (gitSync *ProjectGitSyncObject) Serialize() *ProjectGitSyncDBSerializerStruct {
+	serialized := &ProjectGitSyncDBSerializerStruct{
+		ProjectID:           gitSync.dbModel.ProjectID.String(),
+		RepositoryPath:      gitSync.dbModel.RepositoryPath,
+		Branch:              gitSync.dbModel.Branch,
+		Subdirectory:        gitSync.dbModel.Subdirectory,
+		RootFile:            gitSync.dbModel.RootFile,
+		PollIntervalSeconds: gitSync.dbModel.PollIntervalSeconds,
+		ConfiguredByUserID:  gitSync.dbModel.ConfiguredByUserID.String(),
+		LastSyncedCommitSHA: gitSync.dbModel.LastSyncedCommitSHA,
+		CreatedAt:           gitSync.dbModel.CreatedAt.String(),
+		UpdatedAt:           gitSync.dbModel.UpdatedAt.String(),
+	}
+	if gitSync.dbModel.LastSyncedAt != nil {
+		serialized.LastSyncedAt = gitSync.dbModel.LastSyncedAt.String()
+	}
+	if gitSync.dbModel.LastImportID != nil {
+		serialized.LastImportID = gitSync.dbModel.LastImportID.String()
+	}
+	return serialized
+}
+
+// readBundle reads architecture files at the revision (the configured branch by default)
+func (gitSync *ProjectGitSyncObject) readBundle(revision string) (*ImportBundle, string, error) {
+	repository, err := openGitRepository(gitSync.dbModel.RepositoryPath)
+	if err != nil {
+		return nil, "", err
+	}
+	if revision == "" {
+		revision = gitSync.dbModel.Branch
+	}
+	commitSHA, err := repository.resolveCommit(revision)
+	if err != nil {
+		return nil, "", err
+	}
+	files, err := repository.readFiles(commitSHA, gitSync.dbModel.Subdirectory)
+	if err != nil {
+		return nil, "", err
+	}
+
+	bundle := &ImportBundle{}
+	for name, content := range files {
+		if err := bundle.AddFile(name, content); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := bundle.DetectRootFile(gitSync.dbModel.RootFile); err != nil {
+		return nil, "", err
+	}
+	return bundle, commitSHA, nil
+}
+
+type ProjectGitSyncObjectsManager struct {
+}
+
+func (manager *ProjectGitSyncObjectsManager) GetForProject(projectID uuid.UUID) (*ProjectGitSyncObject, error) {
+	gitSyncDbRecord := db.ProjectGitSyncsDBModel{}
+	dbResult := db.GetDB().Where("project_id = ?", projectID).First(&gitSyncDbRecord)
+
+	if dbResult.Error != nil {
+		return nil, common.FusioncatErrRecordNotFound
+	}
+
+	return &ProjectGitSyncObject{dbModel: gitSyncDbRecord}, nil
+}
+
+// Configure creates or replaces git synchronization settings of the project.
+// The repository and the branch are checked before the settings are saved.
+func (manager *ProjectGitSyncObjectsManager) Configure(
+	projectID uuid.UUID,
+	userID uuid.UUID,
+	repositoryPath string,
+	branch string,
+	subdirectory string,
+	rootFile string,
+	pollIntervalSeconds int) (*ProjectGitSyncObject, error) {
+	repository, err := openGitRepository(repositoryPath)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := repository.resolveCommit(branch); err != nil {
+		return nil, err
+	}
+	if subdirectory != "" {
+		if _, ok := cleanImportBundlePath(subdirectory); !ok {
+			return nil, fmt.Errorf("invalid subdirectory: %s", subdirectory)
+		}
+	}
+
+	gitSyncDbRecord := db.ProjectGitSyncsDBModel{}
+	db.GetDB().Where("project_id = ?", projectID).First(&gitSyncDbRecord)
+
+	gitSyncDbRecord.ProjectID = projectID
+	gitSyncDbRecord.ConfiguredByUserID = userID
+	gitSyncDbRecord.RepositoryPath = filepath.Clean(repositoryPath)
+	gitSyncDbRecord.Branch = branch
+	gitSyncDbRecord.Subdirectory = subdirectory
+	gitSyncDbRecord.RootFile = rootFile
+	gitSyncDbRecord.PollIntervalSeconds = pollIntervalSeconds
+
+	if err := db.GetDB().Save(&gitSyncDbRecord).Error; err != nil {
+		return nil, err
+	}
+	return manager.GetForProject(projectID)
+}
+
+// Remove stops synchronization of the project, entities created by previous synchronizations are kept
+func (manager *ProjectGitSyncObjectsManager) Remove(projectID uuid.UUID) error {
+	dbResult := db.GetDB().Unscoped().Where("project_id = ?", projectID).Delete(&db.ProjectGitSyncsDBModel{})
+	if dbResult.Error != nil {
+		return dbResult.Error
+	}
+	if dbResult.RowsAffected == 0 {
+		return ErrGitSyncNotConfigured
+	}
+	return nil
+}
+
+// Sync applies architecture files at the revision (the head of the configured branch if empty)
+// to the project. Failed synchronizations are recorded as failed import jobs like any other import.
+func (manager *ProjectGitSyncObjectsManager) Sync(
+	projectID uuid.UUID,
+	userID uuid.UUID,
+	revision string) (*ProjectImportJobObject, error) {
+	gitSync, err := manager.GetForProject(projectID)
+	if err != nil {
+		return nil, ErrGitSyncNotConfigured
+	}
+
+	bundle, commitSHA, err := gitSync.readBundle(revision)
+	if err != nil {
+		return nil, err
+	}
+
+	importsManager := ProjectImportJobsObjectsManager{}
+	job, err := importsManager.RunSync(bundle, projectID, userID, commitSHA)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.GetStatus() == ImportJobStatusCompleted {
+		now := time.Now()
+		jobID := job.GetID()
+		db.GetDB().Model(&db.ProjectGitSyncsDBModel{}).
+			Where("project_id = ?", projectID).
+			Updates(map[string]interface{}{
+				"last_synced_commit_sha": commitSHA,
+				"last_synced_at":         &now,
+				"last_import_id":         &jobID,
+			})
+	}
+	return job, nil
+}
+
+// StartGitSyncPolling periodically synchronizes projects with a poll interval when the head
+// of their branch moves to a new commit. Synchronizations run on behalf of the user who
+// configured them. Nothing is polled while git synchronization is disabled.
+func StartGitSyncPolling(ctx context.Context) {
+	if os.Getenv("GIT_SYNC_REPOSITORIES_ROOT") == "" {
+		log.Info("Git synchronization is disabled, GIT_SYNC_REPOSITORIES_ROOT is not set")
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(gitSyncPollerTick)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				pollGitSyncs()
+			}
+		}
+	}()
+}
+
+func pollGitSyncs() {
+	var gitSyncs []db.ProjectGitSyncsDBModel
+	err := db.GetDB().
+		Where("poll_interval_seconds > 0").
+		Where("last_polled_at IS NULL OR last_polled_at + poll_interval_seconds * interval '1 second' <= now()").
+		Find(&gitSyncs).Error
+	if err != nil {
+		log.Errorf("Failed to load git synchronizations to poll: %v", err)
+		return
+	}
+
+	manager := ProjectGitSyncObjectsManager{}
+	for _, gitSyncDbRecord := range gitSyncs {
+		polledAt := map[string]interface{}{"last_polled_at": time.Now()}
+		repository, err := openGitRepository(gitSyncDbRecord.RepositoryPath)
+		if err != nil {
+			db.GetDB().Model(&db.ProjectGitSyncsDBModel{}).Where("id = ?", gitSyncDbRecord.ID).UpdateColumns(polledAt)
+			log.Warnf("Git synchronization of project %s: %v", gitSyncDbRecord.ProjectID, err)
+			continue
+		}
+		headSHA, err := repository.resolveCommit(gitSyncDbRecord.Branch)
+		if err == nil {
+			polledAt["last_polled_commit_sha"] = headSHA
+		}
+		db.GetDB().Model(&db.ProjectGitSyncsDBModel{}).Where("id = ?", gitSyncDbRecord.ID).UpdateColumns(polledAt)
+		if err != nil {
+			log.Warnf("Git synchronization of project %s: %v", gitSyncDbRecord.ProjectID, err)
+			continue
+		}
+
+		// Every commit is tried once, a commit which failed to synchronize is not retried until the branch moves
+		if headSHA == gitSyncDbRecord.LastSyncedCommitSHA || headSHA == gitSyncDbRecord.LastPolledCommitSHA {
+			continue
+		}
+
+		job, err := manager.Sync(gitSyncDbRecord.ProjectID, gitSyncDbRecord.ConfiguredByUserID, headSHA)
+		if err != nil {
+			log.Warnf("Git synchronization of project %s: %v", gitSyncDbRecord.ProjectID, err)
+			continue
+		}
+		log.Infof("Project %s synchronized with commit %s, import %s is %s",
+			gitSyncDbRecord.ProjectID, headSHA, job.GetID(), job.GetStatus())
+	}
+}
@@ -0,0 +1,578 @@
+package logic
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/fusioncatltd/fusioncat/fusionlang"
+	"gopkg.in/yaml.v3"
+)
+
+// Architecture files can be split into several files. The root file lists other files
+// under the top-level "include" key, and schemas may keep their content in a separate file
+// referenced by the "file" key instead of inline "schema". YAML, JSON and Fusionlang (.fusion) files
+// are accepted and can include each other.
+//
+//	version: 1
+//	include:
+//	  - teams/payments.yaml
+//	schemas:
+//	  - name: order
+//	    type: jsonschema
+//	    file: schemas/order.json
+
+// Default names of the root file, checked in this order when the root is not specified explicitly
+var defaultImportRootFiles = []string{"fusioncat.yaml", "fusioncat.yml", "fusioncat.json", "fusioncat.fusion"}
+
+// Formats of architecture files. YAML and JSON share the parser, Fusionlang sources are compiled
+// into the same document model. The format of files is detected by their extension.
+const (
+	ImportFormatYAML       = "yaml"
+	ImportFormatJSON       = "json"
+	ImportFormatFusionlang = "fusionlang"
+)
+
+// Top-level sections which are merged from included files into the root document
+var mergeableImportSections = []string{"servers", "schemas", "messages", "apps"}
+
+// Hard limit for the total unpacked size of a bundle to protect against archive bombs
+const maxImportBundleSize = 32 << 20
+
+var ErrImportBundleTooLarge = errors.New("import bundle is too large")
+
+// ImportBundle is a set of architecture files addressed by their slash-separated relative paths.
+// An empty RootFile name means that the root document was submitted inline (e.g. as a JSON field),
+// in this case issues found in the root document are reported without a file name and RootFormat
+// tells how to parse it (YAML is assumed by default).
+type ImportBundle struct {
+	RootFile   string
+	RootFormat string
+	Files      map[string]string
+}
+
+// NewSingleFileImportBundle wraps a single inline architecture document into a bundle
+func NewSingleFileImportBundle(content string) *ImportBundle {
+	return &ImportBundle{
+		RootFile: "",
+		Files:    map[string]string{"": content},
+	}
+}
+
+// AddFile adds a file to the bundle. The name is normalized and must stay inside the bundle.
+func (bundle *ImportBundle) AddFile(name string, content string) error {
+	cleanName, ok := cleanImportBundlePath(name)
+	if !ok {
+		return fmt.Errorf("invalid file name in import bundle: %s", name)
+	}
+	if bundle.Files == nil {
+		bundle.Files = make(map[string]string)
+	}
+	bundle.Files[cleanName] = content
+	return nil
+}
+
+// DetectRootFile picks the root file of the bundle. An explicitly requested root has priority,
+// then default names are checked and finally a single top-level architecture file is used.
+func (bundle *ImportBundle) DetectRootFile(requestedRoot string) error {
+	if requestedRoot != "" {
+		cleanRoot, ok := cleanImportBundlePath(requestedRoot)
+		if !ok {
+			return fmt.Errorf("invalid root file name: %s", requestedRoot)
+		}
+		if _, exists := bundle.Files[cleanRoot]; !exists {
+			return fmt.Errorf("root file '%s' not found in the import bundle", requestedRoot)
+		}
+		bundle.RootFile = cleanRoot
+		return nil
+	}
+
+	for _, name := range defaultImportRootFiles {
+		if _, exists := bundle.Files[name]; exists {
+			bundle.RootFile = name
+			return nil
+		}
+	}
+
+	var candidates []string
+	for name := range bundle.Files {
+		if !strings.Contains(name, "/") && isArchitectureFileName(name) {
+			candidates = append(candidates, name)
+		}
+	}
+	if len(candidates) != 1 {
+		sort.Strings(candidates)
+		return fmt.Errorf("can't detect the root file of the import bundle (candidates: %s), "+
+			"please specify it explicitly or name it %s", strings.Join(candidates, ", "), defaultImportRootFiles[0])
+	}
+	bundle.RootFile = candidates[0]
+	return nil
+}
+
+// NewImportBundleFromArchive unpacks a zip, tar or gzipped tar archive into a bundle.
+// If all files share a single top-level directory (as produced by "tar czf x.tgz dir/"),
+// this directory is stripped from the names.
+func NewImportBundleFromArchive(archiveName string, data []byte) (*ImportBundle, error) {
+	files := make(map[string]string)
+	var err error
+
+	lowerName := strings.ToLower(archiveName)
+	switch {
+	case strings.HasSuffix(lowerName, ".zip") || bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		err = readZipImportArchive(data, files)
+	case strings.HasSuffix(lowerName, ".tar.gz") || strings.HasSuffix(lowerName, ".tgz") ||
+		bytes.HasPrefix(data, []byte{0x1f, 0x8b}):
+		gzipReader, gzipErr := gzip.NewReader(bytes.NewReader(data))
+		if gzipErr != nil {
+			return nil, fmt.Errorf("failed to read gzip archive: %v", gzipErr)
+		}
+		err = readTarImportArchive(gzipReader, files)
+	case strings.HasSuffix(lowerName, ".tar"):
+		err = readTarImportArchive(bytes.NewReader(data), files)
+	default:
+		return nil, fmt.Errorf("unsupported archive format: %s (zip, tar and tar.gz are supported)", archiveName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("archive %s doesn't contain any files", archiveName)
+	}
+
+	return &ImportBundle{Files: stripCommonImportDirectory(files)}, nil
+}
+
+func readZipImportArchive(data []byte, files map[string]string) error {
+	zipReader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("failed to read zip archive: %v", err)
+	}
+
+	var totalSize int64
+	for _, file := range zipReader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		reader, err := file.Open()
+		if err != nil {
+			return fmt.Errorf("failed to read %s from zip archive: %v", file.Name, err)
+		}
+		content, err := io.ReadAll(io.LimitReader(reader, maxImportBundleSize-totalSize+1))
+		_ = reader.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read %s from zip archive: %v", file.Name, err)
+		}
+		totalSize += int64(len(content))
+		if totalSize > maxImportBundleSize {
+			return ErrImportBundleTooLarge
+		}
+		if err := addImportArchiveFile(files, file.Name, content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readTarImportArchive(source io.Reader, files map[string]string) error {
+	tarReader := tar.NewReader(source)
+
+	var totalSize int64
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read tar archive: %v", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		content, err := io.ReadAll(io.LimitReader(tarReader, maxImportBundleSize-totalSize+1))
+		if err != nil {
+			return fmt.Errorf("failed to read %s from tar archive: %v", header.Name, err)
+		}
+		totalSize += int64(len(content))
+		if totalSize > maxImportBundleSize {
+			return ErrImportBundleTooLarge
+		}
+		if err := addImportArchiveFile(files, header.Name, content); err != nil {
+			return err
+		}
+	}
+}
+
+func addImportArchiveFile(files map[string]string, name string, content []byte) error {
+	cleanName, ok := cleanImportBundlePath(name)
+	if !ok {
+		return fmt.Errorf("invalid file name in archive: %s", name)
+	}
+	// Skip metadata produced by macOS archivers
+	if strings.HasPrefix(cleanName, "__MACOSX/") || path.Base(cleanName) == ".DS_Store" {
+		return nil
+	}
+	files[cleanName] = string(content)
+	return nil
+}
+
+func stripCommonImportDirectory(files map[string]string) map[string]string {
+	commonDir := ""
+	for name := range files {
+		slash := strings.Index(name, "/")
+		if slash < 0 {
+			return files
+		}
+		if commonDir == "" {
+			commonDir = name[:slash+1]
+		} else if !strings.HasPrefix(name, commonDir) {
+			return files
+		}
+	}
+
+	stripped := make(map[string]string, len(files))
+	for name, content := range files {
+		stripped[strings.TrimPrefix(name, commonDir)] = content
+	}
+	return stripped
+}
+
+// cleanImportBundlePath normalizes a relative path and rejects paths escaping the bundle
+func cleanImportBundlePath(name string) (string, bool) {
+	name = strings.ReplaceAll(strings.TrimSpace(name), "\\", "/")
+	cleanName := path.Clean(strings.TrimPrefix(name, "/"))
+	if cleanName == "." || cleanName == ".." || strings.HasPrefix(cleanName, "../") {
+		return "", false
+	}
+	return cleanName, true
+}
+
+func isArchitectureFileName(name string) bool {
+	extension := strings.ToLower(path.Ext(name))
+	return extension == ".yaml" || extension == ".yml" || extension == ".json" || extension == ".fusion"
+}
+
+// fileFormat returns the format of a file in the bundle
+func (bundle *ImportBundle) fileFormat(fileName string) string {
+	if fileName == "" && bundle.RootFile == "" {
+		if bundle.RootFormat == "" {
+			return ImportFormatYAML
+		}
+		return bundle.RootFormat
+	}
+	switch strings.ToLower(path.Ext(fileName)) {
+	case ".fusion":
+		return ImportFormatFusionlang
+	case ".json":
+		return ImportFormatJSON
+	}
+	return ImportFormatYAML
+}
+
+// resolvedImportBundle is the result of merging all files of a bundle into a single document.
+// Nodes keep positions from their original files, nodeFiles maps nodes to the file they came from.
+type resolvedImportBundle struct {
+	document      *yaml.Node
+	nodeFiles     map[*yaml.Node]string
+	projectImport ProjectImportYAML
+}
+
+type importBundleResolver struct {
+	bundle    *ImportBundle
+	nodeFiles map[*yaml.Node]string
+	included  map[string]bool
+	stack     []string
+	issues    ImportValidationIssues
+}
+
+// resolve parses the root file, merges included files into it and loads schemas kept in separate files
+func (bundle *ImportBundle) resolve() (*resolvedImportBundle, ImportValidationIssues) {
+	resolver := &importBundleResolver{
+		bundle:    bundle,
+		nodeFiles: make(map[*yaml.Node]string),
+		included:  map[string]bool{bundle.RootFile: true},
+		issues:    make(ImportValidationIssues, 0),
+	}
+
+	rootContent, exists := bundle.Files[bundle.RootFile]
+	if !exists {
+		return nil, ImportValidationIssues{{
+			Code:     ImportErrCodeFileNotFound,
+			Severity: ImportIssueSeverityError,
+			Message:  fmt.Sprintf("root file '%s' not found in the import bundle", bundle.RootFile),
+			File:     bundle.RootFile,
+		}}
+	}
+
+	document, root := resolver.parse(bundle.RootFile, rootContent)
+	if root == nil {
+		return nil, resolver.issues
+	}
+
+	resolver.stack = []string{bundle.RootFile}
+	resolver.mergeIncludes(root, root, bundle.RootFile)
+	resolver.resolveSchemaContents(root)
+
+	if len(resolver.issues) > 0 {
+		return nil, resolver.issues
+	}
+
+	resolved := &resolvedImportBundle{document: document, nodeFiles: resolver.nodeFiles}
+	if err := document.Decode(&resolved.projectImport); err != nil {
+		issue := yamlErrorToIssue(err)
+		issue.File = bundle.RootFile
+		return nil, ImportValidationIssues{issue}
+	}
+	return resolved, nil
+}
+
+// parse parses a YAML, JSON or Fusionlang file and returns the document together with its top-level mapping
+func (resolver *importBundleResolver) parse(fileName string, content string) (*yaml.Node, *yaml.Node) {
+	if resolver.bundle.fileFormat(fileName) == ImportFormatFusionlang {
+		compiled, err := fusionlang.Compile(content)
+		if err != nil {
+			resolver.issues = append(resolver.issues, fusionlangErrorToIssues(err, fileName)...)
+			return nil, nil
+		}
+		root := compiled.Content[0]
+		resolver.nodeFiles[root] = fileName
+		return compiled, root
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &document); err != nil {
+		issue := yamlErrorToIssue(err)
+		issue.File = fileName
+		resolver.issues = append(resolver.issues, issue)
+		return nil, nil
+	}
+
+	// Empty files are valid, they simply don't contribute anything
+	if len(document.Content) == 0 {
+		document.Kind = yaml.DocumentNode
+		document.Content = []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map", Line: 1, Column: 1}}
+	}
+
+	root := document.Content[0]
+	if root.Kind != yaml.MappingNode {
+		resolver.issueAt(ImportErrCodeInvalidYAML, root, fileName, "",
+			"architecture file '%s' must contain a mapping at the top level", fileName)
+		return nil, nil
+	}
+	resolver.nodeFiles[root] = fileName
+	return &document, root
+}
+
+// mergeIncludes appends sections of every file included by "current" to the root document
+func (resolver *importBundleResolver) mergeIncludes(root *yaml.Node, current *yaml.Node, currentFile string) {
+	for _, section := range mergeableImportSections {
+		if items := yamlMappingValue(current, section); items != nil && items.Kind == yaml.SequenceNode {
+			for _, item := range items.Content {
+				resolver.nodeFiles[item] = currentFile
+			}
+		}
+	}
+
+	includeNode := yamlMappingValue(current, "include")
+	if includeNode == nil {
+		return
+	}
+
+	var includeEntries []*yaml.Node
+	switch includeNode.Kind {
+	case yaml.ScalarNode:
+		includeEntries = []*yaml.Node{includeNode}
+	case yaml.SequenceNode:
+		includeEntries = includeNode.Content
+	default:
+		resolver.issueAt(ImportErrCodeInvalidInclude, includeNode, currentFile, "",
+			"include must be a file name or a list of file names")
+		return
+	}
+
+	for _, entry := range includeEntries {
+		if entry.Kind != yaml.ScalarNode || entry.Value == "" {
+			resolver.issueAt(ImportErrCodeInvalidInclude, entry, currentFile, "",
+				"include entries must be file names")
+			continue
+		}
+
+		includedFile, ok := resolver.relativePath(currentFile, entry.Value)
+		if !ok {
+			resolver.issueAt(ImportErrCodeFileOutsideBundle, entry, currentFile, entry.Value,
+				"included file '%s' is outside of the import bundle", entry.Value)
+			continue
+		}
+
+		for _, parent := range resolver.stack {
+			if parent == includedFile {
+				resolver.issueAt(ImportErrCodeIncludeCycle, entry, currentFile, entry.Value,
+					"file '%s' includes itself: %s -> %s", includedFile, strings.Join(resolver.stack, " -> "), includedFile)
+				ok = false
+				break
+			}
+		}
+		// Diamond-shaped includes are fine, but every file is merged only once
+		if !ok || resolver.included[includedFile] {
+			continue
+		}
+
+		content, exists := resolver.bundle.Files[includedFile]
+		if !exists {
+			resolver.issueAt(ImportErrCodeFileNotFound, entry, currentFile, entry.Value,
+				"included file '%s' not found in the import bundle", includedFile)
+			continue
+		}
+		resolver.included[includedFile] = true
+
+		_, includedRoot := resolver.parse(includedFile, content)
+		if includedRoot == nil {
+			continue
+		}
+
+		resolver.stack = append(resolver.stack, includedFile)
+		resolver.mergeIncludes(root, includedRoot, includedFile)
+		resolver.stack = resolver.stack[:len(resolver.stack)-1]
+
+		for _, section := range mergeableImportSections {
+			items := yamlMappingValue(includedRoot, section)
+			if items == nil || items.Kind != yaml.SequenceNode {
+				continue
+			}
+			rootItems := yamlMappingValue(root, section)
+			if rootItems == nil || rootItems.Kind != yaml.SequenceNode {
+				rootItems = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Line: items.Line, Column: items.Column}
+				yamlSetMappingValue(root, section, rootItems)
+				resolver.nodeFiles[rootItems] = includedFile
+			}
+			rootItems.Content = append(rootItems.Content, items.Content...)
+		}
+	}
+}
+
+// resolveSchemaContents loads schemas referenced by "file" and converts inline schema objects
+// (convenient in JSON architecture files) into the textual form used everywhere else
+func (resolver *importBundleResolver) resolveSchemaContents(root *yaml.Node) {
+	schemas := yamlMappingValue(root, "schemas")
+	if schemas == nil || schemas.Kind != yaml.SequenceNode {
+		return
+	}
+
+	for _, schema := range schemas.Content {
+		if schema.Kind != yaml.MappingNode {
+			continue
+		}
+		declaringFile := resolver.nodeFiles[schema]
+
+		contentNode := yamlMappingValue(schema, "schema")
+		fileNode := yamlMappingValue(schema, "file")
+
+		if contentNode != nil && fileNode != nil {
+			resolver.issueAt(ImportErrCodeInvalidSchema, fileNode, declaringFile, fileNode.Value,
+				"schema can't have both inline content and a file reference")
+			continue
+		}
+
+		if contentNode != nil && (contentNode.Kind == yaml.MappingNode || contentNode.Kind == yaml.SequenceNode) {
+			var decoded interface{}
+			if err := contentNode.Decode(&decoded); err != nil {
+				resolver.issueAt(ImportErrCodeInvalidSchema, contentNode, declaringFile, "",
+					"failed to read inline schema: %v", err)
+				continue
+			}
+			encoded, err := json.Marshal(decoded)
+			if err != nil {
+				resolver.issueAt(ImportErrCodeInvalidSchema, contentNode, declaringFile, "",
+					"failed to convert inline schema to JSON: %v", err)
+				continue
+			}
+			*contentNode = yaml.Node{
+				Kind:   yaml.ScalarNode,
+				Tag:    "!!str",
+				Value:  string(encoded),
+				Line:   contentNode.Line,
+				Column: contentNode.Column,
+			}
+			continue
+		}
+
+		if fileNode == nil {
+			continue
+		}
+		if fileNode.Kind != yaml.ScalarNode || fileNode.Value == "" {
+			resolver.issueAt(ImportErrCodeInvalidInclude, fileNode, declaringFile, "",
+				"schema file must be a file name")
+			continue
+		}
+
+		schemaFile, ok := resolver.relativePath(declaringFile, fileNode.Value)
+		if !ok {
+			resolver.issueAt(ImportErrCodeFileOutsideBundle, fileNode, declaringFile, fileNode.Value,
+				"schema file '%s' is outside of the import bundle", fileNode.Value)
+			continue
+		}
+		content, exists := resolver.bundle.Files[schemaFile]
+		if !exists {
+			resolver.issueAt(ImportErrCodeFileNotFound, fileNode, declaringFile, fileNode.Value,
+				"schema file '%s' not found in the import bundle", schemaFile)
+			continue
+		}
+
+		// Issues related to the schema content are reported against the schema file itself
+		loadedContent := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: content, Line: 1, Column: 1}
+		resolver.nodeFiles[loadedContent] = schemaFile
+		yamlSetMappingValue(schema, "schema", loadedContent)
+	}
+}
+
+// relativePath resolves a reference relative to the directory of the file where it is declared
+func (resolver *importBundleResolver) relativePath(declaringFile string, reference string) (string, bool) {
+	if strings.HasPrefix(reference, "/") {
+		return cleanImportBundlePath(reference)
+	}
+	return cleanImportBundlePath(path.Join(path.Dir(declaringFile), reference))
+}
+
+func (resolver *importBundleResolver) issueAt(code string, node *yaml.Node, file string, value string, format string, args ...interface{}) {
+	resolver.issues = append(resolver.issues, ImportValidationIssue{
+		Code:     code,
+		Severity: ImportIssueSeverityError,
+		Message:  fmt.Sprintf(format, args...),
+		File:     file,
+		Line:     node.Line,
+		Column:   node.Column,
+		Value:    value,
+	})
+}
+
+// yamlMappingValue returns the value of a key in a mapping node or nil if the key is absent
+func yamlMappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// yamlSetMappingValue replaces the value of a key in a mapping node or appends the key
+func yamlSetMappingValue(mapping *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content[i+1] = value
+			return
+		}
+	}
+	keyNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key, Line: value.Line, Column: value.Column}
+	mapping.Content = append(mapping.Content, keyNode, value)
+}
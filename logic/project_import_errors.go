@@ -0,0 +1,244 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/fusioncatltd/fusioncat/fusionlang"
+	"gopkg.in/yaml.v3"
+)
+
+// Severities of import validation issues. Errors block the import, warnings are informational.
+const (
+	ImportIssueSeverityError   = "error"
+	ImportIssueSeverityWarning = "warning"
+)
+
+// Machine-readable codes of import validation issues.
+// Editor tooling relies on these values, so they must stay stable once released.
+const (
+	ImportErrCodeInvalidYAML               = "invalid_yaml"
+	ImportErrCodeUnsupportedVersion        = "unsupported_version"
+	ImportErrCodeRequiredField             = "required_field"
+	ImportErrCodeInvalidProtocol           = "invalid_protocol"
+	ImportErrCodeNameAlreadyExists         = "name_already_exists"
+	ImportErrCodeInvalidResource           = "invalid_resource"
+	ImportErrCodeInvalidResourceMode       = "invalid_resource_mode"
+	ImportErrCodeInvalidResourceType       = "invalid_resource_type"
+	ImportErrCodeUnknownBindResource       = "unknown_bind_resource"
+	ImportErrCodeUnsupportedSchemaType     = "unsupported_schema_type"
+	ImportErrCodeInvalidSchema             = "invalid_schema"
+	ImportErrCodeUnknownSchema             = "unknown_schema"
+	ImportErrCodeInvalidCompatibility      = "invalid_compatibility"
+	ImportErrCodeIncompatibleSchemaVersion = "incompatible_schema_version"
+	ImportErrCodeSchemaTypeChanged         = "schema_type_changed"
+	ImportErrCodeUnknownMessageType        = "unknown_message_type"
+	ImportErrCodeUnknownMessage            = "unknown_message"
+	ImportErrCodeInvalidResourceReference  = "invalid_resource_reference"
+	ImportErrCodeInvalidInclude            = "invalid_include"
+	ImportErrCodeFileNotFound              = "file_not_found"
+	ImportErrCodeFileOutsideBundle         = "file_outside_bundle"
+	ImportErrCodeIncludeCycle              = "include_cycle"
+	ImportErrCodeInvalidFusionlang         = "invalid_fusionlang"
+	ImportErrCodeImportFailed              = "import_failed"
+	ImportErrCodeRetiredMessage            = "retired_message"
+	ImportErrCodeImportInterrupted         = "import_interrupted"
+	ImportWarnCodeServerWithoutResources   = "server_without_resources"
+	ImportWarnCodeSchemaNotUsedByMessages  = "unused_schema"
+	ImportWarnCodeMessageNotUsedByApps     = "unused_message"
+	ImportWarnCodeDeprecatedMessage        = "deprecated_message"
+)
+
+// ImportValidationIssue is a single problem found in an architecture file.
+// Path uses dot notation with sequence indexes, e.g. apps[2].sends[0].resource,
+// Line and Column point at the node in the source YAML document (1-based, 0 if unknown).
+// File is set for multi-file imports and names the file containing the node.
+type ImportValidationIssue struct {
+	Code     string `json:"code"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	File     string `json:"file,omitempty"`
+	Path     string `json:"path"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Value    string `json:"value,omitempty"`
+}
+
+// ImportValidationIssues is a list of issues with a few helpers used by endpoints
+type ImportValidationIssues []ImportValidationIssue
+
+// HasErrors returns true if at least one issue blocks the import
+func (issues ImportValidationIssues) HasErrors() bool {
+	for _, issue := range issues {
+		if issue.Severity == ImportIssueSeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Errors returns only issues with error severity
+func (issues ImportValidationIssues) Errors() ImportValidationIssues {
+	return issues.filterBySeverity(ImportIssueSeverityError)
+}
+
+// Warnings returns only issues with warning severity
+func (issues ImportValidationIssues) Warnings() ImportValidationIssues {
+	return issues.filterBySeverity(ImportIssueSeverityWarning)
+}
+
+func (issues ImportValidationIssues) filterBySeverity(severity string) ImportValidationIssues {
+	filtered := make(ImportValidationIssues, 0)
+	for _, issue := range issues {
+		if issue.Severity == severity {
+			filtered = append(filtered, issue)
+		}
+	}
+	return filtered
+}
+
+// importIssuesCollector accumulates issues and resolves their location in the YAML document
+type importIssuesCollector struct {
+	nodes     map[string]*yaml.Node
+	files     map[string]string
+	nodeFiles map[*yaml.Node]string
+	issues    ImportValidationIssues
+}
+
+// newImportIssuesCollector indexes the document. nodeFiles marks nodes merged from other files
+// of a bundle, every node below a marked one is considered to belong to the same file.
+func newImportIssuesCollector(root *yaml.Node, nodeFiles map[*yaml.Node]string) *importIssuesCollector {
+	collector := &importIssuesCollector{
+		nodes:     make(map[string]*yaml.Node),
+		files:     make(map[string]string),
+		nodeFiles: nodeFiles,
+		issues:    make(ImportValidationIssues, 0),
+	}
+	if root != nil {
+		collector.indexNode(root, "", "")
+	}
+	return collector
+}
+
+// indexNode walks the YAML tree and remembers every node by its path
+func (collector *importIssuesCollector) indexNode(node *yaml.Node, path string, file string) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			collector.indexNode(child, path, file)
+		}
+		return
+	case yaml.AliasNode:
+		if node.Alias != nil {
+			collector.indexNode(node.Alias, path, file)
+		}
+		return
+	}
+
+	if nodeFile, ok := collector.nodeFiles[node]; ok {
+		file = nodeFile
+	}
+	collector.nodes[path] = node
+	collector.files[path] = file
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			collector.indexNode(node.Content[i+1], childPath, file)
+		}
+	case yaml.SequenceNode:
+		for i, child := range node.Content {
+			collector.indexNode(child, fmt.Sprintf("%s[%d]", path, i), file)
+		}
+	}
+}
+
+// locate returns the file and position of the node at path. Missing nodes (e.g. absent required fields)
+// are reported at the position of the closest existing parent.
+func (collector *importIssuesCollector) locate(path string) (string, int, int) {
+	for {
+		if node, ok := collector.nodes[path]; ok {
+			return collector.files[path], node.Line, node.Column
+		}
+		if path == "" {
+			return "", 0, 0
+		}
+		cut := strings.LastIndexAny(path, ".[")
+		if cut < 0 {
+			path = ""
+		} else {
+			path = path[:cut]
+		}
+	}
+}
+
+func (collector *importIssuesCollector) add(severity string, code string, path string, value string, format string, args ...interface{}) {
+	file, line, column := collector.locate(path)
+	collector.issues = append(collector.issues, ImportValidationIssue{
+		Code:     code,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+		File:     file,
+		Path:     path,
+		Line:     line,
+		Column:   column,
+		Value:    value,
+	})
+}
+
+func (collector *importIssuesCollector) errorf(code string, path string, value string, format string, args ...interface{}) {
+	collector.add(ImportIssueSeverityError, code, path, value, format, args...)
+}
+
+func (collector *importIssuesCollector) warnf(code string, path string, value string, format string, args ...interface{}) {
+	collector.add(ImportIssueSeverityWarning, code, path, value, format, args...)
+}
+
+var yamlErrorLineRegexp = regexp.MustCompile(`line (\d+)`)
+
+// yamlErrorToIssue converts a YAML parser error into an issue, keeping the line number if the parser reported it
+func yamlErrorToIssue(err error) ImportValidationIssue {
+	issue := ImportValidationIssue{
+		Code:     ImportErrCodeInvalidYAML,
+		Severity: ImportIssueSeverityError,
+		Message:  fmt.Sprintf("failed to unmarshal YAML: %v", err),
+	}
+	if match := yamlErrorLineRegexp.FindStringSubmatch(err.Error()); match != nil {
+		issue.Line, _ = strconv.Atoi(match[1])
+	}
+	return issue
+}
+
+// fusionlangErrorToIssues converts Fusionlang syntax errors into issues located in the given file
+func fusionlangErrorToIssues(err error, fileName string) ImportValidationIssues {
+	var syntaxErrors fusionlang.ErrorList
+	if !errors.As(err, &syntaxErrors) {
+		return ImportValidationIssues{{
+			Code:     ImportErrCodeInvalidFusionlang,
+			Severity: ImportIssueSeverityError,
+			Message:  err.Error(),
+			File:     fileName,
+		}}
+	}
+
+	issues := make(ImportValidationIssues, 0, len(syntaxErrors))
+	for _, syntaxError := range syntaxErrors {
+		issues = append(issues, ImportValidationIssue{
+			Code:     ImportErrCodeInvalidFusionlang,
+			Severity: ImportIssueSeverityError,
+			Message:  syntaxError.Message,
+			File:     fileName,
+			Line:     syntaxError.Line,
+			Column:   syntaxError.Column,
+		})
+	}
+	return issues
+}
@@ -0,0 +1,480 @@
+package logic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/fusioncatltd/fusioncat/common"
+	"github.com/fusioncatltd/fusioncat/db"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Statuses of import jobs. Queued jobs wait for other imports into the same project to finish,
+// completed, failed and cancelled jobs are finished. Failed and cancelled jobs leave nothing behind,
+// completed jobs can be reverted, which removes the entities they created.
+const (
+	ImportJobStatusQueued    = "queued"
+	ImportJobStatusRunning   = "running"
+	ImportJobStatusCompleted = "completed"
+	ImportJobStatusFailed    = "failed"
+	ImportJobStatusCancelled = "cancelled"
+	ImportJobStatusReverted  = "reverted"
+)
+
+// Phases of a running import job, entities are created in this order
+const (
+	ImportJobPhaseQueued     = "queued"
+	ImportJobPhaseValidating = "validating"
+	ImportJobPhaseServers    = "servers"
+	ImportJobPhaseSchemas    = "schemas"
+	ImportJobPhaseMessages   = "messages"
+	ImportJobPhaseApps       = "apps"
+	ImportJobPhaseFinished   = "finished"
+)
+
+// Modes of import jobs. Imports create new entities only, synchronizations also update the existing ones.
+const (
+	ImportJobModeImport = "import"
+	ImportJobModeSync   = "sync"
+)
+
+var (
+	ErrImportCancelled       = errors.New("import was cancelled")
+	ErrImportAlreadyFinished = errors.New("import is already finished")
+)
+
+// Columns with counters of created entities, by kind reported to importProgressListener
+var importJobCounterColumns = map[string]string{
+	importedServer:        "servers_created",
+	importedResource:      "resources_created",
+	importedBinding:       "bindings_created",
+	importedSchema:        "schemas_created",
+	importedSchemaVersion: "schema_versions_created",
+	importedMessage:       "messages_created",
+	importedApp:           "apps_created",
+	importedConnection:    "connections_created",
+}
+
+// Import jobs run inside the server process. Cancel functions of jobs which are not finished yet
+// are kept here, and imports into the same project are serialized with a per-project slot
+// because they would otherwise compete for the same entity names.
+var importJobsRegistry = struct {
+	sync.Mutex
+	cancels      map[uuid.UUID]context.CancelFunc
+	projectSlots map[uuid.UUID]chan struct{}
+}{
+	cancels:      make(map[uuid.UUID]context.CancelFunc),
+	projectSlots: make(map[uuid.UUID]chan struct{}),
+}
+
+func projectImportSlot(projectID uuid.UUID) chan struct{} {
+	importJobsRegistry.Lock()
+	defer importJobsRegistry.Unlock()
+	slot, exists := importJobsRegistry.projectSlots[projectID]
+	if !exists {
+		slot = make(chan struct{}, 1)
+		importJobsRegistry.projectSlots[projectID] = slot
+	}
+	return slot
+}
+
+// ProjectImportJobObject is a single run of the architecture importer
+type ProjectImportJobObject struct {
+	dbModel db.ProjectImportsDBModel
+}
+
+type ProjectImportCountsStruct struct {
+	Servers     int `json:"servers"`
+	Resources   int `json:"resources"`
+	Bindings    int `json:"bindings"`
+	Schemas     int `json:"schemas"`
+	Messages    int `json:"messages"`
+	Apps        int `json:"apps"`
+	Connections int `json:"connections"`
+	// Versions of schemas created by the job, including first versions of new schemas
+	SchemaVersions int `json:"schema_versions"`
+}
+
+// ProjectImportSourceStruct is the imported document as it was submitted
+type ProjectImportSourceStruct struct {
+	RootFile   string            `json:"root_file"`
+	RootFormat string            `json:"root_format"`
+	Files      map[string]string `json:"files"`
+}
+
+type ProjectImportJobDBSerializerStruct struct {
+	ID                string                     `json:"id"`
+	ProjectID         string                     `json:"project_id"`
+	Status            string                     `json:"status"`
+	Phase             string                     `json:"phase"`
+	Mode              string                     `json:"mode"`
+	CommitSHA         string                     `json:"commit_sha,omitempty"`
+	Counts            ProjectImportCountsStruct  `json:"counts"`
+	Errors            ImportValidationIssues     `json:"errors"`
+	Warnings          ImportValidationIssues     `json:"warnings"`
+	Source            *ProjectImportSourceStruct `json:"source,omitempty"`
+	CreatedByUserID   string                     `json:"created_by_user_id"`
+	CreatedByUserName string                     `json:"created_by_user_name"`
+	StartedAt         string                     `json:"started_at,omitempty"`
+	FinishedAt        string                     `json:"finished_at,omitempty"`
+	RevertedAt        string                     `json:"reverted_at,omitempty"`
+	RevertedByUserID  string                     `json:"reverted_by_user_id,omitempty"`
+	CreatedAt         string                     `json:"created_at"`
+	UpdatedAt         string                     `json:"updated_at"`
+}
+
+// Serialize returns the job without the source document, which can be large
+func (job *ProjectImportJobObject) Serialize() *ProjectImportJobDBSerializerStruct {
+	userDbRecord := db.UsersDBModel{}
+	_ = db.GetDB().First(&userDbRecord, job.dbModel.CreatedByUserID)
+
+	serialized := &ProjectImportJobDBSerializerStruct{
+		ID:        job.dbModel.ID.String(),
+		ProjectID: job.dbModel.ProjectID.String(),
+		Status:    job.dbModel.Status,
+		Phase:     job.dbModel.Phase,
+		Mode:      job.dbModel.Mode,
+		CommitSHA: job.dbModel.CommitSHA,
+		Counts: ProjectImportCountsStruct{
+			Servers:        job.dbModel.ServersCreated,
+			Resources:      job.dbModel.ResourcesCreated,
+			Bindings:       job.dbModel.BindingsCreated,
+			Schemas:        job.dbModel.SchemasCreated,
+			Messages:       job.dbModel.MessagesCreated,
+			Apps:           job.dbModel.AppsCreated,
+			Connections:    job.dbModel.ConnectionsCreated,
+			SchemaVersions: job.dbModel.SchemaVersionsCreated,
+		},
+		Errors:            decodeImportJobIssues(job.dbModel.Errors),
+		Warnings:          decodeImportJobIssues(job.dbModel.Warnings),
+		CreatedByUserID:   job.dbModel.CreatedByUserID.String(),
+		CreatedByUserName: userDbRecord.Handle,
+		CreatedAt:         job.dbModel.CreatedAt.String(),
+		UpdatedAt:         job.dbModel.UpdatedAt.String(),
+	}
+	if job.dbModel.StartedAt != nil {
+		serialized.StartedAt = job.dbModel.StartedAt.String()
+	}
+	if job.dbModel.FinishedAt != nil {
+		serialized.FinishedAt = job.dbModel.FinishedAt.String()
+	}
+	if job.dbModel.RevertedAt != nil {
+		serialized.RevertedAt = job.dbModel.RevertedAt.String()
+	}
+	if job.dbModel.RevertedByUserID != nil {
+		serialized.RevertedByUserID = job.dbModel.RevertedByUserID.String()
+	}
+	return serialized
+}
+
+// SerializeWithSource returns the job together with the submitted document
+func (job *ProjectImportJobObject) SerializeWithSource() *ProjectImportJobDBSerializerStruct {
+	serialized := job.Serialize()
+	source := &ProjectImportSourceStruct{}
+	if err := json.Unmarshal([]byte(job.dbModel.Source), source); err == nil {
+		serialized.Source = source
+	}
+	return serialized
+}
+
+func (job *ProjectImportJobObject) GetID() uuid.UUID {
+	return job.dbModel.ID
+}
+
+func (job *ProjectImportJobObject) GetProjectID() uuid.UUID {
+	return job.dbModel.ProjectID
+}
+
+func (job *ProjectImportJobObject) GetStatus() string {
+	return job.dbModel.Status
+}
+
+func (job *ProjectImportJobObject) GetErrors() ImportValidationIssues {
+	return decodeImportJobIssues(job.dbModel.Errors)
+}
+
+func (job *ProjectImportJobObject) GetWarnings() ImportValidationIssues {
+	return decodeImportJobIssues(job.dbModel.Warnings)
+}
+
+// IsFinished reports whether the job reached a final status
+func (job *ProjectImportJobObject) IsFinished() bool {
+	switch job.dbModel.Status {
+	case ImportJobStatusCompleted, ImportJobStatusFailed, ImportJobStatusCancelled, ImportJobStatusReverted:
+		return true
+	}
+	return false
+}
+
+// PhaseStarted implements importProgressListener
+func (job *ProjectImportJobObject) PhaseStarted(phase string) {
+	job.update(map[string]interface{}{"phase": phase})
+}
+
+// EntitiesCreated implements importProgressListener, it increments the counter of created entities
+func (job *ProjectImportJobObject) EntitiesCreated(kind string, count int) {
+	column, exists := importJobCounterColumns[kind]
+	if !exists {
+		return
+	}
+	_ = db.GetDB().Model(&db.ProjectImportsDBModel{}).
+		Where("id = ?", job.dbModel.ID).
+		UpdateColumn(column, gorm.Expr(column+" + ?", count)).Error
+}
+
+func (job *ProjectImportJobObject) update(values map[string]interface{}) {
+	_ = db.GetDB().Model(&db.ProjectImportsDBModel{}).
+		Where("id = ?", job.dbModel.ID).
+		Updates(values).Error
+}
+
+func (job *ProjectImportJobObject) finish(status string, errorIssues ImportValidationIssues) {
+	now := time.Now()
+	values := map[string]interface{}{
+		"status":      status,
+		"finished_at": &now,
+	}
+	if status == ImportJobStatusCompleted {
+		values["phase"] = ImportJobPhaseFinished
+	} else {
+		// Counters follow the progress of the transaction, nothing is left after a rollback
+		for _, column := range importJobCounterColumns {
+			values[column] = 0
+		}
+	}
+	if len(errorIssues) > 0 {
+		values["errors"] = encodeImportJobIssues(errorIssues)
+	}
+	job.update(values)
+}
+
+// run validates the bundle and imports it. The job waits until no other import
+// into the same project is running.
+func (job *ProjectImportJobObject) run(ctx context.Context, bundle *ImportBundle) {
+	defer func() {
+		importJobsRegistry.Lock()
+		delete(importJobsRegistry.cancels, job.dbModel.ID)
+		importJobsRegistry.Unlock()
+	}()
+
+	slot := projectImportSlot(job.dbModel.ProjectID)
+	select {
+	case slot <- struct{}{}:
+		defer func() { <-slot }()
+	case <-ctx.Done():
+		job.finish(ImportJobStatusCancelled, nil)
+		return
+	}
+
+	now := time.Now()
+	job.update(map[string]interface{}{
+		"status":     ImportJobStatusRunning,
+		"phase":      ImportJobPhaseValidating,
+		"started_at": &now,
+	})
+
+	options := importOptions{
+		syncExisting: job.dbModel.Mode == ImportJobModeSync,
+		importID:     &job.dbModel.ID,
+		commitSHA:    job.dbModel.CommitSHA,
+	}
+	issues := validateProjectImportBundle(bundle, job.dbModel.ProjectID, options)
+	if warnings := issues.Warnings(); len(warnings) > 0 {
+		job.update(map[string]interface{}{"warnings": encodeImportJobIssues(warnings)})
+	}
+	if issues.HasErrors() {
+		job.finish(ImportJobStatusFailed, issues.Errors())
+		return
+	}
+
+	err := importProjectFromBundle(ctx, bundle, job.dbModel.ProjectID, job.dbModel.CreatedByUserID, job, options)
+	switch {
+	case errors.Is(err, ErrImportCancelled):
+		job.finish(ImportJobStatusCancelled, nil)
+	case err != nil:
+		job.finish(ImportJobStatusFailed, ImportValidationIssues{{
+			Code:     ImportErrCodeImportFailed,
+			Severity: ImportIssueSeverityError,
+			Message:  err.Error(),
+		}})
+	default:
+		job.finish(ImportJobStatusCompleted, nil)
+	}
+}
+
+func encodeImportJobIssues(issues ImportValidationIssues) string {
+	encoded, _ := json.Marshal(issues)
+	return string(encoded)
+}
+
+func decodeImportJobIssues(encoded string) ImportValidationIssues {
+	issues := make(ImportValidationIssues, 0)
+	if encoded != "" {
+		_ = json.Unmarshal([]byte(encoded), &issues)
+	}
+	return issues
+}
+
+// ProjectImportJobsObjectsManager submits import jobs and keeps the history of imports
+type ProjectImportJobsObjectsManager struct {
+}
+
+// SubmitImport records a new import job and runs it in the background
+func (manager *ProjectImportJobsObjectsManager) SubmitImport(
+	bundle *ImportBundle,
+	projectID uuid.UUID,
+	userID uuid.UUID) (*ProjectImportJobObject, error) {
+	job, ctx, err := manager.createJob(bundle, projectID, userID, ImportJobModeImport, "")
+	if err != nil {
+		return nil, err
+	}
+	go job.run(ctx, bundle)
+	return job, nil
+}
+
+// RunImport records a new import job and runs it in the calling goroutine. The job is still
+// registered, so it can be cancelled and is listed in the history like background jobs.
+func (manager *ProjectImportJobsObjectsManager) RunImport(
+	bundle *ImportBundle,
+	projectID uuid.UUID,
+	userID uuid.UUID) (*ProjectImportJobObject, error) {
+	job, ctx, err := manager.createJob(bundle, projectID, userID, ImportJobModeImport, "")
+	if err != nil {
+		return nil, err
+	}
+	job.run(ctx, bundle)
+	return manager.GetByID(job.GetID())
+}
+
+// RunSync applies the bundle to the project in the calling goroutine. Unlike imports, entities
+// which already exist are updated and changed schemas get a new version. The commit SHA is recorded
+// on the job and on the schema versions it creates.
+func (manager *ProjectImportJobsObjectsManager) RunSync(
+	bundle *ImportBundle,
+	projectID uuid.UUID,
+	userID uuid.UUID,
+	commitSHA string) (*ProjectImportJobObject, error) {
+	job, ctx, err := manager.createJob(bundle, projectID, userID, ImportJobModeSync, commitSHA)
+	if err != nil {
+		return nil, err
+	}
+	job.run(ctx, bundle)
+	return manager.GetByID(job.GetID())
+}
+
+func (manager *ProjectImportJobsObjectsManager) createJob(
+	bundle *ImportBundle,
+	projectID uuid.UUID,
+	userID uuid.UUID,
+	mode string,
+	commitSHA string) (*ProjectImportJobObject, context.Context, error) {
+	source, err := json.Marshal(ProjectImportSourceStruct{
+		RootFile:   bundle.RootFile,
+		RootFormat: bundle.RootFormat,
+		Files:      bundle.Files,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	newJob := &db.ProjectImportsDBModel{
+		ProjectID:       projectID,
+		CreatedByUserID: userID,
+		Status:          ImportJobStatusQueued,
+		Phase:           ImportJobPhaseQueued,
+		Mode:            mode,
+		CommitSHA:       commitSHA,
+		RootFile:        bundle.RootFile,
+		RootFormat:      bundle.RootFormat,
+		Source:          string(source),
+	}
+	if err := db.GetDB().Create(newJob).Error; err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	importJobsRegistry.Lock()
+	importJobsRegistry.cancels[newJob.ID] = cancel
+	importJobsRegistry.Unlock()
+
+	return &ProjectImportJobObject{dbModel: *newJob}, ctx, nil
+}
+
+func (manager *ProjectImportJobsObjectsManager) GetByID(id uuid.UUID) (*ProjectImportJobObject, error) {
+	jobDbRecord := db.ProjectImportsDBModel{}
+	dbResult := db.GetDB().Model(db.ProjectImportsDBModel{}).First(&jobDbRecord, id)
+
+	if dbResult.Error != nil {
+		return nil, common.FusioncatErrRecordNotFound
+	}
+
+	return &ProjectImportJobObject{dbModel: jobDbRecord}, nil
+}
+
+// GetAllImportsForProject returns the history of imports into the project, newest first
+func (manager *ProjectImportJobsObjectsManager) GetAllImportsForProject(
+	projectID uuid.UUID) ([]ProjectImportJobObject, error) {
+	var jobs []db.ProjectImportsDBModel
+	response := make([]ProjectImportJobObject, 0)
+
+	err := db.GetDB().Model(db.ProjectImportsDBModel{}).
+		Where("project_id = ?", projectID).
+		Order("created_at desc").
+		Find(&jobs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, job := range jobs {
+		response = append(response, ProjectImportJobObject{dbModel: job})
+	}
+	return response, nil
+}
+
+// FailInterruptedImports marks jobs left queued or running by a previous run of the server as failed.
+// Their goroutines are gone, so they would otherwise never finish. Called once on startup, before
+// new imports are submitted.
+func (manager *ProjectImportJobsObjectsManager) FailInterruptedImports() (int64, error) {
+	now := time.Now()
+	result := db.GetDB().Model(&db.ProjectImportsDBModel{}).
+		Where("status IN ?", []string{ImportJobStatusQueued, ImportJobStatusRunning}).
+		Updates(map[string]interface{}{
+			"status":      ImportJobStatusFailed,
+			"finished_at": &now,
+			"errors": encodeImportJobIssues(ImportValidationIssues{{
+				Code:     ImportErrCodeImportInterrupted,
+				Severity: ImportIssueSeverityError,
+				Message:  "import was interrupted by a restart of the server",
+			}}),
+		})
+	return result.RowsAffected, result.Error
+}
+
+// CancelImport stops a queued or running job. Imports run in a transaction, nothing created
+// by a cancelled job is kept.
+// Jobs which are not registered in this process (e.g. interrupted by a restart) are marked
+// as cancelled right away.
+func (manager *ProjectImportJobsObjectsManager) CancelImport(id uuid.UUID) (*ProjectImportJobObject, error) {
+	job, err := manager.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if job.IsFinished() {
+		return nil, ErrImportAlreadyFinished
+	}
+
+	importJobsRegistry.Lock()
+	cancel, registered := importJobsRegistry.cancels[id]
+	importJobsRegistry.Unlock()
+
+	if registered {
+		cancel()
+		return job, nil
+	}
+	job.finish(ImportJobStatusCancelled, nil)
+	return manager.GetByID(id)
+}
@@ -0,0 +1,373 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fusioncatltd/fusioncat/db"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Every entity created by an import keeps the import ID in the "import_id" column.
+// This allows to list what an import created and to revert it, as long as nothing
+// created outside of the import depends on the imported entities.
+
+var (
+	ErrImportNotFinished     = errors.New("import is not finished yet")
+	ErrImportAlreadyReverted = errors.New("import is already reverted")
+	ErrImportHasDependents   = errors.New("imported entities are used by entities created outside of the import")
+	// Synchronizations also update existing entities and add versions to existing schemas,
+	// which aren't tracked by the import ID
+	ErrImportIsSynchronization = errors.New("git synchronizations can't be reverted")
+)
+
+// Tables of entities created by imports, by kind reported to importProgressListener
+var importedEntityTables = map[string]string{
+	importedServer:     db.ServersDBModel{}.TableName(),
+	importedResource:   db.ResourcesDBModel{}.TableName(),
+	importedBinding:    db.ResourceBindingsDBModel{}.TableName(),
+	importedSchema:     db.SchemasDBModel{}.TableName(),
+	importedMessage:    db.MessagesDBModel{}.TableName(),
+	importedApp:        db.AppsDBModel{}.TableName(),
+	importedConnection: db.AppResourceMessagesDBModel{}.TableName(),
+}
+
+// ImportedEntityStruct is a short description of an entity created by an import
+type ImportedEntityStruct struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+type ImportedEntitiesSerializerStruct struct {
+	ImportID    string                 `json:"import_id"`
+	Servers     []ImportedEntityStruct `json:"servers"`
+	Resources   []ImportedEntityStruct `json:"resources"`
+	Bindings    []ImportedEntityStruct `json:"bindings"`
+	Schemas     []ImportedEntityStruct `json:"schemas"`
+	Messages    []ImportedEntityStruct `json:"messages"`
+	Apps        []ImportedEntityStruct `json:"apps"`
+	Connections []ImportedEntityStruct `json:"connections"`
+}
+
+// ImportRevertBlockerStruct describes an entity created outside of the import
+// which depends on an imported entity and prevents the import from being reverted
+type ImportRevertBlockerStruct struct {
+	EntityType    string `json:"entity_type"`
+	EntityID      string `json:"entity_id"`
+	DependentType string `json:"dependent_type"`
+	DependentID   string `json:"dependent_id"`
+	Reason        string `json:"reason"`
+}
+
+// importedEntityIDs contains IDs of all entities created by an import, by kind
+type importedEntityIDs map[string][]uuid.UUID
+
+func loadImportedEntityIDs(connection *gorm.DB, importID uuid.UUID) (importedEntityIDs, error) {
+	ids := make(importedEntityIDs)
+	for kind, table := range importedEntityTables {
+		var kindIDs []uuid.UUID
+		err := connection.Table(table).Where("import_id = ?", importID).Pluck("id", &kindIDs).Error
+		if err != nil {
+			return nil, err
+		}
+		ids[kind] = kindIDs
+	}
+	return ids, nil
+}
+
+// GetCreatedEntities lists all entities created by the import which still exist
+func (job *ProjectImportJobObject) GetCreatedEntities() (*ImportedEntitiesSerializerStruct, error) {
+	connection := db.GetDB()
+	entities := &ImportedEntitiesSerializerStruct{ImportID: job.dbModel.ID.String()}
+
+	namedEntities := []struct {
+		kind   string
+		column string
+		target *[]ImportedEntityStruct
+	}{
+		{importedServer, "name", &entities.Servers},
+		{importedResource, "name", &entities.Resources},
+		{importedBinding, "''", &entities.Bindings},
+		{importedSchema, "name", &entities.Schemas},
+		{importedMessage, "name", &entities.Messages},
+		{importedApp, "name", &entities.Apps},
+		{importedConnection, "direction", &entities.Connections},
+	}
+
+	for _, named := range namedEntities {
+		*named.target = make([]ImportedEntityStruct, 0)
+		var rows []struct {
+			ID   uuid.UUID
+			Name string
+		}
+		err := connection.Table(importedEntityTables[named.kind]).
+			Select("id, "+named.column+" AS name").
+			Where("import_id = ?", job.dbModel.ID).
+			Order("created_at").
+			Scan(&rows).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			*named.target = append(*named.target, ImportedEntityStruct{ID: row.ID.String(), Name: row.Name})
+		}
+	}
+	return entities, nil
+}
+
+// findRevertBlockers looks for entities created outside of the import which depend on imported entities
+func (job *ProjectImportJobObject) findRevertBlockers(connection *gorm.DB, ids importedEntityIDs) ([]ImportRevertBlockerStruct, error) {
+	blockers := make([]ImportRevertBlockerStruct, 0)
+	importID := job.dbModel.ID
+
+	// Resources added to imported servers
+	if len(ids[importedServer]) > 0 {
+		var resources []db.ResourcesDBModel
+		err := connection.Where("server_id IN ? AND import_id IS DISTINCT FROM ? AND status = 'active'",
+			ids[importedServer], importID).Find(&resources).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, resource := range resources {
+			blockers = append(blockers, ImportRevertBlockerStruct{
+				EntityType:    importedServer,
+				EntityID:      resource.ServerID.String(),
+				DependentType: importedResource,
+				DependentID:   resource.ID.String(),
+				Reason:        fmt.Sprintf("resource %s was added to the server after the import", resource.Name),
+			})
+		}
+	}
+
+	// Bindings of imported resources
+	if len(ids[importedResource]) > 0 {
+		var bindings []db.ResourceBindingsDBModel
+		err := connection.Where("(source_resource_id IN ? OR target_resource_id IN ?) AND import_id IS DISTINCT FROM ?",
+			ids[importedResource], ids[importedResource], importID).Find(&bindings).Error
+		if err != nil {
+			return nil, err
+		}
+		imported := uuidSet(ids[importedResource])
+		for _, binding := range bindings {
+			resourceID := binding.SourceResourceID
+			if !imported[resourceID] {
+				resourceID = binding.TargetResourceID
+			}
+			blockers = append(blockers, ImportRevertBlockerStruct{
+				EntityType:    importedResource,
+				EntityID:      resourceID.String(),
+				DependentType: importedBinding,
+				DependentID:   binding.ID.String(),
+				Reason:        "resource is bound to another resource outside of the import",
+			})
+		}
+	}
+
+	// Messages using imported schemas
+	if len(ids[importedSchema]) > 0 {
+		var messages []db.MessagesDBModel
+		err := connection.Where("schema_id IN ? AND import_id IS DISTINCT FROM ? AND status = 'active'",
+			ids[importedSchema], importID).Find(&messages).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, message := range messages {
+			blockers = append(blockers, ImportRevertBlockerStruct{
+				EntityType:    importedSchema,
+				EntityID:      message.SchemaID.String(),
+				DependentType: importedMessage,
+				DependentID:   message.ID.String(),
+				Reason:        fmt.Sprintf("message %s uses the schema", message.Name),
+			})
+		}
+
+		// Schemas outside of the import referencing imported schemas
+		references, err := loadSchemaReferences(connection, "r.referenced_schema_id IN ? AND r.schema_id NOT IN ?",
+			ids[importedSchema], ids[importedSchema])
+		if err != nil {
+			return nil, err
+		}
+		for _, reference := range references {
+			blockers = append(blockers, ImportRevertBlockerStruct{
+				EntityType:    importedSchema,
+				EntityID:      reference.ReferencedSchemaID,
+				DependentType: importedSchema,
+				DependentID:   reference.SchemaID,
+				Reason: fmt.Sprintf("version %d of schema %s references the schema",
+					reference.SchemaVersion, reference.SchemaName),
+			})
+		}
+
+		// Schemas converted from imported schemas
+		var convertedSchemas []db.SchemasDBModel
+		err = connection.Where("converted_from_schema_id IN ? AND import_id IS DISTINCT FROM ? AND status = 'active'",
+			ids[importedSchema], importID).Find(&convertedSchemas).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, convertedSchema := range convertedSchemas {
+			blockers = append(blockers, ImportRevertBlockerStruct{
+				EntityType:    importedSchema,
+				EntityID:      convertedSchema.ConvertedFromSchemaID.String(),
+				DependentType: importedSchema,
+				DependentID:   convertedSchema.ID.String(),
+				Reason:        fmt.Sprintf("schema %s was converted from the schema", convertedSchema.Name),
+			})
+		}
+
+		// Imports create only the first version of schemas, newer versions were added by users
+		var schemaVersions []db.SchemaVersionsDBModel
+		err = connection.Where("schema_id IN ? AND version > 1", ids[importedSchema]).Find(&schemaVersions).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, schemaVersion := range schemaVersions {
+			blockers = append(blockers, ImportRevertBlockerStruct{
+				EntityType:    importedSchema,
+				EntityID:      schemaVersion.SchemaID.String(),
+				DependentType: "schema_version",
+				DependentID:   schemaVersion.ID.String(),
+				Reason:        fmt.Sprintf("version %d of the schema was created after the import", schemaVersion.Version),
+			})
+		}
+	}
+
+	// Connections of apps, resources and messages created outside of the import
+	if len(ids[importedApp]) > 0 || len(ids[importedResource]) > 0 || len(ids[importedMessage]) > 0 {
+		var connections []db.AppResourceMessagesDBModel
+		err := connection.Where("(app_id IN ? OR resource_id IN ? OR message_id IN ?) "+
+			"AND import_id IS DISTINCT FROM ? AND status = 'active'",
+			nonEmptyUUIDs(ids[importedApp]), nonEmptyUUIDs(ids[importedResource]), nonEmptyUUIDs(ids[importedMessage]),
+			importID).Find(&connections).Error
+		if err != nil {
+			return nil, err
+		}
+		apps, resources := uuidSet(ids[importedApp]), uuidSet(ids[importedResource])
+		for _, appConnection := range connections {
+			blocker := ImportRevertBlockerStruct{
+				EntityType:    importedMessage,
+				EntityID:      appConnection.MessageID.String(),
+				DependentType: importedConnection,
+				DependentID:   appConnection.ID.String(),
+				Reason:        fmt.Sprintf("app %s the message", appConnection.Direction),
+			}
+			switch {
+			case apps[appConnection.AppID]:
+				blocker.EntityType, blocker.EntityID = importedApp, appConnection.AppID.String()
+				blocker.Reason = fmt.Sprintf("connection was added to the app after the import (%s)", appConnection.Direction)
+			case resources[appConnection.ResourceID]:
+				blocker.EntityType, blocker.EntityID = importedResource, appConnection.ResourceID.String()
+				blocker.Reason = fmt.Sprintf("app %s messages via the resource", appConnection.Direction)
+			}
+			blockers = append(blockers, blocker)
+		}
+	}
+
+	return blockers, nil
+}
+
+// RevertImport removes all entities created by the import. If entities created outside of the import
+// depend on them, nothing is removed and the blockers are returned with ErrImportHasDependents.
+func (manager *ProjectImportJobsObjectsManager) RevertImport(
+	importID uuid.UUID,
+	userID uuid.UUID) (*ProjectImportJobObject, []ImportRevertBlockerStruct, error) {
+	job, err := manager.GetByID(importID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if job.dbModel.Status == ImportJobStatusReverted {
+		return nil, nil, ErrImportAlreadyReverted
+	}
+	if !job.IsFinished() {
+		return nil, nil, ErrImportNotFinished
+	}
+	if job.dbModel.Mode == ImportJobModeSync {
+		return nil, nil, ErrImportIsSynchronization
+	}
+
+	var blockers []ImportRevertBlockerStruct
+	err = db.GetDB().Transaction(func(tx *gorm.DB) error {
+		ids, err := loadImportedEntityIDs(tx, importID)
+		if err != nil {
+			return err
+		}
+		blockers, err = job.findRevertBlockers(tx, ids)
+		if err != nil {
+			return err
+		}
+		if len(blockers) > 0 {
+			return ErrImportHasDependents
+		}
+
+		// Dependent entities go first. Entities are removed for good, so that their names can be reused.
+		if len(ids[importedSchema]) > 0 {
+			err = tx.Unscoped().Where("schema_id IN ?", ids[importedSchema]).Delete(&db.SchemaVersionsDBModel{}).Error
+			if err != nil {
+				return err
+			}
+			err = tx.Unscoped().Where("schema_id IN ?", ids[importedSchema]).Delete(&db.SchemaReferencesDBModel{}).Error
+			if err != nil {
+				return err
+			}
+			err = tx.Where("schema_id IN ?", ids[importedSchema]).Delete(&db.SchemaFieldsDBModel{}).Error
+			if err != nil {
+				return err
+			}
+		}
+		deletionOrder := []struct {
+			kind  string
+			model interface{}
+		}{
+			{importedConnection, &db.AppResourceMessagesDBModel{}},
+			{importedBinding, &db.ResourceBindingsDBModel{}},
+			{importedApp, &db.AppsDBModel{}},
+			{importedMessage, &db.MessagesDBModel{}},
+			{importedSchema, &db.SchemasDBModel{}},
+			{importedResource, &db.ResourcesDBModel{}},
+			{importedServer, &db.ServersDBModel{}},
+		}
+		for _, entity := range deletionOrder {
+			if len(ids[entity.kind]) == 0 {
+				continue
+			}
+			if err := tx.Unscoped().Where("import_id = ?", importID).Delete(entity.model).Error; err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		return tx.Model(&db.ProjectImportsDBModel{}).Where("id = ?", importID).Updates(map[string]interface{}{
+			"status":              ImportJobStatusReverted,
+			"reverted_at":         &now,
+			"reverted_by_user_id": userID,
+		}).Error
+	})
+	if errors.Is(err, ErrImportHasDependents) {
+		return nil, blockers, err
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	job, err = manager.GetByID(importID)
+	return job, nil, err
+}
+
+func uuidSet(ids []uuid.UUID) map[uuid.UUID]bool {
+	set := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
+
+// nonEmptyUUIDs replaces an empty list with a list containing the nil UUID,
+// because "IN ()" is not valid SQL
+func nonEmptyUUIDs(ids []uuid.UUID) []uuid.UUID {
+	if len(ids) == 0 {
+		return []uuid.UUID{uuid.Nil}
+	}
+	return ids
+}
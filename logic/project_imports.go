@@ -1,13 +1,18 @@
 package logic
 
 import (
+	"context"
 	"fmt"
+	"strconv"
 	"strings"
 
+	"github.com/fusioncatltd/fusioncat/avro"
+	"github.com/fusioncatltd/fusioncat/db"
+	"github.com/fusioncatltd/fusioncat/protobuf"
 	asyncuri "github.com/fusioncatltd/lib-go-asyncresourceuri"
 	"github.com/google/uuid"
-	"github.com/santhosh-tekuri/jsonschema/v5"
-	"gopkg.in/yaml.v3"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 )
 
 // YAML structures for project import
@@ -45,6 +50,12 @@ type SchemaImport struct {
 	Version     int    `yaml:"version"`
 	Description string `yaml:"description"`
 	Schema      string `yaml:"schema"`
+	// Compatibility is the compatibility mode of the schema, see CompatibilityModes.
+	// Synchronization checks changed schemas against it, or against the current mode if it is empty.
+	Compatibility string `yaml:"compatibility,omitempty"`
+	// File is a path to a file with schema content, relative to the file declaring the schema.
+	// It is resolved into Schema when the bundle is read.
+	File string `yaml:"file,omitempty"`
 }
 
 type MessageImport struct {
@@ -55,6 +66,8 @@ type MessageImport struct {
 
 type SchemaReference struct {
 	Name string `yaml:"name"`
+	// MessageType selects a message of a protobuf schema, the first message of the schema is used when empty
+	MessageType string `yaml:"message_type,omitempty"`
 }
 
 type AppImport struct {
@@ -74,19 +87,47 @@ type ReceiveImport struct {
 	Resource string `yaml:"resource"`
 }
 
-// ValidateProjectImportYAML validates the YAML structure and references
-func ValidateProjectImportYAML(yamlStr string, projectID uuid.UUID) []string {
-	var projectImport ProjectImportYAML
-	var errors []string
+// ValidateProjectImportYAML validates the YAML structure and references.
+// Every issue carries a code, the YAML path and the position of the offending node,
+// so that editors can highlight it. Only issues with error severity block the import.
+func ValidateProjectImportYAML(yamlStr string, projectID uuid.UUID) ImportValidationIssues {
+	return ValidateProjectImportBundle(NewSingleFileImportBundle(yamlStr), projectID)
+}
 
-	err := yaml.Unmarshal([]byte(yamlStr), &projectImport)
-	if err != nil {
-		errors = append(errors, fmt.Sprintf("failed to unmarshal YAML: %v", err))
-		return errors
+// ValidateProjectImportBundle validates a multi-file architecture definition.
+// Included files are merged into the root document first, issues point at the file they were found in.
+func ValidateProjectImportBundle(bundle *ImportBundle, projectID uuid.UUID) ImportValidationIssues {
+	return validateProjectImportBundle(bundle, projectID, importOptions{})
+}
+
+// importOptions changes how the bundle is applied to the project. By default all entities must be new.
+// Synchronization (e.g. from a git repository) updates entities which already exist, creates the missing
+// ones and adds a new version to schemas whose content changed.
+type importOptions struct {
+	syncExisting bool
+	// importID is recorded on every created entity, commitSHA on every created schema version
+	importID  *uuid.UUID
+	commitSHA string
+}
+
+func validateProjectImportBundle(bundle *ImportBundle, projectID uuid.UUID, options importOptions) ImportValidationIssues {
+	resolved, resolveIssues := bundle.resolve()
+	if resolved == nil {
+		return resolveIssues
+	}
+	projectImport := resolved.projectImport
+
+	issues := newImportIssuesCollector(resolved.document, resolved.nodeFiles)
+
+	// Names of imported entities must not be used in the project yet, unless the project is synchronized
+	usedNames := make(usedProjectNames)
+	if !options.syncExisting {
+		usedNames = loadUsedProjectNames(projectID)
 	}
 
 	if projectImport.Version != 1 {
-		errors = append(errors, fmt.Sprintf("invalid version: %d, expected 1", projectImport.Version))
+		issues.errorf(ImportErrCodeUnsupportedVersion, "version", strconv.Itoa(projectImport.Version),
+			"invalid version: %d, expected 1", projectImport.Version)
 	}
 
 	// Create maps to track resources and servers for validation
@@ -96,13 +137,15 @@ func ValidateProjectImportYAML(yamlStr string, projectID uuid.UUID) []string {
 	resourceTypes := make(map[string]string)            // server.resource -> type
 
 	// Validate servers and their resources
-	for _, server := range projectImport.Servers {
+	for serverIndex, server := range projectImport.Servers {
+		serverPath := fmt.Sprintf("servers[%d]", serverIndex)
 		if server.Name == "" {
-			errors = append(errors, "server name is required")
+			issues.errorf(ImportErrCodeRequiredField, serverPath+".name", "", "server name is required")
 			continue
 		}
 		if server.Type == "" {
-			errors = append(errors, fmt.Sprintf("server type is required for server: %s", server.Name))
+			issues.errorf(ImportErrCodeRequiredField, serverPath+".type", "",
+				"server type is required for server: %s", server.Name)
 			continue
 		}
 
@@ -116,22 +159,30 @@ func ValidateProjectImportYAML(yamlStr string, projectID uuid.UUID) []string {
 			}
 		}
 		if !isValidProtocol {
-			errors = append(errors, fmt.Sprintf("invalid server type '%s' for server: %s", server.Type, server.Name))
+			issues.errorf(ImportErrCodeInvalidProtocol, serverPath+".type", server.Type,
+				"invalid server type '%s' for server: %s", server.Type, server.Name)
 		}
 
 		// Check server name uniqueness
-		serversManager := ServersObjectsManager{}
-		if !serversManager.CanNameBeUsed(server.Name, projectID) {
-			errors = append(errors, fmt.Sprintf("server name '%s' already exists in the project", server.Name))
+		if usedNames[importedServer][strings.TrimSpace(server.Name)] {
+			issues.errorf(ImportErrCodeNameAlreadyExists, serverPath+".name", server.Name,
+				"server name '%s' already exists in the project", server.Name)
 		}
 
 		serverTypes[server.Name] = server.Type
 		serverResources[server.Name] = make(map[string]bool)
 
+		if len(server.Resources) == 0 {
+			issues.warnf(ImportWarnCodeServerWithoutResources, serverPath, server.Name,
+				"server '%s' has no resources", server.Name)
+		}
+
 		// Validate resources
-		for _, resource := range server.Resources {
+		for resourceIndex, resource := range server.Resources {
+			resourcePath := fmt.Sprintf("%s.resources[%d]", serverPath, resourceIndex)
 			if resource.Name == "" {
-				errors = append(errors, fmt.Sprintf("resource name is required for server: %s", server.Name))
+				issues.errorf(ImportErrCodeRequiredField, resourcePath+".name", "",
+					"resource name is required for server: %s", server.Name)
 				continue
 			}
 
@@ -141,20 +192,23 @@ func ValidateProjectImportYAML(yamlStr string, projectID uuid.UUID) []string {
 			if protocol != "" && !strings.HasPrefix(protocol, "async+") {
 				protocol = "async+" + protocol
 			}
-			resourceURI := fmt.Sprintf("%s://%s@%s/%s/%s", 
+			resourceURI := fmt.Sprintf("%s://%s@%s/%s/%s",
 				protocol, server.Name, resource.Mode, resource.Type, resource.Name)
 			_, err := asyncuri.ParseAsyncResourceReference(resourceURI)
 			if err != nil {
-				errors = append(errors, fmt.Sprintf("invalid resource '%s' in server '%s': %v", resource.Name, server.Name, err))
+				issues.errorf(ImportErrCodeInvalidResource, resourcePath, resource.Name,
+					"invalid resource '%s' in server '%s': %v", resource.Name, server.Name, err)
 				continue
 			}
 
 			if resource.Mode == "" {
-				errors = append(errors, fmt.Sprintf("resource mode is required for resource: %s in server: %s", resource.Name, server.Name))
+				issues.errorf(ImportErrCodeRequiredField, resourcePath+".mode", "",
+					"resource mode is required for resource: %s in server: %s", resource.Name, server.Name)
 				continue
 			}
 			if resource.Type == "" {
-				errors = append(errors, fmt.Sprintf("resource type is required for resource: %s in server: %s", resource.Name, server.Name))
+				issues.errorf(ImportErrCodeRequiredField, resourcePath+".type", "",
+					"resource type is required for resource: %s in server: %s", resource.Name, server.Name)
 				continue
 			}
 
@@ -168,7 +222,8 @@ func ValidateProjectImportYAML(yamlStr string, projectID uuid.UUID) []string {
 				}
 			}
 			if !isValidMode {
-				errors = append(errors, fmt.Sprintf("invalid mode '%s' for resource: %s in server: %s", resource.Mode, resource.Name, server.Name))
+				issues.errorf(ImportErrCodeInvalidResourceMode, resourcePath+".mode", resource.Mode,
+					"invalid mode '%s' for resource: %s in server: %s", resource.Mode, resource.Name, server.Name)
 			}
 
 			// Validate type
@@ -181,7 +236,8 @@ func ValidateProjectImportYAML(yamlStr string, projectID uuid.UUID) []string {
 				}
 			}
 			if !isValidType {
-				errors = append(errors, fmt.Sprintf("invalid type '%s' for resource: %s in server: %s", resource.Type, resource.Name, server.Name))
+				issues.errorf(ImportErrCodeInvalidResourceType, resourcePath+".type", resource.Type,
+					"invalid type '%s' for resource: %s in server: %s", resource.Type, resource.Name, server.Name)
 			}
 
 			serverResources[server.Name][resource.Name] = true
@@ -191,339 +247,986 @@ func ValidateProjectImportYAML(yamlStr string, projectID uuid.UUID) []string {
 		}
 
 		// Validate binds
-		for _, bind := range server.Binds {
+		for bindIndex, bind := range server.Binds {
+			bindPath := fmt.Sprintf("%s.binds[%d]", serverPath, bindIndex)
 			if bind.Source == "" || bind.Target == "" {
-				errors = append(errors, fmt.Sprintf("bind source and target are required for server: %s", server.Name))
+				issues.errorf(ImportErrCodeRequiredField, bindPath, "",
+					"bind source and target are required for server: %s", server.Name)
 				continue
 			}
 			if !serverResources[server.Name][bind.Source] {
-				errors = append(errors, fmt.Sprintf("bind source '%s' not found in server: %s", bind.Source, server.Name))
+				issues.errorf(ImportErrCodeUnknownBindResource, bindPath+".source", bind.Source,
+					"bind source '%s' not found in server: %s", bind.Source, server.Name)
 			}
 			if !serverResources[server.Name][bind.Target] {
-				errors = append(errors, fmt.Sprintf("bind target '%s' not found in server: %s", bind.Target, server.Name))
+				issues.errorf(ImportErrCodeUnknownBindResource, bindPath+".target", bind.Target,
+					"bind target '%s' not found in server: %s", bind.Target, server.Name)
 			}
 		}
 	}
 
 	// Validate schemas
 	schemaNames := make(map[string]bool)
-	for _, schema := range projectImport.Schemas {
+	schemaPaths := make(map[string]string)
+	schemaTypes := make(map[string]string)
+	protobufSchemas := newImportedProtobufSchemas(projectID, projectImport.Schemas)
+	// Synchronization creates new versions of existing schemas, they must satisfy the compatibility modes
+	existingSchemas := make(map[string]db.SchemasDBModel)
+	if options.syncExisting {
+		existingSchemas = loadExistingProjectSchemas(projectID)
+	}
+	// References between JSON schemas of the import resolve to the versions the schemas are going to get
+	jsonSchemaVersions := importedJSONSchemaVersions(projectImport.Schemas, existingSchemas)
+	for schemaIndex, schema := range projectImport.Schemas {
+		schemaPath := fmt.Sprintf("schemas[%d]", schemaIndex)
 		if schema.Name == "" {
-			errors = append(errors, "schema name is required")
+			issues.errorf(ImportErrCodeRequiredField, schemaPath+".name", "", "schema name is required")
 			continue
 		}
 		if schema.Type == "" {
-			errors = append(errors, fmt.Sprintf("schema type is required for schema: %s", schema.Name))
+			issues.errorf(ImportErrCodeRequiredField, schemaPath+".type", "",
+				"schema type is required for schema: %s", schema.Name)
 			continue
 		}
 		if schema.Schema == "" {
-			errors = append(errors, fmt.Sprintf("schema content is required for schema: %s", schema.Name))
+			issues.errorf(ImportErrCodeRequiredField, schemaPath+".schema", "",
+				"schema content is required for schema: %s", schema.Name)
 			continue
 		}
 
-		// Validate schema type
-		if schema.Type != "jsonschema" {
-			errors = append(errors, fmt.Sprintf("invalid schema type '%s' for schema: %s (only 'jsonschema' is supported)", schema.Type, schema.Name))
-		}
-
-		// Validate JSON schema
-		if schema.Type == "jsonschema" {
-			_, err := jsonschema.CompileString("", schema.Schema)
-			if err != nil {
-				errors = append(errors, fmt.Sprintf("invalid JSON schema for schema '%s': %v", schema.Name, err))
+		// Validate schema type and content
+		switch schema.Type {
+		case SchemaTypeJSONSchema:
+			if _, err := compileJSONSchema(projectID, schema.Schema, jsonSchemaVersions); err != nil {
+				issues.errorf(ImportErrCodeInvalidSchema, schemaPath+".schema", "",
+					"invalid JSON schema for schema '%s': %v", schema.Name, schemaContentErrorMessage(err))
 			}
+		case SchemaTypeAvro:
+			if _, err := avro.Parse(schema.Schema); err != nil {
+				issues.errorf(ImportErrCodeInvalidSchema, schemaPath+".schema", "",
+					"invalid Avro schema for schema '%s': %v", schema.Name, err)
+			}
+		case SchemaTypeProtobuf:
+			if _, err := protobufSchemas.compile(schema.Name); err != nil {
+				issues.errorf(ImportErrCodeInvalidSchema, schemaPath+".schema", "",
+					"invalid Protocol Buffers schema for schema '%s': %v", schema.Name, schemaContentErrorMessage(err))
+			}
+		default:
+			issues.errorf(ImportErrCodeUnsupportedSchemaType, schemaPath+".type", schema.Type,
+				"invalid schema type '%s' for schema: %s (supported types: %s)",
+				schema.Type, schema.Name, strings.Join(SupportedSchemaTypes, ", "))
 		}
 
 		schemaNames[schema.Name] = true
+		schemaPaths[schema.Name] = schemaPath
+		schemaTypes[schema.Name] = schema.Type
 
 		// Check schema name uniqueness
-		schemasManager := SchemaObjectsManager{}
-		if !schemasManager.CanNameBeUsed(schema.Name, projectID) {
-			errors = append(errors, fmt.Sprintf("schema name '%s' already exists in the project", schema.Name))
+		if usedNames[importedSchema][strings.TrimSpace(schema.Name)] {
+			issues.errorf(ImportErrCodeNameAlreadyExists, schemaPath+".name", schema.Name,
+				"schema name '%s' already exists in the project", schema.Name)
 		}
+
+		if schema.Compatibility != "" {
+			if err := CheckCompatibilityModeOfType(schema.Type, schema.Compatibility); err != nil {
+				issues.errorf(ImportErrCodeInvalidCompatibility, schemaPath+".compatibility", schema.Compatibility,
+					"invalid compatibility mode for schema '%s': %v", schema.Name, err)
+				continue
+			}
+		}
+		validateSchemaCompatibilityOnSync(issues, existingSchemas, schema, schemaPath)
 	}
 
 	// Validate messages
 	messageNames := make(map[string]bool)
-	for _, message := range projectImport.Messages {
+	messagePaths := make(map[string]string)
+	usedSchemas := make(map[string]bool)
+	for messageIndex, message := range projectImport.Messages {
+		messagePath := fmt.Sprintf("messages[%d]", messageIndex)
 		if message.Name == "" {
-			errors = append(errors, "message name is required")
+			issues.errorf(ImportErrCodeRequiredField, messagePath+".name", "", "message name is required")
 			continue
 		}
 		if message.Schema.Name == "" {
-			errors = append(errors, fmt.Sprintf("schema reference is required for message: %s", message.Name))
+			issues.errorf(ImportErrCodeRequiredField, messagePath+".schema.name", "",
+				"schema reference is required for message: %s", message.Name)
 			continue
 		}
 		if !schemaNames[message.Schema.Name] {
-			errors = append(errors, fmt.Sprintf("schema '%s' referenced by message '%s' not found", message.Schema.Name, message.Name))
+			issues.errorf(ImportErrCodeUnknownSchema, messagePath+".schema.name", message.Schema.Name,
+				"schema '%s' referenced by message '%s' not found", message.Schema.Name, message.Name)
+		} else if schemaTypes[message.Schema.Name] != SchemaTypeProtobuf {
+			if message.Schema.MessageType != "" {
+				issues.errorf(ImportErrCodeUnknownMessageType, messagePath+".schema.message_type", message.Schema.MessageType,
+					"message '%s' selects a message type, but schema '%s' is not a protobuf schema", message.Name, message.Schema.Name)
+			}
+		} else if _, err := protobufSchemas.resolveMessageType(message.Schema); err != nil && !isSchemaContentError(err) {
+			// Invalid schemas are already reported
+			issues.errorf(ImportErrCodeUnknownMessageType, messagePath+".schema.message_type", message.Schema.MessageType,
+				"invalid message type for message '%s': %v", message.Name, err)
 		}
+		usedSchemas[message.Schema.Name] = true
 
 		messageNames[message.Name] = true
+		messagePaths[message.Name] = messagePath
 
 		// Check message name uniqueness
+		if usedNames[importedMessage][strings.TrimSpace(message.Name)] {
+			issues.errorf(ImportErrCodeNameAlreadyExists, messagePath+".name", message.Name,
+				"message name '%s' already exists in the project", message.Name)
+		}
+	}
+
+	// Synced messages keep their lifecycle, apps are warned about deprecated messages and can't use retired ones
+	deprecatedMessages := make(map[string]*MessageObject)
+	if options.syncExisting {
 		messagesManager := MessagesObjectsManager{}
-		if !messagesManager.CanNameBeUsed(message.Name, projectID) {
-			errors = append(errors, fmt.Sprintf("message name '%s' already exists in the project", message.Name))
+		if existingDeprecatedMessages, err := messagesManager.GetDeprecatedMessagesByName(projectID); err == nil {
+			deprecatedMessages = existingDeprecatedMessages
 		}
 	}
+	validateMessageLifecycle := func(path string, messageName string, appName string) {
+		message, deprecated := deprecatedMessages[messageName]
+		if !deprecated || !messageNames[messageName] {
+			return
+		}
+		notice := strings.Join(deprecationNotice("message "+messageName, message.dbModel.Lifecycle), " ")
+		if message.GetLifecycle().State == LifecycleRetired {
+			issues.errorf(ImportErrCodeRetiredMessage, path, messageName, "%s It can't be used by app '%s'", notice, appName)
+			return
+		}
+		issues.warnf(ImportWarnCodeDeprecatedMessage, path, messageName, "%s It is still used by app '%s'", notice, appName)
+	}
 
 	// Validate apps
-	for _, app := range projectImport.Apps {
+	usedMessages := make(map[string]bool)
+	for appIndex, app := range projectImport.Apps {
+		appPath := fmt.Sprintf("apps[%d]", appIndex)
 		if app.Name == "" {
-			errors = append(errors, "app name is required")
+			issues.errorf(ImportErrCodeRequiredField, appPath+".name", "", "app name is required")
 			continue
 		}
 
 		// Check app name uniqueness
-		appsManager := AppsObjectsManager{}
-		if !appsManager.CanNameBeUsed(app.Name, projectID) {
-			errors = append(errors, fmt.Sprintf("app name '%s' already exists in the project", app.Name))
+		if usedNames[importedApp][strings.TrimSpace(app.Name)] {
+			issues.errorf(ImportErrCodeNameAlreadyExists, appPath+".name", app.Name,
+				"app name '%s' already exists in the project", app.Name)
 		}
 
 		// Validate sends
-		for _, send := range app.Sends {
+		for sendIndex, send := range app.Sends {
+			sendPath := fmt.Sprintf("%s.sends[%d]", appPath, sendIndex)
 			if send.Message == "" {
-				errors = append(errors, fmt.Sprintf("message is required for send in app: %s", app.Name))
+				issues.errorf(ImportErrCodeRequiredField, sendPath+".message", "",
+					"message is required for send in app: %s", app.Name)
 				continue
 			}
 			if send.Resource == "" {
-				errors = append(errors, fmt.Sprintf("resource is required for send in app: %s", app.Name))
+				issues.errorf(ImportErrCodeRequiredField, sendPath+".resource", "",
+					"resource is required for send in app: %s", app.Name)
 				continue
 			}
 			if !messageNames[send.Message] {
-				errors = append(errors, fmt.Sprintf("message '%s' referenced in app '%s' send not found", send.Message, app.Name))
+				issues.errorf(ImportErrCodeUnknownMessage, sendPath+".message", send.Message,
+					"message '%s' referenced in app '%s' send not found", send.Message, app.Name)
 			}
+			validateMessageLifecycle(sendPath+".message", send.Message, app.Name)
+			usedMessages[send.Message] = true
 
 			// Validate resource reference using asyncresourceuri
 			_, err := asyncuri.ParseAsyncResourceReference(send.Resource)
 			if err != nil {
-				errors = append(errors, fmt.Sprintf("invalid resource reference '%s' in app '%s' send: %v", send.Resource, app.Name, err))
+				issues.errorf(ImportErrCodeInvalidResourceReference, sendPath+".resource", send.Resource,
+					"invalid resource reference '%s' in app '%s' send: %v", send.Resource, app.Name, err)
 			}
 		}
 
 		// Validate receives
-		for _, receive := range app.Receives {
+		for receiveIndex, receive := range app.Receives {
+			receivePath := fmt.Sprintf("%s.receives[%d]", appPath, receiveIndex)
 			if receive.Message == "" {
-				errors = append(errors, fmt.Sprintf("message is required for receive in app: %s", app.Name))
+				issues.errorf(ImportErrCodeRequiredField, receivePath+".message", "",
+					"message is required for receive in app: %s", app.Name)
 				continue
 			}
 			if receive.Resource == "" {
-				errors = append(errors, fmt.Sprintf("resource is required for receive in app: %s", app.Name))
+				issues.errorf(ImportErrCodeRequiredField, receivePath+".resource", "",
+					"resource is required for receive in app: %s", app.Name)
 				continue
 			}
 			if !messageNames[receive.Message] {
-				errors = append(errors, fmt.Sprintf("message '%s' referenced in app '%s' receive not found", receive.Message, app.Name))
+				issues.errorf(ImportErrCodeUnknownMessage, receivePath+".message", receive.Message,
+					"message '%s' referenced in app '%s' receive not found", receive.Message, app.Name)
 			}
+			validateMessageLifecycle(receivePath+".message", receive.Message, app.Name)
+			usedMessages[receive.Message] = true
 
 			// Validate resource reference using asyncresourceuri
 			_, err := asyncuri.ParseAsyncResourceReference(receive.Resource)
 			if err != nil {
-				errors = append(errors, fmt.Sprintf("invalid resource reference '%s' in app '%s' receive: %v", receive.Resource, app.Name, err))
+				issues.errorf(ImportErrCodeInvalidResourceReference, receivePath+".resource", receive.Resource,
+					"invalid resource reference '%s' in app '%s' receive: %v", receive.Resource, app.Name, err)
 			}
 		}
 	}
 
-	return errors
+	// Entities nobody refers to are allowed, but usually indicate a typo somewhere else
+	for _, schema := range projectImport.Schemas {
+		if schema.Name != "" && !usedSchemas[schema.Name] {
+			issues.warnf(ImportWarnCodeSchemaNotUsedByMessages, schemaPaths[schema.Name], schema.Name,
+				"schema '%s' is not used by any message", schema.Name)
+		}
+	}
+	for _, message := range projectImport.Messages {
+		if message.Name != "" && !usedMessages[message.Name] {
+			issues.warnf(ImportWarnCodeMessageNotUsedByApps, messagePaths[message.Name], message.Name,
+				"message '%s' is not sent or received by any app", message.Name)
+		}
+	}
+
+	return issues.issues
 }
 
 // ImportProjectFromYAML imports the project architecture from YAML
 func ImportProjectFromYAML(yamlStr string, projectID uuid.UUID, userID uuid.UUID) error {
-	var projectImport ProjectImportYAML
+	return ImportProjectFromBundle(NewSingleFileImportBundle(yamlStr), projectID, userID)
+}
 
-	err := yaml.Unmarshal([]byte(yamlStr), &projectImport)
-	if err != nil {
-		return fmt.Errorf("failed to unmarshal YAML: %v", err)
+// ImportProjectFromBundle imports the project architecture defined by a multi-file bundle
+func ImportProjectFromBundle(bundle *ImportBundle, projectID uuid.UUID, userID uuid.UUID) error {
+	return importProjectFromBundle(context.Background(), bundle, projectID, userID, noImportProgress{}, importOptions{})
+}
+
+// importProgressListener is notified when the importer moves to the next phase and every time
+// a batch of entities is created. Import jobs use it to report progress.
+type importProgressListener interface {
+	PhaseStarted(phase string)
+	EntitiesCreated(kind string, count int)
+}
+
+type noImportProgress struct{}
+
+func (noImportProgress) PhaseStarted(string)         {}
+func (noImportProgress) EntitiesCreated(string, int) {}
+
+// Kinds of entities reported to importProgressListener
+const (
+	importedServer        = "server"
+	importedResource      = "resource"
+	importedBinding       = "binding"
+	importedSchema        = "schema"
+	importedSchemaVersion = "schema_version"
+	importedMessage       = "message"
+	importedApp           = "app"
+	importedConnection    = "connection"
+)
+
+// Entities are inserted in batches of this size, large projects contain thousands of entities
+const importBatchSize = 500
+
+// importProjectFromBundle creates all entities of the bundle in one transaction. Entities of the same kind
+// are inserted together in batches, the context is checked between the phases and aborts the running
+// batch insert. A failed or cancelled import (ErrImportCancelled) leaves the project unchanged.
+func importProjectFromBundle(
+	ctx context.Context,
+	bundle *ImportBundle,
+	projectID uuid.UUID,
+	userID uuid.UUID,
+	progress importProgressListener,
+	options importOptions) error {
+	resolved, resolveIssues := bundle.resolve()
+	if resolved == nil {
+		return fmt.Errorf("failed to read import bundle: %s", resolveIssues[0].Message)
+	}
+
+	err := db.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return importProjectEntities(ctx, tx, resolved.projectImport, projectID, userID, progress, options)
+	})
+	if err != nil && ctx.Err() != nil {
+		return ErrImportCancelled
+	}
+	if err == nil {
+		if catalogErr := catalogProjectSchemaFields(projectID); catalogErr != nil {
+			log.Warnf("Failed to catalog fields of schemas imported into project %s: %v", projectID, catalogErr)
+		}
+	}
+	return err
+}
+
+// importProjectEntities creates entities of the architecture in the transaction, phase by phase
+func importProjectEntities(
+	ctx context.Context,
+	tx *gorm.DB,
+	projectImport ProjectImportYAML,
+	projectID uuid.UUID,
+	userID uuid.UUID,
+	progress importProgressListener,
+	options importOptions) error {
+	existing := newExistingProjectEntities()
+	if options.syncExisting {
+		var err error
+		if existing, err = loadExistingProjectEntities(tx, projectID); err != nil {
+			return fmt.Errorf("failed to load existing entities of the project: %v", err)
+		}
+	}
+
+	createInBatches := func(kind string, rows interface{}, count int) error {
+		if count == 0 {
+			return nil
+		}
+		if err := tx.CreateInBatches(rows, importBatchSize).Error; err != nil {
+			if ctx.Err() != nil {
+				return ErrImportCancelled
+			}
+			return fmt.Errorf("failed to create %ss: %v", strings.ReplaceAll(kind, "_", " "), err)
+		}
+		progress.EntitiesCreated(kind, count)
+		return nil
 	}
 
 	// Import servers and resources
+	progress.PhaseStarted(ImportJobPhaseServers)
+	if ctx.Err() != nil {
+		return ErrImportCancelled
+	}
 	serverIDMap := make(map[string]uuid.UUID)
 	resourceIDMap := make(map[string]uuid.UUID)
 
-	serversManager := ServersObjectsManager{}
-	resourcesManager := ResourcesObjectsManager{}
-
+	var newServers []db.ServersDBModel
+	var newServerNames []string
 	for _, server := range projectImport.Servers {
-		// Create server
-		serverObj, err := serversManager.CreateANewServer(
-			server.Name,
-			server.Description,
-			server.Type,
-			projectID,
-			userID,
-		)
-		if err != nil {
-			return fmt.Errorf("failed to create server %s: %v", server.Name, err)
+		if existingServer, serverExists := existing.servers[strings.TrimSpace(server.Name)]; serverExists {
+			serverIDMap[server.Name] = existingServer.ID
+			changes := entityChanges{}
+			changes.compare("protocol", existingServer.Protocol, server.Type)
+			changes.compare("description", existingServer.Description, server.Description)
+			if err := changes.apply(tx, &db.ServersDBModel{}, existingServer.ID); err != nil {
+				return fmt.Errorf("failed to update server %s: %v", server.Name, err)
+			}
+			continue
 		}
-		serverIDMap[server.Name] = serverObj.GetID()
+		newServers = append(newServers, db.ServersDBModel{
+			Name:            strings.TrimSpace(server.Name),
+			Description:     server.Description,
+			Protocol:        server.Type,
+			ProjectID:       projectID,
+			CreatedByUserID: userID,
+			Status:          "active",
+			ImportID:        options.importID,
+		})
+		newServerNames = append(newServerNames, server.Name)
+	}
+	if err := createInBatches(importedServer, &newServers, len(newServers)); err != nil {
+		return err
+	}
+	for index, server := range newServers {
+		serverIDMap[newServerNames[index]] = server.ID
+	}
 
-		// Create resources
+	var newResources []db.ResourcesDBModel
+	var newResourceKeys []string
+	for _, server := range projectImport.Servers {
 		for _, resource := range server.Resources {
-			resourceObj, err := resourcesManager.CreateANewResource(
-				serverObj.GetID(),
-				projectID,
-				resource.Name,
-				resource.Mode,
-				resource.Type,
-				resource.Description,
-				userID,
-			)
-			if err != nil {
-				return fmt.Errorf("failed to create resource %s in server %s: %v", resource.Name, server.Name, err)
-			}
 			resourceKey := fmt.Sprintf("%s.%s", server.Name, resource.Name)
-			resourceIDMap[resourceKey] = resourceObj.GetID()
+			existingResourceKey := strings.TrimSpace(server.Name) + "." + strings.TrimSpace(resource.Name)
+			if existingResource, resourceExists := existing.resources[existingResourceKey]; resourceExists {
+				resourceIDMap[resourceKey] = existingResource.ID
+				changes := entityChanges{}
+				changes.compare("mode", existingResource.Mode, resource.Mode)
+				changes.compare("resource_type", existingResource.ResourceType, resource.Type)
+				changes.compare("description", existingResource.Description, resource.Description)
+				if err := changes.apply(tx, &db.ResourcesDBModel{}, existingResource.ID); err != nil {
+					return fmt.Errorf("failed to update resource %s: %v", resourceKey, err)
+				}
+				continue
+			}
+			newResources = append(newResources, db.ResourcesDBModel{
+				ServerID:        serverIDMap[server.Name],
+				Name:            strings.TrimSpace(resource.Name),
+				Mode:            resource.Mode,
+				ResourceType:    resource.Type,
+				Description:     resource.Description,
+				ProjectID:       projectID,
+				CreatedByUserID: userID,
+				Status:          "active",
+				ImportID:        options.importID,
+			})
+			newResourceKeys = append(newResourceKeys, resourceKey)
 		}
+	}
+	if err := createInBatches(importedResource, &newResources, len(newResources)); err != nil {
+		return err
+	}
+	for index, resource := range newResources {
+		resourceIDMap[newResourceKeys[index]] = resource.ID
+	}
 
-		// Create bindings
-		bindingsManager := ResourceBindingsObjectsManager{}
+	// Create bindings
+	var newBindings []db.ResourceBindingsDBModel
+	for _, server := range projectImport.Servers {
 		for _, bind := range server.Binds {
 			sourceKey := fmt.Sprintf("%s.%s", server.Name, bind.Source)
 			targetKey := fmt.Sprintf("%s.%s", server.Name, bind.Target)
-			
+
 			sourceID, sourceExists := resourceIDMap[sourceKey]
 			targetID, targetExists := resourceIDMap[targetKey]
-			
+
 			if !sourceExists || !targetExists {
 				continue // Skip if resources not found
 			}
-
-			_, err := bindingsManager.CreateABinding(sourceID, targetID)
-			if err != nil {
-				return fmt.Errorf("failed to create binding between %s and %s: %v", bind.Source, bind.Target, err)
+			if existing.bindings[[2]uuid.UUID{sourceID, targetID}] || existing.bindings[[2]uuid.UUID{targetID, sourceID}] {
+				continue
 			}
+
+			newBindings = append(newBindings, db.ResourceBindingsDBModel{
+				SourceResourceID: sourceID,
+				TargetResourceID: targetID,
+				ImportID:         options.importID,
+			})
 		}
 	}
+	if err := createInBatches(importedBinding, &newBindings, len(newBindings)); err != nil {
+		return err
+	}
 
-	// Import schemas
+	// Import schemas. New schemas get their first version, schemas whose content changed
+	// since the previous synchronization get a new version.
+	progress.PhaseStarted(ImportJobPhaseSchemas)
+	if ctx.Err() != nil {
+		return ErrImportCancelled
+	}
 	schemaIDMap := make(map[string]uuid.UUID)
-	schemasManager := SchemaObjectsManager{}
+	schemaVersionMap := make(map[string]int)
 
+	// New schemas without a compatibility mode get the default mode of the project
+	var project db.ProjectsDBModel
+	if err := tx.First(&project, "id = ?", projectID).Error; err != nil {
+		return fmt.Errorf("failed to load the project: %v", err)
+	}
+
+	var newSchemas []db.SchemasDBModel
+	var newSchemaNames []string
+	var changedSchemas []SchemaImport
 	for _, schema := range projectImport.Schemas {
-		schemaObj, err := schemasManager.CreateANewSchema(
-			schema.Name,
-			schema.Description,
-			schema.Schema,
-			schema.Type,
-			"user",
-			userID,
-			userID,
-			projectID,
-		)
-		if err != nil {
-			return fmt.Errorf("failed to create schema %s: %v", schema.Name, err)
+		existingSchema, schemaExists := existing.schemas[strings.TrimSpace(schema.Name)]
+		if !schemaExists {
+			newSchemas = append(newSchemas, db.SchemasDBModel{
+				Name:          strings.TrimSpace(schema.Name),
+				Description:   schema.Description,
+				Schema:        schema.Schema,
+				Type:          schema.Type,
+				Version:       1,
+				Compatibility: importedSchemaCompatibility(schema, defaultSchemaCompatibility(&project, schema.Type)),
+				Status:        "active",
+				CreatedByType: "user",
+				CreatedByID:   userID,
+				ProjectID:     projectID,
+				ImportID:      options.importID,
+			})
+			newSchemaNames = append(newSchemaNames, schema.Name)
+			continue
+		}
+
+		if existingSchema.Type != schema.Type {
+			return fmt.Errorf("type of schema %s can't be changed from %s to %s", schema.Name, existingSchema.Type, schema.Type)
+		}
+		schemaIDMap[schema.Name] = existingSchema.ID
+		schemaVersionMap[schema.Name] = existingSchema.Version
+		if existingSchema.Schema != schema.Schema {
+			changedSchemas = append(changedSchemas, schema)
+			continue
+		}
+		changes := entityChanges{}
+		changes.compare("compatibility", existingSchema.Compatibility,
+			importedSchemaCompatibility(schema, existingSchema.Compatibility))
+		changes.compare("description", existingSchema.Description, schema.Description)
+		if err := changes.apply(tx, &db.SchemasDBModel{}, existingSchema.ID); err != nil {
+			return fmt.Errorf("failed to update schema %s: %v", schema.Name, err)
+		}
+	}
+	if err := createInBatches(importedSchema, &newSchemas, len(newSchemas)); err != nil {
+		return err
+	}
+	newSchemaVersions := make([]db.SchemaVersionsDBModel, 0, len(newSchemas))
+	for index, schema := range newSchemas {
+		schemaIDMap[newSchemaNames[index]] = schema.ID
+		schemaVersionMap[newSchemaNames[index]] = schema.Version
+		newSchemaVersions = append(newSchemaVersions, db.SchemaVersionsDBModel{
+			SchemaID:  schema.ID,
+			UserID:    userID,
+			Version:   schema.Version,
+			Schema:    schema.Schema,
+			CommitSHA: options.commitSHA,
+		})
+	}
+	if err := createInBatches(importedSchemaVersion, &newSchemaVersions, len(newSchemaVersions)); err != nil {
+		return err
+	}
+
+	// References between JSON schemas are recorded once all schemas of the import exist
+	for index, schema := range newSchemas {
+		if schema.Type != SchemaTypeJSONSchema {
+			continue
+		}
+		if err := recordSchemaReferences(tx, projectID, schema.ID, schema.Version, schema.Schema); err != nil {
+			return fmt.Errorf("failed to record references of schema %s: %v", newSchemaNames[index], err)
+		}
+	}
+
+	// Changed schemas get a new version like versions created by users, new schemas of the import
+	// which they reference exist at this point
+	for _, schema := range changedSchemas {
+		schemaObject := &SchemaObject{dbModel: existing.schemas[strings.TrimSpace(schema.Name)]}
+		schemaObject.dbModel.Compatibility = importedSchemaCompatibility(schema, schemaObject.dbModel.Compatibility)
+		schemaObject.dbModel.Description = schema.Description
+		if err := schemaObject.createANewVersion(tx, schema.Schema, userID, false, options.commitSHA); err != nil {
+			return fmt.Errorf("failed to create a new version of schema %s: %v", schema.Name, err)
 		}
-		schemaIDMap[schema.Name] = schemaObj.GetID()
+		schemaVersionMap[schema.Name] = schemaObject.GetCurrentVersion()
+		progress.EntitiesCreated(importedSchemaVersion, 1)
 	}
 
 	// Import messages
+	protobufSchemas := newImportedProtobufSchemas(projectID, projectImport.Schemas)
+	progress.PhaseStarted(ImportJobPhaseMessages)
+	if ctx.Err() != nil {
+		return ErrImportCancelled
+	}
 	messageIDMap := make(map[string]uuid.UUID)
-	messagesManager := MessagesObjectsManager{}
 
+	var newMessages []db.MessagesDBModel
+	var newMessageNames []string
 	for _, message := range projectImport.Messages {
 		schemaID, schemaExists := schemaIDMap[message.Schema.Name]
 		if !schemaExists {
 			return fmt.Errorf("schema %s not found for message %s", message.Schema.Name, message.Name)
 		}
 
-		// Get latest schema version
-		schemaObj, _ := schemasManager.GetByID(schemaID)
-		latestVersion := schemaObj.GetLatestVersion()
-
-		messageObj, err := messagesManager.CreateANewMessage(
-			message.Description,
-			userID,
-			projectID,
-			message.Name,
-			schemaID,
-			latestVersion,
-		)
+		schemaMessageType, err := protobufSchemas.resolveMessageType(message.Schema)
 		if err != nil {
-			return fmt.Errorf("failed to create message %s: %v", message.Name, err)
+			return fmt.Errorf("invalid message type for message %s: %v", message.Name, err)
 		}
-		messageIDMap[message.Name] = messageObj.GetID()
+
+		// Messages use the latest version of the schema, synchronized messages move to new versions
+		if existingMessage, messageExists := existing.messages[strings.TrimSpace(message.Name)]; messageExists {
+			messageIDMap[message.Name] = existingMessage.ID
+			changes := entityChanges{}
+			changes.compare("description", existingMessage.Description, message.Description)
+			changes.compare("schema_id", existingMessage.SchemaID, schemaID)
+			changes.compare("schema_version", existingMessage.SchemaVersion, schemaVersionMap[message.Schema.Name])
+			changes.compare("schema_message_type", existingMessage.SchemaMessageType, schemaMessageType)
+			if err := changes.apply(tx, &db.MessagesDBModel{}, existingMessage.ID); err != nil {
+				return fmt.Errorf("failed to update message %s: %v", message.Name, err)
+			}
+			continue
+		}
+		newMessages = append(newMessages, db.MessagesDBModel{
+			Name:              strings.TrimSpace(message.Name),
+			Description:       message.Description,
+			ProjectID:         projectID,
+			SchemaID:          schemaID,
+			SchemaVersion:     schemaVersionMap[message.Schema.Name],
+			SchemaMessageType: schemaMessageType,
+			Status:            "active",
+			CreatedByID:       userID,
+			ImportID:          options.importID,
+		})
+		newMessageNames = append(newMessageNames, message.Name)
+	}
+	if err := createInBatches(importedMessage, &newMessages, len(newMessages)); err != nil {
+		return err
+	}
+	for index, message := range newMessages {
+		messageIDMap[newMessageNames[index]] = message.ID
 	}
 
 	// Import apps
-	appsManager := AppsObjectsManager{}
-	appResourceMessagesManager := AppsResourcesMessagesObjectsManager{}
-	
+	progress.PhaseStarted(ImportJobPhaseApps)
+	if ctx.Err() != nil {
+		return ErrImportCancelled
+	}
+	appIDMap := make(map[string]uuid.UUID)
+
+	var newApps []db.AppsDBModel
+	var newAppNames []string
 	for _, app := range projectImport.Apps {
-		appObj, err := appsManager.CreateANewApp(
-			app.Name,
-			app.Description,
-			projectID,
-			userID,
-		)
+		if existingApp, appExists := existing.apps[strings.TrimSpace(app.Name)]; appExists {
+			appIDMap[app.Name] = existingApp.ID
+			changes := entityChanges{}
+			changes.compare("description", existingApp.Description, app.Description)
+			if err := changes.apply(tx, &db.AppsDBModel{}, existingApp.ID); err != nil {
+				return fmt.Errorf("failed to update app %s: %v", app.Name, err)
+			}
+			continue
+		}
+		newApps = append(newApps, db.AppsDBModel{
+			Name:            strings.TrimSpace(app.Name),
+			Description:     app.Description,
+			ProjectID:       projectID,
+			CreatedByUserID: userID,
+			Status:          "active",
+			ImportID:        options.importID,
+		})
+		newAppNames = append(newAppNames, app.Name)
+	}
+	if err := createInBatches(importedApp, &newApps, len(newApps)); err != nil {
+		return err
+	}
+	for index, app := range newApps {
+		appIDMap[newAppNames[index]] = app.ID
+	}
+
+	// Process app sends and receives
+	var newConnections []db.AppResourceMessagesDBModel
+	addConnection := func(appName string, messageName string, resourceURI string, direction string) error {
+		// Operation is named after the section of the app, e.g. "send" for "sends"
+		operation := strings.TrimSuffix(direction, "s")
+
+		messageID, exists := messageIDMap[messageName]
+		if !exists {
+			return fmt.Errorf("message '%s' not found for app '%s' %s", messageName, appName, operation)
+		}
+
+		// Parse the resource URI to get resource ID
+		parsedResource, err := asyncuri.ParseAsyncResourceReference(resourceURI)
 		if err != nil {
-			return fmt.Errorf("failed to create app %s: %v", app.Name, err)
+			return fmt.Errorf("failed to parse resource URI for app '%s' %s: %v", appName, operation, err)
+		}
+
+		// Find the resource ID from the map
+		resourceKey := fmt.Sprintf("%s.%s", parsedResource.Server, parsedResource.Name)
+		resourceID, exists := resourceIDMap[resourceKey]
+		if !exists {
+			return fmt.Errorf("resource '%s' not found for app '%s' %s (looking for key: %s)",
+				resourceURI, appName, operation, resourceKey)
+		}
+
+		appID := appIDMap[appName]
+		if existing.connections[appConnectionKey{appID, resourceID, messageID, direction}] {
+			return nil
 		}
+		newConnections = append(newConnections, db.AppResourceMessagesDBModel{
+			AppID:           appID,
+			ResourceID:      resourceID,
+			MessageID:       messageID,
+			Direction:       direction,
+			CreatedByUserID: userID,
+			Status:          "active",
+			ImportID:        options.importID,
+		})
+		return nil
+	}
 
-		// Process app sends
+	for _, app := range projectImport.Apps {
 		for _, send := range app.Sends {
-			messageID, exists := messageIDMap[send.Message]
-			if !exists {
-				return fmt.Errorf("message '%s' not found for app '%s' send", send.Message, app.Name)
+			if err := addConnection(app.Name, send.Message, send.Resource, "sends"); err != nil {
+				return err
 			}
-
-			// Parse the resource URI to get resource ID
-			parsedResource, err := asyncuri.ParseAsyncResourceReference(send.Resource)
-			if err != nil {
-				return fmt.Errorf("failed to parse resource URI for app '%s' send: %v", app.Name, err)
+		}
+		for _, receive := range app.Receives {
+			if err := addConnection(app.Name, receive.Message, receive.Resource, "receives"); err != nil {
+				return err
 			}
+		}
+	}
+	if err := createInBatches(importedConnection, &newConnections, len(newConnections)); err != nil {
+		return err
+	}
 
-			// Find the resource ID from the map
-			resourceKey := fmt.Sprintf("%s.%s", parsedResource.Server, parsedResource.Name)
-			resourceID, exists := resourceIDMap[resourceKey]
-			if !exists {
-				return fmt.Errorf("resource '%s' not found for app '%s' send (looking for key: %s)", send.Resource, app.Name, resourceKey)
-			}
+	return nil
+}
 
-			// Create the app-resource-message connection for sends
-			_, err = appResourceMessagesManager.CreateConnection(
-				appObj.GetID(),
-				resourceID,
-				messageID,
-				"sends",
-				userID,
-			)
-			if err != nil {
-				return fmt.Errorf("failed to create send connection for app '%s': %v", app.Name, err)
-			}
+// existingProjectEntities contains entities which already exist in the project, by the names
+// used in architecture files. Synchronization updates them instead of creating new ones.
+type existingProjectEntities struct {
+	servers     map[string]db.ServersDBModel
+	resources   map[string]db.ResourcesDBModel // server.resource -> resource
+	bindings    map[[2]uuid.UUID]bool
+	schemas     map[string]db.SchemasDBModel
+	messages    map[string]db.MessagesDBModel
+	apps        map[string]db.AppsDBModel
+	connections map[appConnectionKey]bool
+}
+
+type appConnectionKey struct {
+	appID      uuid.UUID
+	resourceID uuid.UUID
+	messageID  uuid.UUID
+	direction  string
+}
+
+func newExistingProjectEntities() *existingProjectEntities {
+	return &existingProjectEntities{
+		servers:     make(map[string]db.ServersDBModel),
+		resources:   make(map[string]db.ResourcesDBModel),
+		bindings:    make(map[[2]uuid.UUID]bool),
+		schemas:     make(map[string]db.SchemasDBModel),
+		messages:    make(map[string]db.MessagesDBModel),
+		apps:        make(map[string]db.AppsDBModel),
+		connections: make(map[appConnectionKey]bool),
+	}
+}
+
+func loadExistingProjectEntities(connection *gorm.DB, projectID uuid.UUID) (*existingProjectEntities, error) {
+	existing := newExistingProjectEntities()
+
+	var servers []db.ServersDBModel
+	if err := connection.Where("project_id = ? AND status = 'active'", projectID).Find(&servers).Error; err != nil {
+		return nil, err
+	}
+	serverNames := make(map[uuid.UUID]string, len(servers))
+	for _, server := range servers {
+		existing.servers[server.Name] = server
+		serverNames[server.ID] = server.Name
+	}
+
+	var resources []db.ResourcesDBModel
+	if err := connection.Where("project_id = ? AND status = 'active'", projectID).Find(&resources).Error; err != nil {
+		return nil, err
+	}
+	for _, resource := range resources {
+		if serverName, exists := serverNames[resource.ServerID]; exists {
+			existing.resources[serverName+"."+resource.Name] = resource
 		}
+	}
 
-		// Process app receives
-		for _, receive := range app.Receives {
-			messageID, exists := messageIDMap[receive.Message]
-			if !exists {
-				return fmt.Errorf("message '%s' not found for app '%s' receive", receive.Message, app.Name)
-			}
+	var bindings []db.ResourceBindingsDBModel
+	err := connection.
+		Where("source_resource_id IN (?)",
+			connection.Model(&db.ResourcesDBModel{}).Select("id").Where("project_id = ?", projectID)).
+		Find(&bindings).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, binding := range bindings {
+		existing.bindings[[2]uuid.UUID{binding.SourceResourceID, binding.TargetResourceID}] = true
+	}
 
-			// Parse the resource URI to get resource ID
-			parsedResource, err := asyncuri.ParseAsyncResourceReference(receive.Resource)
-			if err != nil {
-				return fmt.Errorf("failed to parse resource URI for app '%s' receive: %v", app.Name, err)
-			}
+	var schemas []db.SchemasDBModel
+	if err := connection.Where("project_id = ? AND status = 'active'", projectID).Find(&schemas).Error; err != nil {
+		return nil, err
+	}
+	for _, schema := range schemas {
+		existing.schemas[schema.Name] = schema
+	}
 
-			// Find the resource ID from the map
-			resourceKey := fmt.Sprintf("%s.%s", parsedResource.Server, parsedResource.Name)
-			resourceID, exists := resourceIDMap[resourceKey]
-			if !exists {
-				return fmt.Errorf("resource '%s' not found for app '%s' receive (looking for key: %s)", receive.Resource, app.Name, resourceKey)
-			}
+	var messages []db.MessagesDBModel
+	if err := connection.Where("project_id = ? AND status = 'active'", projectID).Find(&messages).Error; err != nil {
+		return nil, err
+	}
+	for _, message := range messages {
+		existing.messages[message.Name] = message
+	}
 
-			// Create the app-resource-message connection for receives
-			_, err = appResourceMessagesManager.CreateConnection(
-				appObj.GetID(),
-				resourceID,
-				messageID,
-				"receives",
-				userID,
-			)
-			if err != nil {
-				return fmt.Errorf("failed to create receive connection for app '%s': %v", app.Name, err)
+	var apps []db.AppsDBModel
+	if err := connection.Where("project_id = ? AND status = 'active'", projectID).Find(&apps).Error; err != nil {
+		return nil, err
+	}
+	for _, app := range apps {
+		existing.apps[app.Name] = app
+	}
+
+	var connections []db.AppResourceMessagesDBModel
+	err = connection.
+		Where("status = 'active' AND app_id IN (?)",
+			connection.Model(&db.AppsDBModel{}).Select("id").Where("project_id = ?", projectID)).
+		Find(&connections).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, appConnection := range connections {
+		existing.connections[appConnectionKey{
+			appConnection.AppID, appConnection.ResourceID, appConnection.MessageID, appConnection.Direction,
+		}] = true
+	}
+
+	return existing, nil
+}
+
+// entityChanges contains columns of an existing entity whose values differ from architecture files
+type entityChanges map[string]interface{}
+
+// compare records the imported value of the column if it differs from the current one
+func (changes entityChanges) compare(column string, currentValue interface{}, importedValue interface{}) {
+	if currentValue != importedValue {
+		changes[column] = importedValue
+	}
+}
+
+// apply updates the changed columns of the entity
+func (changes entityChanges) apply(connection *gorm.DB, model interface{}, id uuid.UUID) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	return connection.Model(model).Where("id = ?", id).Updates(map[string]interface{}(changes)).Error
+}
+
+// loadExistingProjectSchemas returns active schemas of the project by name
+func loadExistingProjectSchemas(projectID uuid.UUID) map[string]db.SchemasDBModel {
+	var schemas []db.SchemasDBModel
+	db.GetDB().Where("project_id = ? AND status = 'active'", projectID).Find(&schemas)
+
+	schemasByName := make(map[string]db.SchemasDBModel, len(schemas))
+	for _, schema := range schemas {
+		schemasByName[schema.Name] = schema
+	}
+	return schemasByName
+}
+
+// importedSchemaCompatibility returns the compatibility mode of the imported schema,
+// the current mode is kept when the architecture file doesn't set one
+func importedSchemaCompatibility(schema SchemaImport, currentMode string) string {
+	if schema.Compatibility != "" {
+		return schema.Compatibility
+	}
+	if currentMode == "" {
+		return CompatibilityNone
+	}
+	return currentMode
+}
+
+// validateSchemaCompatibilityOnSync reports violations of the compatibility mode by the new version
+// which synchronization creates when content of an existing schema changed. Versions of a schema share
+// its type, so synchronization can't change types of existing schemas.
+func validateSchemaCompatibilityOnSync(issues *importIssuesCollector, existingSchemas map[string]db.SchemasDBModel,
+	schema SchemaImport, schemaPath string) {
+	existingSchema, exists := existingSchemas[strings.TrimSpace(schema.Name)]
+	if !exists {
+		return
+	}
+	if existingSchema.Type != schema.Type {
+		issues.errorf(ImportErrCodeSchemaTypeChanged, schemaPath+".type", schema.Type,
+			"type of existing schema '%s' can't be changed from '%s' to '%s' during synchronization",
+			schema.Name, existingSchema.Type, schema.Type)
+		return
+	}
+	if existingSchema.Schema == schema.Schema {
+		return
+	}
+	schemaObject := &SchemaObject{dbModel: existingSchema}
+	schemaObject.dbModel.Compatibility = importedSchemaCompatibility(schema, existingSchema.Compatibility)
+	violations, err := schemaObject.CheckCompatibilityOfNewVersion(schema.Schema)
+	if err != nil {
+		// Invalid content is reported by the validation of the schema content
+		return
+	}
+	for _, violation := range violations {
+		issues.errorf(ImportErrCodeIncompatibleSchemaVersion, schemaPath+".schema", "",
+			"new version of schema '%s' is not %s compatible with version %d: %s: %s",
+			schema.Name, violation.Direction, violation.Version, violation.Path, violation.Message)
+	}
+}
+
+// usedProjectNames contains names of active servers, schemas, messages and apps of the project, by kind.
+// Validation loads them at once instead of checking names of imported entities one by one.
+type usedProjectNames map[string]map[string]bool
+
+func loadUsedProjectNames(projectID uuid.UUID) usedProjectNames {
+	names := make(usedProjectNames)
+	models := map[string]interface{}{
+		importedServer:  &db.ServersDBModel{},
+		importedSchema:  &db.SchemasDBModel{},
+		importedMessage: &db.MessagesDBModel{},
+		importedApp:     &db.AppsDBModel{},
+	}
+	for kind, model := range models {
+		var kindNames []string
+		db.GetDB().Model(model).Where("project_id = ? AND status = 'active'", projectID).Pluck("name", &kindNames)
+		names[kind] = make(map[string]bool, len(kindNames))
+		for _, name := range kindNames {
+			names[kind][name] = true
+		}
+	}
+	return names
+}
+
+// importedProtobufSchemas compiles protobuf schemas of an import once. Imports of .proto files are
+// resolved among protobuf schemas of the import first, then among schemas of the project.
+type importedProtobufSchemas struct {
+	projectID uuid.UUID
+	sources   map[string]string
+	files     map[string]*protobuf.File
+	errors    map[string]error
+}
+
+func newImportedProtobufSchemas(projectID uuid.UUID, schemas []SchemaImport) *importedProtobufSchemas {
+	importedSchemas := &importedProtobufSchemas{
+		projectID: projectID,
+		sources:   make(map[string]string),
+		files:     make(map[string]*protobuf.File),
+		errors:    make(map[string]error),
+	}
+	for _, schema := range schemas {
+		if schema.Type == SchemaTypeProtobuf && schema.Name != "" {
+			importedSchemas.sources[schema.Name] = schema.Schema
+		}
+	}
+	return importedSchemas
+}
+
+func (importedSchemas *importedProtobufSchemas) compile(schemaName string) (*protobuf.File, error) {
+	if file, compiled := importedSchemas.files[schemaName]; compiled {
+		return file, nil
+	}
+	if err, failed := importedSchemas.errors[schemaName]; failed {
+		return nil, err
+	}
+	file, err := compileProtobufSchema(importedSchemas.projectID, schemaName,
+		importedSchemas.sources[schemaName], importedSchemas.sources)
+	if err != nil {
+		importedSchemas.errors[schemaName] = err
+		return nil, err
+	}
+	importedSchemas.files[schemaName] = file
+	return file, nil
+}
+
+// resolveMessageType returns the full name of the message type used by a message,
+// empty for schemas of other types
+func (importedSchemas *importedProtobufSchemas) resolveMessageType(reference SchemaReference) (string, error) {
+	if _, isProtobuf := importedSchemas.sources[reference.Name]; !isProtobuf {
+		return "", nil
+	}
+	file, err := importedSchemas.compile(reference.Name)
+	if err != nil {
+		return "", err
+	}
+	message, err := findProtobufMessage(file, reference.MessageType)
+	if err != nil {
+		return "", err
+	}
+	return message.Name, nil
+}
+
+// importedJSONSchemaVersions returns contents of versions which JSON schemas of the import are going to get:
+// new schemas get their first version, changed schemas get the next one
+func importedJSONSchemaVersions(schemas []SchemaImport, existingSchemas map[string]db.SchemasDBModel) map[schemaVersionKey]string {
+	versions := make(map[schemaVersionKey]string)
+	for _, schema := range schemas {
+		if schema.Type != SchemaTypeJSONSchema || schema.Name == "" {
+			continue
+		}
+		version := 1
+		if existingSchema, exists := existingSchemas[schema.Name]; exists {
+			version = existingSchema.Version
+			if existingSchema.Schema != schema.Schema {
+				version++
 			}
 		}
+		versions[schemaVersionKey{name: schema.Name, version: version}] = schema.Schema
 	}
+	return versions
+}
 
-	return nil
-}
\ No newline at end of file
+func isSchemaContentError(err error) bool {
+	_, isContentError := err.(*SchemaContentError)
+	return isContentError
+}
+
+// schemaContentErrorMessage returns the message of a compilation error without the generic prefix
+func schemaContentErrorMessage(err error) string {
+	if contentError, isContentError := err.(*SchemaContentError); isContentError {
+		return contentError.Message
+	}
+	return err.Error()
+}
@@ -1,6 +1,7 @@
 package logic
 
 import (
+	"fmt"
 	"github.com/fusioncatltd/fusioncat/common"
 	"github.com/fusioncatltd/fusioncat/db"
 	"github.com/google/uuid"
@@ -24,6 +25,8 @@ type ProjectDBSerializerStruct struct {
 	CreatedByType string `json:"created_by_type"`
 	CreatedByID   string `json:"created_by_id"`
 	CreatedByName string `json:"created_by_name"`
+	// Compatibility mode of schemas created without an explicit one
+	SchemaCompatibility string `json:"schema_compatibility"`
 }
 
 func (project *ProjectObject) Serialize() *ProjectDBSerializerStruct {
@@ -42,6 +45,8 @@ func (project *ProjectObject) Serialize() *ProjectDBSerializerStruct {
 		CreatedByType: project.dbModel.CreatedByType,
 		CreatedByID:   project.dbModel.CreatedByID.String(),
 		CreatedByName: createdByName,
+
+		SchemaCompatibility: project.GetSchemaCompatibility(),
 	}
 }
 
@@ -49,6 +54,27 @@ func (project *ProjectObject) GetID() uuid.UUID {
 	return project.dbModel.ID
 }
 
+// GetSchemaCompatibility returns the compatibility mode of schemas created without an explicit one
+func (project *ProjectObject) GetSchemaCompatibility() string {
+	if project.dbModel.SchemaCompatibility == "" {
+		return CompatibilityNone
+	}
+	return project.dbModel.SchemaCompatibility
+}
+
+// UpdateSchemaCompatibility changes the compatibility mode of schemas created afterwards without
+// an explicit one, existing schemas keep their modes
+func (project *ProjectObject) UpdateSchemaCompatibility(mode string) error {
+	if !IsValidCompatibilityMode(mode) {
+		return fmt.Errorf("unknown compatibility mode: %s", mode)
+	}
+	if err := db.GetDB().Model(&project.dbModel).Update("schema_compatibility", mode).Error; err != nil {
+		return err
+	}
+	project.dbModel.SchemaCompatibility = mode
+	return nil
+}
+
 // ProjectsObjectsManager manages project objects in the system. It accumulates functions
 // which perform operations over multiple project objects, such as creating new projects,
 // retrieving projects by ID or email, etc.
@@ -0,0 +1,210 @@
+package logic
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fusioncatltd/fusioncat/db"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm/clause"
+)
+
+// Code generation is bound to the context of the request and limited by CODE_GENERATION_TIMEOUT_SECONDS.
+// Generated code is cached in the generated_code table by the hash of the self-contained schema
+// (JSON schemas with references bundled, protobuf schemas with their imports), the language and
+// the version of the generator, so downloads of unchanged schemas don't run generators again.
+
+var ErrCodeGenerationTimedOut = errors.New("code generation timed out")
+
+const (
+	defaultCodeGenerationTimeout     = 30 * time.Second
+	defaultCodeGenerationConcurrency = 4
+	// Version of the generators built into fusioncat, it must change whenever they change
+	// the generated code, otherwise code generated by previous versions is served from the cache
+	nativeCodeGeneratorVersion = "native/1"
+)
+
+// codeGenerationTimeout returns the time a single schema may take to generate, CODE_GENERATION_TIMEOUT_SECONDS
+func codeGenerationTimeout() time.Duration {
+	if seconds, err := strconv.Atoi(os.Getenv("CODE_GENERATION_TIMEOUT_SECONDS")); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return defaultCodeGenerationTimeout
+}
+
+// codeGenerationConcurrency returns how many schemas are generated in parallel, CODE_GENERATION_CONCURRENCY
+func codeGenerationConcurrency() int {
+	if concurrency, err := strconv.Atoi(os.Getenv("CODE_GENERATION_CONCURRENCY")); err == nil && concurrency > 0 {
+		return concurrency
+	}
+	return defaultCodeGenerationConcurrency
+}
+
+// codeGenerationError returns the error of generation stopped by the done context
+func codeGenerationError(ctx context.Context) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return ErrCodeGenerationTimedOut
+	}
+	return ctx.Err()
+}
+
+// runCodeGenerator runs the generator until it finishes or the context is done. External generators
+// are killed with the context, native generators can't be interrupted: they finish in the background
+// and their result is dropped.
+func runCodeGenerator(ctx context.Context, generate func(ctx context.Context) (string, error)) (string, error) {
+	if ctx.Err() != nil {
+		return "", codeGenerationError(ctx)
+	}
+	type generated struct {
+		code string
+		err  error
+	}
+	done := make(chan generated, 1)
+	go func() {
+		code, err := generate(ctx)
+		done <- generated{code: code, err: err}
+	}()
+	select {
+	case result := <-done:
+		if result.err != nil && ctx.Err() != nil {
+			return "", codeGenerationError(ctx)
+		}
+		return result.code, result.err
+	case <-ctx.Done():
+		return "", codeGenerationError(ctx)
+	}
+}
+
+// generatedCodeKey is everything the generated code depends on
+type generatedCodeKey struct {
+	schemaType string
+	// Self-contained content of the schema
+	content          string
+	structName       string
+	messageType      string
+	language         string
+	generatorVersion string
+}
+
+// schemaHash is the hash of the schema part of the key, the language and the generator version
+// are stored in their own columns
+func (key generatedCodeKey) schemaHash() string {
+	hash := sha256.New()
+	for _, part := range []string{key.schemaType, key.content, key.structName, key.messageType} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// generateCachedCode returns cached code of the key or runs the generator and caches its code.
+// Failures to read or write the cache are logged, generation doesn't depend on the cache.
+func generateCachedCode(ctx context.Context, key generatedCodeKey,
+	generate func(ctx context.Context) (string, error)) (string, error) {
+	schemaHash := key.schemaHash()
+
+	var cached []db.GeneratedCodeDBModel
+	err := db.GetDB().WithContext(ctx).
+		Where("schema_hash = ? AND language = ? AND generator_version = ?", schemaHash, key.language, key.generatorVersion).
+		Limit(1).
+		Find(&cached).Error
+	if err != nil && ctx.Err() == nil {
+		log.Warnf("Failed to read generated code from the cache: %v", err)
+	}
+	if len(cached) > 0 {
+		return cached[0].Code, nil
+	}
+
+	code, err := runCodeGenerator(ctx, generate)
+	if err != nil {
+		return "", err
+	}
+
+	err = db.GetDB().Clauses(clause.OnConflict{DoNothing: true}).Create(&db.GeneratedCodeDBModel{
+		SchemaHash:       schemaHash,
+		Language:         key.language,
+		GeneratorVersion: key.generatorVersion,
+		Code:             code,
+	}).Error
+	if err != nil {
+		log.Warnf("Failed to cache generated code: %v", err)
+	}
+	return code, nil
+}
+
+var quicktypeVersion struct {
+	sync.Once
+	version string
+}
+
+// quicktypeGeneratorVersion returns the version of quicktype, it is read once per process
+func quicktypeGeneratorVersion(quicktypeCmd string) string {
+	quicktypeVersion.Do(func() {
+		quicktypeVersion.version = "quicktype/unknown"
+		ctx, cancel := context.WithTimeout(context.Background(), codeGenerationTimeout())
+		defer cancel()
+		cmd := exec.CommandContext(ctx, quicktypeCmd, "--version")
+		cmd.Env = append(os.Environ(), "NODE_NO_WARNINGS=1")
+		var output bytes.Buffer
+		cmd.Stdout = &output
+		if err := cmd.Run(); err == nil {
+			quicktypeVersion.version = "quicktype/" + strings.TrimSpace(strings.TrimPrefix(output.String(), "quicktype version"))
+		}
+	})
+	return quicktypeVersion.version
+}
+
+// SchemaCodeRequest selects a schema version to generate code for with GenerateCodeOfSchemaVersions
+type SchemaCodeRequest struct {
+	SchemaVersion *SchemaVersionObject
+	SchemaType    string
+	SchemaName    string
+	MessageType   string
+}
+
+// SchemaCodeResult is the code generated for a SchemaCodeRequest
+type SchemaCodeResult struct {
+	Code       string
+	StructName string
+	Err        error
+}
+
+// GenerateCodeOfSchemaVersions generates code of schema versions in parallel, at most CODE_GENERATION_CONCURRENCY
+// at once. Results are in the order of the requests, every version is generated as by GenerateCode.
+func GenerateCodeOfSchemaVersions(ctx context.Context, language string, requests []SchemaCodeRequest) []SchemaCodeResult {
+	results := make([]SchemaCodeResult, len(requests))
+	workers := codeGenerationConcurrency()
+	if workers > len(requests) {
+		workers = len(requests)
+	}
+
+	pending := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < workers; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range pending {
+				request := requests[index]
+				code, structName, err := request.SchemaVersion.GenerateCode(ctx, language,
+					request.SchemaType, request.SchemaName, request.MessageType)
+				results[index] = SchemaCodeResult{Code: code, StructName: structName, Err: err}
+			}
+		}()
+	}
+	for index := range requests {
+		pending <- index
+	}
+	close(pending)
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,212 @@
+package logic
+
+import (
+	"fmt"
+
+	"github.com/fusioncatltd/fusioncat/avro"
+	"github.com/fusioncatltd/fusioncat/db"
+	"github.com/fusioncatltd/fusioncat/jsonschemadiff"
+)
+
+// Compatibility modes of schemas. Backward compatible versions can read data of the previous version,
+// forward compatible versions produce data the previous version can read, full compatibility means both.
+// Transitive modes check the new version against all previous versions instead of the latest one.
+const (
+	CompatibilityNone               = "none"
+	CompatibilityBackward           = "backward"
+	CompatibilityBackwardTransitive = "backward_transitive"
+	CompatibilityForward            = "forward"
+	CompatibilityForwardTransitive  = "forward_transitive"
+	CompatibilityFull               = "full"
+	CompatibilityFullTransitive     = "full_transitive"
+)
+
+// CompatibilityModes lists compatibility modes in the order they are shown to users
+var CompatibilityModes = []string{
+	CompatibilityNone,
+	CompatibilityBackward, CompatibilityBackwardTransitive,
+	CompatibilityForward, CompatibilityForwardTransitive,
+	CompatibilityFull, CompatibilityFullTransitive,
+}
+
+// Directions in which compatibility of versions is checked
+const (
+	CompatibilityDirectionBackward = "backward"
+	CompatibilityDirectionForward  = "forward"
+)
+
+// IsValidCompatibilityMode checks if the mode is one of CompatibilityModes
+func IsValidCompatibilityMode(mode string) bool {
+	for _, validMode := range CompatibilityModes {
+		if mode == validMode {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckCompatibilityModeOfType checks that compatibility of schemas of the type can be enforced in the mode
+func CheckCompatibilityModeOfType(schemaType string, mode string) error {
+	if !IsValidCompatibilityMode(mode) {
+		return fmt.Errorf("unknown compatibility mode: %s", mode)
+	}
+	if mode != CompatibilityNone && schemaType == SchemaTypeProtobuf {
+		return fmt.Errorf("compatibility of protobuf schemas can't be checked yet, only %q mode is supported", CompatibilityNone)
+	}
+	return nil
+}
+
+// CompatibilityViolation is a change of the new version which breaks compatibility with a previous version
+type CompatibilityViolation struct {
+	// Version is the previous version the new content is checked against
+	Version   int    `json:"version"`
+	Direction string `json:"direction"`
+	// Path is a JSON pointer to the schema location (JSON schemas) or to the data (Avro schemas)
+	Path    string `json:"path"`
+	Kind    string `json:"kind,omitempty"`
+	Message string `json:"message"`
+}
+
+// SchemaCompatibilityError is returned when a new version of a schema breaks its compatibility mode
+type SchemaCompatibilityError struct {
+	Mode       string
+	Violations []CompatibilityViolation
+}
+
+func (err *SchemaCompatibilityError) Error() string {
+	return fmt.Sprintf("the new version is not %s compatible: %d violations found", err.Mode, len(err.Violations))
+}
+
+// checkCompatibility checks the new content against previous versions (ordered by version)
+// according to the mode
+func checkCompatibility(schemaType string, mode string, previousVersions []db.SchemaVersionsDBModel,
+	newContent string) ([]CompatibilityViolation, error) {
+	if mode == CompatibilityNone || mode == "" || len(previousVersions) == 0 {
+		return nil, nil
+	}
+	if err := CheckCompatibilityModeOfType(schemaType, mode); err != nil {
+		return nil, err
+	}
+
+	checkedVersions := previousVersions[len(previousVersions)-1:]
+	if mode == CompatibilityBackwardTransitive || mode == CompatibilityForwardTransitive || mode == CompatibilityFullTransitive {
+		checkedVersions = previousVersions
+	}
+	backward := mode != CompatibilityForward && mode != CompatibilityForwardTransitive
+	forward := mode != CompatibilityBackward && mode != CompatibilityBackwardTransitive
+
+	var violations []CompatibilityViolation
+	for _, previous := range checkedVersions {
+		switch schemaType {
+		case SchemaTypeJSONSchema:
+			changes, err := jsonschemadiff.CompareJSON([]byte(previous.Schema), []byte(newContent))
+			if err != nil {
+				return nil, err
+			}
+			for _, change := range changes {
+				if backward && change.BreaksBackward {
+					violations = append(violations, CompatibilityViolation{Version: previous.Version,
+						Direction: CompatibilityDirectionBackward, Path: change.Path, Kind: change.Kind, Message: change.Message})
+				}
+				if forward && change.BreaksForward {
+					violations = append(violations, CompatibilityViolation{Version: previous.Version,
+						Direction: CompatibilityDirectionForward, Path: change.Path, Kind: change.Kind, Message: change.Message})
+				}
+			}
+		case SchemaTypeAvro:
+			previousSchema, err := avro.Parse(previous.Schema)
+			if err != nil {
+				return nil, &SchemaContentError{SchemaType: schemaType, Message: err.Error()}
+			}
+			newSchema, err := avro.Parse(newContent)
+			if err != nil {
+				return nil, &SchemaContentError{SchemaType: schemaType, Message: err.Error()}
+			}
+			// Backward: the new version reads data written with the previous one
+			if backward {
+				for _, incompatibility := range avro.CheckCompatibility(newSchema, previousSchema) {
+					violations = append(violations, CompatibilityViolation{Version: previous.Version,
+						Direction: CompatibilityDirectionBackward, Path: incompatibility.Path, Message: incompatibility.Message})
+				}
+			}
+			if forward {
+				for _, incompatibility := range avro.CheckCompatibility(previousSchema, newSchema) {
+					violations = append(violations, CompatibilityViolation{Version: previous.Version,
+						Direction: CompatibilityDirectionForward, Path: incompatibility.Path, Message: incompatibility.Message})
+				}
+			}
+		}
+	}
+	return violations, nil
+}
+
+// GetCompatibility returns the compatibility mode of the schema
+func (schema *SchemaObject) GetCompatibility() string {
+	if schema.dbModel.Compatibility == "" {
+		return CompatibilityNone
+	}
+	return schema.dbModel.Compatibility
+}
+
+// CheckCompatibilityOfNewVersion checks the content of a new version against the compatibility mode
+// of the schema and returns the violations, which are empty when the version can be created
+func (schema *SchemaObject) CheckCompatibilityOfNewVersion(newSchemaContent string) ([]CompatibilityViolation, error) {
+	var previousVersions []db.SchemaVersionsDBModel
+	err := db.GetDB().Where("schema_id = ?", schema.dbModel.ID).Order("version asc").Find(&previousVersions).Error
+	if err != nil {
+		return nil, err
+	}
+	return schema.checkCompatibilityWithVersions(schema.GetCompatibility(), previousVersions, newSchemaContent)
+}
+
+// CheckCompatibilityWithVersion checks the content against one version of the schema in the directions
+// of the compatibility mode of the schema. Only the version is checked even in transitive modes.
+func (schema *SchemaObject) CheckCompatibilityWithVersion(content string, version int) ([]CompatibilityViolation, error) {
+	var previousVersion db.SchemaVersionsDBModel
+	err := db.GetDB().Where("schema_id = ? AND version = ?", schema.dbModel.ID, version).First(&previousVersion).Error
+	if err != nil {
+		return nil, err
+	}
+	return schema.checkCompatibilityWithVersions(schema.GetCompatibility(), []db.SchemaVersionsDBModel{previousVersion}, content)
+}
+
+// checkCompatibilityWithVersions compares JSON schemas with references to other schemas bundled,
+// so that changes of the referenced versions are taken into account
+func (schema *SchemaObject) checkCompatibilityWithVersions(mode string, previousVersions []db.SchemaVersionsDBModel,
+	content string) ([]CompatibilityViolation, error) {
+	if schema.dbModel.Type == SchemaTypeJSONSchema && mode != CompatibilityNone {
+		for index := range previousVersions {
+			previousVersions[index].Schema = schema.bundledForComparison(previousVersions[index].Schema)
+		}
+		content = schema.bundledForComparison(content)
+	}
+	return checkCompatibility(schema.dbModel.Type, mode, previousVersions, content)
+}
+
+// UpdateCompatibility changes the compatibility mode of the schema. Existing versions are not rechecked,
+// the mode applies to versions created afterwards.
+func (schema *SchemaObject) UpdateCompatibility(mode string) error {
+	if err := CheckCompatibilityModeOfType(schema.dbModel.Type, mode); err != nil {
+		return err
+	}
+	if err := db.GetDB().Model(&schema.dbModel).Update("compatibility", mode).Error; err != nil {
+		return err
+	}
+	schema.dbModel.Compatibility = mode
+	return nil
+}
+
+// SchemaCompatibilityErrorSerializerStruct is the response to versions rejected by the compatibility mode
+type SchemaCompatibilityErrorSerializerStruct struct {
+	Error         string                   `json:"error"`
+	Compatibility string                   `json:"compatibility"`
+	Violations    []CompatibilityViolation `json:"violations"`
+}
+
+func (err *SchemaCompatibilityError) Serialize() *SchemaCompatibilityErrorSerializerStruct {
+	return &SchemaCompatibilityErrorSerializerStruct{
+		Error:         err.Error(),
+		Compatibility: err.Mode,
+		Violations:    err.Violations,
+	}
+}
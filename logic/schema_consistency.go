@@ -0,0 +1,347 @@
+package logic
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/fusioncatltd/fusioncat/db"
+	"github.com/google/uuid"
+)
+
+// The consistency report groups fields of the same name (case-insensitively) across the schema versions
+// in use: current versions of active schemas and versions messages are pinned to. Fields of a group are
+// compared by their types, formats, enums and units, mapped to common terms so that schemas of different
+// types can be compared: an Avro long and a protobuf int64 are both integers, an Avro timestamp-millis and
+// a protobuf google.protobuf.Timestamp are both date-times. Units are read from descriptions
+// ("Amount in cents") and from Avro logical types.
+
+// Kinds of conflicts between fields of the same name
+const (
+	FieldConflictType   = "type"
+	FieldConflictFormat = "format"
+	FieldConflictEnum   = "enum"
+	FieldConflictUnit   = "unit"
+)
+
+// fieldValueNone stands for fields without a format or an enum among fields which have them
+const fieldValueNone = "none"
+
+// FieldConflictSerializerStruct lists the distinct values fields of the same name take
+type FieldConflictSerializerStruct struct {
+	Kind   string   `json:"kind"`
+	Values []string `json:"values"`
+}
+
+// FieldConsistencyOccurrenceSerializerStruct is a field of an inconsistent group with its values in common terms
+type FieldConsistencyOccurrenceSerializerStruct struct {
+	SchemaFieldOccurrenceSerializerStruct
+	NormalizedType   string `json:"normalized_type"`
+	NormalizedFormat string `json:"normalized_format,omitempty"`
+	Unit             string `json:"unit,omitempty"`
+}
+
+type InconsistentFieldSerializerStruct struct {
+	Name      string                                       `json:"name"`
+	Conflicts []FieldConflictSerializerStruct              `json:"conflicts"`
+	Fields    []FieldConsistencyOccurrenceSerializerStruct `json:"fields"`
+}
+
+// ProjectConsistencySerializerStruct is the consistency report of a project. CheckedNames counts
+// the names of fields found in more than one place.
+type ProjectConsistencySerializerStruct struct {
+	CheckedNames    int                                 `json:"checked_names"`
+	Inconsistencies []InconsistentFieldSerializerStruct `json:"inconsistencies"`
+}
+
+// Types of JSON values which schemas of all types map to
+var avroNormalizedTypes = map[string]string{
+	"null": "", "boolean": "boolean", "int": "integer", "long": "integer", "float": "number", "double": "number",
+	"bytes": "string", "string": "string", "array": "array", "map": "object",
+}
+
+var protobufNormalizedTypes = map[string]string{
+	"double": "number", "float": "number",
+	"int32": "integer", "int64": "integer", "uint32": "integer", "uint64": "integer", "sint32": "integer",
+	"sint64": "integer", "fixed32": "integer", "fixed64": "integer", "sfixed32": "integer", "sfixed64": "integer",
+	"bool": "boolean", "string": "string", "bytes": "string",
+	// Well-known types have JSON representations of their own
+	"google.protobuf.Timestamp": "string", "google.protobuf.Duration": "string",
+	"google.protobuf.StringValue": "string", "google.protobuf.BytesValue": "string",
+	"google.protobuf.BoolValue": "boolean", "google.protobuf.DoubleValue": "number",
+	"google.protobuf.FloatValue": "number", "google.protobuf.Int32Value": "integer",
+	"google.protobuf.Int64Value": "integer", "google.protobuf.UInt32Value": "integer",
+	"google.protobuf.UInt64Value": "integer", "google.protobuf.Struct": "object", "google.protobuf.Value": "",
+	"google.protobuf.ListValue": "array",
+}
+
+var avroNormalizedFormats = map[string]string{
+	"timestamp-millis": "date-time", "timestamp-micros": "date-time", "timestamp-nanos": "date-time",
+	"local-timestamp-millis": "date-time", "local-timestamp-micros": "date-time",
+	"date": "date", "time-millis": "time", "time-micros": "time", "uuid": "uuid", "decimal": "decimal",
+	"duration": "duration",
+}
+
+var protobufNormalizedFormats = map[string]string{
+	"google.protobuf.Timestamp": "date-time", "google.protobuf.Duration": "duration",
+}
+
+var avroUnits = map[string]string{
+	"timestamp-millis": "milliseconds", "timestamp-micros": "microseconds", "timestamp-nanos": "nanoseconds",
+	"local-timestamp-millis": "milliseconds", "local-timestamp-micros": "microseconds",
+	"time-millis": "milliseconds", "time-micros": "microseconds",
+}
+
+// Words of descriptions naming units, by the unit they name
+var unitWords = map[string][]string{
+	"nanoseconds":  {"nanoseconds", "nanosecond", "ns"},
+	"microseconds": {"microseconds", "microsecond", "µs"},
+	"milliseconds": {"milliseconds", "millisecond", "millis", "ms"},
+	"seconds":      {"seconds", "secs"},
+	"minutes":      {"minutes", "minute", "mins"},
+	"hours":        {"hours", "hour"},
+	"days":         {"days"},
+	"cents":        {"cents", "minor units", "pence"},
+	"major units":  {"dollars", "dollar", "euros", "euro", "major units"},
+	"bytes":        {"bytes", "byte"},
+	"kilobytes":    {"kilobytes", "kilobyte", "kb", "kib"},
+	"megabytes":    {"megabytes", "megabyte", "mb", "mib"},
+	"gigabytes":    {"gigabytes", "gigabyte", "gb", "gib"},
+	"meters":       {"meters", "meter", "metres", "metre"},
+	"kilometers":   {"kilometers", "kilometer", "kilometres", "kilometre", "km"},
+	"miles":        {"miles", "mile"},
+	"grams":        {"grams", "gram"},
+	"kilograms":    {"kilograms", "kilogram", "kg"},
+	"pounds":       {"lbs"},
+	"percent":      {"percent", "percentage"},
+	"basis points": {"basis points", "bps"},
+}
+
+var unitPatterns = func() map[string]*regexp.Regexp {
+	patterns := make(map[string]*regexp.Regexp, len(unitWords))
+	for unit, words := range unitWords {
+		quoted := make([]string, 0, len(words))
+		for _, word := range words {
+			quoted = append(quoted, regexp.QuoteMeta(word))
+		}
+		// Units are whole words
+		patterns[unit] = regexp.MustCompile(`(?i)(^|[^\pL\pN])(` + strings.Join(quoted, "|") + `)($|[^\pL\pN])`)
+	}
+	return patterns
+}()
+
+// normalizedFieldType maps the type of a field to types of JSON values, fields of any type are ""
+func normalizedFieldType(schemaType string, field db.SchemaFieldsDBModel) string {
+	var types []string
+	switch schemaType {
+	case SchemaTypeJSONSchema:
+		for _, name := range strings.Split(field.Type, ",") {
+			if name != "null" {
+				types = append(types, name)
+			}
+		}
+		if field.Type == "" && field.Enum != "" {
+			types = append(types, "string")
+		}
+	case SchemaTypeAvro:
+		for _, name := range strings.Split(field.Type, ",") {
+			normalized, primitive := avroNormalizedTypes[name]
+			switch {
+			case primitive:
+				types = append(types, normalized)
+			case field.Enum != "":
+				// Avro enums are the only named types with symbols
+				types = append(types, "string")
+			default:
+				types = append(types, "object")
+			}
+		}
+	case SchemaTypeProtobuf:
+		switch {
+		case strings.HasPrefix(field.Type, "repeated "):
+			types = append(types, "array")
+		case strings.HasPrefix(field.Type, "map<"):
+			types = append(types, "object")
+		case field.Enum != "":
+			types = append(types, "string")
+		default:
+			normalized, known := protobufNormalizedTypes[field.Type]
+			if !known {
+				normalized = "object"
+			}
+			types = append(types, normalized)
+		}
+	}
+	return joinFieldTypes(types)
+}
+
+func normalizedFieldFormat(schemaType string, field db.SchemaFieldsDBModel) string {
+	switch schemaType {
+	case SchemaTypeAvro:
+		return avroNormalizedFormats[field.Format]
+	case SchemaTypeProtobuf:
+		return protobufNormalizedFormats[field.Type]
+	}
+	return field.Format
+}
+
+// normalizedFieldEnum returns sorted values of the enum, JSON encoded
+func normalizedFieldEnum(field db.SchemaFieldsDBModel) string {
+	if field.Enum == "" {
+		return ""
+	}
+	var values []interface{}
+	if err := json.Unmarshal([]byte(field.Enum), &values); err != nil {
+		return field.Enum
+	}
+	names := make([]string, 0, len(values))
+	for _, value := range values {
+		if value != nil {
+			names = append(names, fmt.Sprint(value))
+		}
+	}
+	sort.Strings(names)
+	encoded, _ := json.Marshal(names)
+	return string(encoded)
+}
+
+// fieldUnit returns the unit of the field named by its Avro logical type or its description.
+// Descriptions naming several units have the unit named first.
+func fieldUnit(schemaType string, field db.SchemaFieldsDBModel) string {
+	if schemaType == SchemaTypeAvro {
+		if unit, exists := avroUnits[field.Format]; exists {
+			return unit
+		}
+	}
+	unit := ""
+	position := -1
+	for candidate, pattern := range unitPatterns {
+		if match := pattern.FindStringSubmatchIndex(field.Description); match != nil {
+			// Position of the unit word, after the separator
+			if position == -1 || match[4] < position || (match[4] == position && candidate < unit) {
+				unit = candidate
+				position = match[4]
+			}
+		}
+	}
+	return unit
+}
+
+// fieldConflict returns the conflict of values of a group. Values missing from some fields count
+// as fieldValueNone when others have them, unless missing values are ignored.
+func fieldConflict(kind string, values []string, ignoreMissing bool) *FieldConflictSerializerStruct {
+	distinct := make(map[string]bool)
+	present := false
+	for _, value := range values {
+		if value != "" {
+			present = true
+		}
+	}
+	if !present {
+		return nil
+	}
+	for _, value := range values {
+		switch {
+		case value != "":
+			distinct[value] = true
+		case !ignoreMissing:
+			distinct[fieldValueNone] = true
+		}
+	}
+	if len(distinct) < 2 {
+		return nil
+	}
+	conflict := &FieldConflictSerializerStruct{Kind: kind, Values: make([]string, 0, len(distinct))}
+	for value := range distinct {
+		conflict.Values = append(conflict.Values, value)
+	}
+	sort.Strings(conflict.Values)
+	return conflict
+}
+
+// GetProjectConsistencyReport finds fields of the same name defined inconsistently across schema versions in use
+func GetProjectConsistencyReport(projectID uuid.UUID) (*ProjectConsistencySerializerStruct, error) {
+	if err := catalogProjectSchemaFields(projectID); err != nil {
+		return nil, err
+	}
+
+	var rows []schemaFieldSearchRow
+	err := db.GetDB().Table("schema_fields AS f").
+		Select("f.*, s.name AS schema_name, s.type AS schema_type").
+		Joins("JOIN schemas s ON s.id = f.schema_id AND s.status = 'active' AND s.deleted_at IS NULL").
+		Joins("JOIN schema_versions v ON v.schema_id = f.schema_id AND v.version = f.schema_version AND v.deleted_at IS NULL").
+		Where("f.project_id = ?", projectID).
+		Where("f.schema_version = s.version OR EXISTS (SELECT 1 FROM messages m WHERE m.schema_id = f.schema_id " +
+			"AND m.schema_version = f.schema_version AND m.status = 'active' AND m.deleted_at IS NULL)").
+		Order("lower(f.name), s.name, f.schema_version, f.message_type, f.path").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// Rows are ordered by name, groups are consecutive
+	var groups [][]schemaFieldSearchRow
+	for index, row := range rows {
+		if index == 0 || !strings.EqualFold(rows[index-1].Name, row.Name) {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], row)
+	}
+
+	report := &ProjectConsistencySerializerStruct{Inconsistencies: make([]InconsistentFieldSerializerStruct, 0)}
+	for _, group := range groups {
+		if len(group) < 2 {
+			continue
+		}
+		report.CheckedNames++
+
+		types := make([]string, 0, len(group))
+		formats := make([]string, 0, len(group))
+		enums := make([]string, 0, len(group))
+		units := make([]string, 0, len(group))
+		for _, row := range group {
+			types = append(types, normalizedFieldType(row.SchemaType, row.SchemaFieldsDBModel))
+			formats = append(formats, normalizedFieldFormat(row.SchemaType, row.SchemaFieldsDBModel))
+			enums = append(enums, normalizedFieldEnum(row.SchemaFieldsDBModel))
+			units = append(units, fieldUnit(row.SchemaType, row.SchemaFieldsDBModel))
+		}
+
+		var conflicts []FieldConflictSerializerStruct
+		for _, candidate := range []*FieldConflictSerializerStruct{
+			// Fields of any type don't conflict with typed ones, fields without a unit don't name one
+			fieldConflict(FieldConflictType, types, true),
+			fieldConflict(FieldConflictFormat, formats, false),
+			fieldConflict(FieldConflictEnum, enums, false),
+			fieldConflict(FieldConflictUnit, units, true),
+		} {
+			if candidate != nil {
+				conflicts = append(conflicts, *candidate)
+			}
+		}
+		if len(conflicts) == 0 {
+			continue
+		}
+
+		occurrences, err := serializeSchemaFieldOccurrences(group)
+		if err != nil {
+			return nil, err
+		}
+		inconsistency := InconsistentFieldSerializerStruct{
+			Name:      group[0].Name,
+			Conflicts: conflicts,
+			Fields:    make([]FieldConsistencyOccurrenceSerializerStruct, 0, len(group)),
+		}
+		for index, occurrence := range occurrences {
+			inconsistency.Fields = append(inconsistency.Fields, FieldConsistencyOccurrenceSerializerStruct{
+				SchemaFieldOccurrenceSerializerStruct: occurrence,
+				NormalizedType:                        types[index],
+				NormalizedFormat:                      formats[index],
+				Unit:                                  units[index],
+			})
+		}
+		report.Inconsistencies = append(report.Inconsistencies, inconsistency)
+	}
+	return report, nil
+}
@@ -0,0 +1,155 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/fusioncatltd/fusioncat/avro"
+	"github.com/fusioncatltd/fusioncat/db"
+	"github.com/fusioncatltd/fusioncat/schemaconvert"
+	"github.com/google/uuid"
+)
+
+var ErrConversionToSameType = errors.New("the schema is already of the target type")
+
+// SchemaConversionSerializerStruct is a schema converted to another schema type. Losses list constructs of
+// the source which the target type can't express. SavedSchema is set when the result is saved as a new schema.
+type SchemaConversionSerializerStruct struct {
+	SourceType  string                    `json:"source_type"`
+	TargetType  string                    `json:"target_type"`
+	Schema      string                    `json:"schema"`
+	Losses      []schemaconvert.Loss      `json:"losses"`
+	SavedSchema *SchemaDBSerializerStruct `json:"saved_schema,omitempty"`
+}
+
+// SchemaConversionSourceSerializerStruct points at the schema version a schema was converted from
+type SchemaConversionSourceSerializerStruct struct {
+	SchemaID      string `json:"schema_id"`
+	SchemaVersion int    `json:"schema_version"`
+}
+
+func (schema *SchemaObject) getConversionSource() *SchemaConversionSourceSerializerStruct {
+	if schema.dbModel.ConvertedFromSchemaID == nil || schema.dbModel.ConvertedFromVersion == nil {
+		return nil
+	}
+	return &SchemaConversionSourceSerializerStruct{
+		SchemaID:      schema.dbModel.ConvertedFromSchemaID.String(),
+		SchemaVersion: *schema.dbModel.ConvertedFromVersion,
+	}
+}
+
+// ConvertVersion converts a version of the schema to a schema of the target type, see schemaconvert.
+// Avro and protobuf schemas are converted to each other through JSON Schema, the message type selects
+// the converted message of protobuf schemas. Records and messages are named after the name, the schema
+// name by default, which also is the package of protobuf schemas.
+func (schema *SchemaObject) ConvertVersion(schemaVersion *SchemaVersionObject, targetType string,
+	messageType string, name string) (*SchemaConversionSerializerStruct, error) {
+	sourceType := schema.dbModel.Type
+	if sourceType == targetType {
+		return nil, ErrConversionToSameType
+	}
+	if name == "" {
+		name = schema.dbModel.Name
+	}
+	projectID := schema.dbModel.ProjectID
+	content := schemaVersion.dbModel.Schema
+
+	var losses []schemaconvert.Loss
+	switch sourceType {
+	case SchemaTypeJSONSchema:
+		// References to other schemas of the project are bundled, conversions follow only local references
+		bundled, err := bundleJSONSchema(projectID, content, nil)
+		if err != nil {
+			return nil, err
+		}
+		content = bundled
+	case SchemaTypeAvro:
+		parsed, err := avro.Parse(content)
+		if err != nil {
+			return nil, &SchemaContentError{SchemaType: sourceType, Message: err.Error()}
+		}
+		if content, losses, err = schemaconvert.AvroToJSONSchema(parsed); err != nil {
+			return nil, err
+		}
+	case SchemaTypeProtobuf:
+		file, err := compileProtobufSchema(projectID, schema.dbModel.Name, content, nil)
+		if err != nil {
+			return nil, err
+		}
+		message, err := findProtobufMessage(file, messageType)
+		if err != nil {
+			return nil, err
+		}
+		if content, losses, err = schemaconvert.ProtobufToJSONSchema(message); err != nil {
+			return nil, err
+		}
+	}
+
+	if targetType != SchemaTypeJSONSchema {
+		document, err := decodeJSONSchema(content)
+		if err != nil {
+			return nil, &SchemaContentError{SchemaType: SchemaTypeJSONSchema, Message: err.Error()}
+		}
+		var targetLosses []schemaconvert.Loss
+		if targetType == SchemaTypeAvro {
+			content, targetLosses, err = schemaconvert.JSONSchemaToAvro(document, name)
+		} else {
+			content, targetLosses, err = schemaconvert.JSONSchemaToProtobuf(document, name, protobufPackageName(name))
+		}
+		if err != nil {
+			return nil, err
+		}
+		losses = append(losses, targetLosses...)
+	}
+
+	// Converters produce valid schemas, failures are bugs rather than problems of the source
+	if err := ValidateSchemaContent(projectID, name, targetType, content); err != nil {
+		return nil, fmt.Errorf("converted schema is invalid: %v", err)
+	}
+	if losses == nil {
+		losses = make([]schemaconvert.Loss, 0)
+	}
+	return &SchemaConversionSerializerStruct{
+		SourceType: sourceType,
+		TargetType: targetType,
+		Schema:     content,
+		Losses:     losses,
+	}, nil
+}
+
+// protobufPackageName is the package of protobuf schemas converted from other schemas, names of schemas
+// which can't be package names give no package
+func protobufPackageName(name string) string {
+	packageName := strings.ToLower(name)
+	if packageName == "" || packageName[0] < 'a' || packageName[0] > 'z' {
+		return ""
+	}
+	for _, char := range packageName {
+		if !(char >= 'a' && char <= 'z' || char >= '0' && char <= '9' || char == '_') {
+			return ""
+		}
+	}
+	return packageName
+}
+
+// CreateAConvertedSchema saves a converted schema as a new schema of the project linked to the version
+// it was converted from
+func (schemaManager *SchemaObjectsManager) CreateAConvertedSchema(name string, description string,
+	conversion *SchemaConversionSerializerStruct, source *SchemaVersionObject,
+	userID uuid.UUID, projectID uuid.UUID) (*SchemaObject, error) {
+	sourceID, sourceVersion := source.dbModel.SchemaID, source.dbModel.Version
+	return schemaManager.createSchema(db.SchemasDBModel{
+		Name:                  strings.TrimSpace(name),
+		Description:           description,
+		Schema:                conversion.Schema,
+		Type:                  conversion.TargetType,
+		Version:               1,
+		Status:                "active",
+		CreatedByType:         "user",
+		CreatedByID:           userID,
+		ProjectID:             projectID,
+		ConvertedFromSchemaID: &sourceID,
+		ConvertedFromVersion:  &sourceVersion,
+	}, userID)
+}
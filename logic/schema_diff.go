@@ -0,0 +1,84 @@
+package logic
+
+import (
+	"errors"
+
+	"github.com/fusioncatltd/fusioncat/common"
+	"github.com/fusioncatltd/fusioncat/db"
+	"github.com/fusioncatltd/fusioncat/jsonschemadiff"
+)
+
+var ErrSchemaDiffNotSupported = errors.New("structural diffs are supported only for JSON schemas")
+
+// SchemaDiffChangeSerializerStruct is a single change between two versions of a schema.
+// Producers are broken when data they produce according to the old version is invalid against the new one,
+// consumers are broken when data valid against the new version is invalid against the old one they read with.
+type SchemaDiffChangeSerializerStruct struct {
+	Path            string `json:"path"`
+	Kind            string `json:"kind"`
+	Message         string `json:"message"`
+	Breaking        bool   `json:"breaking"`
+	BreaksProducers bool   `json:"breaks_producers"`
+	BreaksConsumers bool   `json:"breaks_consumers"`
+}
+
+type SchemaDiffSerializerStruct struct {
+	SchemaID        string                             `json:"schema_id"`
+	From            int                                `json:"from"`
+	To              int                                `json:"to"`
+	Breaking        bool                               `json:"breaking"`
+	BreaksProducers bool                               `json:"breaks_producers"`
+	BreaksConsumers bool                               `json:"breaks_consumers"`
+	Changes         []SchemaDiffChangeSerializerStruct `json:"changes"`
+}
+
+// Diff compares two versions of the schema. The versions can be given in any order,
+// changes are reported as the way from the "from" version to the "to" version.
+func (schema *SchemaObject) Diff(fromVersion int, toVersion int) (*SchemaDiffSerializerStruct, error) {
+	if schema.dbModel.Type != SchemaTypeJSONSchema {
+		return nil, ErrSchemaDiffNotSupported
+	}
+
+	var versions []db.SchemaVersionsDBModel
+	err := db.GetDB().Where("schema_id = ? AND version IN ?", schema.dbModel.ID, []int{fromVersion, toVersion}).
+		Find(&versions).Error
+	if err != nil {
+		return nil, err
+	}
+	contents := make(map[int]string, len(versions))
+	for _, version := range versions {
+		contents[version.Version] = version.Schema
+	}
+	fromContent, fromExists := contents[fromVersion]
+	toContent, toExists := contents[toVersion]
+	if !fromExists || !toExists {
+		return nil, common.FusioncatErrRecordNotFound
+	}
+
+	changes, err := jsonschemadiff.CompareJSON([]byte(schema.bundledForComparison(fromContent)),
+		[]byte(schema.bundledForComparison(toContent)))
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &SchemaDiffSerializerStruct{
+		SchemaID: schema.dbModel.ID.String(),
+		From:     fromVersion,
+		To:       toVersion,
+		Changes:  make([]SchemaDiffChangeSerializerStruct, 0, len(changes)),
+	}
+	for _, change := range changes {
+		diff.Changes = append(diff.Changes, SchemaDiffChangeSerializerStruct{
+			Path:            change.Path,
+			Kind:            change.Kind,
+			Message:         change.Message,
+			Breaking:        change.IsBreaking(),
+			BreaksProducers: change.BreaksBackward,
+			BreaksConsumers: change.BreaksForward,
+		})
+		diff.Breaking = diff.Breaking || change.IsBreaking()
+		diff.BreaksProducers = diff.BreaksProducers || change.BreaksBackward
+		diff.BreaksConsumers = diff.BreaksConsumers || change.BreaksForward
+	}
+	return diff, nil
+}
@@ -0,0 +1,130 @@
+package logic
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/fusioncatltd/fusioncat/avro"
+	"github.com/fusioncatltd/fusioncat/fakedata"
+	"github.com/fusioncatltd/fusioncat/jsonschemaexample"
+)
+
+// maxExampleAttempts limits regeneration of examples which turned out invalid, e.g. because of "not"
+// keywords or overlapping "oneOf" branches of JSON schemas
+const maxExampleAttempts = 20
+
+var ErrExampleGenerationFailed = errors.New("couldn't generate a valid example")
+
+type SchemaExamplesSerializerStruct struct {
+	SchemaID      string        `json:"schema_id"`
+	SchemaVersion int           `json:"schema_version"`
+	MessageType   string        `json:"message_type,omitempty"`
+	Seed          int64         `json:"seed"`
+	Examples      []interface{} `json:"examples"`
+}
+
+// GenerateExamples generates payloads valid against the schema version. The same seed always produces
+// the same examples of the same version.
+func (schemaVersion *SchemaVersionObject) GenerateExamples(schemaType string, messageType string,
+	count int, seed int64) (*SchemaExamplesSerializerStruct, error) {
+	validator, err := schemaVersion.payloadValidator(schemaType, messageType)
+	if err != nil {
+		return nil, err
+	}
+
+	var generate func(data *fakedata.Generator) (interface{}, error)
+	switch schemaType {
+	case SchemaTypeJSONSchema:
+		// References to other schemas of the project are bundled, the generator follows only local references
+		schema, err := schemaVersion.getSchemaRecord()
+		if err != nil {
+			return nil, err
+		}
+		content, err := bundleJSONSchema(schema.ProjectID, schemaVersion.dbModel.Schema, nil)
+		if err != nil {
+			return nil, err
+		}
+		generate = func(data *fakedata.Generator) (interface{}, error) {
+			return jsonschemaexample.GenerateJSON([]byte(content), data)
+		}
+	case SchemaTypeAvro:
+		parsed, err := avro.Parse(schemaVersion.dbModel.Schema)
+		if err != nil {
+			return nil, &SchemaContentError{SchemaType: schemaType, Message: err.Error()}
+		}
+		generate = func(data *fakedata.Generator) (interface{}, error) {
+			return parsed.Example(data), nil
+		}
+	case SchemaTypeProtobuf:
+		schema, err := schemaVersion.getSchemaRecord()
+		if err != nil {
+			return nil, err
+		}
+		file, err := compileProtobufSchema(schema.ProjectID, schema.Name, schemaVersion.dbModel.Schema, nil)
+		if err != nil {
+			return nil, err
+		}
+		message, err := findProtobufMessage(file, messageType)
+		if err != nil {
+			return nil, err
+		}
+		generate = func(data *fakedata.Generator) (interface{}, error) {
+			return message.Example(data), nil
+		}
+	default:
+		return nil, fmt.Errorf("unsupported schema type: %s", schemaType)
+	}
+
+	data := fakedata.New(seed)
+	response := &SchemaExamplesSerializerStruct{
+		SchemaID:      schemaVersion.dbModel.SchemaID.String(),
+		SchemaVersion: schemaVersion.dbModel.Version,
+		MessageType:   messageType,
+		Seed:          seed,
+		Examples:      make([]interface{}, 0, count),
+	}
+	for len(response.Examples) < count {
+		example, err := generateValidExample(generate, validator, data)
+		if err != nil {
+			return nil, err
+		}
+		response.Examples = append(response.Examples, example)
+	}
+	return response, nil
+}
+
+// generateValidExample generates examples until one passes the validation of the schema version
+func generateValidExample(generate func(data *fakedata.Generator) (interface{}, error), validator payloadValidator,
+	data *fakedata.Generator) (interface{}, error) {
+	var lastError error
+	for attempt := 0; attempt < maxExampleAttempts; attempt++ {
+		example, err := generate(data)
+		if err != nil {
+			return nil, err
+		}
+		payload, err := json.Marshal(example)
+		if err != nil {
+			return nil, err
+		}
+		if lastError = validator(payload); lastError == nil {
+			return json.RawMessage(payload), nil
+		}
+	}
+	return nil, fmt.Errorf("%w in %d attempts, the last one failed with: %v",
+		ErrExampleGenerationFailed, maxExampleAttempts, lastError)
+}
+
+// GenerateExamples generates payloads valid against the schema version and the message type of the message
+func (message *MessageObject) GenerateExamples(count int, seed int64) (*SchemaExamplesSerializerStruct, error) {
+	schemasManager := SchemaObjectsManager{}
+	schema, err := schemasManager.GetByID(message.dbModel.SchemaID)
+	if err != nil {
+		return nil, err
+	}
+	schemaVersion, err := schemasManager.GetSpecificVersionOfSchema(message.dbModel.SchemaID, message.dbModel.SchemaVersion)
+	if err != nil {
+		return nil, err
+	}
+	return schemaVersion.GenerateExamples(schema.GetType(), message.dbModel.SchemaMessageType, count, seed)
+}
@@ -0,0 +1,575 @@
+package logic
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fusioncatltd/fusioncat/avro"
+	"github.com/fusioncatltd/fusioncat/db"
+	"github.com/fusioncatltd/fusioncat/protobuf"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Versions of schemas are flattened into a catalog of their fields, stored in schema_fields for searches
+// across projects. Paths of fields are JSON pointers to the fields in the data (in the proto3 JSON mapping
+// for protobuf schemas, by the names of the fields), items of arrays and values of maps are "*", e.g.
+// "/items/*/sku". Versions are cataloged when they are created, versions created by imports or before
+// the catalog existed are cataloged when the catalog of their project is read.
+
+// maxFieldDepth limits nesting of cataloged fields, so that recursive schemas terminate
+const maxFieldDepth = 16
+
+// SchemaFieldSerializerStruct is a field of a schema version. MessageType is set for fields of protobuf schemas.
+type SchemaFieldSerializerStruct struct {
+	MessageType string        `json:"message_type,omitempty"`
+	Path        string        `json:"path"`
+	Name        string        `json:"name"`
+	Type        string        `json:"type"`
+	Format      string        `json:"format,omitempty"`
+	Required    bool          `json:"required"`
+	Description string        `json:"description,omitempty"`
+	Enum        []interface{} `json:"enum,omitempty"`
+}
+
+// SchemaFieldOccurrenceSerializerStruct is a field found by a search, with the messages which use its version
+type SchemaFieldOccurrenceSerializerStruct struct {
+	SchemaID      string `json:"schema_id"`
+	SchemaName    string `json:"schema_name"`
+	SchemaType    string `json:"schema_type"`
+	SchemaVersion int    `json:"schema_version"`
+	// SchemaURI identifies the schema version, see SchemaReferenceURI
+	SchemaURI string `json:"schema_uri"`
+	SchemaFieldSerializerStruct
+	Messages []SchemaUsageMessageStruct `json:"messages"`
+}
+
+type SchemaFieldSearchSerializerStruct struct {
+	Name string `json:"name"`
+	// Types are the distinct types of the found fields
+	Types  []string                                `json:"types"`
+	Fields []SchemaFieldOccurrenceSerializerStruct `json:"fields"`
+}
+
+func serializeSchemaField(field db.SchemaFieldsDBModel) SchemaFieldSerializerStruct {
+	serialized := SchemaFieldSerializerStruct{
+		MessageType: field.MessageType,
+		Path:        field.Path,
+		Name:        field.Name,
+		Type:        field.Type,
+		Format:      field.Format,
+		Required:    field.Required,
+		Description: field.Description,
+	}
+	if field.Enum != "" {
+		_ = json.Unmarshal([]byte(field.Enum), &serialized.Enum)
+	}
+	return serialized
+}
+
+// schemaFieldsOfContent flattens the content of a schema version. Contents which can't be parsed have no fields.
+func schemaFieldsOfContent(projectID uuid.UUID, schemaName string, schemaType string, content string) []db.SchemaFieldsDBModel {
+	switch schemaType {
+	case SchemaTypeJSONSchema:
+		if bundled, err := bundleJSONSchema(projectID, content, nil); err == nil {
+			content = bundled
+		}
+		document, err := decodeJSONSchema(content)
+		if err != nil {
+			return nil
+		}
+		flattener := &jsonSchemaFieldsFlattener{root: document, seen: make(map[string]int)}
+		flattener.flatten(document, "", 0)
+		return flattener.fields
+	case SchemaTypeAvro:
+		schema, err := avro.Parse(content)
+		if err != nil {
+			return nil
+		}
+		var fields []db.SchemaFieldsDBModel
+		flattenAvroFields(schema, "", make(map[string]bool), &fields)
+		return fields
+	case SchemaTypeProtobuf:
+		file, err := compileProtobufSchema(projectID, schemaName, content, nil)
+		if err != nil {
+			return nil
+		}
+		var fields []db.SchemaFieldsDBModel
+		defaultMessage := file.DefaultMessage()
+		for _, messageType := range file.MessageTypes() {
+			message := file.FindMessage(messageType)
+			var messageFields []db.SchemaFieldsDBModel
+			flattenProtobufFields(message, "", make(map[string]bool), &messageFields)
+			for index := range messageFields {
+				messageFields[index].MessageType = message.Name
+				messageFields[index].InDefaultMessageType = message == defaultMessage
+			}
+			fields = append(fields, messageFields...)
+		}
+		return fields
+	}
+	return nil
+}
+
+type jsonSchemaFieldsFlattener struct {
+	root   interface{}
+	fields []db.SchemaFieldsDBModel
+	// seen indexes fields by path, fields of several branches (allOf, anyOf, oneOf) are merged
+	seen map[string]int
+	// resolving holds references being flattened, so that recursive schemas terminate
+	resolving []string
+}
+
+// resolve follows local references of the schema
+func (flattener *jsonSchemaFieldsFlattener) resolve(schema map[string]interface{}) (map[string]interface{}, string) {
+	reference, isReference := schema["$ref"].(string)
+	if !isReference || !strings.HasPrefix(reference, "#") {
+		return schema, ""
+	}
+	var target interface{} = flattener.root
+	pointer := strings.TrimPrefix(reference, "#")
+	if pointer != "" {
+		for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+			token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+			object, isObject := target.(map[string]interface{})
+			if !isObject {
+				return nil, reference
+			}
+			target = object[token]
+		}
+	}
+	resolved, _ := target.(map[string]interface{})
+	return resolved, reference
+}
+
+// types returns the types of the schema, including types of its anyOf and oneOf branches
+func (flattener *jsonSchemaFieldsFlattener) types(schema map[string]interface{}, depth int) []string {
+	schema, _ = flattener.resolve(schema)
+	if schema == nil || depth > maxFieldDepth {
+		return nil
+	}
+	var types []string
+	switch value := schema["type"].(type) {
+	case string:
+		types = append(types, value)
+	case []interface{}:
+		for _, item := range value {
+			if name, isString := item.(string); isString {
+				types = append(types, name)
+			}
+		}
+	}
+	for _, keyword := range []string{"anyOf", "oneOf", "allOf"} {
+		branches, _ := schema[keyword].([]interface{})
+		for _, branch := range branches {
+			if branchSchema, isObject := branch.(map[string]interface{}); isObject {
+				types = append(types, flattener.types(branchSchema, depth+1)...)
+			}
+		}
+	}
+	if len(types) == 0 {
+		if _, hasProperties := schema["properties"]; hasProperties {
+			types = append(types, "object")
+		} else if _, hasItems := schema["items"]; hasItems {
+			types = append(types, "array")
+		}
+	}
+	return types
+}
+
+func (flattener *jsonSchemaFieldsFlattener) add(path string, name string, schema map[string]interface{}, required bool) {
+	resolved, _ := flattener.resolve(schema)
+	if resolved == nil {
+		resolved = schema
+	}
+	field := db.SchemaFieldsDBModel{
+		InDefaultMessageType: true,
+		Path:                 path,
+		Name:                 name,
+		Required:             required,
+	}
+	types := flattener.types(schema, 0)
+	field.Type = joinFieldTypes(types)
+	field.Format, _ = resolved["format"].(string)
+	field.Description, _ = schema["description"].(string)
+	if field.Description == "" {
+		field.Description, _ = resolved["description"].(string)
+	}
+	if enum, isArray := resolved["enum"].([]interface{}); isArray {
+		if encoded, err := json.Marshal(enum); err == nil {
+			field.Enum = string(encoded)
+		}
+	}
+
+	if index, exists := flattener.seen[path]; exists {
+		existing := &flattener.fields[index]
+		existing.Type = joinFieldTypes(append(strings.Split(existing.Type, ","), types...))
+		existing.Required = existing.Required || required
+		if existing.Format == "" {
+			existing.Format = field.Format
+		}
+		if existing.Description == "" {
+			existing.Description = field.Description
+		}
+		if existing.Enum == "" {
+			existing.Enum = field.Enum
+		}
+		return
+	}
+	flattener.seen[path] = len(flattener.fields)
+	flattener.fields = append(flattener.fields, field)
+}
+
+// flatten adds properties of the schema at the path of the data
+func (flattener *jsonSchemaFieldsFlattener) flatten(node interface{}, path string, depth int) {
+	schema, isObject := node.(map[string]interface{})
+	if !isObject || depth > maxFieldDepth {
+		return
+	}
+	schema, reference := flattener.resolve(schema)
+	if schema == nil {
+		return
+	}
+	if reference != "" {
+		for _, resolving := range flattener.resolving {
+			if resolving == reference {
+				return
+			}
+		}
+		flattener.resolving = append(flattener.resolving, reference)
+		defer func() { flattener.resolving = flattener.resolving[:len(flattener.resolving)-1] }()
+	}
+
+	required := make(map[string]bool)
+	if requiredNames, isArray := schema["required"].([]interface{}); isArray {
+		for _, name := range requiredNames {
+			if nameString, isString := name.(string); isString {
+				required[nameString] = true
+			}
+		}
+	}
+	if properties, isObject := schema["properties"].(map[string]interface{}); isObject {
+		names := make([]string, 0, len(properties))
+		for name := range properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			property, isObject := properties[name].(map[string]interface{})
+			if !isObject {
+				continue
+			}
+			propertyPath := path + "/" + escapeJSONPointer(name)
+			flattener.add(propertyPath, name, property, required[name])
+			flattener.flatten(property, propertyPath, depth+1)
+		}
+	}
+
+	if items, isObject := schema["items"].(map[string]interface{}); isObject {
+		flattener.flatten(items, path+"/*", depth+1)
+	}
+	if prefixItems, isArray := schema["prefixItems"].([]interface{}); isArray {
+		for _, item := range prefixItems {
+			flattener.flatten(item, path+"/*", depth+1)
+		}
+	}
+	for _, keyword := range []string{"allOf", "anyOf", "oneOf"} {
+		branches, _ := schema[keyword].([]interface{})
+		for _, branch := range branches {
+			flattener.flatten(branch, path, depth+1)
+		}
+	}
+}
+
+// joinFieldTypes joins distinct types, sorted
+func joinFieldTypes(types []string) string {
+	unique := make(map[string]bool)
+	for _, name := range types {
+		if name != "" {
+			unique[name] = true
+		}
+	}
+	sorted := make([]string, 0, len(unique))
+	for name := range unique {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+// flattenAvroFields adds fields of records, recursive records are flattened once on every path
+func flattenAvroFields(schema *avro.Schema, path string, flattening map[string]bool, fields *[]db.SchemaFieldsDBModel) {
+	switch schema.Type {
+	case avro.TypeRecord:
+		if flattening[schema.Name] || strings.Count(path, "/") > maxFieldDepth {
+			return
+		}
+		flattening[schema.Name] = true
+		defer delete(flattening, schema.Name)
+		for _, field := range schema.Fields {
+			fieldPath := path + "/" + escapeJSONPointer(field.Name)
+			catalogField := db.SchemaFieldsDBModel{
+				InDefaultMessageType: true,
+				Path:                 fieldPath,
+				Name:                 field.Name,
+				Description:          field.Doc,
+			}
+			branches := []*avro.Schema{field.Type}
+			if field.Type.Type == avro.TypeUnion {
+				branches = field.Type.Branches
+			}
+			var types []string
+			nullable := false
+			for _, branch := range branches {
+				types = append(types, branch.TypeName())
+				if branch.Type == avro.TypeNull {
+					nullable = true
+				}
+				if catalogField.Format == "" {
+					catalogField.Format = branch.LogicalType
+				}
+				if branch.Type == avro.TypeEnum && catalogField.Enum == "" {
+					if encoded, err := json.Marshal(branch.Symbols); err == nil {
+						catalogField.Enum = string(encoded)
+					}
+				}
+			}
+			catalogField.Type = strings.Join(types, ",")
+			catalogField.Required = !nullable && !field.HasDefault
+			*fields = append(*fields, catalogField)
+			for _, branch := range branches {
+				flattenAvroFields(branch, fieldPath, flattening, fields)
+			}
+		}
+	case avro.TypeArray:
+		flattenAvroFields(schema.Items, path+"/*", flattening, fields)
+	case avro.TypeMap:
+		flattenAvroFields(schema.Values, path+"/*", flattening, fields)
+	}
+}
+
+// flattenProtobufFields adds fields of the message, well-known types are not flattened
+func flattenProtobufFields(message *protobuf.Message, path string, flattening map[string]bool, fields *[]db.SchemaFieldsDBModel) {
+	if flattening[message.Name] || strings.HasPrefix(message.Name, "google.protobuf.") ||
+		strings.Count(path, "/") > maxFieldDepth {
+		return
+	}
+	flattening[message.Name] = true
+	defer delete(flattening, message.Name)
+	for _, field := range message.Fields {
+		fieldPath := path + "/" + escapeJSONPointer(field.Name)
+		catalogField := db.SchemaFieldsDBModel{
+			Path:        fieldPath,
+			Name:        field.Name,
+			Type:        field.Type,
+			Description: field.Doc,
+		}
+		switch {
+		case field.IsMap():
+			catalogField.Type = "map<" + field.KeyType + "," + field.Type + ">"
+		case field.Repeated:
+			catalogField.Type = "repeated " + field.Type
+		}
+		if field.Enum != nil {
+			values := make([]string, 0, len(field.Enum.Values))
+			for _, value := range field.Enum.Values {
+				values = append(values, value.Name)
+			}
+			if encoded, err := json.Marshal(values); err == nil {
+				catalogField.Enum = string(encoded)
+			}
+		}
+		*fields = append(*fields, catalogField)
+		if field.Message != nil {
+			nestedPath := fieldPath
+			if field.IsMap() || field.Repeated {
+				nestedPath += "/*"
+			}
+			flattenProtobufFields(field.Message, nestedPath, flattening, fields)
+		}
+	}
+}
+
+// catalogSchemaVersionFields stores fields of the version unless they are stored already
+func catalogSchemaVersionFields(schema db.SchemasDBModel, version db.SchemaVersionsDBModel) error {
+	return db.GetDB().Transaction(func(tx *gorm.DB) error {
+		// Concurrent catalogs of the version wait for the row and find it cataloged
+		result := tx.Model(&db.SchemaVersionsDBModel{}).
+			Where("id = ? AND fields_cataloged_at IS NULL", version.ID).
+			UpdateColumn("fields_cataloged_at", time.Now())
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		fields := schemaFieldsOfContent(schema.ProjectID, schema.Name, schema.Type, version.Schema)
+		if len(fields) == 0 {
+			return nil
+		}
+		for index := range fields {
+			fields[index].ProjectID = schema.ProjectID
+			fields[index].SchemaID = schema.ID
+			fields[index].SchemaVersion = version.Version
+		}
+		return tx.CreateInBatches(fields, 500).Error
+	})
+}
+
+// catalogFieldsOfLatestVersion catalogs the version the schema object points at, failures are logged
+func (schema *SchemaObject) catalogFieldsOfLatestVersion() {
+	var version db.SchemaVersionsDBModel
+	err := db.GetDB().Where("schema_id = ? AND version = ?", schema.dbModel.ID, schema.dbModel.Version).
+		First(&version).Error
+	if err == nil {
+		err = catalogSchemaVersionFields(schema.dbModel, version)
+	}
+	if err != nil {
+		log.Warnf("Failed to catalog fields of schema %s version %d: %v", schema.dbModel.ID, schema.dbModel.Version, err)
+	}
+}
+
+// catalogProjectSchemaFields catalogs versions of active schemas of the project which are not cataloged yet
+func catalogProjectSchemaFields(projectID uuid.UUID) error {
+	var schemas []db.SchemasDBModel
+	err := db.GetDB().
+		Where("project_id = ? AND status = ?", projectID, "active").
+		Where("EXISTS (SELECT 1 FROM schema_versions v WHERE v.schema_id = schemas.id " +
+			"AND v.fields_cataloged_at IS NULL AND v.deleted_at IS NULL)").
+		Find(&schemas).Error
+	if err != nil {
+		return err
+	}
+	for _, schema := range schemas {
+		var versions []db.SchemaVersionsDBModel
+		err := db.GetDB().Where("schema_id = ? AND fields_cataloged_at IS NULL", schema.ID).
+			Order("version asc").Find(&versions).Error
+		if err != nil {
+			return err
+		}
+		for _, version := range versions {
+			if err := catalogSchemaVersionFields(schema, version); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// GetFields returns the field catalog of the version
+func (schemaVersion *SchemaVersionObject) GetFields() ([]SchemaFieldSerializerStruct, error) {
+	schema, err := schemaVersion.getSchemaRecord()
+	if err != nil {
+		return nil, err
+	}
+	if schemaVersion.dbModel.FieldsCatalogedAt == nil {
+		if err := catalogSchemaVersionFields(*schema, schemaVersion.dbModel); err != nil {
+			return nil, err
+		}
+	}
+
+	var fields []db.SchemaFieldsDBModel
+	err = db.GetDB().Where("schema_id = ? AND schema_version = ?", schema.ID, schemaVersion.dbModel.Version).
+		Order("message_type, path").Find(&fields).Error
+	if err != nil {
+		return nil, err
+	}
+	serialized := make([]SchemaFieldSerializerStruct, 0, len(fields))
+	for _, field := range fields {
+		serialized = append(serialized, serializeSchemaField(field))
+	}
+	return serialized, nil
+}
+
+type schemaFieldSearchRow struct {
+	db.SchemaFieldsDBModel
+	SchemaName string
+	SchemaType string
+}
+
+// SearchSchemaFields finds fields named so (case-insensitively) in all versions of active schemas
+// of the project, with the messages which use the versions
+func SearchSchemaFields(projectID uuid.UUID, name string) (*SchemaFieldSearchSerializerStruct, error) {
+	if err := catalogProjectSchemaFields(projectID); err != nil {
+		return nil, err
+	}
+
+	var rows []schemaFieldSearchRow
+	err := db.GetDB().Table("schema_fields AS f").
+		Select("f.*, s.name AS schema_name, s.type AS schema_type").
+		Joins("JOIN schemas s ON s.id = f.schema_id AND s.status = 'active' AND s.deleted_at IS NULL").
+		Joins("JOIN schema_versions v ON v.schema_id = f.schema_id AND v.version = f.schema_version AND v.deleted_at IS NULL").
+		Where("f.project_id = ? AND lower(f.name) = lower(?)", projectID, name).
+		Order("s.name, f.schema_version, f.message_type, f.path").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	occurrences, err := serializeSchemaFieldOccurrences(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	search := &SchemaFieldSearchSerializerStruct{
+		Name:   name,
+		Types:  make([]string, 0),
+		Fields: occurrences,
+	}
+	types := make(map[string]bool)
+	for _, row := range rows {
+		if !types[row.Type] {
+			types[row.Type] = true
+			search.Types = append(search.Types, row.Type)
+		}
+	}
+	sort.Strings(search.Types)
+	return search, nil
+}
+
+// serializeSchemaFieldOccurrences serializes found fields with the messages which use them. Messages use
+// fields of the message type they select, or of the default one.
+func serializeSchemaFieldOccurrences(rows []schemaFieldSearchRow) ([]SchemaFieldOccurrenceSerializerStruct, error) {
+	schemaIDs := make([]uuid.UUID, 0, len(rows))
+	seen := make(map[uuid.UUID]bool)
+	for _, row := range rows {
+		if !seen[row.SchemaID] {
+			seen[row.SchemaID] = true
+			schemaIDs = append(schemaIDs, row.SchemaID)
+		}
+	}
+	var messages []db.MessagesDBModel
+	if len(schemaIDs) > 0 {
+		err := db.GetDB().Where("schema_id IN ? AND status = ?", schemaIDs, "active").Order("name").Find(&messages).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	occurrences := make([]SchemaFieldOccurrenceSerializerStruct, 0, len(rows))
+	for _, row := range rows {
+		occurrence := SchemaFieldOccurrenceSerializerStruct{
+			SchemaID:                    row.SchemaID.String(),
+			SchemaName:                  row.SchemaName,
+			SchemaType:                  row.SchemaType,
+			SchemaVersion:               row.SchemaVersion,
+			SchemaURI:                   SchemaReferenceURI(row.SchemaName, row.SchemaVersion),
+			SchemaFieldSerializerStruct: serializeSchemaField(row.SchemaFieldsDBModel),
+			Messages:                    make([]SchemaUsageMessageStruct, 0),
+		}
+		for _, message := range messages {
+			if message.SchemaID != row.SchemaID || message.SchemaVersion != row.SchemaVersion {
+				continue
+			}
+			if message.SchemaMessageType == row.MessageType || (message.SchemaMessageType == "" && row.InDefaultMessageType) {
+				occurrence.Messages = append(occurrence.Messages, SchemaUsageMessageStruct{
+					ID:            message.ID.String(),
+					Name:          message.Name,
+					SchemaVersion: message.SchemaVersion,
+				})
+			}
+		}
+		occurrences = append(occurrences, occurrence)
+	}
+	return occurrences, nil
+}
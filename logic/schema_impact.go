@@ -0,0 +1,261 @@
+package logic
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/fusioncatltd/fusioncat/db"
+	"github.com/google/uuid"
+)
+
+// Impact analysis shows what depends on every version of a schema: messages pinned to the version, apps which
+// send or receive the messages and resources the messages travel through. For a proposed new version it also
+// shows which of those apps would break. Receivers of a message still pinned to an older version break when
+// data of the proposed version is invalid against their version (forward violations), senders break when
+// readers on the proposed version can't read data of their version (backward violations).
+
+var ErrSchemaImpactNotSupported = errors.New("compatibility of proposed versions can be checked only for JSON and Avro schemas")
+
+// SchemaImpactUsageSerializerStruct is an app which sends or receives a message through a resource
+type SchemaImpactUsageSerializerStruct struct {
+	AppID        string `json:"app_id"`
+	AppName      string `json:"app_name"`
+	Direction    string `json:"direction"`
+	ResourceID   string `json:"resource_id"`
+	ResourceName string `json:"resource_name"`
+	ServerID     string `json:"server_id"`
+	ServerName   string `json:"server_name"`
+}
+
+type SchemaImpactMessageSerializerStruct struct {
+	ID                string                              `json:"id"`
+	Name              string                              `json:"name"`
+	SchemaMessageType string                              `json:"schema_message_type,omitempty"`
+	Usages            []SchemaImpactUsageSerializerStruct `json:"usages"`
+}
+
+type SchemaImpactVersionSerializerStruct struct {
+	Version  int                                   `json:"version"`
+	Messages []SchemaImpactMessageSerializerStruct `json:"messages"`
+}
+
+type SchemaImpactResourceSerializerStruct struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Mode         string `json:"mode"`
+	ResourceType string `json:"resource_type"`
+	ServerID     string `json:"server_id"`
+	ServerName   string `json:"server_name"`
+}
+
+// SchemaImpactBreakSerializerStruct is an app which would break if the proposed version is created
+type SchemaImpactBreakSerializerStruct struct {
+	Version      int                      `json:"version"`
+	MessageID    string                   `json:"message_id"`
+	MessageName  string                   `json:"message_name"`
+	AppID        string                   `json:"app_id"`
+	AppName      string                   `json:"app_name"`
+	Direction    string                   `json:"direction"`
+	ResourceID   string                   `json:"resource_id"`
+	ResourceName string                   `json:"resource_name"`
+	Violations   []CompatibilityViolation `json:"violations"`
+}
+
+type SchemaImpactProposalSerializerStruct struct {
+	// Compatible tells if the proposed version satisfies the compatibility mode of the schema
+	Compatible bool                                `json:"compatible"`
+	Violations []CompatibilityViolation            `json:"violations"`
+	Breaks     []SchemaImpactBreakSerializerStruct `json:"breaks"`
+}
+
+type SchemaImpactSerializerStruct struct {
+	SchemaID       string                                 `json:"schema_id"`
+	SchemaName     string                                 `json:"schema_name"`
+	CurrentVersion int                                    `json:"current_version"`
+	Compatibility  string                                 `json:"compatibility"`
+	Versions       []SchemaImpactVersionSerializerStruct  `json:"versions"`
+	Resources      []SchemaImpactResourceSerializerStruct `json:"resources"`
+	ProposedChange *SchemaImpactProposalSerializerStruct  `json:"proposed_change,omitempty"`
+}
+
+type schemaImpactUsageRow struct {
+	MessageID    uuid.UUID
+	Direction    string
+	AppID        uuid.UUID
+	AppName      string
+	ResourceID   uuid.UUID
+	ResourceName string
+	ResourceMode string
+	ResourceType string
+	ServerID     uuid.UUID
+	ServerName   string
+}
+
+// AnalyzeImpact lists what depends on every version of the schema. When the proposed content is not empty,
+// it is checked against every version with pinned messages and the apps which would break are listed.
+// The proposed content must be a valid schema of the type of the schema.
+func (schema *SchemaObject) AnalyzeImpact(proposedContent string) (*SchemaImpactSerializerStruct, error) {
+	if proposedContent != "" && schema.dbModel.Type == SchemaTypeProtobuf {
+		return nil, ErrSchemaImpactNotSupported
+	}
+
+	connection := db.GetDB()
+	var versions []db.SchemaVersionsDBModel
+	err := connection.Where("schema_id = ?", schema.dbModel.ID).Order("version asc").Find(&versions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []db.MessagesDBModel
+	err = connection.Where("schema_id = ? AND status = ?", schema.dbModel.ID, "active").
+		Order("name").Find(&messages).Error
+	if err != nil {
+		return nil, err
+	}
+
+	messageIDs := make([]uuid.UUID, 0, len(messages))
+	for _, message := range messages {
+		messageIDs = append(messageIDs, message.ID)
+	}
+	var usages []schemaImpactUsageRow
+	if len(messageIDs) > 0 {
+		err = connection.Table("apps_resources_messages AS arm").
+			Select("arm.message_id, arm.direction, a.id AS app_id, a.name AS app_name, "+
+				"r.id AS resource_id, r.name AS resource_name, r.mode AS resource_mode, r.resource_type, "+
+				"s.id AS server_id, s.name AS server_name").
+			Joins("JOIN apps a ON a.id = arm.app_id AND a.status = 'active' AND a.deleted_at IS NULL").
+			Joins("JOIN resources r ON r.id = arm.resource_id AND r.status = 'active' AND r.deleted_at IS NULL").
+			Joins("JOIN servers s ON s.id = r.server_id").
+			Where("arm.deleted_at IS NULL AND arm.status = 'active'").
+			Where("arm.message_id IN ?", messageIDs).
+			Order("a.name, arm.direction, s.name, r.name").
+			Scan(&usages).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	usagesOfMessages := make(map[uuid.UUID][]SchemaImpactUsageSerializerStruct)
+	resources := make(map[uuid.UUID]SchemaImpactResourceSerializerStruct)
+	for _, usage := range usages {
+		usagesOfMessages[usage.MessageID] = append(usagesOfMessages[usage.MessageID], SchemaImpactUsageSerializerStruct{
+			AppID:        usage.AppID.String(),
+			AppName:      usage.AppName,
+			Direction:    usage.Direction,
+			ResourceID:   usage.ResourceID.String(),
+			ResourceName: usage.ResourceName,
+			ServerID:     usage.ServerID.String(),
+			ServerName:   usage.ServerName,
+		})
+		resources[usage.ResourceID] = SchemaImpactResourceSerializerStruct{
+			ID:           usage.ResourceID.String(),
+			Name:         usage.ResourceName,
+			Mode:         usage.ResourceMode,
+			ResourceType: usage.ResourceType,
+			ServerID:     usage.ServerID.String(),
+			ServerName:   usage.ServerName,
+		}
+	}
+
+	messagesOfVersions := make(map[int][]SchemaImpactMessageSerializerStruct)
+	for _, message := range messages {
+		messageUsages := usagesOfMessages[message.ID]
+		if messageUsages == nil {
+			messageUsages = make([]SchemaImpactUsageSerializerStruct, 0)
+		}
+		messagesOfVersions[message.SchemaVersion] = append(messagesOfVersions[message.SchemaVersion],
+			SchemaImpactMessageSerializerStruct{
+				ID:                message.ID.String(),
+				Name:              message.Name,
+				SchemaMessageType: message.SchemaMessageType,
+				Usages:            messageUsages,
+			})
+	}
+
+	impact := &SchemaImpactSerializerStruct{
+		SchemaID:       schema.dbModel.ID.String(),
+		SchemaName:     schema.dbModel.Name,
+		CurrentVersion: schema.dbModel.Version,
+		Compatibility:  schema.GetCompatibility(),
+		Versions:       make([]SchemaImpactVersionSerializerStruct, 0, len(versions)),
+		Resources:      make([]SchemaImpactResourceSerializerStruct, 0, len(resources)),
+	}
+	for _, version := range versions {
+		versionMessages := messagesOfVersions[version.Version]
+		if versionMessages == nil {
+			versionMessages = make([]SchemaImpactMessageSerializerStruct, 0)
+		}
+		impact.Versions = append(impact.Versions, SchemaImpactVersionSerializerStruct{
+			Version:  version.Version,
+			Messages: versionMessages,
+		})
+	}
+	for _, resource := range resources {
+		impact.Resources = append(impact.Resources, resource)
+	}
+	sort.Slice(impact.Resources, func(i, j int) bool {
+		if impact.Resources[i].ServerName != impact.Resources[j].ServerName {
+			return impact.Resources[i].ServerName < impact.Resources[j].ServerName
+		}
+		return impact.Resources[i].Name < impact.Resources[j].Name
+	})
+
+	if proposedContent == "" {
+		return impact, nil
+	}
+
+	violations, err := schema.checkCompatibilityWithVersions(schema.GetCompatibility(),
+		append([]db.SchemaVersionsDBModel(nil), versions...), proposedContent)
+	if err != nil {
+		return nil, err
+	}
+	proposal := &SchemaImpactProposalSerializerStruct{
+		Compatible: len(violations) == 0,
+		Violations: make([]CompatibilityViolation, 0, len(violations)),
+		Breaks:     make([]SchemaImpactBreakSerializerStruct, 0),
+	}
+	proposal.Violations = append(proposal.Violations, violations...)
+
+	for _, version := range versions {
+		if len(messagesOfVersions[version.Version]) == 0 {
+			continue
+		}
+		// Both directions are checked regardless of the mode, the mode doesn't change what breaks apps
+		versionViolations, err := schema.checkCompatibilityWithVersions(CompatibilityFull,
+			[]db.SchemaVersionsDBModel{version}, proposedContent)
+		if err != nil {
+			return nil, err
+		}
+		violationsOfDirections := make(map[string][]CompatibilityViolation)
+		for _, violation := range versionViolations {
+			violationsOfDirections[violation.Direction] = append(violationsOfDirections[violation.Direction], violation)
+		}
+
+		for _, message := range messagesOfVersions[version.Version] {
+			for _, usage := range message.Usages {
+				// Receivers read data of the proposed version with their version, senders write data
+				// which readers on the proposed version must read
+				direction := CompatibilityDirectionBackward
+				if usage.Direction == "receives" {
+					direction = CompatibilityDirectionForward
+				}
+				if len(violationsOfDirections[direction]) == 0 {
+					continue
+				}
+				proposal.Breaks = append(proposal.Breaks, SchemaImpactBreakSerializerStruct{
+					Version:      version.Version,
+					MessageID:    message.ID,
+					MessageName:  message.Name,
+					AppID:        usage.AppID,
+					AppName:      usage.AppName,
+					Direction:    usage.Direction,
+					ResourceID:   usage.ResourceID,
+					ResourceName: usage.ResourceName,
+					Violations:   violationsOfDirections[direction],
+				})
+			}
+		}
+	}
+	impact.ProposedChange = proposal
+	return impact, nil
+}
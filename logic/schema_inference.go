@@ -0,0 +1,39 @@
+package logic
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/fusioncatltd/fusioncat/jsonschemainfer"
+)
+
+var ErrNoSamplesToInferFrom = errors.New("at least one sample payload is required")
+
+// InferredSchemaSerializerStruct is a JSON schema inferred from sample payloads. SavedSchema is set
+// when the inferred schema is saved as a new schema or as a new version of an existing one.
+type InferredSchemaSerializerStruct struct {
+	Schema      string                    `json:"schema"`
+	Samples     int                       `json:"samples"`
+	SavedSchema *SchemaDBSerializerStruct `json:"saved_schema,omitempty"`
+}
+
+// InferJSONSchema infers a draft 2020-12 JSON schema describing all samples, see jsonschemainfer.
+// The title of the schema is set when it is not empty.
+func InferJSONSchema(samples []interface{}, title string) (*InferredSchemaSerializerStruct, error) {
+	if len(samples) == 0 {
+		return nil, ErrNoSamplesToInferFrom
+	}
+
+	schema := jsonschemainfer.Infer(samples)
+	if title != "" {
+		schema["title"] = title
+	}
+	content, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return &InferredSchemaSerializerStruct{
+		Schema:  string(content),
+		Samples: len(samples),
+	}, nil
+}
@@ -0,0 +1,235 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/fusioncatltd/fusioncat/db"
+	"github.com/fusioncatltd/fusioncat/jsonschemalint"
+	"github.com/google/uuid"
+)
+
+// JSON schemas are linted with the rules of jsonschemalint when they are created or modified, and on demand
+// for whole projects. Projects enable and disable rules and set their severities, findings of rules with
+// the "error" severity reject new schemas and versions. Other schema types are not linted.
+
+var (
+	ErrUnknownLintRule       = errors.New("unknown lint rule")
+	ErrInvalidLintRuleOption = errors.New("invalid option of the lint rule")
+)
+
+// SchemaLintRuleSerializerStruct is the configuration of a lint rule in a project
+type SchemaLintRuleSerializerStruct struct {
+	Rule        string   `json:"rule"`
+	Description string   `json:"description"`
+	Enabled     bool     `json:"enabled"`
+	Severity    string   `json:"severity"`
+	Option      string   `json:"option,omitempty"`
+	Options     []string `json:"options,omitempty"`
+}
+
+// SchemaLintError is returned when a schema breaks lint rules with the "error" severity
+type SchemaLintError struct {
+	Findings []jsonschemalint.Finding
+}
+
+func (err *SchemaLintError) Error() string {
+	errorsFound := 0
+	for _, finding := range err.Findings {
+		if finding.Severity == jsonschemalint.SeverityError {
+			errorsFound++
+		}
+	}
+	return fmt.Sprintf("the schema breaks lint rules: %d errors found", errorsFound)
+}
+
+// SchemaLintErrorSerializerStruct is the response to schemas rejected by lint rules
+type SchemaLintErrorSerializerStruct struct {
+	Error    string                   `json:"error"`
+	Findings []jsonschemalint.Finding `json:"findings"`
+}
+
+func (err *SchemaLintError) Serialize() *SchemaLintErrorSerializerStruct {
+	return &SchemaLintErrorSerializerStruct{
+		Error:    err.Error(),
+		Findings: err.Findings,
+	}
+}
+
+// SchemaLintResultSerializerStruct lists findings of the current version of a schema
+type SchemaLintResultSerializerStruct struct {
+	SchemaID   string                   `json:"schema_id"`
+	SchemaName string                   `json:"schema_name"`
+	Version    int                      `json:"version"`
+	Findings   []jsonschemalint.Finding `json:"findings"`
+}
+
+// ProjectLintSerializerStruct lists findings of all JSON schemas of a project, counted by severity
+type ProjectLintSerializerStruct struct {
+	Schemas  []SchemaLintResultSerializerStruct `json:"schemas"`
+	Errors   int                                `json:"errors"`
+	Warnings int                                `json:"warnings"`
+	Infos    int                                `json:"infos"`
+}
+
+// projectLintConfig returns the default lint configuration with the overrides of the project
+func projectLintConfig(projectID uuid.UUID) (jsonschemalint.Config, error) {
+	var overrides []db.SchemaLintRulesDBModel
+	if err := db.GetDB().Where("project_id = ?", projectID).Find(&overrides).Error; err != nil {
+		return nil, err
+	}
+	config := jsonschemalint.DefaultConfig()
+	for _, override := range overrides {
+		ruleConfig, exists := config[override.Rule]
+		if !exists {
+			continue
+		}
+		ruleConfig.Enabled = override.Enabled
+		ruleConfig.Severity = override.Severity
+		if override.Option != "" {
+			ruleConfig.Option = override.Option
+		}
+		config[override.Rule] = ruleConfig
+	}
+	return config, nil
+}
+
+// GetSchemaLintRules returns all lint rules with their configuration in the project
+func GetSchemaLintRules(projectID uuid.UUID) ([]SchemaLintRuleSerializerStruct, error) {
+	config, err := projectLintConfig(projectID)
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]SchemaLintRuleSerializerStruct, 0, len(jsonschemalint.Rules))
+	for _, rule := range jsonschemalint.Rules {
+		rules = append(rules, serializeSchemaLintRule(rule, config[rule.ID]))
+	}
+	return rules, nil
+}
+
+func serializeSchemaLintRule(rule jsonschemalint.Rule, config jsonschemalint.RuleConfig) SchemaLintRuleSerializerStruct {
+	return SchemaLintRuleSerializerStruct{
+		Rule:        rule.ID,
+		Description: rule.Description,
+		Enabled:     config.Enabled,
+		Severity:    config.Severity,
+		Option:      config.Option,
+		Options:     rule.Options,
+	}
+}
+
+// ConfigureSchemaLintRule changes the configuration of a lint rule in the project. Nil enabled,
+// an empty severity or an empty option keep the current values.
+func ConfigureSchemaLintRule(projectID uuid.UUID, ruleID string, enabled *bool, severity string,
+	option string) (*SchemaLintRuleSerializerStruct, error) {
+	rule, exists := jsonschemalint.FindRule(ruleID)
+	if !exists {
+		return nil, ErrUnknownLintRule
+	}
+	if option != "" {
+		valid := false
+		for _, allowed := range rule.Options {
+			valid = valid || allowed == option
+		}
+		if !valid {
+			if len(rule.Options) == 0 {
+				return nil, fmt.Errorf("%w: rule %s has no options", ErrInvalidLintRuleOption, rule.ID)
+			}
+			return nil, fmt.Errorf("%w: rule %s takes one of %v", ErrInvalidLintRuleOption, rule.ID, rule.Options)
+		}
+	}
+
+	config, err := projectLintConfig(projectID)
+	if err != nil {
+		return nil, err
+	}
+	ruleConfig := config[rule.ID]
+	if enabled != nil {
+		ruleConfig.Enabled = *enabled
+	}
+	if severity != "" {
+		ruleConfig.Severity = severity
+	}
+	if option != "" {
+		ruleConfig.Option = option
+	}
+
+	override := db.SchemaLintRulesDBModel{}
+	db.GetDB().Where("project_id = ? AND rule = ?", projectID, rule.ID).First(&override)
+	override.ProjectID = projectID
+	override.Rule = rule.ID
+	override.Enabled = ruleConfig.Enabled
+	override.Severity = ruleConfig.Severity
+	override.Option = ruleConfig.Option
+	if err := db.GetDB().Save(&override).Error; err != nil {
+		return nil, err
+	}
+
+	serialized := serializeSchemaLintRule(rule, ruleConfig)
+	return &serialized, nil
+}
+
+// LintSchemaContent lints the content of a new schema or version with the rules of the project.
+// *SchemaLintError is returned along with the findings when some of them are errors.
+func LintSchemaContent(projectID uuid.UUID, schemaType string, content string) ([]jsonschemalint.Finding, error) {
+	if schemaType != SchemaTypeJSONSchema {
+		return nil, nil
+	}
+	config, err := projectLintConfig(projectID)
+	if err != nil {
+		return nil, err
+	}
+	findings, err := jsonschemalint.LintJSON([]byte(content), config)
+	if err != nil {
+		return nil, err
+	}
+	for _, finding := range findings {
+		if finding.Severity == jsonschemalint.SeverityError {
+			return findings, &SchemaLintError{Findings: findings}
+		}
+	}
+	return findings, nil
+}
+
+// LintProject lints the current versions of all active JSON schemas of the project
+func LintProject(projectID uuid.UUID) (*ProjectLintSerializerStruct, error) {
+	config, err := projectLintConfig(projectID)
+	if err != nil {
+		return nil, err
+	}
+	var schemas []db.SchemasDBModel
+	err = db.GetDB().Where("project_id = ? AND status = ? AND type = ?", projectID, "active", SchemaTypeJSONSchema).
+		Order("name").Find(&schemas).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ProjectLintSerializerStruct{Schemas: make([]SchemaLintResultSerializerStruct, 0, len(schemas))}
+	for _, schema := range schemas {
+		findings, err := jsonschemalint.LintJSON([]byte(schema.Schema), config)
+		if err != nil {
+			// Stored schemas are valid JSON, schemas which are not can't be linted
+			findings = nil
+		}
+		if findings == nil {
+			findings = make([]jsonschemalint.Finding, 0)
+		}
+		for _, finding := range findings {
+			switch finding.Severity {
+			case jsonschemalint.SeverityError:
+				result.Errors++
+			case jsonschemalint.SeverityWarning:
+				result.Warnings++
+			default:
+				result.Infos++
+			}
+		}
+		result.Schemas = append(result.Schemas, SchemaLintResultSerializerStruct{
+			SchemaID:   schema.ID.String(),
+			SchemaName: schema.Name,
+			Version:    schema.Version,
+			Findings:   findings,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,229 @@
+package logic
+
+import (
+	"bytes"
+	"container/list"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/fusioncatltd/fusioncat/avro"
+	"github.com/fusioncatltd/fusioncat/protobuf"
+	"github.com/google/uuid"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// payloadValidator checks a single JSON payload against a compiled schema version
+type payloadValidator func(payload []byte) error
+
+// maxCachedPayloadValidators limits the number of compiled schema versions kept in memory
+const maxCachedPayloadValidators = 256
+
+// Content of schema versions never changes, so compiled JSON and Avro schemas are cached by the ID
+// of the version record. References of JSON schemas point at versions, which never change either. Protobuf schemas are not cached: their imports resolve to the latest versions
+// of other schemas, which change independently. Least recently used validators are evicted first.
+var payloadValidatorsCache = struct {
+	sync.Mutex
+	entries map[uuid.UUID]*list.Element
+	order   *list.List
+}{
+	entries: make(map[uuid.UUID]*list.Element),
+	order:   list.New(),
+}
+
+type cachedPayloadValidator struct {
+	versionID uuid.UUID
+	validator payloadValidator
+}
+
+func cachedPayloadValidatorOf(versionID uuid.UUID) (payloadValidator, bool) {
+	payloadValidatorsCache.Lock()
+	defer payloadValidatorsCache.Unlock()
+	element, exists := payloadValidatorsCache.entries[versionID]
+	if !exists {
+		return nil, false
+	}
+	payloadValidatorsCache.order.MoveToFront(element)
+	return element.Value.(*cachedPayloadValidator).validator, true
+}
+
+func cachePayloadValidator(versionID uuid.UUID, validator payloadValidator) {
+	payloadValidatorsCache.Lock()
+	defer payloadValidatorsCache.Unlock()
+	if _, exists := payloadValidatorsCache.entries[versionID]; exists {
+		return
+	}
+	payloadValidatorsCache.entries[versionID] = payloadValidatorsCache.order.PushFront(
+		&cachedPayloadValidator{versionID: versionID, validator: validator})
+	if payloadValidatorsCache.order.Len() > maxCachedPayloadValidators {
+		oldest := payloadValidatorsCache.order.Back()
+		payloadValidatorsCache.order.Remove(oldest)
+		delete(payloadValidatorsCache.entries, oldest.Value.(*cachedPayloadValidator).versionID)
+	}
+}
+
+// payloadValidator compiles the schema version, or takes the compiled version from the cache
+func (schemaVersion *SchemaVersionObject) payloadValidator(schemaType string, messageType string) (payloadValidator, error) {
+	if schemaType != SchemaTypeProtobuf {
+		if validator, cached := cachedPayloadValidatorOf(schemaVersion.dbModel.ID); cached {
+			return validator, nil
+		}
+	}
+
+	var validator payloadValidator
+	switch schemaType {
+	case SchemaTypeJSONSchema:
+		schema, err := schemaVersion.getSchemaRecord()
+		if err != nil {
+			return nil, err
+		}
+		compiled, err := compileJSONSchema(schema.ProjectID, schemaVersion.dbModel.Schema, nil)
+		if err != nil {
+			return nil, err
+		}
+		validator = func(payload []byte) error {
+			decoder := json.NewDecoder(bytes.NewReader(payload))
+			decoder.UseNumber()
+			var value interface{}
+			if err := decoder.Decode(&value); err != nil {
+				return fmt.Errorf("invalid JSON: %v", err)
+			}
+			if _, err := decoder.Token(); err != io.EOF {
+				return errors.New("invalid JSON: unexpected data after the value")
+			}
+			return compiled.Validate(value)
+		}
+	case SchemaTypeAvro:
+		parsed, err := avro.Parse(schemaVersion.dbModel.Schema)
+		if err != nil {
+			return nil, &SchemaContentError{SchemaType: schemaType, Message: err.Error()}
+		}
+		validator = parsed.ValidateJSON
+	case SchemaTypeProtobuf:
+		schema, err := schemaVersion.getSchemaRecord()
+		if err != nil {
+			return nil, err
+		}
+		file, err := compileProtobufSchema(schema.ProjectID, schema.Name, schemaVersion.dbModel.Schema, nil)
+		if err != nil {
+			return nil, err
+		}
+		message, err := findProtobufMessage(file, messageType)
+		if err != nil {
+			return nil, err
+		}
+		return message.ValidateJSON, nil
+	default:
+		return nil, fmt.Errorf("unsupported schema type: %s", schemaType)
+	}
+
+	cachePayloadValidator(schemaVersion.dbModel.ID, validator)
+	return validator, nil
+}
+
+// PayloadValidationErrorStruct is a single failure of a payload. InstanceLocation is a JSON pointer
+// to the invalid value in the payload. Keyword failures are reported for JSON schemas only.
+type PayloadValidationErrorStruct struct {
+	InstanceLocation string `json:"instance_location"`
+	KeywordLocation  string `json:"keyword_location,omitempty"`
+	Keyword          string `json:"keyword,omitempty"`
+	Message          string `json:"message"`
+}
+
+type PayloadValidationResultStruct struct {
+	// Index is the position of the payload in the request
+	Index  int                            `json:"index"`
+	Valid  bool                           `json:"valid"`
+	Errors []PayloadValidationErrorStruct `json:"errors"`
+}
+
+type PayloadsValidationSerializerStruct struct {
+	SchemaID      string                          `json:"schema_id"`
+	SchemaVersion int                             `json:"schema_version"`
+	MessageType   string                          `json:"message_type,omitempty"`
+	Valid         bool                            `json:"valid"`
+	Results       []PayloadValidationResultStruct `json:"results"`
+}
+
+// ValidatePayloads checks every payload against the schema version. Invalid payloads don't fail the call,
+// errors are returned only when the schema version itself can't be compiled.
+func (schemaVersion *SchemaVersionObject) ValidatePayloads(schemaType string, messageType string,
+	payloads []json.RawMessage) (*PayloadsValidationSerializerStruct, error) {
+	validator, err := schemaVersion.payloadValidator(schemaType, messageType)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PayloadsValidationSerializerStruct{
+		SchemaID:      schemaVersion.dbModel.SchemaID.String(),
+		SchemaVersion: schemaVersion.dbModel.Version,
+		MessageType:   messageType,
+		Valid:         true,
+		Results:       make([]PayloadValidationResultStruct, 0, len(payloads)),
+	}
+	for index, payload := range payloads {
+		result := PayloadValidationResultStruct{
+			Index:  index,
+			Valid:  true,
+			Errors: make([]PayloadValidationErrorStruct, 0),
+		}
+		if err := validator(payload); err != nil {
+			result.Valid = false
+			result.Errors = payloadValidationErrors(err)
+			response.Valid = false
+		}
+		response.Results = append(response.Results, result)
+	}
+	return response, nil
+}
+
+// ValidatePayloads checks payloads against the schema version and the message type of the message
+func (message *MessageObject) ValidatePayloads(payloads []json.RawMessage) (*PayloadsValidationSerializerStruct, error) {
+	schemasManager := SchemaObjectsManager{}
+	schema, err := schemasManager.GetByID(message.dbModel.SchemaID)
+	if err != nil {
+		return nil, err
+	}
+	schemaVersion, err := schemasManager.GetSpecificVersionOfSchema(message.dbModel.SchemaID, message.dbModel.SchemaVersion)
+	if err != nil {
+		return nil, err
+	}
+	return schemaVersion.ValidatePayloads(schema.GetType(), message.dbModel.SchemaMessageType, payloads)
+}
+
+// payloadValidationErrors converts errors of validators into a flat list. Causes of JSON schema
+// errors form a tree, the leaves are the keywords which actually failed.
+func payloadValidationErrors(err error) []PayloadValidationErrorStruct {
+	var jsonSchemaError *jsonschema.ValidationError
+	var avroError *avro.ValidationError
+	var protobufError *protobuf.ValidationError
+	switch {
+	case errors.As(err, &jsonSchemaError):
+		var validationErrors []PayloadValidationErrorStruct
+		collectJSONSchemaValidationErrors(jsonSchemaError, &validationErrors)
+		return validationErrors
+	case errors.As(err, &avroError):
+		return []PayloadValidationErrorStruct{{InstanceLocation: avroError.Path, Message: avroError.Message}}
+	case errors.As(err, &protobufError):
+		return []PayloadValidationErrorStruct{{InstanceLocation: protobufError.Path, Message: protobufError.Message}}
+	}
+	return []PayloadValidationErrorStruct{{Message: err.Error()}}
+}
+
+func collectJSONSchemaValidationErrors(err *jsonschema.ValidationError, validationErrors *[]PayloadValidationErrorStruct) {
+	if len(err.Causes) > 0 {
+		for _, cause := range err.Causes {
+			collectJSONSchemaValidationErrors(cause, validationErrors)
+		}
+		return
+	}
+	*validationErrors = append(*validationErrors, PayloadValidationErrorStruct{
+		InstanceLocation: err.InstanceLocation,
+		KeywordLocation:  err.KeywordLocation,
+		Keyword:          err.KeywordLocation[strings.LastIndex(err.KeywordLocation, "/")+1:],
+		Message:          err.Message,
+	})
+}
@@ -0,0 +1,415 @@
+package logic
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fusioncatltd/fusioncat/db"
+	"github.com/google/uuid"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"gorm.io/gorm"
+)
+
+// JSON schemas reference other JSON schemas of the same project by URIs of their versions, e.g.
+// {"$ref": "fusioncat://schemas/address/versions/2"} or {"$ref": "fusioncat://schemas/money/versions/1#/$defs/currency"}.
+// Versions are immutable, so content behind a reference never changes. References are resolved during
+// validation, referenced versions are bundled into the schema for code generation and examples.
+// References are recorded when versions are created, referenced schemas can't be deleted.
+const schemaReferenceURIPrefix = "fusioncat://schemas/"
+
+// SchemaReferenceURI is the URI by which JSON schemas reference the version of a schema
+func SchemaReferenceURI(schemaName string, version int) string {
+	return fmt.Sprintf("%s%s/versions/%d", schemaReferenceURIPrefix, schemaName, version)
+}
+
+// schemaVersionKey identifies a version of a schema within a project
+type schemaVersionKey struct {
+	name    string
+	version int
+}
+
+// parseSchemaReferenceURI extracts the schema name and version from a reference URI, the fragment is ignored
+func parseSchemaReferenceURI(uri string) (schemaVersionKey, bool) {
+	if !strings.HasPrefix(uri, schemaReferenceURIPrefix) {
+		return schemaVersionKey{}, false
+	}
+	path := strings.TrimPrefix(uri, schemaReferenceURIPrefix)
+	if index := strings.Index(path, "#"); index >= 0 {
+		path = path[:index]
+	}
+	parts := strings.Split(path, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] != "versions" {
+		return schemaVersionKey{}, false
+	}
+	version, err := strconv.Atoi(parts[2])
+	if err != nil || version < 1 {
+		return schemaVersionKey{}, false
+	}
+	return schemaVersionKey{name: parts[0], version: version}, true
+}
+
+// referenceFragment returns the JSON pointer of the reference URI, e.g. "/$defs/currency"
+func referenceFragment(uri string) string {
+	if index := strings.Index(uri, "#"); index >= 0 {
+		return uri[index+1:]
+	}
+	return ""
+}
+
+func decodeJSONSchema(content string) (interface{}, error) {
+	decoder := json.NewDecoder(strings.NewReader(content))
+	decoder.UseNumber()
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+// walkReferences calls visit for every object of the schema which has a "$ref"
+func walkReferences(node interface{}, visit func(object map[string]interface{}, reference string)) {
+	switch value := node.(type) {
+	case map[string]interface{}:
+		if reference, isString := value["$ref"].(string); isString {
+			visit(value, reference)
+		}
+		for _, child := range value {
+			walkReferences(child, visit)
+		}
+	case []interface{}:
+		for _, child := range value {
+			walkReferences(child, visit)
+		}
+	}
+}
+
+// jsonSchemaReferences lists versions of project schemas referenced by the JSON schema
+func jsonSchemaReferences(content string) ([]schemaVersionKey, error) {
+	document, err := decodeJSONSchema(content)
+	if err != nil {
+		return nil, err
+	}
+	unique := make(map[schemaVersionKey]bool)
+	walkReferences(document, func(_ map[string]interface{}, reference string) {
+		if key, isReference := parseSchemaReferenceURI(reference); isReference {
+			unique[key] = true
+		}
+	})
+	references := make([]schemaVersionKey, 0, len(unique))
+	for key := range unique {
+		references = append(references, key)
+	}
+	sort.Slice(references, func(i, j int) bool {
+		if references[i].name != references[j].name {
+			return references[i].name < references[j].name
+		}
+		return references[i].version < references[j].version
+	})
+	return references, nil
+}
+
+// loadReferencedJSONSchema loads the content of a referenced version. Versions in pendingVersions take
+// precedence over stored versions, they are used to validate schemas which are not saved yet, e.g. during imports.
+func loadReferencedJSONSchema(projectID uuid.UUID, key schemaVersionKey, pendingVersions map[schemaVersionKey]string) (string, error) {
+	if source, exists := pendingVersions[key]; exists {
+		return source, nil
+	}
+	var referenced db.SchemasDBModel
+	result := db.GetDB().Where("project_id = ? AND name = ? AND type = ? AND status = ?",
+		projectID, key.name, SchemaTypeJSONSchema, "active").First(&referenced)
+	if result.Error != nil {
+		return "", fmt.Errorf("there is no JSON schema named %q in the project", key.name)
+	}
+	var version db.SchemaVersionsDBModel
+	result = db.GetDB().Where("schema_id = ? AND version = ?", referenced.ID, key.version).First(&version)
+	if result.Error != nil {
+		return "", fmt.Errorf("JSON schema %q has no version %d", key.name, key.version)
+	}
+	return version.Schema, nil
+}
+
+// compileJSONSchema compiles a JSON schema resolving its references to other schemas of the project.
+// Only references to project schemas can be loaded, other remote references are rejected.
+func compileJSONSchema(projectID uuid.UUID, content string, pendingVersions map[schemaVersionKey]string) (*jsonschema.Schema, error) {
+	compiler := jsonschema.NewCompiler()
+	compiler.LoadURL = func(url string) (io.ReadCloser, error) {
+		key, isReference := parseSchemaReferenceURI(url)
+		if !isReference {
+			return nil, fmt.Errorf("%s can't be loaded, only schemas of the project can be referenced, e.g. %s",
+				url, SchemaReferenceURI("address", 1))
+		}
+		source, err := loadReferencedJSONSchema(projectID, key, pendingVersions)
+		if err != nil {
+			return nil, err
+		}
+		return io.NopCloser(strings.NewReader(source)), nil
+	}
+	if err := compiler.AddResource("", strings.NewReader(content)); err != nil {
+		return nil, &SchemaContentError{SchemaType: SchemaTypeJSONSchema, Message: jsonSchemaCompilationMessage(err)}
+	}
+	compiled, err := compiler.Compile("")
+	if err != nil {
+		return nil, &SchemaContentError{SchemaType: SchemaTypeJSONSchema, Message: jsonSchemaCompilationMessage(err)}
+	}
+	return compiled, nil
+}
+
+// bundleJSONSchema makes a self-contained copy of the JSON schema: referenced versions are embedded
+// into "definitions" of the schema under the names of their schemas and references point at them.
+// Schemas without references are returned as they are.
+func bundleJSONSchema(projectID uuid.UUID, content string, pendingVersions map[schemaVersionKey]string) (string, error) {
+	references, err := jsonSchemaReferences(content)
+	if err != nil || len(references) == 0 {
+		return content, err
+	}
+	root, _ := decodeJSONSchema(content)
+	rootObject, isObject := root.(map[string]interface{})
+	if !isObject {
+		return content, nil
+	}
+
+	definitions, _ := rootObject["definitions"].(map[string]interface{})
+	if definitions == nil {
+		definitions = make(map[string]interface{})
+	}
+	bundledNames := make(map[schemaVersionKey]string)
+	var queue []schemaVersionKey
+	// Definitions are named after schemas, versions are added only when names are taken
+	definitionName := func(key schemaVersionKey) string {
+		if name, bundled := bundledNames[key]; bundled {
+			return name
+		}
+		taken := func(name string) bool {
+			if _, exists := definitions[name]; exists {
+				return true
+			}
+			for _, bundledName := range bundledNames {
+				if bundledName == name {
+					return true
+				}
+			}
+			return false
+		}
+		name := key.name
+		for suffix := 1; taken(name); suffix++ {
+			name = fmt.Sprintf("%s_v%d", key.name, key.version)
+			if suffix > 1 {
+				name = fmt.Sprintf("%s_v%d_%d", key.name, key.version, suffix)
+			}
+		}
+		bundledNames[key] = name
+		queue = append(queue, key)
+		return name
+	}
+	// base is the location of the rewritten document in the bundle, local references are moved along
+	rewrite := func(document interface{}, base string) {
+		walkReferences(document, func(object map[string]interface{}, reference string) {
+			if key, isReference := parseSchemaReferenceURI(reference); isReference {
+				object["$ref"] = "#/definitions/" + escapeJSONPointer(definitionName(key)) + referenceFragment(reference)
+			} else if base != "" && (reference == "#" || strings.HasPrefix(reference, "#/")) {
+				object["$ref"] = "#" + base + strings.TrimPrefix(reference, "#")
+			}
+		})
+	}
+
+	rewrite(rootObject, "")
+	embedded := make(map[string]interface{})
+	for len(queue) > 0 {
+		key := queue[0]
+		queue = queue[1:]
+		source, err := loadReferencedJSONSchema(projectID, key, pendingVersions)
+		if err != nil {
+			return "", err
+		}
+		document, err := decodeJSONSchema(source)
+		if err != nil {
+			return "", fmt.Errorf("invalid JSON schema %q version %d: %v", key.name, key.version, err)
+		}
+		if object, isObject := document.(map[string]interface{}); isObject {
+			delete(object, "$schema")
+			delete(object, "$id")
+		}
+		name := bundledNames[key]
+		rewrite(document, "/definitions/"+escapeJSONPointer(name))
+		embedded[name] = document
+	}
+	for name, document := range embedded {
+		definitions[name] = document
+	}
+	rootObject["definitions"] = definitions
+
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(rootObject); err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
+
+// bundledForComparison bundles references of the JSON schema, so that versions referencing different
+// versions of other schemas are compared structurally. References which can't be resolved, e.g. to schemas
+// of an import which is still running, are compared as they are.
+func (schema *SchemaObject) bundledForComparison(content string) string {
+	bundled, err := bundleJSONSchema(schema.dbModel.ProjectID, content, nil)
+	if err != nil {
+		return content
+	}
+	return bundled
+}
+
+func escapeJSONPointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// recordSchemaReferences stores references of a new version of a JSON schema. References are checked
+// when content is validated, references which can't be resolved are skipped.
+func recordSchemaReferences(connection *gorm.DB, projectID uuid.UUID, schemaID uuid.UUID, version int, content string) error {
+	references, err := jsonSchemaReferences(content)
+	if err != nil || len(references) == 0 {
+		return nil
+	}
+	records := make([]db.SchemaReferencesDBModel, 0, len(references))
+	for _, reference := range references {
+		var referenced db.SchemasDBModel
+		result := connection.Where("project_id = ? AND name = ? AND type = ? AND status = ?",
+			projectID, reference.name, SchemaTypeJSONSchema, "active").First(&referenced)
+		if result.Error != nil {
+			continue
+		}
+		records = append(records, db.SchemaReferencesDBModel{
+			SchemaID:                schemaID,
+			SchemaVersion:           version,
+			ReferencedSchemaID:      referenced.ID,
+			ReferencedSchemaVersion: reference.version,
+		})
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	return connection.Create(&records).Error
+}
+
+// SchemaReferenceSerializerStruct is a reference from a version of a schema to a version of another schema
+type SchemaReferenceSerializerStruct struct {
+	SchemaID                string `json:"schema_id"`
+	SchemaName              string `json:"schema_name"`
+	SchemaVersion           int    `json:"schema_version"`
+	ReferencedSchemaID      string `json:"referenced_schema_id"`
+	ReferencedSchemaName    string `json:"referenced_schema_name"`
+	ReferencedSchemaVersion int    `json:"referenced_schema_version"`
+	URI                     string `json:"uri"`
+}
+
+type SchemaReferencesSerializerStruct struct {
+	SchemaID string `json:"schema_id"`
+	// References are versions of other schemas referenced by versions of this schema
+	References []SchemaReferenceSerializerStruct `json:"references"`
+	// ReferencedBy are versions of other schemas which reference versions of this schema
+	ReferencedBy []SchemaReferenceSerializerStruct `json:"referenced_by"`
+}
+
+// loadSchemaReferences loads references between active schemas matching the condition,
+// the condition refers to the references table as "r"
+func loadSchemaReferences(connection *gorm.DB, condition string, args ...interface{}) ([]SchemaReferenceSerializerStruct, error) {
+	var rows []struct {
+		SchemaID                uuid.UUID
+		SchemaName              string
+		SchemaVersion           int
+		ReferencedSchemaID      uuid.UUID
+		ReferencedSchemaName    string
+		ReferencedSchemaVersion int
+	}
+	err := connection.Table("schema_references AS r").
+		Select("r.schema_id, s.name AS schema_name, r.schema_version, "+
+			"r.referenced_schema_id, rs.name AS referenced_schema_name, r.referenced_schema_version").
+		Joins("JOIN schemas s ON s.id = r.schema_id AND s.status = 'active'").
+		Joins("JOIN schemas rs ON rs.id = r.referenced_schema_id").
+		Where("r.deleted_at IS NULL").
+		Where(condition, args...).
+		Order("s.name, r.schema_version, rs.name, r.referenced_schema_version").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	references := make([]SchemaReferenceSerializerStruct, 0, len(rows))
+	for _, row := range rows {
+		references = append(references, SchemaReferenceSerializerStruct{
+			SchemaID:                row.SchemaID.String(),
+			SchemaName:              row.SchemaName,
+			SchemaVersion:           row.SchemaVersion,
+			ReferencedSchemaID:      row.ReferencedSchemaID.String(),
+			ReferencedSchemaName:    row.ReferencedSchemaName,
+			ReferencedSchemaVersion: row.ReferencedSchemaVersion,
+			URI:                     SchemaReferenceURI(row.ReferencedSchemaName, row.ReferencedSchemaVersion),
+		})
+	}
+	return references, nil
+}
+
+// GetReferences lists schemas referenced by the schema and schemas which reference it.
+// References between versions of the same schema are not listed.
+func (schema *SchemaObject) GetReferences() (*SchemaReferencesSerializerStruct, error) {
+	connection := db.GetDB()
+	references, err := loadSchemaReferences(connection,
+		"r.schema_id = ? AND r.referenced_schema_id <> r.schema_id", schema.dbModel.ID)
+	if err != nil {
+		return nil, err
+	}
+	referencedBy, err := loadSchemaReferences(connection,
+		"r.referenced_schema_id = ? AND r.schema_id <> r.referenced_schema_id", schema.dbModel.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &SchemaReferencesSerializerStruct{
+		SchemaID:     schema.dbModel.ID.String(),
+		References:   references,
+		ReferencedBy: referencedBy,
+	}, nil
+}
+
+var ErrSchemaInUse = errors.New("the schema is in use")
+
+// SchemaUsageMessageStruct is a message which uses a version of a schema
+type SchemaUsageMessageStruct struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	SchemaVersion int    `json:"schema_version"`
+}
+
+// SchemaInUseError is returned when a schema can't be deleted because other schemas reference it
+// or messages use it
+type SchemaInUseError struct {
+	ReferencedBy []SchemaReferenceSerializerStruct
+	Messages     []SchemaUsageMessageStruct
+}
+
+func (err *SchemaInUseError) Error() string {
+	return fmt.Sprintf("%v: referenced by %d schema versions, used by %d messages",
+		ErrSchemaInUse, len(err.ReferencedBy), len(err.Messages))
+}
+
+func (err *SchemaInUseError) Unwrap() error {
+	return ErrSchemaInUse
+}
+
+type SchemaInUseErrorSerializerStruct struct {
+	Error        string                            `json:"error"`
+	ReferencedBy []SchemaReferenceSerializerStruct `json:"referenced_by"`
+	Messages     []SchemaUsageMessageStruct        `json:"messages"`
+}
+
+func (err *SchemaInUseError) Serialize() *SchemaInUseErrorSerializerStruct {
+	return &SchemaInUseErrorSerializerStruct{
+		Error:        "The schema is referenced by other schemas or used by messages",
+		ReferencedBy: err.ReferencedBy,
+		Messages:     err.Messages,
+	}
+}
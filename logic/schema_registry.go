@@ -0,0 +1,637 @@
+package logic
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fusioncatltd/fusioncat/db"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Schema types of the Confluent compatible schema registry API
+const (
+	RegistrySchemaTypeAvro     = "AVRO"
+	RegistrySchemaTypeJSON     = "JSON"
+	RegistrySchemaTypeProtobuf = "PROTOBUF"
+)
+
+var RegistrySchemaTypes = []string{RegistrySchemaTypeAvro, RegistrySchemaTypeJSON, RegistrySchemaTypeProtobuf}
+
+var registrySchemaTypes = map[string]string{
+	RegistrySchemaTypeAvro:     SchemaTypeAvro,
+	RegistrySchemaTypeJSON:     SchemaTypeJSONSchema,
+	RegistrySchemaTypeProtobuf: SchemaTypeProtobuf,
+}
+
+// Error codes of the Confluent schema registry API, the first three digits are the HTTP status
+const (
+	RegistryErrorSubjectNotFound       = 40401
+	RegistryErrorVersionNotFound       = 40402
+	RegistryErrorSchemaNotFound        = 40403
+	RegistryErrorIncompatibleSchema    = 409
+	RegistryErrorInvalidSchema         = 42201
+	RegistryErrorInvalidVersion        = 42202
+	RegistryErrorInvalidCompatibility  = 42203
+	RegistryErrorOperationNotPermitted = 42205
+)
+
+// RegistryError is an error of the schema registry API reported to clients with its error code
+type RegistryError struct {
+	ErrorCode int
+	Message   string
+}
+
+func (err *RegistryError) Error() string {
+	return err.Message
+}
+
+// StatusCode is the HTTP status of the error
+func (err *RegistryError) StatusCode() int {
+	code := err.ErrorCode
+	for code >= 1000 {
+		code /= 10
+	}
+	return code
+}
+
+type RegistryErrorSerializerStruct struct {
+	ErrorCode int    `json:"error_code"`
+	Message   string `json:"message"`
+}
+
+func (err *RegistryError) Serialize() *RegistryErrorSerializerStruct {
+	return &RegistryErrorSerializerStruct{ErrorCode: err.ErrorCode, Message: err.Message}
+}
+
+// RegistrySchemaSerializerStruct is a version of a subject, the schema type is omitted for Avro schemas
+type RegistrySchemaSerializerStruct struct {
+	Subject    string `json:"subject"`
+	ID         int64  `json:"id"`
+	Version    int    `json:"version"`
+	SchemaType string `json:"schemaType,omitempty"`
+	Schema     string `json:"schema"`
+}
+
+type RegistrySchemaByIDSerializerStruct struct {
+	SchemaType string `json:"schemaType,omitempty"`
+	Schema     string `json:"schema"`
+}
+
+type RegistrySubjectVersionSerializerStruct struct {
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+}
+
+type RegistryRegisteredSchemaSerializerStruct struct {
+	ID int64 `json:"id"`
+}
+
+type RegistryCompatibilitySerializerStruct struct {
+	IsCompatible bool     `json:"is_compatible"`
+	Messages     []string `json:"messages,omitempty"`
+}
+
+type RegistryConfigSerializerStruct struct {
+	CompatibilityLevel string `json:"compatibilityLevel"`
+}
+
+type RegistryConfigUpdateSerializerStruct struct {
+	Compatibility string `json:"compatibility"`
+}
+
+// SchemaRegistryObject exposes schemas of a project through a subset of the Confluent schema registry API,
+// so that Kafka serializers fetch and register schemas at runtime.
+//
+// Subjects of the registry are schemas and messages of the project. The subject of a schema has all
+// versions of the schema, the subject of a message has only the schema version of the message.
+// Subjects are mapped to names of schemas and messages by replacing characters other than letters,
+// digits and underscores with underscores. The "-key" and "-value" suffixes of the topic name strategy
+// are dropped when there is no schema or message with the full name, e.g. subject "orders-value" is
+// the "orders_value" schema or message, or the "orders" one. IDs of schemas are IDs of schema versions.
+type SchemaRegistryObject struct {
+	project *ProjectObject
+}
+
+// GetSchemaRegistry returns the schema registry of the project
+func (project *ProjectObject) GetSchemaRegistry() *SchemaRegistryObject {
+	return &SchemaRegistryObject{project: project}
+}
+
+// registrySubject is a schema, or a message and its schema
+type registrySubject struct {
+	name    string
+	schema  db.SchemasDBModel
+	message *db.MessagesDBModel
+}
+
+var nonIdentifierCharacters = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// registryNames lists names of schemas and messages the subject can refer to, in the order of preference
+func registryNames(subject string) []string {
+	name := nonIdentifierCharacters.ReplaceAllString(subject, "_")
+	names := []string{name}
+	for _, suffix := range []string{"_key", "_value"} {
+		if base := strings.TrimSuffix(name, suffix); base != name && base != "" {
+			names = append(names, base)
+		}
+	}
+	return names
+}
+
+func (registry *SchemaRegistryObject) findSubject(subject string) (*registrySubject, error) {
+	connection := db.GetDB()
+	projectID := registry.project.GetID()
+	for _, name := range registryNames(subject) {
+		var schema db.SchemasDBModel
+		err := connection.Where("project_id = ? AND name = ? AND status = ?", projectID, name, "active").
+			First(&schema).Error
+		if err == nil {
+			return &registrySubject{name: subject, schema: schema}, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+
+		var message db.MessagesDBModel
+		err = connection.Where("project_id = ? AND name = ? AND status = ?", projectID, name, "active").
+			First(&message).Error
+		if err == nil {
+			err = connection.Where("id = ? AND status = ?", message.SchemaID, "active").First(&schema).Error
+			if err != nil {
+				return nil, err
+			}
+			return &registrySubject{name: subject, schema: schema, message: &message}, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+	return nil, &RegistryError{ErrorCode: RegistryErrorSubjectNotFound, Message: fmt.Sprintf("Subject '%s' not found.", subject)}
+}
+
+// versions loads versions of the subject ordered by version
+func (subject *registrySubject) versions() ([]db.SchemaVersionsDBModel, error) {
+	query := db.GetDB().Where("schema_id = ?", subject.schema.ID)
+	if subject.message != nil {
+		query = query.Where("version = ?", subject.message.SchemaVersion)
+	}
+	var versions []db.SchemaVersionsDBModel
+	if err := query.Order("version asc").Find(&versions).Error; err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// latestVersion is the version of the message of the subject or the latest version of its schema
+func (subject *registrySubject) latestVersion() int {
+	if subject.message != nil {
+		return subject.message.SchemaVersion
+	}
+	return subject.schema.Version
+}
+
+// getVersion loads the version of the subject, "latest" and -1 mean the latest version
+func (subject *registrySubject) getVersion(version string) (*db.SchemaVersionsDBModel, error) {
+	number, err := strconv.Atoi(version)
+	if version == "latest" || (err == nil && number == -1) {
+		number, err = subject.latestVersion(), nil
+	}
+	if err != nil || number < 1 {
+		return nil, &RegistryError{ErrorCode: RegistryErrorInvalidVersion, Message: fmt.Sprintf(
+			"The specified version '%s' is not a valid version id. Allowed values are between [1, 2^31-1] and the string \"latest\"",
+			version)}
+	}
+
+	notFound := &RegistryError{ErrorCode: RegistryErrorVersionNotFound, Message: fmt.Sprintf("Version %d not found.", number)}
+	if subject.message != nil && number != subject.message.SchemaVersion {
+		return nil, notFound
+	}
+	var schemaVersion db.SchemaVersionsDBModel
+	err = db.GetDB().Where("schema_id = ? AND version = ?", subject.schema.ID, number).First(&schemaVersion).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, notFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &schemaVersion, nil
+}
+
+func (subject *registrySubject) serializeVersion(schemaVersion *db.SchemaVersionsDBModel) *RegistrySchemaSerializerStruct {
+	return &RegistrySchemaSerializerStruct{
+		Subject:    subject.name,
+		ID:         schemaVersion.RegistryID,
+		Version:    schemaVersion.Version,
+		SchemaType: registrySchemaTypeOf(subject.schema.Type),
+		Schema:     schemaVersion.Schema,
+	}
+}
+
+// findVersionWithContent finds a version of the subject with the same schema, registering
+// the same schema again returns the existing version
+func (subject *registrySubject) findVersionWithContent(content string) (*db.SchemaVersionsDBModel, error) {
+	versions, err := subject.versions()
+	if err != nil {
+		return nil, err
+	}
+	for index := len(versions) - 1; index >= 0; index-- {
+		if sameSchemaContent(subject.schema.Type, versions[index].Schema, content) {
+			return &versions[index], nil
+		}
+	}
+	return nil, nil
+}
+
+// sameSchemaContent compares JSON and Avro schemas ignoring formatting and order of keys
+func sameSchemaContent(schemaType string, first string, second string) bool {
+	if schemaType == SchemaTypeProtobuf {
+		return strings.TrimSpace(first) == strings.TrimSpace(second)
+	}
+	var firstValue, secondValue interface{}
+	if json.Unmarshal([]byte(first), &firstValue) != nil || json.Unmarshal([]byte(second), &secondValue) != nil {
+		return first == second
+	}
+	return reflect.DeepEqual(firstValue, secondValue)
+}
+
+// registrySchemaTypeOf returns the registry type of schemas of the type, empty for Avro schemas
+// which are the default type of the registry
+func registrySchemaTypeOf(schemaType string) string {
+	for registryType, fusioncatType := range registrySchemaTypes {
+		if fusioncatType == schemaType && registryType != RegistrySchemaTypeAvro {
+			return registryType
+		}
+	}
+	return ""
+}
+
+// parseRegistrySchemaType maps registry types to schema types, schemas without a type are Avro schemas
+func parseRegistrySchemaType(registryType string) (string, error) {
+	if registryType == "" {
+		return SchemaTypeAvro, nil
+	}
+	schemaType, known := registrySchemaTypes[registryType]
+	if !known {
+		return "", &RegistryError{ErrorCode: RegistryErrorInvalidSchema,
+			Message: fmt.Sprintf("Unknown schema type: %s, expected one of %s", registryType, strings.Join(RegistrySchemaTypes, ", "))}
+	}
+	return schemaType, nil
+}
+
+// checkSchemaType rejects schemas of another type than the schema of the subject
+func (subject *registrySubject) checkSchemaType(schemaType string) error {
+	if subject.schema.Type == schemaType {
+		return nil
+	}
+	return &RegistryError{ErrorCode: RegistryErrorIncompatibleSchema, Message: fmt.Sprintf(
+		"Schema being registered is incompatible with an earlier schema for subject '%s': the subject has %s schemas",
+		subject.name, subject.schema.Type)}
+}
+
+func invalidRegistrySchemaError(err error) *RegistryError {
+	return &RegistryError{ErrorCode: RegistryErrorInvalidSchema, Message: err.Error()}
+}
+
+// GetSubjects lists subjects of schemas and messages of the project
+func (registry *SchemaRegistryObject) GetSubjects() ([]string, error) {
+	connection := db.GetDB()
+	var schemaNames, messageNames []string
+	err := connection.Model(&db.SchemasDBModel{}).
+		Where("project_id = ? AND status = ?", registry.project.GetID(), "active").
+		Pluck("name", &schemaNames).Error
+	if err != nil {
+		return nil, err
+	}
+	err = connection.Table("messages AS m").
+		Joins("JOIN schemas s ON s.id = m.schema_id AND s.status = 'active'").
+		Where("m.project_id = ? AND m.status = ? AND m.deleted_at IS NULL", registry.project.GetID(), "active").
+		Pluck("m.name", &messageNames).Error
+	if err != nil {
+		return nil, err
+	}
+
+	subjects := make([]string, 0, len(schemaNames)+len(messageNames))
+	seen := make(map[string]bool)
+	for _, name := range append(schemaNames, messageNames...) {
+		if !seen[name] {
+			seen[name] = true
+			subjects = append(subjects, name)
+		}
+	}
+	sort.Strings(subjects)
+	return subjects, nil
+}
+
+// GetSubjectVersions lists versions of the subject
+func (registry *SchemaRegistryObject) GetSubjectVersions(subjectName string) ([]int, error) {
+	subject, err := registry.findSubject(subjectName)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := subject.versions()
+	if err != nil {
+		return nil, err
+	}
+	numbers := make([]int, 0, len(versions))
+	for _, version := range versions {
+		numbers = append(numbers, version.Version)
+	}
+	return numbers, nil
+}
+
+// GetSubjectVersion returns a version of the subject, the version is a number or "latest"
+func (registry *SchemaRegistryObject) GetSubjectVersion(subjectName string, version string) (*RegistrySchemaSerializerStruct, error) {
+	subject, err := registry.findSubject(subjectName)
+	if err != nil {
+		return nil, err
+	}
+	schemaVersion, err := subject.getVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	return subject.serializeVersion(schemaVersion), nil
+}
+
+// RegisterSchema registers the schema under the subject and returns its ID. Registering a schema
+// the subject already has returns the existing version. Unknown subjects become new schemas,
+// new versions of existing schemas must satisfy their compatibility modes. Subjects of messages
+// are read-only, their schemas get new versions through the subjects of the schemas.
+func (registry *SchemaRegistryObject) RegisterSchema(subjectName string, registryType string, content string,
+	userID uuid.UUID) (int64, error) {
+	schemaType, err := parseRegistrySchemaType(registryType)
+	if err != nil {
+		return 0, err
+	}
+
+	subject, err := registry.findSubject(subjectName)
+	var registryError *RegistryError
+	if errors.As(err, &registryError) && registryError.ErrorCode == RegistryErrorSubjectNotFound {
+		return registry.registerNewSubject(subjectName, schemaType, content, userID)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if err := subject.checkSchemaType(schemaType); err != nil {
+		return 0, err
+	}
+
+	existingVersion, err := subject.findVersionWithContent(content)
+	if err != nil {
+		return 0, err
+	}
+	if existingVersion != nil {
+		return existingVersion.RegistryID, nil
+	}
+	if subject.message != nil {
+		return 0, &RegistryError{ErrorCode: RegistryErrorOperationNotPermitted, Message: fmt.Sprintf(
+			"Subject '%s' is the message %s, register new versions under the subject of its schema %s",
+			subjectName, subject.message.Name, subject.schema.Name)}
+	}
+
+	if err := ValidateSchemaContent(subject.schema.ProjectID, subject.schema.Name, schemaType, content); err != nil {
+		return 0, invalidRegistrySchemaError(err)
+	}
+	schema := &SchemaObject{dbModel: subject.schema}
+	_, err = schema.CreateANewVersion(content, userID, false)
+	var compatibilityError *SchemaCompatibilityError
+	if errors.As(err, &compatibilityError) {
+		return 0, &RegistryError{ErrorCode: RegistryErrorIncompatibleSchema, Message: fmt.Sprintf(
+			"Schema being registered is incompatible with an earlier schema for subject '%s': %s",
+			subjectName, compatibilityError.Error())}
+	}
+	if err != nil {
+		return 0, err
+	}
+	return registry.registryIDOfVersion(schema.GetID(), schema.GetCurrentVersion())
+}
+
+// registerNewSubject creates a schema named after the subject
+func (registry *SchemaRegistryObject) registerNewSubject(subjectName string, schemaType string, content string,
+	userID uuid.UUID) (int64, error) {
+	name := registryNames(subjectName)[0]
+	if name == "" || len(name) > 45 {
+		return 0, &RegistryError{ErrorCode: RegistryErrorInvalidSchema,
+			Message: fmt.Sprintf("Subject '%s' can't be a schema name, names are up to 45 characters long", subjectName)}
+	}
+	if err := ValidateSchemaContent(registry.project.GetID(), name, schemaType, content); err != nil {
+		return 0, invalidRegistrySchemaError(err)
+	}
+
+	schemasManager := SchemaObjectsManager{}
+	schema, err := schemasManager.CreateANewSchema(name, "", content, schemaType, "",
+		"user", userID, userID, registry.project.GetID())
+	if err != nil {
+		return 0, err
+	}
+	return registry.registryIDOfVersion(schema.GetID(), schema.GetCurrentVersion())
+}
+
+func (registry *SchemaRegistryObject) registryIDOfVersion(schemaID uuid.UUID, version int) (int64, error) {
+	var schemaVersion db.SchemaVersionsDBModel
+	err := db.GetDB().Where("schema_id = ? AND version = ?", schemaID, version).First(&schemaVersion).Error
+	if err != nil {
+		return 0, err
+	}
+	return schemaVersion.RegistryID, nil
+}
+
+// LookupSchema finds the version of the subject with the schema
+func (registry *SchemaRegistryObject) LookupSchema(subjectName string, registryType string,
+	content string) (*RegistrySchemaSerializerStruct, error) {
+	schemaType, err := parseRegistrySchemaType(registryType)
+	if err != nil {
+		return nil, err
+	}
+	subject, err := registry.findSubject(subjectName)
+	if err != nil {
+		return nil, err
+	}
+
+	var schemaVersion *db.SchemaVersionsDBModel
+	if subject.schema.Type == schemaType {
+		if schemaVersion, err = subject.findVersionWithContent(content); err != nil {
+			return nil, err
+		}
+	}
+	if schemaVersion == nil {
+		return nil, &RegistryError{ErrorCode: RegistryErrorSchemaNotFound, Message: "Schema not found"}
+	}
+	return subject.serializeVersion(schemaVersion), nil
+}
+
+// getVersionByID loads the schema version with the registry ID and its schema, both belonging to the project
+func (registry *SchemaRegistryObject) getVersionByID(id int64) (*db.SchemaVersionsDBModel, *db.SchemasDBModel, error) {
+	notFound := &RegistryError{ErrorCode: RegistryErrorSchemaNotFound, Message: fmt.Sprintf("Schema %d not found", id)}
+	connection := db.GetDB()
+	var schemaVersion db.SchemaVersionsDBModel
+	err := connection.Where("registry_id = ?", id).First(&schemaVersion).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, notFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var schema db.SchemasDBModel
+	err = connection.Where("id = ? AND project_id = ? AND status = ?", schemaVersion.SchemaID, registry.project.GetID(), "active").
+		First(&schema).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, notFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return &schemaVersion, &schema, nil
+}
+
+// GetSchemaByID returns the schema with the ID
+func (registry *SchemaRegistryObject) GetSchemaByID(id int64) (*RegistrySchemaByIDSerializerStruct, error) {
+	schemaVersion, schema, err := registry.getVersionByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return &RegistrySchemaByIDSerializerStruct{
+		SchemaType: registrySchemaTypeOf(schema.Type),
+		Schema:     schemaVersion.Schema,
+	}, nil
+}
+
+// GetSubjectVersionsOfSchema lists subjects and versions which have the schema with the ID:
+// the subject of its schema and subjects of messages using the version
+func (registry *SchemaRegistryObject) GetSubjectVersionsOfSchema(id int64) ([]RegistrySubjectVersionSerializerStruct, error) {
+	schemaVersion, schema, err := registry.getVersionByID(id)
+	if err != nil {
+		return nil, err
+	}
+	var messageNames []string
+	err = db.GetDB().Model(&db.MessagesDBModel{}).
+		Where("schema_id = ? AND schema_version = ? AND status = ?", schema.ID, schemaVersion.Version, "active").
+		Order("name").Pluck("name", &messageNames).Error
+	if err != nil {
+		return nil, err
+	}
+
+	subjectVersions := []RegistrySubjectVersionSerializerStruct{{Subject: schema.Name, Version: schemaVersion.Version}}
+	for _, name := range messageNames {
+		subjectVersions = append(subjectVersions, RegistrySubjectVersionSerializerStruct{Subject: name, Version: schemaVersion.Version})
+	}
+	return subjectVersions, nil
+}
+
+// CheckCompatibility checks the schema against a version of the subject in the directions of
+// the compatibility mode of its schema. Empty version means the versions the mode checks new versions
+// against: the latest one, or all of them in transitive modes.
+func (registry *SchemaRegistryObject) CheckCompatibility(subjectName string, version string, registryType string,
+	content string) (*RegistryCompatibilitySerializerStruct, error) {
+	schemaType, err := parseRegistrySchemaType(registryType)
+	if err != nil {
+		return nil, err
+	}
+	subject, err := registry.findSubject(subjectName)
+	if err != nil {
+		return nil, err
+	}
+	if err := subject.checkSchemaType(schemaType); err != nil {
+		return &RegistryCompatibilitySerializerStruct{IsCompatible: false, Messages: []string{err.Error()}}, nil
+	}
+	if err := ValidateSchemaContent(subject.schema.ProjectID, subject.schema.Name, schemaType, content); err != nil {
+		return nil, invalidRegistrySchemaError(err)
+	}
+
+	schema := &SchemaObject{dbModel: subject.schema}
+	var violations []CompatibilityViolation
+	if version == "" && subject.message == nil {
+		violations, err = schema.CheckCompatibilityOfNewVersion(content)
+	} else {
+		if version == "" {
+			version = "latest"
+		}
+		schemaVersion, versionError := subject.getVersion(version)
+		if versionError != nil {
+			return nil, versionError
+		}
+		violations, err = schema.CheckCompatibilityWithVersion(content, schemaVersion.Version)
+	}
+	var contentError *SchemaContentError
+	if errors.As(err, &contentError) {
+		return nil, invalidRegistrySchemaError(err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	response := &RegistryCompatibilitySerializerStruct{IsCompatible: len(violations) == 0}
+	for _, violation := range violations {
+		response.Messages = append(response.Messages, fmt.Sprintf("%s incompatibility with version %d at %s: %s",
+			violation.Direction, violation.Version, violation.Path, violation.Message))
+	}
+	return response, nil
+}
+
+// parseRegistryCompatibility maps compatibility levels of the registry, e.g. "BACKWARD_TRANSITIVE",
+// to compatibility modes
+func parseRegistryCompatibility(level string) (string, error) {
+	mode := strings.ToLower(level)
+	if !IsValidCompatibilityMode(mode) {
+		return "", &RegistryError{ErrorCode: RegistryErrorInvalidCompatibility, Message: fmt.Sprintf(
+			"Invalid compatibility level: %s, expected one of %s", level, strings.ToUpper(strings.Join(CompatibilityModes, ", ")))}
+	}
+	return mode, nil
+}
+
+// GetConfig returns the compatibility level of new subjects, the schema compatibility of the project
+func (registry *SchemaRegistryObject) GetConfig() *RegistryConfigSerializerStruct {
+	return &RegistryConfigSerializerStruct{CompatibilityLevel: strings.ToUpper(registry.project.GetSchemaCompatibility())}
+}
+
+// UpdateConfig changes the compatibility level of new subjects
+func (registry *SchemaRegistryObject) UpdateConfig(level string) (*RegistryConfigUpdateSerializerStruct, error) {
+	mode, err := parseRegistryCompatibility(level)
+	if err != nil {
+		return nil, err
+	}
+	if err := registry.project.UpdateSchemaCompatibility(mode); err != nil {
+		return nil, err
+	}
+	return &RegistryConfigUpdateSerializerStruct{Compatibility: strings.ToUpper(mode)}, nil
+}
+
+// GetSubjectConfig returns the compatibility level of the schema of the subject
+func (registry *SchemaRegistryObject) GetSubjectConfig(subjectName string) (*RegistryConfigSerializerStruct, error) {
+	subject, err := registry.findSubject(subjectName)
+	if err != nil {
+		return nil, err
+	}
+	schema := &SchemaObject{dbModel: subject.schema}
+	return &RegistryConfigSerializerStruct{CompatibilityLevel: strings.ToUpper(schema.GetCompatibility())}, nil
+}
+
+// UpdateSubjectConfig changes the compatibility level of the schema of the subject
+func (registry *SchemaRegistryObject) UpdateSubjectConfig(subjectName string, level string) (*RegistryConfigUpdateSerializerStruct, error) {
+	mode, err := parseRegistryCompatibility(level)
+	if err != nil {
+		return nil, err
+	}
+	subject, err := registry.findSubject(subjectName)
+	if err != nil {
+		return nil, err
+	}
+	if subject.message != nil {
+		return nil, &RegistryError{ErrorCode: RegistryErrorOperationNotPermitted, Message: fmt.Sprintf(
+			"Subject '%s' is the message %s, change the compatibility level of the subject of its schema %s",
+			subjectName, subject.message.Name, subject.schema.Name)}
+	}
+
+	schema := &SchemaObject{dbModel: subject.schema}
+	if err := schema.UpdateCompatibility(mode); err != nil {
+		return nil, &RegistryError{ErrorCode: RegistryErrorInvalidCompatibility, Message: err.Error()}
+	}
+	return &RegistryConfigUpdateSerializerStruct{Compatibility: strings.ToUpper(mode)}, nil
+}
@@ -0,0 +1,98 @@
+package logic
+
+import (
+	"errors"
+
+	"github.com/fusioncatltd/fusioncat/db"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrRevertToCurrentVersion = errors.New("the version is the current version of the schema")
+
+// SchemaRevertSerializerStruct is the result of reverting a schema to a previous version. Messages which
+// were pinned to the reverted version are moved to the new one on request, messages of protobuf schemas
+// selecting a message type which the new version doesn't define stay where they are.
+type SchemaRevertSerializerStruct struct {
+	Schema          *SchemaDBSerializerStruct  `json:"schema"`
+	RevertedFrom    int                        `json:"reverted_from"`
+	RevertedTo      int                        `json:"reverted_to"`
+	MovedMessages   []SchemaUsageMessageStruct `json:"moved_messages"`
+	SkippedMessages []SchemaUsageMessageStruct `json:"skipped_messages"`
+}
+
+// RevertToVersion creates a new version of the schema with the content of a previous version, history
+// of versions stays append-only. The new version is checked like any other one: *SchemaCompatibilityError
+// is returned when it breaks the compatibility mode, unless ignoreCompatibility is set. moveMessages moves
+// messages pinned to the current version to the new one.
+func (schema *SchemaObject) RevertToVersion(schemaVersion *SchemaVersionObject, userID uuid.UUID,
+	ignoreCompatibility bool, moveMessages bool) (*SchemaRevertSerializerStruct, error) {
+	revertedFrom := schema.dbModel.Version
+	if schemaVersion.dbModel.Version == revertedFrom {
+		return nil, ErrRevertToCurrentVersion
+	}
+	content := schemaVersion.dbModel.Schema
+
+	result := &SchemaRevertSerializerStruct{
+		RevertedFrom:    revertedFrom,
+		RevertedTo:      schemaVersion.dbModel.Version,
+		MovedMessages:   make([]SchemaUsageMessageStruct, 0),
+		SkippedMessages: make([]SchemaUsageMessageStruct, 0),
+	}
+	original := schema.dbModel
+	err := db.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := schema.createANewVersion(tx, content, userID, ignoreCompatibility, ""); err != nil {
+			return err
+		}
+		if !moveMessages {
+			return nil
+		}
+
+		var messages []db.MessagesDBModel
+		err := tx.Where("schema_id = ? AND schema_version = ? AND status = ?", schema.dbModel.ID, revertedFrom, "active").
+			Order("name").Find(&messages).Error
+		if err != nil {
+			return err
+		}
+		var movedIDs []uuid.UUID
+		for _, message := range messages {
+			if message.SchemaMessageType != "" && !schema.definesMessageType(content, message.SchemaMessageType) {
+				result.SkippedMessages = append(result.SkippedMessages, SchemaUsageMessageStruct{
+					ID:            message.ID.String(),
+					Name:          message.Name,
+					SchemaVersion: message.SchemaVersion,
+				})
+				continue
+			}
+			movedIDs = append(movedIDs, message.ID)
+			result.MovedMessages = append(result.MovedMessages, SchemaUsageMessageStruct{
+				ID:            message.ID.String(),
+				Name:          message.Name,
+				SchemaVersion: schema.dbModel.Version,
+			})
+		}
+		if len(movedIDs) == 0 {
+			return nil
+		}
+		return tx.Model(&db.MessagesDBModel{}).Where("id IN ?", movedIDs).
+			Update("schema_version", schema.dbModel.Version).Error
+	})
+	if err != nil {
+		schema.dbModel = original
+		return nil, err
+	}
+
+	schema.catalogFieldsOfLatestVersion()
+	result.Schema = schema.Serialize()
+	return result, nil
+}
+
+// definesMessageType tells if the content of a protobuf schema defines the message type
+func (schema *SchemaObject) definesMessageType(content string, messageType string) bool {
+	file, err := compileProtobufSchema(schema.dbModel.ProjectID, schema.dbModel.Name, content, nil)
+	if err != nil {
+		return false
+	}
+	_, err = findProtobufMessage(file, messageType)
+	return err == nil
+}
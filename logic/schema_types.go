@@ -0,0 +1,349 @@
+package logic
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/fusioncatltd/fusioncat/avro"
+	"github.com/fusioncatltd/fusioncat/common"
+	"github.com/fusioncatltd/fusioncat/db"
+	"github.com/fusioncatltd/fusioncat/jsonschemacodegen"
+	"github.com/fusioncatltd/fusioncat/protobuf"
+	"github.com/google/uuid"
+)
+
+// Types of schemas. JSON schemas are compiled with santhosh-tekuri/jsonschema, Avro schemas
+// are parsed by the avro package, Protocol Buffers sources by the protobuf package. All are stored
+// as text in the same tables and versioned the same way.
+const (
+	SchemaTypeJSONSchema = "jsonschema"
+	SchemaTypeAvro       = "avro"
+	SchemaTypeProtobuf   = "protobuf"
+)
+
+// SupportedSchemaTypes lists schema types in the order they are shown to users
+var SupportedSchemaTypes = []string{SchemaTypeJSONSchema, SchemaTypeAvro, SchemaTypeProtobuf}
+
+// IsSupportedSchemaType checks if schemas of the type can be created
+func IsSupportedSchemaType(schemaType string) bool {
+	for _, supportedType := range SupportedSchemaTypes {
+		if schemaType == supportedType {
+			return true
+		}
+	}
+	return false
+}
+
+// SchemaContentError is returned when the content of a schema is not valid for its type
+type SchemaContentError struct {
+	SchemaType string
+	Message    string
+}
+
+func (err *SchemaContentError) Error() string {
+	switch err.SchemaType {
+	case SchemaTypeJSONSchema:
+		return "invalid JSON schema: " + err.Message
+	case SchemaTypeAvro:
+		return "invalid Avro schema: " + err.Message
+	case SchemaTypeProtobuf:
+		return "invalid Protocol Buffers schema: " + err.Message
+	}
+	return err.Message
+}
+
+// ValidateSchemaContent checks that the content is a valid schema of the type.
+// JSON schemas must declare their dialect in the "$schema" field, they can reference other JSON schemas
+// of the project, see SchemaReferenceURI. Protocol Buffers schemas can import other protobuf schemas
+// of the project, see ProtobufImportPath.
+func ValidateSchemaContent(projectID uuid.UUID, schemaName string, schemaType string, content string) error {
+	switch schemaType {
+	case SchemaTypeJSONSchema:
+		_, exists, err := common.ExtractSchemaField(content)
+		if err != nil {
+			return &SchemaContentError{SchemaType: schemaType, Message: err.Error()}
+		}
+		if !exists {
+			return &SchemaContentError{SchemaType: schemaType, Message: "\"$schema\" field is required"}
+		}
+		if _, err := compileJSONSchema(projectID, content, nil); err != nil {
+			return err
+		}
+	case SchemaTypeAvro:
+		if _, err := avro.Parse(content); err != nil {
+			return &SchemaContentError{SchemaType: schemaType, Message: err.Error()}
+		}
+	case SchemaTypeProtobuf:
+		if _, err := compileProtobufSchema(projectID, schemaName, content, nil); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported schema type: %s", schemaType)
+	}
+	return nil
+}
+
+// jsonSchemaCompilationMessage strips the generic prefix from errors of the JSON schema compiler
+func jsonSchemaCompilationMessage(err error) string {
+	message := err.Error()
+	if index := strings.Index(message, "compilation failed:"); index != -1 {
+		return strings.TrimSpace(message[index+len("compilation failed:"):])
+	}
+	return message
+}
+
+// ValidatePayload checks that the payload is valid data of this schema version. JSON payloads
+// are checked against JSON schemas, Avro data is expected in the Avro JSON encoding, protobuf
+// messages in the proto3 JSON mapping. The message type selects a message of protobuf schemas.
+func (schemaVersion *SchemaVersionObject) ValidatePayload(schemaType string, messageType string, payload []byte) error {
+	validator, err := schemaVersion.payloadValidator(schemaType, messageType)
+	if err != nil {
+		return err
+	}
+	return validator(payload)
+}
+
+// generateAvroCode generates types for an Avro schema. Only Go is supported, types are generated
+// without external tools.
+func generateAvroCode(ctx context.Context, content string, language string, structName string) (string, error) {
+	if language != "go" {
+		return "", fmt.Errorf("code generation for avro schemas is supported only for go")
+	}
+	parsed, err := avro.Parse(content)
+	if err != nil {
+		return "", &SchemaContentError{SchemaType: SchemaTypeAvro, Message: err.Error()}
+	}
+	key := generatedCodeKey{
+		schemaType:       SchemaTypeAvro,
+		content:          content,
+		structName:       structName,
+		language:         language,
+		generatorVersion: nativeCodeGeneratorVersion,
+	}
+	return generateCachedCode(ctx, key, func(context.Context) (string, error) {
+		return avro.GenerateGo(parsed, structName)
+	})
+}
+
+// JSONSchemaCodegenBackendQuicktype selects quicktype for generating Go code of JSON schemas,
+// set in JSON_SCHEMA_CODEGEN_BACKEND
+const JSONSchemaCodegenBackendQuicktype = "quicktype"
+
+// generateJSONSchemaCode generates types for a JSON schema. References to other schemas of the project
+// are bundled first, so that generators get a self-contained schema. Go types are generated without
+// external tools unless JSON_SCHEMA_CODEGEN_BACKEND is set to quicktype, other languages require quicktype.
+func generateJSONSchemaCode(ctx context.Context, projectID uuid.UUID, content string, language string,
+	structName string) (string, error) {
+	bundled, err := bundleJSONSchema(projectID, content, nil)
+	if err != nil {
+		return "", err
+	}
+	key := generatedCodeKey{
+		schemaType: SchemaTypeJSONSchema,
+		content:    bundled,
+		structName: structName,
+		language:   language,
+	}
+	if language == "go" && os.Getenv("JSON_SCHEMA_CODEGEN_BACKEND") != JSONSchemaCodegenBackendQuicktype {
+		key.generatorVersion = nativeCodeGeneratorVersion
+		return generateCachedCode(ctx, key, func(context.Context) (string, error) {
+			return jsonschemacodegen.GenerateGo([]byte(bundled), structName)
+		})
+	}
+
+	quicktypeCmd, ok := quicktypeCommand()
+	if !ok {
+		return "", fmt.Errorf("code generation for JSON schemas in %s requires quicktype, "+
+			"set JSON_SCHEMA_CONVERTOR_CMD to the full path to quicktype", language)
+	}
+	key.generatorVersion = quicktypeGeneratorVersion(quicktypeCmd)
+	return generateCachedCode(ctx, key, func(ctx context.Context) (string, error) {
+		return generateQuicktypeCode(ctx, quicktypeCmd, bundled, language, structName)
+	})
+}
+
+// quicktypeCommand returns the path to quicktype from JSON_SCHEMA_CONVERTOR_CMD, if it is set and exists
+func quicktypeCommand() (string, bool) {
+	quicktypeCmd := os.Getenv("JSON_SCHEMA_CONVERTOR_CMD")
+	if quicktypeCmd == "" {
+		return "", false
+	}
+	if _, err := exec.LookPath(quicktypeCmd); err != nil {
+		return "", false
+	}
+	return quicktypeCmd, true
+}
+
+// generateQuicktypeCode generates types for a self-contained JSON schema with quicktype,
+// quicktype is killed when the context is done
+func generateQuicktypeCode(ctx context.Context, quicktypeCmd string, content string, language string,
+	structName string) (string, error) {
+	// Use --top-level for all languages to ensure proper naming
+	cmd := exec.CommandContext(ctx, quicktypeCmd,
+		"--lang", language,
+		"--just-types",
+		"-s", "schema",
+		"--top-level", structName)
+	cmd.Env = append(os.Environ(), "NODE_NO_WARNINGS=1")
+	cmd.Stdin = strings.NewReader(content)
+
+	var output bytes.Buffer
+	var stderrBuf bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &stderrBuf
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("code generation failed: %v - stderr: %s", err, stderrBuf.String())
+	}
+
+	generatedCode := output.String()
+
+	// For Go, ensure the struct name is properly capitalized
+	// This handles cases where quicktype might not capitalize properly
+	if language == "go" && len(generatedCode) > 0 {
+		generatedCode = strings.ReplaceAll(generatedCode, "type "+strings.ToLower(structName)+" ", "type "+structName+" ")
+		generatedCode = strings.ReplaceAll(generatedCode, "type "+strings.ToLower(structName)+" struct", "type "+structName+" struct")
+	}
+	return generatedCode, nil
+}
+
+// generateProtobufCode generates types for a message of a protobuf schema and the types it references.
+// Only Go is supported, types are generated without external tools.
+func generateProtobufCode(ctx context.Context, projectID uuid.UUID, schemaName string, content string, language string,
+	structName string, messageType string) (string, error) {
+	if language != "go" {
+		return "", fmt.Errorf("code generation for protobuf schemas is supported only for go")
+	}
+	importedSources := make(map[string]string)
+	file, err := compileProtobufSchemaRecordingImports(projectID, schemaName, content, nil, importedSources)
+	if err != nil {
+		return "", err
+	}
+	if _, err := findProtobufMessage(file, messageType); err != nil {
+		return "", err
+	}
+
+	// Generated types depend on the imported schemas too
+	importedNames := make([]string, 0, len(importedSources))
+	for importedName := range importedSources {
+		importedNames = append(importedNames, importedName)
+	}
+	sort.Strings(importedNames)
+	selfContained := content
+	for _, importedName := range importedNames {
+		selfContained += "\x00" + importedName + "\x00" + importedSources[importedName]
+	}
+	key := generatedCodeKey{
+		schemaType:       SchemaTypeProtobuf,
+		content:          selfContained,
+		structName:       structName,
+		messageType:      messageType,
+		language:         language,
+		generatorVersion: nativeCodeGeneratorVersion,
+	}
+	return generateCachedCode(ctx, key, func(context.Context) (string, error) {
+		return protobuf.GenerateGo(file, messageType, structName)
+	})
+}
+
+// ProtobufImportPath is the path by which other protobuf schemas import the schema:
+// import "orders.proto" refers to the protobuf schema named orders in the same project.
+// Directories in import paths are ignored.
+func ProtobufImportPath(schemaName string) string {
+	return schemaName + ".proto"
+}
+
+// protobufSchemaNameFromImport returns the name of the schema imported by the path
+func protobufSchemaNameFromImport(importPath string) string {
+	return strings.TrimSuffix(path.Base(importPath), ".proto")
+}
+
+// compileProtobufSchema compiles a protobuf schema resolving its imports among protobuf schemas
+// of the project. Sources in pendingSchemas (by schema name) take precedence over stored schemas,
+// they are used to validate schemas which are not saved yet, e.g. during imports.
+func compileProtobufSchema(projectID uuid.UUID, schemaName string, content string,
+	pendingSchemas map[string]string) (*protobuf.File, error) {
+	return compileProtobufSchemaRecordingImports(projectID, schemaName, content, pendingSchemas, nil)
+}
+
+// compileProtobufSchemaRecordingImports compiles a protobuf schema like compileProtobufSchema and records
+// sources of imported schemas of the project in importedSources, by schema name
+func compileProtobufSchemaRecordingImports(projectID uuid.UUID, schemaName string, content string,
+	pendingSchemas map[string]string, importedSources map[string]string) (*protobuf.File, error) {
+	importer := func(importPath string) (string, error) {
+		importedName := protobufSchemaNameFromImport(importPath)
+		if source, exists := pendingSchemas[importedName]; exists {
+			if importedSources != nil {
+				importedSources[importedName] = source
+			}
+			return source, nil
+		}
+		var imported db.SchemasDBModel
+		result := db.GetDB().Where("project_id = ? AND name = ? AND type = ? AND status = ?",
+			projectID, importedName, SchemaTypeProtobuf, "active").First(&imported)
+		if result.Error != nil {
+			return "", fmt.Errorf("there is no protobuf schema named %q in the project", importedName)
+		}
+		if importedSources != nil {
+			importedSources[importedName] = imported.Schema
+		}
+		return imported.Schema, nil
+	}
+
+	file, err := protobuf.Compile(ProtobufImportPath(schemaName), content, importer)
+	if err != nil {
+		return nil, &SchemaContentError{SchemaType: SchemaTypeProtobuf, Message: err.Error()}
+	}
+	return file, nil
+}
+
+// findProtobufMessage finds the message type in the compiled schema, an empty message type
+// selects the first message of the schema
+func findProtobufMessage(file *protobuf.File, messageType string) (*protobuf.Message, error) {
+	if messageType == "" {
+		if message := file.DefaultMessage(); message != nil {
+			return message, nil
+		}
+		return nil, fmt.Errorf("the schema doesn't define any messages")
+	}
+	if message := file.FindMessage(messageType); message != nil {
+		return message, nil
+	}
+	return nil, fmt.Errorf("message type %q is not defined in the schema, defined types: %s",
+		messageType, strings.Join(file.MessageTypes(), ", "))
+}
+
+// ResolveMessageType checks the message type selected by a message which uses the version of the schema
+// and returns its full name. Protobuf schemas default to their first message, message types can't be
+// selected in schemas of other types.
+func (schema *SchemaObject) ResolveMessageType(schemaVersion *SchemaVersionObject, messageType string) (string, error) {
+	if schema.dbModel.Type != SchemaTypeProtobuf {
+		if messageType != "" {
+			return "", fmt.Errorf("message types can be selected only in protobuf schemas")
+		}
+		return "", nil
+	}
+	file, err := compileProtobufSchema(schema.dbModel.ProjectID, schema.dbModel.Name, schemaVersion.dbModel.Schema, nil)
+	if err != nil {
+		return "", err
+	}
+	message, err := findProtobufMessage(file, messageType)
+	if err != nil {
+		return "", err
+	}
+	return message.Name, nil
+}
+
+// getSchemaRecord loads the schema of the version
+func (schemaVersion *SchemaVersionObject) getSchemaRecord() (*db.SchemasDBModel, error) {
+	var schema db.SchemasDBModel
+	if err := db.GetDB().Where("id = ?", schemaVersion.dbModel.SchemaID).First(&schema).Error; err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
@@ -1,14 +1,14 @@
 package logic
 
 import (
-	"bytes"
+	"context"
 	"fmt"
-	"os"
-	"os/exec"
 	"strings"
 
 	"github.com/fusioncatltd/fusioncat/db"
+	"github.com/fusioncatltd/fusioncat/jsonschemalint"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // SchemaObject represents a schema in the system.
@@ -24,9 +24,27 @@ type SchemaVersionObject struct {
 
 // GenerateCode generates code from this schema version in the specified language
 // Returns the generated code and the name of the generated structure/class
-// Requires JSON_SCHEMA_CONVERTOR_CMD environment variable to be set with the full path to quicktype
-func (schemaVersion *SchemaVersionObject) GenerateCode(language string, schemaType string, schemaName string) (generatedCode string, structName string, err error) {
-	if schemaType != "jsonschema" {
+// Go types of JSON schemas are generated natively, other languages require quicktype, see JSON_SCHEMA_CONVERTOR_CMD
+// The message type selects a message of protobuf schemas and is ignored for other types
+// Go types of deprecated and retired schemas and schema versions get a "// Deprecated:" comment
+// Generation stops with the context or after CODE_GENERATION_TIMEOUT_SECONDS, generated code is cached
+func (schemaVersion *SchemaVersionObject) GenerateCode(ctx context.Context, language string, schemaType string, schemaName string, messageType string) (generatedCode string, structName string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, codeGenerationTimeout())
+	defer cancel()
+	generatedCode, structName, err = schemaVersion.generateCode(ctx, language, schemaType, schemaName, messageType)
+	if err != nil || language != "go" {
+		return generatedCode, structName, err
+	}
+	schemaRecord, err := schemaVersion.getSchemaRecord()
+	if err != nil {
+		return "", "", err
+	}
+	notice := schemaDeprecationNotice(schemaRecord, &schemaVersion.dbModel)
+	return addGoDeprecationComment(generatedCode, structName, notice), structName, nil
+}
+
+func (schemaVersion *SchemaVersionObject) generateCode(ctx context.Context, language string, schemaType string, schemaName string, messageType string) (generatedCode string, structName string, err error) {
+	if !IsSupportedSchemaType(schemaType) {
 		return "", "", fmt.Errorf("unsupported schema type: %s", schemaType)
 	}
 
@@ -35,80 +53,77 @@ func (schemaVersion *SchemaVersionObject) GenerateCode(language string, schemaTy
 	if len(structName) > 0 && structName[0] >= 'a' && structName[0] <= 'z' {
 		structName = strings.ToUpper(structName[:1]) + structName[1:]
 	}
-	
-	// Add version suffix to the struct name
-	structName = fmt.Sprintf("%sVersion%dFusioncatGeneratedSchema", structName, schemaVersion.dbModel.Version)
 
-	// Get quicktype command from environment (required)
-	quicktypeCmd := os.Getenv("JSON_SCHEMA_CONVERTOR_CMD")
-	if quicktypeCmd == "" {
-		return "", "", fmt.Errorf("JSON_SCHEMA_CONVERTOR_CMD environment variable is not set")
+	// Messages can use different message types of the same protobuf schema, each type gets its own struct
+	if schemaType == SchemaTypeProtobuf && messageType != "" {
+		structName += protobufMessageTypeStructName(messageType)
 	}
 
-	// Create the quicktype command with appropriate options
-	cmd := exec.Command(quicktypeCmd,
-		"--lang", language,
-		"--just-types",
-		"-s", "schema",
-		"--top-level", structName)
-	
-	cmd.Env = append(os.Environ(), "NODE_NO_WARNINGS=1")
-	
-	// Pipe the schema JSON to quicktype
-	cmd.Stdin = strings.NewReader(schemaVersion.dbModel.Schema)
-	
-	// Capture the output
-	var output bytes.Buffer
-	var stderrBuf bytes.Buffer
-	cmd.Stdout = &output
-	cmd.Stderr = &stderrBuf
+	// Add version suffix to the struct name
+	structName = fmt.Sprintf("%sVersion%dFusioncatGeneratedSchema", structName, schemaVersion.dbModel.Version)
 
-	// Run the command
-	if err := cmd.Run(); err != nil {
-		return "", "", fmt.Errorf("code generation failed: %v - stderr: %s", err, stderrBuf.String())
+	if schemaType == SchemaTypeAvro {
+		generatedCode, err = generateAvroCode(ctx, schemaVersion.dbModel.Schema, language, structName)
+		return generatedCode, structName, err
 	}
 
-	generatedCode = output.String()
-	
-	// For Go, ensure the struct name is properly capitalized (extra safety check)
-	if language == "go" && len(generatedCode) > 0 {
-		// Replace the generated struct name with our capitalized version if needed
-		generatedCode = strings.ReplaceAll(generatedCode, "type "+strings.ToLower(structName)+" ", "type "+structName+" ")
-		generatedCode = strings.ReplaceAll(generatedCode, "type "+strings.ToLower(structName)+" struct", "type "+structName+" struct")
+	if schemaType == SchemaTypeProtobuf {
+		schemaRecord, err := schemaVersion.getSchemaRecord()
+		if err != nil {
+			return "", "", err
+		}
+		generatedCode, err = generateProtobufCode(ctx, schemaRecord.ProjectID, schemaName, schemaVersion.dbModel.Schema,
+			language, structName, messageType)
+		return generatedCode, structName, err
 	}
 
-	return generatedCode, structName, nil
+	schemaRecord, err := schemaVersion.getSchemaRecord()
+	if err != nil {
+		return "", "", err
+	}
+	generatedCode, err = generateJSONSchemaCode(ctx, schemaRecord.ProjectID, schemaVersion.dbModel.Schema, language, structName)
+	return generatedCode, structName, err
 }
 
 type SchemaDBSerializerStruct struct {
-	ID            string `json:"id"`
-	Name          string `json:"name"`
-	Status        string `json:"status"`
-	Type          string `json:"type"`
-	Description   string `json:"description"`
-	CreatedByType string `json:"created_by_type"`
-	CreatedByID   string `json:"created_by_id"`
-	ProjectID     string `json:"project_id"`
-	CreatedByName string `json:"created_by_name"`
-	Schema        string `json:"schema"`
-	Version       int    `json:"version"`
+	ID            string                     `json:"id"`
+	Name          string                     `json:"name"`
+	Status        string                     `json:"status"`
+	Type          string                     `json:"type"`
+	Description   string                     `json:"description"`
+	CreatedByType string                     `json:"created_by_type"`
+	CreatedByID   string                     `json:"created_by_id"`
+	ProjectID     string                     `json:"project_id"`
+	CreatedByName string                     `json:"created_by_name"`
+	Schema        string                     `json:"schema"`
+	Version       int                        `json:"version"`
+	Compatibility string                     `json:"compatibility"`
+	Lifecycle     *LifecycleSerializerStruct `json:"lifecycle"`
+	// Lint findings are returned when schemas are created or modified
+	Lint []jsonschemalint.Finding `json:"lint,omitempty"`
+	// Version of the schema this schema was converted from, see SchemaObject.ConvertVersion
+	ConvertedFrom *SchemaConversionSourceSerializerStruct `json:"converted_from,omitempty"`
 }
 
 type SchemaEditShortDBSerializerStruct struct {
-	CreatedAt     string `json:"created_at"`
-	SchemaID      string `json:"schema_id"`
-	UserID        string `json:"user_id"`
-	Version       int    `json:"version"`
-	CreatedByName string `json:"created_by_name"`
+	CreatedAt     string                     `json:"created_at"`
+	SchemaID      string                     `json:"schema_id"`
+	UserID        string                     `json:"user_id"`
+	Version       int                        `json:"version"`
+	CreatedByName string                     `json:"created_by_name"`
+	CommitSHA     string                     `json:"commit_sha,omitempty"`
+	Lifecycle     *LifecycleSerializerStruct `json:"lifecycle"`
 }
 
 type SchemaEditDBSerializerStruct struct {
-	CreatedAt     string `json:"created_at"`
-	SchemaID      string `json:"schema_id"`
-	UserID        string `json:"user_id"`
-	Version       int    `json:"version"`
-	CreatedByName string `json:"created_by_name"`
-	Schema        string `json:"schema"`
+	CreatedAt     string                     `json:"created_at"`
+	SchemaID      string                     `json:"schema_id"`
+	UserID        string                     `json:"user_id"`
+	Version       int                        `json:"version"`
+	CreatedByName string                     `json:"created_by_name"`
+	Schema        string                     `json:"schema"`
+	CommitSHA     string                     `json:"commit_sha,omitempty"`
+	Lifecycle     *LifecycleSerializerStruct `json:"lifecycle"`
 }
 
 func (schemaEditObject *SchemaVersionObject) SerializeShort() *SchemaEditShortDBSerializerStruct {
@@ -122,6 +137,8 @@ func (schemaEditObject *SchemaVersionObject) SerializeShort() *SchemaEditShortDB
 		UserID:        schemaEditObject.dbModel.UserID.String(),
 		Version:       schemaEditObject.dbModel.Version,
 		CreatedByName: createdByName,
+		CommitSHA:     schemaEditObject.dbModel.CommitSHA,
+		Lifecycle:     schemaEditObject.GetLifecycle(),
 	}
 }
 
@@ -137,6 +154,8 @@ func (schemaEditObject *SchemaVersionObject) SerializeLong() *SchemaEditDBSerial
 		Version:       schemaEditObject.dbModel.Version,
 		Schema:        schemaEditObject.dbModel.Schema,
 		CreatedByName: createdByName,
+		CommitSHA:     schemaEditObject.dbModel.CommitSHA,
+		Lifecycle:     schemaEditObject.GetLifecycle(),
 	}
 }
 
@@ -159,6 +178,9 @@ func (schema *SchemaObject) Serialize() *SchemaDBSerializerStruct {
 		Version:       schema.dbModel.Version,
 		Type:          schema.dbModel.Type,
 		ProjectID:     schema.dbModel.ProjectID.String(),
+		Compatibility: schema.GetCompatibility(),
+		Lifecycle:     schema.GetLifecycle(),
+		ConvertedFrom: schema.getConversionSource(),
 	}
 }
 
@@ -228,32 +250,48 @@ func (schemaManager *SchemaObjectsManager) CanNameBeUsed(name string, projectID
 
 // CreateANewSchema creates a new schema in the database.
 // Creation of schema also creates a new schema version.
+// Empty compatibility means the default compatibility mode of the project, or no compatibility checks
+// of new versions when schemas of the type don't support the default mode.
 func (schemaManager *SchemaObjectsManager) CreateANewSchema(name string,
 	description string,
 	schema string,
 	schemaType string,
+	compatibility string,
 	createdByType string,
 	createdById uuid.UUID,
 	userID uuid.UUID,
 	projectID uuid.UUID,
 ) (*SchemaObject, error) {
-
-	connection := db.GetDB()
-	tx := connection.Begin()
-
-	// Create a new schema
-	newSchema := db.SchemasDBModel{
+	return schemaManager.createSchema(db.SchemasDBModel{
 		Name:          strings.TrimSpace(name),
 		Description:   description,
 		Schema:        schema,
 		Type:          schemaType,
 		Version:       1,
+		Compatibility: compatibility,
 		Status:        "active",
 		CreatedByType: createdByType,
 		CreatedByID:   createdById,
 		ProjectID:     projectID,
+	}, userID)
+}
+
+// createSchema creates the schema with its first version, schemas without a compatibility mode
+// get the default mode of the project
+func (schemaManager *SchemaObjectsManager) createSchema(newSchema db.SchemasDBModel, userID uuid.UUID) (*SchemaObject, error) {
+	schemaType, projectID, schema := newSchema.Type, newSchema.ProjectID, newSchema.Schema
+	if newSchema.Compatibility == "" {
+		var project db.ProjectsDBModel
+		if err := db.GetDB().First(&project, "id = ?", projectID).Error; err != nil {
+			return nil, err
+		}
+		newSchema.Compatibility = defaultSchemaCompatibility(&project, schemaType)
 	}
 
+	connection := db.GetDB()
+	tx := connection.Begin()
+
+	// Create a new schema
 	if err := tx.Create(&newSchema).Error; err != nil {
 		tx.Rollback()
 		return nil, err
@@ -272,9 +310,27 @@ func (schemaManager *SchemaObjectsManager) CreateANewSchema(name string,
 		return nil, err
 	}
 
+	if schemaType == SchemaTypeJSONSchema {
+		if err := recordSchemaReferences(tx, projectID, newSchema.ID, newSchema.Version, schema); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
 	tx.Commit()
 
-	return &SchemaObject{dbModel: newSchema}, nil
+	created := &SchemaObject{dbModel: newSchema}
+	created.catalogFieldsOfLatestVersion()
+	return created, nil
+}
+
+// defaultSchemaCompatibility returns the default compatibility mode of the project for new schemas of the type,
+// schemas of types which don't support the mode aren't checked
+func defaultSchemaCompatibility(project *db.ProjectsDBModel, schemaType string) string {
+	if CheckCompatibilityModeOfType(schemaType, project.SchemaCompatibility) == nil {
+		return project.SchemaCompatibility
+	}
+	return CompatibilityNone
 }
 
 // GetByID retrieves a schema by its ID
@@ -297,35 +353,93 @@ func (schemaManager *SchemaObjectsManager) GetSpecificVersionOfSchema(schemaID u
 	return &SchemaVersionObject{dbModel: schemaVersion}, nil
 }
 
-// CreateANewVersion creates a new version of an existing schema
-func (schema *SchemaObject) CreateANewVersion(newSchemaContent string, userID uuid.UUID) (*SchemaObject, error) {
-	connection := db.GetDB()
-	tx := connection.Begin()
+// CreateANewVersion creates a new version of an existing schema.
+// The new version must be compatible with previous versions according to the compatibility mode of the schema,
+// otherwise *SchemaCompatibilityError listing the violations is returned. ignoreCompatibility skips the check.
+func (schema *SchemaObject) CreateANewVersion(newSchemaContent string, userID uuid.UUID, ignoreCompatibility bool) (*SchemaObject, error) {
+	err := db.GetDB().Transaction(func(tx *gorm.DB) error {
+		return schema.createANewVersion(tx, newSchemaContent, userID, ignoreCompatibility, "")
+	})
+	if err != nil {
+		return nil, err
+	}
+	schema.catalogFieldsOfLatestVersion()
+	return schema, nil
+}
+
+// createANewVersion creates a new version of the schema in the transaction, imports use it to add versions
+// together with other entities. The commit SHA is recorded on versions read from git repositories.
+func (schema *SchemaObject) createANewVersion(tx *gorm.DB, newSchemaContent string, userID uuid.UUID,
+	ignoreCompatibility bool, commitSHA string) error {
+	if !ignoreCompatibility {
+		violations, err := schema.CheckCompatibilityOfNewVersion(newSchemaContent)
+		if err != nil {
+			return err
+		}
+		if len(violations) > 0 {
+			return &SchemaCompatibilityError{Mode: schema.GetCompatibility(), Violations: violations}
+		}
+	}
 
 	// Update the schema version
-	schema.dbModel.Version++
-	schema.dbModel.Schema = newSchemaContent
-	
-	if err := tx.Save(&schema.dbModel).Error; err != nil {
-		tx.Rollback()
-		return nil, err
+	updated := schema.dbModel
+	updated.Version++
+	updated.Schema = newSchemaContent
+	if err := tx.Save(&updated).Error; err != nil {
+		return err
 	}
 
 	// Create a new schema version record
 	newSchemaVersion := &db.SchemaVersionsDBModel{
-		SchemaID: schema.dbModel.ID,
-		UserID:   userID,
-		Version:  schema.dbModel.Version,
-		Schema:   newSchemaContent,
+		SchemaID:  updated.ID,
+		UserID:    userID,
+		Version:   updated.Version,
+		Schema:    newSchemaContent,
+		CommitSHA: commitSHA,
 	}
-	
 	if err := tx.Create(&newSchemaVersion).Error; err != nil {
-		tx.Rollback()
-		return nil, err
+		return err
 	}
 
-	tx.Commit()
-	return schema, nil
+	if updated.Type == SchemaTypeJSONSchema {
+		if err := recordSchemaReferences(tx, updated.ProjectID, updated.ID, updated.Version, newSchemaContent); err != nil {
+			return err
+		}
+	}
+
+	schema.dbModel = updated
+	return nil
+}
+
+// Delete removes the schema from the project, its name can be reused afterwards. Schemas referenced
+// by other schemas or used by messages can't be deleted, *SchemaInUseError lists what uses them.
+func (schema *SchemaObject) Delete() error {
+	return db.GetDB().Transaction(func(tx *gorm.DB) error {
+		referencedBy, err := loadSchemaReferences(tx,
+			"r.referenced_schema_id = ? AND r.schema_id <> r.referenced_schema_id", schema.dbModel.ID)
+		if err != nil {
+			return err
+		}
+		var messages []db.MessagesDBModel
+		err = tx.Where("schema_id = ? AND status = ?", schema.dbModel.ID, "active").Order("name").Find(&messages).Error
+		if err != nil {
+			return err
+		}
+		if len(referencedBy) > 0 || len(messages) > 0 {
+			inUseError := &SchemaInUseError{ReferencedBy: referencedBy, Messages: make([]SchemaUsageMessageStruct, 0)}
+			for _, message := range messages {
+				inUseError.Messages = append(inUseError.Messages, SchemaUsageMessageStruct{
+					ID:            message.ID.String(),
+					Name:          message.Name,
+					SchemaVersion: message.SchemaVersion,
+				})
+			}
+			return inUseError
+		}
+
+		schema.dbModel.Status = "deleted"
+		return tx.Model(&db.SchemasDBModel{}).Where("id = ?", schema.dbModel.ID).Update("status", "deleted").Error
+	})
 }
 
 // SchemaWithVersionExists checks if a schema with the specific ID and version exists and is active
@@ -358,6 +472,20 @@ func capitalizeFirstLetter(s string) string {
 	return strings.ToUpper(s[:1]) + s[1:]
 }
 
+// protobufMessageTypeStructName converts a full protobuf message name to CamelCase without the package,
+// e.g. orders.v1.OrderCreated.Item becomes OrderCreatedItem
+func protobufMessageTypeStructName(messageType string) string {
+	result := ""
+	for _, part := range strings.Split(strings.TrimPrefix(messageType, "."), ".") {
+		// Package names are lowercase, message names start with a capital letter
+		if result == "" && len(part) > 0 && part[0] >= 'a' && part[0] <= 'z' {
+			continue
+		}
+		result += toCamelCase(part)
+	}
+	return result
+}
+
 // toCamelCase converts snake_case to CamelCase
 func toCamelCase(s string) string {
 	parts := strings.Split(s, "_")
@@ -370,9 +498,28 @@ func toCamelCase(s string) string {
 
 // GenerateCode generates code from the schema in the specified language
 // Returns the generated code and the name of the generated structure/class
-// Requires JSON_SCHEMA_CONVERTOR_CMD environment variable to be set with the full path to quicktype
-func (schema *SchemaObject) GenerateCode(language string) (generatedCode string, structName string, err error) {
-	if schema.dbModel.Type != "jsonschema" {
+// Go types of JSON schemas are generated natively, other languages require quicktype, see JSON_SCHEMA_CONVERTOR_CMD
+// The message type selects a message of protobuf schemas, the first message is used when it is empty
+// Go types of deprecated and retired schemas and schema versions get a "// Deprecated:" comment
+// Generation stops with the context or after CODE_GENERATION_TIMEOUT_SECONDS, generated code is cached
+func (schema *SchemaObject) GenerateCode(ctx context.Context, language string, messageType string) (generatedCode string, structName string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, codeGenerationTimeout())
+	defer cancel()
+	generatedCode, structName, err = schema.generateCode(ctx, language, messageType)
+	if err != nil || language != "go" {
+		return generatedCode, structName, err
+	}
+	var currentVersion *db.SchemaVersionsDBModel
+	schemaManager := SchemaObjectsManager{}
+	if schemaVersion, err := schemaManager.GetSpecificVersionOfSchema(schema.dbModel.ID, schema.dbModel.Version); err == nil {
+		currentVersion = &schemaVersion.dbModel
+	}
+	notice := schemaDeprecationNotice(&schema.dbModel, currentVersion)
+	return addGoDeprecationComment(generatedCode, structName, notice), structName, nil
+}
+
+func (schema *SchemaObject) generateCode(ctx context.Context, language string, messageType string) (generatedCode string, structName string, err error) {
+	if !IsSupportedSchemaType(schema.dbModel.Type) {
 		return "", "", fmt.Errorf("unsupported schema type: %s", schema.dbModel.Type)
 	}
 
@@ -382,45 +529,17 @@ func (schema *SchemaObject) GenerateCode(language string) (generatedCode string,
 		structName = strings.ToUpper(structName[:1]) + structName[1:]
 	}
 
-	// Get quicktype command from environment (required)
-	quicktypeCmd := os.Getenv("JSON_SCHEMA_CONVERTOR_CMD")
-	if quicktypeCmd == "" {
-		return "", "", fmt.Errorf("JSON_SCHEMA_CONVERTOR_CMD environment variable is not set")
-	}
-
-	// Create the quicktype command with appropriate options
-	// Use --top-level for all languages to ensure proper naming
-	cmd := exec.Command(quicktypeCmd,
-		"--lang", language,
-		"--just-types",
-		"-s", "schema",
-		"--top-level", structName)
-	
-	cmd.Env = append(os.Environ(), "NODE_NO_WARNINGS=1")
-	
-	// Pipe the schema JSON to quicktype
-	cmd.Stdin = strings.NewReader(schema.dbModel.Schema)
-	
-	// Capture the output
-	var output bytes.Buffer
-	var stderrBuf bytes.Buffer
-	cmd.Stdout = &output
-	cmd.Stderr = &stderrBuf
-
-	// Run the command
-	if err := cmd.Run(); err != nil {
-		return "", "", fmt.Errorf("code generation failed: %v - stderr: %s", err, stderrBuf.String())
+	if schema.dbModel.Type == SchemaTypeAvro {
+		generatedCode, err = generateAvroCode(ctx, schema.dbModel.Schema, language, structName)
+		return generatedCode, structName, err
 	}
 
-	generatedCode = output.String()
-	
-	// For Go, ensure the struct name is properly capitalized (extra safety check)
-	if language == "go" && len(generatedCode) > 0 {
-		// Replace the generated struct name with our capitalized version if needed
-		// This handles cases where quicktype might not capitalize properly
-		generatedCode = strings.ReplaceAll(generatedCode, "type "+strings.ToLower(structName)+" ", "type "+structName+" ")
-		generatedCode = strings.ReplaceAll(generatedCode, "type "+strings.ToLower(structName)+" struct", "type "+structName+" struct")
+	if schema.dbModel.Type == SchemaTypeProtobuf {
+		generatedCode, err = generateProtobufCode(ctx, schema.dbModel.ProjectID, schema.dbModel.Name, schema.dbModel.Schema,
+			language, structName, messageType)
+		return generatedCode, structName, err
 	}
 
-	return generatedCode, structName, nil
+	generatedCode, err = generateJSONSchemaCode(ctx, schema.dbModel.ProjectID, schema.dbModel.Schema, language, structName)
+	return generatedCode, structName, err
 }
@@ -1,11 +1,13 @@
 package main
 
 import (
+	"context"
 	"github.com/fusioncatltd/fusioncat/api/input_contracts"
 	"github.com/fusioncatltd/fusioncat/api/protected_endpoints"
 	"github.com/fusioncatltd/fusioncat/api/public_endpoints"
 	"github.com/fusioncatltd/fusioncat/common"
 	_ "github.com/fusioncatltd/fusioncat/docs"
+	"github.com/fusioncatltd/fusioncat/logic"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
@@ -83,10 +85,15 @@ func main() {
 	protected_endpoints.AuthenticationProtectedRoutesV1(V1ProtectedRoutesGroup)
 	protected_endpoints.MeProtectedRoutesV1(V1ProtectedRoutesGroup)
 	protected_endpoints.ProjectsProtectedRoutesV1(V1ProtectedRoutesGroup)
+	protected_endpoints.ImportsProtectedRoutesV1(V1ProtectedRoutesGroup)
 	protected_endpoints.SchemasProtectedRoutesV1(V1ProtectedRoutesGroup)
+	protected_endpoints.SchemaLintProtectedRoutesV1(V1ProtectedRoutesGroup)
+	protected_endpoints.SchemaRegistryProtectedRoutesV1(V1ProtectedRoutesGroup)
 	protected_endpoints.MessagesProtectedRoutesV1(V1ProtectedRoutesGroup)
 	protected_endpoints.AppsProtectedRoutesV1(V1ProtectedRoutesGroup)
 	protected_endpoints.ServersProtectedRoutesV1(V1ProtectedRoutesGroup)
+	protected_endpoints.FusionlangProtectedRoutesV1(V1ProtectedRoutesGroup)
+	protected_endpoints.GitSyncProtectedRoutesV1(V1ProtectedRoutesGroup)
 
 	// Set up Swagger documentation
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(ff.Handler))
@@ -108,7 +115,6 @@ func main() {
 
 		// Add new custom validators here
 		validators := map[string]validator.Func{
-			"valid_json_schema":                      input_contracts.ValidJSONSchemaValidator,
 			"alphanum_with_underscore":               input_contracts.ValidateAlphanumWithUnderscore,
 			"alphanum_with_underscore_and_dots":      input_contracts.ValidateAlphanumWithUnderscoreAndDots,
 			"valid_existing_schema_id_and_version":   input_contracts.ValidExistingSchemaIDAndVersionValidator,
@@ -123,6 +129,17 @@ func main() {
 		}
 	}
 
+	// Import jobs run inside the server process, jobs interrupted by the previous run can't finish
+	importJobsManager := logic.ProjectImportJobsObjectsManager{}
+	if interrupted, err := importJobsManager.FailInterruptedImports(); err != nil {
+		log.Errorf("Failed to mark interrupted import jobs as failed: %v", err)
+	} else if interrupted > 0 {
+		log.Warningf("Marked %d import jobs interrupted by the previous run as failed", interrupted)
+	}
+
+	// Projects following git repositories are synchronized in the background
+	logic.StartGitSyncPolling(context.Background())
+
 	// Launching server
 	serverAddressPort := os.Getenv("SERVER_ADDRESS_AND_PORT")
 	serverMode := os.Getenv("SERVER_MODE")
@@ -0,0 +1,214 @@
+package protobuf
+
+import (
+	"fmt"
+	"go/format"
+	"strings"
+)
+
+// GenerateGo generates Go types for a message of the file and types it references. The message gets
+// typeName, other messages and enums get typeName as a prefix, so that types of several schemas can be
+// placed into the same package. An empty messageType selects the default message of the file.
+// Struct fields are tagged with JSON names of the proto3 JSON mapping, message fields and optional
+// scalars are pointers like in protoc-gen-go, members of a oneof are pointers of which only one is set.
+func GenerateGo(file *File, messageType string, typeName string) (string, error) {
+	root := file.DefaultMessage()
+	if messageType != "" {
+		root = file.FindMessage(messageType)
+	}
+	if root == nil {
+		if messageType != "" {
+			return "", fmt.Errorf("message type %q is not defined in %s", messageType, file.Path)
+		}
+		return "", fmt.Errorf("%s doesn't define any messages", file.Path)
+	}
+
+	generator := &goGenerator{
+		root:      root,
+		rootName:  typeName,
+		typeNames: map[interface{}]string{root: typeName},
+		usedNames: map[string]bool{typeName: true},
+	}
+	generator.declareMessage(root, typeName)
+	// Referenced types are declared in the order they are referenced
+	for index := 0; index < len(generator.pending); index++ {
+		generator.output.WriteString("\n")
+		switch declaration := generator.pending[index].(type) {
+		case *Message:
+			generator.declareMessage(declaration, generator.typeNames[declaration])
+		case *Enum:
+			generator.declareEnum(declaration, generator.typeNames[declaration])
+		}
+	}
+
+	formatted, err := format.Source([]byte(generator.output.String()))
+	if err != nil {
+		return "", fmt.Errorf("failed to format generated code: %v", err)
+	}
+	return string(formatted), nil
+}
+
+type goGenerator struct {
+	root      *Message
+	rootName  string
+	typeNames map[interface{}]string
+	usedNames map[string]bool
+	pending   []interface{}
+	output    strings.Builder
+}
+
+// namedType returns the Go name of a message or enum and schedules its declaration. Types nested
+// into the generated message are named relative to it, other types relative to their package.
+func (generator *goGenerator) namedType(declaration interface{}, fullName string, file *File) string {
+	if name, exists := generator.typeNames[declaration]; exists {
+		return name
+	}
+	relative := relativeName(fullName, file)
+	if strings.HasPrefix(fullName, generator.root.Name+".") {
+		relative = strings.TrimPrefix(fullName, generator.root.Name+".")
+	}
+	var baseName strings.Builder
+	baseName.WriteString(generator.rootName)
+	for _, part := range strings.Split(relative, ".") {
+		baseName.WriteString(exportedGoName(part))
+	}
+
+	name := baseName.String()
+	for suffix := 2; generator.usedNames[name]; suffix++ {
+		name = fmt.Sprintf("%s%d", baseName.String(), suffix)
+	}
+	generator.usedNames[name] = true
+	generator.typeNames[declaration] = name
+	generator.pending = append(generator.pending, declaration)
+	return name
+}
+
+// goType returns the Go type of a value of the field, without the repetition
+func (generator *goGenerator) goType(field *Field) string {
+	switch {
+	case field.Message != nil:
+		return "*" + generator.namedType(field.Message, field.Message.Name, field.Message.File)
+	case field.Enum != nil:
+		return generator.namedType(field.Enum, field.Enum.Name, field.Enum.File)
+	}
+	return scalarGoType(field.Type)
+}
+
+func (generator *goGenerator) fieldType(field *Field, message *Message) string {
+	valueType := generator.goType(field)
+	switch {
+	case field.IsMap():
+		return fmt.Sprintf("map[%s]%s", scalarGoType(field.KeyType), valueType)
+	case field.Repeated:
+		return "[]" + valueType
+	case strings.HasPrefix(valueType, "*") || valueType == "[]byte":
+		return valueType
+	case field.Optional || field.Oneof != "" || message.File.Syntax == "proto2":
+		return "*" + valueType
+	}
+	return valueType
+}
+
+func (generator *goGenerator) declareMessage(message *Message, name string) {
+	generator.writeDoc(message.Doc, "")
+	fmt.Fprintf(&generator.output, "type %s struct {\n", name)
+	usedFieldNames := make(map[string]bool)
+	for _, field := range message.Fields {
+		fieldName := exportedGoName(field.Name)
+		for suffix := 2; usedFieldNames[fieldName]; suffix++ {
+			fieldName = fmt.Sprintf("%s%d", exportedGoName(field.Name), suffix)
+		}
+		usedFieldNames[fieldName] = true
+
+		generator.writeDoc(field.Doc, "\t")
+		if field.Deprecated {
+			generator.output.WriteString("\t// Deprecated: the field is marked as deprecated in the schema.\n")
+		}
+		fmt.Fprintf(&generator.output, "\t%s %s `json:\"%s,omitempty\"`", fieldName,
+			generator.fieldType(field, message), JSONName(field.Name))
+		if field.Oneof != "" {
+			fmt.Fprintf(&generator.output, " // oneof %s", field.Oneof)
+		}
+		generator.output.WriteString("\n")
+	}
+	generator.output.WriteString("}\n")
+}
+
+// declareEnum declares an enum as int32 with constants named like in protoc-gen-go: Type_VALUE
+func (generator *goGenerator) declareEnum(enum *Enum, name string) {
+	generator.writeDoc(enum.Doc, "")
+	fmt.Fprintf(&generator.output, "type %s int32\n\nconst (\n", name)
+	for _, value := range enum.Values {
+		generator.writeDoc(value.Doc, "\t")
+		fmt.Fprintf(&generator.output, "\t%s_%s %s = %d\n", name, value.Name, name, value.Number)
+	}
+	generator.output.WriteString(")\n")
+}
+
+func (generator *goGenerator) writeDoc(doc string, indent string) {
+	doc = strings.TrimSpace(doc)
+	if doc == "" {
+		return
+	}
+	for _, line := range strings.Split(doc, "\n") {
+		fmt.Fprintf(&generator.output, "%s// %s\n", indent, strings.TrimSpace(line))
+	}
+}
+
+func scalarGoType(protoType string) string {
+	switch protoType {
+	case "double":
+		return "float64"
+	case "float":
+		return "float32"
+	case "int32", "sint32", "sfixed32":
+		return "int32"
+	case "int64", "sint64", "sfixed64":
+		return "int64"
+	case "uint32", "fixed32":
+		return "uint32"
+	case "uint64", "fixed64":
+		return "uint64"
+	case "bool":
+		return "bool"
+	case "string":
+		return "string"
+	case "bytes":
+		return "[]byte"
+	}
+	return "interface{}"
+}
+
+// JSONName is the name of a field in the proto3 JSON mapping: the field name in lowerCamelCase
+func JSONName(fieldName string) string {
+	var builder strings.Builder
+	upperNext := false
+	for _, char := range fieldName {
+		switch {
+		case char == '_':
+			upperNext = true
+		case upperNext && char >= 'a' && char <= 'z':
+			builder.WriteRune(char - 'a' + 'A')
+			upperNext = false
+		default:
+			builder.WriteRune(char)
+			upperNext = false
+		}
+	}
+	return builder.String()
+}
+
+// exportedGoName converts a protobuf name (snake_case or CamelCase) to an exported Go identifier
+func exportedGoName(name string) string {
+	var builder strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		if part == "" {
+			continue
+		}
+		builder.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	if builder.Len() == 0 {
+		return "X"
+	}
+	return builder.String()
+}
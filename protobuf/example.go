@@ -0,0 +1,175 @@
+package protobuf
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fusioncatltd/fusioncat/fakedata"
+)
+
+// maxExampleDepth limits nesting of examples of recursive messages, deeper message fields are left unset
+const maxExampleDepth = 8
+
+// Example generates an example of the message in the proto3 JSON mapping, the same mapping ValidateJSON expects.
+// Fields are named in lowerCamelCase and only one field of every oneof is set.
+func (message *Message) Example(data *fakedata.Generator) interface{} {
+	return messageExample(message, data, 0)
+}
+
+func messageExample(message *Message, data *fakedata.Generator, depth int) interface{} {
+	if special, isSpecial := wellKnownExamples[message.Name]; isSpecial {
+		return special(data)
+	}
+
+	// A random field of every oneof is set
+	chosenOneofFields := make(map[string]*Field)
+	oneofFields := make(map[string][]*Field)
+	var oneofs []string
+	for _, field := range message.Fields {
+		if field.Oneof != "" {
+			if _, seen := oneofFields[field.Oneof]; !seen {
+				oneofs = append(oneofs, field.Oneof)
+			}
+			oneofFields[field.Oneof] = append(oneofFields[field.Oneof], field)
+		}
+	}
+	for _, oneof := range oneofs {
+		chosenOneofFields[oneof] = oneofFields[oneof][data.Intn(len(oneofFields[oneof]))]
+	}
+
+	values := make(map[string]interface{}, len(message.Fields))
+	for _, field := range message.Fields {
+		if field.Oneof != "" && chosenOneofFields[field.Oneof] != field {
+			continue
+		}
+		if field.Message != nil && depth >= maxExampleDepth {
+			continue
+		}
+		values[JSONName(field.Name)] = fieldExample(field, data, depth)
+	}
+	return values
+}
+
+func fieldExample(field *Field, data *fakedata.Generator, depth int) interface{} {
+	count := 1 + data.Intn(3)
+	switch {
+	case field.IsMap():
+		entries := make(map[string]interface{}, count)
+		for len(entries) < count {
+			key := mapKeyExample(field.KeyType, data)
+			entries[key] = singularExample(field, "", data, depth)
+			if field.KeyType == "bool" && len(entries) == 2 {
+				break
+			}
+		}
+		return entries
+	case field.Repeated:
+		items := make([]interface{}, 0, count)
+		for len(items) < count {
+			items = append(items, singularExample(field, fakedata.Singular(field.Name), data, depth))
+		}
+		return items
+	}
+	return singularExample(field, field.Name, data, depth)
+}
+
+func singularExample(field *Field, name string, data *fakedata.Generator, depth int) interface{} {
+	switch {
+	case field.Message != nil:
+		return messageExample(field.Message, data, depth+1)
+	case field.Enum != nil:
+		return enumExample(field.Enum, data)
+	}
+	return scalarExample(field.Type, name, data)
+}
+
+// enumExample prefers values other than the zero one, which usually means that the value is unspecified
+func enumExample(enum *Enum, data *fakedata.Generator) string {
+	var candidates []*EnumValue
+	for _, value := range enum.Values {
+		if value.Number != 0 {
+			candidates = append(candidates, value)
+		}
+	}
+	if len(candidates) == 0 {
+		candidates = enum.Values
+	}
+	return candidates[data.Intn(len(candidates))].Name
+}
+
+func scalarExample(protoType string, name string, data *fakedata.Generator) interface{} {
+	switch protoType {
+	case "bool":
+		return data.Bool()
+	case "string":
+		if value, known := data.ForName(name); known {
+			return value
+		}
+		return data.Word()
+	case "bytes":
+		return base64.StdEncoding.EncodeToString([]byte(data.Word()))
+	case "double", "float":
+		return data.FloatBetween(0, 1000)
+	case "int64", "sint64", "sfixed64", "uint64", "fixed64":
+		// 64-bit integers are strings in the canonical JSON form
+		return strconv.FormatInt(data.IntBetween(1, 100000), 10)
+	}
+	return data.IntBetween(1, 1000)
+}
+
+func mapKeyExample(keyType string, data *fakedata.Generator) string {
+	switch keyType {
+	case "string":
+		return data.Word()
+	case "bool":
+		return strconv.FormatBool(data.Bool())
+	}
+	return strconv.FormatInt(data.IntBetween(1, 1000), 10)
+}
+
+// wellKnownExamples generate well-known types which have special JSON representations
+var wellKnownExamples = map[string]func(data *fakedata.Generator) interface{}{
+	"google.protobuf.Timestamp": func(data *fakedata.Generator) interface{} {
+		return data.Time().Format(time.RFC3339)
+	},
+	"google.protobuf.Duration": func(data *fakedata.Generator) interface{} {
+		return fmt.Sprintf("%d.%ds", data.IntBetween(0, 3600), data.Intn(10))
+	},
+	"google.protobuf.FieldMask": func(data *fakedata.Generator) interface{} {
+		return strings.Join([]string{data.Word(), data.Word()}, ",")
+	},
+	"google.protobuf.Any": func(data *fakedata.Generator) interface{} {
+		return map[string]interface{}{"@type": "type.googleapis.com/google.protobuf.Empty"}
+	},
+	"google.protobuf.Struct": func(data *fakedata.Generator) interface{} {
+		return map[string]interface{}{data.Word(): data.Word()}
+	},
+	"google.protobuf.ListValue": func(data *fakedata.Generator) interface{} {
+		return []interface{}{data.Word(), data.IntBetween(1, 1000)}
+	},
+	"google.protobuf.Value": func(data *fakedata.Generator) interface{} {
+		return data.Word()
+	},
+	"google.protobuf.Empty": func(data *fakedata.Generator) interface{} {
+		return map[string]interface{}{}
+	},
+	"google.protobuf.DoubleValue": wrapperExample("double"),
+	"google.protobuf.FloatValue":  wrapperExample("float"),
+	"google.protobuf.Int64Value":  wrapperExample("int64"),
+	"google.protobuf.UInt64Value": wrapperExample("uint64"),
+	"google.protobuf.Int32Value":  wrapperExample("int32"),
+	"google.protobuf.UInt32Value": wrapperExample("uint32"),
+	"google.protobuf.BoolValue":   wrapperExample("bool"),
+	"google.protobuf.StringValue": wrapperExample("string"),
+	"google.protobuf.BytesValue":  wrapperExample("bytes"),
+}
+
+// wrapperExample generates wrappers of scalars which are represented by the wrapped value
+func wrapperExample(protoType string) func(data *fakedata.Generator) interface{} {
+	return func(data *fakedata.Generator) interface{} {
+		return scalarExample(protoType, "", data)
+	}
+}
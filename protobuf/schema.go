@@ -0,0 +1,524 @@
+// Package protobuf validates Protocol Buffers sources (.proto files) and generates Go types for their
+// messages. Sources are parsed with github.com/emicklei/proto, this package resolves imports and
+// references to message and enum types which the parser leaves unchecked.
+//
+//	syntax = "proto3";
+//	package orders.v1;
+//
+//	import "google/protobuf/timestamp.proto";
+//	import "common.proto";
+//
+//	message OrderCreated {
+//	  string id = 1;
+//	  common.v1.Money total = 2;
+//	  google.protobuf.Timestamp created_at = 3;
+//	}
+package protobuf
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/scanner"
+
+	"github.com/emicklei/proto"
+)
+
+// Importer returns the source of a file imported by the path used in the import statement
+type Importer func(path string) (string, error)
+
+// File is a parsed .proto file with resolved imports and type references
+type File struct {
+	Path     string
+	Syntax   string
+	Package  string
+	Messages []*Message
+	Enums    []*Enum
+	Imports  []*File
+
+	publicImports []*File
+}
+
+// Message is a message type, Name is the full name including the package and enclosing messages
+type Message struct {
+	Name     string
+	Doc      string
+	Fields   []*Field
+	Messages []*Message
+	Enums    []*Enum
+	File     *File
+}
+
+// Field is a field of a message. Fields of message and enum types point at the resolved type.
+type Field struct {
+	Name       string
+	Number     int
+	Doc        string
+	Type       string
+	KeyType    string // map fields
+	Repeated   bool
+	Optional   bool
+	Oneof      string
+	Deprecated bool
+	Message    *Message
+	Enum       *Enum
+}
+
+// Enum is an enum type, Name is the full name including the package and enclosing messages
+type Enum struct {
+	Name   string
+	Doc    string
+	Values []*EnumValue
+	File   *File
+}
+
+type EnumValue struct {
+	Name   string
+	Number int
+	Doc    string
+}
+
+// IsMap tells if the field is a map field
+func (field *Field) IsMap() bool {
+	return field.KeyType != ""
+}
+
+// relativeName is the name of a message or enum without the package of the file defining it
+func relativeName(fullName string, file *File) string {
+	if file.Package == "" {
+		return fullName
+	}
+	return strings.TrimPrefix(fullName, file.Package+".")
+}
+
+// MessageTypes returns full names of all messages defined in the file, including nested messages
+func (file *File) MessageTypes() []string {
+	var names []string
+	var collect func(messages []*Message)
+	collect = func(messages []*Message) {
+		for _, message := range messages {
+			names = append(names, message.Name)
+			collect(message.Messages)
+		}
+	}
+	collect(file.Messages)
+	return names
+}
+
+// FindMessage finds a message defined in the file by its full name or by the name relative to the package
+func (file *File) FindMessage(name string) *Message {
+	name = strings.TrimPrefix(name, ".")
+	var find func(messages []*Message) *Message
+	find = func(messages []*Message) *Message {
+		for _, message := range messages {
+			if message.Name == name || relativeName(message.Name, file) == name {
+				return message
+			}
+			if nested := find(message.Messages); nested != nil {
+				return nested
+			}
+		}
+		return nil
+	}
+	return find(file.Messages)
+}
+
+// DefaultMessage is the message type used when a specific type is not selected:
+// the first top-level message of the file
+func (file *File) DefaultMessage() *Message {
+	if len(file.Messages) == 0 {
+		return nil
+	}
+	return file.Messages[0]
+}
+
+// Error is an error in a .proto file
+type Error struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (err *Error) Error() string {
+	if err.Line == 0 {
+		return fmt.Sprintf("%s: %s", err.File, err.Message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", err.File, err.Line, err.Column, err.Message)
+}
+
+// Compile parses the source of the file at the path and all files it imports. Imports of the well-known
+// types (google/protobuf/*.proto) are resolved without the importer.
+func Compile(path string, source string, importer Importer) (*File, error) {
+	compiler := &compiler{
+		importer: importer,
+		files:    make(map[string]*File),
+		loading:  make(map[string]bool),
+	}
+	return compiler.compile(path, source)
+}
+
+type compiler struct {
+	importer Importer
+	files    map[string]*File
+	loading  map[string]bool
+}
+
+// fileCompilation holds the state of compilation of a single file
+type fileCompilation struct {
+	file    *File
+	symbols map[string]interface{} // full name -> *Message or *Enum, including visible imported types
+	fields  []pendingField
+	errors  []error
+}
+
+// pendingField is a field which type is resolved after all types of the file are known
+type pendingField struct {
+	field    *Field
+	scope    string
+	position scanner.Position
+}
+
+func (compiler *compiler) compile(path string, source string) (*File, error) {
+	parser := proto.NewParser(strings.NewReader(source))
+	parser.Filename(path)
+	definition, err := parser.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	compiler.loading[path] = true
+	defer delete(compiler.loading, path)
+
+	compilation := &fileCompilation{
+		file:    &File{Path: path, Syntax: "proto2"},
+		symbols: make(map[string]interface{}),
+	}
+	file := compilation.file
+
+	// Syntax, package and imports come first, declared types depend on them
+	for _, element := range definition.Elements {
+		switch element := element.(type) {
+		case *proto.Syntax:
+			file.Syntax = element.Value
+		case *proto.Edition:
+			file.Syntax = "editions"
+		case *proto.Package:
+			file.Package = element.Name
+		case *proto.Import:
+			imported, err := compiler.importFile(element)
+			if err != nil {
+				return nil, err
+			}
+			file.Imports = append(file.Imports, imported)
+			if element.Kind == "public" {
+				file.publicImports = append(file.publicImports, imported)
+			}
+		}
+	}
+
+	for _, element := range definition.Elements {
+		switch element := element.(type) {
+		case *proto.Message:
+			if !element.IsExtend {
+				if message := compilation.addMessage(element, file.Package); message != nil {
+					file.Messages = append(file.Messages, message)
+				}
+			}
+		case *proto.Enum:
+			if enum := compilation.addEnum(element, file.Package); enum != nil {
+				file.Enums = append(file.Enums, enum)
+			}
+		}
+	}
+
+	// Types of imported files are visible, including types of files they import publicly
+	for _, imported := range file.Imports {
+		for name, symbol := range visibleSymbols(imported) {
+			if _, exists := compilation.symbols[name]; !exists {
+				compilation.symbols[name] = symbol
+			}
+		}
+	}
+	for _, pending := range compilation.fields {
+		compilation.resolve(pending)
+	}
+
+	if len(compilation.errors) > 0 {
+		return nil, compilation.errors[0]
+	}
+	compiler.files[path] = file
+	return file, nil
+}
+
+func (compiler *compiler) importFile(statement *proto.Import) (*File, error) {
+	path := statement.Filename
+	if file, exists := compiler.files[path]; exists {
+		return file, nil
+	}
+	if compiler.loading[path] {
+		return nil, positionError(statement.Position, "import cycle: %s imports itself", path)
+	}
+
+	source, isWellKnown := wellKnownTypes[path]
+	if !isWellKnown {
+		if compiler.importer == nil {
+			return nil, positionError(statement.Position, "imported file %q not found", path)
+		}
+		var err error
+		if source, err = compiler.importer(path); err != nil {
+			return nil, positionError(statement.Position, "failed to import %q: %v", path, err)
+		}
+	}
+	file, err := compiler.compile(path, source)
+	if err != nil {
+		return nil, positionError(statement.Position, "invalid imported file %q: %v", path, err)
+	}
+	return file, nil
+}
+
+// visibleSymbols returns types defined in the file and in files it imports publicly
+func visibleSymbols(file *File) map[string]interface{} {
+	symbols := make(map[string]interface{})
+	var addMessages func(messages []*Message)
+	addEnums := func(enums []*Enum) {
+		for _, enum := range enums {
+			symbols[enum.Name] = enum
+		}
+	}
+	addMessages = func(messages []*Message) {
+		for _, message := range messages {
+			symbols[message.Name] = message
+			addMessages(message.Messages)
+			addEnums(message.Enums)
+		}
+	}
+	addMessages(file.Messages)
+	addEnums(file.Enums)
+	for _, public := range file.publicImports {
+		for name, symbol := range visibleSymbols(public) {
+			symbols[name] = symbol
+		}
+	}
+	return symbols
+}
+
+func (compilation *fileCompilation) errorf(position scanner.Position, format string, args ...interface{}) {
+	compilation.errors = append(compilation.errors, positionError(position, format, args...))
+}
+
+func (compilation *fileCompilation) define(position scanner.Position, name string, symbol interface{}) bool {
+	if _, exists := compilation.symbols[name]; exists {
+		compilation.errorf(position, "%q is already defined", name)
+		return false
+	}
+	compilation.symbols[name] = symbol
+	return true
+}
+
+func (compilation *fileCompilation) addMessage(definition *proto.Message, scope string) *Message {
+	message := &Message{
+		Name: qualify(scope, definition.Name),
+		Doc:  commentText(definition.Comment),
+		File: compilation.file,
+	}
+	if !compilation.define(definition.Position, message.Name, message) {
+		return nil
+	}
+
+	fieldNames := make(map[string]bool)
+	fieldNumbers := make(map[int]string)
+	addField := func(field *Field, position scanner.Position) {
+		if fieldNames[field.Name] {
+			compilation.errorf(position, "field %q is already defined in %s", field.Name, message.Name)
+			return
+		}
+		if other, exists := fieldNumbers[field.Number]; exists {
+			compilation.errorf(position, "field number %d of %q is already used by %q in %s",
+				field.Number, field.Name, other, message.Name)
+			return
+		}
+		if field.Number < 1 || field.Number > 536870911 || (field.Number >= 19000 && field.Number <= 19999) {
+			compilation.errorf(position, "invalid field number %d of %q", field.Number, field.Name)
+			return
+		}
+		fieldNames[field.Name] = true
+		fieldNumbers[field.Number] = field.Name
+		message.Fields = append(message.Fields, field)
+		compilation.fields = append(compilation.fields, pendingField{field: field, scope: message.Name, position: position})
+	}
+
+	for _, element := range definition.Elements {
+		switch element := element.(type) {
+		case *proto.NormalField:
+			if element.Required && compilation.file.Syntax == "proto3" {
+				compilation.errorf(element.Position, "required fields are not allowed in proto3: %q", element.Name)
+				continue
+			}
+			field := newField(element.Field)
+			field.Repeated = element.Repeated
+			field.Optional = element.Optional
+			addField(field, element.Position)
+		case *proto.MapField:
+			field := newField(element.Field)
+			field.KeyType = element.KeyType
+			if !isValidMapKey(element.KeyType) {
+				compilation.errorf(element.Position, "invalid key type %q of map field %q", element.KeyType, element.Name)
+				continue
+			}
+			addField(field, element.Position)
+		case *proto.Oneof:
+			for _, oneofElement := range element.Elements {
+				if oneofField, isField := oneofElement.(*proto.OneOfField); isField {
+					field := newField(oneofField.Field)
+					field.Oneof = element.Name
+					addField(field, oneofField.Position)
+				}
+			}
+		case *proto.Group:
+			compilation.errorf(element.Position, "groups are not supported: %q", element.Name)
+		case *proto.Message:
+			if !element.IsExtend {
+				if nested := compilation.addMessage(element, message.Name); nested != nil {
+					message.Messages = append(message.Messages, nested)
+				}
+			}
+		case *proto.Enum:
+			if enum := compilation.addEnum(element, message.Name); enum != nil {
+				message.Enums = append(message.Enums, enum)
+			}
+		}
+	}
+	return message
+}
+
+func (compilation *fileCompilation) addEnum(definition *proto.Enum, scope string) *Enum {
+	enum := &Enum{
+		Name: qualify(scope, definition.Name),
+		Doc:  commentText(definition.Comment),
+		File: compilation.file,
+	}
+	if !compilation.define(definition.Position, enum.Name, enum) {
+		return nil
+	}
+
+	valueNames := make(map[string]bool)
+	for _, element := range definition.Elements {
+		value, isValue := element.(*proto.EnumField)
+		if !isValue {
+			continue
+		}
+		if valueNames[value.Name] {
+			compilation.errorf(value.Position, "enum value %q is already defined in %s", value.Name, enum.Name)
+			continue
+		}
+		if len(enum.Values) == 0 && value.Integer != 0 && compilation.file.Syntax == "proto3" {
+			compilation.errorf(value.Position, "the first value of enum %s must be zero in proto3", enum.Name)
+		}
+		valueNames[value.Name] = true
+		enum.Values = append(enum.Values, &EnumValue{Name: value.Name, Number: value.Integer, Doc: commentText(value.Comment)})
+	}
+	if len(enum.Values) == 0 {
+		compilation.errorf(definition.Position, "enum %s must have at least one value", enum.Name)
+	}
+	return enum
+}
+
+// resolve finds the message or enum type of a field, searching from the innermost scope outwards
+func (compilation *fileCompilation) resolve(pending pendingField) {
+	field := pending.field
+	if scalarTypes[field.Type] {
+		return
+	}
+
+	var symbol interface{}
+	if strings.HasPrefix(field.Type, ".") {
+		symbol = compilation.symbols[strings.TrimPrefix(field.Type, ".")]
+	} else {
+		for scope := pending.scope; symbol == nil; scope = parentScope(scope) {
+			symbol = compilation.symbols[qualify(scope, field.Type)]
+			if scope == "" {
+				break
+			}
+		}
+	}
+
+	switch resolved := symbol.(type) {
+	case *Message:
+		field.Message = resolved
+		field.Type = resolved.Name
+	case *Enum:
+		field.Enum = resolved
+		field.Type = resolved.Name
+	default:
+		compilation.errorf(pending.position, "unknown type %q of field %q", field.Type, field.Name)
+	}
+}
+
+func newField(definition *proto.Field) *Field {
+	field := &Field{
+		Name:   definition.Name,
+		Number: definition.Sequence,
+		Doc:    commentText(definition.Comment),
+		Type:   definition.Type,
+	}
+	if field.Doc == "" {
+		field.Doc = commentText(definition.InlineComment)
+	}
+	for _, option := range definition.Options {
+		if option.Name == "deprecated" && option.Constant.Source == "true" {
+			field.Deprecated = true
+		}
+	}
+	return field
+}
+
+var scalarTypes = map[string]bool{
+	"double": true, "float": true,
+	"int32": true, "int64": true, "uint32": true, "uint64": true, "sint32": true, "sint64": true,
+	"fixed32": true, "fixed64": true, "sfixed32": true, "sfixed64": true,
+	"bool": true, "string": true, "bytes": true,
+}
+
+func isValidMapKey(keyType string) bool {
+	return scalarTypes[keyType] && keyType != "double" && keyType != "float" && keyType != "bytes"
+}
+
+func qualify(scope string, name string) string {
+	if scope == "" {
+		return name
+	}
+	return scope + "." + name
+}
+
+func parentScope(scope string) string {
+	if index := strings.LastIndex(scope, "."); index >= 0 {
+		return scope[:index]
+	}
+	return ""
+}
+
+func commentText(comment *proto.Comment) string {
+	if comment == nil {
+		return ""
+	}
+	var lines []string
+	for _, line := range comment.Lines {
+		lines = append(lines, strings.TrimSpace(line))
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+func positionError(position scanner.Position, format string, args ...interface{}) error {
+	return &Error{File: position.Filename, Line: position.Line, Column: position.Column, Message: fmt.Sprintf(format, args...)}
+}
+
+// WellKnownImports lists the well-known type files which can be imported without the importer
+func WellKnownImports() []string {
+	paths := make([]string, 0, len(wellKnownTypes))
+	for path := range wellKnownTypes {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
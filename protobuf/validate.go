@@ -0,0 +1,363 @@
+package protobuf
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Data is validated in the proto3 JSON mapping: fields are named in lowerCamelCase (original names
+// are accepted too), 64-bit integers can be strings, bytes are base64 strings, enums are names or
+// numbers, well-known types have their special representations (e.g. timestamps are RFC 3339 strings).
+
+// ValidationError tells why a value doesn't match the message. Path is a JSON pointer to the value.
+type ValidationError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (err *ValidationError) Error() string {
+	if err.Path == "" {
+		return err.Message
+	}
+	return err.Path + ": " + err.Message
+}
+
+// ValidateJSON checks that the document is a message of the type in the proto3 JSON mapping
+func (message *Message) ValidateJSON(document []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return &ValidationError{Message: fmt.Sprintf("invalid JSON: %v", err)}
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return &ValidationError{Message: "invalid JSON: unexpected data after the value"}
+	}
+	return message.Validate(value)
+}
+
+// Validate checks a value decoded from JSON (numbers can be json.Number or float64)
+func (message *Message) Validate(value interface{}) error {
+	return validateMessage(message, value, "")
+}
+
+func validationErrorf(path string, format string, args ...interface{}) error {
+	return &ValidationError{Path: path, Message: fmt.Sprintf(format, args...)}
+}
+
+func validateMessage(message *Message, value interface{}, path string) error {
+	if special, isSpecial := wellKnownValidators[message.Name]; isSpecial {
+		return special(value, path)
+	}
+
+	values, isObject := value.(map[string]interface{})
+	if !isObject {
+		return validationErrorf(path, "expected an object of message %s", message.Name)
+	}
+	setOneofs := make(map[string]string)
+	for _, key := range sortedKeys(values) {
+		fieldPath := path + "/" + escapePointer(key)
+		field := findField(message, key)
+		if field == nil {
+			return validationErrorf(fieldPath, "message %s has no field %q", message.Name, key)
+		}
+		fieldValue := values[key]
+		if fieldValue == nil && (field.Message == nil || field.Message.Name != "google.protobuf.Value") {
+			// null is the default value of any field
+			continue
+		}
+		if field.Oneof != "" {
+			if other, isSet := setOneofs[field.Oneof]; isSet {
+				return validationErrorf(fieldPath, "only one field of oneof %s can be set, %q is set too", field.Oneof, other)
+			}
+			setOneofs[field.Oneof] = key
+		}
+		if err := validateField(field, fieldValue, fieldPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func findField(message *Message, key string) *Field {
+	for _, field := range message.Fields {
+		if field.Name == key || JSONName(field.Name) == key {
+			return field
+		}
+	}
+	return nil
+}
+
+func validateField(field *Field, value interface{}, path string) error {
+	switch {
+	case field.IsMap():
+		entries, isObject := value.(map[string]interface{})
+		if !isObject {
+			return validationErrorf(path, "expected an object")
+		}
+		for _, key := range sortedKeys(entries) {
+			entryPath := path + "/" + escapePointer(key)
+			if err := validateMapKey(field.KeyType, key, entryPath); err != nil {
+				return err
+			}
+			if err := validateSingular(field, entries[key], entryPath); err != nil {
+				return err
+			}
+		}
+		return nil
+	case field.Repeated:
+		items, isList := value.([]interface{})
+		if !isList {
+			return validationErrorf(path, "expected an array")
+		}
+		for index, item := range items {
+			if err := validateSingular(field, item, path+"/"+strconv.Itoa(index)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return validateSingular(field, value, path)
+}
+
+func validateSingular(field *Field, value interface{}, path string) error {
+	switch {
+	case field.Message != nil:
+		return validateMessage(field.Message, value, path)
+	case field.Enum != nil:
+		return validateEnum(field.Enum, value, path)
+	}
+	return validateScalar(field.Type, value, path)
+}
+
+func validateEnum(enum *Enum, value interface{}, path string) error {
+	if name, isString := value.(string); isString {
+		for _, enumValue := range enum.Values {
+			if enumValue.Name == name {
+				return nil
+			}
+		}
+		return validationErrorf(path, "%q is not a value of enum %s", name, enum.Name)
+	}
+	if number, isNumber := asNumber(value); isNumber {
+		if _, err := strconv.ParseInt(number.String(), 10, 32); err != nil {
+			return validationErrorf(path, "expected a value of enum %s", enum.Name)
+		}
+		return nil
+	}
+	return validationErrorf(path, "expected a value of enum %s", enum.Name)
+}
+
+func validateScalar(protoType string, value interface{}, path string) error {
+	switch protoType {
+	case "bool":
+		if _, isBool := value.(bool); !isBool {
+			return validationErrorf(path, "expected a boolean")
+		}
+	case "string":
+		if _, isString := value.(string); !isString {
+			return validationErrorf(path, "expected a string")
+		}
+	case "bytes":
+		text, isString := value.(string)
+		if !isString {
+			return validationErrorf(path, "expected a base64 string")
+		}
+		if !isBase64(text) {
+			return validationErrorf(path, "expected a base64 string")
+		}
+	case "double", "float":
+		if text, isString := value.(string); isString {
+			if text == "NaN" || text == "Infinity" || text == "-Infinity" {
+				return nil
+			}
+			if _, err := strconv.ParseFloat(text, 64); err != nil {
+				return validationErrorf(path, "expected a number")
+			}
+			return nil
+		}
+		number, isNumber := asNumber(value)
+		if !isNumber {
+			return validationErrorf(path, "expected a number")
+		}
+		if protoType == "float" {
+			if parsed, err := number.Float64(); err == nil && math.Abs(parsed) > math.MaxFloat32 {
+				return validationErrorf(path, "number is out of the range of float")
+			}
+		}
+	default:
+		// Integers can be numbers or strings, 64-bit integers are strings in the canonical form
+		var text string
+		if number, isNumber := asNumber(value); isNumber {
+			text = number.String()
+		} else if stringValue, isString := value.(string); isString {
+			text = stringValue
+		} else {
+			return validationErrorf(path, "expected an integer")
+		}
+		if err := validateInteger(protoType, text); err != nil {
+			return validationErrorf(path, "%s", err.Error())
+		}
+	}
+	return nil
+}
+
+func validateInteger(protoType string, text string) error {
+	// Exponent notation of whole numbers is allowed, e.g. 1e3
+	if strings.ContainsAny(text, ".eE") {
+		parsed, err := strconv.ParseFloat(text, 64)
+		if err != nil || parsed != math.Trunc(parsed) {
+			return fmt.Errorf("expected an integer")
+		}
+		text = strconv.FormatFloat(parsed, 'f', -1, 64)
+	}
+	var err error
+	switch protoType {
+	case "int32", "sint32", "sfixed32":
+		_, err = strconv.ParseInt(text, 10, 32)
+	case "int64", "sint64", "sfixed64":
+		_, err = strconv.ParseInt(text, 10, 64)
+	case "uint32", "fixed32":
+		_, err = strconv.ParseUint(text, 10, 32)
+	case "uint64", "fixed64":
+		_, err = strconv.ParseUint(text, 10, 64)
+	}
+	if err != nil {
+		return fmt.Errorf("expected an integer in the range of %s", protoType)
+	}
+	return nil
+}
+
+func validateMapKey(keyType string, key string, path string) error {
+	switch keyType {
+	case "string":
+		return nil
+	case "bool":
+		if key != "true" && key != "false" {
+			return validationErrorf(path, "expected a boolean key")
+		}
+		return nil
+	}
+	if err := validateInteger(keyType, key); err != nil {
+		return validationErrorf(path, "invalid key: %s", err.Error())
+	}
+	return nil
+}
+
+var durationPattern = regexp.MustCompile(`^-?[0-9]+(\.[0-9]{1,9})?s$`)
+
+// wellKnownValidators check well-known types which have special JSON representations
+var wellKnownValidators = map[string]func(value interface{}, path string) error{
+	"google.protobuf.Timestamp": func(value interface{}, path string) error {
+		text, isString := value.(string)
+		if !isString {
+			return validationErrorf(path, "expected a timestamp in RFC 3339 format")
+		}
+		if _, err := time.Parse(time.RFC3339Nano, text); err != nil {
+			return validationErrorf(path, "expected a timestamp in RFC 3339 format")
+		}
+		return nil
+	},
+	"google.protobuf.Duration": func(value interface{}, path string) error {
+		text, isString := value.(string)
+		if !isString || !durationPattern.MatchString(text) {
+			return validationErrorf(path, "expected a duration in seconds, e.g. \"1.5s\"")
+		}
+		return nil
+	},
+	"google.protobuf.FieldMask": func(value interface{}, path string) error {
+		if _, isString := value.(string); !isString {
+			return validationErrorf(path, "expected a field mask string")
+		}
+		return nil
+	},
+	"google.protobuf.Any": func(value interface{}, path string) error {
+		values, isObject := value.(map[string]interface{})
+		if !isObject {
+			return validationErrorf(path, "expected an object")
+		}
+		if typeURL, isString := values["@type"].(string); !isString || typeURL == "" {
+			return validationErrorf(path, "\"@type\" is required")
+		}
+		return nil
+	},
+	"google.protobuf.Struct": func(value interface{}, path string) error {
+		if _, isObject := value.(map[string]interface{}); !isObject {
+			return validationErrorf(path, "expected an object")
+		}
+		return nil
+	},
+	"google.protobuf.ListValue": func(value interface{}, path string) error {
+		if _, isList := value.([]interface{}); !isList {
+			return validationErrorf(path, "expected an array")
+		}
+		return nil
+	},
+	"google.protobuf.Value": func(value interface{}, path string) error {
+		return nil
+	},
+	"google.protobuf.Empty": func(value interface{}, path string) error {
+		values, isObject := value.(map[string]interface{})
+		if !isObject || len(values) > 0 {
+			return validationErrorf(path, "expected an empty object")
+		}
+		return nil
+	},
+	"google.protobuf.DoubleValue": wrapperValidator("double"),
+	"google.protobuf.FloatValue":  wrapperValidator("float"),
+	"google.protobuf.Int64Value":  wrapperValidator("int64"),
+	"google.protobuf.UInt64Value": wrapperValidator("uint64"),
+	"google.protobuf.Int32Value":  wrapperValidator("int32"),
+	"google.protobuf.UInt32Value": wrapperValidator("uint32"),
+	"google.protobuf.BoolValue":   wrapperValidator("bool"),
+	"google.protobuf.StringValue": wrapperValidator("string"),
+	"google.protobuf.BytesValue":  wrapperValidator("bytes"),
+}
+
+// wrapperValidator checks wrappers of scalars which are represented by the wrapped value
+func wrapperValidator(protoType string) func(value interface{}, path string) error {
+	return func(value interface{}, path string) error {
+		return validateScalar(protoType, value, path)
+	}
+}
+
+func isBase64(text string) bool {
+	for _, encoding := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.RawURLEncoding} {
+		if _, err := encoding.DecodeString(text); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func asNumber(value interface{}) (json.Number, bool) {
+	switch number := value.(type) {
+	case json.Number:
+		return number, true
+	case float64:
+		return json.Number(strconv.FormatFloat(number, 'f', -1, 64)), true
+	}
+	return "", false
+}
+
+func sortedKeys(values map[string]interface{}) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
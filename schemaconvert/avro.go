@@ -0,0 +1,405 @@
+package schemaconvert
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/fusioncatltd/fusioncat/avro"
+)
+
+// JSONSchemaToAvro converts a JSON schema decoded with json.Decoder.UseNumber to an Avro schema.
+// Objects with properties become records named after their titles or properties, the root record
+// is named after its title or the name. Optional properties become unions with null, defaulting to null.
+func JSONSchemaToAvro(document interface{}, name string) (string, []Loss, error) {
+	losses := &lossList{}
+	root := readJSONSchema(document, name, "Avro", losses)
+	writer := &avroWriter{losses: losses, defined: make(map[string]bool)}
+	content, err := marshal(writer.schema(root, ""))
+	if err != nil {
+		return "", nil, err
+	}
+	if _, err := avro.Parse(content); err != nil {
+		return "", nil, fmt.Errorf("converted Avro schema is invalid: %w", err)
+	}
+	return content, losses.result(), nil
+}
+
+type avroRecord struct {
+	Type   string      `json:"type"`
+	Name   string      `json:"name"`
+	Doc    string      `json:"doc,omitempty"`
+	Fields []avroField `json:"fields"`
+}
+
+type avroField struct {
+	Name    string          `json:"name"`
+	Type    interface{}     `json:"type"`
+	Doc     string          `json:"doc,omitempty"`
+	Default json.RawMessage `json:"default,omitempty"`
+}
+
+type avroEnum struct {
+	Type    string   `json:"type"`
+	Name    string   `json:"name"`
+	Doc     string   `json:"doc,omitempty"`
+	Symbols []string `json:"symbols"`
+}
+
+type avroWriter struct {
+	losses *lossList
+	// Names of records and enums which are defined, further uses refer to them by name
+	defined map[string]bool
+}
+
+// schema returns the Avro definition of the node: a type name, an array of union branches or an object
+func (writer *avroWriter) schema(n *node, path string) interface{} {
+	switch n.kind {
+	case kindObject:
+		if writer.defined[n.name] {
+			return n.name
+		}
+		writer.defined[n.name] = true
+		record := &avroRecord{Type: avro.TypeRecord, Name: n.name, Doc: n.doc, Fields: make([]avroField, 0, len(n.fields))}
+		names := fieldNames(n.fields, path, writer.losses)
+		for index, field := range n.fields {
+			record.Fields = append(record.Fields, writer.field(field, names[index], path+"/"+escapePointer(field.name)))
+		}
+		return record
+	case kindEnum:
+		if writer.defined[n.name] {
+			return n.name
+		}
+		seen := make(map[string]bool)
+		for _, symbol := range n.symbols {
+			if !identifierPattern.MatchString(symbol) || seen[symbol] {
+				writer.losses.add(path, "enum value %q is not a valid Avro enum symbol, values of the enum are strings", symbol)
+				return avro.TypeString
+			}
+			seen[symbol] = true
+		}
+		writer.defined[n.name] = true
+		return &avroEnum{Type: avro.TypeEnum, Name: n.name, Doc: n.doc, Symbols: n.symbols}
+	case kindMap:
+		return map[string]interface{}{"type": avro.TypeMap, "values": writer.schema(n.items, path+"/*")}
+	case kindArray:
+		return map[string]interface{}{"type": avro.TypeArray, "items": writer.schema(n.items, path+"/*")}
+	case kindString:
+		switch n.format {
+		case "date-time":
+			return map[string]interface{}{"type": avro.TypeLong, "logicalType": "timestamp-millis"}
+		case "date":
+			return map[string]interface{}{"type": avro.TypeInt, "logicalType": "date"}
+		case "time":
+			return map[string]interface{}{"type": avro.TypeInt, "logicalType": "time-millis"}
+		case "uuid":
+			return map[string]interface{}{"type": avro.TypeString, "logicalType": "uuid"}
+		}
+		return avro.TypeString
+	case kindBytes:
+		return avro.TypeBytes
+	case kindInteger:
+		if n.int32 {
+			return avro.TypeInt
+		}
+		return avro.TypeLong
+	case kindNumber:
+		return avro.TypeDouble
+	case kindBoolean:
+		return avro.TypeBoolean
+	case kindNull:
+		return avro.TypeNull
+	case kindUnion:
+		return writer.union(n.branches, path)
+	}
+	writer.losses.add(path, "values of any type are not representable in Avro, they are strings")
+	return avro.TypeString
+}
+
+// union writes branches of a union, Avro unions can't contain several branches of the same type
+func (writer *avroWriter) union(branches []*node, path string) interface{} {
+	var union []interface{}
+	keys := make(map[string]bool)
+	for _, branch := range branches {
+		schema := writer.schema(branch, path)
+		key := avroUnionKey(schema)
+		if keys[key] {
+			writer.losses.add(path, "Avro unions can't contain several branches of type %s, only the first one is kept", key)
+			continue
+		}
+		keys[key] = true
+		union = append(union, schema)
+	}
+	if len(union) == 1 {
+		return union[0]
+	}
+	return union
+}
+
+// avroUnionKey is the type which identifies a branch of a union, logical types are identified by their underlying type
+func avroUnionKey(schema interface{}) string {
+	switch schema := schema.(type) {
+	case string:
+		return schema
+	case *avroRecord:
+		return schema.Name
+	case *avroEnum:
+		return schema.Name
+	case map[string]interface{}:
+		return fmt.Sprint(schema["type"])
+	}
+	return ""
+}
+
+// field writes a field of a record. Optional fields become nullable, they default to null unless they have
+// a default value, which makes its type the first branch of the union.
+func (writer *avroWriter) field(field *field, name string, path string) avroField {
+	result := avroField{Name: name, Doc: field.doc, Type: writer.schema(field.node, path)}
+	_, nullable := field.node.nonNullBranches()
+	optional := !field.required && !nullable
+	branches, isUnion := result.Type.([]interface{})
+	if !isUnion {
+		branches = []interface{}{result.Type}
+	}
+
+	if field.hasDefault && field.defaultValue != nil && avroDefaultMatches(branches[0], field.defaultValue) {
+		result.Default, _ = json.Marshal(field.defaultValue)
+		if optional {
+			result.Type = append(branches, avro.TypeNull)
+		}
+		return result
+	}
+	if field.hasDefault && field.defaultValue != nil {
+		writer.losses.add(path, "default value %v is not representable in Avro, it is dropped", field.defaultValue)
+	}
+	if optional {
+		branches = append([]interface{}{avro.TypeNull}, branches...)
+		result.Type = branches
+	}
+	if !field.required && branches[0] == avro.TypeNull {
+		result.Default = json.RawMessage("null")
+	}
+	return result
+}
+
+// avroDefaultMatches tells if the value is a valid default of the type
+func avroDefaultMatches(schema interface{}, value interface{}) bool {
+	switch schema := schema.(type) {
+	case string:
+		switch schema {
+		case avro.TypeNull:
+			return value == nil
+		case avro.TypeString:
+			_, isString := value.(string)
+			return isString
+		case avro.TypeBoolean:
+			_, isBool := value.(bool)
+			return isBool
+		case avro.TypeInt, avro.TypeLong:
+			number, isNumber := value.(json.Number)
+			_, err := number.Int64()
+			return isNumber && err == nil
+		case avro.TypeDouble:
+			_, isNumber := value.(json.Number)
+			return isNumber
+		}
+	case *avroEnum:
+		symbol, isString := value.(string)
+		return isString && containsString(schema.Symbols, symbol)
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+// AvroToJSONSchema converts an Avro schema to a draft 2020-12 JSON schema. Records become objects
+// titled with their names, records other than the root are defined in "$defs". Fields without defaults
+// are required.
+func AvroToJSONSchema(schema *avro.Schema) (string, []Loss, error) {
+	writer := &avroJSONSchemaWriter{
+		losses:      &lossList{},
+		root:        schema,
+		definitions: make(map[string]interface{}),
+		references:  make(map[*avro.Schema]string),
+		names:       make(nameSet),
+	}
+	document := writer.schema(schema, "")
+	document["$schema"] = Dialect
+	if len(writer.definitions) > 0 {
+		document["$defs"] = writer.definitions
+	}
+	content, err := marshal(document)
+	if err != nil {
+		return "", nil, err
+	}
+	return content, writer.losses.result(), nil
+}
+
+type avroJSONSchemaWriter struct {
+	losses      *lossList
+	root        *avro.Schema
+	definitions map[string]interface{}
+	// References to records, by record
+	references map[*avro.Schema]string
+	names      nameSet
+}
+
+func (writer *avroJSONSchemaWriter) schema(schema *avro.Schema, path string) map[string]interface{} {
+	if schema.LogicalType != "" {
+		if converted, represented := writer.logicalType(schema, path); represented {
+			return converted
+		}
+	}
+
+	switch schema.Type {
+	case avro.TypeRecord:
+		return writer.record(schema, path)
+	case avro.TypeEnum:
+		converted := map[string]interface{}{"type": "string", "title": schema.ShortName(), "enum": schema.Symbols}
+		if schema.Doc != "" {
+			converted["description"] = schema.Doc
+		}
+		if schema.EnumDefault != "" {
+			writer.losses.add(path, "default symbol %q of enum %s is not representable in JSON Schema", schema.EnumDefault, schema.Name)
+		}
+		return converted
+	case avro.TypeFixed:
+		// Bytes are strings of code points in the Avro JSON encoding
+		converted := map[string]interface{}{"type": "string", "title": schema.ShortName(), "minLength": schema.Size, "maxLength": schema.Size}
+		if schema.Doc != "" {
+			converted["description"] = schema.Doc
+		}
+		return converted
+	case avro.TypeArray:
+		return map[string]interface{}{"type": "array", "items": writer.schema(schema.Items, path+"/*")}
+	case avro.TypeMap:
+		return map[string]interface{}{"type": "object", "additionalProperties": writer.schema(schema.Values, path+"/*")}
+	case avro.TypeUnion:
+		return writer.union(schema, path)
+	case avro.TypeInt:
+		return map[string]interface{}{"type": "integer", "minimum": math.MinInt32, "maximum": math.MaxInt32}
+	case avro.TypeLong:
+		return map[string]interface{}{"type": "integer"}
+	case avro.TypeFloat, avro.TypeDouble:
+		return map[string]interface{}{"type": "number"}
+	case avro.TypeBytes:
+		return map[string]interface{}{"type": "string"}
+	}
+	return map[string]interface{}{"type": schema.Type}
+}
+
+// logicalType converts logical types which JSON schemas represent by formats
+func (writer *avroJSONSchemaWriter) logicalType(schema *avro.Schema, path string) (map[string]interface{}, bool) {
+	switch schema.LogicalType {
+	case "uuid":
+		return map[string]interface{}{"type": "string", "format": "uuid"}, true
+	case "date":
+		return map[string]interface{}{"type": "string", "format": "date"}, true
+	case "time-millis", "time-micros":
+		return map[string]interface{}{"type": "string", "format": "time"}, true
+	case "timestamp-millis", "timestamp-micros":
+		return map[string]interface{}{"type": "string", "format": "date-time"}, true
+	}
+	writer.losses.add(path, "logical type %q is not representable in JSON Schema, values are of type %s",
+		schema.LogicalType, schema.Type)
+	return nil, false
+}
+
+// record defines the record in "$defs" on its first use, the root record is the root schema
+func (writer *avroJSONSchemaWriter) record(schema *avro.Schema, path string) map[string]interface{} {
+	if reference, defined := writer.references[schema]; defined {
+		return map[string]interface{}{"$ref": reference}
+	}
+	var definitionName string
+	if schema == writer.root {
+		writer.references[schema] = "#"
+	} else {
+		definitionName = writer.names.take(schema.ShortName())
+		writer.references[schema] = "#/$defs/" + escapePointer(definitionName)
+	}
+	if len(schema.Aliases) > 0 {
+		writer.losses.add(path, "aliases of record %s are not representable in JSON Schema", schema.Name)
+	}
+
+	properties := make(map[string]interface{})
+	required := make([]string, 0)
+	for _, field := range schema.Fields {
+		fieldPath := path + "/" + escapePointer(field.Name)
+		property := writer.schema(field.Type, fieldPath)
+		if field.Doc != "" {
+			property["description"] = field.Doc
+		}
+		if field.HasDefault {
+			property["default"] = field.Default
+		} else {
+			required = append(required, field.Name)
+		}
+		if len(field.Aliases) > 0 {
+			writer.losses.add(fieldPath, "aliases of field %q are not representable in JSON Schema", field.Name)
+		}
+		properties[field.Name] = property
+	}
+	converted := map[string]interface{}{
+		"type":                 "object",
+		"title":                schema.ShortName(),
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+	if schema.Doc != "" {
+		converted["description"] = schema.Doc
+	}
+	if schema == writer.root {
+		return converted
+	}
+	writer.definitions[definitionName] = converted
+	return map[string]interface{}{"$ref": writer.references[schema]}
+}
+
+// union converts unions to "anyOf", unions of null and a single primitive type to a list of types
+func (writer *avroJSONSchemaWriter) union(schema *avro.Schema, path string) map[string]interface{} {
+	var branches []interface{}
+	nullable := false
+	for _, branch := range schema.Branches {
+		if branch.Type == avro.TypeNull {
+			nullable = true
+			continue
+		}
+		branches = append(branches, writer.schema(branch, path))
+	}
+	if len(branches) == 1 && nullable {
+		if converted := branches[0].(map[string]interface{}); isPrimitiveJSONSchema(converted) {
+			converted["type"] = []interface{}{converted["type"], "null"}
+			return converted
+		}
+	}
+	if nullable {
+		branches = append(branches, map[string]interface{}{"type": "null"})
+	}
+	if len(branches) == 1 {
+		return branches[0].(map[string]interface{})
+	}
+	return map[string]interface{}{"anyOf": branches}
+}
+
+// isPrimitiveJSONSchema tells if the schema has a single type and no keywords which reject null
+func isPrimitiveJSONSchema(schema map[string]interface{}) bool {
+	if _, isString := schema["type"].(string); !isString {
+		return false
+	}
+	for keyword := range schema {
+		switch keyword {
+		case "type", "format", "minimum", "maximum", "minLength", "maxLength", "contentEncoding":
+		default:
+			return false
+		}
+	}
+	return true
+}
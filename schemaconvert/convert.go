@@ -0,0 +1,247 @@
+// Package schemaconvert converts schemas between JSON Schema, Apache Avro and Protocol Buffers.
+// Conversions map data models: objects become records and messages and back, optional fields become
+// nullable, string formats become logical types and well-known types. Values are encoded the way the
+// target type encodes them, e.g. timestamps are date-time strings in JSON, timestamp-millis longs in Avro
+// and google.protobuf.Timestamp messages in protobuf.
+//
+// Constructs of the source schema which the target type can't express are dropped or relaxed, each
+// of them is reported as a Loss:
+//
+//	content, losses, err := schemaconvert.JSONSchemaToAvro(document, "Order")
+//
+// JSON schemas are converted after references to other schemas are bundled, only local references
+// ("#/...") are followed.
+package schemaconvert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Dialect is the value of "$schema" of JSON schemas produced by conversions
+const Dialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Loss is a construct of the source schema which is dropped or relaxed by a conversion. Path is a JSON
+// pointer to the affected field in the data, items of arrays and values of maps are "*".
+type Loss struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+type lossList struct {
+	losses []Loss
+	seen   map[Loss]bool
+}
+
+func (list *lossList) add(path string, format string, args ...interface{}) {
+	loss := Loss{Path: path, Message: fmt.Sprintf(format, args...)}
+	if list.seen == nil {
+		list.seen = make(map[Loss]bool)
+	}
+	if list.seen[loss] {
+		return
+	}
+	list.seen[loss] = true
+	list.losses = append(list.losses, loss)
+}
+
+// result returns losses sorted by path, losses of the same path stay in the order they were found
+func (list *lossList) result() []Loss {
+	losses := make([]Loss, len(list.losses))
+	copy(losses, list.losses)
+	sort.SliceStable(losses, func(i, j int) bool {
+		return losses[i].Path < losses[j].Path
+	})
+	return losses
+}
+
+// Kinds of nodes of the data model
+const (
+	kindObject  = "object"
+	kindMap     = "map"
+	kindArray   = "array"
+	kindString  = "string"
+	kindBytes   = "bytes"
+	kindInteger = "integer"
+	kindNumber  = "number"
+	kindBoolean = "boolean"
+	kindNull    = "null"
+	kindEnum    = "enum"
+	kindUnion   = "union"
+	// Values of any type
+	kindAny = "any"
+)
+
+// node is a type of the data model JSON schemas are read into. Objects and enums are named,
+// nodes of objects are shared by all references to them, so recursive objects form cycles.
+// Nullable types are unions with a null branch.
+type node struct {
+	kind string
+	name string
+	doc  string
+	// Format of strings: date-time, date, time or uuid
+	format string
+	// Integers fitting into 32 bits
+	int32    bool
+	fields   []*field
+	items    *node // array items and map values
+	symbols  []string
+	branches []*node
+}
+
+type field struct {
+	name         string
+	doc          string
+	node         *node
+	required     bool
+	hasDefault   bool
+	defaultValue interface{}
+}
+
+// nonNullBranches returns branches of unions which are not null, other nodes are returned as they are
+func (n *node) nonNullBranches() ([]*node, bool) {
+	if n.kind == kindNull {
+		return nil, true
+	}
+	if n.kind != kindUnion {
+		return []*node{n}, false
+	}
+	var branches []*node
+	nullable := false
+	for _, branch := range n.branches {
+		if branch.kind == kindNull {
+			nullable = true
+			continue
+		}
+		branches = append(branches, branch)
+	}
+	return branches, nullable
+}
+
+// unionOf makes a union of the branches, nested unions are flattened
+func unionOf(branches []*node, nullable bool) *node {
+	var flattened []*node
+	for _, branch := range branches {
+		nested, nestedNullable := branch.nonNullBranches()
+		nullable = nullable || nestedNullable
+		flattened = append(flattened, nested...)
+	}
+	if nullable {
+		flattened = append([]*node{{kind: kindNull}}, flattened...)
+	}
+	switch len(flattened) {
+	case 0:
+		return &node{kind: kindAny}
+	case 1:
+		return flattened[0]
+	}
+	return &node{kind: kindUnion, branches: flattened}
+}
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+var nonIdentifierCharacters = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// identifier makes a valid Avro name or protobuf identifier of the name
+func identifier(name string) string {
+	if identifierPattern.MatchString(name) {
+		return name
+	}
+	name = nonIdentifierCharacters.ReplaceAllString(name, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	return name
+}
+
+// typeName makes a CamelCase name of a record, message or enum from a hint
+func typeName(hint string) string {
+	var builder strings.Builder
+	for _, part := range nonIdentifierCharacters.Split(strings.ReplaceAll(hint, "_", " "), -1) {
+		for _, word := range strings.Fields(part) {
+			builder.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	name := builder.String()
+	if name == "" {
+		return "Record"
+	}
+	if name[0] >= '0' && name[0] <= '9' {
+		name = "T" + name
+	}
+	return name
+}
+
+// nameSet gives out unique names, taken names get a numeric suffix
+type nameSet map[string]bool
+
+func (names nameSet) take(name string) string {
+	unique := name
+	for suffix := 2; names[unique]; suffix++ {
+		unique = name + strconv.Itoa(suffix)
+	}
+	names[unique] = true
+	return unique
+}
+
+// fieldNames makes valid unique names of fields, renamed fields are reported as losses
+func fieldNames(fields []*field, path string, losses *lossList) []string {
+	taken := make(nameSet)
+	names := make([]string, len(fields))
+	for index, field := range fields {
+		names[index] = taken.take(identifier(field.name))
+		if names[index] != field.name {
+			losses.add(path+"/"+escapePointer(field.name), "field %q is renamed to %q", field.name, names[index])
+		}
+	}
+	return names
+}
+
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+func sortedKeys(values map[string]interface{}) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func isInt32Range(minimum interface{}, maximum interface{}) bool {
+	low, lowIsNumber := asFloat(minimum)
+	high, highIsNumber := asFloat(maximum)
+	return lowIsNumber && highIsNumber && low >= math.MinInt32 && high <= math.MaxInt32
+}
+
+func asFloat(value interface{}) (float64, bool) {
+	switch number := value.(type) {
+	case float64:
+		return number, true
+	case json.Number:
+		parsed, err := number.Float64()
+		return parsed, err == nil
+	case int:
+		return float64(number), true
+	}
+	return 0, false
+}
+
+// marshal encodes JSON schemas and Avro schemas of conversions
+func marshal(value interface{}) (string, error) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buffer.String(), "\n"), nil
+}
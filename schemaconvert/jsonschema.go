@@ -0,0 +1,388 @@
+package schemaconvert
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Keywords of JSON schemas which constrain values beyond their types, Avro and protobuf schemas can't express them
+var constraintKeywords = []string{
+	"minLength", "maxLength", "pattern",
+	"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "multipleOf",
+	"minItems", "maxItems", "uniqueItems", "contains", "minContains", "maxContains",
+	"minProperties", "maxProperties", "patternProperties", "propertyNames",
+	"dependentRequired", "dependentSchemas", "dependencies",
+	"not", "if", "then", "else", "unevaluatedProperties", "unevaluatedItems",
+}
+
+// Formats of strings which Avro or protobuf types represent
+var representedFormats = map[string]bool{"date-time": true, "date": true, "time": true, "uuid": true}
+
+// jsonSchemaReader reads JSON schemas into the data model of conversions
+type jsonSchemaReader struct {
+	root interface{}
+	// target is the name of the target type in losses
+	target string
+	losses *lossList
+	// Nodes of local references, by reference
+	references map[string]*node
+	names      nameSet
+}
+
+// readJSONSchema reads the JSON schema decoded with json.Decoder.UseNumber, the root type is named
+// after its title or the name
+func readJSONSchema(document interface{}, name string, target string, losses *lossList) *node {
+	reader := &jsonSchemaReader{
+		root:       document,
+		target:     target,
+		losses:     losses,
+		references: make(map[string]*node),
+		names:      make(nameSet),
+	}
+	// References to the root resolve to the node of the root
+	root := &node{}
+	reader.references["#"] = root
+	*root = *reader.read(document, "", name)
+	return root
+}
+
+func (reader *jsonSchemaReader) read(schema interface{}, path string, name string) *node {
+	object, isObject := schema.(map[string]interface{})
+	if !isObject {
+		if schema == false {
+			reader.losses.add(path, "the schema accepts no values, any values are accepted in %s", reader.target)
+		}
+		return &node{kind: kindAny}
+	}
+	if reference, isString := object["$ref"].(string); isString {
+		return reader.reference(reference, path, name)
+	}
+	if title, isString := object["title"].(string); isString && title != "" {
+		name = title
+	}
+
+	var result *node
+	switch {
+	case object["allOf"] != nil:
+		result = reader.readAllOf(object, path, name)
+	case object["anyOf"] != nil || object["oneOf"] != nil:
+		result = reader.readBranches(object, path, name)
+	case object["enum"] != nil:
+		reader.reportConstraints(object, path)
+		result = reader.readEnum(object, path, name)
+	default:
+		reader.reportConstraints(object, path)
+		if _, hasConst := object["const"]; hasConst {
+			reader.losses.add(path, "\"const\" is not representable in %s, values of the type are accepted", reader.target)
+		}
+		result = reader.readTypes(object, path, name)
+	}
+	if description, isString := object["description"].(string); isString && result.doc == "" &&
+		(result.kind == kindObject || result.kind == kindEnum) {
+		result.doc = description
+	}
+	return result
+}
+
+func (reader *jsonSchemaReader) reportConstraints(object map[string]interface{}, path string) {
+	for _, keyword := range constraintKeywords {
+		if _, exists := object[keyword]; !exists {
+			continue
+		}
+		// Bounds of 32-bit integers are represented by their types
+		if (keyword == "minimum" || keyword == "maximum") && containsString(schemaTypes(object), kindInteger) &&
+			isInt32Range(object["minimum"], object["maximum"]) {
+			continue
+		}
+		reader.losses.add(path, "%q is not representable in %s", keyword, reader.target)
+	}
+}
+
+// reference reads the schema of a local reference, every reference is read once
+func (reader *jsonSchemaReader) reference(reference string, path string, name string) *node {
+	if resolved, exists := reader.references[reference]; exists {
+		return resolved
+	}
+	target, found := reader.resolve(reference)
+	if !found {
+		reader.losses.add(path, "reference %q can't be resolved, any values are accepted in %s", reference, reader.target)
+		return &node{kind: kindAny}
+	}
+	if index := strings.LastIndex(reference, "/"); index >= 0 {
+		name = reference[index+1:]
+	}
+	resolved := &node{}
+	reader.references[reference] = resolved
+	*resolved = *reader.read(target, path, name)
+	return resolved
+}
+
+// resolve finds the schema of a local reference, a JSON pointer in the fragment
+func (reader *jsonSchemaReader) resolve(reference string) (interface{}, bool) {
+	if !strings.HasPrefix(reference, "#") {
+		return nil, false
+	}
+	pointer, err := url.PathUnescape(strings.TrimPrefix(reference, "#"))
+	if err != nil {
+		return nil, false
+	}
+	if pointer == "" {
+		return reader.root, true
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, false
+	}
+	current := reader.root
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch value := current.(type) {
+		case map[string]interface{}:
+			next, exists := value[token]
+			if !exists {
+				return nil, false
+			}
+			current = next
+		case []interface{}:
+			var index int
+			if _, err := fmt.Sscanf(token, "%d", &index); err != nil || index < 0 || index >= len(value) {
+				return nil, false
+			}
+			current = value[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// readAllOf merges properties and required properties of branches of "allOf" into the schema
+func (reader *jsonSchemaReader) readAllOf(object map[string]interface{}, path string, name string) *node {
+	merged := make(map[string]interface{})
+	properties := make(map[string]interface{})
+	var required []interface{}
+	merge := func(schema map[string]interface{}) {
+		for key, value := range schema {
+			switch key {
+			case "allOf", "$ref":
+			case "properties":
+				if branchProperties, isObject := value.(map[string]interface{}); isObject {
+					for property, propertySchema := range branchProperties {
+						properties[property] = propertySchema
+					}
+				}
+			case "required":
+				if branchRequired, isArray := value.([]interface{}); isArray {
+					required = append(required, branchRequired...)
+				}
+			case "type":
+				if existing, exists := merged["type"]; exists && fmt.Sprint(existing) != fmt.Sprint(value) {
+					reader.losses.add(path, "\"allOf\" combines different types, only %v is kept in %s", existing, reader.target)
+					continue
+				}
+				merged[key] = value
+			default:
+				if _, exists := merged[key]; !exists {
+					merged[key] = value
+				}
+			}
+		}
+	}
+
+	merge(object)
+	branches, _ := object["allOf"].([]interface{})
+	for index := 0; index < len(branches); index++ {
+		branch := branches[index]
+		// Branches are followed through references, nested "allOf" are merged as well
+		for depth := 0; depth < maxReferenceDepth; depth++ {
+			branchObject, isObject := branch.(map[string]interface{})
+			if !isObject {
+				break
+			}
+			if reference, isString := branchObject["$ref"].(string); isString {
+				resolved, found := reader.resolve(reference)
+				if !found {
+					reader.losses.add(path, "reference %q can't be resolved, any values are accepted in %s", reference, reader.target)
+					break
+				}
+				merge(branchObject)
+				branch = resolved
+				continue
+			}
+			merge(branchObject)
+			if nested, isArray := branchObject["allOf"].([]interface{}); isArray {
+				branches = append(branches, nested...)
+			}
+			break
+		}
+	}
+	if len(properties) > 0 {
+		merged["properties"] = properties
+	}
+	if len(required) > 0 {
+		merged["required"] = required
+	}
+	return reader.read(merged, path, name)
+}
+
+// maxReferenceDepth limits chains of references followed when branches of "allOf" are merged
+const maxReferenceDepth = 16
+
+// readBranches reads "anyOf" and "oneOf" as unions
+func (reader *jsonSchemaReader) readBranches(object map[string]interface{}, path string, name string) *node {
+	for _, keyword := range []string{"type", "properties", "items"} {
+		if _, exists := object[keyword]; exists {
+			reader.losses.add(path, "%q next to \"anyOf\" or \"oneOf\" is not representable in %s", keyword, reader.target)
+		}
+	}
+	var branches []*node
+	for _, keyword := range []string{"anyOf", "oneOf"} {
+		schemas, _ := object[keyword].([]interface{})
+		for index, schema := range schemas {
+			branches = append(branches, reader.read(schema, path, fmt.Sprintf("%s%d", name, index+1)))
+		}
+	}
+	return unionOf(branches, false)
+}
+
+// readEnum reads enums of strings as enums, values of other enums aren't enforced
+func (reader *jsonSchemaReader) readEnum(object map[string]interface{}, path string, name string) *node {
+	values, _ := object["enum"].([]interface{})
+	var symbols []string
+	nullable := false
+	for _, value := range values {
+		switch value := value.(type) {
+		case string:
+			symbols = append(symbols, value)
+		case nil:
+			nullable = true
+		default:
+			reader.losses.add(path, "\"enum\" of values which aren't strings is not representable in %s", reader.target)
+			return reader.readTypes(object, path, name)
+		}
+	}
+	if len(symbols) == 0 {
+		return &node{kind: kindNull}
+	}
+	enum := &node{kind: kindEnum, name: reader.names.take(typeName(name)), symbols: symbols}
+	return unionOf([]*node{enum}, nullable)
+}
+
+// schemaTypes returns types of the schema, types of schemas without "type" are told by their keywords
+func schemaTypes(object map[string]interface{}) []string {
+	switch declared := object["type"].(type) {
+	case string:
+		return []string{declared}
+	case []interface{}:
+		var types []string
+		for _, value := range declared {
+			if schemaType, isString := value.(string); isString {
+				types = append(types, schemaType)
+			}
+		}
+		return types
+	}
+	for _, keyword := range []string{"properties", "additionalProperties", "required"} {
+		if _, exists := object[keyword]; exists {
+			return []string{kindObject}
+		}
+	}
+	for _, keyword := range []string{"items", "prefixItems"} {
+		if _, exists := object[keyword]; exists {
+			return []string{kindArray}
+		}
+	}
+	return nil
+}
+
+func (reader *jsonSchemaReader) readTypes(object map[string]interface{}, path string, name string) *node {
+	types := schemaTypes(object)
+	if len(types) == 0 {
+		return &node{kind: kindAny}
+	}
+	var branches []*node
+	nullable := false
+	for _, schemaType := range types {
+		if schemaType == kindNull {
+			nullable = true
+			continue
+		}
+		branches = append(branches, reader.readType(schemaType, object, path, name))
+	}
+	if len(branches) == 0 {
+		return &node{kind: kindNull}
+	}
+	return unionOf(branches, nullable)
+}
+
+func (reader *jsonSchemaReader) readType(schemaType string, object map[string]interface{}, path string, name string) *node {
+	switch schemaType {
+	case kindObject:
+		return reader.readObject(object, path, name)
+	case kindArray:
+		if _, isTuple := object["prefixItems"]; isTuple {
+			reader.losses.add(path, "tuples (\"prefixItems\") are not representable in %s, items of any type are accepted", reader.target)
+			return &node{kind: kindArray, items: &node{kind: kindAny}}
+		}
+		items, hasItems := object["items"]
+		if !hasItems {
+			return &node{kind: kindArray, items: &node{kind: kindAny}}
+		}
+		return &node{kind: kindArray, items: reader.read(items, path+"/*", name+"Item")}
+	case kindString:
+		if object["contentEncoding"] == "base64" {
+			return &node{kind: kindBytes}
+		}
+		format, _ := object["format"].(string)
+		if format != "" && !representedFormats[format] {
+			reader.losses.add(path, "format %q is not representable in %s", format, reader.target)
+			format = ""
+		}
+		return &node{kind: kindString, format: format}
+	case kindInteger:
+		return &node{kind: kindInteger, int32: isInt32Range(object["minimum"], object["maximum"])}
+	case kindNumber:
+		return &node{kind: kindNumber}
+	case kindBoolean:
+		return &node{kind: kindBoolean}
+	}
+	reader.losses.add(path, "type %q is unknown, any values are accepted in %s", schemaType, reader.target)
+	return &node{kind: kindAny}
+}
+
+// readObject reads objects with properties as named objects and objects without them as maps
+func (reader *jsonSchemaReader) readObject(object map[string]interface{}, path string, name string) *node {
+	properties, _ := object["properties"].(map[string]interface{})
+	additional, hasAdditional := object["additionalProperties"]
+	additionalSchema, additionalIsSchema := additional.(map[string]interface{})
+	if len(properties) == 0 && additional != false {
+		if additionalIsSchema {
+			return &node{kind: kindMap, items: reader.read(additionalSchema, path+"/*", name+"Value")}
+		}
+		return &node{kind: kindMap, items: &node{kind: kindAny}}
+	}
+	if hasAdditional && additional != false {
+		reader.losses.add(path, "additional properties are not representable in %s, they are dropped", reader.target)
+	}
+
+	required := make(map[string]bool)
+	if list, isArray := object["required"].([]interface{}); isArray {
+		for _, property := range list {
+			if propertyName, isString := property.(string); isString {
+				required[propertyName] = true
+			}
+		}
+	}
+	result := &node{kind: kindObject, name: reader.names.take(typeName(name))}
+	for _, property := range sortedKeys(properties) {
+		propertySchema := properties[property]
+		field := &field{name: property, required: required[property]}
+		if propertyObject, isObject := propertySchema.(map[string]interface{}); isObject {
+			field.doc, _ = propertyObject["description"].(string)
+			field.defaultValue, field.hasDefault = propertyObject["default"]
+		}
+		field.node = reader.read(propertySchema, path+"/"+escapePointer(property), property)
+		result.fields = append(result.fields, field)
+	}
+	return result
+}
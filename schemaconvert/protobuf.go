@@ -0,0 +1,503 @@
+package schemaconvert
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/fusioncatltd/fusioncat/protobuf"
+)
+
+const (
+	protobufStructImport    = "google/protobuf/struct.proto"
+	protobufTimestampImport = "google/protobuf/timestamp.proto"
+)
+
+// JSONSchemaToProtobuf converts a JSON schema decoded with json.Decoder.UseNumber to a proto3 file.
+// Objects with properties become messages, the root message is named after its title or the name,
+// roots of other types are wrapped into a message with a single field "value". Optional scalar fields
+// keep their presence with "optional". Fields are numbered in the order of their names, the file declares
+// the package when it is not empty.
+func JSONSchemaToProtobuf(document interface{}, name string, packageName string) (string, []Loss, error) {
+	losses := &lossList{}
+	root := readJSONSchema(document, name, "protobuf", losses)
+	writer := &protobufWriter{
+		losses:  losses,
+		defined: make(map[string]bool),
+		enums:   make(map[string]string),
+		imports: make(map[string]bool),
+	}
+	if root.kind == kindObject {
+		writer.message(root, "")
+	} else {
+		wrapper := &protobufMessage{name: typeName(name), enumValues: make(map[string]bool)}
+		losses.add("", "the root schema is not an object, it is wrapped into message %s with field \"value\"", wrapper.name)
+		writer.defined[wrapper.name] = true
+		writer.messages = append(writer.messages, wrapper)
+		if label, typ, represented := writer.fieldType(wrapper, root, "", true); represented {
+			wrapper.fields = append(wrapper.fields, protobufField{label: label, typ: typ, name: "value"})
+		}
+	}
+	content := writer.render(packageName)
+	if _, err := protobuf.Compile("converted.proto", content, nil); err != nil {
+		return "", nil, fmt.Errorf("converted protobuf schema is invalid: %w", err)
+	}
+	return content, losses.result(), nil
+}
+
+type protobufMessage struct {
+	name   string
+	doc    string
+	fields []protobufField
+	enums  []protobufEnum
+	// Values of enums of the message, they share the scope of the message
+	enumValues map[string]bool
+}
+
+type protobufField struct {
+	label string
+	typ   string
+	name  string
+	doc   string
+}
+
+type protobufEnum struct {
+	name   string
+	doc    string
+	values []string
+}
+
+type protobufWriter struct {
+	losses   *lossList
+	messages []*protobufMessage
+	// Names of defined messages
+	defined map[string]bool
+	// Types of defined enums by name, enums are nested into the first message which uses them
+	enums   map[string]string
+	imports map[string]bool
+}
+
+// message defines the message of an object on its first use and returns its name
+func (writer *protobufWriter) message(n *node, path string) string {
+	if writer.defined[n.name] {
+		return n.name
+	}
+	writer.defined[n.name] = true
+	message := &protobufMessage{name: n.name, doc: n.doc, enumValues: make(map[string]bool)}
+	writer.messages = append(writer.messages, message)
+
+	var required []string
+	names := protobufFieldNames(n.fields, path, writer.losses)
+	for index, field := range n.fields {
+		fieldPath := path + "/" + escapePointer(field.name)
+		if field.required {
+			required = append(required, field.name)
+		}
+		if field.hasDefault {
+			writer.losses.add(fieldPath, "default values are not representable in protobuf, the default is dropped")
+		}
+		label, typ, represented := writer.fieldType(message, field.node, fieldPath, field.required)
+		if !represented {
+			continue
+		}
+		message.fields = append(message.fields, protobufField{label: label, typ: typ, name: names[index], doc: field.doc})
+	}
+	if len(required) > 0 {
+		writer.losses.add(path, "required fields are not enforced by protobuf: %s", strings.Join(required, ", "))
+	}
+	return n.name
+}
+
+// protobufFieldNames makes valid unique names of fields. lowerCamelCase names become snake_case, which
+// the proto3 JSON mapping names the same way.
+func protobufFieldNames(fields []*field, path string, losses *lossList) []string {
+	names := fieldNames(fields, path, losses)
+	taken := make(nameSet)
+	for _, name := range names {
+		taken[name] = true
+	}
+	for index, name := range names {
+		snakeCase := snakeCaseName(name)
+		if snakeCase != name && !taken[snakeCase] && protobuf.JSONName(snakeCase) == fields[index].name {
+			taken[snakeCase] = true
+			names[index] = snakeCase
+		}
+	}
+	return names
+}
+
+func snakeCaseName(name string) string {
+	var builder strings.Builder
+	for index, char := range name {
+		if char >= 'A' && char <= 'Z' {
+			if index > 0 {
+				builder.WriteByte('_')
+			}
+			char += 'a' - 'A'
+		}
+		builder.WriteRune(char)
+	}
+	return builder.String()
+}
+
+// fieldType returns the label and the type of a field, fields of null type aren't represented. Scalar fields
+// which can be missing or null are optional.
+func (writer *protobufWriter) fieldType(message *protobufMessage, n *node, path string, required bool) (string, string, bool) {
+	switch n.kind {
+	case kindNull:
+		writer.losses.add(path, "fields which are always null are not representable in protobuf, the field is dropped")
+		return "", "", false
+	case kindArray:
+		return "repeated", writer.elementType(message, n.items, path+"/*", "items of lists"), true
+	case kindMap:
+		if n.items.kind == kindAny {
+			writer.imports[protobufStructImport] = true
+			return "", "google.protobuf.Struct", true
+		}
+		return "", "map<string, " + writer.elementType(message, n.items, path+"/*", "values of maps") + ">", true
+	case kindUnion:
+		branches, _ := n.nonNullBranches()
+		if len(branches) == 1 {
+			if branches[0].kind == kindArray || branches[0].kind == kindMap {
+				writer.losses.add(path, "null is not distinguished from an empty %s in protobuf", branches[0].kind)
+				return writer.fieldType(message, branches[0], path, true)
+			}
+			typ, isMessage := writer.singularType(message, branches[0], path)
+			if isMessage {
+				return "", typ, true
+			}
+			return "optional", typ, true
+		}
+	}
+	typ, isMessage := writer.singularType(message, n, path)
+	if isMessage || required {
+		return "", typ, true
+	}
+	return "optional", typ, true
+}
+
+// elementType returns the type of items of repeated fields and values of map fields, which can't be
+// repeated, maps or null
+func (writer *protobufWriter) elementType(message *protobufMessage, n *node, path string, elements string) string {
+	branches, nullable := n.nonNullBranches()
+	if nullable && len(branches) == 1 {
+		writer.losses.add(path, "null %s are not representable in protobuf", elements)
+		n = branches[0]
+	}
+	switch n.kind {
+	case kindArray:
+		writer.losses.add(path, "nested lists are not representable in protobuf, items of inner lists are of any type")
+		writer.imports[protobufStructImport] = true
+		return "google.protobuf.ListValue"
+	case kindMap:
+		writer.imports[protobufStructImport] = true
+		if n.items.kind != kindAny {
+			writer.losses.add(path, "maps in %s are not representable in protobuf, their values are of any type", elements)
+		}
+		return "google.protobuf.Struct"
+	}
+	typ, _ := writer.singularType(message, n, path)
+	return typ
+}
+
+// singularType returns the type of a singular value and tells if it is a message type
+func (writer *protobufWriter) singularType(message *protobufMessage, n *node, path string) (string, bool) {
+	switch n.kind {
+	case kindObject:
+		return writer.message(n, path), true
+	case kindEnum:
+		if typ, represented := writer.enum(message, n, path); represented {
+			return typ, false
+		}
+		return "string", false
+	case kindString:
+		switch n.format {
+		case "date-time":
+			writer.imports[protobufTimestampImport] = true
+			return "google.protobuf.Timestamp", true
+		case "":
+		default:
+			writer.losses.add(path, "format %q is not representable in protobuf", n.format)
+		}
+		return "string", false
+	case kindBytes:
+		return "bytes", false
+	case kindInteger:
+		if n.int32 {
+			return "int32", false
+		}
+		return "int64", false
+	case kindNumber:
+		return "double", false
+	case kindBoolean:
+		return "bool", false
+	case kindUnion:
+		writer.losses.add(path, "unions of several types are not representable in protobuf, values are of any type")
+	case kindArray, kindMap:
+		writer.losses.add(path, "nested %ss are not representable in protobuf, values are of any type", n.kind)
+	}
+	writer.imports[protobufStructImport] = true
+	return "google.protobuf.Value", true
+}
+
+// enum nests the enum into the message which uses it first, values of enums share the scope of the message
+// so they must be unique in it
+func (writer *protobufWriter) enum(message *protobufMessage, n *node, path string) (string, bool) {
+	if typ, defined := writer.enums[n.name]; defined {
+		return typ, true
+	}
+	for _, value := range n.symbols {
+		if !identifierPattern.MatchString(value) || message.enumValues[value] {
+			writer.losses.add(path, "enum value %q is not a valid unique protobuf enum value, values of the enum are strings", value)
+			return "", false
+		}
+	}
+	for _, value := range n.symbols {
+		message.enumValues[value] = true
+	}
+	message.enums = append(message.enums, protobufEnum{name: n.name, doc: n.doc, values: n.symbols})
+	writer.enums[n.name] = message.name + "." + n.name
+	writer.losses.add(path, "the first value %q of the enum is the value of missing fields in protobuf", n.symbols[0])
+	return writer.enums[n.name], true
+}
+
+func (writer *protobufWriter) render(packageName string) string {
+	var builder strings.Builder
+	builder.WriteString("syntax = \"proto3\";\n")
+	if packageName != "" {
+		fmt.Fprintf(&builder, "\npackage %s;\n", packageName)
+	}
+	if len(writer.imports) > 0 {
+		imports := make([]string, 0, len(writer.imports))
+		for path := range writer.imports {
+			imports = append(imports, path)
+		}
+		sort.Strings(imports)
+		builder.WriteString("\n")
+		for _, path := range imports {
+			fmt.Fprintf(&builder, "import %q;\n", path)
+		}
+	}
+	for _, message := range writer.messages {
+		builder.WriteString("\n")
+		writeProtobufComment(&builder, message.doc, "")
+		fmt.Fprintf(&builder, "message %s {\n", message.name)
+		for _, enum := range message.enums {
+			writeProtobufComment(&builder, enum.doc, "  ")
+			fmt.Fprintf(&builder, "  enum %s {\n", enum.name)
+			for number, value := range enum.values {
+				fmt.Fprintf(&builder, "    %s = %d;\n", value, number)
+			}
+			builder.WriteString("  }\n\n")
+		}
+		for index, field := range message.fields {
+			writeProtobufComment(&builder, field.doc, "  ")
+			builder.WriteString("  ")
+			if field.label != "" {
+				builder.WriteString(field.label + " ")
+			}
+			fmt.Fprintf(&builder, "%s %s = %d;\n", field.typ, field.name, index+1)
+		}
+		builder.WriteString("}\n")
+	}
+	return builder.String()
+}
+
+func writeProtobufComment(builder *strings.Builder, doc string, indent string) {
+	if doc == "" {
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSpace(doc), "\n") {
+		fmt.Fprintf(builder, "%s// %s\n", indent, strings.TrimSpace(line))
+	}
+}
+
+// ProtobufToJSONSchema converts a protobuf message type to a draft 2020-12 JSON schema of its proto3 JSON
+// mapping. Messages become objects titled with their names, properties are named by JSON names of fields,
+// messages other than the root are defined in "$defs". Well-known types are converted to the JSON values
+// which represent them.
+func ProtobufToJSONSchema(message *protobuf.Message) (string, []Loss, error) {
+	writer := &protobufJSONSchemaWriter{
+		losses:      &lossList{},
+		root:        message,
+		definitions: make(map[string]interface{}),
+		references:  make(map[*protobuf.Message]string),
+		names:       make(nameSet),
+	}
+	document := writer.message(message, "")
+	document["$schema"] = Dialect
+	if len(writer.definitions) > 0 {
+		document["$defs"] = writer.definitions
+	}
+	content, err := marshal(document)
+	if err != nil {
+		return "", nil, err
+	}
+	return content, writer.losses.result(), nil
+}
+
+type protobufJSONSchemaWriter struct {
+	losses      *lossList
+	root        *protobuf.Message
+	definitions map[string]interface{}
+	// References to messages, by message
+	references map[*protobuf.Message]string
+	names      nameSet
+}
+
+// Schemas of well-known types, by full name of the type
+var wellKnownJSONSchemas = map[string]func() map[string]interface{}{
+	"google.protobuf.Timestamp": func() map[string]interface{} {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	},
+	"google.protobuf.Duration": func() map[string]interface{} {
+		return map[string]interface{}{"type": "string", "pattern": `^-?[0-9]+(\.[0-9]+)?s$`}
+	},
+	"google.protobuf.Struct":    func() map[string]interface{} { return map[string]interface{}{"type": "object"} },
+	"google.protobuf.Value":     func() map[string]interface{} { return map[string]interface{}{} },
+	"google.protobuf.ListValue": func() map[string]interface{} { return map[string]interface{}{"type": "array"} },
+	"google.protobuf.Empty": func() map[string]interface{} {
+		return map[string]interface{}{"type": "object", "additionalProperties": false}
+	},
+	"google.protobuf.FieldMask": func() map[string]interface{} { return map[string]interface{}{"type": "string"} },
+	"google.protobuf.Any": func() map[string]interface{} {
+		return map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"@type": map[string]interface{}{"type": "string"}},
+			"required":   []string{"@type"},
+		}
+	},
+	"google.protobuf.DoubleValue": nullableJSONSchema("number"),
+	"google.protobuf.FloatValue":  nullableJSONSchema("number"),
+	"google.protobuf.Int64Value":  nullableJSONSchema("integer"),
+	"google.protobuf.UInt64Value": nullableJSONSchema("integer"),
+	"google.protobuf.Int32Value":  nullableJSONSchema("integer"),
+	"google.protobuf.UInt32Value": nullableJSONSchema("integer"),
+	"google.protobuf.BoolValue":   nullableJSONSchema("boolean"),
+	"google.protobuf.StringValue": nullableJSONSchema("string"),
+	"google.protobuf.BytesValue": func() map[string]interface{} {
+		return map[string]interface{}{"type": []interface{}{"string", "null"}, "contentEncoding": "base64"}
+	},
+}
+
+func nullableJSONSchema(schemaType string) func() map[string]interface{} {
+	return func() map[string]interface{} {
+		return map[string]interface{}{"type": []interface{}{schemaType, "null"}}
+	}
+}
+
+// message defines the message in "$defs" on its first use, the root message is the root schema
+func (writer *protobufJSONSchemaWriter) message(message *protobuf.Message, path string) map[string]interface{} {
+	if reference, defined := writer.references[message]; defined {
+		return map[string]interface{}{"$ref": reference}
+	}
+	shortName := message.Name[strings.LastIndex(message.Name, ".")+1:]
+	var definitionName string
+	if message == writer.root {
+		writer.references[message] = "#"
+	} else {
+		definitionName = writer.names.take(shortName)
+		writer.references[message] = "#/$defs/" + escapePointer(definitionName)
+	}
+
+	properties := make(map[string]interface{})
+	oneofs := make(map[string][]string)
+	var oneofNames []string
+	for _, field := range message.Fields {
+		name := protobuf.JSONName(field.Name)
+		fieldPath := path + "/" + escapePointer(name)
+		property := writer.field(field, fieldPath)
+		if field.Doc != "" {
+			property["description"] = field.Doc
+		}
+		if field.Deprecated {
+			property["deprecated"] = true
+		}
+		properties[name] = property
+		if field.Oneof != "" {
+			if _, exists := oneofs[field.Oneof]; !exists {
+				oneofNames = append(oneofNames, field.Oneof)
+			}
+			oneofs[field.Oneof] = append(oneofs[field.Oneof], name)
+		}
+	}
+	for _, oneof := range oneofNames {
+		writer.losses.add(path, "only one of fields %s of oneof %q can be set, it is not enforced by JSON Schema",
+			strings.Join(oneofs[oneof], ", "), oneof)
+	}
+
+	converted := map[string]interface{}{
+		"type":                 "object",
+		"title":                shortName,
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if message.Doc != "" {
+		converted["description"] = message.Doc
+	}
+	if message == writer.root {
+		return converted
+	}
+	writer.definitions[definitionName] = converted
+	return map[string]interface{}{"$ref": writer.references[message]}
+}
+
+func (writer *protobufJSONSchemaWriter) field(field *protobuf.Field, path string) map[string]interface{} {
+	switch {
+	case field.IsMap():
+		return map[string]interface{}{"type": "object", "additionalProperties": writer.singular(field, path+"/*")}
+	case field.Repeated:
+		return map[string]interface{}{"type": "array", "items": writer.singular(field, path+"/*")}
+	}
+	return writer.singular(field, path)
+}
+
+// singular converts a value of the type of the field
+func (writer *protobufJSONSchemaWriter) singular(field *protobuf.Field, path string) map[string]interface{} {
+	if field.Message != nil {
+		if wellKnown, isWellKnown := wellKnownJSONSchemas[field.Message.Name]; isWellKnown {
+			if field.Message.Name == "google.protobuf.Any" {
+				writer.losses.add(path, "types of messages packed into google.protobuf.Any are not known to JSON Schema")
+			}
+			return wellKnown()
+		}
+		return writer.message(field.Message, path)
+	}
+	if field.Enum != nil {
+		if field.Enum.Name == "google.protobuf.NullValue" {
+			return map[string]interface{}{"type": "null"}
+		}
+		values := make([]string, 0, len(field.Enum.Values))
+		for _, value := range field.Enum.Values {
+			values = append(values, value.Name)
+		}
+		converted := map[string]interface{}{
+			"type":  "string",
+			"title": field.Enum.Name[strings.LastIndex(field.Enum.Name, ".")+1:],
+			"enum":  values,
+		}
+		if field.Enum.Doc != "" {
+			converted["description"] = field.Enum.Doc
+		}
+		return converted
+	}
+
+	switch field.Type {
+	case "double", "float":
+		return map[string]interface{}{"type": "number"}
+	case "int32", "sint32", "sfixed32":
+		return map[string]interface{}{"type": "integer", "minimum": math.MinInt32, "maximum": math.MaxInt32}
+	case "uint32", "fixed32":
+		return map[string]interface{}{"type": "integer", "minimum": 0, "maximum": math.MaxUint32}
+	case "int64", "sint64", "sfixed64":
+		return map[string]interface{}{"type": "integer"}
+	case "uint64", "fixed64":
+		return map[string]interface{}{"type": "integer", "minimum": 0}
+	case "bool":
+		return map[string]interface{}{"type": "boolean"}
+	case "bytes":
+		return map[string]interface{}{"type": "string", "contentEncoding": "base64"}
+	}
+	return map[string]interface{}{"type": "string"}
+}
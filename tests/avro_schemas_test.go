@@ -0,0 +1,203 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fusioncatltd/fusioncat/api/input_contracts"
+	"github.com/fusioncatltd/fusioncat/logic"
+	"github.com/gavv/httpexpect/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAvroSchemas(t *testing.T) {
+	// Clean database before running test
+	CleanDatabase(t)
+
+	h := os.Getenv("TESTSERVER_URL")
+	e := httpexpect.Default(t, h)
+
+	// Create a user and get bearer token
+	userEmail := fmt.Sprintf("test-avro-%s@mail.com", strconv.FormatInt(time.Now().UnixNano(), 10))
+	userPayload := input_contracts.SignInSignUpApiInputContract{
+		Email:    userEmail,
+		Password: "123456789",
+	}
+
+	userSignUpResponse := e.POST("/v1/public/users").
+		WithJSON(userPayload).
+		Expect().
+		Status(http.StatusOK)
+
+	userBearer := userSignUpResponse.Raw().Header.Get("Authorization")
+
+	projectResponse := e.POST("/v1/protected/projects").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateModifyProjectApiInputContract{
+			Name:        fmt.Sprintf("AvroProject%d", time.Now().UnixNano()),
+			Description: "Test project for Avro schemas",
+		}).
+		Expect().
+		Status(http.StatusOK)
+
+	var project logic.ProjectDBSerializerStruct
+	rawProjectReader := projectResponse.Raw().Body
+	defer rawProjectReader.Close()
+	rawProjectBytes, _ := io.ReadAll(rawProjectReader)
+	require.NoError(t, json.Unmarshal(rawProjectBytes, &project))
+
+	userEventV1, err := ReadTestFileString("avroschemas/userEventV1.avsc")
+	require.NoError(t, err)
+	userEventV2, err := ReadTestFileString("avroschemas/userEventV2.avsc")
+	require.NoError(t, err)
+	invalidAvroSchema, err := ReadTestFileString("avroschemas/invalidSchema1UnknownType.avsc")
+	require.NoError(t, err)
+	jsonSchema, err := ReadTestFileString("jsonschemas/validSchema1.json")
+	require.NoError(t, err)
+
+	// Test 1: Avro schemas are created like JSON schemas
+	createdSchema := e.POST("/v1/protected/projects/"+project.ID+"/schemas").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateSchemaApiInputContract{
+			Name:        "user_event",
+			Description: "User event in Avro",
+			Type:        "avro",
+			Schema:      userEventV1,
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	createdSchema.HasValue("type", logic.SchemaTypeAvro)
+	createdSchema.HasValue("version", 1)
+	schemaID := createdSchema.Value("id").String().Raw()
+
+	// Test 2: Invalid Avro schemas and unsupported types are rejected
+	invalidResponse := e.POST("/v1/protected/projects/"+project.ID+"/schemas").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateSchemaApiInputContract{
+			Name:   "broken_event",
+			Type:   "avro",
+			Schema: invalidAvroSchema,
+		}).
+		Expect().
+		Status(http.StatusUnprocessableEntity).
+		JSON().Object().Value("error").Array().Value(0).Object()
+	invalidResponse.HasValue("field", "schema")
+	invalidResponse.Value("message").String().Contains("Invalid Avro schema").Contains(`unknown type "uuid"`)
+
+	e.POST("/v1/protected/projects/"+project.ID+"/schemas").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateSchemaApiInputContract{
+			Name:   "json_schema_as_avro",
+			Type:   "avro",
+			Schema: jsonSchema,
+		}).
+		Expect().
+		Status(http.StatusUnprocessableEntity)
+
+	e.POST("/v1/protected/projects/"+project.ID+"/schemas").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateSchemaApiInputContract{
+			Name:   "xml_event",
+			Type:   "xsd",
+			Schema: userEventV1,
+		}).
+		Expect().
+		Status(http.StatusUnprocessableEntity)
+
+	// Test 3: New versions are validated against the type of the schema
+	e.PUT("/v1/protected/schemas/"+schemaID).
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ModifySchemaApiInputContract{Schema: invalidAvroSchema}).
+		Expect().
+		Status(http.StatusUnprocessableEntity)
+
+	e.PUT("/v1/protected/schemas/"+schemaID).
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ModifySchemaApiInputContract{Schema: jsonSchema}).
+		Expect().
+		Status(http.StatusUnprocessableEntity)
+
+	e.PUT("/v1/protected/schemas/"+schemaID).
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ModifySchemaApiInputContract{Schema: userEventV2}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().HasValue("version", 2)
+
+	versions := e.GET("/v1/protected/schemas/"+schemaID+"/versions").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array()
+	versions.Length().IsEqual(2)
+	require.JSONEq(t, userEventV1, versions.Value(0).Object().Value("schema").String().Raw())
+	require.JSONEq(t, userEventV2, versions.Value(1).Object().Value("schema").String().Raw())
+
+	// Test 4: Go code is generated without external tools
+	generatedCode := e.GET("/v1/protected/schemas/"+schemaID+"/code/go").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		Body().Raw()
+	require.Contains(t, generatedCode, "// Event published when a user changes")
+	require.Contains(t, generatedCode, "type UserEvent struct")
+	require.Contains(t, generatedCode, "type UserEventUserStatus string")
+	require.Contains(t, generatedCode, `UserEventUserStatusACTIVE  UserEventUserStatus = "ACTIVE"`)
+	require.Contains(t, generatedCode, "DisplayName *string")
+	require.Contains(t, generatedCode, `json:"created_at" avro:"created_at"`)
+	require.True(t, strings.Contains(generatedCode, "int64") && strings.Contains(generatedCode, "// timestamp-millis"))
+
+	e.GET("/v1/protected/schemas/"+schemaID+"/code/typescript").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusBadRequest).
+		JSON().Object().Value("error").String().Contains("only for go")
+
+	// Test 5: Avro schemas can be imported with the rest of the architecture
+	avroImportYAML, err := ReadTestFileString("imports/valid_avro.yaml")
+	require.NoError(t, err)
+
+	importProjectResponse := e.POST("/v1/protected/projects").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateModifyProjectApiInputContract{
+			Name:        fmt.Sprintf("AvroImportProject%d", time.Now().UnixNano()),
+			Description: "Test project for Avro imports",
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	importProjectID := importProjectResponse.Value("id").String().Raw()
+
+	brokenImportYAML := strings.Replace(avroImportYAML, `"type": "string"`, `"type": "text"`, 1)
+	brokenImportErrors := e.POST("/v1/protected/projects/"+importProjectID+"/imports/validator").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ImportFileInputContract{YAML: brokenImportYAML}).
+		Expect().
+		Status(http.StatusConflict).
+		JSON().Object().Value("errors").Array()
+	brokenImportErrors.Length().IsEqual(1)
+	brokenImportErrors.Value(0).Object().HasValue("code", logic.ImportErrCodeInvalidSchema)
+	brokenImportErrors.Value(0).Object().Value("message").String().Contains("invalid Avro schema")
+
+	e.POST("/v1/protected/projects/"+importProjectID+"/imports").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ImportFileInputContract{YAML: avroImportYAML}).
+		Expect().
+		Status(http.StatusOK)
+
+	importedSchemas := e.GET("/v1/protected/projects/"+importProjectID+"/schemas").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array()
+	importedSchemas.Length().IsEqual(1)
+	importedSchemas.Value(0).Object().HasValue("type", logic.SchemaTypeAvro)
+}
@@ -0,0 +1,212 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fusioncatltd/fusioncat/api/input_contracts"
+	"github.com/fusioncatltd/fusioncat/logic"
+	"github.com/gavv/httpexpect/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFusionlangImports(t *testing.T) {
+	// Clean database before running test
+	CleanDatabase(t)
+
+	h := os.Getenv("TESTSERVER_URL")
+	e := httpexpect.Default(t, h)
+
+	// Create a user and get bearer token
+	userEmail := fmt.Sprintf("test-fusionlang-%s@mail.com", strconv.FormatInt(time.Now().UnixNano(), 10))
+	userPayload := input_contracts.SignInSignUpApiInputContract{
+		Email:    userEmail,
+		Password: "123456789",
+	}
+
+	userSignUpResponse := e.POST("/v1/public/users").
+		WithJSON(userPayload).
+		Expect().
+		Status(http.StatusOK)
+
+	userBearer := userSignUpResponse.Raw().Header.Get("Authorization")
+
+	projectResponse := e.POST("/v1/protected/projects").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateModifyProjectApiInputContract{
+			Name:        fmt.Sprintf("FusionlangProject%d", time.Now().UnixNano()),
+			Description: "Test project for Fusionlang imports",
+		}).
+		Expect().
+		Status(http.StatusOK)
+
+	var project logic.ProjectDBSerializerStruct
+	rawProjectReader := projectResponse.Raw().Body
+	defer rawProjectReader.Close()
+	rawProjectBytes, _ := io.ReadAll(rawProjectReader)
+	require.NoError(t, json.Unmarshal(rawProjectBytes, &project))
+
+	bundleFiles := loadImportBundleFiles(t, "fusionlang")
+	rootFile := bundleFiles["fusioncat.fusion"]
+	includedFiles := make(map[string]string)
+	for name, content := range bundleFiles {
+		if name != "fusioncat.fusion" {
+			includedFiles[name] = content
+		}
+	}
+
+	// Test 1: Syntax errors are reported with positions and suggestions
+	brokenSource := strings.Replace(rootFile, "server kafka_server kafka", "sever kafka_server kafka", 1)
+	syntaxResponse := e.POST("/v1/protected/projects/"+project.ID+"/imports/validator").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ImportFileInputContract{YAML: brokenSource, Format: "fusionlang", Files: includedFiles}).
+		Expect().
+		Status(http.StatusConflict)
+
+	syntaxError := syntaxResponse.JSON().Object().Value("errors").Array().Value(0).Object()
+	syntaxError.HasValue("code", logic.ImportErrCodeInvalidFusionlang)
+	syntaxError.HasValue("line", 5)
+	syntaxError.HasValue("column", 1)
+	syntaxError.Value("message").String().Contains("did you mean 'server'?")
+
+	// Test 2: Reference errors found in included Fusionlang files point at the .fusion file
+	brokenFiles := make(map[string]string)
+	for name, content := range includedFiles {
+		brokenFiles[name] = content
+	}
+	brokenFiles["teams/billing.fusion"] = strings.Replace(brokenFiles["teams/billing.fusion"],
+		"sends invoice_issued", "sends invoice_sent", 1)
+
+	referenceResponse := e.POST("/v1/protected/projects/"+project.ID+"/imports/validator").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ImportFileInputContract{YAML: rootFile, Format: "fusionlang", Files: brokenFiles}).
+		Expect().
+		Status(http.StatusConflict)
+
+	referenceError := referenceResponse.JSON().Object().Value("errors").Array().Value(0).Object()
+	referenceError.HasValue("code", logic.ImportErrCodeUnknownMessage)
+	referenceError.HasValue("file", "teams/billing.fusion")
+	referenceError.HasValue("line", 22)
+	referenceError.HasValue("column", 11)
+
+	// Test 3: Unknown formats of the inline root are rejected
+	e.POST("/v1/protected/projects/"+project.ID+"/imports/validator").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ImportFileInputContract{YAML: rootFile, Format: "toml"}).
+		Expect().
+		Status(http.StatusUnprocessableEntity)
+
+	// Test 4: Validate and import the architecture, fusioncat.fusion is detected as the root file
+	e.POST("/v1/protected/projects/"+project.ID+"/imports/validator").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ImportFileInputContract{YAML: rootFile, Format: "fusionlang", Files: includedFiles}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().HasValue("message", "YAML is valid")
+
+	multipartRequest := e.POST("/v1/protected/projects/"+project.ID+"/imports").
+		WithHeader("Authorization", userBearer).
+		WithMultipart()
+	for name, content := range bundleFiles {
+		multipartRequest = multipartRequest.WithFileBytes("files", name, []byte(content))
+	}
+	multipartRequest.Expect().
+		Status(http.StatusOK)
+
+	schemasResponse := e.GET("/v1/protected/projects/"+project.ID+"/schemas").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK)
+
+	var schemas []logic.SchemaDBSerializerStruct
+	rawSchemasReader := schemasResponse.Raw().Body
+	defer rawSchemasReader.Close()
+	rawSchemasBytes, _ := io.ReadAll(rawSchemasReader)
+	require.NoError(t, json.Unmarshal(rawSchemasBytes, &schemas))
+	require.Len(t, schemas, 2)
+	require.Equal(t, "invoice", schemas[0].Name)
+	require.Contains(t, schemas[0].Schema, "invoice_id")
+	require.Equal(t, "order", schemas[1].Name)
+	require.Equal(t, bundleFiles["schemas/order.json"], schemas[1].Schema)
+
+	e.GET("/v1/protected/projects/"+project.ID+"/apps").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array().Length().IsEqual(2)
+}
+
+func TestFusionlangFormatter(t *testing.T) {
+	// Clean database before running test
+	CleanDatabase(t)
+
+	h := os.Getenv("TESTSERVER_URL")
+	e := httpexpect.Default(t, h)
+
+	// Create a user and get bearer token
+	userEmail := fmt.Sprintf("test-formatter-%s@mail.com", strconv.FormatInt(time.Now().UnixNano(), 10))
+	userPayload := input_contracts.SignInSignUpApiInputContract{
+		Email:    userEmail,
+		Password: "123456789",
+	}
+
+	userSignUpResponse := e.POST("/v1/public/users").
+		WithJSON(userPayload).
+		Expect().
+		Status(http.StatusOK)
+
+	userBearer := userSignUpResponse.Raw().Header.Get("Authorization")
+
+	// Test 1: Sources are printed in the canonical layout, comments are kept
+	unformatted := "version 1\n" +
+		"server kafka_server kafka { description \"Main\"  // primary cluster\n" +
+		"  resource orders_topic topic readwrite\n" +
+		"}\n" +
+		"message order_created { schema order }\n"
+	expected := "version 1\n" +
+		"\n" +
+		"server kafka_server kafka {\n" +
+		"    description \"Main\" // primary cluster\n" +
+		"    resource orders_topic topic readwrite\n" +
+		"}\n" +
+		"\n" +
+		"message order_created {\n" +
+		"    schema order\n" +
+		"}\n"
+
+	e.POST("/v1/protected/fusionlang/formatter").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.FusionlangSourceInputContract{Source: unformatted}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().HasValue("source", expected)
+
+	// Formatting is idempotent
+	e.POST("/v1/protected/fusionlang/formatter").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.FusionlangSourceInputContract{Source: expected}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().HasValue("source", expected)
+
+	// Test 2: Every syntax error is reported, not only the first one
+	errorsResponse := e.POST("/v1/protected/fusionlang/formatter").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.FusionlangSourceInputContract{
+			Source: "server kafka_server {\n}\n\nmessage order_created {\n    schema\n}\n",
+		}).
+		Expect().
+		Status(http.StatusConflict)
+
+	syntaxErrors := errorsResponse.JSON().Object().Value("errors").Array()
+	syntaxErrors.Length().IsEqual(2)
+	syntaxErrors.Value(0).Object().HasValue("line", 1).HasValue("column", 21)
+	syntaxErrors.Value(1).Object().HasValue("line", 6).HasValue("column", 1)
+}
@@ -0,0 +1,408 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fusioncatltd/fusioncat/api/input_contracts"
+	"github.com/fusioncatltd/fusioncat/logic"
+	"github.com/gavv/httpexpect/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// Helper function to run git in a directory and return its trimmed output
+func runGit(t *testing.T, dir string, args ...string) string {
+	cmd := exec.Command("git", append([]string{
+		"-C", dir,
+		"-c", "user.name=Fusioncat Tests",
+		"-c", "user.email=tests@fusioncat.dev",
+		"-c", "init.defaultBranch=main",
+	}, args...)...)
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, "git %s: %s", strings.Join(args, " "), output)
+	return strings.TrimSpace(string(output))
+}
+
+// Helper function to commit files into the working copy and push them to the bare repository
+func commitAndPush(t *testing.T, workDir string, files map[string]string, message string) string {
+	for name, content := range files {
+		filePath := filepath.Join(workDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(filePath), 0o755))
+		require.NoError(t, os.WriteFile(filePath, []byte(content), 0o644))
+	}
+	runGit(t, workDir, "add", "-A")
+	runGit(t, workDir, "commit", "-m", message)
+	runGit(t, workDir, "push", "origin", "main")
+	return runGit(t, workDir, "rev-parse", "HEAD")
+}
+
+// Helper function to create a directory for test repositories inside the repositories root of git
+// synchronization, the test is skipped when git synchronization is disabled
+func gitRepositoriesTestDir(t *testing.T) string {
+	repositoriesRoot := os.Getenv("GIT_SYNC_REPOSITORIES_ROOT")
+	if repositoriesRoot == "" {
+		t.Skip("GIT_SYNC_REPOSITORIES_ROOT is not set, git synchronization is disabled")
+	}
+	repositoryDir, err := os.MkdirTemp(repositoriesRoot, "fusioncat-test-")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(repositoryDir) })
+	return repositoryDir
+}
+
+func TestGitSync(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	// Clean database before running test
+	CleanDatabase(t)
+
+	h := os.Getenv("TESTSERVER_URL")
+	e := httpexpect.Default(t, h)
+
+	// Create a user and get bearer token
+	userEmail := fmt.Sprintf("test-git-sync-%s@mail.com", strconv.FormatInt(time.Now().UnixNano(), 10))
+	userPayload := input_contracts.SignInSignUpApiInputContract{
+		Email:    userEmail,
+		Password: "123456789",
+	}
+
+	userSignUpResponse := e.POST("/v1/public/users").
+		WithJSON(userPayload).
+		Expect().
+		Status(http.StatusOK)
+
+	userBearer := userSignUpResponse.Raw().Header.Get("Authorization")
+
+	projectResponse := e.POST("/v1/protected/projects").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateModifyProjectApiInputContract{
+			Name:        fmt.Sprintf("GitSyncProject%d", time.Now().UnixNano()),
+			Description: "Test project for git synchronization",
+		}).
+		Expect().
+		Status(http.StatusOK)
+
+	var project logic.ProjectDBSerializerStruct
+	rawProjectReader := projectResponse.Raw().Body
+	defer rawProjectReader.Close()
+	rawProjectBytes, _ := io.ReadAll(rawProjectReader)
+	require.NoError(t, json.Unmarshal(rawProjectBytes, &project))
+
+	// Prepare a bare repository with architecture files in a subdirectory
+	repositoryDir := gitRepositoriesTestDir(t)
+	bareRepository := filepath.Join(repositoryDir, "architecture.git")
+	workDir := filepath.Join(repositoryDir, "work")
+	runGit(t, repositoryDir, "init", "--bare", bareRepository)
+	runGit(t, repositoryDir, "clone", bareRepository, workDir)
+	runGit(t, workDir, "checkout", "-B", "main")
+
+	validYAML, err := ReadTestFileString("imports/valid_basic.yaml")
+	require.NoError(t, err)
+	firstCommit := commitAndPush(t, workDir, map[string]string{
+		"architecture/fusioncat.yaml": validYAML,
+		"README.md":                   "Architecture of the system",
+	}, "Initial architecture")
+
+	// Test 1: Synchronization is not configured yet
+	e.GET("/v1/protected/projects/"+project.ID+"/git-sync").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusNotFound)
+
+	e.POST("/v1/protected/projects/"+project.ID+"/git-sync/syncs").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusNotFound)
+
+	// Test 2: Repositories and branches are checked when synchronization is configured
+	e.PUT("/v1/protected/projects/"+project.ID+"/git-sync").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ConfigureGitSyncInputContract{
+			RepositoryPath: bareRepository,
+			Branch:         "does-not-exist",
+		}).
+		Expect().
+		Status(http.StatusConflict)
+
+	e.PUT("/v1/protected/projects/"+project.ID+"/git-sync").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ConfigureGitSyncInputContract{
+			RepositoryPath: repositoryDir,
+			Branch:         "main",
+		}).
+		Expect().
+		Status(http.StatusConflict)
+
+	// Repositories outside of the repositories root can't be followed, also through symbolic links
+	e.PUT("/v1/protected/projects/"+project.ID+"/git-sync").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ConfigureGitSyncInputContract{
+			RepositoryPath: filepath.Dir(filepath.Clean(os.Getenv("GIT_SYNC_REPOSITORIES_ROOT"))),
+			Branch:         "main",
+		}).
+		Expect().
+		Status(http.StatusConflict).
+		JSON().Object().HasValue("error", logic.ErrGitRepositoryNotAllowed.Error())
+
+	outsideDir := t.TempDir()
+	if relativePath, err := filepath.Rel(os.Getenv("GIT_SYNC_REPOSITORIES_ROOT"), outsideDir); err == nil &&
+		strings.HasPrefix(relativePath, "..") {
+		outsideRepository := filepath.Join(outsideDir, "outside.git")
+		runGit(t, outsideDir, "init", "--bare", outsideRepository)
+		linkedRepository := filepath.Join(repositoryDir, "linked.git")
+		require.NoError(t, os.Symlink(outsideRepository, linkedRepository))
+
+		e.PUT("/v1/protected/projects/"+project.ID+"/git-sync").
+			WithHeader("Authorization", userBearer).
+			WithJSON(input_contracts.ConfigureGitSyncInputContract{
+				RepositoryPath: linkedRepository,
+				Branch:         "main",
+			}).
+			Expect().
+			Status(http.StatusConflict).
+			JSON().Object().HasValue("error", logic.ErrGitRepositoryNotAllowed.Error())
+	}
+
+	e.PUT("/v1/protected/projects/"+project.ID+"/git-sync").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ConfigureGitSyncInputContract{
+			RepositoryPath:      bareRepository,
+			Branch:              "main",
+			PollIntervalSeconds: 5,
+		}).
+		Expect().
+		Status(http.StatusUnprocessableEntity)
+
+	settings := e.PUT("/v1/protected/projects/"+project.ID+"/git-sync").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ConfigureGitSyncInputContract{
+			RepositoryPath: bareRepository,
+			Branch:         "main",
+			Subdirectory:   "architecture",
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	settings.HasValue("repository_path", bareRepository)
+	settings.HasValue("branch", "main")
+	settings.HasValue("subdirectory", "architecture")
+	settings.NotContainsKey("last_synced_commit_sha")
+
+	// Test 3: The first synchronization creates all entities and records the commit
+	firstSync := e.POST("/v1/protected/projects/"+project.ID+"/git-sync/syncs").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	firstSync.HasValue("status", logic.ImportJobStatusCompleted)
+	firstSync.HasValue("mode", logic.ImportJobModeSync)
+	firstSync.HasValue("commit_sha", firstCommit)
+	firstCounts := firstSync.Value("counts").Object()
+	firstCounts.HasValue("servers", 1)
+	firstCounts.HasValue("resources", 2)
+	firstCounts.HasValue("schemas", 1)
+	firstCounts.HasValue("schema_versions", 1)
+	firstCounts.HasValue("messages", 1)
+	firstCounts.HasValue("apps", 1)
+	firstCounts.HasValue("connections", 2)
+
+	// Synchronizations can't be reverted
+	e.POST("/v1/protected/imports/"+firstSync.Value("id").String().Raw()+"/revert").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusConflict)
+
+	schemas := e.GET("/v1/protected/projects/"+project.ID+"/schemas").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array()
+	schemas.Length().IsEqual(1)
+	schemaID := schemas.Value(0).Object().Value("id").String().Raw()
+
+	e.GET("/v1/protected/schemas/"+schemaID+"/versions").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array().Value(0).Object().HasValue("commit_sha", firstCommit)
+
+	e.GET("/v1/protected/projects/"+project.ID+"/git-sync").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().HasValue("last_synced_commit_sha", firstCommit)
+
+	// Test 4: Synchronizing the same commit again changes nothing
+	repeatedSync := e.POST("/v1/protected/projects/"+project.ID+"/git-sync/syncs").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	repeatedSync.HasValue("commit_sha", firstCommit)
+	repeatedCounts := repeatedSync.Value("counts").Object()
+	repeatedCounts.HasValue("servers", 0)
+	repeatedCounts.HasValue("schemas", 0)
+	repeatedCounts.HasValue("schema_versions", 0)
+	repeatedCounts.HasValue("connections", 0)
+
+	// Test 5: Changed schemas get a new version with the commit SHA, new entities are created
+	updatedYAML := strings.Replace(validYAML,
+		`"name": {"type": "string"}`,
+		`"name": {"type": "string"},
+          "email": {"type": "string"}`, 1)
+	updatedYAML = strings.Replace(updatedYAML, "apps:\n", `apps:
+  - name: AuditApp
+    description: Audit application
+    receives:
+      - message: UserMessage
+        resource: async+kafka://kafka_server@readwrite/topic/users_topic
+`, 1)
+	updatedYAML = strings.Replace(updatedYAML, "description: Main Kafka server", "description: Shared Kafka cluster", 1)
+	updatedYAML = strings.Replace(updatedYAML, "mode: read\n", "mode: write\n", 1)
+	updatedYAML = strings.Replace(updatedYAML, "description: User message", "description: User changed", 1)
+	require.NotEqual(t, validYAML, updatedYAML)
+	secondCommit := commitAndPush(t, workDir, map[string]string{
+		"architecture/fusioncat.yaml": updatedYAML,
+	}, "Add email to users and the audit app")
+
+	secondSync := e.POST("/v1/protected/projects/"+project.ID+"/git-sync/syncs").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	secondSync.HasValue("commit_sha", secondCommit)
+	secondCounts := secondSync.Value("counts").Object()
+	secondCounts.HasValue("servers", 0)
+	secondCounts.HasValue("schemas", 0)
+	secondCounts.HasValue("schema_versions", 1)
+	secondCounts.HasValue("apps", 1)
+	secondCounts.HasValue("connections", 1)
+
+	versions := e.GET("/v1/protected/schemas/"+schemaID+"/versions").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array()
+	versions.Length().IsEqual(2)
+	versions.Value(1).Object().HasValue("version", 2)
+	versions.Value(1).Object().HasValue("commit_sha", secondCommit)
+
+	e.GET("/v1/protected/projects/"+project.ID+"/apps").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array().Length().IsEqual(2)
+
+	// Existing entities are updated to match the files, messages move to the new version of their schema
+	servers := e.GET("/v1/protected/projects/"+project.ID+"/servers").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array()
+	servers.Length().IsEqual(1)
+	servers.Value(0).Object().HasValue("description", "Shared Kafka cluster")
+	serverID := servers.Value(0).Object().Value("id").String().Raw()
+
+	resourceModes := map[string]string{}
+	resources := e.GET("/v1/protected/servers/"+serverID+"/resources").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array()
+	resources.Length().IsEqual(2)
+	for _, resource := range resources.Iter() {
+		resourceModes[resource.Object().Value("name").String().Raw()] = resource.Object().Value("mode").String().Raw()
+	}
+	require.Equal(t, map[string]string{"users_topic": "readwrite", "orders_topic": "write"}, resourceModes)
+
+	messages := e.GET("/v1/protected/projects/"+project.ID+"/messages").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array()
+	messages.Length().IsEqual(1)
+	messages.Value(0).Object().HasValue("description", "User changed")
+	messages.Value(0).Object().HasValue("schema_version", 2)
+
+	// Test 6: A specific revision can be synchronized, invalid files fail the synchronization
+	brokenCommit := commitAndPush(t, workDir, map[string]string{
+		"architecture/fusioncat.yaml": "version: 2\n",
+	}, "Break the architecture")
+
+	failedSync := e.POST("/v1/protected/projects/"+project.ID+"/git-sync/syncs").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.GitSyncInputContract{Revision: brokenCommit}).
+		Expect().
+		Status(http.StatusConflict).
+		JSON().Object()
+	failedSync.HasValue("status", logic.ImportJobStatusFailed)
+	failedSync.HasValue("commit_sha", brokenCommit)
+	failedSync.Value("errors").Array().NotEmpty()
+
+	// Types of existing schemas can't be changed
+	avroYAML := strings.Replace(updatedYAML, "type: jsonschema", "type: avro", 1)
+	avroYAML = strings.Replace(avroYAML, `"type": "object",`, `"type": "record",
+        "name": "User",
+        "fields": [{"name": "id", "type": "string"}],`, 1)
+	require.NotEqual(t, updatedYAML, avroYAML)
+	typeChangeCommit := commitAndPush(t, workDir, map[string]string{
+		"architecture/fusioncat.yaml": avroYAML,
+	}, "Change users to Avro")
+
+	typeChangeSync := e.POST("/v1/protected/projects/"+project.ID+"/git-sync/syncs").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.GitSyncInputContract{Revision: typeChangeCommit}).
+		Expect().
+		Status(http.StatusConflict).
+		JSON().Object()
+	typeChangeSync.HasValue("status", logic.ImportJobStatusFailed)
+	typeChangeSync.Value("errors").Array().Value(0).Object().
+		HasValue("code", logic.ImportErrCodeSchemaTypeChanged).
+		HasValue("path", "schemas[0].type")
+
+	// The last successfully synchronized commit is kept
+	e.GET("/v1/protected/projects/"+project.ID+"/git-sync").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().HasValue("last_synced_commit_sha", secondCommit)
+
+	e.POST("/v1/protected/projects/"+project.ID+"/git-sync/syncs").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.GitSyncInputContract{Revision: "does-not-exist"}).
+		Expect().
+		Status(http.StatusConflict)
+
+	// Synchronizations are listed in the import history
+	e.GET("/v1/protected/projects/"+project.ID+"/imports").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array().Length().IsEqual(5)
+
+	// Test 7: Synchronization can be removed
+	e.DELETE("/v1/protected/projects/"+project.ID+"/git-sync").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK)
+
+	e.DELETE("/v1/protected/projects/"+project.ID+"/git-sync").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusNotFound)
+
+	e.POST("/v1/protected/projects/"+project.ID+"/git-sync/syncs").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusNotFound)
+}
@@ -0,0 +1,235 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fusioncatltd/fusioncat/api/input_contracts"
+	"github.com/fusioncatltd/fusioncat/logic"
+	"github.com/gavv/httpexpect/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// Size of the generated architecture, in total about 5,000 entities
+const (
+	largeImportServers           = 50
+	largeImportResourcesByServer = 20
+	largeImportSchemas           = 1200
+	largeImportApps              = 500
+	largeImportEntities          = largeImportServers + largeImportServers*largeImportResourcesByServer +
+		2*largeImportSchemas + largeImportApps + 2*largeImportApps
+)
+
+// Helper function to generate an architecture YAML with servers, resources, schemas with a message each
+// and apps which send and receive these messages
+func generateLargeArchitectureYAML() string {
+	var builder strings.Builder
+	builder.WriteString("version: 1\nservers:\n")
+	for server := 0; server < largeImportServers; server++ {
+		fmt.Fprintf(&builder, "  - name: server_%d\n    type: kafka\n    description: Kafka server %d\n    resources:\n", server, server)
+		for resource := 0; resource < largeImportResourcesByServer; resource++ {
+			fmt.Fprintf(&builder, "      - name: topic_%d\n        mode: readwrite\n        type: topic\n        description: Topic %d\n",
+				resource, resource)
+		}
+	}
+
+	builder.WriteString("schemas:\n")
+	for schema := 0; schema < largeImportSchemas; schema++ {
+		fmt.Fprintf(&builder, "  - name: Schema%d\n    type: jsonschema\n    version: 1\n    description: Schema %d\n    schema: |\n", schema, schema)
+		fmt.Fprintf(&builder, "      {\"type\": \"object\", \"properties\": {\"id\": {\"type\": \"string\"}, \"field%d\": {\"type\": \"integer\"}}}\n", schema)
+	}
+
+	builder.WriteString("messages:\n")
+	for message := 0; message < largeImportSchemas; message++ {
+		fmt.Fprintf(&builder, "  - name: Message%d\n    description: Message %d\n    schema:\n      name: Schema%d\n", message, message, message)
+	}
+
+	builder.WriteString("apps:\n")
+	for app := 0; app < largeImportApps; app++ {
+		resource := fmt.Sprintf("async+kafka://server_%d@readwrite/topic/topic_%d",
+			app%largeImportServers, app%largeImportResourcesByServer)
+		fmt.Fprintf(&builder, "  - name: App%d\n    description: Application %d\n", app, app)
+		fmt.Fprintf(&builder, "    sends:\n      - message: Message%d\n        resource: %s\n", (2*app)%largeImportSchemas, resource)
+		fmt.Fprintf(&builder, "    receives:\n      - message: Message%d\n        resource: %s\n", (2*app+1)%largeImportSchemas, resource)
+	}
+	return builder.String()
+}
+
+func TestLargeProjectImport(t *testing.T) {
+	if testing.Short() {
+		t.Skip("large import is skipped in short mode")
+	}
+
+	// Clean database before running test
+	CleanDatabase(t)
+
+	h := os.Getenv("TESTSERVER_URL")
+	e := httpexpect.Default(t, h)
+
+	userBearer, createProject := PrepareTestUserAndProject(t, e, "test-bulk-import", "Test project for bulk imports")
+	project := createProject("BulkImportProject")
+	largeYAML := generateLargeArchitectureYAML()
+
+	// Test 1: All entities of a large architecture are imported
+	importID := e.POST("/v1/protected/projects/"+project.ID+"/imports").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ImportFileInputContract{YAML: largeYAML}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("import_id").String().NotEmpty().Raw()
+
+	importJob := e.GET("/v1/protected/imports/"+importID).
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	importJob.HasValue("status", logic.ImportJobStatusCompleted)
+	counts := importJob.Value("counts").Object()
+	counts.HasValue("servers", largeImportServers)
+	counts.HasValue("resources", largeImportServers*largeImportResourcesByServer)
+	counts.HasValue("schemas", largeImportSchemas)
+	counts.HasValue("schema_versions", largeImportSchemas)
+	counts.HasValue("messages", largeImportSchemas)
+	counts.HasValue("apps", largeImportApps)
+	counts.HasValue("connections", 2*largeImportApps)
+
+	e.GET("/v1/protected/projects/"+project.ID+"/schemas").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array().Length().IsEqual(largeImportSchemas)
+
+	e.GET("/v1/protected/projects/"+project.ID+"/messages").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array().Length().IsEqual(largeImportSchemas)
+
+	// Test 2: Every name already used in the project is reported by the validation
+	e.POST("/v1/protected/projects/"+project.ID+"/imports/validator").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ImportFileInputContract{YAML: largeYAML}).
+		Expect().
+		Status(http.StatusConflict).
+		JSON().Object().Value("errors").Array().Length().IsEqual(largeImportServers + 2*largeImportSchemas + largeImportApps)
+}
+
+// Helper function to create the generated architecture one entity at a time through the managers of
+// entities, checking every name with its own query first, like imports did before batching
+func importLargeArchitecturePerEntity(projectID uuid.UUID, userID uuid.UUID) error {
+	serversManager := logic.ServersObjectsManager{}
+	resourcesManager := logic.ResourcesObjectsManager{}
+	schemasManager := logic.SchemaObjectsManager{}
+	messagesManager := logic.MessagesObjectsManager{}
+	appsManager := logic.AppsObjectsManager{}
+	connectionsManager := logic.AppsResourcesMessagesObjectsManager{}
+
+	for server := 0; server < largeImportServers; server++ {
+		serversManager.CanNameBeUsed(fmt.Sprintf("server_%d", server), projectID)
+	}
+	for schema := 0; schema < largeImportSchemas; schema++ {
+		schemasManager.CanNameBeUsed(fmt.Sprintf("Schema%d", schema), projectID)
+		messagesManager.CanNameBeUsed(fmt.Sprintf("Message%d", schema), projectID)
+	}
+	for app := 0; app < largeImportApps; app++ {
+		appsManager.CanNameBeUsed(fmt.Sprintf("App%d", app), projectID)
+	}
+
+	resourceIDs := make(map[string]uuid.UUID)
+	for server := 0; server < largeImportServers; server++ {
+		serverObject, err := serversManager.CreateANewServer(fmt.Sprintf("server_%d", server),
+			fmt.Sprintf("Kafka server %d", server), "kafka", projectID, userID)
+		if err != nil {
+			return err
+		}
+		for resource := 0; resource < largeImportResourcesByServer; resource++ {
+			resourceObject, err := resourcesManager.CreateANewResource(serverObject.GetID(), projectID,
+				fmt.Sprintf("topic_%d", resource), "readwrite", "topic", fmt.Sprintf("Topic %d", resource), userID)
+			if err != nil {
+				return err
+			}
+			resourceIDs[fmt.Sprintf("%d.%d", server, resource)] = resourceObject.GetID()
+		}
+	}
+
+	messageIDs := make([]uuid.UUID, largeImportSchemas)
+	for schema := 0; schema < largeImportSchemas; schema++ {
+		schemaObject, err := schemasManager.CreateANewSchema(fmt.Sprintf("Schema%d", schema), fmt.Sprintf("Schema %d", schema),
+			fmt.Sprintf(`{"type": "object", "properties": {"id": {"type": "string"}, "field%d": {"type": "integer"}}}`, schema),
+			logic.SchemaTypeJSONSchema, "", "user", userID, userID, projectID)
+		if err != nil {
+			return err
+		}
+		messageObject, err := messagesManager.CreateANewMessage(fmt.Sprintf("Message %d", schema), userID, projectID,
+			fmt.Sprintf("Message%d", schema), schemaObject.GetID(), schemaObject.GetLatestVersion(), "")
+		if err != nil {
+			return err
+		}
+		messageIDs[schema] = messageObject.GetID()
+	}
+
+	for app := 0; app < largeImportApps; app++ {
+		appObject, err := appsManager.CreateANewApp(fmt.Sprintf("App%d", app), fmt.Sprintf("Application %d", app),
+			projectID, userID)
+		if err != nil {
+			return err
+		}
+		resourceID := resourceIDs[fmt.Sprintf("%d.%d", app%largeImportServers, app%largeImportResourcesByServer)]
+		_, err = connectionsManager.CreateConnection(appObject.GetID(), resourceID,
+			messageIDs[(2*app)%largeImportSchemas], "sends", userID)
+		if err != nil {
+			return err
+		}
+		_, err = connectionsManager.CreateConnection(appObject.GetID(), resourceID,
+			messageIDs[(2*app+1)%largeImportSchemas], "receives", userID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BenchmarkLargeProjectImport compares how fast a project with about 5,000 entities is imported with
+// batched inserts and when entities are inserted one at a time.
+// Run it with: go test ./tests -run '^$' -bench LargeProjectImport -benchtime 5x
+func BenchmarkLargeProjectImport(b *testing.B) {
+	// Clean database before running benchmark
+	CleanDatabase(b)
+	ConnectToTestDatabase(b)
+
+	h := os.Getenv("TESTSERVER_URL")
+	largeYAML := generateLargeArchitectureYAML()
+
+	benchmarkImport := func(b *testing.B, importArchitecture func(projectID uuid.UUID, userID uuid.UUID) error) {
+		_, createProject := PrepareTestUserAndProject(b, httpexpect.Default(b, h), "test-bulk-import",
+			"Test project for bulk imports")
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			project := createProject("BulkImportProject")
+			projectID, userID := uuid.MustParse(project.ID), uuid.MustParse(project.CreatedByID)
+			b.StartTimer()
+
+			require.NoError(b, importArchitecture(projectID, userID))
+		}
+		b.ReportMetric(float64(largeImportEntities*b.N)/b.Elapsed().Seconds(), "entities/s")
+	}
+
+	b.Run("batched", func(b *testing.B) {
+		benchmarkImport(b, func(projectID uuid.UUID, userID uuid.UUID) error {
+			if issues := logic.ValidateProjectImportYAML(largeYAML, projectID); issues.HasErrors() {
+				return fmt.Errorf("invalid architecture: %v", issues.Errors())
+			}
+			return logic.ImportProjectFromYAML(largeYAML, projectID, userID)
+		})
+	})
+
+	b.Run("per_entity", func(b *testing.B) {
+		benchmarkImport(b, importLargeArchitecturePerEntity)
+	})
+}
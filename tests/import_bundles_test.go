@@ -0,0 +1,186 @@
+package tests
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fusioncatltd/fusioncat/api/input_contracts"
+	"github.com/fusioncatltd/fusioncat/logic"
+	"github.com/gavv/httpexpect/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// Helper function to load all files of a multi-file import bundle keyed by their relative paths
+func loadImportBundleFiles(t *testing.T, bundleName string) map[string]string {
+	bundleRoot := filepath.Join("testfiles", "imports", bundleName)
+	files := make(map[string]string)
+	err := filepath.Walk(bundleRoot, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		relativePath, err := filepath.Rel(bundleRoot, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(relativePath)] = string(content)
+		return nil
+	})
+	require.NoError(t, err, "Failed to read import bundle: %s", bundleName)
+	return files
+}
+
+func TestMultiFileProjectImports(t *testing.T) {
+	// Clean database before running test
+	CleanDatabase(t)
+
+	h := os.Getenv("TESTSERVER_URL")
+	e := httpexpect.Default(t, h)
+
+	// Create a user and get bearer token
+	userBearer, createProject := PrepareTestUserAndProject(t, e, "test-bundle", "Test project for multi-file imports")
+
+	bundleFiles := loadImportBundleFiles(t, "bundle")
+	rootFile := bundleFiles["fusioncat.yaml"]
+	includedFiles := make(map[string]string)
+	for name, content := range bundleFiles {
+		if name != "fusioncat.yaml" {
+			includedFiles[name] = content
+		}
+	}
+
+	// Test 1: Validate and import a bundle sent as JSON payload with additional files
+	jsonProject := createProject("JSONBundleProject")
+	bundlePayload := input_contracts.ImportFileInputContract{
+		YAML:  rootFile,
+		Files: includedFiles,
+	}
+
+	e.POST("/v1/protected/projects/"+jsonProject.ID+"/imports/validator").
+		WithHeader("Authorization", userBearer).
+		WithJSON(bundlePayload).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().HasValue("message", "YAML is valid")
+
+	e.POST("/v1/protected/projects/"+jsonProject.ID+"/imports").
+		WithHeader("Authorization", userBearer).
+		WithJSON(bundlePayload).
+		Expect().
+		Status(http.StatusOK)
+
+	// Schemas come from an external JSON file and from an inline object in a JSON architecture file
+	schemasResponse := e.GET("/v1/protected/projects/"+jsonProject.ID+"/schemas").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK)
+
+	var schemas []logic.SchemaDBSerializerStruct
+	rawSchemasReader := schemasResponse.Raw().Body
+	defer rawSchemasReader.Close()
+	rawSchemasBytes, _ := io.ReadAll(rawSchemasReader)
+	require.NoError(t, json.Unmarshal(rawSchemasBytes, &schemas))
+	require.Len(t, schemas, 2)
+	require.Equal(t, "invoice", schemas[0].Name)
+	require.Contains(t, schemas[0].Schema, "invoice_id")
+	require.Equal(t, "order", schemas[1].Name)
+	require.Equal(t, bundleFiles["schemas/order.json"], schemas[1].Schema)
+
+	appsResponse := e.GET("/v1/protected/projects/"+jsonProject.ID+"/apps").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK)
+	appsResponse.JSON().Array().Length().IsEqual(2)
+
+	// Test 2: Import the same bundle uploaded as a zip archive with a top-level directory
+	archiveProject := createProject("ArchiveBundleProject")
+	var archive bytes.Buffer
+	zipWriter := zip.NewWriter(&archive)
+	for name, content := range bundleFiles {
+		fileWriter, err := zipWriter.Create("architecture/" + name)
+		require.NoError(t, err)
+		_, err = fileWriter.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zipWriter.Close())
+
+	e.POST("/v1/protected/projects/"+archiveProject.ID+"/imports").
+		WithHeader("Authorization", userBearer).
+		WithMultipart().
+		WithFileBytes("archive", "architecture.zip", archive.Bytes()).
+		Expect().
+		Status(http.StatusOK)
+
+	e.GET("/v1/protected/projects/"+archiveProject.ID+"/messages").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array().Length().IsEqual(2)
+
+	// Test 3: Validate the bundle uploaded as separate multipart files
+	multipartProject := createProject("MultipartBundleProject")
+	multipartRequest := e.POST("/v1/protected/projects/"+multipartProject.ID+"/imports/validator").
+		WithHeader("Authorization", userBearer).
+		WithMultipart().
+		WithFormField("root", "fusioncat.yaml")
+	for name, content := range bundleFiles {
+		multipartRequest = multipartRequest.WithFileBytes("files", name, []byte(content))
+	}
+	multipartRequest.Expect().
+		Status(http.StatusOK)
+
+	// Test 4: Issues found in included files point at the file they were found in
+	brokenFiles := make(map[string]string)
+	for name, content := range includedFiles {
+		brokenFiles[name] = content
+	}
+	delete(brokenFiles, "schemas/order.json")
+
+	brokenResponse := e.POST("/v1/protected/projects/"+multipartProject.ID+"/imports/validator").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ImportFileInputContract{YAML: rootFile, Files: brokenFiles}).
+		Expect().
+		Status(http.StatusConflict)
+
+	brokenErrors := brokenResponse.JSON().Object().Value("errors").Array()
+	brokenErrors.Length().IsEqual(1)
+	brokenError := brokenErrors.Value(0).Object()
+	brokenError.HasValue("code", logic.ImportErrCodeFileNotFound)
+	brokenError.HasValue("file", "teams/orders.yaml")
+	brokenError.HasValue("line", 5)
+
+	// Test 5: Include cycles are rejected
+	cyclicFiles := map[string]string{
+		"teams/orders.yaml": "include: orders.yaml\n" + includedFiles["teams/orders.yaml"],
+	}
+	for name, content := range includedFiles {
+		if name != "teams/orders.yaml" {
+			cyclicFiles[name] = content
+		}
+	}
+
+	e.POST("/v1/protected/projects/"+multipartProject.ID+"/imports/validator").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ImportFileInputContract{YAML: rootFile, Files: cyclicFiles}).
+		Expect().
+		Status(http.StatusConflict).
+		JSON().Object().Value("errors").Array().Value(0).Object().
+		HasValue("code", logic.ImportErrCodeIncludeCycle)
+
+	// Test 6: Archives in unknown formats are rejected
+	e.POST("/v1/protected/projects/"+multipartProject.ID+"/imports").
+		WithHeader("Authorization", userBearer).
+		WithMultipart().
+		WithFileBytes("archive", "architecture.rar", []byte("not an archive")).
+		Expect().
+		Status(http.StatusUnprocessableEntity)
+}
@@ -0,0 +1,240 @@
+package tests
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/fusioncatltd/fusioncat/api/input_contracts"
+	"github.com/fusioncatltd/fusioncat/logic"
+	"github.com/gavv/httpexpect/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// Helper function to poll an import job until it reaches a final status
+func waitForImportToFinish(t *testing.T, e *httpexpect.Expect, userBearer string, importID string) logic.ProjectImportJobDBSerializerStruct {
+	deadline := time.Now().Add(30 * time.Second)
+	for {
+		response := e.GET("/v1/protected/imports/"+importID).
+			WithHeader("Authorization", userBearer).
+			Expect().
+			Status(http.StatusOK)
+
+		var importJob logic.ProjectImportJobDBSerializerStruct
+		rawImportReader := response.Raw().Body
+		rawImportBytes, _ := io.ReadAll(rawImportReader)
+		_ = rawImportReader.Close()
+		require.NoError(t, json.Unmarshal(rawImportBytes, &importJob))
+
+		switch importJob.Status {
+		case logic.ImportJobStatusCompleted, logic.ImportJobStatusFailed, logic.ImportJobStatusCancelled:
+			return importJob
+		}
+		require.True(t, time.Now().Before(deadline), "Import %s didn't finish in time, last status: %s", importID, importJob.Status)
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func TestProjectImportJobs(t *testing.T) {
+	// Clean database before running test
+	CleanDatabase(t)
+
+	h := os.Getenv("TESTSERVER_URL")
+	e := httpexpect.Default(t, h)
+
+	// Create a user and get bearer token
+	userBearer, createProject := PrepareTestUserAndProject(t, e, "test-import-jobs", "Test project for import jobs")
+
+	validYAML, err := ReadTestFileString("imports/valid_basic.yaml")
+	require.NoError(t, err)
+
+	// Test 1: Synchronous imports are recorded in the history too
+	syncProject := createProject("SyncImportProject")
+	syncImportID := e.POST("/v1/protected/projects/"+syncProject.ID+"/imports").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ImportFileInputContract{YAML: validYAML}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("import_id").String().NotEmpty().Raw()
+
+	history := e.GET("/v1/protected/projects/"+syncProject.ID+"/imports").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array()
+	history.Length().IsEqual(1)
+	historyEntry := history.Value(0).Object()
+	historyEntry.HasValue("id", syncImportID)
+	historyEntry.HasValue("status", logic.ImportJobStatusCompleted)
+	historyEntry.HasValue("phase", logic.ImportJobPhaseFinished)
+	historyEntry.NotContainsKey("source")
+	historyEntry.Value("created_by_user_name").String().NotEmpty()
+
+	counts := historyEntry.Value("counts").Object()
+	counts.HasValue("servers", 1)
+	counts.HasValue("resources", 2)
+	counts.HasValue("schemas", 1)
+	counts.HasValue("messages", 1)
+	counts.HasValue("apps", 1)
+	counts.HasValue("connections", 2)
+
+	// The source document is available in the import details
+	e.GET("/v1/protected/imports/"+syncImportID).
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("source").Object().Value("files").Object().HasValue("", validYAML)
+
+	// Test 2: Background import jobs report progress until they are finished
+	jobProject := createProject("JobImportProject")
+	submittedJob := e.POST("/v1/protected/projects/"+jobProject.ID+"/imports/jobs").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ImportFileInputContract{YAML: validYAML}).
+		Expect().
+		Status(http.StatusAccepted).
+		JSON().Object()
+	submittedJob.HasValue("project_id", jobProject.ID)
+	jobID := submittedJob.Value("id").String().NotEmpty().Raw()
+
+	finishedJob := waitForImportToFinish(t, e, userBearer, jobID)
+	require.Equal(t, logic.ImportJobStatusCompleted, finishedJob.Status)
+	require.Equal(t, 1, finishedJob.Counts.Schemas)
+	require.Equal(t, 2, finishedJob.Counts.Connections)
+	require.NotEmpty(t, finishedJob.FinishedAt)
+
+	e.GET("/v1/protected/projects/"+jobProject.ID+"/apps").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array().Length().IsEqual(1)
+
+	// Finished imports can't be cancelled
+	e.POST("/v1/protected/imports/"+jobID+"/cancel").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusConflict)
+
+	// Test 3: Validation errors are reported by failed jobs
+	// Importing the same document again fails because all names are already used
+	failedJobID := e.POST("/v1/protected/projects/"+jobProject.ID+"/imports/jobs").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ImportFileInputContract{YAML: validYAML}).
+		Expect().
+		Status(http.StatusAccepted).
+		JSON().Object().Value("id").String().Raw()
+
+	failedJob := waitForImportToFinish(t, e, userBearer, failedJobID)
+	require.Equal(t, logic.ImportJobStatusFailed, failedJob.Status)
+	require.NotEmpty(t, failedJob.Errors)
+	require.Equal(t, logic.ImportErrCodeNameAlreadyExists, failedJob.Errors[0].Code)
+	require.Equal(t, 0, failedJob.Counts.Servers)
+
+	// History lists the newest imports first
+	jobHistory := e.GET("/v1/protected/projects/"+jobProject.ID+"/imports").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array()
+	jobHistory.Length().IsEqual(2)
+	jobHistory.Value(0).Object().HasValue("id", failedJobID)
+	jobHistory.Value(1).Object().HasValue("id", jobID)
+
+	// Test 4: Unknown imports are not found
+	e.GET("/v1/protected/imports/00000000-0000-0000-0000-000000000000").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusNotFound)
+
+	e.POST("/v1/protected/imports/00000000-0000-0000-0000-000000000000/cancel").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusNotFound)
+}
+
+func TestCancelProjectImportJobs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("cancellation needs a large import which is still running, skipped in short mode")
+	}
+
+	// Clean database before running test
+	CleanDatabase(t)
+
+	h := os.Getenv("TESTSERVER_URL")
+	e := httpexpect.Default(t, h)
+
+	// Create a user and get bearer token
+	userBearer, createProject := PrepareTestUserAndProject(t, e, "test-import-cancel", "Test project for cancelled imports")
+
+	validYAML, err := ReadTestFileString("imports/valid_basic.yaml")
+	require.NoError(t, err)
+
+	submitJob := func(projectID string, content string) string {
+		return e.POST("/v1/protected/projects/"+projectID+"/imports/jobs").
+			WithHeader("Authorization", userBearer).
+			WithJSON(input_contracts.ImportFileInputContract{YAML: content}).
+			Expect().
+			Status(http.StatusAccepted).
+			JSON().Object().Value("id").String().NotEmpty().Raw()
+	}
+
+	requireNothingImported := func(importID string) {
+		entities := e.GET("/v1/protected/imports/"+importID+"/entities").
+			WithHeader("Authorization", userBearer).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object()
+		for _, kind := range []string{"servers", "resources", "bindings", "schemas", "messages", "apps", "connections"} {
+			entities.Value(kind).Array().IsEmpty()
+		}
+	}
+
+	// The large import keeps the project busy, the small one waits in the queue
+	project := createProject("CancelImportProject")
+	runningJobID := submitJob(project.ID, generateLargeArchitectureYAML())
+	deadline := time.Now().Add(10 * time.Second)
+	for e.GET("/v1/protected/imports/"+runningJobID).
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("status").String().Raw() == logic.ImportJobStatusQueued {
+		require.True(t, time.Now().Before(deadline), "Import %s didn't start in time", runningJobID)
+		time.Sleep(10 * time.Millisecond)
+	}
+	queuedJobID := submitJob(project.ID, validYAML)
+
+	// Test 1: Queued imports are cancelled before they start
+	e.GET("/v1/protected/imports/"+queuedJobID).
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().HasValue("status", logic.ImportJobStatusQueued)
+
+	e.POST("/v1/protected/imports/"+queuedJobID+"/cancel").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK)
+
+	queuedJob := waitForImportToFinish(t, e, userBearer, queuedJobID)
+	require.Equal(t, logic.ImportJobStatusCancelled, queuedJob.Status)
+	requireNothingImported(queuedJobID)
+
+	// Test 2: Running imports are cancelled and entities they created are removed
+	e.POST("/v1/protected/imports/"+runningJobID+"/cancel").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK)
+
+	runningJob := waitForImportToFinish(t, e, userBearer, runningJobID)
+	require.Equal(t, logic.ImportJobStatusCancelled, runningJob.Status)
+	require.Equal(t, logic.ProjectImportCountsStruct{}, runningJob.Counts)
+	requireNothingImported(runningJobID)
+
+	// Cancelled imports can't be cancelled again
+	e.POST("/v1/protected/imports/"+runningJobID+"/cancel").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusConflict)
+}
@@ -0,0 +1,136 @@
+package tests
+
+import (
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/fusioncatltd/fusioncat/api/input_contracts"
+	"github.com/fusioncatltd/fusioncat/logic"
+	"github.com/gavv/httpexpect/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportProvenanceAndRevert(t *testing.T) {
+	// Clean database before running test
+	CleanDatabase(t)
+
+	h := os.Getenv("TESTSERVER_URL")
+	e := httpexpect.Default(t, h)
+
+	// Create a user and get bearer token
+	userBearer, createProject := PrepareTestUserAndProject(t, e, "test-import-revert", "Test project for import reverts")
+
+	importYAML := func(project logic.ProjectDBSerializerStruct, content string) string {
+		return e.POST("/v1/protected/projects/"+project.ID+"/imports").
+			WithHeader("Authorization", userBearer).
+			WithJSON(input_contracts.ImportFileInputContract{YAML: content}).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().Value("import_id").String().NotEmpty().Raw()
+	}
+
+	validYAML, err := ReadTestFileString("imports/valid_basic.yaml")
+	require.NoError(t, err)
+
+	// Test 1: Entities created by an import are listed
+	project := createProject("RevertImportProject")
+	importID := importYAML(project, validYAML)
+
+	entities := e.GET("/v1/protected/imports/"+importID+"/entities").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	entities.HasValue("import_id", importID)
+	entities.Value("servers").Array().Length().IsEqual(1)
+	entities.Value("servers").Array().Value(0).Object().HasValue("name", "kafka_server")
+	entities.Value("resources").Array().Length().IsEqual(2)
+	entities.Value("schemas").Array().Length().IsEqual(1)
+	entities.Value("messages").Array().Length().IsEqual(1)
+	entities.Value("apps").Array().Length().IsEqual(1)
+	entities.Value("connections").Array().Length().IsEqual(2)
+	schemaID := entities.Value("schemas").Array().Value(0).Object().Value("id").String().Raw()
+
+	// Test 2: Imports can't be reverted while other entities depend on the imported ones
+	dependentMessageID := e.POST("/v1/protected/projects/"+project.ID+"/messages").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateMessageApiInputContract{
+			Name:          "ManualUserMessage",
+			Description:   "Message created outside of the import",
+			SchemaID:      schemaID,
+			SchemaVersion: 1,
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("id").String().Raw()
+
+	blockedRevert := e.POST("/v1/protected/imports/"+importID+"/revert").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusConflict).
+		JSON().Object()
+	blockedRevert.Value("error").String().NotEmpty()
+	dependencies := blockedRevert.Value("dependencies").Array()
+	dependencies.Length().IsEqual(1)
+	dependency := dependencies.Value(0).Object()
+	dependency.HasValue("entity_type", "schema")
+	dependency.HasValue("entity_id", schemaID)
+	dependency.HasValue("dependent_type", "message")
+	dependency.HasValue("dependent_id", dependentMessageID)
+
+	// Nothing was removed
+	e.GET("/v1/protected/projects/"+project.ID+"/apps").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array().Length().IsEqual(1)
+
+	// Test 3: Imports without dependents are reverted
+	revertProject := createProject("RevertedImportProject")
+	revertImportID := importYAML(revertProject, validYAML)
+
+	reverted := e.POST("/v1/protected/imports/"+revertImportID+"/revert").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	reverted.HasValue("status", logic.ImportJobStatusReverted)
+	reverted.Value("reverted_at").String().NotEmpty()
+
+	for _, entityPath := range []string{"servers", "schemas", "messages", "apps"} {
+		e.GET("/v1/protected/projects/"+revertProject.ID+"/"+entityPath).
+			WithHeader("Authorization", userBearer).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Array().Length().IsEqual(0)
+	}
+
+	revertedEntities := e.GET("/v1/protected/imports/"+revertImportID+"/entities").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	revertedEntities.Value("servers").Array().Length().IsEqual(0)
+	revertedEntities.Value("connections").Array().Length().IsEqual(0)
+
+	// Reverted imports can't be reverted again
+	e.POST("/v1/protected/imports/"+revertImportID+"/revert").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusConflict)
+
+	// Names of reverted entities can be used again
+	importYAML(revertProject, validYAML)
+
+	// Test 4: Unknown imports are not found
+	e.GET("/v1/protected/imports/00000000-0000-0000-0000-000000000000/entities").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusNotFound)
+
+	e.POST("/v1/protected/imports/00000000-0000-0000-0000-000000000000/revert").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusNotFound)
+}
@@ -92,7 +92,7 @@ func TestProjectImportsAndValidation(t *testing.T) {
 		require.Equal(t, http.StatusOK, validateResponse.Raw().StatusCode, "Validation should succeed")
 	}
 
-	var validateResult map[string]string
+	var validateResult map[string]interface{}
 	rawValidateReader := validateResponse.Raw().Body
 	defer rawValidateReader.Close()
 	rawValidateBytes, _ := io.ReadAll(rawValidateReader)
@@ -210,12 +210,17 @@ func TestProjectImportsAndValidation(t *testing.T) {
 	errors, ok := validateDuplicateResult["errors"].([]interface{})
 	require.True(t, ok)
 	require.Greater(t, len(errors), 0)
-	// Check that at least one error mentions the duplicate server
+	// Check that at least one error mentions the duplicate server and points at its name
 	foundDuplicateError := false
 	for _, err := range errors {
-		if errStr, ok := err.(string); ok {
-			if contains(errStr, "kafka_server") && contains(errStr, "already exists") {
+		if errObj, ok := err.(map[string]interface{}); ok {
+			if errObj["code"] == logic.ImportErrCodeNameAlreadyExists && errObj["value"] == "kafka_server" {
 				foundDuplicateError = true
+				require.Equal(t, "error", errObj["severity"])
+				require.Equal(t, "servers[0].name", errObj["path"])
+				require.Greater(t, errObj["line"].(float64), float64(0))
+				require.Greater(t, errObj["column"].(float64), float64(0))
+				require.Contains(t, errObj["message"], "already exists")
 				break
 			}
 		}
@@ -242,6 +247,14 @@ func TestProjectImportsAndValidation(t *testing.T) {
 
 	require.NoError(t, json.Unmarshal(rawValidateInvalidResourceBytes, &validateInvalidResourceResult))
 	require.Contains(t, validateInvalidResourceResult, "errors")
+	missingMessageErrors := validateInvalidResourceResult["errors"].([]interface{})
+	require.Len(t, missingMessageErrors, 1)
+	missingMessageError := missingMessageErrors[0].(map[string]interface{})
+	require.Equal(t, logic.ImportErrCodeUnknownMessage, missingMessageError["code"])
+	require.Equal(t, "apps[0].sends[0].message", missingMessageError["path"])
+	require.Equal(t, "NonExistentMessage", missingMessageError["value"])
+	require.Equal(t, float64(16), missingMessageError["line"])
+	require.Equal(t, float64(18), missingMessageError["column"])
 
 	// Test 7: Test validation without authentication
 	_ = e.POST("/v1/protected/projects/" + createdProject.ID + "/imports/validator").
@@ -287,7 +300,7 @@ func TestProjectImportsAndValidation(t *testing.T) {
 		require.Equal(t, http.StatusOK, validateComplexResponse.Raw().StatusCode, "Complex YAML validation should succeed")
 	}
 
-	var validateComplexResult map[string]string
+	var validateComplexResult map[string]interface{}
 	rawValidateComplexReader := validateComplexResponse.Raw().Body
 	defer rawValidateComplexReader.Close()
 	rawValidateComplexBytes, _ := io.ReadAll(rawValidateComplexReader)
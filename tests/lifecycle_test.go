@@ -0,0 +1,359 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fusioncatltd/fusioncat/api/input_contracts"
+	"github.com/fusioncatltd/fusioncat/logic"
+	"github.com/gavv/httpexpect/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLifecycleStates(t *testing.T) {
+	// Clean database before running test
+	CleanDatabase(t)
+
+	h := os.Getenv("TESTSERVER_URL")
+	e := httpexpect.Default(t, h)
+
+	// Create a user and get bearer token
+	userEmail := fmt.Sprintf("test-lifecycle-%s@mail.com", strconv.FormatInt(time.Now().UnixNano(), 10))
+	userSignUpResponse := e.POST("/v1/public/users").
+		WithJSON(input_contracts.SignInSignUpApiInputContract{
+			Email:    userEmail,
+			Password: "123456789",
+		}).
+		Expect().
+		Status(http.StatusOK)
+
+	userBearer := userSignUpResponse.Raw().Header.Get("Authorization")
+
+	projectID := e.POST("/v1/protected/projects").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateModifyProjectApiInputContract{
+			Name:        fmt.Sprintf("LifecycleProject%d", time.Now().UnixNano()),
+			Description: "Test project for lifecycle states",
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("id").String().Raw()
+
+	userEventV1, err := ReadTestFileString("avroschemas/userEventV1.avsc")
+	require.NoError(t, err)
+	userEventV2, err := ReadTestFileString("avroschemas/userEventV2.avsc")
+	require.NoError(t, err)
+
+	createSchema := func(name string) string {
+		return e.POST("/v1/protected/projects/"+projectID+"/schemas").
+			WithHeader("Authorization", userBearer).
+			WithJSON(input_contracts.CreateSchemaApiInputContract{
+				Name:   name,
+				Type:   "avro",
+				Schema: userEventV1,
+			}).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().Value("id").String().Raw()
+	}
+	createMessage := func(name string, schemaID string, version int) *httpexpect.Response {
+		return e.POST("/v1/protected/projects/"+projectID+"/messages").
+			WithHeader("Authorization", userBearer).
+			WithJSON(input_contracts.CreateMessageApiInputContract{
+				Name:          name,
+				SchemaID:      schemaID,
+				SchemaVersion: version,
+			}).
+			Expect()
+	}
+	changeLifecycle := func(path string, input input_contracts.LifecycleApiInputContract) *httpexpect.Response {
+		return e.PUT("/v1/protected/"+path+"/lifecycle").
+			WithHeader("Authorization", userBearer).
+			WithJSON(input).
+			Expect()
+	}
+
+	// Test 1: New schemas are active
+	userEventSchemaID := createSchema("user_event")
+	userChangedSchemaID := createSchema("user_changed")
+
+	e.PUT("/v1/protected/schemas/"+userEventSchemaID).
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ModifySchemaApiInputContract{Schema: userEventV2}).
+		Expect().
+		Status(http.StatusOK)
+
+	e.GET("/v1/protected/schemas/"+userEventSchemaID).
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("lifecycle").Object().IsEqual(map[string]interface{}{"state": "active"})
+
+	// Test 2: Invalid lifecycle changes are rejected
+	changeLifecycle("schemas/"+userEventSchemaID, input_contracts.LifecycleApiInputContract{State: "obsolete"}).
+		Status(http.StatusUnprocessableEntity)
+
+	changeLifecycle("schemas/"+userEventSchemaID, input_contracts.LifecycleApiInputContract{State: "draft"}).
+		Status(http.StatusUnprocessableEntity).
+		JSON().Object().Value("error").Array().Value(0).Object().HasValue("field", "state")
+
+	changeLifecycle("schemas/"+userEventSchemaID, input_contracts.LifecycleApiInputContract{
+		State:             "active",
+		DeprecationReason: "Not deprecated yet",
+	}).
+		Status(http.StatusUnprocessableEntity).
+		JSON().Object().Value("error").Array().Value(0).Object().HasValue("field", "state")
+
+	changeLifecycle("schemas/"+userEventSchemaID, input_contracts.LifecycleApiInputContract{
+		State:      "deprecated",
+		ReplacedBy: "user_event",
+	}).
+		Status(http.StatusUnprocessableEntity).
+		JSON().Object().Value("error").Array().Value(0).Object().HasValue("field", "replaced_by")
+
+	changeLifecycle("schemas/"+userEventSchemaID, input_contracts.LifecycleApiInputContract{
+		State:    "deprecated",
+		SunsetAt: "31.12.2026",
+	}).
+		Status(http.StatusUnprocessableEntity).
+		JSON().Object().Value("error").Array().Value(0).Object().HasValue("field", "sunset_at")
+
+	// Test 3: Deprecated schemas have a reason, a replacement and a sunset date
+	changeLifecycle("schemas/"+userEventSchemaID, input_contracts.LifecycleApiInputContract{
+		State:             "deprecated",
+		DeprecationReason: "User events are split by change type",
+		ReplacedBy:        "user_changed",
+		SunsetAt:          "2026-12-31",
+	}).
+		Status(http.StatusOK).
+		JSON().Object().Value("lifecycle").Object().IsEqual(map[string]interface{}{
+		"state":              "deprecated",
+		"deprecation_reason": "User events are split by change type",
+		"replaced_by":        "user_changed",
+		"sunset_at":          "2026-12-31",
+	})
+
+	// Test 4: Generated Go code of deprecated schemas has deprecation comments
+	generatedCode := e.GET("/v1/protected/schemas/"+userEventSchemaID+"/code/go").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		Body().Raw()
+	require.Contains(t, generatedCode, `// Event published when a user changes
+//
+// Deprecated: schema user_event is deprecated: User events are split by change type.
+// Use user_changed instead.
+// Sunset on 2026-12-31.
+type UserEvent struct`)
+
+	require.NotContains(t, e.GET("/v1/protected/schemas/"+userChangedSchemaID+"/code/go").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		Body().Raw(), "Deprecated:")
+
+	// Test 5: Schema versions are replaced by versions referenced by URIs, retired versions can't be used by new messages
+	changeLifecycle("schemas/"+userEventSchemaID+"/versions/1", input_contracts.LifecycleApiInputContract{
+		State:      "retired",
+		ReplacedBy: "user_event",
+	}).
+		Status(http.StatusUnprocessableEntity).
+		JSON().Object().Value("error").Array().Value(0).Object().HasValue("field", "replaced_by")
+
+	changeLifecycle("schemas/"+userEventSchemaID+"/versions/1", input_contracts.LifecycleApiInputContract{
+		State:      "retired",
+		ReplacedBy: "fusioncat://schemas/user_event/versions/3",
+	}).
+		Status(http.StatusUnprocessableEntity)
+
+	changeLifecycle("schemas/"+userEventSchemaID+"/versions/1", input_contracts.LifecycleApiInputContract{
+		State:      "retired",
+		ReplacedBy: "fusioncat://schemas/user_event/versions/2",
+	}).
+		Status(http.StatusOK).
+		JSON().Object().
+		HasValue("version", 1).
+		Value("lifecycle").Object().
+		HasValue("state", "retired").
+		HasValue("replaced_by", "fusioncat://schemas/user_event/versions/2")
+
+	changeLifecycle("schemas/"+userEventSchemaID+"/versions/1", input_contracts.LifecycleApiInputContract{State: "active"}).
+		Status(http.StatusUnprocessableEntity)
+
+	e.GET("/v1/protected/schemas/"+userEventSchemaID+"/versions").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array().Value(1).Object().Value("lifecycle").Object().HasValue("state", "active")
+
+	createMessage("user_event_v1", userEventSchemaID, 1).
+		Status(http.StatusUnprocessableEntity)
+
+	messageID := createMessage("user_event_v2", userEventSchemaID, 2).
+		Status(http.StatusOK).
+		JSON().Object().Value("id").String().Raw()
+
+	// Test 6: Messages are replaced by other messages of the project
+	createMessage("user_changed", userChangedSchemaID, 1).
+		Status(http.StatusOK)
+
+	changeLifecycle("messages/"+messageID, input_contracts.LifecycleApiInputContract{
+		State:      "deprecated",
+		ReplacedBy: "unknown_message",
+	}).
+		Status(http.StatusUnprocessableEntity).
+		JSON().Object().Value("error").Array().Value(0).Object().HasValue("field", "replaced_by")
+
+	changeLifecycle("messages/"+messageID, input_contracts.LifecycleApiInputContract{
+		State:      "deprecated",
+		ReplacedBy: "user_changed",
+	}).
+		Status(http.StatusOK).
+		JSON().Object().Value("lifecycle").Object().
+		HasValue("state", "deprecated").
+		HasValue("replaced_by", "user_changed").
+		NotContainsKey("sunset_at")
+
+	messages := e.GET("/v1/protected/projects/"+projectID+"/messages").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array()
+	for _, message := range messages.Iter() {
+		expectedState := "active"
+		if message.Object().Value("id").String().Raw() == messageID {
+			expectedState = "deprecated"
+		}
+		message.Object().Value("lifecycle").Object().HasValue("state", expectedState)
+	}
+
+	// Deprecated messages become active again without deprecation details
+	changeLifecycle("messages/"+messageID, input_contracts.LifecycleApiInputContract{State: "active"}).
+		Status(http.StatusOK).
+		JSON().Object().Value("lifecycle").Object().IsEqual(map[string]interface{}{"state": "active"})
+
+	// Test 7: Retired schemas can't be used by new messages
+	changeLifecycle("schemas/"+userEventSchemaID, input_contracts.LifecycleApiInputContract{State: "retired"}).
+		Status(http.StatusOK)
+
+	createMessage("user_event_v3", userEventSchemaID, 2).
+		Status(http.StatusUnprocessableEntity)
+
+	// Test 8: Unknown entities are not found
+	changeLifecycle("messages/00000000-0000-0000-0000-000000000000", input_contracts.LifecycleApiInputContract{State: "active"}).
+		Status(http.StatusNotFound)
+
+	changeLifecycle("schemas/"+userEventSchemaID+"/versions/5", input_contracts.LifecycleApiInputContract{State: "active"}).
+		Status(http.StatusNotFound)
+}
+
+func TestLifecycleOfSynchronizedMessages(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	// Clean database before running test
+	CleanDatabase(t)
+
+	h := os.Getenv("TESTSERVER_URL")
+	e := httpexpect.Default(t, h)
+
+	// Create a user and get bearer token
+	userEmail := fmt.Sprintf("test-lifecycle-sync-%s@mail.com", strconv.FormatInt(time.Now().UnixNano(), 10))
+	userSignUpResponse := e.POST("/v1/public/users").
+		WithJSON(input_contracts.SignInSignUpApiInputContract{
+			Email:    userEmail,
+			Password: "123456789",
+		}).
+		Expect().
+		Status(http.StatusOK)
+
+	userBearer := userSignUpResponse.Raw().Header.Get("Authorization")
+
+	projectID := e.POST("/v1/protected/projects").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateModifyProjectApiInputContract{
+			Name:        fmt.Sprintf("LifecycleSyncProject%d", time.Now().UnixNano()),
+			Description: "Test project for lifecycle states of synchronized messages",
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("id").String().Raw()
+
+	repositoryDir := gitRepositoriesTestDir(t)
+	bareRepository := filepath.Join(repositoryDir, "architecture.git")
+	workDir := filepath.Join(repositoryDir, "work")
+	runGit(t, repositoryDir, "init", "--bare", bareRepository)
+	runGit(t, repositoryDir, "clone", bareRepository, workDir)
+	runGit(t, workDir, "checkout", "-B", "main")
+
+	validYAML, err := ReadTestFileString("imports/valid_basic.yaml")
+	require.NoError(t, err)
+	commitAndPush(t, workDir, map[string]string{"fusioncat.yaml": validYAML}, "Initial architecture")
+
+	e.PUT("/v1/protected/projects/"+projectID+"/git-sync").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ConfigureGitSyncInputContract{
+			RepositoryPath: bareRepository,
+			Branch:         "main",
+		}).
+		Expect().
+		Status(http.StatusOK)
+
+	sync := func() *httpexpect.Response {
+		return e.POST("/v1/protected/projects/"+projectID+"/git-sync/syncs").
+			WithHeader("Authorization", userBearer).
+			Expect()
+	}
+	sync().Status(http.StatusOK).JSON().Object().Value("warnings").Array().IsEmpty()
+
+	messageID := e.GET("/v1/protected/projects/"+projectID+"/messages").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array().Value(0).Object().Value("id").String().Raw()
+
+	// Test 1: Apps using deprecated messages get warnings
+	e.PUT("/v1/protected/messages/"+messageID+"/lifecycle").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.LifecycleApiInputContract{
+			State:             "deprecated",
+			DeprecationReason: "Users are published as events",
+			SunsetAt:          "2026-12-31",
+		}).
+		Expect().
+		Status(http.StatusOK)
+
+	updatedYAML := strings.Replace(validYAML, "description: User application", "description: Application of users", 1)
+	commitAndPush(t, workDir, map[string]string{"fusioncat.yaml": updatedYAML}, "Describe the user app")
+
+	warnings := sync().Status(http.StatusOK).JSON().Object().Value("warnings").Array()
+	warnings.Length().IsEqual(2)
+	warnings.Value(0).Object().
+		HasValue("code", logic.ImportWarnCodeDeprecatedMessage).
+		HasValue("path", "apps[0].sends[0].message").
+		HasValue("value", "UserMessage").
+		Value("message").String().Contains("Sunset on 2026-12-31.")
+
+	// Test 2: Apps can't use retired messages
+	e.PUT("/v1/protected/messages/"+messageID+"/lifecycle").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.LifecycleApiInputContract{State: "retired"}).
+		Expect().
+		Status(http.StatusOK)
+
+	commitAndPush(t, workDir, map[string]string{"fusioncat.yaml": validYAML}, "Restore the description")
+
+	failedSync := sync().Status(http.StatusConflict).JSON().Object()
+	failedSync.HasValue("status", logic.ImportJobStatusFailed)
+	failedSync.Value("errors").Array().Value(0).Object().
+		HasValue("code", logic.ImportErrCodeRetiredMessage).
+		HasValue("path", "apps[0].sends[0].message")
+}
@@ -0,0 +1,189 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/fusioncatltd/fusioncat/api/input_contracts"
+	"github.com/gavv/httpexpect/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayloadExamples(t *testing.T) {
+	// Clean database before running test
+	CleanDatabase(t)
+
+	h := os.Getenv("TESTSERVER_URL")
+	e := httpexpect.Default(t, h)
+
+	// Create a user and get bearer token
+	userEmail := fmt.Sprintf("test-payload-examples-%s@mail.com", strconv.FormatInt(time.Now().UnixNano(), 10))
+	userSignUpResponse := e.POST("/v1/public/users").
+		WithJSON(input_contracts.SignInSignUpApiInputContract{
+			Email:    userEmail,
+			Password: "123456789",
+		}).
+		Expect().
+		Status(http.StatusOK)
+
+	userBearer := userSignUpResponse.Raw().Header.Get("Authorization")
+
+	projectID := e.POST("/v1/protected/projects").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateModifyProjectApiInputContract{
+			Name:        fmt.Sprintf("PayloadExamplesProject%d", time.Now().UnixNano()),
+			Description: "Test project for example payloads",
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("id").String().Raw()
+
+	orderSchema, err := ReadTestFileString("examples/order.json")
+	require.NoError(t, err)
+	userEventSchema, err := ReadTestFileString("avroschemas/userEventV1.avsc")
+	require.NoError(t, err)
+
+	schemaID := e.POST("/v1/protected/projects/"+projectID+"/schemas").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateSchemaApiInputContract{
+			Name:   "order",
+			Type:   "jsonschema",
+			Schema: orderSchema,
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("id").String().Raw()
+
+	// Test 1: The same seed produces the same examples
+	examplesURL := "/v1/protected/schemas/" + schemaID + "/versions/1/examples"
+	firstResponse := e.GET(examplesURL).
+		WithQuery("count", 5).
+		WithQuery("seed", 42).
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		HasValue("schema_id", schemaID).
+		HasValue("schema_version", 1).
+		HasValue("seed", 42)
+	examples := firstResponse.Value("examples").Array()
+	examples.Length().IsEqual(5)
+
+	e.GET(examplesURL).
+		WithQuery("count", 5).
+		WithQuery("seed", 42).
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("examples").IsEqual(examples.Raw())
+
+	otherExamples := e.GET(examplesURL).
+		WithQuery("count", 5).
+		WithQuery("seed", 43).
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("examples").Array().Raw()
+	require.NotEqual(t, examples.Raw(), otherExamples)
+
+	// Test 2: Examples honor formats, enums and bounds, and are valid against the schema
+	first := examples.Value(0).Object()
+	first.Value("id").String().Match(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	first.Value("status").String().InList("new", "paid", "shipped")
+	first.Value("customer").Object().Value("email").String().Contains("@")
+	_, err = time.Parse(time.RFC3339, first.Value("placed_at").String().Raw())
+	require.NoError(t, err)
+	first.Value("items").Array().Length().InRange(1, 5)
+
+	payloads := make([]json.RawMessage, 0, 5)
+	for _, example := range examples.Iter() {
+		payload, err := json.Marshal(example.Raw())
+		require.NoError(t, err)
+		payloads = append(payloads, payload)
+	}
+	e.POST("/v1/protected/schemas/"+schemaID+"/versions/1/validate").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ValidatePayloadsApiInputContract{Payloads: payloads}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().HasValue("valid", true)
+
+	// Test 3: Requests without a seed get a random one which reproduces the examples
+	randomResponse := e.GET(examplesURL).
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	randomResponse.Value("examples").Array().Length().IsEqual(1)
+	e.GET(examplesURL).
+		WithQuery("seed", int64(randomResponse.Value("seed").Number().Raw())).
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("examples").IsEqual(randomResponse.Value("examples").Raw())
+
+	// Test 4: Count and seed are validated, unknown versions are not found
+	e.GET(examplesURL).
+		WithQuery("count", 101).
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusUnprocessableEntity).
+		JSON().Object().Value("error").Array().Value(0).Object().HasValue("field", "count")
+
+	e.GET(examplesURL).
+		WithQuery("seed", "abc").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusUnprocessableEntity).
+		JSON().Object().Value("error").Array().Value(0).Object().HasValue("field", "seed")
+
+	e.GET("/v1/protected/schemas/"+schemaID+"/versions/5/examples").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusNotFound)
+
+	// Test 5: Messages generate examples of their schema version in the Avro JSON encoding
+	avroSchemaID := e.POST("/v1/protected/projects/"+projectID+"/schemas").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateSchemaApiInputContract{
+			Name:   "user_event",
+			Type:   "avro",
+			Schema: userEventSchema,
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("id").String().Raw()
+
+	messageID := e.POST("/v1/protected/projects/"+projectID+"/messages").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateMessageApiInputContract{
+			Name:          "user_changed",
+			SchemaID:      avroSchemaID,
+			SchemaVersion: 1,
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("id").String().Raw()
+
+	messageExamples := e.GET("/v1/protected/messages/"+messageID+"/examples").
+		WithQuery("count", 3).
+		WithQuery("seed", 7).
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		HasValue("schema_id", avroSchemaID).
+		Value("examples").Array()
+	messageExamples.Length().IsEqual(3)
+	messageExamples.Value(0).Object().Value("status").String().InList("ACTIVE", "BLOCKED")
+
+	e.GET("/v1/protected/messages/00000000-0000-0000-0000-000000000000/examples").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusNotFound)
+}
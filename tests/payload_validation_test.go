@@ -0,0 +1,182 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/fusioncatltd/fusioncat/api/input_contracts"
+	"github.com/gavv/httpexpect/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayloadValidation(t *testing.T) {
+	// Clean database before running test
+	CleanDatabase(t)
+
+	h := os.Getenv("TESTSERVER_URL")
+	e := httpexpect.Default(t, h)
+
+	// Create a user and get bearer token
+	userEmail := fmt.Sprintf("test-payload-validation-%s@mail.com", strconv.FormatInt(time.Now().UnixNano(), 10))
+	userSignUpResponse := e.POST("/v1/public/users").
+		WithJSON(input_contracts.SignInSignUpApiInputContract{
+			Email:    userEmail,
+			Password: "123456789",
+		}).
+		Expect().
+		Status(http.StatusOK)
+
+	userBearer := userSignUpResponse.Raw().Header.Get("Authorization")
+
+	projectID := e.POST("/v1/protected/projects").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateModifyProjectApiInputContract{
+			Name:        fmt.Sprintf("PayloadValidationProject%d", time.Now().UnixNano()),
+			Description: "Test project for payload validation",
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("id").String().Raw()
+
+	personSchema, err := ReadTestFileString("jsonschemas/validSchema1.json")
+	require.NoError(t, err)
+	userEventSchema, err := ReadTestFileString("avroschemas/userEventV1.avsc")
+	require.NoError(t, err)
+
+	schemaID := e.POST("/v1/protected/projects/"+projectID+"/schemas").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateSchemaApiInputContract{
+			Name:   "person",
+			Type:   "jsonschema",
+			Schema: personSchema,
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("id").String().Raw()
+
+	// Test 1: A single payload is validated against a schema version
+	e.POST("/v1/protected/schemas/"+schemaID+"/versions/1/validate").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ValidatePayloadsApiInputContract{
+			Payload: json.RawMessage(`{"firstName": "John", "lastName": "Doe", "age": 42}`),
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		HasValue("valid", true).
+		HasValue("schema_version", 1).
+		Value("results").Array().Length().IsEqual(1)
+
+	// Test 2: Every payload gets its own result with instance locations and failed keywords
+	results := e.POST("/v1/protected/schemas/"+schemaID+"/versions/1/validate").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ValidatePayloadsApiInputContract{
+			Payloads: []json.RawMessage{
+				json.RawMessage(`{"firstName": "John", "lastName": "Doe"}`),
+				json.RawMessage(`{"firstName": 7, "lastName": "Doe", "age": -1}`),
+				json.RawMessage(`"not an object"`),
+			},
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		HasValue("valid", false).
+		Value("results").Array()
+	results.Length().IsEqual(3)
+	results.Value(0).Object().HasValue("index", 0).HasValue("valid", true).Value("errors").Array().IsEmpty()
+
+	invalidPayload := results.Value(1).Object().HasValue("index", 1).HasValue("valid", false)
+	failedKeywords := map[string]string{}
+	for _, failure := range invalidPayload.Value("errors").Array().Iter() {
+		failedKeywords[failure.Object().Value("instance_location").String().Raw()] =
+			failure.Object().Value("keyword").String().Raw()
+	}
+	require.Equal(t, map[string]string{"/firstName": "type", "/age": "minimum"}, failedKeywords)
+
+	results.Value(2).Object().HasValue("valid", false).
+		Value("errors").Array().Value(0).Object().
+		HasValue("instance_location", "").
+		HasValue("keyword_location", "/type")
+
+	// Test 3: Either a payload or a list of payloads is required
+	e.POST("/v1/protected/schemas/"+schemaID+"/versions/1/validate").
+		WithHeader("Authorization", userBearer).
+		WithJSON(map[string]interface{}{}).
+		Expect().
+		Status(http.StatusUnprocessableEntity).
+		JSON().Object().Value("error").Array().Value(0).Object().HasValue("field", "payloads")
+
+	e.POST("/v1/protected/schemas/"+schemaID+"/versions/1/validate").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ValidatePayloadsApiInputContract{
+			Payload:  json.RawMessage(`{}`),
+			Payloads: []json.RawMessage{json.RawMessage(`{}`)},
+		}).
+		Expect().
+		Status(http.StatusUnprocessableEntity)
+
+	e.POST("/v1/protected/schemas/"+schemaID+"/versions/5/validate").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ValidatePayloadsApiInputContract{Payload: json.RawMessage(`{}`)}).
+		Expect().
+		Status(http.StatusNotFound)
+
+	e.POST("/v1/protected/schemas/"+schemaID+"/versions/1/validate").
+		WithQuery("message_type", "Person").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ValidatePayloadsApiInputContract{Payload: json.RawMessage(`{}`)}).
+		Expect().
+		Status(http.StatusUnprocessableEntity)
+
+	// Test 4: Messages validate payloads against their schema version
+	avroSchemaID := e.POST("/v1/protected/projects/"+projectID+"/schemas").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateSchemaApiInputContract{
+			Name:   "user_event",
+			Type:   "avro",
+			Schema: userEventSchema,
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("id").String().Raw()
+
+	messageID := e.POST("/v1/protected/projects/"+projectID+"/messages").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateMessageApiInputContract{
+			Name:          "user_changed",
+			SchemaID:      avroSchemaID,
+			SchemaVersion: 1,
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("id").String().Raw()
+
+	messageResults := e.POST("/v1/protected/messages/"+messageID+"/validate").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ValidatePayloadsApiInputContract{
+			Payloads: []json.RawMessage{
+				json.RawMessage(`{"id": "4b1f3b4e-5a8e-4c1f-9f0a-1f2d3c4b5a69", "email": "john@example.com", "status": "ACTIVE", "created_at": 1700000000000}`),
+				json.RawMessage(`{"id": "4b1f3b4e-5a8e-4c1f-9f0a-1f2d3c4b5a69", "email": "john@example.com", "status": "DELETED", "created_at": 1700000000000}`),
+			},
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		HasValue("schema_id", avroSchemaID).
+		HasValue("valid", false).
+		Value("results").Array()
+	messageResults.Value(0).Object().HasValue("valid", true)
+	messageResults.Value(1).Object().HasValue("valid", false).
+		Value("errors").Array().Value(0).Object().HasValue("instance_location", "/status")
+
+	e.POST("/v1/protected/messages/00000000-0000-0000-0000-000000000000/validate").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ValidatePayloadsApiInputContract{Payload: json.RawMessage(`{}`)}).
+		Expect().
+		Status(http.StatusNotFound)
+}
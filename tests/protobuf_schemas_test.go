@@ -0,0 +1,271 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fusioncatltd/fusioncat/api/input_contracts"
+	"github.com/fusioncatltd/fusioncat/logic"
+	"github.com/gavv/httpexpect/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtobufSchemas(t *testing.T) {
+	// Clean database before running test
+	CleanDatabase(t)
+
+	h := os.Getenv("TESTSERVER_URL")
+	e := httpexpect.Default(t, h)
+
+	// Create a user and get bearer token
+	userEmail := fmt.Sprintf("test-protobuf-%s@mail.com", strconv.FormatInt(time.Now().UnixNano(), 10))
+	userPayload := input_contracts.SignInSignUpApiInputContract{
+		Email:    userEmail,
+		Password: "123456789",
+	}
+
+	userSignUpResponse := e.POST("/v1/public/users").
+		WithJSON(userPayload).
+		Expect().
+		Status(http.StatusOK)
+
+	userBearer := userSignUpResponse.Raw().Header.Get("Authorization")
+
+	projectResponse := e.POST("/v1/protected/projects").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateModifyProjectApiInputContract{
+			Name:        fmt.Sprintf("ProtobufProject%d", time.Now().UnixNano()),
+			Description: "Test project for Protocol Buffers schemas",
+		}).
+		Expect().
+		Status(http.StatusOK)
+
+	var project logic.ProjectDBSerializerStruct
+	rawProjectReader := projectResponse.Raw().Body
+	defer rawProjectReader.Close()
+	rawProjectBytes, _ := io.ReadAll(rawProjectReader)
+	require.NoError(t, json.Unmarshal(rawProjectBytes, &project))
+
+	commonProto, err := ReadTestFileString("protobufschemas/common.proto")
+	require.NoError(t, err)
+	ordersProto, err := ReadTestFileString("protobufschemas/orders.proto")
+	require.NoError(t, err)
+	invalidProto, err := ReadTestFileString("protobufschemas/invalidSchema1UnknownType.proto")
+	require.NoError(t, err)
+	jsonSchema, err := ReadTestFileString("jsonschemas/validSchema1.json")
+	require.NoError(t, err)
+
+	// Test 1: Imports are resolved among protobuf schemas of the project
+	e.POST("/v1/protected/projects/"+project.ID+"/schemas").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateSchemaApiInputContract{
+			Name:   "orders",
+			Type:   "protobuf",
+			Schema: ordersProto,
+		}).
+		Expect().
+		Status(http.StatusUnprocessableEntity).
+		JSON().Object().Value("error").Array().Value(0).Object().
+		Value("message").String().Contains(`there is no protobuf schema named "common"`)
+
+	e.POST("/v1/protected/projects/"+project.ID+"/schemas").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateSchemaApiInputContract{
+			Name:        "common",
+			Description: "Shared types",
+			Type:        "protobuf",
+			Schema:      commonProto,
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().HasValue("type", logic.SchemaTypeProtobuf)
+
+	createdSchema := e.POST("/v1/protected/projects/"+project.ID+"/schemas").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateSchemaApiInputContract{
+			Name:        "orders",
+			Description: "Order events",
+			Type:        "protobuf",
+			Schema:      ordersProto,
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	createdSchema.HasValue("version", 1)
+	schemaID := createdSchema.Value("id").String().Raw()
+
+	// Test 2: Invalid sources are rejected
+	invalidResponse := e.POST("/v1/protected/projects/"+project.ID+"/schemas").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateSchemaApiInputContract{
+			Name:   "shipping",
+			Type:   "protobuf",
+			Schema: invalidProto,
+		}).
+		Expect().
+		Status(http.StatusUnprocessableEntity).
+		JSON().Object().Value("error").Array().Value(0).Object()
+	invalidResponse.HasValue("field", "schema")
+	invalidResponse.Value("message").String().Contains("Invalid Protocol Buffers schema").Contains(`unknown type "Address"`)
+
+	e.PUT("/v1/protected/schemas/"+schemaID).
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ModifySchemaApiInputContract{Schema: jsonSchema}).
+		Expect().
+		Status(http.StatusUnprocessableEntity)
+
+	// Test 3: Messages select a message type of the schema, the first message is used by default
+	e.POST("/v1/protected/projects/"+project.ID+"/messages").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateMessageApiInputContract{
+			Name:          "order_created",
+			SchemaID:      schemaID,
+			SchemaVersion: 1,
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().HasValue("schema_message_type", "orders.v1.OrderCreated")
+
+	e.POST("/v1/protected/projects/"+project.ID+"/messages").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateMessageApiInputContract{
+			Name:              "order_cancelled",
+			SchemaID:          schemaID,
+			SchemaVersion:     1,
+			SchemaMessageType: "OrderCancelled",
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().HasValue("schema_message_type", "orders.v1.OrderCancelled")
+
+	e.POST("/v1/protected/projects/"+project.ID+"/messages").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateMessageApiInputContract{
+			Name:              "order_shipped",
+			SchemaID:          schemaID,
+			SchemaVersion:     1,
+			SchemaMessageType: "orders.v1.OrderShipped",
+		}).
+		Expect().
+		Status(http.StatusUnprocessableEntity).
+		JSON().Object().Value("error").String().Contains("orders.v1.OrderCancelled")
+
+	jsonSchemaID := e.POST("/v1/protected/projects/"+project.ID+"/schemas").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateSchemaApiInputContract{
+			Name:   "json_event",
+			Type:   "jsonschema",
+			Schema: jsonSchema,
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("id").String().Raw()
+
+	e.POST("/v1/protected/projects/"+project.ID+"/messages").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateMessageApiInputContract{
+			Name:              "json_event_message",
+			SchemaID:          jsonSchemaID,
+			SchemaVersion:     1,
+			SchemaMessageType: "orders.v1.OrderCreated",
+		}).
+		Expect().
+		Status(http.StatusUnprocessableEntity)
+
+	// Test 4: Go code is generated for the selected message type and the types it references
+	generatedCode := e.GET("/v1/protected/schemas/"+schemaID+"/code/go").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		Body().Raw()
+	require.Contains(t, generatedCode, "// Published when a customer places an order")
+	require.Contains(t, generatedCode, "type Orders struct")
+	require.Contains(t, generatedCode, "*OrdersMoney")
+	require.Contains(t, generatedCode, "[]*OrdersItem")
+	require.Contains(t, generatedCode, "OrdersStatus_STATUS_PAID")
+	require.Contains(t, generatedCode, `json:"orderId,omitempty"`)
+	require.Contains(t, generatedCode, "// Deprecated:")
+	require.NotContains(t, generatedCode, "Reason")
+
+	cancelledCode := e.GET("/v1/protected/schemas/"+schemaID+"/code/go").
+		WithQuery("message_type", "orders.v1.OrderCancelled").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		Body().Raw()
+	require.Contains(t, cancelledCode, "Reason string")
+
+	e.GET("/v1/protected/schemas/"+schemaID+"/code/typescript").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusBadRequest).
+		JSON().Object().Value("error").String().Contains("only for go")
+
+	// Test 5: Protobuf schemas and message types are imported with the rest of the architecture
+	protobufImportYAML, err := ReadTestFileString("imports/valid_protobuf.yaml")
+	require.NoError(t, err)
+
+	importProjectID := e.POST("/v1/protected/projects").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateModifyProjectApiInputContract{
+			Name:        fmt.Sprintf("ProtobufImportProject%d", time.Now().UnixNano()),
+			Description: "Test project for protobuf imports",
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("id").String().Raw()
+
+	brokenImportYAML := strings.Replace(protobufImportYAML,
+		"message_type: orders.v1.OrderCancelled", "message_type: orders.v1.OrderShipped", 1)
+	brokenImportErrors := e.POST("/v1/protected/projects/"+importProjectID+"/imports/validator").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ImportFileInputContract{YAML: brokenImportYAML}).
+		Expect().
+		Status(http.StatusConflict).
+		JSON().Object().Value("errors").Array()
+	brokenImportErrors.Length().IsEqual(1)
+	brokenImportErrors.Value(0).Object().HasValue("code", logic.ImportErrCodeUnknownMessageType)
+
+	e.POST("/v1/protected/projects/"+importProjectID+"/imports").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ImportFileInputContract{YAML: protobufImportYAML}).
+		Expect().
+		Status(http.StatusOK)
+
+	importedMessages := e.GET("/v1/protected/projects/"+importProjectID+"/messages").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array()
+	importedMessages.Length().IsEqual(2)
+	messageTypes := map[string]string{}
+	for _, message := range importedMessages.Iter() {
+		messageTypes[message.Object().Value("name").String().Raw()] =
+			message.Object().Value("schema_message_type").String().Raw()
+	}
+	require.Equal(t, "orders.v1.OrderCreated", messageTypes["order_created"])
+	require.Equal(t, "orders.v1.OrderCancelled", messageTypes["order_cancelled"])
+
+	// Test 6: App code contains a schema struct for every used message type
+	importedApps := e.GET("/v1/protected/projects/"+importProjectID+"/apps").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array()
+	appID := importedApps.Value(0).Object().Value("id").String().Raw()
+
+	appCode := e.GET("/v1/protected/apps/"+appID+"/code/go").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		Body().Raw()
+	require.Contains(t, appCode, "OrdersOrderCreatedVersion1")
+	require.Contains(t, appCode, "OrdersOrderCancelledVersion1")
+}
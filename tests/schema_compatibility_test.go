@@ -0,0 +1,258 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/fusioncatltd/fusioncat/api/input_contracts"
+	"github.com/fusioncatltd/fusioncat/logic"
+	"github.com/gavv/httpexpect/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaCompatibility(t *testing.T) {
+	// Clean database before running test
+	CleanDatabase(t)
+
+	h := os.Getenv("TESTSERVER_URL")
+	e := httpexpect.Default(t, h)
+
+	// Create a user and get bearer token
+	userEmail := fmt.Sprintf("test-compatibility-%s@mail.com", strconv.FormatInt(time.Now().UnixNano(), 10))
+	userPayload := input_contracts.SignInSignUpApiInputContract{
+		Email:    userEmail,
+		Password: "123456789",
+	}
+
+	userSignUpResponse := e.POST("/v1/public/users").
+		WithJSON(userPayload).
+		Expect().
+		Status(http.StatusOK)
+
+	userBearer := userSignUpResponse.Raw().Header.Get("Authorization")
+
+	projectResponse := e.POST("/v1/protected/projects").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateModifyProjectApiInputContract{
+			Name:        fmt.Sprintf("CompatibilityProject%d", time.Now().UnixNano()),
+			Description: "Test project for schema compatibility",
+		}).
+		Expect().
+		Status(http.StatusOK)
+
+	var project logic.ProjectDBSerializerStruct
+	rawProjectReader := projectResponse.Raw().Body
+	defer rawProjectReader.Close()
+	rawProjectBytes, _ := io.ReadAll(rawProjectReader)
+	require.NoError(t, json.Unmarshal(rawProjectBytes, &project))
+
+	personV1, err := ReadTestFileString("compatibility/personV1.json")
+	require.NoError(t, err)
+	personV2, err := ReadTestFileString("compatibility/personV2OptionalField.json")
+	require.NoError(t, err)
+	personV3, err := ReadTestFileString("compatibility/personV3Breaking.json")
+	require.NoError(t, err)
+	userEventV1, err := ReadTestFileString("avroschemas/userEventV1.avsc")
+	require.NoError(t, err)
+	userEventV2, err := ReadTestFileString("avroschemas/userEventV2.avsc")
+	require.NoError(t, err)
+	userEventV3, err := ReadTestFileString("compatibility/userEventV3MissingDefault.avsc")
+	require.NoError(t, err)
+	commonProto, err := ReadTestFileString("protobufschemas/common.proto")
+	require.NoError(t, err)
+
+	// Test 1: Schemas are created without compatibility checks by default
+	defaultSchema := e.POST("/v1/protected/projects/"+project.ID+"/schemas").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateSchemaApiInputContract{
+			Name:   "person_default",
+			Type:   "jsonschema",
+			Schema: personV1,
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	defaultSchema.HasValue("compatibility", logic.CompatibilityNone)
+
+	e.PUT("/v1/protected/schemas/"+defaultSchema.Value("id").String().Raw()).
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ModifySchemaApiInputContract{Schema: personV3}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().HasValue("version", 2)
+
+	e.POST("/v1/protected/projects/"+project.ID+"/schemas").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateSchemaApiInputContract{
+			Name:          "person_unknown_mode",
+			Type:          "jsonschema",
+			Schema:        personV1,
+			Compatibility: "sideways",
+		}).
+		Expect().
+		Status(http.StatusUnprocessableEntity)
+
+	// Test 2: Backward compatible JSON schemas accept optional properties and reject breaking changes
+	schemaID := e.POST("/v1/protected/projects/"+project.ID+"/schemas").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateSchemaApiInputContract{
+			Name:          "person",
+			Type:          "jsonschema",
+			Schema:        personV1,
+			Compatibility: logic.CompatibilityBackward,
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().HasValue("compatibility", logic.CompatibilityBackward).
+		Value("id").String().Raw()
+
+	e.PUT("/v1/protected/schemas/"+schemaID).
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ModifySchemaApiInputContract{Schema: personV2}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().HasValue("version", 2)
+
+	rejected := e.PUT("/v1/protected/schemas/"+schemaID).
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ModifySchemaApiInputContract{Schema: personV3}).
+		Expect().
+		Status(http.StatusConflict).
+		JSON().Object()
+	rejected.HasValue("compatibility", logic.CompatibilityBackward)
+	violations := rejected.Value("violations").Array()
+	violations.Length().IsEqual(2)
+	violationKinds := map[string]string{}
+	for _, violation := range violations.Iter() {
+		violation.Object().HasValue("version", 2).HasValue("direction", logic.CompatibilityDirectionBackward)
+		violationKinds[violation.Object().Value("kind").String().Raw()] = violation.Object().Value("path").String().Raw()
+	}
+	require.Equal(t, "/required", violationKinds["required_added"])
+	require.Equal(t, "/properties/status/enum", violationKinds["enum_values_removed"])
+
+	e.GET("/v1/protected/schemas/"+schemaID).
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().HasValue("version", 2)
+
+	// Test 3: Incompatible versions are created when the check is explicitly overridden
+	e.PUT("/v1/protected/schemas/"+schemaID).
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ModifySchemaApiInputContract{Schema: personV3, IgnoreCompatibility: true}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().HasValue("version", 3)
+
+	// Test 4: Transitive modes check the new version against all previous versions, not only the latest one
+	e.PUT("/v1/protected/schemas/"+schemaID+"/compatibility").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.SchemaCompatibilityApiInputContract{Compatibility: logic.CompatibilityBackwardTransitive}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().HasValue("compatibility", logic.CompatibilityBackwardTransitive)
+
+	transitiveViolations := e.PUT("/v1/protected/schemas/"+schemaID).
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ModifySchemaApiInputContract{Schema: personV3}).
+		Expect().
+		Status(http.StatusConflict).
+		JSON().Object().Value("violations").Array()
+	checkedVersions := map[int64]bool{}
+	for _, violation := range transitiveViolations.Iter() {
+		checkedVersions[int64(violation.Object().Value("version").Number().Raw())] = true
+	}
+	require.Equal(t, map[int64]bool{1: true, 2: true}, checkedVersions)
+
+	// Test 5: Forward compatibility rejects versions which readers of previous versions can't handle
+	e.PUT("/v1/protected/schemas/"+schemaID+"/compatibility").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.SchemaCompatibilityApiInputContract{Compatibility: logic.CompatibilityForward}).
+		Expect().
+		Status(http.StatusOK)
+
+	forwardViolations := e.PUT("/v1/protected/schemas/"+schemaID).
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ModifySchemaApiInputContract{Schema: personV1}).
+		Expect().
+		Status(http.StatusConflict).
+		JSON().Object().Value("violations").Array()
+	forwardViolations.Length().IsEqual(2)
+	for _, violation := range forwardViolations.Iter() {
+		violation.Object().HasValue("version", 3).HasValue("direction", logic.CompatibilityDirectionForward)
+	}
+
+	e.PUT("/v1/protected/schemas/"+schemaID+"/compatibility").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.SchemaCompatibilityApiInputContract{Compatibility: "sideways"}).
+		Expect().
+		Status(http.StatusUnprocessableEntity)
+
+	// Test 6: Avro schemas follow the schema resolution rules
+	avroSchemaID := e.POST("/v1/protected/projects/"+project.ID+"/schemas").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateSchemaApiInputContract{
+			Name:          "user_event",
+			Type:          "avro",
+			Schema:        userEventV1,
+			Compatibility: logic.CompatibilityFull,
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("id").String().Raw()
+
+	e.PUT("/v1/protected/schemas/"+avroSchemaID).
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ModifySchemaApiInputContract{Schema: userEventV2}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().HasValue("version", 2)
+
+	avroViolations := e.PUT("/v1/protected/schemas/"+avroSchemaID).
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ModifySchemaApiInputContract{Schema: userEventV3}).
+		Expect().
+		Status(http.StatusConflict).
+		JSON().Object().Value("violations").Array()
+	avroViolations.Length().IsEqual(1)
+	avroViolations.Value(0).Object().
+		HasValue("direction", logic.CompatibilityDirectionBackward).
+		HasValue("path", "/country")
+
+	// Test 7: Compatibility of protobuf schemas can't be enforced yet
+	e.POST("/v1/protected/projects/"+project.ID+"/schemas").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateSchemaApiInputContract{
+			Name:          "common",
+			Type:          "protobuf",
+			Schema:        commonProto,
+			Compatibility: logic.CompatibilityBackward,
+		}).
+		Expect().
+		Status(http.StatusUnprocessableEntity).
+		JSON().Object().Value("error").String().Contains("protobuf")
+
+	// Test 8: Imports validate compatibility modes of schemas
+	importErrors := e.POST("/v1/protected/projects/"+project.ID+"/imports/validator").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ImportFileInputContract{YAML: `version: 1
+schemas:
+  - name: imported_person
+    type: jsonschema
+    compatibility: sideways
+    schema: '{"type": "object"}'
+`}).
+		Expect().
+		Status(http.StatusConflict).
+		JSON().Object().Value("errors").Array()
+	importErrors.Length().IsEqual(1)
+	importErrors.Value(0).Object().
+		HasValue("code", logic.ImportErrCodeInvalidCompatibility).
+		HasValue("path", "schemas[0].compatibility")
+}
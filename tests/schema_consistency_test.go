@@ -0,0 +1,149 @@
+package tests
+
+import (
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/fusioncatltd/fusioncat/api/input_contracts"
+	"github.com/fusioncatltd/fusioncat/logic"
+	"github.com/gavv/httpexpect/v2"
+)
+
+func TestFieldsConsistencyReport(t *testing.T) {
+	// Clean database before running test
+	CleanDatabase(t)
+
+	h := os.Getenv("TESTSERVER_URL")
+	e := httpexpect.Default(t, h)
+
+	userBearer, createProject := PrepareTestUserAndProject(t, e, "test-fields-consistency", "Test project for the consistency report")
+	project := createProject("FieldsConsistencyProject")
+	reportURL := "/v1/protected/projects/" + project.ID + "/fields/consistency"
+
+	paymentsID := e.POST("/v1/protected/projects/"+project.ID+"/schemas").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateSchemaApiInputContract{
+			Name: "payments",
+			Type: logic.SchemaTypeJSONSchema,
+			Schema: `{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"type": "object",
+				"properties": {"legacy_id": {"type": "integer"}, "note": {"type": "string"}}
+			}`,
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("id").String().Raw()
+	e.PUT("/v1/protected/schemas/"+paymentsID).
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ModifySchemaApiInputContract{Schema: `{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"type": "object",
+			"properties": {
+				"amount": {"type": "string", "description": "Amount in dollars"},
+				"status": {"type": "string", "enum": ["paid", "failed"]},
+				"customer_id": {"type": "string", "format": "uuid"},
+				"created_at": {"type": "string", "format": "date-time"},
+				"note": {"type": "string"}
+			}
+		}`}).
+		Expect().
+		Status(http.StatusOK)
+
+	e.POST("/v1/protected/projects/"+project.ID+"/schemas").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateSchemaApiInputContract{
+			Name: "refunds",
+			Type: logic.SchemaTypeAvro,
+			Schema: `{"type": "record", "name": "Refund", "fields": [
+				{"name": "amount", "type": "long", "doc": "Amount in cents"},
+				{"name": "status", "type": {"type": "enum", "name": "Status", "symbols": ["paid", "refunded"]}},
+				{"name": "customer_id", "type": "string"},
+				{"name": "created_at", "type": {"type": "long", "logicalType": "timestamp-millis"}},
+				{"name": "note", "type": ["null", "string"], "default": null},
+				{"name": "legacy_id", "type": "string"}
+			]}`,
+		}).
+		Expect().
+		Status(http.StatusOK)
+
+	// Test 1: Conflicting fields of current versions are reported
+	report := e.GET(reportURL).
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	report.HasValue("checked_names", 5)
+	inconsistencies := report.Value("inconsistencies").Array()
+	inconsistencies.Length().IsEqual(4)
+
+	amount := inconsistencies.Value(0).Object()
+	amount.HasValue("name", "amount").
+		HasValue("conflicts", []map[string]interface{}{
+			{"kind": logic.FieldConflictType, "values": []string{"integer", "string"}},
+			{"kind": logic.FieldConflictUnit, "values": []string{"cents", "major units"}},
+		})
+	amountFields := amount.Value("fields").Array()
+	amountFields.Length().IsEqual(2)
+	amountFields.Value(0).Object().
+		HasValue("schema_id", paymentsID).
+		HasValue("schema_version", 2).
+		HasValue("schema_uri", logic.SchemaReferenceURI("payments", 2)).
+		HasValue("normalized_type", "string").
+		HasValue("unit", "major units")
+	amountFields.Value(1).Object().
+		HasValue("schema_name", "refunds").
+		HasValue("type", "long").
+		HasValue("normalized_type", "integer").
+		HasValue("unit", "cents")
+
+	inconsistencies.Value(1).Object().
+		HasValue("name", "created_at").
+		HasValue("conflicts", []map[string]interface{}{
+			{"kind": logic.FieldConflictType, "values": []string{"integer", "string"}},
+		})
+	inconsistencies.Value(2).Object().
+		HasValue("name", "customer_id").
+		HasValue("conflicts", []map[string]interface{}{
+			{"kind": logic.FieldConflictFormat, "values": []string{"none", "uuid"}},
+		})
+	inconsistencies.Value(3).Object().
+		HasValue("name", "status").
+		HasValue("conflicts", []map[string]interface{}{
+			{"kind": logic.FieldConflictEnum, "values": []string{`["failed","paid"]`, `["paid","refunded"]`}},
+		})
+
+	// Test 2: Old versions count while messages are pinned to them
+	e.POST("/v1/protected/projects/"+project.ID+"/messages").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateMessageApiInputContract{
+			Name:          "LegacyPayment",
+			SchemaID:      paymentsID,
+			SchemaVersion: 1,
+		}).
+		Expect().
+		Status(http.StatusOK)
+
+	report = e.GET(reportURL).
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	report.HasValue("checked_names", 6)
+	inconsistencies = report.Value("inconsistencies").Array()
+	inconsistencies.Length().IsEqual(5)
+	legacyID := inconsistencies.Value(3).Object()
+	legacyID.HasValue("name", "legacy_id")
+	legacyFields := legacyID.Value("fields").Array()
+	legacyFields.Value(0).Object().
+		HasValue("schema_version", 1).
+		Value("messages").Array().Value(0).Object().HasValue("name", "LegacyPayment")
+	legacyFields.Value(1).Object().HasValue("schema_name", "refunds")
+
+	// Test 3: Unknown projects
+	e.GET("/v1/protected/projects/00000000-0000-0000-0000-000000000000/fields/consistency").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusNotFound)
+}
@@ -0,0 +1,174 @@
+package tests
+
+import (
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/fusioncatltd/fusioncat/api/input_contracts"
+	"github.com/fusioncatltd/fusioncat/logic"
+	"github.com/gavv/httpexpect/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaConversion(t *testing.T) {
+	// Clean database before running test
+	CleanDatabase(t)
+
+	h := os.Getenv("TESTSERVER_URL")
+	e := httpexpect.Default(t, h)
+
+	userBearer, createProject := PrepareTestUserAndProject(t, e, "test-schema-conversion", "Test project for schema conversions")
+	project := createProject("SchemaConversionProject")
+
+	schemaID := e.POST("/v1/protected/projects/"+project.ID+"/schemas").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateSchemaApiInputContract{
+			Name: "orders",
+			Type: logic.SchemaTypeJSONSchema,
+			Schema: `{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"type": "object",
+				"properties": {
+					"id": {"type": "string", "format": "uuid"},
+					"created_at": {"type": "string", "format": "date-time"},
+					"status": {"type": "string", "enum": ["paid", "failed"]},
+					"note": {"type": "string", "maxLength": 200}
+				},
+				"required": ["id", "created_at"]
+			}`,
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("id").String().Raw()
+	convertURL := "/v1/protected/schemas/" + schemaID + "/versions/1/convert"
+
+	// Test 1: Conversions which aren't saved return the converted schema and losses
+	avroConversion := e.POST(convertURL).
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ConvertSchemaVersionApiInputContract{Target: logic.SchemaTypeAvro}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	avroConversion.HasValue("source_type", logic.SchemaTypeJSONSchema).
+		HasValue("target_type", logic.SchemaTypeAvro).
+		HasValue("losses", []map[string]interface{}{
+			{"path": "/note", "message": `"maxLength" is not representable in Avro`},
+		}).
+		NotContainsKey("saved_schema")
+	avroSchema := avroConversion.Value("schema").String()
+	avroSchema.Contains(`"name": "Orders"`).
+		Contains(`"logicalType": "timestamp-millis"`)
+
+	// Test 2: Saved conversions are new schemas linked to the converted version
+	protobufConversion := e.POST(convertURL).
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ConvertSchemaVersionApiInputContract{
+			Target:      logic.SchemaTypeProtobuf,
+			Name:        "orders_proto",
+			Description: "Orders for protobuf consumers",
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	protobufConversion.Value("schema").String().
+		Contains("message OrdersProto {").
+		Contains("google.protobuf.Timestamp created_at = 1;")
+	losses := protobufConversion.Value("losses").Array()
+	losses.Length().IsEqual(4)
+	losses.Value(0).Object().
+		HasValue("path", "").
+		HasValue("message", "required fields are not enforced by protobuf: created_at, id")
+	savedSchema := protobufConversion.Value("saved_schema").Object()
+	savedSchema.HasValue("name", "orders_proto").
+		HasValue("type", logic.SchemaTypeProtobuf).
+		HasValue("converted_from", map[string]interface{}{"schema_id": schemaID, "schema_version": 1})
+	protobufSchemaID := savedSchema.Value("id").String().Raw()
+
+	e.GET("/v1/protected/schemas/"+protobufSchemaID).
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		HasValue("converted_from", map[string]interface{}{"schema_id": schemaID, "schema_version": 1})
+
+	// Test 3: Protobuf schemas are converted back to JSON schemas
+	e.POST("/v1/protected/schemas/"+protobufSchemaID+"/versions/1/convert").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ConvertSchemaVersionApiInputContract{
+			Target:      logic.SchemaTypeJSONSchema,
+			MessageType: "orders_proto.OrdersProto",
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		HasValue("source_type", logic.SchemaTypeProtobuf).
+		HasValue("losses", []interface{}{}).
+		Value("schema").String().
+		Contains(`"createdAt"`).
+		Contains(`"format": "date-time"`)
+
+	// Test 4: Invalid conversions
+	e.POST(convertURL).
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ConvertSchemaVersionApiInputContract{Target: logic.SchemaTypeJSONSchema}).
+		Expect().
+		Status(http.StatusUnprocessableEntity)
+
+	e.POST(convertURL).
+		WithHeader("Authorization", userBearer).
+		WithJSON(map[string]interface{}{"target": "xml"}).
+		Expect().
+		Status(http.StatusUnprocessableEntity)
+
+	e.POST(convertURL).
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ConvertSchemaVersionApiInputContract{Target: logic.SchemaTypeAvro, Name: "orders_proto"}).
+		Expect().
+		Status(http.StatusConflict)
+
+	e.POST("/v1/protected/schemas/"+protobufSchemaID+"/versions/1/convert").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ConvertSchemaVersionApiInputContract{Target: logic.SchemaTypeAvro, MessageType: "Missing"}).
+		Expect().
+		Status(http.StatusUnprocessableEntity)
+
+	e.POST("/v1/protected/schemas/"+schemaID+"/versions/9/convert").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ConvertSchemaVersionApiInputContract{Target: logic.SchemaTypeAvro}).
+		Expect().
+		Status(http.StatusNotFound)
+
+	// Test 5: Imports can't be reverted while schemas converted from the imported schemas exist
+	validYAML, err := ReadTestFileString("imports/valid_basic.yaml")
+	require.NoError(t, err)
+	importID := e.POST("/v1/protected/projects/"+project.ID+"/imports").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ImportFileInputContract{YAML: validYAML}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("import_id").String().Raw()
+	importedSchemaID := e.GET("/v1/protected/imports/"+importID+"/entities").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("schemas").Array().Value(0).Object().Value("id").String().Raw()
+
+	convertedSchemaID := e.POST("/v1/protected/schemas/"+importedSchemaID+"/versions/1/convert").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ConvertSchemaVersionApiInputContract{Target: logic.SchemaTypeAvro, Name: "user_avro"}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("saved_schema").Object().Value("id").String().Raw()
+
+	dependencies := e.POST("/v1/protected/imports/"+importID+"/revert").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusConflict).
+		JSON().Object().Value("dependencies").Array()
+	dependencies.Length().IsEqual(1)
+	dependencies.Value(0).Object().
+		HasValue("entity_id", importedSchemaID).
+		HasValue("dependent_type", "schema").
+		HasValue("dependent_id", convertedSchemaID)
+}
@@ -0,0 +1,159 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/fusioncatltd/fusioncat/api/input_contracts"
+	"github.com/gavv/httpexpect/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaVersionsDiff(t *testing.T) {
+	// Clean database before running test
+	CleanDatabase(t)
+
+	h := os.Getenv("TESTSERVER_URL")
+	e := httpexpect.Default(t, h)
+
+	// Create a user and get bearer token
+	userEmail := fmt.Sprintf("test-schema-diff-%s@mail.com", strconv.FormatInt(time.Now().UnixNano(), 10))
+	userSignUpResponse := e.POST("/v1/public/users").
+		WithJSON(input_contracts.SignInSignUpApiInputContract{
+			Email:    userEmail,
+			Password: "123456789",
+		}).
+		Expect().
+		Status(http.StatusOK)
+
+	userBearer := userSignUpResponse.Raw().Header.Get("Authorization")
+
+	projectID := e.POST("/v1/protected/projects").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateModifyProjectApiInputContract{
+			Name:        fmt.Sprintf("SchemaDiffProject%d", time.Now().UnixNano()),
+			Description: "Test project for schema diffs",
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("id").String().Raw()
+
+	personV1, err := ReadTestFileString("compatibility/personV1.json")
+	require.NoError(t, err)
+	personV2, err := ReadTestFileString("compatibility/personV2OptionalField.json")
+	require.NoError(t, err)
+	personV3, err := ReadTestFileString("compatibility/personV3Breaking.json")
+	require.NoError(t, err)
+	userEventV1, err := ReadTestFileString("avroschemas/userEventV1.avsc")
+	require.NoError(t, err)
+
+	schemaID := e.POST("/v1/protected/projects/"+projectID+"/schemas").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateSchemaApiInputContract{
+			Name:   "person",
+			Type:   "jsonschema",
+			Schema: personV1,
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("id").String().Raw()
+
+	for _, content := range []string{personV2, personV3} {
+		e.PUT("/v1/protected/schemas/"+schemaID).
+			WithHeader("Authorization", userBearer).
+			WithJSON(input_contracts.ModifySchemaApiInputContract{Schema: content}).
+			Expect().
+			Status(http.StatusOK)
+	}
+
+	// Test 1: Optional properties don't break anybody
+	nonBreakingDiff := e.GET("/v1/protected/schemas/"+schemaID+"/diff").
+		WithQuery("from", 1).
+		WithQuery("to", 2).
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	nonBreakingDiff.HasValue("from", 1).HasValue("to", 2).HasValue("breaking", false)
+	nonBreakingChanges := nonBreakingDiff.Value("changes").Array()
+	nonBreakingChanges.Length().IsEqual(1)
+	nonBreakingChanges.Value(0).Object().
+		HasValue("path", "/properties/nickname").
+		HasValue("kind", "property_added").
+		HasValue("breaking", false)
+
+	// Test 2: New required properties and removed enum values break producers of old data
+	breakingDiff := e.GET("/v1/protected/schemas/"+schemaID+"/diff").
+		WithQuery("from", 1).
+		WithQuery("to", 3).
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	breakingDiff.HasValue("breaking", true).HasValue("breaks_producers", true).HasValue("breaks_consumers", false)
+	changesByPath := map[string]*httpexpect.Object{}
+	for _, change := range breakingDiff.Value("changes").Array().Iter() {
+		changesByPath[change.Object().Value("path").String().Raw()] = change.Object()
+	}
+	require.Len(t, changesByPath, 4)
+	changesByPath["/required"].HasValue("kind", "required_added").HasValue("breaks_producers", true)
+	changesByPath["/properties/status/enum"].HasValue("kind", "enum_values_removed").HasValue("breaks_producers", true)
+	changesByPath["/properties/email"].HasValue("breaking", false)
+
+	// Test 3: Diffs can go from newer versions to older ones
+	e.GET("/v1/protected/schemas/"+schemaID+"/diff").
+		WithQuery("from", 3).
+		WithQuery("to", 1).
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		HasValue("breaks_producers", false).
+		HasValue("breaks_consumers", true)
+
+	e.GET("/v1/protected/schemas/"+schemaID+"/diff").
+		WithQuery("from", 2).
+		WithQuery("to", 2).
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("changes").Array().IsEmpty()
+
+	// Test 4: Versions must be given and exist
+	e.GET("/v1/protected/schemas/"+schemaID+"/diff").
+		WithQuery("from", 1).
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusUnprocessableEntity).
+		JSON().Object().Value("error").Array().Value(0).Object().HasValue("field", "to")
+
+	e.GET("/v1/protected/schemas/"+schemaID+"/diff").
+		WithQuery("from", 1).
+		WithQuery("to", 9).
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusNotFound)
+
+	// Test 5: Only JSON schemas can be compared
+	avroSchemaID := e.POST("/v1/protected/projects/"+projectID+"/schemas").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateSchemaApiInputContract{
+			Name:   "user_event",
+			Type:   "avro",
+			Schema: userEventV1,
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("id").String().Raw()
+
+	e.GET("/v1/protected/schemas/"+avroSchemaID+"/diff").
+		WithQuery("from", 1).
+		WithQuery("to", 1).
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusBadRequest)
+}
@@ -0,0 +1,152 @@
+package tests
+
+import (
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/fusioncatltd/fusioncat/api/input_contracts"
+	"github.com/fusioncatltd/fusioncat/logic"
+	"github.com/gavv/httpexpect/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaFields(t *testing.T) {
+	// Clean database before running test
+	CleanDatabase(t)
+
+	h := os.Getenv("TESTSERVER_URL")
+	e := httpexpect.Default(t, h)
+
+	userBearer, createProject := PrepareTestUserAndProject(t, e, "test-schema-fields", "Test project for the field catalog")
+	project := createProject("SchemaFieldsProject")
+	schemasURL := "/v1/protected/projects/" + project.ID + "/schemas"
+
+	// Versions created by imports are cataloged when the catalog is read
+	importYAML, err := ReadTestFileString("imports/valid_basic.yaml")
+	require.NoError(t, err)
+	e.POST("/v1/protected/projects/"+project.ID+"/imports").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ImportFileInputContract{YAML: importYAML}).
+		Expect().
+		Status(http.StatusOK)
+
+	createSchema := func(name string, schemaType string, content string) string {
+		return e.POST(schemasURL).
+			WithHeader("Authorization", userBearer).
+			WithJSON(input_contracts.CreateSchemaApiInputContract{
+				Name:   name,
+				Type:   schemaType,
+				Schema: content,
+			}).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().Value("id").String().Raw()
+	}
+	jsonSchemaID := createSchema("orders_json", logic.SchemaTypeJSONSchema, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"required": ["customer_id"],
+		"properties": {
+			"customer_id": {"type": "string", "format": "uuid", "description": "Customer placing the order"},
+			"lines": {"type": "array", "items": {"$ref": "#/$defs/line"}}
+		},
+		"$defs": {"line": {"type": "object", "properties": {"status": {"type": "string", "enum": ["open", "shipped"]}}}}
+	}`)
+	createSchema("orders_avro", logic.SchemaTypeAvro,
+		`{"type": "record", "name": "Order", "fields": [{"name": "customer_id", "type": "long"}]}`)
+	createSchema("orders_proto", logic.SchemaTypeProtobuf,
+		"syntax = \"proto3\";\npackage shop;\nmessage Order {\n  string customer_id = 1;\n}\n")
+
+	e.POST("/v1/protected/projects/"+project.ID+"/messages").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateMessageApiInputContract{
+			Name:          "OrderPlaced",
+			SchemaID:      jsonSchemaID,
+			SchemaVersion: 1,
+		}).
+		Expect().
+		Status(http.StatusOK)
+
+	// Test 1: Field catalog of a version
+	fields := e.GET("/v1/protected/schemas/"+jsonSchemaID+"/versions/1/fields").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array()
+	fields.Length().IsEqual(3)
+	fields.Value(0).Object().
+		HasValue("path", "/customer_id").
+		HasValue("name", "customer_id").
+		HasValue("type", "string").
+		HasValue("format", "uuid").
+		HasValue("required", true).
+		HasValue("description", "Customer placing the order")
+	fields.Value(1).Object().HasValue("path", "/lines").HasValue("type", "array").HasValue("required", false)
+	fields.Value(2).Object().
+		HasValue("path", "/lines/*/status").
+		HasValue("enum", []interface{}{"open", "shipped"})
+
+	// Test 2: Fields are found across schemas of all types, case-insensitively
+	search := e.GET("/v1/protected/projects/"+project.ID+"/fields").
+		WithHeader("Authorization", userBearer).
+		WithQuery("name", "Customer_ID").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	search.HasValue("types", []interface{}{"long", "string"})
+	found := search.Value("fields").Array()
+	found.Length().IsEqual(3)
+	found.Value(0).Object().
+		HasValue("schema_name", "orders_avro").
+		HasValue("schema_type", logic.SchemaTypeAvro).
+		HasValue("type", "long").
+		HasValue("messages", []interface{}{})
+	jsonField := found.Value(1).Object()
+	jsonField.HasValue("schema_id", jsonSchemaID).
+		HasValue("schema_version", 1).
+		HasValue("path", "/customer_id")
+	jsonField.Value("messages").Array().Length().IsEqual(1)
+	jsonField.Value("messages").Array().Value(0).Object().HasValue("name", "OrderPlaced")
+	found.Value(2).Object().
+		HasValue("schema_name", "orders_proto").
+		HasValue("message_type", "shop.Order")
+
+	// Imported schemas are cataloged too
+	imported := e.GET("/v1/protected/projects/"+project.ID+"/fields").
+		WithHeader("Authorization", userBearer).
+		WithQuery("name", "name").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	imported.Value("fields").Array().Length().IsEqual(1)
+	importedField := imported.Value("fields").Array().Value(0).Object()
+	importedField.HasValue("schema_name", "UserSchema")
+	importedField.Value("messages").Array().Value(0).Object().HasValue("name", "UserMessage")
+
+	// Test 3: Unknown fields, invalid requests
+	e.GET("/v1/protected/projects/"+project.ID+"/fields").
+		WithHeader("Authorization", userBearer).
+		WithQuery("name", "unknown_field").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		HasValue("types", []interface{}{}).
+		HasValue("fields", []interface{}{})
+
+	e.GET("/v1/protected/projects/"+project.ID+"/fields").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusUnprocessableEntity)
+
+	e.GET("/v1/protected/projects/00000000-0000-0000-0000-000000000000/fields").
+		WithHeader("Authorization", userBearer).
+		WithQuery("name", "customer_id").
+		Expect().
+		Status(http.StatusNotFound)
+
+	e.GET("/v1/protected/schemas/"+jsonSchemaID+"/versions/5/fields").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusNotFound)
+}
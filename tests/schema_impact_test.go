@@ -0,0 +1,173 @@
+package tests
+
+import (
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/fusioncatltd/fusioncat/api/input_contracts"
+	"github.com/fusioncatltd/fusioncat/logic"
+	"github.com/gavv/httpexpect/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaImpact(t *testing.T) {
+	// Clean database before running test
+	CleanDatabase(t)
+
+	h := os.Getenv("TESTSERVER_URL")
+	e := httpexpect.Default(t, h)
+
+	userBearer, createProject := PrepareTestUserAndProject(t, e, "test-schema-impact", "Test project for schema impact analysis")
+	project := createProject("SchemaImpactProject")
+
+	// UserApp sends and receives UserMessage, which is pinned to version 1 of UserSchema
+	importYAML, err := ReadTestFileString("imports/valid_basic.yaml")
+	require.NoError(t, err)
+	e.POST("/v1/protected/projects/"+project.ID+"/imports").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ImportFileInputContract{YAML: importYAML}).
+		Expect().
+		Status(http.StatusOK)
+
+	schemaID := e.GET("/v1/protected/projects/"+project.ID+"/schemas").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array().Value(0).Object().Value("id").String().Raw()
+
+	userSchemaV2 := `{"type": "object", "properties": {"id": {"type": "string"}, "name": {"type": "string"},
+		"nickname": {"type": "string"}}}`
+	e.PUT("/v1/protected/schemas/"+schemaID).
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ModifySchemaApiInputContract{Schema: userSchemaV2}).
+		Expect().
+		Status(http.StatusOK)
+	e.PUT("/v1/protected/schemas/"+schemaID+"/compatibility").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.SchemaCompatibilityApiInputContract{Compatibility: logic.CompatibilityBackward}).
+		Expect().
+		Status(http.StatusOK)
+
+	// A message pinned to version 2 is not used by apps
+	e.POST("/v1/protected/projects/"+project.ID+"/messages").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateMessageApiInputContract{
+			Name:          "UserMessageV2",
+			SchemaID:      schemaID,
+			SchemaVersion: 2,
+		}).
+		Expect().
+		Status(http.StatusOK)
+
+	// Test 1: Usage of every version
+	impact := e.GET("/v1/protected/schemas/"+schemaID+"/impact").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	impact.HasValue("schema_id", schemaID).
+		HasValue("schema_name", "UserSchema").
+		HasValue("current_version", 2).
+		HasValue("compatibility", logic.CompatibilityBackward).
+		NotContainsKey("proposed_change")
+
+	versions := impact.Value("versions").Array()
+	versions.Length().IsEqual(2)
+	firstVersion := versions.Value(0).Object()
+	firstVersion.HasValue("version", 1)
+	firstVersion.Value("messages").Array().Length().IsEqual(1)
+	userMessage := firstVersion.Value("messages").Array().Value(0).Object()
+	userMessage.HasValue("name", "UserMessage")
+	usages := userMessage.Value("usages").Array()
+	usages.Length().IsEqual(2)
+	usages.Value(0).Object().
+		HasValue("app_name", "UserApp").
+		HasValue("direction", "receives").
+		HasValue("resource_name", "users_topic").
+		HasValue("server_name", "kafka_server")
+	usages.Value(1).Object().
+		HasValue("app_name", "UserApp").
+		HasValue("direction", "sends")
+
+	secondVersion := versions.Value(1).Object()
+	secondVersion.HasValue("version", 2)
+	secondVersion.Value("messages").Array().Value(0).Object().
+		HasValue("name", "UserMessageV2").
+		Value("usages").Array().IsEmpty()
+
+	resources := impact.Value("resources").Array()
+	resources.Length().IsEqual(1)
+	resources.Value(0).Object().
+		HasValue("name", "users_topic").
+		HasValue("server_name", "kafka_server")
+
+	// Test 2: A new required field breaks senders of older versions, readers on the new version can't read their data
+	requiredEmail := `{"type": "object", "properties": {"id": {"type": "string"}, "name": {"type": "string"},
+		"nickname": {"type": "string"}, "email": {"type": "string"}}, "required": ["email"]}`
+	proposal := e.POST("/v1/protected/schemas/"+schemaID+"/impact").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.SchemaImpactApiInputContract{Schema: requiredEmail}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("proposed_change").Object()
+	proposal.HasValue("compatible", false)
+	proposal.Value("violations").Array().NotEmpty()
+	breaks := proposal.Value("breaks").Array()
+	breaks.Length().IsEqual(1)
+	breaks.Value(0).Object().
+		HasValue("version", 1).
+		HasValue("message_name", "UserMessage").
+		HasValue("app_name", "UserApp").
+		HasValue("direction", "sends").
+		HasValue("resource_name", "users_topic").
+		Value("violations").Array().Value(0).Object().
+		HasValue("direction", logic.CompatibilityDirectionBackward)
+
+	// Test 3: A widened type breaks receivers of older versions, they can't read data of the new version
+	nullableName := `{"type": "object", "properties": {"id": {"type": "string"}, "name": {"type": ["string", "null"]},
+		"nickname": {"type": "string"}}}`
+	proposal = e.POST("/v1/protected/schemas/"+schemaID+"/impact").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.SchemaImpactApiInputContract{Schema: nullableName}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("proposed_change").Object()
+	proposal.HasValue("compatible", true)
+	proposal.Value("violations").Array().IsEmpty()
+	breaks = proposal.Value("breaks").Array()
+	breaks.Length().IsEqual(1)
+	breaks.Value(0).Object().
+		HasValue("version", 1).
+		HasValue("app_name", "UserApp").
+		HasValue("direction", "receives").
+		Value("violations").Array().Value(0).Object().
+		HasValue("direction", logic.CompatibilityDirectionForward)
+
+	// Test 4: Proposals are validated and nothing is saved
+	invalidSchema, err := ReadTestFileString("jsonschemas/invalidSchema1InvalidType.json")
+	require.NoError(t, err)
+	e.POST("/v1/protected/schemas/"+schemaID+"/impact").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.SchemaImpactApiInputContract{Schema: invalidSchema}).
+		Expect().
+		Status(http.StatusUnprocessableEntity)
+
+	e.POST("/v1/protected/schemas/"+schemaID+"/impact").
+		WithHeader("Authorization", userBearer).
+		WithJSON(map[string]string{}).
+		Expect().
+		Status(http.StatusUnprocessableEntity)
+
+	e.GET("/v1/protected/schemas/"+schemaID).
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().HasValue("version", 2)
+
+	// Test 5: Unknown schemas are not found
+	e.GET("/v1/protected/schemas/00000000-0000-0000-0000-000000000000/impact").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusNotFound)
+}
@@ -0,0 +1,154 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/fusioncatltd/fusioncat/api/input_contracts"
+	"github.com/fusioncatltd/fusioncat/logic"
+	"github.com/gavv/httpexpect/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaInference(t *testing.T) {
+	// Clean database before running test
+	CleanDatabase(t)
+
+	h := os.Getenv("TESTSERVER_URL")
+	e := httpexpect.Default(t, h)
+
+	userBearer, createProject := PrepareTestUserAndProject(t, e, "test-schema-inference", "Test project for schema inference")
+	project := createProject("SchemaInferenceProject")
+	inferURL := "/v1/protected/projects/" + project.ID + "/schemas/infer"
+
+	orders, err := ReadTestFileString("inference/orders.ndjson")
+	require.NoError(t, err)
+
+	// Test 1: Schemas are inferred from payloads without saving them
+	inferred := e.POST(inferURL).
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.InferSchemaApiInputContract{
+			Payloads: []json.RawMessage{
+				json.RawMessage(`{"id": 1, "kind": "signup", "at": "2024-05-01T10:00:00Z", "referrer": "https://example.com/a"}`),
+				json.RawMessage(`{"id": 2, "kind": "login", "at": "2024-05-01T11:00:00Z"}`),
+				json.RawMessage(`{"id": 3.5, "kind": "signup", "at": "2024-05-02T10:00:00Z", "referrer": null}`),
+				json.RawMessage(`{"id": 4, "kind": "login", "at": "2024-05-03T10:00:00Z", "tags": ["a", 1]}`),
+			},
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	inferred.HasValue("samples", 4).NotContainsKey("saved_schema")
+
+	var schema map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(inferred.Value("schema").String().Raw()), &schema))
+	require.Equal(t, "https://json-schema.org/draft/2020-12/schema", schema["$schema"])
+	require.Equal(t, "object", schema["type"])
+	require.Equal(t, []interface{}{"at", "id", "kind"}, schema["required"])
+	properties := schema["properties"].(map[string]interface{})
+	require.Equal(t, map[string]interface{}{"type": "number"}, properties["id"])
+	require.Equal(t, map[string]interface{}{"type": "string", "enum": []interface{}{"login", "signup"}}, properties["kind"])
+	require.Equal(t, map[string]interface{}{"type": "string", "format": "date-time"}, properties["at"])
+	require.Equal(t, map[string]interface{}{"type": []interface{}{"string", "null"}, "format": "uri"}, properties["referrer"])
+	require.Equal(t, map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": []interface{}{"string", "integer"}}},
+		properties["tags"])
+
+	// Test 2: Inferred schemas are saved as new schemas
+	saved := e.POST(inferURL).
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.InferSchemaApiInputContract{
+			Payloads:      []json.RawMessage{json.RawMessage(`{"order_id": "a"}`), json.RawMessage(`{"order_id": "b"}`)},
+			Name:          "orders",
+			Description:   "Orders of the legacy topic",
+			Compatibility: logic.CompatibilityBackward,
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	savedSchema := saved.Value("saved_schema").Object()
+	savedSchema.HasValue("name", "orders").
+		HasValue("type", logic.SchemaTypeJSONSchema).
+		HasValue("version", 1).
+		HasValue("compatibility", logic.CompatibilityBackward).
+		HasValue("schema", saved.Value("schema").String().Raw())
+	schemaID := savedSchema.Value("id").String().Raw()
+
+	e.POST(inferURL).
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.InferSchemaApiInputContract{
+			Payloads: []json.RawMessage{json.RawMessage(`{"order_id": "c"}`)},
+			Name:     "orders",
+		}).
+		Expect().
+		Status(http.StatusConflict)
+
+	// Test 3: NDJSON uploads are saved as new versions, which must satisfy the compatibility mode
+	e.POST(inferURL).
+		WithHeader("Authorization", userBearer).
+		WithMultipart().
+		WithFileBytes("file", "orders.ndjson", []byte(orders)).
+		WithFormField("schema_id", schemaID).
+		Expect().
+		Status(http.StatusConflict).
+		JSON().Object().Value("violations").Array().NotEmpty()
+
+	uploaded := e.POST(inferURL).
+		WithHeader("Authorization", userBearer).
+		WithMultipart().
+		WithFileBytes("file", "orders.ndjson", []byte(orders)).
+		WithFormField("schema_id", schemaID).
+		WithFormField("ignore_compatibility", "true").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	uploaded.HasValue("samples", 4)
+	uploaded.Value("saved_schema").Object().
+		HasValue("id", schemaID).
+		HasValue("version", 2)
+
+	require.NoError(t, json.Unmarshal([]byte(uploaded.Value("schema").String().Raw()), &schema))
+	require.Equal(t, "orders", schema["title"])
+	require.Equal(t, []interface{}{"amount", "created_at", "customer_email", "items", "order_id", "status"}, schema["required"])
+	properties = schema["properties"].(map[string]interface{})
+	require.Equal(t, map[string]interface{}{"type": "string", "format": "uuid"}, properties["order_id"])
+	require.Equal(t, map[string]interface{}{"type": "string", "format": "email"}, properties["customer_email"])
+	require.Equal(t, map[string]interface{}{"type": []interface{}{"string", "null"}}, properties["coupon"])
+
+	// Test 4: Invalid requests
+	e.POST(inferURL).
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.InferSchemaApiInputContract{}).
+		Expect().
+		Status(http.StatusUnprocessableEntity)
+
+	e.POST(inferURL).
+		WithHeader("Authorization", userBearer).
+		WithMultipart().
+		WithFileBytes("file", "broken.ndjson", []byte("{\"id\": 1}\n{\"id\": ")).
+		Expect().
+		Status(http.StatusUnprocessableEntity).
+		JSON().Object().Value("error").Array().Value(0).Object().
+		HasValue("field", "file").
+		Value("message").String().HasPrefix("line 2")
+
+	e.POST(inferURL).
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.InferSchemaApiInputContract{
+			Payloads: []json.RawMessage{json.RawMessage(`{"id": 1}`)},
+			Name:     "other_orders",
+			SchemaID: schemaID,
+		}).
+		Expect().
+		Status(http.StatusUnprocessableEntity)
+
+	e.POST(inferURL).
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.InferSchemaApiInputContract{
+			Payloads: []json.RawMessage{json.RawMessage(`{"id": 1}`)},
+			SchemaID: "00000000-0000-0000-0000-000000000000",
+		}).
+		Expect().
+		Status(http.StatusNotFound)
+}
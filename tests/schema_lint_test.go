@@ -0,0 +1,177 @@
+package tests
+
+import (
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/fusioncatltd/fusioncat/api/input_contracts"
+	"github.com/fusioncatltd/fusioncat/jsonschemalint"
+	"github.com/fusioncatltd/fusioncat/logic"
+	"github.com/gavv/httpexpect/v2"
+)
+
+func TestSchemaLint(t *testing.T) {
+	// Clean database before running test
+	CleanDatabase(t)
+
+	h := os.Getenv("TESTSERVER_URL")
+	e := httpexpect.Default(t, h)
+
+	userBearer, createProject := PrepareTestUserAndProject(t, e, "test-schema-lint", "Test project for schema linting")
+	project := createProject("SchemaLintProject")
+	schemasURL := "/v1/protected/projects/" + project.ID + "/schemas"
+	rulesURL := "/v1/protected/projects/" + project.ID + "/lint/rules"
+
+	cleanSchema := `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {
+			"order_id": {"type": "string", "description": "Order"},
+			"created_at": {"type": "string", "format": "date-time", "description": "Creation time"}
+		},
+		"required": ["order_id"],
+		"additionalProperties": false
+	}`
+	sloppySchema := `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {
+			"orderId": {"type": "string"},
+			"created_at": {"type": "string", "description": "Creation time"}
+		}
+	}`
+
+	// Test 1: All rules are enabled with the warning severity by default
+	rules := e.GET(rulesURL).
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array()
+	rules.Length().IsEqual(len(jsonschemalint.Rules))
+	rules.Value(0).Object().
+		HasValue("rule", jsonschemalint.RulePropertyDescription).
+		HasValue("enabled", true).
+		HasValue("severity", jsonschemalint.SeverityWarning)
+
+	// Test 2: Warnings are returned along with created schemas
+	e.POST(schemasURL).
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateSchemaApiInputContract{Name: "clean", Type: logic.SchemaTypeJSONSchema, Schema: cleanSchema}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().NotContainsKey("lint")
+
+	sloppy := e.POST(schemasURL).
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateSchemaApiInputContract{Name: "sloppy", Type: logic.SchemaTypeJSONSchema, Schema: sloppySchema}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	sloppyID := sloppy.Value("id").String().Raw()
+	lint := sloppy.Value("lint").Array()
+	lint.Length().IsEqual(5)
+	lint.Value(0).Object().
+		HasValue("rule", jsonschemalint.RuleAdditionalProperties).
+		HasValue("severity", jsonschemalint.SeverityWarning).
+		HasValue("path", "")
+	lint.Value(1).Object().HasValue("rule", jsonschemalint.RuleRequiredDeclared)
+	lint.Value(2).Object().
+		HasValue("rule", jsonschemalint.RuleDateTimeFormat).
+		HasValue("path", "/properties/created_at")
+	lint.Value(3).Object().HasValue("rule", jsonschemalint.RulePropertyNaming).HasValue("path", "/properties/orderId")
+	lint.Value(4).Object().HasValue("rule", jsonschemalint.RulePropertyDescription).HasValue("path", "/properties/orderId")
+
+	// Test 3: Rules are configured per project, errors reject new schemas and versions
+	enabled := false
+	e.PUT(rulesURL+"/"+jsonschemalint.RuleRequiredDeclared).
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.SchemaLintRuleApiInputContract{Enabled: &enabled}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		HasValue("enabled", false).
+		HasValue("severity", jsonschemalint.SeverityWarning)
+	e.PUT(rulesURL+"/"+jsonschemalint.RulePropertyNaming).
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.SchemaLintRuleApiInputContract{Severity: jsonschemalint.SeverityError}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		HasValue("enabled", true).
+		HasValue("severity", jsonschemalint.SeverityError).
+		HasValue("option", jsonschemalint.NamingSnakeCase)
+
+	rejected := e.PUT("/v1/protected/schemas/"+sloppyID).
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ModifySchemaApiInputContract{Schema: sloppySchema}).
+		Expect().
+		Status(http.StatusUnprocessableEntity).
+		JSON().Object()
+	rejected.Value("findings").Array().Length().IsEqual(4)
+	rejected.Value("findings").Array().Value(2).Object().
+		HasValue("rule", jsonschemalint.RulePropertyNaming).
+		HasValue("severity", jsonschemalint.SeverityError)
+
+	e.POST(schemasURL).
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateSchemaApiInputContract{Name: "sloppy2", Type: logic.SchemaTypeJSONSchema, Schema: sloppySchema}).
+		Expect().
+		Status(http.StatusUnprocessableEntity)
+
+	// camelCase projects reject snake_case names instead
+	e.PUT(rulesURL+"/"+jsonschemalint.RulePropertyNaming).
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.SchemaLintRuleApiInputContract{Option: jsonschemalint.NamingCamelCase}).
+		Expect().
+		Status(http.StatusOK)
+	e.PUT("/v1/protected/schemas/"+sloppyID).
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ModifySchemaApiInputContract{Schema: sloppySchema}).
+		Expect().
+		Status(http.StatusUnprocessableEntity).
+		JSON().Object().Value("findings").Array().Value(1).Object().
+		HasValue("rule", jsonschemalint.RulePropertyNaming).
+		HasValue("path", "/properties/created_at")
+
+	// Test 4: Whole projects are linted
+	e.PUT(rulesURL+"/"+jsonschemalint.RulePropertyNaming).
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.SchemaLintRuleApiInputContract{Option: jsonschemalint.NamingSnakeCase}).
+		Expect().
+		Status(http.StatusOK)
+	report := e.GET("/v1/protected/projects/"+project.ID+"/lint").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	report.HasValue("errors", 1).HasValue("warnings", 3).HasValue("infos", 0)
+	schemas := report.Value("schemas").Array()
+	schemas.Length().IsEqual(2)
+	schemas.Value(0).Object().HasValue("schema_name", "clean").HasValue("findings", []interface{}{})
+	schemas.Value(1).Object().HasValue("schema_name", "sloppy").HasValue("version", 1)
+
+	// Test 5: Invalid configurations
+	e.PUT(rulesURL+"/unknown-rule").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.SchemaLintRuleApiInputContract{Severity: jsonschemalint.SeverityError}).
+		Expect().
+		Status(http.StatusNotFound)
+
+	e.PUT(rulesURL+"/"+jsonschemalint.RulePropertyNaming).
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.SchemaLintRuleApiInputContract{Option: "kebab-case"}).
+		Expect().
+		Status(http.StatusUnprocessableEntity)
+
+	e.PUT(rulesURL+"/"+jsonschemalint.RuleNoAnyType).
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.SchemaLintRuleApiInputContract{Severity: "fatal"}).
+		Expect().
+		Status(http.StatusUnprocessableEntity)
+
+	e.GET("/v1/protected/projects/00000000-0000-0000-0000-000000000000/lint").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusNotFound)
+}
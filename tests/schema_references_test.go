@@ -0,0 +1,199 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fusioncatltd/fusioncat/api/input_contracts"
+	"github.com/gavv/httpexpect/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaReferences(t *testing.T) {
+	// Clean database before running test
+	CleanDatabase(t)
+
+	h := os.Getenv("TESTSERVER_URL")
+	e := httpexpect.Default(t, h)
+
+	// Create a user and get bearer token
+	userEmail := fmt.Sprintf("test-schema-references-%s@mail.com", strconv.FormatInt(time.Now().UnixNano(), 10))
+	userSignUpResponse := e.POST("/v1/public/users").
+		WithJSON(input_contracts.SignInSignUpApiInputContract{
+			Email:    userEmail,
+			Password: "123456789",
+		}).
+		Expect().
+		Status(http.StatusOK)
+
+	userBearer := userSignUpResponse.Raw().Header.Get("Authorization")
+
+	projectID := e.POST("/v1/protected/projects").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateModifyProjectApiInputContract{
+			Name:        fmt.Sprintf("SchemaReferencesProject%d", time.Now().UnixNano()),
+			Description: "Test project for references between schemas",
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("id").String().Raw()
+
+	addressSchema, err := ReadTestFileString("references/address.json")
+	require.NoError(t, err)
+	personSchema, err := ReadTestFileString("references/person.json")
+	require.NoError(t, err)
+
+	// Test 1: References to missing schemas and versions are rejected
+	e.POST("/v1/protected/projects/"+projectID+"/schemas").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateSchemaApiInputContract{
+			Name:   "person",
+			Type:   "jsonschema",
+			Schema: personSchema,
+		}).
+		Expect().
+		Status(http.StatusUnprocessableEntity)
+
+	addressSchemaID := e.POST("/v1/protected/projects/"+projectID+"/schemas").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateSchemaApiInputContract{
+			Name:   "address",
+			Type:   "jsonschema",
+			Schema: addressSchema,
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("id").String().Raw()
+
+	e.POST("/v1/protected/projects/"+projectID+"/schemas").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateSchemaApiInputContract{
+			Name:   "person",
+			Type:   "jsonschema",
+			Schema: strings.ReplaceAll(personSchema, "address/versions/1", "address/versions/2"),
+		}).
+		Expect().
+		Status(http.StatusUnprocessableEntity)
+
+	// Test 2: Schemas reference versions of other schemas by their URIs
+	personSchemaID := e.POST("/v1/protected/projects/"+projectID+"/schemas").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateSchemaApiInputContract{
+			Name:   "person",
+			Type:   "jsonschema",
+			Schema: personSchema,
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("id").String().Raw()
+
+	// Test 3: Payload validation follows the references
+	validatePayloads := func(payloads ...string) *httpexpect.Object {
+		rawPayloads := make([]json.RawMessage, 0, len(payloads))
+		for _, payload := range payloads {
+			rawPayloads = append(rawPayloads, json.RawMessage(payload))
+		}
+		return e.POST("/v1/protected/schemas/"+personSchemaID+"/versions/1/validate").
+			WithHeader("Authorization", userBearer).
+			WithJSON(input_contracts.ValidatePayloadsApiInputContract{Payloads: rawPayloads}).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object()
+	}
+	validatePayloads(`{"name": "Ann", "home": {"street": "Main st", "city": "Riga", "country": "LV"}, "country": "LV"}`).
+		HasValue("valid", true)
+	validatePayloads(`{"name": "Ann", "home": {"street": "Main st", "city": "Riga", "country": "Latvia"}}`).
+		HasValue("valid", false)
+	validatePayloads(`{"name": "Ann", "home": {"street": "Main st"}}`).
+		HasValue("valid", false)
+
+	// Test 4: Examples of referencing schemas are generated from the referenced schemas
+	example := e.GET("/v1/protected/schemas/"+personSchemaID+"/versions/1/examples").
+		WithQuery("seed", 42).
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("examples").Array().Value(0).Object()
+	example.Value("home").Object().Value("country").String().Match(`^[A-Z]{2}$`)
+
+	// Test 5: References are listed for both sides
+	personReferences := e.GET("/v1/protected/schemas/"+personSchemaID+"/references").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	personReferences.Value("referenced_by").Array().IsEmpty()
+	personReferences.Value("references").Array().Length().IsEqual(1)
+	personReferences.Value("references").Array().Value(0).Object().
+		HasValue("schema_version", 1).
+		HasValue("referenced_schema_id", addressSchemaID).
+		HasValue("referenced_schema_version", 1).
+		HasValue("uri", "fusioncat://schemas/address/versions/1")
+
+	addressReferences := e.GET("/v1/protected/schemas/"+addressSchemaID+"/references").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	addressReferences.Value("references").Array().IsEmpty()
+	addressReferences.Value("referenced_by").Array().Value(0).Object().
+		HasValue("schema_id", personSchemaID).
+		HasValue("schema_name", "person")
+
+	// Test 6: Referenced schemas can't be deleted
+	conflict := e.DELETE("/v1/protected/schemas/"+addressSchemaID).
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusConflict).
+		JSON().Object()
+	conflict.Value("referenced_by").Array().Length().IsEqual(1)
+	conflict.Value("messages").Array().IsEmpty()
+
+	// Test 7: Deleted schemas no longer block deletion of the schemas they reference
+	e.DELETE("/v1/protected/schemas/"+personSchemaID).
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK)
+
+	e.GET("/v1/protected/schemas/"+personSchemaID).
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusNotFound)
+
+	e.GET("/v1/protected/schemas/"+addressSchemaID+"/references").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("referenced_by").Array().IsEmpty()
+
+	// Test 8: Schemas used by messages can't be deleted
+	messageID := e.POST("/v1/protected/projects/"+projectID+"/messages").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateMessageApiInputContract{
+			Name:          "address_changed",
+			SchemaID:      addressSchemaID,
+			SchemaVersion: 1,
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("id").String().Raw()
+
+	e.DELETE("/v1/protected/schemas/"+addressSchemaID).
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusConflict).
+		JSON().Object().Value("messages").Array().Value(0).Object().
+		HasValue("id", messageID).
+		HasValue("schema_version", 1)
+
+	e.DELETE("/v1/protected/schemas/00000000-0000-0000-0000-000000000000").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusNotFound)
+}
@@ -0,0 +1,292 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/fusioncatltd/fusioncat/api/input_contracts"
+	"github.com/fusioncatltd/fusioncat/logic"
+	"github.com/gavv/httpexpect/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaRegistry(t *testing.T) {
+	// Clean database before running test
+	CleanDatabase(t)
+
+	h := os.Getenv("TESTSERVER_URL")
+	e := httpexpect.Default(t, h)
+
+	// Create a user and get bearer token
+	userEmail := fmt.Sprintf("test-schema-registry-%s@mail.com", strconv.FormatInt(time.Now().UnixNano(), 10))
+	userSignUpResponse := e.POST("/v1/public/users").
+		WithJSON(input_contracts.SignInSignUpApiInputContract{
+			Email:    userEmail,
+			Password: "123456789",
+		}).
+		Expect().
+		Status(http.StatusOK)
+
+	userBearer := userSignUpResponse.Raw().Header.Get("Authorization")
+
+	projectID := e.POST("/v1/protected/projects").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateModifyProjectApiInputContract{
+			Name:        fmt.Sprintf("SchemaRegistryProject%d", time.Now().UnixNano()),
+			Description: "Test project for the schema registry API",
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("id").String().Raw()
+	registryURL := "/v1/protected/projects/" + projectID + "/registry"
+
+	userEventV1, err := ReadTestFileString("avroschemas/userEventV1.avsc")
+	require.NoError(t, err)
+	userEventV2, err := ReadTestFileString("avroschemas/userEventV2.avsc")
+	require.NoError(t, err)
+	userEventV3, err := ReadTestFileString("compatibility/userEventV3MissingDefault.avsc")
+	require.NoError(t, err)
+
+	// Serializers send compact schemas
+	var compactV1 interface{}
+	require.NoError(t, json.Unmarshal([]byte(userEventV1), &compactV1))
+	compactV1JSON, err := json.Marshal(compactV1)
+	require.NoError(t, err)
+
+	// Test 1: The default compatibility level applies to new subjects
+	e.GET(registryURL+"/config").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().HasValue("compatibilityLevel", "NONE")
+
+	e.PUT(registryURL+"/config").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.RegistryConfigApiInputContract{Compatibility: "SIDEWAYS"}).
+		Expect().
+		Status(http.StatusUnprocessableEntity).
+		JSON().Object().HasValue("error_code", 42203)
+
+	e.PUT(registryURL+"/config").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.RegistryConfigApiInputContract{Compatibility: "BACKWARD"}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().HasValue("compatibility", "BACKWARD")
+
+	// Test 2: Registering a schema under an unknown subject creates a schema
+	registerSchema := func(subject string, schema string) *httpexpect.Response {
+		return e.POST(registryURL+"/subjects/"+subject+"/versions").
+			WithHeader("Authorization", userBearer).
+			WithJSON(input_contracts.RegistrySchemaApiInputContract{Schema: schema}).
+			Expect()
+	}
+	firstID := registerSchema("user_events-value", userEventV1).
+		Status(http.StatusOK).
+		JSON().Object().Value("id").Number().Raw()
+
+	e.GET("/v1/protected/projects/"+projectID+"/schemas").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array().Value(0).Object().
+		HasValue("name", "user_events_value").
+		HasValue("type", "avro").
+		HasValue("compatibility", "backward")
+
+	// Test 3: Registering the same schema again returns its ID
+	registerSchema("user_events-value", string(compactV1JSON)).
+		Status(http.StatusOK).
+		JSON().Object().HasValue("id", firstID)
+
+	e.POST(registryURL+"/subjects/user_events-value").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.RegistrySchemaApiInputContract{Schema: string(compactV1JSON)}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		HasValue("subject", "user_events-value").
+		HasValue("id", firstID).
+		HasValue("version", 1)
+
+	// Test 4: New versions must satisfy the compatibility mode
+	secondID := registerSchema("user_events-value", userEventV2).
+		Status(http.StatusOK).
+		JSON().Object().Value("id").Number().Raw()
+	require.NotEqual(t, firstID, secondID)
+
+	e.POST(registryURL+"/compatibility/subjects/user_events-value/versions/latest").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.RegistrySchemaApiInputContract{Schema: userEventV3}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		HasValue("is_compatible", false).
+		Value("messages").Array().Length().IsEqual(1)
+
+	e.POST(registryURL+"/compatibility/subjects/user_events-value/versions").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.RegistrySchemaApiInputContract{Schema: userEventV2}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().HasValue("is_compatible", true)
+
+	registerSchema("user_events-value", userEventV3).
+		Status(http.StatusConflict).
+		JSON().Object().HasValue("error_code", 409)
+
+	registerSchema("user_events-value", `{"type": "record"}`).
+		Status(http.StatusUnprocessableEntity).
+		JSON().Object().HasValue("error_code", 42201)
+
+	// Test 5: Versions of subjects and schemas by ID
+	e.GET(registryURL+"/subjects/user_events-value/versions").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array().IsEqual([]int{1, 2})
+
+	latest := e.GET(registryURL+"/subjects/user_events-value/versions/latest").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK)
+	latest.Header("Content-Type").HasPrefix("application/vnd.schemaregistry.v1+json")
+	latest.JSON().Object().
+		HasValue("id", secondID).
+		HasValue("version", 2).
+		HasValue("schema", userEventV2).
+		NotContainsKey("schemaType")
+
+	e.GET(registryURL+"/subjects/user_events-value/versions/1/schema").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		Body().IsEqual(userEventV1)
+
+	e.GET(registryURL+"/subjects/user_events-value/versions/3").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusNotFound).
+		JSON().Object().HasValue("error_code", 40402)
+
+	e.GET(registryURL+"/subjects/user_events-value/versions/first").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusUnprocessableEntity).
+		JSON().Object().HasValue("error_code", 42202)
+
+	e.GET(registryURL+"/schemas/ids/"+strconv.Itoa(int(firstID))).
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().HasValue("schema", userEventV1)
+
+	e.GET(registryURL+"/schemas/ids/0").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusNotFound).
+		JSON().Object().HasValue("error_code", 40403)
+
+	e.GET(registryURL+"/schemas/types").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array().ContainsAll("AVRO", "JSON", "PROTOBUF")
+
+	// Test 6: Subjects of messages have the schema versions of the messages
+	schemaID := e.GET("/v1/protected/projects/"+projectID+"/schemas").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array().Value(0).Object().Value("id").String().Raw()
+
+	e.POST("/v1/protected/projects/"+projectID+"/messages").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateMessageApiInputContract{
+			Name:          "user_changed",
+			SchemaID:      schemaID,
+			SchemaVersion: 1,
+		}).
+		Expect().
+		Status(http.StatusOK)
+
+	e.GET(registryURL+"/subjects").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array().IsEqual([]string{"user_changed", "user_events_value"})
+
+	e.GET(registryURL+"/subjects/user_changed-value/versions/latest").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		HasValue("subject", "user_changed-value").
+		HasValue("id", firstID).
+		HasValue("version", 1)
+
+	e.GET(registryURL+"/schemas/ids/"+strconv.Itoa(int(firstID))+"/versions").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array().IsEqual([]map[string]interface{}{
+		{"subject": "user_events_value", "version": 1},
+		{"subject": "user_changed", "version": 1},
+	})
+
+	registerSchema("user_changed-value", userEventV2).
+		Status(http.StatusUnprocessableEntity).
+		JSON().Object().HasValue("error_code", 42205)
+
+	// Test 7: Compatibility levels of subjects are compatibility modes of their schemas
+	e.PUT(registryURL+"/config/user_events-value").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.RegistryConfigApiInputContract{Compatibility: "FULL_TRANSITIVE"}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().HasValue("compatibility", "FULL_TRANSITIVE")
+
+	e.GET(registryURL+"/config/user_changed-value").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().HasValue("compatibilityLevel", "FULL_TRANSITIVE")
+
+	// Test 8: Imported schemas without a compatibility mode get the default mode of the project
+	importYAML, err := ReadTestFileString("imports/valid_basic.yaml")
+	require.NoError(t, err)
+	e.POST("/v1/protected/projects/"+projectID+"/imports").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ImportFileInputContract{YAML: importYAML}).
+		Expect().
+		Status(http.StatusOK)
+
+	importedCompatibility := ""
+	for _, schema := range e.GET("/v1/protected/projects/"+projectID+"/schemas").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array().Iter() {
+		if schema.Object().Value("name").String().Raw() == "UserSchema" {
+			importedCompatibility = schema.Object().Value("compatibility").String().Raw()
+		}
+	}
+	require.Equal(t, logic.CompatibilityBackward, importedCompatibility)
+
+	// Test 9: Unknown subjects and projects are not found
+	e.GET(registryURL+"/subjects/unknown-value/versions").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusNotFound).
+		JSON().Object().HasValue("error_code", 40401)
+
+	e.GET("/v1/protected/projects/00000000-0000-0000-0000-000000000000/registry/subjects").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusNotFound)
+}
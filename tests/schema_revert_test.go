@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/fusioncatltd/fusioncat/api/input_contracts"
+	"github.com/fusioncatltd/fusioncat/logic"
+	"github.com/gavv/httpexpect/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaRevert(t *testing.T) {
+	// Clean database before running test
+	CleanDatabase(t)
+
+	h := os.Getenv("TESTSERVER_URL")
+	e := httpexpect.Default(t, h)
+
+	userBearer, createProject := PrepareTestUserAndProject(t, e, "test-schema-revert", "Test project for schema reverts")
+	project := createProject("SchemaRevertProject")
+
+	customerV1 := `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {"id": {"type": "string"}, "name": {"type": "string"}},
+		"required": ["id", "name"]
+	}`
+	customerV2 := `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {"id": {"type": "string"}, "name": {"type": "string"}, "email": {"type": "string"}},
+		"required": ["id"]
+	}`
+
+	schemaID := e.POST("/v1/protected/projects/"+project.ID+"/schemas").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.CreateSchemaApiInputContract{
+			Name:   "customers",
+			Type:   logic.SchemaTypeJSONSchema,
+			Schema: customerV1,
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("id").String().Raw()
+	schemaURL := "/v1/protected/schemas/" + schemaID
+
+	e.PUT(schemaURL).
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.ModifySchemaApiInputContract{Schema: customerV2}).
+		Expect().
+		Status(http.StatusOK)
+
+	for name, version := range map[string]int{"CustomerV1": 1, "CustomerV2": 2} {
+		e.POST("/v1/protected/projects/"+project.ID+"/messages").
+			WithHeader("Authorization", userBearer).
+			WithJSON(input_contracts.CreateMessageApiInputContract{
+				Name:          name,
+				SchemaID:      schemaID,
+				SchemaVersion: version,
+			}).
+			Expect().
+			Status(http.StatusOK)
+	}
+
+	e.PUT(schemaURL+"/compatibility").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.SchemaCompatibilityApiInputContract{Compatibility: logic.CompatibilityBackward}).
+		Expect().
+		Status(http.StatusOK)
+
+	// Test 1: Reverts are checked against the compatibility mode, making name required again breaks it
+	e.POST(schemaURL+"/versions/1/revert").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.RevertSchemaVersionApiInputContract{MoveMessages: true}).
+		Expect().
+		Status(http.StatusConflict).
+		JSON().Object().
+		HasValue("compatibility", logic.CompatibilityBackward).
+		Value("violations").Array().NotEmpty()
+
+	// Test 2: Reverts create a new version with the content of the old one and move messages of the bad one
+	reverted := e.POST(schemaURL+"/versions/1/revert").
+		WithHeader("Authorization", userBearer).
+		WithJSON(input_contracts.RevertSchemaVersionApiInputContract{MoveMessages: true, IgnoreCompatibility: true}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	reverted.HasValue("reverted_from", 2).
+		HasValue("reverted_to", 1).
+		HasValue("skipped_messages", []interface{}{})
+	reverted.Value("schema").Object().
+		HasValue("version", 3).
+		HasValue("schema", customerV1)
+	reverted.Value("moved_messages").Array().Length().IsEqual(1)
+	reverted.Value("moved_messages").Array().Value(0).Object().
+		HasValue("name", "CustomerV2").
+		HasValue("schema_version", 3)
+
+	versions := e.GET(schemaURL+"/versions").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array()
+	versions.Length().IsEqual(3)
+
+	messages := e.GET("/v1/protected/projects/"+project.ID+"/messages").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array()
+	pinned := make(map[string]float64)
+	for _, message := range messages.Iter() {
+		object := message.Object()
+		pinned[object.Value("name").String().Raw()] = object.Value("schema_version").Number().Raw()
+	}
+	require.Equal(t, map[string]float64{"CustomerV1": 1, "CustomerV2": 3}, pinned)
+
+	// Test 3: Without move_messages messages stay where they are
+	e.POST(schemaURL+"/versions/2/revert").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		HasValue("reverted_from", 3).
+		HasValue("moved_messages", []interface{}{}).
+		Value("schema").Object().HasValue("version", 4)
+
+	// Test 4: Invalid reverts
+	e.POST(schemaURL+"/versions/4/revert").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusUnprocessableEntity)
+
+	e.POST(schemaURL+"/versions/9/revert").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusNotFound)
+
+	e.POST("/v1/protected/schemas/00000000-0000-0000-0000-000000000000/versions/1/revert").
+		WithHeader("Authorization", userBearer).
+		Expect().
+		Status(http.StatusNotFound)
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -113,6 +114,93 @@ func TestSchemaCodeGeneration(t *testing.T) {
 		require.NotContains(t, generatedCode, "type user_profile struct", "Struct name should be in CamelCase, not snake_case")
 	})
 
+	// Test Go code generation of nested objects, enums, references and nullable values
+	t.Run("Generate Go code of nested schema", func(t *testing.T) {
+		nestedSchemaContent, err := ReadTestFileString("jsonschemas/orderCreatedNested.json")
+		require.NoError(t, err)
+
+		nestedSchemaResponse := e.POST("/v1/protected/projects/"+createdProject.ID+"/schemas").
+			WithHeader("Authorization", bearerToken).
+			WithJSON(input_contracts.CreateSchemaApiInputContract{
+				Name:        "order_created",
+				Description: "Test schema with nested objects",
+				Schema:      nestedSchemaContent,
+				Type:        "jsonschema",
+			}).
+			Expect().
+			Status(http.StatusOK)
+
+		var nestedSchema logic.SchemaDBSerializerStruct
+		require.NoError(t, json.Unmarshal([]byte(nestedSchemaResponse.Body().Raw()), &nestedSchema))
+
+		generatedCode := e.GET("/v1/protected/schemas/"+nestedSchema.ID+"/code/go").
+			WithHeader("Authorization", bearerToken).
+			Expect().
+			Status(http.StatusOK).
+			Body().Raw()
+
+		require.Contains(t, generatedCode, "// Order was created\ntype OrderCreated struct")
+		require.Regexp(t, `OrderID\s+string\s+`+"`"+`json:"order_id"`+"`"+` // uuid`, generatedCode)
+		require.Regexp(t, `Status\s+OrderCreatedStatus\s+`, generatedCode)
+		require.Contains(t, generatedCode, "type OrderCreatedStatus string")
+		require.Regexp(t, `OrderCreatedStatusOnHold\s+OrderCreatedStatus = "on-hold"`, generatedCode)
+		require.Regexp(t, `Customer\s+OrderCreatedCustomer\s+`, generatedCode)
+		require.Contains(t, generatedCode, "type OrderCreatedCustomer struct")
+		require.Regexp(t, `Items\s+\[\]OrderCreatedItem\s+`, generatedCode)
+		require.Contains(t, generatedCode, "type OrderCreatedItem struct")
+		require.Regexp(t, `BillingAddress\s+\*OrderCreatedAddress\s+`+"`"+`json:"billing_address,omitempty"`+"`", generatedCode)
+		require.Regexp(t, `ShippingAddress\s+\*OrderCreatedAddress\s+`, generatedCode)
+		require.Equal(t, 1, strings.Count(generatedCode, "type OrderCreatedAddress struct"), "Referenced definitions are declared once")
+		require.Regexp(t, `Coupon\s+\*string\s+`, generatedCode)
+		require.Regexp(t, `Discount\s+\*float64\s+`, generatedCode)
+	})
+
+	// Test caching of generated code
+	t.Run("Generated code is cached", func(t *testing.T) {
+		database := OpenTestDatabase(t)
+		defer database.Close()
+		countCachedCode := func() int {
+			var count int
+			require.NoError(t, database.QueryRow("SELECT count(*) FROM generated_code WHERE language = 'go'").Scan(&count))
+			return count
+		}
+
+		firstCode := e.GET("/v1/protected/schemas/"+createdSchema.ID+"/code/go").
+			WithHeader("Authorization", bearerToken).
+			Expect().
+			Status(http.StatusOK).
+			Body().Raw()
+		cachedEntries := countCachedCode()
+		require.Positive(t, cachedEntries)
+
+		// Downloading the same schema again reuses the cached code
+		secondCode := e.GET("/v1/protected/schemas/"+createdSchema.ID+"/code/go").
+			WithHeader("Authorization", bearerToken).
+			Expect().
+			Status(http.StatusOK).
+			Body().Raw()
+		require.Equal(t, firstCode, secondCode)
+		require.Equal(t, cachedEntries, countCachedCode())
+
+		// A new version of the schema is generated and cached separately
+		e.PUT("/v1/protected/schemas/"+createdSchema.ID).
+			WithHeader("Authorization", bearerToken).
+			WithJSON(input_contracts.ModifySchemaApiInputContract{
+				Schema: strings.Replace(schemaContent, `"is_active":{"type":"boolean"}`,
+					`"is_active":{"type":"boolean"},"nickname":{"type":"string"}`, 1),
+			}).
+			Expect().
+			Status(http.StatusOK)
+
+		newVersionCode := e.GET("/v1/protected/schemas/"+createdSchema.ID+"/code/go").
+			WithHeader("Authorization", bearerToken).
+			Expect().
+			Status(http.StatusOK).
+			Body().Raw()
+		require.Contains(t, newVersionCode, "Nickname")
+		require.Equal(t, cachedEntries+1, countCachedCode())
+	})
+
 	// Test Python code generation
 	t.Run("Generate Python code", func(t *testing.T) {
 		codeResponse := e.GET("/v1/protected/schemas/"+createdSchema.ID+"/code/python").
@@ -2,13 +2,23 @@ package tests
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"testing"
+	"time"
 	
+	"github.com/fusioncatltd/fusioncat/api/input_contracts"
+	"github.com/fusioncatltd/fusioncat/db"
+	"github.com/fusioncatltd/fusioncat/logic"
+	"github.com/gavv/httpexpect/v2"
 	_ "github.com/lib/pq"
 	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/require"
 )
 
 // ReadTestFile reads the content of a file from the testfiles directory.
@@ -33,9 +43,8 @@ func ReadTestFileString(filePath string) (string, error) {
 	return string(content), nil
 }
 
-// CleanDatabase truncates all tables except schema_migrations
-// This should be called at the beginning of each test to ensure a clean state
-func CleanDatabase(t *testing.T) {
+// OpenTestDatabase connects to the database of the test server with connection parameters from ../.env
+func OpenTestDatabase(t testing.TB) *sql.DB {
 	// Load .env file from parent directory
 	envPath := filepath.Join("..", ".env")
 	if err := godotenv.Load(envPath); err != nil {
@@ -68,6 +77,13 @@ func CleanDatabase(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Failed to connect to database: %v", err)
 	}
+	return db
+}
+
+// CleanDatabase truncates all tables except schema_migrations
+// This should be called at the beginning of each test to ensure a clean state
+func CleanDatabase(t testing.TB) {
+	db := OpenTestDatabase(t)
 	defer db.Close()
 	
 	// Get all table names except schema_migrations
@@ -99,4 +115,51 @@ func CleanDatabase(t *testing.T) {
 			t.Logf("Warning: Failed to truncate table %s: %v", tableName, err)
 		}
 	}
-}
\ No newline at end of file
+}
+
+// ConnectToTestDatabase connects the logic package to the database of the test server, so that tests
+// can call it directly. Migrations are applied by the test server already.
+func ConnectToTestDatabase(t testing.TB) {
+	envPath := filepath.Join("..", ".env")
+	if err := godotenv.Load(envPath); err != nil {
+		t.Logf("Warning: Could not load .env file from %s: %v", envPath, err)
+	}
+	os.Setenv("DB_MANUAL_MIGRATIONS_FOLDER", "file://../migrations")
+	require.NotNil(t, db.GetDB())
+}
+
+// PrepareTestUserAndProject signs up a user and returns the bearer token of the user and a function
+// creating projects of the user. Emails of users start with the prefix, project names are made
+// unique by appending a timestamp to the given name.
+func PrepareTestUserAndProject(t testing.TB, e *httpexpect.Expect, emailPrefix string,
+	projectDescription string) (string, func(name string) logic.ProjectDBSerializerStruct) {
+	userEmail := fmt.Sprintf("%s-%s@mail.com", emailPrefix, strconv.FormatInt(time.Now().UnixNano(), 10))
+	userSignUpResponse := e.POST("/v1/public/users").
+		WithJSON(input_contracts.SignInSignUpApiInputContract{
+			Email:    userEmail,
+			Password: "123456789",
+		}).
+		Expect().
+		Status(http.StatusOK)
+
+	userBearer := userSignUpResponse.Raw().Header.Get("Authorization")
+
+	createProject := func(name string) logic.ProjectDBSerializerStruct {
+		projectResponse := e.POST("/v1/protected/projects").
+			WithHeader("Authorization", userBearer).
+			WithJSON(input_contracts.CreateModifyProjectApiInputContract{
+				Name:        fmt.Sprintf("%s%d", name, time.Now().UnixNano()),
+				Description: projectDescription,
+			}).
+			Expect().
+			Status(http.StatusOK)
+
+		var project logic.ProjectDBSerializerStruct
+		rawProjectReader := projectResponse.Raw().Body
+		defer rawProjectReader.Close()
+		rawProjectBytes, _ := io.ReadAll(rawProjectReader)
+		require.NoError(t, json.Unmarshal(rawProjectBytes, &project))
+		return project
+	}
+	return userBearer, createProject
+}